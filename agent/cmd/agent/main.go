@@ -12,6 +12,7 @@ import (
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -26,14 +27,16 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/controllers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/monitoring"
 	agentscheme "github.com/stolostron/multicluster-global-hub/agent/pkg/scheme"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/diagnostics"
 	"github.com/stolostron/multicluster-global-hub/pkg/jobs"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
@@ -50,6 +53,7 @@ var setupLog = ctrl.Log.WithName("setup")
 
 func init() {
 	agentscheme.AddToScheme(scheme.Scheme)
+	monitoring.RegisterMetrics()
 }
 
 func main() {
@@ -125,6 +129,9 @@ func parseFlags() *config.AgentConfig {
 	pflag.StringVar(&agentConfig.LeafHubName, "leaf-hub-name", "", "The name of the leaf hub.")
 	pflag.StringVar(&agentConfig.TransportConfig.KafkaConfig.BootstrapServer, "kafka-bootstrap-server", "",
 		"The bootstrap server for kafka.")
+	pflag.StringVar(&agentConfig.TransportConfig.KafkaConfig.BootstrapServerPath, "kafka-bootstrap-server-path", "",
+		"The path of the file containing the bootstrap server for kafka, it overrides the kafka-bootstrap-server and "+
+			"the kafka clients reconnect once it's changed.")
 	pflag.StringVar(&agentConfig.TransportConfig.KafkaConfig.CaCertPath, "kafka-ca-cert-path", "",
 		"The path of CA certificate for kafka bootstrap server.")
 	pflag.StringVar(&agentConfig.TransportConfig.KafkaConfig.ClientCertPath, "kafka-client-cert-path", "",
@@ -137,6 +144,9 @@ func parseFlags() *config.AgentConfig {
 		"status", "Topic for the kafka producer.")
 	pflag.IntVar(&agentConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB,
 		"kafka-message-size-limit", 940, "The limit for kafka message size in KB.")
+	pflag.BoolVar(&agentConfig.TransportConfig.KafkaConfig.ProducerConfig.DisableIdempotence,
+		"kafka-disable-idempotence", false, "Disable the idempotent kafka producer, e.g. the kafka cluster doesn't "+
+			"grant the idempotent write to the user.")
 	pflag.StringVar(&agentConfig.TransportConfig.KafkaConfig.Topics.SpecTopic, "kafka-consumer-topic",
 		"spec", "Topic for the kafka consumer.")
 	pflag.StringVar(&agentConfig.TransportConfig.KafkaConfig.Topics.EventTopic, "kafka-event-topic",
@@ -171,10 +181,34 @@ func parseFlags() *config.AgentConfig {
 		"QPS for the multicluster global hub agent")
 	pflag.IntVar(&agentConfig.Burst, "burst", 300,
 		"Burst for the multicluster global hub agent")
+	pflag.StringVar(&agentConfig.StatusBufferDir, "status-buffer-dir", "",
+		"The directory to buffer the status events during the transport outage, the buffer is disabled if it's empty.")
+	pflag.IntVar(&agentConfig.StatusBufferSizeMB, "status-buffer-size-mb", 100,
+		"The max size of the buffered status events in MB.")
+	pflag.StringVar(&agentConfig.SigningKeyPath, "signing-key-path", "",
+		"The ed25519 private key to sign the status events, the events aren't signed if it's empty.")
+	pflag.BoolVar(&agentConfig.Standalone, "standalone", false,
+		"Run the agent on the cluster without the ACM hub, it reports the cluster itself to the global hub.")
+	pflag.StringVar(&agentConfig.HubMetricsPrometheusURL, "hub-metrics-prometheus-url", "",
+		"The prometheus(or thanos querier) of the managed hub to query the key metrics, they aren't reported if it's empty.")
+	pflag.StringVar(&agentConfig.HubMetricsCACertPath, "hub-metrics-ca-cert-path", "",
+		"The CA certificate of the prometheus of the managed hub.")
+	pflag.DurationVar(&agentConfig.HubMetricsInterval, "hub-metrics-interval", 5*time.Minute,
+		"The interval to query the key metrics from the prometheus of the managed hub.")
+	pflag.StringVar(&agentConfig.LogLevelConfigMap, "log-level-configmap", "multicluster-global-hub-agent-logging",
+		"The configmap of the log levels in the agent namespace, e.g. the transport key sets the level of the "+
+			"transport layer, the levels aren't watched if it's empty.")
+	pflag.StringVar(&agentConfig.ScrubRulesConfigMap, "scrub-rules-configmap",
+		"multicluster-global-hub-agent-scrub-rules",
+		"The configmap of the rules to redact and truncate the status events in the agent namespace before they're "+
+			"sent, the rules aren't watched if it's empty.")
+	pflag.BoolVar(&agentConfig.EnableDiagnostics, "enable-diagnostics", false,
+		"Serve the pprof, expvar and the diagnostics bundle under /debug of the metrics server, the requests are "+
+			"authenticated and authorized by the kube-apiserver.")
 	pflag.Parse()
 
 	// set zap logger
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctrl.SetLogger(logger.New(&opts))
 
 	return agentConfig
 }
@@ -195,6 +229,9 @@ func completeConfig(agentConfig *config.AgentConfig) error {
 		return fmt.Errorf("flag kafka-message-size-limit %d must not exceed %d",
 			agentConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB, producer.MaxMessageKBLimit)
 	}
+	if agentConfig.StatusBufferDir != "" && agentConfig.StatusBufferSizeMB < 1 {
+		return fmt.Errorf("flag status-buffer-size-mb must be positive")
+	}
 	agentConfig.TransportConfig.KafkaConfig.EnableTLS = true
 	if agentConfig.MetricsAddress == "" {
 		agentConfig.MetricsAddress = fmt.Sprintf("%s:%d", metricsHost, metricsPort)
@@ -233,16 +270,50 @@ func createManager(ctx context.Context, restConfig *rest.Config, agentConfig *co
 		RenewDeadline:           &renewDeadline,
 		RetryPeriod:             &retryPeriod,
 		NewCache:                initCache,
+		// release the lease on shutdown, so the standby replica takes over without waiting for the lease to expire
+		LeaderElectionReleaseOnCancel: true,
+	}
+	if agentConfig.Standalone {
+		options.NewCache = initStandaloneCache
+	}
+	if agentConfig.EnableDiagnostics {
+		handlers, err := diagnostics.ProtectedHandlers(restConfig, setupLog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to protect the diagnostics endpoints: %w", err)
+		}
+		options.Metrics.ExtraHandlers = handlers
 	}
 
 	mgr, err := ctrl.NewManager(restConfig, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a new manager: %w", err)
 	}
+	if agentConfig.LogLevelConfigMap != "" {
+		if err := logger.AddLevelWatcher(mgr, agentConfig.PodNameSpace, agentConfig.LogLevelConfigMap); err != nil {
+			return nil, fmt.Errorf("failed to add the log level watcher: %w", err)
+		}
+	}
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubeclient: %w", err)
 	}
+	if err := controllers.AddCertController(mgr, kubeClient); err != nil {
+		return nil, fmt.Errorf("failed to add crd controller: %w", err)
+	}
+
+	// the standalone cluster has no ACM hub, so the status syncers are added without waiting for the crd
+	if agentConfig.Standalone {
+		if err := controllers.AddStandaloneController(mgr, agentConfig); err != nil {
+			return nil, fmt.Errorf("failed to add standalone controller: %w", err)
+		}
+		return mgr, nil
+	}
+
+	// the global hub cluster can't be imported as a managed hub, otherwise the events loop back to the global hub
+	if err := controllers.CheckImportLoop(ctx, mgr.GetAPIReader()); err != nil {
+		return nil, err
+	}
+
 	// Need this controller to update the value of clusterclaim hub.open-cluster-management.io
 	// we use the value to decide whether install the ACM or not
 	if err := controllers.AddHubClusterClaimController(mgr); err != nil {
@@ -253,14 +324,11 @@ func createManager(ctx context.Context, restConfig *rest.Config, agentConfig *co
 		return nil, fmt.Errorf("failed to add crd controller: %w", err)
 	}
 
-	if err := controllers.AddCertController(mgr, kubeClient); err != nil {
-		return nil, fmt.Errorf("failed to add crd controller: %w", err)
-	}
-
 	return mgr, nil
 }
 
 func initCache(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error) {
+	cacheOpts.DefaultTransform = stripManagedFields
 	cacheOpts.ByObject = map[client.Object]cache.ByObject{
 		&apiextensionsv1.CustomResourceDefinition{}: {
 			Field: fields.OneTermEqualSelector("metadata.name", "clustermanagers.operator.open-cluster-management.io"),
@@ -285,3 +353,36 @@ func initCache(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error
 	}
 	return cache.New(config, cacheOpts)
 }
+
+// initStandaloneCache only caches the kubernetes resources, and the policies if the policy framework is installed,
+// since the ACM resources don't exist on the standalone cluster
+func initStandaloneCache(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error) {
+	cacheOpts.DefaultTransform = stripManagedFields
+	cacheOpts.ByObject = map[client.Object]cache.ByObject{
+		&coordinationv1.Lease{}: {
+			Field: fields.OneTermEqualSelector("metadata.namespace", constants.GHAgentNamespace),
+		},
+		&corev1.Event{}: {
+			Field: fields.OneTermEqualSelector("involvedObject.kind", policyv1.Kind),
+		},
+		&corev1.Secret{}: {
+			Field: fields.OneTermEqualSelector("metadata.namespace", constants.GHAgentNamespace),
+		},
+	}
+	_, err := cacheOpts.Mapper.RESTMapping(policyv1.GroupVersion.WithKind(policyv1.Kind).GroupKind())
+	if err == nil {
+		cacheOpts.ByObject[&policyv1.Policy{}] = cache.ByObject{}
+	} else if !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	return cache.New(config, cacheOpts)
+}
+
+// stripManagedFields drops the managed fields of the objects before they're stored in the cache, they're never read
+// by the agent but take a large part of the memory on the hub with thousands of the managed clusters
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+	return obj, nil
+}
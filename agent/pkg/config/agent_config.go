@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -18,4 +20,21 @@ type AgentConfig struct {
 	EnableGlobalResource         bool
 	QPS                          float32
 	Burst                        int
+	// the status events are buffered in the directory during the transport outage if it's specified
+	StatusBufferDir    string
+	StatusBufferSizeMB int
+	// the status events are signed with the key of the file if it's specified
+	SigningKeyPath string
+	// the agent runs on the cluster without the ACM hub, and reports the cluster itself to the global hub
+	Standalone bool
+	// the key metrics are queried from the prometheus of the managed hub on the interval if the url is specified
+	HubMetricsPrometheusURL string
+	HubMetricsCACertPath    string
+	HubMetricsInterval      time.Duration
+	// LogLevelConfigMap is the configmap of the log levels in the agent namespace, they're changed at runtime by it
+	LogLevelConfigMap string
+	// ScrubRulesConfigMap is the configmap of the rules to scrub the status events in the agent namespace
+	ScrubRulesConfigMap string
+	// the pprof, expvar and the diagnostics bundle are served under /debug of the metrics server if it's true
+	EnableDiagnostics bool
 }
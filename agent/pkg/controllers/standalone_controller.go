@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	statusController "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller"
+)
+
+// AddStandaloneController adds the status controllers of the standalone agent once it's elected as the leader. there
+// is no ACM hub on the standalone cluster, so it doesn't wait for the clustermanager crd like the crd controller, and
+// the spec controllers aren't added since there is no managed cluster to propagate the global resources to.
+func AddStandaloneController(mgr ctrl.Manager, agentConfig *config.AgentConfig) error {
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := statusController.AddStandaloneControllers(ctx, mgr, agentConfig); err != nil {
+			return fmt.Errorf("failed to add standalone status syncer: %w", err)
+		}
+		<-ctx.Done()
+		return nil
+	}))
+}
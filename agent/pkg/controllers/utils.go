@@ -123,3 +123,19 @@ func updateHubClusterClaim(ctx context.Context, k8sClient client.Client,
 	}
 	return mch, updateClusterClaim(ctx, k8sClient, constants.HubClusterClaimName, hubValue)
 }
+
+// CheckImportLoop returns an error if the agent is running on a global hub cluster, which is marked by the global hub
+// claim. the agent would send the events of the global hub back to itself, and the global hub would sync them to the
+// cluster again. run the global hubs hierarchically instead of importing one as the managed hub of the other.
+func CheckImportLoop(ctx context.Context, reader client.Reader) error {
+	clusterClaim := &clustersv1alpha1.ClusterClaim{}
+	err := reader.Get(ctx, client.ObjectKey{Name: constants.GlobalHubClusterClaimName}, clusterClaim)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the clusterclaim %s: %w", constants.GlobalHubClusterClaimName, err)
+	}
+	return fmt.Errorf("the cluster is running the global hub %s, the agent can't run on it to avoid the import loop",
+		clusterClaim.Spec.Value)
+}
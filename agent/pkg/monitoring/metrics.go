@@ -0,0 +1,79 @@
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var AgentBundleSizeHistogramVec = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_agent_bundle_size_bytes",
+		Help:    "The size of the status bundle sent by the agent, before it's compressed by the transport.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var AgentBundleSerializationDurationHistogramVec = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_agent_bundle_serialization_duration_seconds",
+		Help:    "The duration to build the status bundle and serialize it into the CloudEvent.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var AgentProduceDurationHistogramVec = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_agent_produce_duration_seconds",
+		Help:    "The duration to send the status bundle to the transport, including the retries of the producer.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var AgentProduceErrorsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_produce_errors_total",
+		Help: "The number of failures to send the status bundles to the transport.",
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var AgentProducedBytesCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_produced_bytes_total",
+		Help: "The bytes of the status bundles sent by the agent to the transport, before they're compressed.",
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var AgentWatchCacheObjectsGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_agent_watch_cache_objects",
+		Help: "The number of the objects in the informer cache of the resource watched by the status syncers.",
+	},
+	[]string{
+		"kind", // The kind of the watched resource, e.g. ManagedCluster and Policy.
+	},
+)
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(AgentBundleSizeHistogramVec)
+	metrics.Registry.MustRegister(AgentBundleSerializationDurationHistogramVec)
+	metrics.Registry.MustRegister(AgentProduceDurationHistogramVec)
+	metrics.Registry.MustRegister(AgentProduceErrorsCounterVec)
+	metrics.Registry.MustRegister(AgentProducedBytesCounterVec)
+	metrics.Registry.MustRegister(AgentWatchCacheObjectsGaugeVec)
+}
@@ -45,5 +45,8 @@ func AddToManager(mgr ctrl.Manager, agentConfig *config.AgentConfig) error {
 	}
 
 	dispatcher.RegisterSyncer(constants.ResyncMsgKey, syncers.NewResyncSyncer())
+	dispatcher.RegisterSyncer(constants.DataCollectionMsgKey, syncers.NewDataCollectionSyncer())
+	dispatcher.RegisterSyncer(constants.ManagedClusterMigrationMsgKey,
+		syncers.NewManagedClusterMigrationSyncer(mgr.GetClient(), mgr.GetAPIReader()))
 	return nil
 }
@@ -0,0 +1,68 @@
+package syncers
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+// applyResults records the result of applying each global resource of the spec bundles, the deleted resources are
+// removed from them. the generation is increased once a result is changed, so the status syncer knows when to report.
+var applyResults = struct {
+	sync.RWMutex
+	results    map[string]spec.ApplyResult
+	generation uint64
+}{results: map[string]spec.ApplyResult{}}
+
+// GetApplyResults returns the current apply results and the generation of them.
+func GetApplyResults() (spec.ApplyResultBundle, uint64) {
+	applyResults.RLock()
+	defer applyResults.RUnlock()
+
+	results := make(spec.ApplyResultBundle, 0, len(applyResults.results))
+	for _, r := range applyResults.results {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return conflictKey(results[i].Kind, results[i].Namespace, results[i].Name) <
+			conflictKey(results[j].Kind, results[j].Namespace, results[j].Name)
+	})
+	return results, applyResults.generation
+}
+
+// recordApplyResult records the result of the resource, the generation isn't changed if the resource is applied again
+// with the same result, so the unchanged results aren't reported repeatedly
+func recordApplyResult(obj *unstructured.Unstructured, result, reason string) {
+	applyResults.Lock()
+	defer applyResults.Unlock()
+
+	key := conflictKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	if existing, found := applyResults.results[key]; found && existing.Result == result && existing.Reason == reason {
+		return
+	}
+	applyResults.results[key] = spec.ApplyResult{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		Result:     result,
+		Reason:     reason,
+		AppliedAt:  time.Now(),
+	}
+	applyResults.generation++
+}
+
+func removeApplyResult(obj *unstructured.Unstructured) {
+	applyResults.Lock()
+	defer applyResults.Unlock()
+
+	key := conflictKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	if _, found := applyResults.results[key]; found {
+		delete(applyResults.results, key)
+		applyResults.generation++
+	}
+}
@@ -0,0 +1,49 @@
+package syncers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+)
+
+// dataCollectionSyncer applies the data collection profile propagated by the manager, the status bundles which aren't
+// collected by the profile are dropped before they're sent
+type dataCollectionSyncer struct {
+	log logr.Logger
+}
+
+func NewDataCollectionSyncer() *dataCollectionSyncer {
+	return &dataCollectionSyncer{
+		log: ctrl.Log.WithName("data-collection-syncer"),
+	}
+}
+
+func (syncer *dataCollectionSyncer) Sync(payload []byte) error {
+	bundle := &specbundle.DataCollectionBundle{}
+	if err := json.Unmarshal(payload, bundle); err != nil {
+		return err
+	}
+	if !specbundle.IsValidDataCollectionProfile(bundle.Profile) {
+		return fmt.Errorf("unknown data collection profile: %s", bundle.Profile)
+	}
+
+	previous := statusconfig.GetDataCollectionProfile()
+	if previous == bundle.Profile {
+		return nil
+	}
+	statusconfig.SetDataCollectionProfile(bundle.Profile)
+	syncer.log.Info("data collection profile is changed", "from", previous, "to", bundle.Profile)
+
+	// the bundles which weren't collected are resynced, otherwise they aren't sent until the objects are changed
+	for eventType, resyncVersion := range supportedResyncTypes {
+		if !specbundle.Collects(previous, eventType) && specbundle.Collects(bundle.Profile, eventType) {
+			resyncVersion.Incr()
+		}
+	}
+	return nil
+}
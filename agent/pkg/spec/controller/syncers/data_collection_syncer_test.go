@@ -0,0 +1,41 @@
+package syncers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestDataCollectionSyncer(t *testing.T) {
+	clusterVersion, placementVersion := version.NewVersion(), version.NewVersion()
+	SupportResyc(string(enum.ManagedClusterType), clusterVersion)
+	SupportResyc(string(enum.PlacementDecisionType), placementVersion)
+	defer statusconfig.SetDataCollectionProfile(specbundle.DataCollectionFull)
+
+	syncer := NewDataCollectionSyncer()
+	sync := func(profile string) error {
+		payload, err := json.Marshal(specbundle.DataCollectionBundle{Profile: profile})
+		assert.Nil(t, err)
+		return syncer.Sync(payload)
+	}
+
+	assert.Nil(t, sync(specbundle.DataCollectionMinimal))
+	assert.Equal(t, specbundle.DataCollectionMinimal, statusconfig.GetDataCollectionProfile())
+	assert.False(t, statusconfig.IsCollected(string(enum.PlacementDecisionType)))
+	assert.Equal(t, uint64(0), placementVersion.Value)
+
+	// the placement decisions are resynced once they're collected again
+	assert.Nil(t, sync(specbundle.DataCollectionStandard))
+	assert.True(t, statusconfig.IsCollected(string(enum.PlacementDecisionType)))
+	assert.Equal(t, uint64(1), placementVersion.Value)
+	assert.Equal(t, uint64(0), clusterVersion.Value)
+
+	assert.ErrorContains(t, sync("Unknown"), "unknown data collection profile")
+	assert.Equal(t, specbundle.DataCollectionStandard, statusconfig.GetDataCollectionProfile())
+}
@@ -3,9 +3,12 @@ package syncers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -14,6 +17,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/rbac"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/workers"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 	helper "github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -61,11 +65,26 @@ func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Uns
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
 
+			foreign, err := isForeignManagedServiceAccount(ctx, k8sClient, unstructuredObject)
+			if err != nil {
+				syncer.log.Error(err, "failed to get the managed cluster", "name", unstructuredObject.GetNamespace())
+				recordApplyResult(unstructuredObject, spec.ApplyResultFailed,
+					fmt.Sprintf("failed to get the managed cluster: %v", err))
+				return
+			}
+			if foreign {
+				syncer.log.V(2).Info("skip the managed service account of the cluster on the other hub", "name",
+					unstructuredObject.GetName(), "namespace", unstructuredObject.GetNamespace())
+				return
+			}
+
 			if !syncer.enforceHohRbac { // if rbac not enforced, create missing namespaces.
 				if err := utils.CreateNamespaceIfNotExist(ctx, k8sClient,
 					unstructuredObject.GetNamespace()); err != nil {
 					syncer.log.Error(err, "failed to create namespace",
 						"namespace", unstructuredObject.GetNamespace())
+					recordApplyResult(unstructuredObject, spec.ApplyResultFailed,
+						fmt.Sprintf("failed to create namespace: %v", err))
 					return
 				}
 			}
@@ -78,21 +97,28 @@ func (syncer *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Uns
 			// Reference:
 			//   "spec.spreadPolicy": https://github.com/open-cluster-management-io/api/pull/225
 			//   "spec.decisionStrategy": https://github.com/open-cluster-management-io/api/pull/242
-			spec, ok := unstructuredObject.Object["spec"]
+			objSpec, ok := unstructuredObject.Object["spec"]
 			if ok {
-				specMap := spec.(map[string]interface{})
+				specMap := objSpec.(map[string]interface{})
 				delete(specMap, "decisionStrategy")
 				delete(specMap, "spreadPolicy")
 				unstructuredObject.Object["spec"] = specMap
 			}
 
 			delete(unstructuredObject.Object, "status")
-			err := helper.UpdateObject(ctx, k8sClient, unstructuredObject)
+
+			if syncer.skipByConflictPolicy(ctx, k8sClient, unstructuredObject) {
+				return
+			}
+
+			err = helper.UpdateObject(ctx, k8sClient, unstructuredObject)
 			if err != nil {
 				syncer.log.Error(err, "failed to update object", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
+				recordApplyResult(unstructuredObject, spec.ApplyResultFailed, err.Error())
 				return
 			}
+			recordApplyResult(unstructuredObject, spec.ApplyResultApplied, "")
 			syncer.log.V(2).Info("object updated", "name", unstructuredObject.GetName(), "namespace",
 				unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 		}))
@@ -111,13 +137,20 @@ func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstruct
 			defer syncer.bundleProcessingWaitingGroup.Done()
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
+			resolveResourceConflict(unstructuredObject)
 
 			// syncer.deleteObject(ctx, k8sClient, obj.(*unstructured.Unstructured))
-			if deleted, err := helper.DeleteObject(ctx, k8sClient, unstructuredObject); err != nil {
+			deleted, err := helper.DeleteObject(ctx, k8sClient, unstructuredObject)
+			if err != nil {
 				syncer.log.Error(err, "failed to delete object", "name",
 					unstructuredObject.GetName(), "namespace",
 					unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
-			} else if deleted {
+				recordApplyResult(unstructuredObject, spec.ApplyResultFailed,
+					fmt.Sprintf("failed to delete: %v", err))
+				return
+			}
+			removeApplyResult(unstructuredObject)
+			if deleted {
 				syncer.log.Info("object deleted", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
 			}
@@ -125,6 +158,48 @@ func (syncer *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstruct
 	}
 }
 
+// skipByConflictPolicy returns true if the object is modified on the managed hub and its conflict policy is to keep
+// the local modification, the conflict is recorded if the policy is to report it.
+func (syncer *genericBundleSyncer) skipByConflictPolicy(ctx context.Context, k8sClient client.Client,
+	obj *unstructured.Unstructured,
+) bool {
+	policy := obj.GetAnnotations()[constants.ConflictPolicyAnnotation]
+	if policy != constants.ConflictPolicyIgnore && policy != constants.ConflictPolicyReport {
+		resolveResourceConflict(obj)
+		return false
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if apierrors.IsNotFound(err) {
+		resolveResourceConflict(obj)
+		return false
+	}
+	if err != nil {
+		syncer.log.Error(err, "failed to get object", "name", obj.GetName(), "namespace", obj.GetNamespace(),
+			"kind", obj.GetKind())
+		return false
+	}
+
+	fieldManagers := helper.GetLocalFieldManagers(existing, obj)
+	if len(fieldManagers) == 0 {
+		resolveResourceConflict(obj)
+		return false
+	}
+
+	syncer.log.Info("skip the object modified on the managed hub", "name", obj.GetName(), "namespace",
+		obj.GetNamespace(), "kind", obj.GetKind(), "policy", policy, "fieldManagers", fieldManagers)
+	if policy == constants.ConflictPolicyReport {
+		recordResourceConflict(obj, policy, fieldManagers)
+	} else {
+		resolveResourceConflict(obj)
+	}
+	recordApplyResult(obj, spec.ApplyResultConflicted,
+		fmt.Sprintf("modified on the managed hub by %s", strings.Join(fieldManagers, ", ")))
+	return true
+}
+
 func (syncer *genericBundleSyncer) anonymize(obj *unstructured.Unstructured) *unstructured.Unstructured {
 	annotations := obj.GetAnnotations()
 	delete(annotations, rbac.UserIdentityAnnotation)
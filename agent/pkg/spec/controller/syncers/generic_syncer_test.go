@@ -13,7 +13,9 @@ import (
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/syncers"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
@@ -61,6 +63,20 @@ var _ = Describe("GenericBundle", func() {
 			}
 			return err
 		}, 10*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+
+		By("Check the apply result of the placement is recorded")
+		Eventually(func() error {
+			results, _ := syncers.GetApplyResults()
+			for _, result := range results {
+				if result.Kind == "Placement" && result.Name == placement.Name {
+					if result.Result != spec.ApplyResultApplied {
+						return fmt.Errorf("unexpected apply result: %v", result)
+					}
+					return nil
+				}
+			}
+			return fmt.Errorf("the apply result of the placement isn't recorded: %v", results)
+		}, 10*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
 	})
 
 	It("sync placementbinding bundle", func() {
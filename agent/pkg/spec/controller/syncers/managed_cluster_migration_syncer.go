@@ -0,0 +1,283 @@
+package syncers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const (
+	// the secret of the bootstrap kubeconfig used by the klusterlet to register the cluster to the hub
+	bootstrapSecretName      = "bootstrap-hub-kubeconfig"
+	bootstrapSecretNamespace = "open-cluster-management-agent"
+	// the status of the migration is removed from the agent once it isn't updated within the ttl
+	migrationStatusTTL    = 24 * time.Hour
+	migrationPhaseTimeout = 30 * time.Second
+)
+
+// migrationStatuses records the results of running the migration phases on the hub. the generation is increased once
+// a phase is run, so the status syncer reports the result for each of the requests from the manager.
+var migrationStatuses = struct {
+	sync.RWMutex
+	statuses   map[int64]spec.ManagedClusterMigrationStatus
+	generation uint64
+}{statuses: map[int64]spec.ManagedClusterMigrationStatus{}}
+
+// GetMigrationStatuses returns the current migration statuses and the generation of them.
+func GetMigrationStatuses() (spec.ManagedClusterMigrationBundle, uint64) {
+	migrationStatuses.RLock()
+	defer migrationStatuses.RUnlock()
+
+	statuses := make(spec.ManagedClusterMigrationBundle, 0, len(migrationStatuses.statuses))
+	for _, s := range migrationStatuses.statuses {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].MigrationID < statuses[j].MigrationID })
+	return statuses, migrationStatuses.generation
+}
+
+func recordMigrationStatus(status spec.ManagedClusterMigrationStatus) {
+	migrationStatuses.Lock()
+	defer migrationStatuses.Unlock()
+
+	for id, s := range migrationStatuses.statuses {
+		if time.Since(s.UpdatedAt) > migrationStatusTTL {
+			delete(migrationStatuses.statuses, id)
+		}
+	}
+	migrationStatuses.statuses[status.MigrationID] = status
+	migrationStatuses.generation++
+}
+
+// managedClusterMigrationSyncer runs the phases of migrating the managed cluster between the hubs. each phase is
+// idempotent, the manager requests it repeatedly until it's completed.
+type managedClusterMigrationSyncer struct {
+	log    logr.Logger
+	client client.Client
+	// the secrets and the manifestworks are read without the cache
+	reader client.Reader
+}
+
+func NewManagedClusterMigrationSyncer(c client.Client, reader client.Reader) *managedClusterMigrationSyncer {
+	return &managedClusterMigrationSyncer{
+		log:    ctrl.Log.WithName("managed-cluster-migration-syncer"),
+		client: c,
+		reader: reader,
+	}
+}
+
+func (s *managedClusterMigrationSyncer) Sync(payload []byte) error {
+	migration := &spec.ManagedClusterMigrationSpec{}
+	if err := json.Unmarshal(payload, migration); err != nil {
+		return err
+	}
+	s.log.V(2).Info("run the migration phase", "id", migration.MigrationID, "phase", migration.Phase,
+		"cluster", migration.ClusterName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrationPhaseTimeout)
+	defer cancel()
+
+	status := spec.ManagedClusterMigrationStatus{
+		MigrationID: migration.MigrationID,
+		Phase:       migration.Phase,
+		ClusterName: migration.ClusterName,
+	}
+	var err error
+	switch migration.Phase {
+	case spec.MigrationRegistering:
+		status.BootstrapKubeconfig, status.Completed, err = s.register(ctx, migration)
+	case spec.MigrationDeploying:
+		status.Completed, err = s.deploy(ctx, migration)
+	case spec.MigrationJoining:
+		status.Completed, err = s.join(ctx, migration)
+	case spec.MigrationCleaning:
+		status.Completed, err = s.clean(ctx, migration)
+	default:
+		err = fmt.Errorf("unknown migration phase %s", migration.Phase)
+	}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	status.UpdatedAt = time.Now()
+	recordMigrationStatus(status)
+	return err
+}
+
+// register creates the managed cluster on the target hub, and returns the bootstrap kubeconfig in the import secret of
+// the cluster once it's generated by the import controller
+func (s *managedClusterMigrationSyncer) register(ctx context.Context, migration *spec.ManagedClusterMigrationSpec,
+) ([]byte, bool, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	err := s.client.Get(ctx, client.ObjectKey{Name: migration.ClusterName}, cluster)
+	if k8serrors.IsNotFound(err) {
+		cluster = &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: migration.ClusterName,
+				Annotations: map[string]string{
+					constants.ManagedClusterMigratedFromAnnotation: migration.FromHub,
+				},
+			},
+			Spec: clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+		}
+		if err := s.client.Create(ctx, cluster); err != nil {
+			return nil, false, fmt.Errorf("failed to create the managed cluster: %w", err)
+		}
+	} else if err != nil {
+		return nil, false, err
+	} else if _, found := cluster.Annotations[constants.ManagedClusterMigratedFromAnnotation]; !found {
+		return nil, false, fmt.Errorf("the managed cluster %s already exists on the hub", migration.ClusterName)
+	}
+
+	importSecret := &corev1.Secret{}
+	err = s.reader.Get(ctx, client.ObjectKey{
+		Namespace: migration.ClusterName,
+		Name:      fmt.Sprintf("%s-import", migration.ClusterName),
+	}, importSecret)
+	if k8serrors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	kubeconfig, err := getBootstrapKubeconfig(importSecret.Data["import.yaml"])
+	if err != nil {
+		return nil, false, err
+	}
+	return kubeconfig, true, nil
+}
+
+// deploy applies the bootstrap kubeconfig of the target hub to the klusterlet on the cluster with a manifestwork, then
+// the klusterlet bootstraps with the target hub. the secret is orphaned once the manifestwork is deleted.
+func (s *managedClusterMigrationSyncer) deploy(ctx context.Context, migration *spec.ManagedClusterMigrationSpec,
+) (bool, error) {
+	if len(migration.BootstrapKubeconfig) == 0 {
+		return false, errors.New("the bootstrap kubeconfig of the target hub is empty")
+	}
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapSecretName,
+			Namespace: bootstrapSecretNamespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"kubeconfig": migration.BootstrapKubeconfig},
+	}
+	raw, err := json.Marshal(secret)
+	if err != nil {
+		return false, err
+	}
+	workSpec := workv1.ManifestWorkSpec{
+		Workload: workv1.ManifestsTemplate{
+			Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: raw}}},
+		},
+		DeleteOption: &workv1.DeleteOption{PropagationPolicy: workv1.DeletePropagationPolicyTypeOrphan},
+	}
+
+	work := &workv1.ManifestWork{}
+	err = s.reader.Get(ctx, migrationWorkKey(migration.ClusterName), work)
+	if k8serrors.IsNotFound(err) {
+		work = &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      migrationWorkKey(migration.ClusterName).Name,
+				Namespace: migration.ClusterName,
+			},
+			Spec: workSpec,
+		}
+		return false, s.client.Create(ctx, work)
+	} else if err != nil {
+		return false, err
+	}
+	if len(work.Spec.Workload.Manifests) != 1 || !bytes.Equal(work.Spec.Workload.Manifests[0].Raw, raw) {
+		work.Spec = workSpec
+		return false, s.client.Update(ctx, work)
+	}
+	return meta.IsStatusConditionTrue(work.Status.Conditions, workv1.WorkApplied), nil
+}
+
+// join waits for the cluster to be available on the target hub
+func (s *managedClusterMigrationSyncer) join(ctx context.Context, migration *spec.ManagedClusterMigrationSpec,
+) (bool, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	if err := s.client.Get(ctx, client.ObjectKey{Name: migration.ClusterName}, cluster); err != nil {
+		return false, err
+	}
+	return meta.IsStatusConditionTrue(cluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable), nil
+}
+
+// clean removes the cluster from the source hub, the klusterlet isn't removed from the cluster since it has joined the
+// target hub
+func (s *managedClusterMigrationSyncer) clean(ctx context.Context, migration *spec.ManagedClusterMigrationSpec,
+) (bool, error) {
+	work := &workv1.ManifestWork{}
+	err := s.reader.Get(ctx, migrationWorkKey(migration.ClusterName), work)
+	if err == nil {
+		if err := s.client.Delete(ctx, work); err != nil && !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+	} else if !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: migration.ClusterName}}
+	if err := s.client.Delete(ctx, cluster); err != nil && !k8serrors.IsNotFound(err) {
+		return false, err
+	}
+	// the cluster is deleted once its resources are cleaned up by the finalizers
+	err = s.reader.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)
+	if k8serrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+func migrationWorkKey(clusterName string) client.ObjectKey {
+	return client.ObjectKey{Namespace: clusterName, Name: fmt.Sprintf("%s-migration", clusterName)}
+}
+
+// getBootstrapKubeconfig returns the kubeconfig of the bootstrap secret in the import manifests of the cluster
+func getBootstrapKubeconfig(importYAML []byte) ([]byte, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(importYAML), 4096)
+	for {
+		obj := map[string]interface{}{}
+		if err := decoder.Decode(&obj); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode the import manifests: %w", err)
+		}
+		if obj["kind"] != "Secret" {
+			continue
+		}
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		secret := &corev1.Secret{}
+		if err := json.Unmarshal(raw, secret); err != nil {
+			return nil, err
+		}
+		if secret.Name == bootstrapSecretName && len(secret.Data["kubeconfig"]) > 0 {
+			return secret.Data["kubeconfig"], nil
+		}
+	}
+	return nil, errors.New("the bootstrap kubeconfig isn't found in the import secret")
+}
@@ -0,0 +1,41 @@
+package syncers
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	managedServiceAccountGroupKind = schema.GroupKind{
+		Group: "authentication.open-cluster-management.io",
+		Kind:  "ManagedServiceAccount",
+	}
+	managedClusterGVK = schema.GroupVersionKind{
+		Group:   "cluster.open-cluster-management.io",
+		Version: "v1",
+		Kind:    "ManagedCluster",
+	}
+)
+
+// isForeignManagedServiceAccount returns true if the object is a managed service account of the cluster which isn't
+// managed by the hub. The account is requested in the namespace of the managed cluster, and it's sent to all the
+// managed hubs, so only the hub of the cluster applies it, the others don't create the cluster namespace for it.
+func isForeignManagedServiceAccount(ctx context.Context, k8sClient client.Client,
+	obj *unstructured.Unstructured,
+) (bool, error) {
+	if obj.GroupVersionKind().GroupKind() != managedServiceAccountGroupKind {
+		return false, nil
+	}
+	cluster := &metav1.PartialObjectMetadata{}
+	cluster.SetGroupVersionKind(managedClusterGVK)
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, cluster)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
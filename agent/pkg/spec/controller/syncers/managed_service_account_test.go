@@ -0,0 +1,59 @@
+package syncers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIsForeignManagedServiceAccount(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.Nil(t, clusterv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+	}).Build()
+
+	newObject := func(apiVersion, kind, namespace string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName("automation")
+		return obj
+	}
+
+	cases := []struct {
+		name     string
+		obj      *unstructured.Unstructured
+		expected bool
+	}{
+		{
+			"the account of the cluster on the hub",
+			newObject("authentication.open-cluster-management.io/v1beta1", "ManagedServiceAccount", "cluster1"),
+			false,
+		},
+		{
+			"the account of the cluster on the other hub",
+			newObject("authentication.open-cluster-management.io/v1beta1", "ManagedServiceAccount", "cluster2"),
+			true,
+		},
+		{
+			"the other resource in the namespace without the cluster",
+			newObject("apps.open-cluster-management.io/v1", "Subscription", "cluster2"),
+			false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			foreign, err := isForeignManagedServiceAccount(context.Background(), k8sClient, c.obj)
+			assert.Nil(t, err)
+			assert.Equal(t, c.expected, foreign)
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 var supportedResyncTypes map[string]*version.Version
@@ -31,16 +32,23 @@ func (syncer *resyncSyncer) Sync(payload []byte) error {
 		return err
 	}
 
+	if supportedResyncTypes == nil {
+		syncer.log.Info("not support to resync any type of resources")
+		return nil
+	}
+
 	for _, eventType := range eventTypes {
 		syncer.log.Info("Resync event", "key", eventType)
-		if supportedResyncTypes == nil {
-			syncer.log.Info("not support to resync any type of resources")
+		if eventType == constants.ResyncAllEventTypes {
+			for _, resyncVersion := range supportedResyncTypes {
+				resyncVersion.Incr()
+			}
 			return nil
 		}
 		resyncVersion, ok := supportedResyncTypes[eventType]
 		if !ok {
 			syncer.log.Info("not support to resync the current resource type", "event key", eventType)
-			return nil
+			continue
 		}
 		resyncVersion.Incr()
 	}
@@ -0,0 +1,72 @@
+package syncers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/conflict"
+)
+
+// resourceConflicts records the global resources which are modified locally and reported by the conflict policy.
+// the generation is increased once the conflicts are changed, so the status syncer knows when to report them.
+var resourceConflicts = struct {
+	sync.RWMutex
+	conflicts  map[string]conflict.ResourceConflict
+	generation uint64
+}{conflicts: map[string]conflict.ResourceConflict{}}
+
+// GetResourceConflicts returns the current conflicts and the generation of them.
+func GetResourceConflicts() (conflict.ResourceConflictBundle, uint64) {
+	resourceConflicts.RLock()
+	defer resourceConflicts.RUnlock()
+
+	conflicts := make(conflict.ResourceConflictBundle, 0, len(resourceConflicts.conflicts))
+	for _, c := range resourceConflicts.conflicts {
+		conflicts = append(conflicts, c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflictKey(conflicts[i].Kind, conflicts[i].Namespace, conflicts[i].Name) <
+			conflictKey(conflicts[j].Kind, conflicts[j].Namespace, conflicts[j].Name)
+	})
+	return conflicts, resourceConflicts.generation
+}
+
+func recordResourceConflict(obj *unstructured.Unstructured, policy string, fieldManagers []string) {
+	resourceConflicts.Lock()
+	defer resourceConflicts.Unlock()
+
+	key := conflictKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	if existing, found := resourceConflicts.conflicts[key]; found && existing.Policy == policy &&
+		fmt.Sprint(existing.FieldManagers) == fmt.Sprint(fieldManagers) {
+		return
+	}
+	resourceConflicts.conflicts[key] = conflict.ResourceConflict{
+		APIVersion:    obj.GetAPIVersion(),
+		Kind:          obj.GetKind(),
+		Namespace:     obj.GetNamespace(),
+		Name:          obj.GetName(),
+		Policy:        policy,
+		FieldManagers: fieldManagers,
+		DetectedAt:    time.Now(),
+	}
+	resourceConflicts.generation++
+}
+
+func resolveResourceConflict(obj *unstructured.Unstructured) {
+	resourceConflicts.Lock()
+	defer resourceConflicts.Unlock()
+
+	key := conflictKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	if _, found := resourceConflicts.conflicts[key]; found {
+		delete(resourceConflicts.conflicts, key)
+		resourceConflicts.generation++
+	}
+}
+
+func conflictKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
@@ -0,0 +1,199 @@
+package addons
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// LaunchManagedClusterAddonSyncer reports the health of the addons on all the managed clusters of the hub, which is
+// collected from the conditions of the ManagedClusterAddOns. The syncer is skipped if the addon crd isn't installed.
+func LaunchManagedClusterAddonSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	gvk := addonv1alpha1.GroupVersion.WithKind("ManagedClusterAddOn")
+	_, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		ctrl.Log.WithName("status.managed_cluster_addon").Info("skip the addon syncer, the addon crd isn't installed")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the addons are listed by the api reader page by page, rather than caching the addons of all the clusters
+	return generic.LaunchGenericEventSyncer(
+		"status.managed_cluster_addon",
+		mgr,
+		nil,
+		producer,
+		config.GetManagerClusterDuration,
+		NewManagedClusterAddonEmitter(mgr.GetAPIReader(), mgr.GetClient()),
+	)
+}
+
+var _ generic.Emitter = &addonEmitter{}
+
+// NewManagedClusterAddonEmitter lists the addons with the reader, and the managed clusters with the client to exclude
+// the addons of the clusters which don't match the managed cluster selector
+func NewManagedClusterAddonEmitter(reader client.Reader, clusterClient client.Client) *addonEmitter {
+	return &addonEmitter{
+		log:             ctrl.Log.WithName("managed-cluster-addon"),
+		reader:          reader,
+		client:          clusterClient,
+		eventType:       enum.ManagedClusterAddonType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+}
+
+type addonEmitter struct {
+	log             logr.Logger
+	reader          client.Reader
+	client          client.Client
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	addons          cluster.ManagedClusterAddonBundle
+}
+
+// the addons are listed on each sync, not updated by the event controllers
+func (s *addonEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *addonEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *addonEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, s.addons)
+	return &e, err
+}
+
+func (s *addonEmitter) Topic() string { return "" }
+
+// ShouldSend sends the addons once the agent is started, so the removed addons are cleaned up on the global hub, then
+// only when the health of them is changed
+func (s *addonEmitter) ShouldSend() bool {
+	addons, err := s.collectAddons(context.Background())
+	if err != nil {
+		s.log.Error(err, "failed to list the managed cluster addons")
+		return false
+	}
+	if s.addons == nil || !reflect.DeepEqual(addons, s.addons) {
+		s.addons = addons
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *addonEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+func (s *addonEmitter) collectAddons(ctx context.Context) (cluster.ManagedClusterAddonBundle, error) {
+	excludedClusters, err := excludedClusters(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	addons := cluster.ManagedClusterAddonBundle{}
+	err = generic.PagedList(ctx, s.reader, &addonv1alpha1.ManagedClusterAddOnList{}, func(object runtime.Object) error {
+		addon := object.(*addonv1alpha1.ManagedClusterAddOn)
+		// the addon is in the namespace of the managed cluster
+		if !excludedClusters[addon.Namespace] {
+			addons = append(addons, NormalizeAddon(addon))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortAddons(addons)
+	return addons, nil
+}
+
+// excludedClusters returns the managed clusters which don't match the managed cluster selector of the agent config
+func excludedClusters(ctx context.Context, clusterClient client.Client) (map[string]bool, error) {
+	excluded := map[string]bool{}
+	clusterSelector := config.GetSelector(config.ManagedClusterSelectorKey)
+	if clusterSelector.Empty() {
+		return excluded, nil
+	}
+	clusters := &clusterv1.ManagedClusterList{}
+	if err := clusterClient.List(ctx, clusters); err != nil {
+		return nil, err
+	}
+	for _, c := range clusters.Items {
+		if !clusterSelector.Matches(labels.Set(c.GetLabels())) {
+			excluded[c.GetName()] = true
+		}
+	}
+	return excluded, nil
+}
+
+// NormalizeAddon derives the health of the addon from its conditions: it's degraded if the addon isn't available or
+// it's reported as degraded, progressing if its configurations are being applied, and available if the addon agent
+// is running, otherwise it's unknown, e.g. the lease of the addon agent isn't updated yet.
+func NormalizeAddon(addon *addonv1alpha1.ManagedClusterAddOn) cluster.ManagedClusterAddon {
+	result := cluster.ManagedClusterAddon{
+		ClusterName: addon.Namespace,
+		AddonName:   addon.Name,
+		Status:      cluster.AddonStatusUnknown,
+	}
+
+	conditions := addon.Status.Conditions
+	available := meta.FindStatusCondition(conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable)
+	degraded := meta.FindStatusCondition(conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded)
+	progressing := meta.FindStatusCondition(conditions, addonv1alpha1.ManagedClusterAddOnConditionProgressing)
+
+	var condition *metav1.Condition
+	switch {
+	case degraded != nil && degraded.Status == metav1.ConditionTrue:
+		result.Status, condition = cluster.AddonStatusDegraded, degraded
+	case available != nil && available.Status == metav1.ConditionFalse:
+		result.Status, condition = cluster.AddonStatusDegraded, available
+	case progressing != nil && progressing.Status == metav1.ConditionTrue:
+		result.Status, condition = cluster.AddonStatusProgressing, progressing
+	case available != nil && available.Status == metav1.ConditionTrue:
+		result.Status, condition = cluster.AddonStatusAvailable, available
+	default:
+		condition = available
+	}
+	if condition != nil {
+		result.Reason = condition.Reason
+		result.Message = condition.Message
+		transitionTime := condition.LastTransitionTime.Time
+		result.LastTransitionTime = &transitionTime
+	}
+	return result
+}
+
+func sortAddons(addons cluster.ManagedClusterAddonBundle) {
+	sort.Slice(addons, func(i, j int) bool {
+		if addons[i].ClusterName != addons[j].ClusterName {
+			return addons[i].ClusterName < addons[j].ClusterName
+		}
+		return addons[i].AddonName < addons[j].AddonName
+	})
+}
@@ -0,0 +1,66 @@
+package addons
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+func newAddon(conditions ...metav1.Condition) *addonv1alpha1.ManagedClusterAddOn {
+	return &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: "observability-controller", Namespace: "cluster1"},
+		Status:     addonv1alpha1.ManagedClusterAddOnStatus{Conditions: conditions},
+	}
+}
+
+func TestNormalizeAddon(t *testing.T) {
+	available := metav1.Condition{
+		Type:   addonv1alpha1.ManagedClusterAddOnConditionAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: "ManagedClusterAddOnLeaseUpdated",
+	}
+	unavailable := metav1.Condition{
+		Type:    addonv1alpha1.ManagedClusterAddOnConditionAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ManagedClusterAddOnLeaseUpdateStopped",
+		Message: "observability-controller add-on is not available.",
+	}
+	degraded := metav1.Condition{
+		Type:   addonv1alpha1.ManagedClusterAddOnConditionDegraded,
+		Status: metav1.ConditionTrue,
+		Reason: "Degraded",
+	}
+	progressing := metav1.Condition{
+		Type:   addonv1alpha1.ManagedClusterAddOnConditionProgressing,
+		Status: metav1.ConditionTrue,
+		Reason: "Upgrading",
+	}
+
+	cases := []struct {
+		name           string
+		addon          *addonv1alpha1.ManagedClusterAddOn
+		expectedStatus string
+		expectedReason string
+	}{
+		{"available", newAddon(available), cluster.AddonStatusAvailable, available.Reason},
+		{"unavailable", newAddon(unavailable), cluster.AddonStatusDegraded, unavailable.Reason},
+		{"degraded", newAddon(available, degraded), cluster.AddonStatusDegraded, degraded.Reason},
+		{"progressing", newAddon(available, progressing), cluster.AddonStatusProgressing, progressing.Reason},
+		{"without conditions", newAddon(), cluster.AddonStatusUnknown, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addon := NormalizeAddon(c.addon)
+			if addon.ClusterName != "cluster1" || addon.AddonName != "observability-controller" {
+				t.Errorf("unexpected addon: %v", addon)
+			}
+			if addon.Status != c.expectedStatus || addon.Reason != c.expectedReason {
+				t.Errorf("expected %s with the reason %q, but got %s with %q", c.expectedStatus, c.expectedReason,
+					addon.Status, addon.Reason)
+			}
+		})
+	}
+}
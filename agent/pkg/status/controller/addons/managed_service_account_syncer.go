@@ -0,0 +1,223 @@
+package addons
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var ManagedServiceAccountGVK = schema.GroupVersionKind{
+	Group:   "authentication.open-cluster-management.io",
+	Version: "v1beta1",
+	Kind:    "ManagedServiceAccount",
+}
+
+// the conditions of the managed service account, the token is reported by the addon agent on the managed cluster,
+// then it's stored in the secret of the cluster namespace on the hub
+const (
+	serviceAccountConditionTokenReported = "TokenReported"
+	serviceAccountConditionSecretCreated = "SecretCreated"
+)
+
+// managedServiceAccountStatus is the status of the managed service account, only the metadata of the token is read
+type managedServiceAccountStatus struct {
+	Conditions          []metav1.Condition `json:"conditions,omitempty"`
+	ExpirationTimestamp *metav1.Time       `json:"expirationTimestamp,omitempty"`
+	TokenSecretRef      *struct {
+		Name                 string      `json:"name"`
+		LastRefreshTimestamp metav1.Time `json:"lastRefreshTimestamp"`
+	} `json:"tokenSecretRef,omitempty"`
+}
+
+// LaunchManagedServiceAccountSyncer reports the credentials of the managed service accounts on all the managed clusters
+// of the hub, the tokens stay in the secrets of the hub. The syncer is skipped if the managed-serviceaccount addon
+// isn't installed.
+func LaunchManagedServiceAccountSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(ManagedServiceAccountGVK.GroupKind(), ManagedServiceAccountGVK.Version)
+	if meta.IsNoMatchError(err) {
+		ctrl.Log.WithName("status.managed_service_account").Info(
+			"skip the managed service account syncer, the crd isn't installed")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return generic.LaunchGenericEventSyncer(
+		"status.managed_service_account",
+		mgr,
+		nil,
+		producer,
+		config.GetManagerClusterDuration,
+		NewManagedServiceAccountEmitter(mgr.GetAPIReader(), mgr.GetClient()),
+	)
+}
+
+var _ generic.Emitter = &managedServiceAccountEmitter{}
+
+// NewManagedServiceAccountEmitter lists the managed service accounts with the reader, and the managed clusters with the
+// client to exclude the accounts of the clusters which don't match the managed cluster selector
+func NewManagedServiceAccountEmitter(reader client.Reader, clusterClient client.Client) *managedServiceAccountEmitter {
+	return &managedServiceAccountEmitter{
+		log:             ctrl.Log.WithName("managed-service-account"),
+		reader:          reader,
+		client:          clusterClient,
+		eventType:       enum.ManagedServiceAccountType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+}
+
+type managedServiceAccountEmitter struct {
+	log             logr.Logger
+	reader          client.Reader
+	client          client.Client
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	accounts        cluster.ManagedServiceAccountBundle
+}
+
+// the accounts are listed on each sync, not updated by the event controllers
+func (s *managedServiceAccountEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *managedServiceAccountEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *managedServiceAccountEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, s.accounts)
+	return &e, err
+}
+
+func (s *managedServiceAccountEmitter) Topic() string { return "" }
+
+// ShouldSend sends the accounts once the agent is started, so the removed accounts are cleaned up on the global hub,
+// then only when the credentials of them are changed, e.g. the token is rotated
+func (s *managedServiceAccountEmitter) ShouldSend() bool {
+	accounts, err := s.collectAccounts(context.Background())
+	if err != nil {
+		s.log.Error(err, "failed to list the managed service accounts")
+		return false
+	}
+	if s.accounts == nil || !reflect.DeepEqual(accounts, s.accounts) {
+		s.accounts = accounts
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *managedServiceAccountEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+func (s *managedServiceAccountEmitter) collectAccounts(ctx context.Context) (
+	cluster.ManagedServiceAccountBundle, error,
+) {
+	excludedClusters, err := excludedClusters(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(ManagedServiceAccountGVK.GroupVersion().WithKind(ManagedServiceAccountGVK.Kind + "List"))
+	accounts := cluster.ManagedServiceAccountBundle{}
+	err = generic.PagedList(ctx, s.reader, list, func(object runtime.Object) error {
+		account, ok := object.(*unstructured.Unstructured)
+		// the account is in the namespace of the managed cluster
+		if !ok || excludedClusters[account.GetNamespace()] {
+			return nil
+		}
+		normalized, err := NormalizeManagedServiceAccount(account)
+		if err != nil {
+			s.log.Error(err, "skip the invalid managed service account", "namespace", account.GetNamespace(),
+				"name", account.GetName())
+			return nil
+		}
+		accounts = append(accounts, normalized)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].ClusterName != accounts[j].ClusterName {
+			return accounts[i].ClusterName < accounts[j].ClusterName
+		}
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts, nil
+}
+
+// NormalizeManagedServiceAccount derives the status of the credential from the conditions: it's failed if the token
+// isn't reported or the secret isn't created, ready once both of them are done, otherwise pending, e.g. the addon agent
+// isn't running on the managed cluster yet. Only the reference of the token secret is read, not the token.
+func NormalizeManagedServiceAccount(account *unstructured.Unstructured) (cluster.ManagedServiceAccount, error) {
+	result := cluster.ManagedServiceAccount{
+		ClusterName: account.GetNamespace(),
+		Name:        account.GetName(),
+		Status:      cluster.ServiceAccountStatusPending,
+	}
+
+	status := managedServiceAccountStatus{}
+	if statusObj, found, _ := unstructured.NestedMap(account.Object, "status"); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusObj, &status); err != nil {
+			return result, err
+		}
+	}
+
+	tokenReported := meta.FindStatusCondition(status.Conditions, serviceAccountConditionTokenReported)
+	secretCreated := meta.FindStatusCondition(status.Conditions, serviceAccountConditionSecretCreated)
+
+	var condition *metav1.Condition
+	switch {
+	case tokenReported != nil && tokenReported.Status == metav1.ConditionFalse:
+		result.Status, condition = cluster.ServiceAccountStatusFailed, tokenReported
+	case secretCreated != nil && secretCreated.Status == metav1.ConditionFalse:
+		result.Status, condition = cluster.ServiceAccountStatusFailed, secretCreated
+	case tokenReported != nil && tokenReported.Status == metav1.ConditionTrue &&
+		secretCreated != nil && secretCreated.Status == metav1.ConditionTrue:
+		result.Status, condition = cluster.ServiceAccountStatusReady, secretCreated
+	default:
+		condition = tokenReported
+	}
+	if condition != nil {
+		result.Reason = condition.Reason
+		result.Message = condition.Message
+	}
+
+	if status.TokenSecretRef != nil {
+		result.TokenSecretName = status.TokenSecretRef.Name
+		if !status.TokenSecretRef.LastRefreshTimestamp.IsZero() {
+			lastRefreshTime := status.TokenSecretRef.LastRefreshTimestamp.UTC()
+			result.LastRefreshTime = &lastRefreshTime
+		}
+	}
+	if status.ExpirationTimestamp != nil {
+		expirationTime := status.ExpirationTimestamp.UTC()
+		result.ExpirationTime = &expirationTime
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package addons
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+func newManagedServiceAccount(status map[string]interface{}) *unstructured.Unstructured {
+	account := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "automation", "namespace": "cluster1"},
+	}}
+	account.SetGroupVersionKind(ManagedServiceAccountGVK)
+	if status != nil {
+		account.Object["status"] = status
+	}
+	return account
+}
+
+func newServiceAccountCondition(conditionType, status, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":               conditionType,
+		"status":             status,
+		"reason":             reason,
+		"message":            reason,
+		"lastTransitionTime": "2024-05-01T10:00:00Z",
+	}
+}
+
+func TestNormalizeManagedServiceAccount(t *testing.T) {
+	tokenReported := newServiceAccountCondition(serviceAccountConditionTokenReported, "True", "TokenReported")
+	tokenFailed := newServiceAccountCondition(serviceAccountConditionTokenReported, "False", "TokenReportFailed")
+	secretCreated := newServiceAccountCondition(serviceAccountConditionSecretCreated, "True", "SecretCreated")
+	secretFailed := newServiceAccountCondition(serviceAccountConditionSecretCreated, "False", "SecretCreateFailed")
+
+	cases := []struct {
+		name           string
+		status         map[string]interface{}
+		expectedStatus string
+		expectedReason string
+	}{
+		{"without status", nil, cluster.ServiceAccountStatusPending, ""},
+		{
+			"ready",
+			map[string]interface{}{"conditions": []interface{}{tokenReported, secretCreated}},
+			cluster.ServiceAccountStatusReady, "SecretCreated",
+		},
+		{
+			"token isn't reported",
+			map[string]interface{}{"conditions": []interface{}{tokenFailed}},
+			cluster.ServiceAccountStatusFailed, "TokenReportFailed",
+		},
+		{
+			"secret isn't created",
+			map[string]interface{}{"conditions": []interface{}{tokenReported, secretFailed}},
+			cluster.ServiceAccountStatusFailed, "SecretCreateFailed",
+		},
+		{
+			"secret is being created",
+			map[string]interface{}{"conditions": []interface{}{tokenReported}},
+			cluster.ServiceAccountStatusPending, "TokenReported",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			account, err := NormalizeManagedServiceAccount(newManagedServiceAccount(c.status))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if account.ClusterName != "cluster1" || account.Name != "automation" {
+				t.Errorf("unexpected account: %v", account)
+			}
+			if account.Status != c.expectedStatus || account.Reason != c.expectedReason {
+				t.Errorf("expected %s with the reason %q, but got %s with %q", c.expectedStatus, c.expectedReason,
+					account.Status, account.Reason)
+			}
+		})
+	}
+
+	account, err := NormalizeManagedServiceAccount(newManagedServiceAccount(map[string]interface{}{
+		"conditions":          []interface{}{tokenReported, secretCreated},
+		"expirationTimestamp": "2024-06-01T10:00:00Z",
+		"tokenSecretRef": map[string]interface{}{
+			"name":                 "automation",
+			"lastRefreshTimestamp": "2024-05-01T10:00:00Z",
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastRefreshTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	expirationTime := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	if account.TokenSecretName != "automation" || account.LastRefreshTime == nil ||
+		!account.LastRefreshTime.Equal(lastRefreshTime) || account.ExpirationTime == nil ||
+		!account.ExpirationTime.Equal(expirationTime) {
+		t.Errorf("unexpected credential of the account: %v", account)
+	}
+}
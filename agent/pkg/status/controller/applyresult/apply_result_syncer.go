@@ -0,0 +1,80 @@
+package applyresult
+
+import (
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/syncers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// LaunchApplyResultSyncer reports the results of applying the global resources of the spec bundles on the managed
+// hub, so the failed and conflicted resources are visible on the global hub.
+func LaunchApplyResultSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	return generic.LaunchGenericEventSyncer(
+		"status.spec_apply_result",
+		mgr,
+		nil,
+		producer,
+		config.GetPolicyDuration,
+		NewApplyResultEmitter(),
+	)
+}
+
+var _ generic.Emitter = &applyResultEmitter{}
+
+func NewApplyResultEmitter() *applyResultEmitter {
+	emitter := &applyResultEmitter{
+		eventType:       enum.SpecApplyResultType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+	// send the results once started, so the ones of the resources deleted before the restart are cleaned up
+	emitter.currentVersion.Incr()
+	syncers.SupportResyc(string(emitter.eventType), emitter.currentVersion)
+	return emitter
+}
+
+type applyResultEmitter struct {
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	generation      uint64
+}
+
+// the results are recorded by the spec syncers, not by the event controllers
+func (s *applyResultEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *applyResultEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *applyResultEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	results, _ := syncers.GetApplyResults()
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, results)
+	return &e, err
+}
+
+func (s *applyResultEmitter) Topic() string { return "" }
+
+func (s *applyResultEmitter) ShouldSend() bool {
+	if _, generation := syncers.GetApplyResults(); generation != s.generation {
+		s.generation = generation
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *applyResultEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
@@ -0,0 +1,84 @@
+package apps
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	ArgoApplicationGVK    = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+	ArgoApplicationSetGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"}
+)
+
+// LaunchArgoApplicationSyncer sends the Argo CD Applications and ApplicationSets of the managed hub to the global
+// hub. The syncer is skipped if the Argo CD isn't installed on the managed hub.
+func LaunchArgoApplicationSyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig,
+	producer transport.Producer,
+) error {
+	log := ctrl.Log.WithName("status.argocd_application")
+	for _, gvk := range []schema.GroupVersionKind{ArgoApplicationGVK, ArgoApplicationSetGVK} {
+		_, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if meta.IsNoMatchError(err) {
+			log.Info("skip the argocd syncer, the resource isn't found on the managed hub", "gvk", gvk.String())
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// the history and the operation state of the application are not needed by the global hub
+	tweakFunc := func(obj client.Object) {
+		obj.SetManagedFields(nil)
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			unstructured.RemoveNestedField(u.Object, "status", "history")
+			unstructured.RemoveNestedField(u.Object, "status", "operationState")
+		}
+	}
+	syncInterval := statusconfig.GetPolicyDuration
+
+	err := generic.LaunchGenericObjectSyncer(
+		"status.argocd_application",
+		mgr,
+		generic.NewGenericController(newUnstructuredInstance(ArgoApplicationGVK),
+			predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		producer,
+		syncInterval,
+		[]generic.ObjectEmitter{
+			generic.ObjectEmitterWrapper(enum.ArgoApplicationType, nil, tweakFunc, false),
+		})
+	if err != nil {
+		return err
+	}
+
+	return generic.LaunchGenericObjectSyncer(
+		"status.argocd_applicationset",
+		mgr,
+		generic.NewGenericController(newUnstructuredInstance(ArgoApplicationSetGVK),
+			predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		producer,
+		syncInterval,
+		[]generic.ObjectEmitter{
+			generic.ObjectEmitterWrapper(enum.ArgoApplicationSetType, nil, tweakFunc, false),
+		})
+}
+
+func newUnstructuredInstance(gvk schema.GroupVersionKind) func() client.Object {
+	return func() client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		return obj
+	}
+}
@@ -0,0 +1,83 @@
+package clusterset
+
+import (
+	"context"
+
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// LaunchManagedClusterSetSyncer sends the global managed cluster sets back to the global hub, then the members of
+// the sets can be aggregated with the managed clusters across the managed hubs.
+func LaunchManagedClusterSetSyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig,
+	producer transport.Producer,
+) error {
+	// controller config
+	instance := func() client.Object { return &clusterv1beta2.ManagedClusterSet{} }
+	predicate := predicate.NewPredicateFuncs(func(object client.Object) bool { return true })
+
+	// emitter config
+	clusterSetEmitter := generic.ObjectEmitterWrapper(enum.ManagedClusterSetSpecType,
+		func(obj client.Object) bool {
+			return agentConfig.EnableGlobalResource &&
+				utils.HasAnnotation(obj, constants.OriginOwnerReferenceAnnotation)
+		}, func(obj client.Object) {
+			obj.SetManagedFields(nil)
+		}, false)
+
+	// syncer
+	name := "status.managed_cluster_set"
+	syncInterval := statusconfig.GetPolicyDuration
+
+	return generic.LaunchGenericObjectSyncer(
+		name,
+		mgr,
+		generic.NewGenericController(instance, predicate),
+		producer,
+		syncInterval,
+		[]generic.ObjectEmitter{
+			clusterSetEmitter,
+		})
+}
+
+// LaunchManagedClusterSetBindingSyncer sends the global managed cluster set bindings back to the global hub.
+func LaunchManagedClusterSetBindingSyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig,
+	producer transport.Producer,
+) error {
+	// controller config
+	instance := func() client.Object { return &clusterv1beta2.ManagedClusterSetBinding{} }
+	predicate := predicate.NewPredicateFuncs(func(object client.Object) bool { return true })
+
+	// emitter config
+	clusterSetBindingEmitter := generic.ObjectEmitterWrapper(enum.ManagedClusterSetBindingSpecType,
+		func(obj client.Object) bool {
+			return agentConfig.EnableGlobalResource &&
+				utils.HasAnnotation(obj, constants.OriginOwnerReferenceAnnotation)
+		}, func(obj client.Object) {
+			obj.SetManagedFields(nil)
+		}, false)
+
+	// syncer
+	name := "status.managed_cluster_set_binding"
+	syncInterval := statusconfig.GetPolicyDuration
+
+	return generic.LaunchGenericObjectSyncer(
+		name,
+		mgr,
+		generic.NewGenericController(instance, predicate),
+		producer,
+		syncInterval,
+		[]generic.ObjectEmitter{
+			clusterSetBindingEmitter,
+		})
+}
@@ -61,30 +61,49 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 
 	c.setSyncInterval(agentConfigMap, ManagedClusterIntervalKey)
 	c.setSyncInterval(agentConfigMap, PolicyIntervalKey)
+	c.setSyncInterval(agentConfigMap, ComplianceIntervalKey)
 	c.setSyncInterval(agentConfigMap, HubClusterInfoIntervalKey)
 	c.setSyncInterval(agentConfigMap, HubClusterHeartBeatIntervalKey)
 	c.setSyncInterval(agentConfigMap, EventIntervalKey)
+	c.setSyncInterval(agentConfigMap, FullResyncIntervalKey)
 
 	c.setAgentConfig(agentConfigMap, AgentAggregationKey)
 	c.setAgentConfig(agentConfigMap, EnableLocalPolicyKey)
 
+	c.setSelector(agentConfigMap, ManagedClusterSelectorKey)
+	c.setSelector(agentConfigMap, PolicySelectorKey)
+	c.setSelector(agentConfigMap, NamespaceSelectorKey)
+
 	reqLogger.V(2).Info("Reconciliation complete.")
 	return ctrl.Result{}, nil
 }
 
+// setSyncInterval replaces the interval at runtime, the syncers pick it up after their current tick. the default
+// interval is restored once the key is removed from the configmap.
 func (c *hubOfHubsConfigController) setSyncInterval(configMap *v1.ConfigMap, key AgentConfigKey) {
-	intervalStr, found := configMap.Data[string(key)]
-	if !found {
-		c.log.Info(fmt.Sprintf("%s sync interval not defined, using %s", key, syncIntervals[key].String()))
+	interval := defaultSyncIntervals[key]
+	if intervalStr, found := configMap.Data[string(key)]; !found {
+		c.log.V(2).Info(fmt.Sprintf("%s sync interval not defined, using %s", key, interval.String()))
+	} else if parsed, err := time.ParseDuration(intervalStr); err != nil || parsed <= 0 {
+		c.log.Info(fmt.Sprintf("%s sync interval %q is invalid, using %s", key, intervalStr, GetInterval(key).String()))
 		return
+	} else {
+		interval = parsed
 	}
 
-	interval, err := time.ParseDuration(intervalStr)
-	if err != nil {
-		c.log.Info(fmt.Sprintf("%s sync interval has invalid format, using %s", key, syncIntervals[key].String()))
-		return
+	if current := GetInterval(key); current != interval {
+		c.log.Info("sync interval is changed", "key", key, "from", current.String(), "to", interval.String())
+		SetInterval(key, interval)
+	}
+}
+
+// setSelector replaces the label selector of the reported resources, the invalid selector is ignored so that the
+// resources aren't reported by mistake.
+func (c *hubOfHubsConfigController) setSelector(configMap *v1.ConfigMap, key AgentConfigKey) {
+	selector := configMap.Data[string(key)]
+	if err := SetSelector(key, selector); err != nil {
+		c.log.Error(err, "the selector is invalid, keep the current one", "key", key, "selector", selector)
 	}
-	syncIntervals[key] = interval
 }
 
 func (c *hubOfHubsConfigController) setAgentConfig(configMap *v1.ConfigMap, configKey AgentConfigKey) {
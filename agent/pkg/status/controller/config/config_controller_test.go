@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestSetSyncInterval(t *testing.T) {
+	controller := &hubOfHubsConfigController{log: ctrl.Log.WithName("test")}
+	configMap := &corev1.ConfigMap{Data: map[string]string{}}
+
+	configMap.Data[string(ComplianceIntervalKey)] = "30s"
+	controller.setSyncInterval(configMap, ComplianceIntervalKey)
+	if interval := GetComplianceDuration(); interval != 30*time.Second {
+		t.Fatalf("expected the compliance interval 30s, but got %s", interval)
+	}
+
+	// the invalid intervals are ignored
+	for _, invalid := range []string{"30", "0s", "-5s"} {
+		configMap.Data[string(ComplianceIntervalKey)] = invalid
+		controller.setSyncInterval(configMap, ComplianceIntervalKey)
+		if interval := GetComplianceDuration(); interval != 30*time.Second {
+			t.Fatalf("expected the compliance interval 30s with %q, but got %s", invalid, interval)
+		}
+	}
+
+	// the default interval is restored once the key is removed
+	delete(configMap.Data, string(ComplianceIntervalKey))
+	controller.setSyncInterval(configMap, ComplianceIntervalKey)
+	if interval := GetComplianceDuration(); interval != defaultSyncIntervals[ComplianceIntervalKey] {
+		t.Fatalf("expected the default compliance interval, but got %s", interval)
+	}
+}
+
+func TestMinDuration(t *testing.T) {
+	SetInterval(PolicyIntervalKey, 10*time.Second)
+	SetInterval(EventIntervalKey, 3*time.Second)
+	defer SetInterval(PolicyIntervalKey, defaultSyncIntervals[PolicyIntervalKey])
+	defer SetInterval(EventIntervalKey, defaultSyncIntervals[EventIntervalKey])
+
+	intervalFunc := MinDuration(GetPolicyDuration, GetEventDuration)
+	if interval := intervalFunc(); interval != 3*time.Second {
+		t.Fatalf("expected the interval 3s, but got %s", interval)
+	}
+}
+
+func TestSetSelector(t *testing.T) {
+	controller := &hubOfHubsConfigController{log: ctrl.Log.WithName("test")}
+	configMap := &corev1.ConfigMap{Data: map[string]string{}}
+	defer func() { _ = SetSelector(ManagedClusterSelectorKey, "") }()
+
+	generation := GetSelectorGeneration()
+	configMap.Data[string(ManagedClusterSelectorKey)] = "tenant notin (secret)"
+	controller.setSelector(configMap, ManagedClusterSelectorKey)
+	if !HasSelector() || GetSelectorGeneration() != generation+1 {
+		t.Fatalf("expected the selector is changed")
+	}
+	selector := GetSelector(ManagedClusterSelectorKey)
+	if selector.Matches(labels.Set{"tenant": "secret"}) || !selector.Matches(labels.Set{"tenant": "public"}) {
+		t.Fatalf("expected the selector excludes the secret tenant, but got %s", selector)
+	}
+
+	// the invalid selector is ignored
+	configMap.Data[string(ManagedClusterSelectorKey)] = "tenant in secret"
+	controller.setSelector(configMap, ManagedClusterSelectorKey)
+	if GetSelector(ManagedClusterSelectorKey).String() != selector.String() {
+		t.Fatalf("expected the selector isn't changed, but got %s", GetSelector(ManagedClusterSelectorKey))
+	}
+
+	// everything is selected once the key is removed
+	delete(configMap.Data, string(ManagedClusterSelectorKey))
+	controller.setSelector(configMap, ManagedClusterSelectorKey)
+	if HasSelector() || !GetSelector(ManagedClusterSelectorKey).Empty() {
+		t.Fatalf("expected no selector, but got %s", GetSelector(ManagedClusterSelectorKey))
+	}
+}
@@ -1,32 +1,48 @@
 package config
 
 import (
+	"sync"
 	"time"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 )
 
 var (
-	leafHubName   = "leaf-hub"
-	syncIntervals = map[AgentConfigKey]time.Duration{
+	leafHubName = "leaf-hub"
+	// the default intervals are used once the keys are removed from the agent configmap
+	defaultSyncIntervals = map[AgentConfigKey]time.Duration{
 		ManagedClusterIntervalKey:      5 * time.Second,
 		PolicyIntervalKey:              5 * time.Second,
+		ComplianceIntervalKey:          5 * time.Second,
 		HubClusterInfoIntervalKey:      60 * time.Second,
 		HubClusterHeartBeatIntervalKey: 60 * time.Second,
 		EventIntervalKey:               5 * time.Second,
+		FullResyncIntervalKey:          10 * time.Minute,
 	}
+	// the intervals are replaced by the config controller at runtime, while they're read by the syncers
+	syncIntervals     = copyIntervals(defaultSyncIntervals)
+	syncIntervalsLock sync.RWMutex
+
 	agentConfigs = map[AgentConfigKey]AgentConfigValue{
 		AgentAggregationKey:  AggregationFull,
 		EnableLocalPolicyKey: EnableLocalPolicyTrue,
 	}
+
+	// the profile is replaced by the data collection syncer once the manager propagates it
+	dataCollectionProfile     = specbundle.DataCollectionFull
+	dataCollectionProfileLock sync.RWMutex
 )
 
 type AgentConfigKey string
 
 const (
 	PolicyIntervalKey              AgentConfigKey = "policies"
+	ComplianceIntervalKey          AgentConfigKey = "compliance"
 	ManagedClusterIntervalKey      AgentConfigKey = "managedClusters"
 	HubClusterInfoIntervalKey      AgentConfigKey = "hubClusterInfo"
 	HubClusterHeartBeatIntervalKey AgentConfigKey = "hubClusterHeartbeat"
 	EventIntervalKey               AgentConfigKey = "events"
+	FullResyncIntervalKey          AgentConfigKey = "fullResync"
 
 	AgentAggregationKey  AgentConfigKey = "aggregationLevel"
 	EnableLocalPolicyKey AgentConfigKey = "enableLocalPolicies"
@@ -46,25 +62,49 @@ type ResolveSyncIntervalFunc func() time.Duration
 
 // GetManagerClusterDuration returns managed clusters sync interval.
 func GetManagerClusterDuration() time.Duration {
-	return syncIntervals[ManagedClusterIntervalKey]
+	return GetInterval(ManagedClusterIntervalKey)
 }
 
 // GetPolicyDuration returns policies sync interval.
 func GetPolicyDuration() time.Duration {
-	return syncIntervals[PolicyIntervalKey]
+	return GetInterval(PolicyIntervalKey)
+}
+
+// GetComplianceDuration returns the policy compliance sync interval.
+func GetComplianceDuration() time.Duration {
+	return GetInterval(ComplianceIntervalKey)
 }
 
 // GetHubClusterInfoDuration returns control info sync interval.
 func GetHubClusterInfoDuration() time.Duration {
-	return syncIntervals[HubClusterInfoIntervalKey]
+	return GetInterval(HubClusterInfoIntervalKey)
 }
 
 func GetHeartbeatDuration() time.Duration {
-	return syncIntervals[HubClusterHeartBeatIntervalKey]
+	return GetInterval(HubClusterHeartBeatIntervalKey)
 }
 
 func GetEventDuration() time.Duration {
-	return syncIntervals[EventIntervalKey]
+	return GetInterval(EventIntervalKey)
+}
+
+// GetFullResyncDuration returns the interval of sending the full bundle as the checkpoint of the delta bundles.
+func GetFullResyncDuration() time.Duration {
+	return GetInterval(FullResyncIntervalKey)
+}
+
+// MinDuration resolves the shortest interval of the given ones, it's used by the syncer which sends the events with
+// different intervals.
+func MinDuration(intervalFuncs ...ResolveSyncIntervalFunc) ResolveSyncIntervalFunc {
+	return func() time.Duration {
+		var min time.Duration
+		for _, intervalFunc := range intervalFuncs {
+			if interval := intervalFunc(); min == 0 || interval < min {
+				min = interval
+			}
+		}
+		return min
+	}
 }
 
 func GetLeafHubName() string {
@@ -79,6 +119,40 @@ func GetEnableLocalPolicy() AgentConfigValue {
 	return agentConfigs[EnableLocalPolicyKey]
 }
 
+// GetDataCollectionProfile returns the profile deciding which status bundles are collected.
+func GetDataCollectionProfile() string {
+	dataCollectionProfileLock.RLock()
+	defer dataCollectionProfileLock.RUnlock()
+	return dataCollectionProfile
+}
+
+func SetDataCollectionProfile(profile string) {
+	dataCollectionProfileLock.Lock()
+	defer dataCollectionProfileLock.Unlock()
+	dataCollectionProfile = profile
+}
+
+// IsCollected returns true if the status bundle of the event type is collected by the current profile.
+func IsCollected(eventType string) bool {
+	return specbundle.Collects(GetDataCollectionProfile(), eventType)
+}
+
+func GetInterval(key AgentConfigKey) time.Duration {
+	syncIntervalsLock.RLock()
+	defer syncIntervalsLock.RUnlock()
+	return syncIntervals[key]
+}
+
 func SetInterval(key AgentConfigKey, val time.Duration) {
+	syncIntervalsLock.Lock()
+	defer syncIntervalsLock.Unlock()
 	syncIntervals[key] = val
 }
+
+func copyIntervals(intervals map[AgentConfigKey]time.Duration) map[AgentConfigKey]time.Duration {
+	copied := make(map[AgentConfigKey]time.Duration, len(intervals))
+	for key, val := range intervals {
+		copied[key] = val
+	}
+	return copied
+}
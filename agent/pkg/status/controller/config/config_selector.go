@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	ManagedClusterSelectorKey AgentConfigKey = "managedClusterSelector"
+	PolicySelectorKey         AgentConfigKey = "policySelector"
+	NamespaceSelectorKey      AgentConfigKey = "namespaceSelector"
+)
+
+var (
+	// the selectors are replaced by the config controller at runtime, the resources not matching them aren't reported
+	// to the global hub
+	selectors     = map[AgentConfigKey]string{}
+	selectorsLock sync.RWMutex
+	// the generation is increased once any selector is changed, so the syncers refilter the resources
+	selectorGeneration int64
+)
+
+// GetSelector returns the label selector of the key, which selects everything if the key isn't defined.
+func GetSelector(key AgentConfigKey) labels.Selector {
+	selectorsLock.RLock()
+	defer selectorsLock.RUnlock()
+	selector, err := labels.Parse(selectors[key])
+	if err != nil {
+		// the invalid selector isn't accepted by the config controller
+		return labels.Everything()
+	}
+	return selector
+}
+
+// SetSelector replaces the label selector of the key, the empty selector selects everything.
+func SetSelector(key AgentConfigKey, selector string) error {
+	if _, err := labels.Parse(selector); err != nil {
+		return err
+	}
+	selectorsLock.Lock()
+	defer selectorsLock.Unlock()
+	if selectors[key] == selector {
+		return nil
+	}
+	selectors[key] = selector
+	selectorGeneration++
+	return nil
+}
+
+// HasSelector returns true if any resources are excluded by the selectors.
+func HasSelector() bool {
+	selectorsLock.RLock()
+	defer selectorsLock.RUnlock()
+	for _, selector := range selectors {
+		if selector != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func GetSelectorGeneration() int64 {
+	selectorsLock.RLock()
+	defer selectorsLock.RUnlock()
+	return selectorGeneration
+}
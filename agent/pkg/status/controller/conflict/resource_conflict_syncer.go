@@ -0,0 +1,80 @@
+package conflict
+
+import (
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/syncers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// LaunchResourceConflictSyncer reports the global resources which are modified on the managed hub and kept by the
+// "report" conflict policy.
+func LaunchResourceConflictSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	return generic.LaunchGenericEventSyncer(
+		"status.resource_conflict",
+		mgr,
+		nil,
+		producer,
+		config.GetPolicyDuration,
+		NewResourceConflictEmitter(),
+	)
+}
+
+var _ generic.Emitter = &resourceConflictEmitter{}
+
+func NewResourceConflictEmitter() *resourceConflictEmitter {
+	emitter := &resourceConflictEmitter{
+		eventType:       enum.ResourceConflictType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+	// send the conflicts once started, so the ones resolved before the restart are cleaned up on the global hub
+	emitter.currentVersion.Incr()
+	syncers.SupportResyc(string(emitter.eventType), emitter.currentVersion)
+	return emitter
+}
+
+type resourceConflictEmitter struct {
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	generation      uint64
+}
+
+// the conflicts are collected by the spec syncers, not by the event controllers
+func (s *resourceConflictEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *resourceConflictEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *resourceConflictEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	conflicts, _ := syncers.GetResourceConflicts()
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, conflicts)
+	return &e, err
+}
+
+func (s *resourceConflictEmitter) Topic() string { return "" }
+
+func (s *resourceConflictEmitter) ShouldSend() bool {
+	if _, generation := syncers.GetResourceConflicts(); generation != s.generation {
+		s.generation = generation
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *resourceConflictEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
@@ -7,17 +7,29 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/addons"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/applyresult"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/apps"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/clusterset"
 	agentstatusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/conflict"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/event"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/gatekeeper"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/hubcluster"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/managedclusters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/migration"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/placement"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/policies"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/policyreport"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/security"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	transportproducer "github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/scrubber"
 )
 
 // AddControllers adds all the controllers to the Manager.
@@ -26,11 +38,9 @@ func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.A
 		return fmt.Errorf("failed to add ConfigMap controller: %w", err)
 	}
 
-	// only use the cloudevents
-	producer, err := transportproducer.NewGenericProducer(agentConfig.TransportConfig,
-		agentConfig.TransportConfig.KafkaConfig.Topics.StatusTopic)
+	producer, err := newStatusProducer(ctx, mgr, agentConfig)
 	if err != nil {
-		return fmt.Errorf("failed to init status transport producer: %w", err)
+		return err
 	}
 
 	// managed cluster
@@ -49,6 +59,10 @@ func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.A
 	if err != nil {
 		return fmt.Errorf("failed to launch policy syncer: %w", err)
 	}
+	err = policies.LaunchPolicyTemplateDetailSyncer(mgr, producer)
+	if err != nil {
+		return fmt.Errorf("failed to launch policy template detail syncer: %w", err)
+	}
 
 	// hub cluster info
 	err = hubcluster.LaunchHubClusterInfoSyncer(mgr, producer)
@@ -62,6 +76,18 @@ func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.A
 		return fmt.Errorf("failed to launch hub cluster heartbeat syncer: %w", err)
 	}
 
+	// the resource counts to detect the lost messages on the global hub
+	err = hubcluster.LaunchHubResourceCountsSyncer(mgr, producer)
+	if err != nil {
+		return fmt.Errorf("failed to launch hub resource counts syncer: %w", err)
+	}
+
+	// the key metrics of the hub, it's enabled by the prometheus url
+	err = hubcluster.LaunchHubMetricsSyncer(mgr, agentConfig, producer)
+	if err != nil {
+		return fmt.Errorf("failed to launch hub metrics syncer: %w", err)
+	}
+
 	// placement
 	if err := placement.LaunchPlacementSyncer(ctx, mgr, agentConfig, producer); err != nil {
 		return fmt.Errorf("failed to launch placement syncer: %w", err)
@@ -73,9 +99,159 @@ func AddControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.A
 		return fmt.Errorf("failed to launch placementRule syncer: %w", err)
 	}
 
+	// managed cluster set
+	if err := clusterset.LaunchManagedClusterSetSyncer(ctx, mgr, agentConfig, producer); err != nil {
+		return fmt.Errorf("failed to launch managedClusterSet syncer: %w", err)
+	}
+	if err := clusterset.LaunchManagedClusterSetBindingSyncer(ctx, mgr, agentConfig, producer); err != nil {
+		return fmt.Errorf("failed to launch managedClusterSetBinding syncer: %w", err)
+	}
+
 	// app
 	if err := apps.LaunchSubscriptionReportSyncer(ctx, mgr, agentConfig, producer); err != nil {
 		return fmt.Errorf("failed to launch subscription report syncer: %w", err)
 	}
+	if err := apps.LaunchArgoApplicationSyncer(ctx, mgr, agentConfig, producer); err != nil {
+		return fmt.Errorf("failed to launch argocd application syncer: %w", err)
+	}
+
+	// the kyverno policy reports
+	if err := launchPolicyReportSyncer(mgr, producer); err != nil {
+		return err
+	}
+	if err := gatekeeper.LaunchGatekeeperConstraintSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch gatekeeper constraint syncer: %w", err)
+	}
+	if err := security.LaunchSecurityPostureSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch security posture syncer: %w", err)
+	}
+
+	// the health of the addons on the managed clusters
+	if err := addons.LaunchManagedClusterAddonSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch managed cluster addon syncer: %w", err)
+	}
+	// the credentials brokered by the managed service accounts on the managed clusters
+	if err := addons.LaunchManagedServiceAccountSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch managed service account syncer: %w", err)
+	}
+
+	// the global resources modified on the managed hub
+	if err := conflict.LaunchResourceConflictSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch resource conflict syncer: %w", err)
+	}
+
+	// the results of applying the global resources on the managed hub
+	if err := applyresult.LaunchApplyResultSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch spec apply result syncer: %w", err)
+	}
+
+	// the results of running the managed cluster migration phases on the hub
+	if err := migration.LaunchMigrationStatusSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch managed cluster migration status syncer: %w", err)
+	}
 	return nil
 }
+
+// AddStandaloneControllers adds the controllers of the standalone agent, which runs on the cluster without the ACM hub.
+// it reports the cluster itself as the only managed cluster, and the policies and events of the policy framework if
+// it's installed on the cluster.
+func AddStandaloneControllers(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig) error {
+	if err := agentstatusconfig.AddConfigController(mgr, agentConfig); err != nil {
+		return fmt.Errorf("failed to add ConfigMap controller: %w", err)
+	}
+
+	producer, err := newStatusProducer(ctx, mgr, agentConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := managedclusters.LaunchStandaloneClusterSyncer(ctx, mgr, agentConfig, producer); err != nil {
+		return fmt.Errorf("failed to launch standalone cluster syncer: %w", err)
+	}
+	if err := hubcluster.LaunchStandaloneHubClusterInfoSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch hub cluster info syncer: %w", err)
+	}
+	if err := hubcluster.LaunchHubClusterHeartbeatSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch hub cluster heartbeat syncer: %w", err)
+	}
+
+	if err := launchPolicyReportSyncer(mgr, producer); err != nil {
+		return err
+	}
+	if err := gatekeeper.LaunchGatekeeperConstraintSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch gatekeeper constraint syncer: %w", err)
+	}
+	if err := security.LaunchSecurityPostureSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch security posture syncer: %w", err)
+	}
+
+	// the policy framework is optional on the standalone cluster
+	_, err = mgr.GetRESTMapper().RESTMapping(policiesv1.GroupVersion.WithKind(policiesv1.Kind).GroupKind())
+	if meta.IsNoMatchError(err) {
+		ctrl.Log.WithName("standalone").Info("the policy framework isn't installed, skip the policy syncers")
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get the policy mapping: %w", err)
+	}
+	if err := policies.LaunchStandalonePolicySyncer(ctx, mgr, agentConfig, producer); err != nil {
+		return fmt.Errorf("failed to launch standalone policy syncer: %w", err)
+	}
+	if err := event.LaunchEventSyncer(ctx, mgr, agentConfig, producer); err != nil {
+		return fmt.Errorf("failed to launch event syncer: %w", err)
+	}
+	return nil
+}
+
+// launchPolicyReportSyncer launches the policy report syncer if the policy report crd is installed, e.g. by kyverno
+func launchPolicyReportSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	installed, err := policyreport.PolicyReportInstalled(mgr)
+	if err != nil {
+		return fmt.Errorf("failed to get the policy report mapping: %w", err)
+	}
+	if !installed {
+		return nil
+	}
+	if err := policyreport.LaunchPolicyReportSyncer(mgr, producer); err != nil {
+		return fmt.Errorf("failed to launch policy report syncer: %w", err)
+	}
+	return nil
+}
+
+// newStatusProducer creates the producer of the status events, which are buffered in the directory during the
+// transport outage if it's specified, and signed with the key of the managed hub if it's specified. The events are
+// scrubbed by the rules of the configmap before leaving the managed hub.
+func newStatusProducer(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig,
+) (transport.Producer, error) {
+	// only use the cloudevents
+	var producer transport.Producer
+	producer, err := transportproducer.NewGenericProducer(agentConfig.TransportConfig,
+		agentConfig.TransportConfig.KafkaConfig.Topics.StatusTopic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init status transport producer: %w", err)
+	}
+	if agentConfig.StatusBufferDir != "" {
+		producer, err = transportproducer.NewBufferedProducer(ctx, producer, agentConfig.StatusBufferDir,
+			int64(agentConfig.StatusBufferSizeMB)*1024*1024)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init status buffer: %w", err)
+		}
+	}
+	// sign the events before they're buffered, so the buffered events are delivered with the signatures
+	if agentConfig.SigningKeyPath != "" {
+		producer, err = transportproducer.NewSigningProducer(producer, agentConfig.SigningKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init status signing: %w", err)
+		}
+	}
+	// scrub the events before they're signed and buffered, so the sensitive values are neither signed nor persisted
+	if agentConfig.ScrubRulesConfigMap != "" {
+		eventScrubber := scrubber.NewScrubber()
+		if err := scrubber.AddWatcher(ctx, mgr, agentConfig.PodNameSpace, agentConfig.ScrubRulesConfigMap,
+			eventScrubber); err != nil {
+			return nil, fmt.Errorf("failed to add the scrubbing rules watcher: %w", err)
+		}
+		producer = transportproducer.NewScrubbingProducer(producer, eventScrubber)
+	}
+	// drop the bundles which aren't collected by the data collection profile propagated by the manager
+	return transportproducer.NewFilteringProducer(producer, agentstatusconfig.IsCollected), nil
+}
@@ -144,6 +144,12 @@ func (h *localReplicatedPolicyEmitter) Update(obj client.Object) bool {
 			Count:          evt.Count,
 			Source:         evt.Source,
 			CreatedAt:      evt.CreationTimestamp,
+			Type:           evt.Type,
+			InvolvedObject: event.InvolvedObject{
+				Kind:      evt.InvolvedObject.Kind,
+				Namespace: evt.InvolvedObject.Namespace,
+				Name:      evt.InvolvedObject.Name,
+			},
 		},
 		PolicyID:   string(rootPolicy.GetUID()),
 		ClusterID:  clusterID,
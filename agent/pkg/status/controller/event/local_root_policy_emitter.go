@@ -117,6 +117,12 @@ func (h *localRootPolicyEmitter) Update(obj client.Object) bool {
 			Count:          evt.Count,
 			Source:         evt.Source,
 			CreatedAt:      evt.CreationTimestamp,
+			Type:           evt.Type,
+			InvolvedObject: event.InvolvedObject{
+				Kind:      evt.InvolvedObject.Kind,
+				Namespace: evt.InvolvedObject.Namespace,
+				Name:      evt.InvolvedObject.Name,
+			},
 		},
 		PolicyID:   string(policy.GetUID()),
 		Compliance: policyCompliance(policy, evt),
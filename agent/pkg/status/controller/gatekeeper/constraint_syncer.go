@@ -0,0 +1,317 @@
+package gatekeeper
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	ConstraintTemplateGVK = schema.GroupVersionKind{
+		Group:   "templates.gatekeeper.sh",
+		Version: "v1",
+		Kind:    "ConstraintTemplate",
+	}
+	// ConstraintGroupVersion is the group version of the constraints, the kinds of them are defined by the templates
+	ConstraintGroupVersion = schema.GroupVersion{
+		Group:   "constraints.gatekeeper.sh",
+		Version: "v1beta1",
+	}
+)
+
+// LaunchGatekeeperConstraintSyncer reports the audit results of the gatekeeper constraints on the cluster. The syncer
+// is skipped if the gatekeeper isn't installed on the cluster.
+func LaunchGatekeeperConstraintSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(ConstraintTemplateGVK.GroupKind(), ConstraintTemplateGVK.Version)
+	if meta.IsNoMatchError(err) {
+		ctrl.Log.WithName("status.gatekeeper_constraint").Info("skip the gatekeeper syncer, it isn't installed")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the kinds of the constraints are created by the templates at runtime, so the constraints are watched once their
+	// templates are reconciled
+	emitter := NewConstraintEmitter(mgr.GetCache())
+	return generic.LaunchGenericEventSyncer(
+		"status.gatekeeper_constraint",
+		mgr,
+		[]generic.EventController{NewConstraintTemplateController(emitter)},
+		producer,
+		config.GetComplianceDuration,
+		emitter,
+	)
+}
+
+var _ generic.Emitter = &constraintEmitter{}
+
+func NewConstraintEmitter(informers cache.Informers) *constraintEmitter {
+	emitter := &constraintEmitter{
+		log:             ctrl.Log.WithName("gatekeeper-constraint"),
+		informers:       informers,
+		eventType:       enum.GatekeeperConstraintType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+		constraints:     map[string]grc.GatekeeperConstraint{},
+		watchedKinds:    map[string]toolscache.ResourceEventHandlerRegistration{},
+	}
+	// send the constraints once the agent is started, so the removed constraints are cleaned up on the global hub
+	emitter.currentVersion.Incr()
+	return emitter
+}
+
+// constraintEmitter keeps the audit results of the constraints by the watch events of them, and emits the bundle
+// from the results rather than listing the constraints on each sync
+type constraintEmitter struct {
+	log             logr.Logger
+	informers       cache.Informers
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+
+	// the mutex guards the fields below, they're updated by the informers of the constraints besides the syncer
+	mutex        sync.Mutex
+	constraints  map[string]grc.GatekeeperConstraint // the key is kind/name
+	watchedKinds map[string]toolscache.ResourceEventHandlerRegistration
+}
+
+// the constraint templates only start or stop watching the constraints of their kinds
+func (s *constraintEmitter) ShouldUpdate(object client.Object) bool { return true }
+
+func (s *constraintEmitter) PostUpdate() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.currentVersion.Incr()
+}
+
+func (s *constraintEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	s.mutex.Lock()
+	constraints := make(grc.GatekeeperConstraintBundle, 0, len(s.constraints))
+	for _, constraint := range s.constraints {
+		constraints = append(constraints, constraint)
+	}
+	version := s.currentVersion.String()
+	s.mutex.Unlock()
+	sortConstraints(constraints)
+
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, version)
+	err := e.SetData(cloudevents.ApplicationJSON, constraints)
+	return &e, err
+}
+
+func (s *constraintEmitter) Topic() string { return "" }
+
+// ShouldSend sends the constraints when they're changed, e.g. by a new audit. It waits for the informers of the
+// constraints to be synced, so the constraints which aren't listed yet aren't removed from the global hub.
+func (s *constraintEmitter) ShouldSend() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, registration := range s.watchedKinds {
+		if !registration.HasSynced() {
+			return false
+		}
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *constraintEmitter) PostSend() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+// watchKind starts watching the constraints of the kind, the constraints are added into the bundle by the informer
+func (s *constraintEmitter) watchKind(ctx context.Context, kind string) error {
+	s.mutex.Lock()
+	_, found := s.watchedKinds[kind]
+	s.mutex.Unlock()
+	if found {
+		return nil
+	}
+
+	informer, err := s.informers.GetInformer(ctx, constraintObject(kind), cache.BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    s.updateConstraint,
+		UpdateFunc: func(_, obj interface{}) { s.updateConstraint(obj) },
+		DeleteFunc: s.deleteConstraint,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.watchedKinds[kind] = registration
+	s.log.Info("watch the gatekeeper constraints", "kind", kind)
+	return nil
+}
+
+// unwatchKind stops watching the constraints of the kind, and removes them from the bundle. It returns whether the
+// bundle is changed.
+func (s *constraintEmitter) unwatchKind(ctx context.Context, kind string) bool {
+	s.mutex.Lock()
+	_, found := s.watchedKinds[kind]
+	delete(s.watchedKinds, kind)
+	removed := false
+	for key, constraint := range s.constraints {
+		if constraint.Kind == kind {
+			delete(s.constraints, key)
+			removed = true
+		}
+	}
+	s.mutex.Unlock()
+
+	if found {
+		if err := s.informers.RemoveInformer(ctx, constraintObject(kind)); err != nil {
+			s.log.Error(err, "failed to stop watching the gatekeeper constraints", "kind", kind)
+		}
+		s.log.Info("stop watching the gatekeeper constraints", "kind", kind)
+	}
+	return removed
+}
+
+// watchedKind returns the kind of the constraints of the template, the template is named after the lowercase kind
+func (s *constraintEmitter) watchedKind(templateName string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for kind := range s.watchedKinds {
+		if strings.ToLower(kind) == templateName {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+func (s *constraintEmitter) updateConstraint(obj interface{}) {
+	constraint, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	result := normalizeConstraint(config.GetLeafHubName(), constraint)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := constraintKey(result.Kind, result.Name)
+	if existing, found := s.constraints[key]; found && reflect.DeepEqual(existing, result) {
+		return
+	}
+	s.constraints[key] = result
+	s.currentVersion.Incr()
+}
+
+func (s *constraintEmitter) deleteConstraint(obj interface{}) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	constraint, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := constraintKey(constraint.GetKind(), constraint.GetName())
+	if _, found := s.constraints[key]; !found {
+		return
+	}
+	delete(s.constraints, key)
+	s.currentVersion.Incr()
+}
+
+func constraintKey(kind, name string) string {
+	return kind + "/" + name
+}
+
+func constraintObject(kind string) *unstructured.Unstructured {
+	constraint := &unstructured.Unstructured{}
+	constraint.SetGroupVersionKind(ConstraintGroupVersion.WithKind(kind))
+	return constraint
+}
+
+// NormalizeConstraints converts the status of the constraints into the audit results, the constraints which haven't
+// been audited are reported without the audit timestamp.
+func NormalizeConstraints(clusterName string, constraints []unstructured.Unstructured) grc.GatekeeperConstraintBundle {
+	results := make(grc.GatekeeperConstraintBundle, 0, len(constraints))
+	for i := range constraints {
+		results = append(results, normalizeConstraint(clusterName, &constraints[i]))
+	}
+	sortConstraints(results)
+	return results
+}
+
+func normalizeConstraint(clusterName string, constraint *unstructured.Unstructured) grc.GatekeeperConstraint {
+	result := grc.GatekeeperConstraint{
+		Kind:              constraint.GetKind(),
+		Name:              constraint.GetName(),
+		ClusterName:       clusterName,
+		EnforcementAction: "deny",
+	}
+	if action, _, _ := unstructured.NestedString(constraint.Object, "spec", "enforcementAction"); action != "" {
+		result.EnforcementAction = action
+	}
+	if total, found, _ := unstructured.NestedInt64(constraint.Object, "status", "totalViolations"); found {
+		result.TotalViolations = int(total)
+	}
+	timestamp, _, _ := unstructured.NestedString(constraint.Object, "status", "auditTimestamp")
+	if auditTime, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		result.AuditTimestamp = &auditTime
+	}
+
+	violations, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+	for _, item := range violations {
+		violation, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kind, _ := violation["kind"].(string)
+		namespace, _ := violation["namespace"].(string)
+		name, _ := violation["name"].(string)
+		message, _ := violation["message"].(string)
+		action, _ := violation["enforcementAction"].(string)
+		result.Violations = append(result.Violations, grc.GatekeeperViolation{
+			Kind:              kind,
+			Namespace:         namespace,
+			Name:              name,
+			Message:           message,
+			EnforcementAction: action,
+		})
+	}
+	return result
+}
+
+func sortConstraints(results grc.GatekeeperConstraintBundle) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind < results[j].Kind
+		}
+		return results[i].Name < results[j].Name
+	})
+}
@@ -0,0 +1,202 @@
+package gatekeeper
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+)
+
+func newConstraint(kind, name string, spec, status map[string]interface{}) unstructured.Unstructured {
+	constraint := unstructured.Unstructured{Object: map[string]interface{}{}}
+	constraint.SetGroupVersionKind(ConstraintGroupVersion.WithKind(kind))
+	constraint.SetName(name)
+	if spec != nil {
+		constraint.Object["spec"] = spec
+	}
+	if status != nil {
+		constraint.Object["status"] = status
+	}
+	return constraint
+}
+
+func TestNormalizeConstraints(t *testing.T) {
+	constraints := []unstructured.Unstructured{
+		newConstraint("K8sRequiredLabels", "ns-must-have-owner", nil, map[string]interface{}{
+			"auditTimestamp":  "2024-01-01T00:00:00Z",
+			"totalViolations": int64(2),
+			"violations": []interface{}{
+				map[string]interface{}{
+					"enforcementAction": "deny",
+					"kind":              "Namespace",
+					"name":              "default",
+					"message":           "you must provide labels: {\"owner\"}",
+				},
+				map[string]interface{}{
+					"enforcementAction": "deny",
+					"kind":              "Namespace",
+					"name":              "kube-public",
+					"message":           "you must provide labels: {\"owner\"}",
+				},
+			},
+		}),
+		// the constraint which hasn't been audited
+		newConstraint("K8sAllowedRepos", "repo-is-quay", map[string]interface{}{"enforcementAction": "dryrun"}, nil),
+	}
+
+	results := NormalizeConstraints("hub1", constraints)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 constraints, but got %v", results)
+	}
+
+	allowedRepos := results[0]
+	if allowedRepos.Kind != "K8sAllowedRepos" || allowedRepos.EnforcementAction != "dryrun" ||
+		allowedRepos.TotalViolations != 0 || allowedRepos.AuditTimestamp != nil || len(allowedRepos.Violations) != 0 {
+		t.Errorf("unexpected constraint without the audit: %v", allowedRepos)
+	}
+
+	requiredLabels := results[1]
+	if requiredLabels.Name != "ns-must-have-owner" || requiredLabels.ClusterName != "hub1" ||
+		requiredLabels.EnforcementAction != "deny" || requiredLabels.TotalViolations != 2 {
+		t.Errorf("unexpected audited constraint: %v", requiredLabels)
+	}
+	if requiredLabels.AuditTimestamp == nil || requiredLabels.AuditTimestamp.Year() != 2024 {
+		t.Errorf("unexpected audit timestamp: %v", requiredLabels.AuditTimestamp)
+	}
+	if len(requiredLabels.Violations) != 2 || requiredLabels.Violations[1].Name != "kube-public" ||
+		requiredLabels.Violations[1].Kind != "Namespace" {
+		t.Errorf("unexpected violations: %v", requiredLabels.Violations)
+	}
+}
+
+// fakeInformers returns the same informer for all the kinds of the constraints
+type fakeInformers struct {
+	*informertest.FakeInformers
+	informer *fakeInformer
+}
+
+func (f *fakeInformers) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption,
+) (cache.Informer, error) {
+	return f.informer, nil
+}
+
+func (f *fakeInformers) RemoveInformer(ctx context.Context, obj client.Object) error {
+	f.informer.removed = true
+	return nil
+}
+
+type fakeInformer struct {
+	*controllertest.FakeInformer
+	registration *fakeRegistration
+	removed      bool
+}
+
+func (f *fakeInformer) AddEventHandler(handler toolscache.ResourceEventHandler,
+) (toolscache.ResourceEventHandlerRegistration, error) {
+	_, _ = f.FakeInformer.AddEventHandler(handler)
+	return f.registration, nil
+}
+
+type fakeRegistration struct {
+	synced bool
+}
+
+func (r *fakeRegistration) HasSynced() bool { return r.synced }
+
+func TestConstraintEmitter(t *testing.T) {
+	informer := &fakeInformer{FakeInformer: &controllertest.FakeInformer{}, registration: &fakeRegistration{}}
+	emitter := NewConstraintEmitter(&fakeInformers{FakeInformers: &informertest.FakeInformers{}, informer: informer})
+	templateController := NewConstraintTemplateController(emitter)
+
+	sentConstraints := func() grc.GatekeeperConstraintBundle {
+		if !emitter.ShouldSend() {
+			return nil
+		}
+		evt, err := emitter.ToCloudEvent()
+		if err != nil {
+			t.Fatalf("failed to get the event: %v", err)
+		}
+		constraints := grc.GatekeeperConstraintBundle{}
+		if err := evt.DataAs(&constraints); err != nil {
+			t.Fatalf("failed to decode the constraints: %v", err)
+		}
+		emitter.PostSend()
+		return constraints
+	}
+
+	// the constraints are sent once the agent is started
+	if constraints := sentConstraints(); constraints == nil || len(constraints) != 0 {
+		t.Fatalf("expected the empty constraints are sent, got %v", constraints)
+	}
+	if emitter.ShouldSend() {
+		t.Fatal("the unchanged constraints shouldn't be sent")
+	}
+
+	template := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"crd": map[string]interface{}{
+				"spec": map[string]interface{}{"names": map[string]interface{}{"kind": "K8sRequiredLabels"}},
+			},
+		},
+	}}
+	template.SetGroupVersionKind(ConstraintTemplateGVK)
+	template.SetName("k8srequiredlabels")
+	if templateController.Update(template) {
+		t.Error("the template shouldn't update the bundle")
+	}
+
+	// the constraints are sent once the informer of them is synced
+	constraint := newConstraint("K8sRequiredLabels", "ns-must-have-owner", nil,
+		map[string]interface{}{"totalViolations": int64(1)})
+	informer.Add(&constraint)
+	if emitter.ShouldSend() {
+		t.Fatal("the constraints shouldn't be sent before the informer is synced")
+	}
+	informer.registration.synced = true
+	if constraints := sentConstraints(); len(constraints) != 1 || constraints[0].TotalViolations != 1 {
+		t.Fatalf("expected the watched constraint is sent, got %v", constraints)
+	}
+
+	// only the changed constraints are sent
+	informer.Update(&constraint, &constraint)
+	if emitter.ShouldSend() {
+		t.Fatal("the unchanged constraint shouldn't be sent")
+	}
+	audited := newConstraint("K8sRequiredLabels", "ns-must-have-owner", nil,
+		map[string]interface{}{"totalViolations": int64(3)})
+	informer.Update(&constraint, &audited)
+	if constraints := sentConstraints(); len(constraints) != 1 || constraints[0].TotalViolations != 3 {
+		t.Fatalf("expected the audited constraint is sent, got %v", constraints)
+	}
+
+	informer.Delete(&audited)
+	if constraints := sentConstraints(); constraints == nil || len(constraints) != 0 {
+		t.Fatalf("expected the deleted constraint is removed, got %v", constraints)
+	}
+
+	// the constraints of the deleted template are removed, and they aren't watched anymore
+	informer.Add(&audited)
+	sentConstraints()
+	deleted := &unstructured.Unstructured{}
+	deleted.SetName("k8srequiredlabels")
+	if !templateController.Delete(deleted) {
+		t.Fatal("the constraints of the deleted template should be removed")
+	}
+	if !informer.removed {
+		t.Error("the informer of the constraints should be removed")
+	}
+	if _, found := emitter.watchedKind("k8srequiredlabels"); found {
+		t.Error("the constraints of the deleted template shouldn't be watched")
+	}
+	emitter.PostUpdate()
+	if constraints := sentConstraints(); constraints == nil || len(constraints) != 0 {
+		t.Fatalf("expected the constraints of the deleted template are removed, got %v", constraints)
+	}
+}
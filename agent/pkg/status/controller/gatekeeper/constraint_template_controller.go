@@ -0,0 +1,61 @@
+package gatekeeper
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+)
+
+var _ generic.EventController = &constraintTemplateController{}
+
+// constraintTemplateController watches the constraints of the kinds defined by the constraint templates
+type constraintTemplateController struct {
+	generic.Controller
+	emitter *constraintEmitter
+}
+
+func NewConstraintTemplateController(emitter *constraintEmitter) generic.EventController {
+	instance := func() client.Object {
+		template := &unstructured.Unstructured{}
+		template.SetGroupVersionKind(ConstraintTemplateGVK)
+		return template
+	}
+	return &constraintTemplateController{
+		Controller: generic.NewGenericController(instance, nil),
+		emitter:    emitter,
+	}
+}
+
+// Update starts watching the constraints of the template, the bundle is updated by the informer of the constraints
+func (c *constraintTemplateController) Update(obj client.Object) bool {
+	template, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	kind, _, _ := unstructured.NestedString(template.Object, "spec", "crd", "spec", "names", "kind")
+	if kind == "" {
+		return false
+	}
+	err := c.emitter.watchKind(context.Background(), kind)
+	// the crd of the constraint isn't created yet by the template, it's watched once the status of the template is
+	// updated by the gatekeeper
+	if meta.IsNoMatchError(err) {
+		c.emitter.log.V(2).Info("the constraint isn't created by the template yet", "kind", kind)
+	} else if err != nil {
+		c.emitter.log.Error(err, "failed to watch the gatekeeper constraints", "kind", kind)
+	}
+	return false
+}
+
+// Delete stops watching the constraints of the template, and removes them from the bundle
+func (c *constraintTemplateController) Delete(obj client.Object) bool {
+	kind, found := c.emitter.watchedKind(obj.GetName())
+	if !found {
+		return false
+	}
+	return c.emitter.unwatchKind(context.Background(), kind)
+}
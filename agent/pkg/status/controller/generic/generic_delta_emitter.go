@@ -0,0 +1,132 @@
+package generic
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	genericpayload "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+var _ ObjectEmitter = &deltaObjectEmitter{}
+
+// deltaObjectEmitter sends the changes since the last full bundle, and the full bundle is sent as the checkpoint when:
+// 1. the full bundle hasn't been sent, or it's requested by the resync
+// 2. the full resync interval is reached since the last full bundle
+// 3. the delta is larger than half of the full bundle
+type deltaObjectEmitter struct {
+	*genericEmitter
+	Handler
+
+	deltaEventType enum.EventType
+	eventData      *genericpayload.GenericObjectBundle
+	delta          *genericpayload.GenericObjectDeltaBundle
+	// the value of the current version updated by the handler, it's changed without update once the resync happens
+	updatedValue   uint64
+	fullVersion    *eventversion.Version
+	lastFullSentAt time.Time
+	sendFull       bool
+}
+
+func DeltaObjectEmitterWrapper(eventType enum.EventType, deltaEventType enum.EventType,
+	shouldUpdate func(client.Object) bool,
+	tweakFunc func(client.Object),
+	isSpecHandler bool,
+) ObjectEmitter {
+	eventData := genericpayload.GenericObjectBundle{}
+	return &deltaObjectEmitter{
+		genericEmitter: NewGenericEmitter(eventType, &eventData,
+			WithShouldUpdate(shouldUpdate), WithTweakFunc(tweakFunc)),
+		Handler:        NewGenericObjectHandler(&eventData, isSpecHandler),
+		deltaEventType: deltaEventType,
+		eventData:      &eventData,
+		delta:          &genericpayload.GenericObjectDeltaBundle{},
+	}
+}
+
+func (e *deltaObjectEmitter) Update(obj client.Object) bool {
+	if !e.Handler.Update(obj) {
+		return false
+	}
+	e.removeDeleted(obj)
+	if index := getObjectIndexByUID(obj.GetUID(), e.delta.Updated); index != -1 {
+		e.delta.Updated[index] = obj
+	} else {
+		e.delta.Updated = append(e.delta.Updated, obj)
+	}
+	return true
+}
+
+func (e *deltaObjectEmitter) Delete(obj client.Object) bool {
+	// the deleted object might only have the namespace and name, get the identity from the full bundle
+	index := getObjectIndexByObj(obj, *e.eventData)
+	if index == -1 {
+		return false
+	}
+	deleted := (*e.eventData)[index]
+	if !e.Handler.Delete(obj) {
+		return false
+	}
+	if index = getObjectIndexByUID(deleted.GetUID(), e.delta.Updated); index != -1 {
+		e.delta.Updated = append(e.delta.Updated[:index], e.delta.Updated[index+1:]...)
+	}
+	e.delta.Deleted = append(e.delta.Deleted, genericpayload.ObjectIdentity{
+		UID:       deleted.GetUID(),
+		Namespace: deleted.GetNamespace(),
+		Name:      deleted.GetName(),
+	})
+	return true
+}
+
+func (e *deltaObjectEmitter) removeDeleted(obj client.Object) {
+	for i, deleted := range e.delta.Deleted {
+		if deleted.UID == obj.GetUID() ||
+			(deleted.Namespace == obj.GetNamespace() && deleted.Name == obj.GetName()) {
+			e.delta.Deleted = append(e.delta.Deleted[:i], e.delta.Deleted[i+1:]...)
+			return
+		}
+	}
+}
+
+func (e *deltaObjectEmitter) PostUpdate() {
+	e.genericEmitter.PostUpdate()
+	e.updatedValue = e.currentVersion.Value
+}
+
+func (e *deltaObjectEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e.sendFull = e.fullRequired()
+	if e.sendFull {
+		return e.genericEmitter.ToCloudEvent()
+	}
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource(config.GetLeafHubName())
+	evt.SetType(string(e.deltaEventType))
+	evt.SetExtension(eventversion.ExtVersion, e.currentVersion.String())
+	evt.SetExtension(eventversion.ExtDependencyVersion, e.fullVersion.String())
+	err := evt.SetData(cloudevents.ApplicationJSON, e.delta)
+	return &evt, err
+}
+
+func (e *deltaObjectEmitter) fullRequired() bool {
+	return e.fullVersion == nil ||
+		e.currentVersion.Value != e.updatedValue ||
+		time.Since(e.lastFullSentAt) >= config.GetFullResyncDuration() ||
+		2*(len(e.delta.Updated)+len(e.delta.Deleted)) >= len(*e.eventData)
+}
+
+func (e *deltaObjectEmitter) PostSend() {
+	if e.sendFull {
+		// the delta is sent since the full bundle, so the version of it is the checkpoint
+		fullVersion := *e.currentVersion
+		e.fullVersion = &fullVersion
+		e.lastFullSentAt = time.Now()
+		e.updatedValue = e.currentVersion.Value
+		e.delta = &genericpayload.GenericObjectDeltaBundle{}
+	}
+	e.genericEmitter.PostSend()
+}
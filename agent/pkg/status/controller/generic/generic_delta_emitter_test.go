@@ -0,0 +1,116 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	genericpayload "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newConfigMap(name, resourceVersion string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID(name + "-uid"),
+			ResourceVersion: resourceVersion,
+		},
+	}
+}
+
+func sendEvent(t *testing.T, emitter ObjectEmitter) (string, string) {
+	if !emitter.ShouldSend() {
+		t.Fatal("the emitter should send the event")
+	}
+	evt, err := emitter.ToCloudEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	emitter.PostSend()
+	dependencyVersion := ""
+	if val, found := evt.Extensions()[eventversion.ExtDependencyVersion]; found {
+		dependencyVersion = fmt.Sprintf("%v", val)
+	}
+	return evt.Type(), dependencyVersion
+}
+
+func update(emitter ObjectEmitter, objs ...*corev1.ConfigMap) {
+	for _, obj := range objs {
+		if emitter.ShouldUpdate(obj) && emitter.Update(obj) {
+			emitter.PostUpdate()
+		}
+	}
+}
+
+func TestDeltaObjectEmitter(t *testing.T) {
+	emitter := DeltaObjectEmitterWrapper(enum.ManagedClusterType, enum.ManagedClusterDeltaType, nil, nil, false)
+
+	// the first bundle is the full bundle
+	for i := 0; i < 8; i++ {
+		update(emitter, newConfigMap(fmt.Sprintf("cm%d", i), "1"))
+	}
+	evtType, _ := sendEvent(t, emitter)
+	if evtType != string(enum.ManagedClusterType) {
+		t.Fatalf("expected the full bundle, but got %s", evtType)
+	}
+	if emitter.ShouldSend() {
+		t.Fatal("the emitter shouldn't send the event without changes")
+	}
+
+	// the changes are sent with the delta bundle depending on the full bundle
+	update(emitter, newConfigMap("cm1", "2"))
+	if !emitter.Delete(newConfigMap("cm2", "")) {
+		t.Fatal("failed to delete the object")
+	}
+	emitter.PostUpdate()
+	evt, err := emitter.ToCloudEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evt.Type() != string(enum.ManagedClusterDeltaType) {
+		t.Fatalf("expected the delta bundle, but got %s", evt.Type())
+	}
+	if dependency := evt.Extensions()[eventversion.ExtDependencyVersion]; dependency != "0.8" {
+		t.Fatalf("expected the dependency version 0.8, but got %v", dependency)
+	}
+	delta := genericpayload.DeltaBundle[corev1.ConfigMap]{}
+	if err := json.Unmarshal(evt.Data(), &delta); err != nil {
+		t.Fatal(err)
+	}
+	if len(delta.Updated) != 1 || delta.Updated[0].Name != "cm1" {
+		t.Fatalf("unexpected updated objects: %v", delta.Updated)
+	}
+	if len(delta.Deleted) != 1 || delta.Deleted[0].UID != "cm2-uid" {
+		t.Fatalf("unexpected deleted objects: %v", delta.Deleted)
+	}
+	emitter.PostSend()
+
+	// the delta is cumulative since the full bundle
+	update(emitter, newConfigMap("cm3", "2"))
+	evtType, dependency := sendEvent(t, emitter)
+	if evtType != string(enum.ManagedClusterDeltaType) || dependency != "0.8" {
+		t.Fatalf("expected the delta bundle depending on 0.8, but got %s(%s)", evtType, dependency)
+	}
+
+	// the full bundle is sent once the resync is requested
+	emitter.(*deltaObjectEmitter).currentVersion.Incr()
+	evtType, _ = sendEvent(t, emitter)
+	if evtType != string(enum.ManagedClusterType) {
+		t.Fatalf("expected the full bundle after resync, but got %s", evtType)
+	}
+
+	// the full bundle is sent once the delta is larger than half of it
+	update(emitter, newConfigMap("cm1", "3"), newConfigMap("cm3", "3"), newConfigMap("cm4", "3"),
+		newConfigMap("cm5", "3"))
+	evtType, _ = sendEvent(t, emitter)
+	if evtType != string(enum.ManagedClusterType) {
+		t.Fatalf("expected the full bundle with the large delta, but got %s", evtType)
+	}
+}
@@ -45,6 +45,8 @@ func LaunchGenericEventSyncer(name string, mgr ctrl.Manager, eventControllers []
 		lock:             &sync.Mutex{},
 	}
 
+	registerCollector(name)
+
 	// start the periodic syncer
 	syncer.startOnce.Do(func() {
 		go syncer.periodicSync()
@@ -85,17 +87,19 @@ func (s *genericEventSyncer) syncEvent() {
 	defer s.lock.Unlock()
 
 	if s.emitter.ShouldSend() {
+		start := time.Now()
 		evt, err := s.emitter.ToCloudEvent()
 		if err != nil {
 			s.log.Error(err, "failed to get CloudEvent instance", "evt", evt)
 			return
 		}
+		observeBundle(evt, start)
 
 		ctx := context.TODO()
 		if s.emitter.Topic() != "" {
 			ctx = cecontext.WithTopic(ctx, s.emitter.Topic())
 		}
-		if err := s.producer.SendEvent(ctx, *evt); err != nil {
+		if err := produceEvent(ctx, s.producer, evt); err != nil {
 			s.log.Error(err, "failed to send event", "evt", evt)
 			return
 		}
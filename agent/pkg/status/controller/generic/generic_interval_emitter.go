@@ -0,0 +1,36 @@
+package generic
+
+import (
+	"time"
+)
+
+var _ ObjectEmitter = &intervalObjectEmitter{}
+
+// intervalObjectEmitter sends the event of the emitter at its own interval, rather than on every tick of the syncer.
+// it's used when the emitters of a syncer have different intervals, the syncer ticks at the shortest one of them.
+type intervalObjectEmitter struct {
+	ObjectEmitter
+	intervalFunc func() time.Duration
+	lastSentTime time.Time
+}
+
+func IntervalObjectEmitter(emitter ObjectEmitter, intervalFunc func() time.Duration) ObjectEmitter {
+	return &intervalObjectEmitter{
+		ObjectEmitter: emitter,
+		intervalFunc:  intervalFunc,
+	}
+}
+
+func (e *intervalObjectEmitter) ShouldSend() bool {
+	// the ticks of the syncer are jittered, tolerate 10% of the interval so that the event isn't delayed a whole tick
+	interval := e.intervalFunc()
+	if time.Since(e.lastSentTime) < interval-interval/10 {
+		return false
+	}
+	return e.ObjectEmitter.ShouldSend()
+}
+
+func (e *intervalObjectEmitter) PostSend() {
+	e.lastSentTime = time.Now()
+	e.ObjectEmitter.PostSend()
+}
@@ -0,0 +1,32 @@
+package generic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestIntervalObjectEmitter(t *testing.T) {
+	interval := 200 * time.Millisecond
+	emitter := IntervalObjectEmitter(
+		ObjectEmitterWrapper(enum.ManagedClusterType, nil, nil, false),
+		func() time.Duration { return interval },
+	)
+
+	update(emitter, newConfigMap("cm1", "1"))
+	if !emitter.ShouldSend() {
+		t.Fatal("the emitter should send the first event")
+	}
+	emitter.PostSend()
+
+	// the change isn't sent until the interval is elapsed
+	update(emitter, newConfigMap("cm1", "2"))
+	if emitter.ShouldSend() {
+		t.Fatal("the emitter shouldn't send the event within the interval")
+	}
+	time.Sleep(interval)
+	if !emitter.ShouldSend() {
+		t.Fatal("the emitter should send the event after the interval")
+	}
+}
@@ -0,0 +1,56 @@
+package generic
+
+import (
+	"context"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// observeBundle records the size of the bundle and the duration to build and serialize it since the start
+func observeBundle(evt *cloudevents.Event, start time.Time) {
+	monitoring.AgentBundleSerializationDurationHistogramVec.WithLabelValues(evt.Type()).
+		Observe(time.Since(start).Seconds())
+	monitoring.AgentBundleSizeHistogramVec.WithLabelValues(evt.Type()).Observe(float64(len(evt.Data())))
+}
+
+// produceEvent sends the bundle to the transport, and records the produce latency, the produced bytes and the last
+// sync of the bundle
+func produceEvent(ctx context.Context, producer transport.Producer, evt *cloudevents.Event) error {
+	start := time.Now()
+	err := producer.SendEvent(ctx, *evt)
+	monitoring.AgentProduceDurationHistogramVec.WithLabelValues(evt.Type()).Observe(time.Since(start).Seconds())
+	if err != nil {
+		monitoring.AgentProduceErrorsCounterVec.WithLabelValues(evt.Type()).Inc()
+		return err
+	}
+	monitoring.AgentProducedBytesCounterVec.WithLabelValues(evt.Type()).Add(float64(len(evt.Data())))
+	recordSync(evt.Type())
+	return nil
+}
+
+// observeCacheSize records the number of the objects in the informer cache of the watched resource, it's skipped if
+// the informer isn't started yet
+func observeCacheSize(ctx context.Context, informers cache.Informers, object client.Object,
+	runtimeClient client.Client,
+) error {
+	gvk, err := apiutil.GVKForObject(object, runtimeClient.Scheme())
+	if err != nil {
+		return err
+	}
+	informer, err := informers.GetInformer(ctx, object, cache.BlockUntilSynced(false))
+	if err != nil {
+		return err
+	}
+	if indexer, ok := informer.(interface{ GetStore() toolscache.Store }); ok {
+		monitoring.AgentWatchCacheObjectsGaugeVec.WithLabelValues(gvk.Kind).Set(float64(len(indexer.GetStore().ListKeys())))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/monitoring"
+)
+
+type metricsProducer struct {
+	err error
+}
+
+func (p *metricsProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	return p.err
+}
+
+func TestObserveBundle(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.metrics.bundle")
+	if err := evt.SetData(cloudevents.ApplicationJSON, []byte(`{"objects":[]}`)); err != nil {
+		t.Fatalf("failed to set the data: %v", err)
+	}
+
+	observeBundle(&evt, time.Now().Add(-time.Second))
+
+	size := histogram(t, monitoring.AgentBundleSizeHistogramVec, evt.Type())
+	if size.GetSampleCount() != 1 || size.GetSampleSum() != float64(len(evt.Data())) {
+		t.Errorf("unexpected bundle size: count %d, sum %f", size.GetSampleCount(), size.GetSampleSum())
+	}
+	duration := histogram(t, monitoring.AgentBundleSerializationDurationHistogramVec, evt.Type())
+	if duration.GetSampleCount() != 1 || duration.GetSampleSum() < 1 {
+		t.Errorf("unexpected serialization duration: count %d, sum %f", duration.GetSampleCount(),
+			duration.GetSampleSum())
+	}
+}
+
+func TestProduceEvent(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.metrics.produce")
+
+	if err := produceEvent(context.Background(), &metricsProducer{}, &evt); err != nil {
+		t.Fatalf("failed to produce the event: %v", err)
+	}
+	if _, ok := GetLastSyncTimes()["metrics.produce"]; !ok {
+		t.Error("the sync of the produced event isn't recorded")
+	}
+
+	sendErr := errors.New("the broker isn't available")
+	if err := produceEvent(context.Background(), &metricsProducer{err: sendErr}, &evt); !errors.Is(err, sendErr) {
+		t.Fatalf("expected the send error, got %v", err)
+	}
+
+	if count := histogram(t, monitoring.AgentProduceDurationHistogramVec, evt.Type()).GetSampleCount(); count != 2 {
+		t.Errorf("expected 2 observations of the produce duration, got %d", count)
+	}
+	if errs := testutil.ToFloat64(monitoring.AgentProduceErrorsCounterVec.WithLabelValues(evt.Type())); errs != 1 {
+		t.Errorf("expected 1 produce error, got %f", errs)
+	}
+}
+
+func histogram(t *testing.T, vec *prometheus.HistogramVec, label string) *dto.Histogram {
+	metric := &dto.Metric{}
+	if err := vec.WithLabelValues(label).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatalf("failed to write the histogram: %v", err)
+	}
+	return metric.GetHistogram()
+}
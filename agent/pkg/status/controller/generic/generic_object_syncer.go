@@ -9,8 +9,12 @@ import (
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -32,6 +36,13 @@ type genericObjectSyncer struct {
 	syncIntervalFunc func() time.Duration
 	startOnce        sync.Once
 	lock             sync.Mutex
+	filter           *resourceFilter
+	// the reader lists the objects from the apiserver directly, rather than starting another informer for them
+	reader client.Reader
+	// the informers of the watched resource, the size of its cache is exposed by the metrics
+	informers cache.Informers
+	// the generation of the selectors which the resources are filtered by
+	selectorGeneration int64
 }
 
 // LaunchGenericObjectSyncer is used to send multi event(by the eventEmitter) by a specific client.Object
@@ -46,8 +57,15 @@ func LaunchGenericObjectSyncer(name string, mgr ctrl.Manager, controller Control
 		controller:       controller,
 		eventEmitters:    eventEmitters,
 		leafHubName:      config.GetLeafHubName(),
+		filter:           &resourceFilter{client: mgr.GetClient()},
+		reader:           mgr.GetAPIReader(),
+		informers:        mgr.GetCache(),
+
+		selectorGeneration: config.GetSelectorGeneration(),
 	}
 
+	registerCollector(name)
+
 	// start the periodic syncer
 	syncer.startOnce.Do(func() {
 		go syncer.periodicSync()
@@ -85,13 +103,24 @@ func (c *genericObjectSyncer) Reconcile(ctx context.Context, request ctrl.Reques
 		return ctrl.Result{}, nil
 	}
 
+	// the object excluded by the selectors is removed from the bundles as it's deleted
+	filtered, err := c.filter.filter(ctx, object)
+	if err != nil {
+		c.log.Error(err, "failed to filter the object", "namespace", request.Namespace, "name", request.Name)
+		return ctrl.Result{Requeue: true, RequeueAfter: REQUEUE_PERIOD}, err
+	}
+	if filtered {
+		c.deleteObject(object)
+		return ctrl.Result{}, nil
+	}
+
 	// update/insert
 	cleanObject(object)
 	c.updateObject(object)
 	if !enableCleanUpFinalizer(object) {
 		return ctrl.Result{}, nil
 	}
-	err := addFinalizer(ctx, c.client, object, FinalizerName)
+	err = addFinalizer(ctx, c.client, object, FinalizerName)
 	if err != nil {
 		c.log.Error(err, "failed to add finalizer", "namespace", request.Namespace, "name", request.Name)
 		return ctrl.Result{Requeue: true, RequeueAfter: REQUEUE_PERIOD}, err
@@ -129,6 +158,20 @@ func (c *genericObjectSyncer) periodicSync() {
 		<-ticker.C // wait for next time interval
 		c.syncEvents()
 
+		if err := observeCacheSize(context.Background(), c.informers, c.controller.Instance(), c.client); err != nil {
+			c.log.V(2).Info("failed to observe the cache size", "error", err.Error())
+		}
+
+		// reconcile all the objects once the selectors are changed, so the excluded ones are removed from the bundles
+		// and the included ones are added back
+		if generation := config.GetSelectorGeneration(); generation != c.selectorGeneration {
+			if err := c.refilter(context.Background()); err != nil {
+				c.log.Error(err, "failed to refilter the objects with the selectors")
+			} else {
+				c.selectorGeneration = generation
+			}
+		}
+
 		resolvedInterval := c.syncIntervalFunc()
 
 		// reset ticker if sync interval has changed
@@ -140,6 +183,29 @@ func (c *genericObjectSyncer) periodicSync() {
 	}
 }
 
+func (c *genericObjectSyncer) refilter(ctx context.Context) error {
+	gvk, err := apiutil.GVKForObject(c.controller.Instance(), c.client.Scheme())
+	if err != nil {
+		return err
+	}
+	objects := &metav1.PartialObjectMetadataList{}
+	objects.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	count := 0
+	err = PagedList(ctx, c.reader, objects, func(object runtime.Object) error {
+		request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(object.(client.Object))}
+		if _, err := c.Reconcile(ctx, request); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.log.Info("the objects are refiltered with the selectors", "count", count)
+	return nil
+}
+
 func (c *genericObjectSyncer) syncEvents() {
 	c.lock.Lock() // make sure bundles are not updated if we're during bundles sync
 	defer c.lock.Unlock()
@@ -148,17 +214,19 @@ func (c *genericObjectSyncer) syncEvents() {
 		emitter := c.eventEmitters[i]
 
 		if emitter.ShouldSend() {
+			start := time.Now()
 			evt, err := emitter.ToCloudEvent()
 			if err != nil {
 				c.log.Error(err, "failed to get CloudEvent instance", "evt", evt)
 			}
 			evt.SetSource(c.leafHubName)
+			observeBundle(evt, start)
 
 			ctx := context.TODO()
 			if emitter.Topic() != "" {
 				ctx = cecontext.WithTopic(ctx, emitter.Topic())
 			}
-			if err := c.producer.SendEvent(ctx, *evt); err != nil {
+			if err := produceEvent(ctx, c.producer, evt); err != nil {
 				c.log.Error(err, "failed to send event", "evt", evt)
 				continue
 			}
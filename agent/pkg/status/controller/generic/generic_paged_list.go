@@ -0,0 +1,35 @@
+package generic
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ListPageSize is the number of the objects listed from the apiserver at once, it keeps the memory of the agent
+// bounded on the hub with thousands of the managed clusters
+var ListPageSize int64 = 500
+
+// PagedList lists the objects from the apiserver page by page, and calls the fn with each object of the page, so the
+// whole set of the objects isn't kept in memory at once. The reader must be the api reader, since the cache doesn't
+// support the continue token and only returns the first page.
+func PagedList(ctx context.Context, reader client.Reader, list client.ObjectList, fn func(object runtime.Object) error,
+	opts ...client.ListOption,
+) error {
+	continueToken := ""
+	for {
+		pageOpts := append([]client.ListOption{client.Limit(ListPageSize), client.Continue(continueToken)}, opts...)
+		if err := reader.List(ctx, list, pageOpts...); err != nil {
+			return err
+		}
+		if err := meta.EachListItem(list, fn); err != nil {
+			return err
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
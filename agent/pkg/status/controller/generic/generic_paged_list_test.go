@@ -0,0 +1,71 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// pagedReader serves the namespaces by the limit and the continue token like the apiserver
+type pagedReader struct {
+	client.Reader
+	names []string
+	calls int
+}
+
+func (r *pagedReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	r.calls++
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	start := 0
+	if listOpts.Continue != "" {
+		start, _ = strconv.Atoi(listOpts.Continue)
+	}
+	end := start + int(listOpts.Limit)
+	namespaces := list.(*corev1.NamespaceList)
+	namespaces.Items = nil
+	namespaces.Continue = ""
+	if end < len(r.names) {
+		namespaces.Continue = strconv.Itoa(end)
+	} else {
+		end = len(r.names)
+	}
+	for _, name := range r.names[start:end] {
+		namespaces.Items = append(namespaces.Items, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	return nil
+}
+
+func TestPagedList(t *testing.T) {
+	pageSize := ListPageSize
+	ListPageSize = 2
+	defer func() { ListPageSize = pageSize }()
+
+	reader := &pagedReader{names: []string{"ns1", "ns2", "ns3", "ns4", "ns5"}}
+	names := []string{}
+	err := PagedList(context.Background(), reader, &corev1.NamespaceList{}, func(object runtime.Object) error {
+		names = append(names, object.(client.Object).GetName())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(names) != fmt.Sprint(reader.names) || reader.calls != 3 {
+		t.Errorf("unexpected objects %v listed by %d pages", names, reader.calls)
+	}
+
+	// the error of the handler stops the listing
+	reader.calls = 0
+	err = PagedList(context.Background(), reader, &corev1.NamespaceList{}, func(object runtime.Object) error {
+		return fmt.Errorf("failed to handle %s", object.(client.Object).GetName())
+	})
+	if err == nil || reader.calls != 1 {
+		t.Errorf("expected the listing to be stopped by the error, got %v by %d pages", err, reader.calls)
+	}
+}
@@ -0,0 +1,116 @@
+package generic
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+)
+
+// resourceFilter excludes the resources which don't match the selectors of the agent config, so that the resources
+// of the sensitive tenants aren't reported to the global hub:
+//   - the managed clusters are selected by the managed cluster selector, and the resources in the namespace of the
+//     excluded cluster, e.g. the replicated policies, are excluded as well
+//   - the policies are selected by the policy selector, and the excluded clusters are removed from their status
+//   - the namespaced resources are selected by the labels of their namespace
+//   - the events of the policy are excluded along with the policy
+type resourceFilter struct {
+	client client.Client
+}
+
+// filter returns true if the object is excluded from the bundles
+func (f *resourceFilter) filter(ctx context.Context, object client.Object) (bool, error) {
+	if !config.HasSelector() {
+		return false, nil
+	}
+
+	switch obj := object.(type) {
+	case *clusterv1.ManagedCluster:
+		return !config.GetSelector(config.ManagedClusterSelectorKey).Matches(labels.Set(obj.GetLabels())), nil
+	case *policiesv1.Policy:
+		if !config.GetSelector(config.PolicySelectorKey).Matches(labels.Set(obj.GetLabels())) {
+			return true, nil
+		}
+		if err := f.pruneClusters(ctx, obj); err != nil {
+			return false, err
+		}
+	case *corev1.Event:
+		if obj.InvolvedObject.Kind == policiesv1.Kind {
+			policy := &policiesv1.Policy{}
+			err := f.client.Get(ctx, types.NamespacedName{
+				Namespace: obj.InvolvedObject.Namespace,
+				Name:      obj.InvolvedObject.Name,
+			}, policy)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+			if err == nil && !config.GetSelector(config.PolicySelectorKey).Matches(labels.Set(policy.GetLabels())) {
+				return true, nil
+			}
+		}
+	}
+
+	if object.GetNamespace() == "" {
+		return false, nil
+	}
+	return f.filterNamespace(ctx, object.GetNamespace())
+}
+
+// filterNamespace returns true if the namespace doesn't match the namespace selector, or it's the namespace of the
+// excluded managed cluster
+func (f *resourceFilter) filterNamespace(ctx context.Context, name string) (bool, error) {
+	if namespaceSelector := config.GetSelector(config.NamespaceSelectorKey); !namespaceSelector.Empty() {
+		namespace := &corev1.Namespace{}
+		if err := f.client.Get(ctx, types.NamespacedName{Name: name}, namespace); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		if !namespaceSelector.Matches(labels.Set(namespace.GetLabels())) {
+			return true, nil
+		}
+	}
+	return f.filterCluster(ctx, name)
+}
+
+// filterCluster returns true if the managed cluster exists and it doesn't match the managed cluster selector
+func (f *resourceFilter) filterCluster(ctx context.Context, name string) (bool, error) {
+	clusterSelector := config.GetSelector(config.ManagedClusterSelectorKey)
+	if clusterSelector.Empty() {
+		return false, nil
+	}
+	cluster := &clusterv1.ManagedCluster{}
+	if err := f.client.Get(ctx, types.NamespacedName{Name: name}, cluster); err != nil {
+		// the managed cluster crd doesn't exist on the standalone cluster
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !clusterSelector.Matches(labels.Set(cluster.GetLabels())), nil
+}
+
+// pruneClusters removes the excluded managed clusters from the compliance status of the policy
+func (f *resourceFilter) pruneClusters(ctx context.Context, policy *policiesv1.Policy) error {
+	if config.GetSelector(config.ManagedClusterSelectorKey).Empty() {
+		return nil
+	}
+	statuses := make([]*policiesv1.CompliancePerClusterStatus, 0, len(policy.Status.Status))
+	for _, status := range policy.Status.Status {
+		filtered, err := f.filterCluster(ctx, status.ClusterName)
+		if err != nil {
+			return err
+		}
+		if !filtered {
+			statuses = append(statuses, status)
+		}
+	}
+	policy.Status.Status = statuses
+	return nil
+}
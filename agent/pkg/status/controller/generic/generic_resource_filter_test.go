@@ -0,0 +1,104 @@
+package generic
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+)
+
+func TestResourceFilter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = clusterv1.AddToScheme(scheme)
+	_ = policiesv1.AddToScheme(scheme)
+
+	newCluster := func(name, tenant string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: map[string]string{"tenant": tenant},
+		}}
+	}
+	newNamespace := func(name, tenant string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Labels: map[string]string{"tenant": tenant},
+		}}
+	}
+	newPolicy := func(namespace, name, tenant string, clusters ...string) *policiesv1.Policy {
+		policy := &policiesv1.Policy{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: name, Labels: map[string]string{"tenant": tenant},
+		}}
+		for _, cluster := range clusters {
+			policy.Status.Status = append(policy.Status.Status, &policiesv1.CompliancePerClusterStatus{
+				ClusterName: cluster, ComplianceState: policiesv1.Compliant,
+			})
+		}
+		return policy
+	}
+
+	filter := &resourceFilter{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCluster("cluster1", "public"), newCluster("cluster2", "secret"),
+		newNamespace("default", "public"), newNamespace("secret", "secret"), newNamespace("cluster2", "public"),
+		newPolicy("default", "policy1", "secret"),
+	).Build()}
+
+	ctx := context.Background()
+	cases := []struct {
+		name     string
+		object   client.Object
+		filtered bool
+	}{
+		{"the public cluster", newCluster("cluster1", "public"), false},
+		{"the secret cluster", newCluster("cluster2", "secret"), true},
+		{"the public policy", newPolicy("default", "policy2", "public"), false},
+		{"the secret policy", newPolicy("default", "policy1", "secret"), true},
+		{"the policy in the secret namespace", newPolicy("secret", "policy2", "public"), true},
+		{"the policy in the secret cluster namespace", newPolicy("cluster2", "policy2", "public"), true},
+		{"the event of the secret policy", &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "event1"},
+			InvolvedObject: corev1.ObjectReference{Kind: policiesv1.Kind, Namespace: "default", Name: "policy1"},
+		}, true},
+	}
+
+	// nothing is filtered without the selectors
+	for _, c := range cases {
+		filtered, err := filter.filter(ctx, c.object)
+		if err != nil || filtered {
+			t.Fatalf("%s shouldn't be filtered without the selectors: %v", c.name, err)
+		}
+	}
+
+	for _, key := range []config.AgentConfigKey{
+		config.ManagedClusterSelectorKey, config.PolicySelectorKey, config.NamespaceSelectorKey,
+	} {
+		if err := config.SetSelector(key, "tenant!=secret"); err != nil {
+			t.Fatal(err)
+		}
+		defer func(key config.AgentConfigKey) { _ = config.SetSelector(key, "") }(key)
+	}
+	for _, c := range cases {
+		filtered, err := filter.filter(ctx, c.object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filtered != c.filtered {
+			t.Fatalf("%s: expected filtered %v, but got %v", c.name, c.filtered, filtered)
+		}
+	}
+
+	// the secret cluster is removed from the policy status
+	policy := newPolicy("default", "policy2", "public", "cluster1", "cluster2")
+	if _, err := filter.filter(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Status.Status) != 1 || policy.Status.Status[0].ClusterName != "cluster1" {
+		t.Fatalf("expected only the public cluster in the policy status, but got %v", policy.Status.Status)
+	}
+}
@@ -0,0 +1,57 @@
+package generic
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// syncStatus records the syncers launched by the agent and the last time each bundle is sent to the transport, they're
+// reported with the heartbeat of the agent
+var syncStatus = struct {
+	sync.RWMutex
+	collectors    map[string]bool
+	lastSyncTimes map[string]time.Time
+}{
+	collectors:    map[string]bool{},
+	lastSyncTimes: map[string]time.Time{},
+}
+
+func registerCollector(name string) {
+	syncStatus.Lock()
+	defer syncStatus.Unlock()
+	syncStatus.collectors[name] = true
+}
+
+// recordSync records the bundle is sent successfully, the bundle is named by the event type without the prefix
+func recordSync(eventType string) {
+	syncStatus.Lock()
+	defer syncStatus.Unlock()
+	syncStatus.lastSyncTimes[strings.TrimPrefix(eventType, enum.EventTypePrefix)] = time.Now()
+}
+
+// GetCollectors returns the names of the syncers launched by the agent
+func GetCollectors() []string {
+	syncStatus.RLock()
+	defer syncStatus.RUnlock()
+	collectors := make([]string, 0, len(syncStatus.collectors))
+	for name := range syncStatus.collectors {
+		collectors = append(collectors, name)
+	}
+	sort.Strings(collectors)
+	return collectors
+}
+
+// GetLastSyncTimes returns the last time each bundle is sent successfully
+func GetLastSyncTimes() map[string]time.Time {
+	syncStatus.RLock()
+	defer syncStatus.RUnlock()
+	lastSyncTimes := make(map[string]time.Time, len(syncStatus.lastSyncTimes))
+	for bundle, syncTime := range syncStatus.lastSyncTimes {
+		lastSyncTimes[bundle] = syncTime
+	}
+	return lastSyncTimes
+}
@@ -0,0 +1,31 @@
+package generic
+
+import (
+	"testing"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestSyncStatus(t *testing.T) {
+	registerCollector("status.managed_cluster")
+	registerCollector("status.hub_cluster_heartbeat")
+	registerCollector("status.managed_cluster")
+
+	collectors := GetCollectors()
+	if len(collectors) != 2 || collectors[0] != "status.hub_cluster_heartbeat" ||
+		collectors[1] != "status.managed_cluster" {
+		t.Errorf("unexpected collectors: %v", collectors)
+	}
+
+	recordSync(string(enum.ManagedClusterType))
+	lastSyncTimes := GetLastSyncTimes()
+	if syncTime, ok := lastSyncTimes["managedcluster"]; !ok || syncTime.IsZero() {
+		t.Errorf("unexpected last sync times: %v", lastSyncTimes)
+	}
+
+	// the returned times are a copy of the status
+	delete(lastSyncTimes, "managedcluster")
+	if _, ok := GetLastSyncTimes()["managedcluster"]; !ok {
+		t.Error("the last sync times are modified by the caller")
+	}
+}
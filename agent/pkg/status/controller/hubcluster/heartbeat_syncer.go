@@ -7,10 +7,11 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
-	genericdata "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/version"
 )
 
 func LaunchHubClusterHeartbeatSyncer(mgr ctrl.Manager, producer transport.Producer) error {
@@ -53,10 +54,20 @@ func (s *heartbeatEmitter) ToCloudEvent() (*cloudevents.Event, error) {
 	e.SetSource(config.GetLeafHubName())
 	e.SetType(string(s.eventType))
 	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
-	err := e.SetData(cloudevents.ApplicationJSON, genericdata.GenericObjectBundle{})
+	err := e.SetData(cloudevents.ApplicationJSON, heartbeatPayload())
 	return &e, err
 }
 
+// heartbeatPayload reports the version and the health of the agent with the heartbeat
+func heartbeatPayload() *cluster.HubHeartbeat {
+	return &cluster.HubHeartbeat{
+		AgentVersion:   version.Get(),
+		Collectors:     generic.GetCollectors(),
+		LastSyncTimes:  generic.GetLastSyncTimes(),
+		ResourceCounts: getLastResourceCounts(),
+	}
+}
+
 func (s *heartbeatEmitter) Topic() string    { return "" }
 func (s *heartbeatEmitter) ShouldSend() bool { return true }
 func (s *heartbeatEmitter) PostSend() {
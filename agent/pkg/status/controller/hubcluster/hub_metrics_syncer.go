@@ -0,0 +1,244 @@
+package hubcluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// the token of the agent service account, which is authorized to query the prometheus of the managed hub
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" // #nosec G101
+
+// hubMetricQueries are the key metrics of the managed hub, they're aggregated by the queries so only a few series
+// are reported for each hub
+var hubMetricQueries = []struct {
+	name  string
+	query string
+}{
+	{
+		// the latency of the configuration policy controller to evaluate the policies
+		name:  "policy_evaluation_latency_seconds",
+		query: `histogram_quantile(0.95, sum(rate(config_policies_evaluation_duration_seconds_bucket[5m])) by (le))`,
+	},
+	{
+		// the latency of the policy propagator to handle the root policies
+		name:  "policy_propagation_latency_seconds",
+		query: `histogram_quantile(0.95, sum(rate(ocm_handle_root_policy_duration_seconds_bucket[5m])) by (le))`,
+	},
+	{
+		name: "apiserver_request_latency_seconds",
+		query: `histogram_quantile(0.99, sum(rate(apiserver_request_duration_seconds_bucket{verb!="WATCH"}[5m]))` +
+			` by (le, verb))`,
+	},
+	{
+		name:  "agent_produce_errors_rate",
+		query: `sum(rate(multicluster_global_hub_agent_produce_errors_total[5m]))`,
+	},
+}
+
+// LaunchHubMetricsSyncer reports the key metrics of the managed hub queried from its prometheus, it's disabled if the
+// prometheus url isn't specified
+func LaunchHubMetricsSyncer(mgr ctrl.Manager, agentConfig *agentconfig.AgentConfig,
+	producer transport.Producer,
+) error {
+	if agentConfig.HubMetricsPrometheusURL == "" {
+		return nil
+	}
+	emitter, err := NewHubMetricsEmitter(agentConfig.HubMetricsPrometheusURL, agentConfig.HubMetricsCACertPath,
+		serviceAccountTokenPath)
+	if err != nil {
+		return err
+	}
+	return generic.LaunchGenericEventSyncer(
+		"status.hub_metrics",
+		mgr,
+		nil,
+		producer,
+		func() time.Duration { return agentConfig.HubMetricsInterval },
+		emitter,
+	)
+}
+
+var _ generic.Emitter = &hubMetricsEmitter{}
+
+type hubMetricsEmitter struct {
+	log             logr.Logger
+	prometheusURL   string
+	tokenPath       string
+	httpClient      *http.Client
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	metrics         cluster.HubMetrics
+}
+
+func NewHubMetricsEmitter(prometheusURL, caCertPath, tokenPath string) (*hubMetricsEmitter, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caCertPath != "" {
+		caCert, err := os.ReadFile(filepath.Clean(caCertPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate of the prometheus: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to append the CA certificate of the prometheus")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &hubMetricsEmitter{
+		log:           ctrl.Log.WithName("hub-metrics"),
+		prometheusURL: strings.TrimSuffix(prometheusURL, "/"),
+		tokenPath:     tokenPath,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		eventType:       enum.HubMetricsType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}, nil
+}
+
+// the metrics are queried on each sync, not updated by the event controllers
+func (e *hubMetricsEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (e *hubMetricsEmitter) PostUpdate() {
+	e.currentVersion.Incr()
+}
+
+func (e *hubMetricsEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(config.GetLeafHubName())
+	evt.SetType(string(e.eventType))
+	evt.SetExtension(eventversion.ExtVersion, e.currentVersion.String())
+	err := evt.SetData(cloudevents.ApplicationJSON, e.metrics)
+	return &evt, err
+}
+
+func (e *hubMetricsEmitter) Topic() string { return "" }
+
+// ShouldSend sends the samples on each interval, the failed query is skipped so the other metrics are still reported
+func (e *hubMetricsEmitter) ShouldSend() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	samples := []cluster.HubMetricSample{}
+	for _, metric := range hubMetricQueries {
+		results, err := e.query(ctx, metric.query)
+		if err != nil {
+			e.log.Error(err, "failed to query the metric", "name", metric.name)
+			continue
+		}
+		for _, result := range results {
+			result.Name = metric.name
+			samples = append(samples, result)
+		}
+	}
+	if len(samples) == 0 {
+		return false
+	}
+	e.metrics = cluster.HubMetrics{Samples: samples, SampledAt: time.Now()}
+	e.PostUpdate()
+	return e.currentVersion.NewerThan(&e.lastSentVersion)
+}
+
+func (e *hubMetricsEmitter) PostSend() {
+	e.currentVersion.Next()
+	e.lastSentVersion = *e.currentVersion
+}
+
+// queryResponse is the instant query response of the prometheus http api
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			// the value is [<unix time>, "<sample value>"]
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// query runs the instant query, the series with the NaN value, e.g. the histogram quantile without any request, are
+// skipped
+func (e *hubMetricsEmitter) query(ctx context.Context, query string) ([]cluster.HubMetricSample, error) {
+	endpoint := e.prometheusURL + "/api/v1/query?" + url.Values{"query": []string{query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if e.tokenPath != "" {
+		token, err := os.ReadFile(filepath.Clean(e.tokenPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	response := &queryResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, err
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("failed to query the prometheus: %s", response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected result type %s", response.Data.ResultType)
+	}
+
+	samples := make([]cluster.HubMetricSample, 0, len(response.Data.Result))
+	for _, result := range response.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+		raw, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value != value { // NaN
+			continue
+		}
+		delete(result.Metric, "__name__")
+		samples = append(samples, cluster.HubMetricSample{Labels: result.Metric, Value: value})
+	}
+	return samples, nil
+}
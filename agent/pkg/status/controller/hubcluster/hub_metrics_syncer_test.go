@@ -0,0 +1,58 @@
+package hubcluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubMetricsEmitter(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("abc\n"), 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("query") {
+		case hubMetricQueries[0].query:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{},"value":[1700000000.1,"0.25"]}]}}`))
+		case hubMetricQueries[2].query:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"__name__":"x","verb":"GET"},"value":[1700000000.1,"0.05"]},
+				{"metric":{"verb":"POST"},"value":[1700000000.1,"NaN"]}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"bad query"}`))
+		}
+	}))
+	defer server.Close()
+
+	emitter, err := NewHubMetricsEmitter(server.URL+"/", "", tokenPath)
+	require.NoError(t, err)
+
+	// the failed queries and the NaN samples are skipped
+	require.True(t, emitter.ShouldSend())
+	samples := emitter.metrics.Samples
+	require.Len(t, samples, 2)
+	assert.Equal(t, "policy_evaluation_latency_seconds", samples[0].Name)
+	assert.Equal(t, 0.25, samples[0].Value)
+	assert.Equal(t, "apiserver_request_latency_seconds", samples[1].Name)
+	assert.Equal(t, map[string]string{"verb": "GET"}, samples[1].Labels)
+
+	evt, err := emitter.ToCloudEvent()
+	require.NoError(t, err)
+	assert.Equal(t, "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.metrics", evt.Type())
+	emitter.PostSend()
+
+	// nothing is sent if the prometheus isn't available
+	server.Close()
+	assert.False(t, emitter.ShouldSend())
+}
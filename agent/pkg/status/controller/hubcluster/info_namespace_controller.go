@@ -0,0 +1,45 @@
+package hubcluster
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/managedclusters"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+var _ generic.EventController = &infoNamespaceController{}
+
+// infoNamespaceController sets the cluster id of the standalone cluster, which has no id.k8s.io clusterclaim, with the
+// uid of the kube-system namespace
+type infoNamespaceController struct {
+	generic.Controller
+	evtData cluster.HubClusterInfoBundle
+}
+
+func NewInfoNamespaceController(eventData cluster.HubClusterInfoBundle) generic.EventController {
+	instance := func() client.Object { return &corev1.Namespace{} }
+	namespacePredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetName() == managedclusters.StandaloneClusterIDNamespace
+	})
+	return &infoNamespaceController{
+		Controller: generic.NewGenericController(instance, namespacePredicate),
+		evtData:    eventData,
+	}
+}
+
+func (p *infoNamespaceController) Update(obj client.Object) bool {
+	if obj.GetName() != managedclusters.StandaloneClusterIDNamespace || obj.GetUID() == "" {
+		return false
+	}
+	oldClusterID := p.evtData.ClusterId
+	p.evtData.ClusterId = string(obj.GetUID())
+	return oldClusterID != p.evtData.ClusterId
+}
+
+func (p *infoNamespaceController) Delete(obj client.Object) bool {
+	// do nothing
+	return false
+}
@@ -24,3 +24,18 @@ func LaunchHubClusterInfoSyncer(mgr ctrl.Manager, producer transport.Producer) e
 		generic.NewGenericEmitter(enum.HubClusterInfoType, eventData),
 	)
 }
+
+// LaunchStandaloneHubClusterInfoSyncer reports the info of the standalone cluster, which has no clusterclaims
+func LaunchStandaloneHubClusterInfoSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	eventData := &cluster.HubClusterInfo{}
+	return generic.LaunchGenericEventSyncer(
+		"status.hub_cluster_info",
+		mgr,
+		[]generic.EventController{
+			NewInfoNamespaceController(eventData),
+		},
+		producer,
+		config.GetHubClusterInfoDuration,
+		generic.NewGenericEmitter(enum.HubClusterInfoType, eventData),
+	)
+}
@@ -0,0 +1,140 @@
+package hubcluster
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// LaunchHubResourceCountsSyncer reports the number of the resources synced to the global hub, so the global hub can
+// detect the lost messages by comparing them with the database
+func LaunchHubResourceCountsSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	return generic.LaunchGenericEventSyncer(
+		"status.hub_resource_counts",
+		mgr,
+		nil,
+		producer,
+		config.GetHeartbeatDuration,
+		NewResourceCountsEmitter(mgr.GetClient()),
+	)
+}
+
+// lastResourceCounts is the last counts of the resources, which are also reported with the heartbeat
+var lastResourceCounts = struct {
+	sync.RWMutex
+	counts map[string]int
+}{}
+
+func getLastResourceCounts() map[string]int {
+	lastResourceCounts.RLock()
+	defer lastResourceCounts.RUnlock()
+	return lastResourceCounts.counts
+}
+
+var _ generic.Emitter = &resourceCountsEmitter{}
+
+func NewResourceCountsEmitter(runtimeClient client.Client) *resourceCountsEmitter {
+	return &resourceCountsEmitter{
+		log:             ctrl.Log.WithName("hub-resource-counts"),
+		runtimeClient:   runtimeClient,
+		eventType:       enum.HubResourceCountsType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+		counts:          cluster.HubResourceCounts{Counts: map[string]int{}},
+	}
+}
+
+type resourceCountsEmitter struct {
+	log             logr.Logger
+	runtimeClient   client.Client
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	counts          cluster.HubResourceCounts
+}
+
+// the counts are listed from the cache on each sync, not updated by the event controllers
+func (s *resourceCountsEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *resourceCountsEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *resourceCountsEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, s.counts)
+	return &e, err
+}
+
+func (s *resourceCountsEmitter) Topic() string { return "" }
+
+// ShouldSend sends the counts once the agent is started, then only when they're changed
+func (s *resourceCountsEmitter) ShouldSend() bool {
+	counts, err := s.countResources(context.Background())
+	if err != nil {
+		s.log.Error(err, "failed to count the resources")
+		return false
+	}
+	if !reflect.DeepEqual(counts, s.counts.Counts) || s.counts.CountedAt.IsZero() {
+		s.counts = cluster.HubResourceCounts{Counts: counts, CountedAt: time.Now()}
+		s.PostUpdate()
+
+		lastResourceCounts.Lock()
+		lastResourceCounts.counts = counts
+		lastResourceCounts.Unlock()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *resourceCountsEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+// countResources counts the resources in the same way as they're filtered by the status syncers
+func (s *resourceCountsEmitter) countResources(ctx context.Context) (map[string]int, error) {
+	counts := map[string]int{}
+
+	// the resources are counted from the cache without copying them, since they're only read here
+	clusters := &clusterv1.ManagedClusterList{}
+	if err := s.runtimeClient.List(ctx, clusters, client.UnsafeDisableDeepCopy); err != nil {
+		return nil, err
+	}
+	counts[cluster.ResourceManagedClusters] = len(clusters.Items)
+
+	if config.GetEnableLocalPolicy() == config.EnableLocalPolicyTrue {
+		policies := &policiesv1.PolicyList{}
+		if err := s.runtimeClient.List(ctx, policies, client.UnsafeDisableDeepCopy); err != nil {
+			return nil, err
+		}
+		localPolicies := 0
+		for i := range policies.Items {
+			if !utils.HasAnnotation(&policies.Items[i], constants.OriginOwnerReferenceAnnotation) &&
+				!utils.HasLabel(&policies.Items[i], constants.PolicyEventRootPolicyNameLabelKey) {
+				localPolicies++
+			}
+		}
+		counts[cluster.ResourceLocalPolicies] = localPolicies
+	}
+	return counts, nil
+}
@@ -30,7 +30,8 @@ func LaunchManagedClusterSyncer(ctx context.Context, mgr ctrl.Manager, agentConf
 			constants.ManagedClusterManagedByAnnotation: statusconfig.GetLeafHubName(),
 		})
 	}
-	emitter := generic.ObjectEmitterWrapper(enum.ManagedClusterType, nil, tweakFunc, false)
+	emitter := generic.DeltaObjectEmitterWrapper(enum.ManagedClusterType, enum.ManagedClusterDeltaType,
+		nil, tweakFunc, false)
 
 	return generic.LaunchGenericObjectSyncer(
 		"status.managed_cluster",
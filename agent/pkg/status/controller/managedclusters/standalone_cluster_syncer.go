@@ -0,0 +1,124 @@
+package managedclusters
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// the standalone cluster is identified by the uid of the kube-system namespace, which is also the id.k8s.io
+// clusterclaim of the managed cluster
+const StandaloneClusterIDNamespace = "kube-system"
+
+// LaunchStandaloneClusterSyncer reports the standalone cluster itself as the only managed cluster of the hub, so its
+// policies and events are related to the cluster on the global hub
+func LaunchStandaloneClusterSyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig,
+	producer transport.Producer,
+) error {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	serverVersion, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get the kubernetes version: %w", err)
+	}
+
+	instance := func() client.Object { return &corev1.Namespace{} }
+	predicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetName() == StandaloneClusterIDNamespace
+	})
+
+	return generic.LaunchGenericObjectSyncer(
+		"status.standalone_cluster",
+		mgr,
+		generic.NewGenericController(instance, predicate),
+		producer,
+		statusconfig.GetManagerClusterDuration,
+		[]generic.ObjectEmitter{
+			&standaloneClusterEmitter{
+				ObjectEmitter: generic.ObjectEmitterWrapper(enum.ManagedClusterType, nil, nil, false),
+				kubeVersion:   serverVersion.GitVersion,
+			},
+		})
+}
+
+var _ generic.ObjectEmitter = &standaloneClusterEmitter{}
+
+// standaloneClusterEmitter converts the kube-system namespace into the managed cluster of the standalone cluster
+type standaloneClusterEmitter struct {
+	generic.ObjectEmitter
+	kubeVersion string
+}
+
+func (e *standaloneClusterEmitter) Update(obj client.Object) bool {
+	namespace, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return false
+	}
+	return e.ObjectEmitter.Update(e.standaloneCluster(namespace))
+}
+
+// Delete does nothing, the kube-system namespace isn't deleted
+func (e *standaloneClusterEmitter) Delete(obj client.Object) bool {
+	return false
+}
+
+func (e *standaloneClusterEmitter) standaloneCluster(namespace *corev1.Namespace) *clusterv1.ManagedCluster {
+	name := statusconfig.GetLeafHubName()
+	return &clusterv1.ManagedCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ManagedCluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               namespace.GetUID(),
+			ResourceVersion:   namespace.GetResourceVersion(),
+			CreationTimestamp: namespace.GetCreationTimestamp(),
+			Labels: map[string]string{
+				"name": name,
+			},
+			Annotations: map[string]string{
+				constants.ManagedClusterManagedByAnnotation: name,
+			},
+		},
+		Spec: clusterv1.ManagedClusterSpec{
+			HubAcceptsClient: true,
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:               clusterv1.ManagedClusterConditionAvailable,
+					Status:             metav1.ConditionTrue,
+					Reason:             "StandaloneAgentAvailable",
+					Message:            "The standalone agent is reporting the cluster",
+					LastTransitionTime: namespace.GetCreationTimestamp(),
+				},
+			},
+			Version: clusterv1.ManagedClusterVersion{
+				Kubernetes: e.kubeVersion,
+			},
+			ClusterClaims: []clusterv1.ManagedClusterClaim{
+				{
+					Name:  "id.k8s.io",
+					Value: string(namespace.GetUID()),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package migration
+
+import (
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/controller/syncers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// LaunchMigrationStatusSyncer reports the results of running the managed cluster migration phases on the hub, so the
+// manager moves the migrations forward.
+func LaunchMigrationStatusSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	return generic.LaunchGenericEventSyncer(
+		"status.managed_cluster_migration",
+		mgr,
+		nil,
+		producer,
+		config.GetHeartbeatDuration,
+		NewMigrationStatusEmitter(),
+	)
+}
+
+var _ generic.Emitter = &migrationStatusEmitter{}
+
+func NewMigrationStatusEmitter() *migrationStatusEmitter {
+	return &migrationStatusEmitter{
+		eventType:       enum.ManagedClusterMigrationType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+}
+
+type migrationStatusEmitter struct {
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	generation      uint64
+}
+
+// the statuses are recorded by the migration syncer, not by the event controllers
+func (s *migrationStatusEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *migrationStatusEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *migrationStatusEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	statuses, _ := syncers.GetMigrationStatuses()
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, statuses)
+	return &e, err
+}
+
+func (s *migrationStatusEmitter) Topic() string { return "" }
+
+func (s *migrationStatusEmitter) ShouldSend() bool {
+	if _, generation := syncers.GetMigrationStatuses(); generation != s.generation {
+		s.generation = generation
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *migrationStatusEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
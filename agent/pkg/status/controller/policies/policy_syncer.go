@@ -60,7 +60,8 @@ func LaunchPolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *conf
 	)
 
 	// 4. local policy spec
-	localPolicySpecEmitter := generic.ObjectEmitterWrapper(enum.LocalPolicySpecType,
+	localPolicySpecEmitter := generic.DeltaObjectEmitterWrapper(enum.LocalPolicySpecType,
+		enum.LocalPolicySpecDeltaType,
 		func(obj client.Object) bool {
 			return statusconfig.GetEnableLocalPolicy() == statusconfig.EnableLocalPolicyTrue && // enable local policy
 				!utils.HasAnnotation(obj, constants.OriginOwnerReferenceAnnotation) && // local resource
@@ -91,20 +92,22 @@ func LaunchPolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *conf
 		compliancePredicate,
 	)
 
+	// the syncer ticks at the shortest interval, and each emitter is sent at the interval of its resource
 	return generic.LaunchGenericObjectSyncer(
 		"status.policy",
 		mgr,
 		controller,
 		producer,
-		statusconfig.GetPolicyDuration,
+		statusconfig.MinDuration(statusconfig.GetPolicyDuration, statusconfig.GetComplianceDuration,
+			statusconfig.GetEventDuration),
 		[]generic.ObjectEmitter{
-			localComplianceEmitter,
-			localCompleteEmitter,
-			localStatusEventEmitter,
-			localPolicySpecEmitter,
+			generic.IntervalObjectEmitter(localComplianceEmitter, statusconfig.GetComplianceDuration),
+			generic.IntervalObjectEmitter(localCompleteEmitter, statusconfig.GetComplianceDuration),
+			generic.IntervalObjectEmitter(localStatusEventEmitter, statusconfig.GetEventDuration),
+			generic.IntervalObjectEmitter(localPolicySpecEmitter, statusconfig.GetPolicyDuration),
 			// global compliance
-			complianceEmitter,
-			completeEmitter,
+			generic.IntervalObjectEmitter(complianceEmitter, statusconfig.GetComplianceDuration),
+			generic.IntervalObjectEmitter(completeEmitter, statusconfig.GetComplianceDuration),
 		})
 }
 
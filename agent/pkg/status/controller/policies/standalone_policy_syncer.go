@@ -0,0 +1,87 @@
+package policies
+
+import (
+	"context"
+
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/config"
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// LaunchStandalonePolicySyncer reports the policies evaluated by the policy framework of the standalone cluster as the
+// local policies, and their compliance as the compliance of the standalone cluster itself.
+func LaunchStandalonePolicySyncer(ctx context.Context, mgr ctrl.Manager, agentConfig *config.AgentConfig,
+	producer transport.Producer,
+) error {
+	instance := func() client.Object { return &policiesv1.Policy{} }
+	predicate := predicate.NewPredicateFuncs(func(object client.Object) bool { return true })
+	controller := generic.NewGenericController(instance, predicate)
+
+	shouldUpdate := func(obj client.Object) bool {
+		return statusconfig.GetEnableLocalPolicy() == statusconfig.EnableLocalPolicyTrue
+	}
+
+	// 1. local compliance
+	localComplianceVersion := eventversion.NewVersion()
+	localComplianceEmitter := &standaloneComplianceEmitter{
+		ObjectEmitter: ComplianceEmitterWrapper(enum.LocalComplianceType, localComplianceVersion, shouldUpdate),
+	}
+
+	// 2. local complete compliance
+	localCompleteEmitter := &standaloneComplianceEmitter{
+		ObjectEmitter: CompleteComplianceEmitterWrapper(enum.LocalCompleteComplianceType, localComplianceVersion,
+			shouldUpdate),
+	}
+
+	// 3. local policy spec
+	localPolicySpecEmitter := generic.DeltaObjectEmitterWrapper(enum.LocalPolicySpecType,
+		enum.LocalPolicySpecDeltaType, shouldUpdate, cleanPolicy, true)
+
+	return generic.LaunchGenericObjectSyncer(
+		"status.standalone_policy",
+		mgr,
+		controller,
+		producer,
+		statusconfig.MinDuration(statusconfig.GetPolicyDuration, statusconfig.GetComplianceDuration),
+		[]generic.ObjectEmitter{
+			generic.IntervalObjectEmitter(localComplianceEmitter, statusconfig.GetComplianceDuration),
+			generic.IntervalObjectEmitter(localCompleteEmitter, statusconfig.GetComplianceDuration),
+			generic.IntervalObjectEmitter(localPolicySpecEmitter, statusconfig.GetPolicyDuration),
+		})
+}
+
+var _ generic.ObjectEmitter = &standaloneComplianceEmitter{}
+
+// standaloneComplianceEmitter converts the compliance of the policy on the standalone cluster into the status of the
+// root policy, which lists the compliance per cluster, so it's handled as the local policy of the managed hub.
+type standaloneComplianceEmitter struct {
+	generic.ObjectEmitter
+}
+
+func (e *standaloneComplianceEmitter) Update(obj client.Object) bool {
+	policy, ok := obj.(*policiesv1.Policy)
+	if !ok {
+		return false
+	}
+	return e.ObjectEmitter.Update(standalonePolicy(policy))
+}
+
+func standalonePolicy(policy *policiesv1.Policy) *policiesv1.Policy {
+	standalone := policy.DeepCopy()
+	standalone.Status.Status = []*policiesv1.CompliancePerClusterStatus{
+		{
+			ClusterName:      statusconfig.GetLeafHubName(),
+			ClusterNamespace: policy.GetNamespace(),
+			ComplianceState:  policy.Status.ComplianceState,
+		},
+	}
+	return standalone
+}
@@ -0,0 +1,43 @@
+package policies
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestStandaloneComplianceEmitter(t *testing.T) {
+	emitter := &standaloneComplianceEmitter{
+		ObjectEmitter: ComplianceEmitterWrapper(enum.LocalComplianceType, eventversion.NewVersion(), nil),
+	}
+
+	policy := &policiesv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "policy1", UID: "1234"},
+		Status:     policiesv1.PolicyStatus{ComplianceState: policiesv1.NonCompliant},
+	}
+	if !emitter.Update(policy) {
+		t.Fatal("the compliance of the standalone policy should be updated")
+	}
+	if len(policy.Status.Status) != 0 {
+		t.Fatal("the policy from the cache shouldn't be changed")
+	}
+
+	evt, err := emitter.ToCloudEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	compliances := grc.ComplianceBundle{}
+	if err := evt.DataAs(&compliances); err != nil {
+		t.Fatal(err)
+	}
+	if len(compliances) != 1 || len(compliances[0].NonCompliantClusters) != 1 ||
+		compliances[0].NonCompliantClusters[0] != statusconfig.GetLeafHubName() {
+		t.Fatalf("expected the standalone cluster is non compliant, but got %v", compliances)
+	}
+}
@@ -115,6 +115,11 @@ func (h *statusEventEmitter) Update(obj client.Object) bool {
 							Component: "policy-status-history-sync",
 						},
 						CreatedAt: evt.LastTimestamp,
+						InvolvedObject: event.InvolvedObject{
+							Kind:      policiesv1.Kind,
+							Namespace: policy.Namespace,
+							Name:      policy.Name,
+						},
 					},
 					PolicyID:   string(rootPolicy.GetUID()),
 					ClusterID:  clusterID,
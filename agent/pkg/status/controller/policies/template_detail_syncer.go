@@ -0,0 +1,275 @@
+package policies
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	statusconfig "github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// ConfigurationPolicyGVK is the template kind reporting the objects it checks as the related objects
+var ConfigurationPolicyGVK = schema.GroupVersionKind{
+	Group:   "policy.open-cluster-management.io",
+	Version: "v1",
+	Kind:    "ConfigurationPolicy",
+}
+
+// LaunchPolicyTemplateDetailSyncer reports the latest results of the templates of the replicated policies, including
+// the messages and the related objects. The related objects are only available for the configuration policies
+// evaluated on the hub cluster itself, e.g. the policies of the local-cluster.
+func LaunchPolicyTemplateDetailSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	_, err := mgr.GetRESTMapper().RESTMapping(ConfigurationPolicyGVK.GroupKind(), ConfigurationPolicyGVK.Version)
+	if err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	emitter := NewPolicyTemplateDetailEmitter(mgr.GetClient(), mgr.GetAPIReader())
+	emitter.configPolicyInstalled = err == nil
+	return generic.LaunchGenericEventSyncer(
+		"status.policy_template_detail",
+		mgr,
+		nil,
+		producer,
+		statusconfig.GetComplianceDuration,
+		emitter,
+	)
+}
+
+var _ generic.Emitter = &policyTemplateDetailEmitter{}
+
+func NewPolicyTemplateDetailEmitter(c client.Client, reader client.Reader) *policyTemplateDetailEmitter {
+	return &policyTemplateDetailEmitter{
+		log:             ctrl.Log.WithName("policy-template-detail"),
+		runtimeClient:   c,
+		reader:          reader,
+		eventType:       enum.PolicyTemplateDetailType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+}
+
+type policyTemplateDetailEmitter struct {
+	log                   logr.Logger
+	runtimeClient         client.Client
+	reader                client.Reader
+	eventType             enum.EventType
+	configPolicyInstalled bool
+	currentVersion        *eventversion.Version
+	lastSentVersion       eventversion.Version
+	details               grc.PolicyTemplateDetailBundle
+}
+
+// the details are collected on each sync, not updated by the event controllers
+func (s *policyTemplateDetailEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *policyTemplateDetailEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *policyTemplateDetailEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e := cloudevents.NewEvent()
+	e.SetSource(statusconfig.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, s.details)
+	return &e, err
+}
+
+func (s *policyTemplateDetailEmitter) Topic() string { return "" }
+
+// ShouldSend sends the details once the agent is started, so the removed templates are cleaned up on the global hub,
+// then only when they're changed, e.g. by a new evaluation of the template
+func (s *policyTemplateDetailEmitter) ShouldSend() bool {
+	if statusconfig.GetAggregationLevel() != statusconfig.AggregationFull {
+		return false
+	}
+	details, err := s.collectDetails(context.Background())
+	if err != nil {
+		s.log.Error(err, "failed to collect the policy template details")
+		return false
+	}
+	if s.details == nil || !reflect.DeepEqual(details, s.details) {
+		s.details = details
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *policyTemplateDetailEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+func (s *policyTemplateDetailEmitter) collectDetails(ctx context.Context) (grc.PolicyTemplateDetailBundle, error) {
+	policies := &policiesv1.PolicyList{}
+	if err := s.runtimeClient.List(ctx, policies, client.HasLabels{constants.PolicyEventRootPolicyNameLabelKey}); err != nil {
+		return nil, err
+	}
+
+	relatedObjects := map[string][]grc.TemplateRelatedObject{}
+	if s.configPolicyInstalled {
+		configPolicies := &unstructured.UnstructuredList{}
+		configPolicies.SetGroupVersionKind(
+			ConfigurationPolicyGVK.GroupVersion().WithKind(ConfigurationPolicyGVK.Kind + "List"))
+		if err := s.reader.List(ctx, configPolicies); err != nil {
+			return nil, err
+		}
+		for _, configPolicy := range configPolicies.Items {
+			key := templateKey(configPolicy.GetNamespace(), configPolicy.GetKind(), configPolicy.GetName())
+			relatedObjects[key] = RelatedObjectsOf(configPolicy)
+		}
+	}
+
+	details := grc.PolicyTemplateDetailBundle{}
+	clusterIDs := map[string]string{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if len(policy.Status.Details) == 0 {
+			continue
+		}
+		rootPolicy, err := utils.GetRootPolicy(ctx, s.runtimeClient,
+			policy.Labels[constants.PolicyEventRootPolicyNameLabelKey])
+		if err != nil {
+			s.log.V(2).Info("skip the replicated policy without the root policy", "policy", policy.Name,
+				"error", err.Error())
+			continue
+		}
+		// the local policies are reported only if they're enabled
+		if !utils.HasAnnotation(rootPolicy, constants.OriginOwnerReferenceAnnotation) &&
+			statusconfig.GetEnableLocalPolicy() != statusconfig.EnableLocalPolicyTrue {
+			continue
+		}
+
+		clusterName := policy.Labels[constants.PolicyEventClusterNameLabelKey]
+		if _, found := clusterIDs[clusterName]; !found {
+			clusterID, err := utils.GetClusterId(ctx, s.runtimeClient, clusterName)
+			if err != nil {
+				s.log.V(2).Info("failed to get the cluster id", "cluster", clusterName, "error", err.Error())
+			}
+			clusterIDs[clusterName] = clusterID
+		}
+		details = append(details, TemplateDetailsOf(policy, extractPolicyIdentity(rootPolicy), rootPolicy,
+			clusterName, clusterIDs[clusterName], relatedObjects)...)
+	}
+	sort.Slice(details, func(i, j int) bool {
+		return templateKey(details[i].PolicyID, details[i].ClusterName, details[i].TemplateName) <
+			templateKey(details[j].PolicyID, details[j].ClusterName, details[j].TemplateName)
+	})
+	return details, nil
+}
+
+// TemplateDetailsOf returns the latest results of the templates of the replicated policy. The related objects are
+// keyed by the namespace, kind and name of the template evaluated on the cluster.
+func TemplateDetailsOf(policy *policiesv1.Policy, policyID string, rootPolicy *policiesv1.Policy,
+	clusterName, clusterID string, relatedObjects map[string][]grc.TemplateRelatedObject,
+) []grc.PolicyTemplateDetail {
+	// the status of the template doesn't contain the kind, which is defined in the spec
+	templateKinds := map[string]string{}
+	for _, template := range policy.Spec.PolicyTemplates {
+		if template == nil {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(template.ObjectDefinition.Raw); err == nil {
+			templateKinds[obj.GetName()] = obj.GetKind()
+		}
+	}
+
+	details := make([]grc.PolicyTemplateDetail, 0, len(policy.Status.Details))
+	for _, templateStatus := range policy.Status.Details {
+		if templateStatus == nil {
+			continue
+		}
+		detail := grc.PolicyTemplateDetail{
+			PolicyID:        policyID,
+			PolicyNamespace: rootPolicy.Namespace,
+			PolicyName:      rootPolicy.Name,
+			ClusterName:     clusterName,
+			ClusterID:       clusterID,
+			TemplateKind:    templateKinds[templateStatus.TemplateMeta.Name],
+			TemplateName:    templateStatus.TemplateMeta.Name,
+			Compliance:      string(templateStatus.ComplianceState),
+		}
+		if detail.Compliance == "" {
+			detail.Compliance = "Pending"
+		}
+		// the history is sorted from the newest
+		if len(templateStatus.History) > 0 {
+			detail.Message = truncateMessage(templateStatus.History[0].Message, grc.MaxTemplateMessageLength)
+			if !templateStatus.History[0].LastTimestamp.IsZero() {
+				lastTimestamp := templateStatus.History[0].LastTimestamp.Time
+				detail.LastTimestamp = &lastTimestamp
+			}
+		}
+		objects := relatedObjects[templateKey(policy.Namespace, detail.TemplateKind, detail.TemplateName)]
+		detail.TotalRelatedObjects = len(objects)
+		if len(objects) > grc.MaxTemplateRelatedObjects {
+			objects = objects[:grc.MaxTemplateRelatedObjects]
+		}
+		detail.RelatedObjects = objects
+		details = append(details, detail)
+	}
+	return details
+}
+
+// RelatedObjectsOf returns the related objects of the configuration policy, the non-compliant ones are sorted first
+func RelatedObjectsOf(configPolicy unstructured.Unstructured) []grc.TemplateRelatedObject {
+	items, _, _ := unstructured.NestedSlice(configPolicy.Object, "status", "relatedObjects")
+	objects := make([]grc.TemplateRelatedObject, 0, len(items))
+	for _, item := range items {
+		relatedObject, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		object := grc.TemplateRelatedObject{}
+		object.APIVersion, _, _ = unstructured.NestedString(relatedObject, "object", "apiVersion")
+		object.Kind, _, _ = unstructured.NestedString(relatedObject, "object", "kind")
+		object.Namespace, _, _ = unstructured.NestedString(relatedObject, "object", "metadata", "namespace")
+		object.Name, _, _ = unstructured.NestedString(relatedObject, "object", "metadata", "name")
+		object.Compliance, _, _ = unstructured.NestedString(relatedObject, "compliant")
+		reason, _, _ := unstructured.NestedString(relatedObject, "reason")
+		object.Reason = truncateMessage(reason, grc.MaxTemplateMessageLength)
+		objects = append(objects, object)
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		iCompliant := objects[i].Compliance == string(policiesv1.Compliant)
+		jCompliant := objects[j].Compliance == string(policiesv1.Compliant)
+		if iCompliant != jCompliant {
+			return !iCompliant
+		}
+		return templateKey(objects[i].Kind, objects[i].Namespace, objects[i].Name) <
+			templateKey(objects[j].Kind, objects[j].Namespace, objects[j].Name)
+	})
+	return objects
+}
+
+func templateKey(namespace, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, kind, name)
+}
+
+// truncateMessage cuts the message to the max bytes without breaking the last character
+func truncateMessage(message string, max int) string {
+	if len(message) <= max {
+		return message
+	}
+	return strings.ToValidUTF8(message[:max-3], "") + "..."
+}
@@ -0,0 +1,122 @@
+package policies
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+)
+
+func TestTemplateDetailsOf(t *testing.T) {
+	rootPolicy := &policiesv1.Policy{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "policy1", UID: "1234"}}
+	lastTimestamp := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	policy := &policiesv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "local-cluster", Name: "default.policy1"},
+		Spec: policiesv1.PolicySpec{PolicyTemplates: []*policiesv1.PolicyTemplate{
+			{ObjectDefinition: runtime.RawExtension{
+				Raw: []byte(`{"kind":"ConfigurationPolicy","metadata":{"name":"namespace-template"}}`),
+			}},
+			{ObjectDefinition: runtime.RawExtension{
+				Raw: []byte(`{"kind":"CertificatePolicy","metadata":{"name":"certificate-template"}}`),
+			}},
+		}},
+		Status: policiesv1.PolicyStatus{Details: []*policiesv1.DetailsPerTemplate{
+			{
+				TemplateMeta:    metav1.ObjectMeta{Name: "namespace-template"},
+				ComplianceState: policiesv1.NonCompliant,
+				History: []policiesv1.ComplianceHistory{
+					{LastTimestamp: lastTimestamp, Message: "NonCompliant; " + strings.Repeat("x", 2000)},
+					{Message: "Compliant; notification - namespaces [test] found as specified"},
+				},
+			},
+			{TemplateMeta: metav1.ObjectMeta{Name: "certificate-template"}},
+		}},
+	}
+
+	related := []grc.TemplateRelatedObject{}
+	for i := 0; i < grc.MaxTemplateRelatedObjects+2; i++ {
+		related = append(related, grc.TemplateRelatedObject{Kind: "Namespace", Name: fmt.Sprintf("ns%d", i)})
+	}
+	relatedObjects := map[string][]grc.TemplateRelatedObject{
+		templateKey("local-cluster", "ConfigurationPolicy", "namespace-template"): related,
+	}
+
+	details := TemplateDetailsOf(policy, "1234", rootPolicy, "local-cluster", "5678", relatedObjects)
+	if len(details) != 2 {
+		t.Fatalf("expect 2 template details, but got %d", len(details))
+	}
+
+	detail := details[0]
+	if detail.PolicyID != "1234" || detail.PolicyName != "policy1" || detail.ClusterID != "5678" ||
+		detail.TemplateKind != "ConfigurationPolicy" || detail.Compliance != "NonCompliant" {
+		t.Fatalf("unexpected template detail: %+v", detail)
+	}
+	// the latest message is kept with the bounded length
+	if len(detail.Message) != grc.MaxTemplateMessageLength || !strings.HasPrefix(detail.Message, "NonCompliant; ") {
+		t.Fatalf("the message should be truncated to %d, but got %d", grc.MaxTemplateMessageLength,
+			len(detail.Message))
+	}
+	if detail.LastTimestamp == nil || !detail.LastTimestamp.Equal(lastTimestamp.Time) {
+		t.Fatalf("unexpected last timestamp: %v", detail.LastTimestamp)
+	}
+	if detail.TotalRelatedObjects != grc.MaxTemplateRelatedObjects+2 ||
+		len(detail.RelatedObjects) != grc.MaxTemplateRelatedObjects {
+		t.Fatalf("the related objects should be limited to %d of %d, but got %d of %d",
+			grc.MaxTemplateRelatedObjects, grc.MaxTemplateRelatedObjects+2, len(detail.RelatedObjects),
+			detail.TotalRelatedObjects)
+	}
+
+	// the template without the history is pending
+	detail = details[1]
+	if detail.TemplateKind != "CertificatePolicy" || detail.Compliance != "Pending" || detail.Message != "" ||
+		detail.LastTimestamp != nil || len(detail.RelatedObjects) != 0 {
+		t.Fatalf("unexpected template detail: %+v", detail)
+	}
+}
+
+func TestRelatedObjectsOf(t *testing.T) {
+	configPolicy := unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"relatedObjects": []interface{}{
+				map[string]interface{}{
+					"compliant": "Compliant",
+					"reason":    "Resource found as expected",
+					"object": map[string]interface{}{
+						"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": "a"},
+					},
+				},
+				map[string]interface{}{
+					"compliant": "NonCompliant",
+					"reason":    "Resource not found but should exist",
+					"object": map[string]interface{}{
+						"apiVersion": "v1", "kind": "ConfigMap",
+						"metadata": map[string]interface{}{"namespace": "b", "name": "c"},
+					},
+				},
+			},
+		},
+	}}
+
+	objects := RelatedObjectsOf(configPolicy)
+	if len(objects) != 2 {
+		t.Fatalf("expect 2 related objects, but got %d", len(objects))
+	}
+	// the non-compliant objects are sorted first
+	expected := grc.TemplateRelatedObject{
+		APIVersion: "v1", Kind: "ConfigMap", Namespace: "b", Name: "c", Compliance: "NonCompliant",
+		Reason: "Resource not found but should exist",
+	}
+	if objects[0] != expected {
+		t.Fatalf("unexpected related object: %+v", objects[0])
+	}
+	if objects[1].Name != "a" || objects[1].Compliance != "Compliant" {
+		t.Fatalf("unexpected related object: %+v", objects[1])
+	}
+}
@@ -0,0 +1,229 @@
+package policyreport
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	PolicyReportGVK = schema.GroupVersionKind{
+		Group:   "wgpolicyk8s.io",
+		Version: "v1alpha2",
+		Kind:    "PolicyReport",
+	}
+	ClusterPolicyReportGVK = schema.GroupVersionKind{
+		Group:   "wgpolicyk8s.io",
+		Version: "v1alpha2",
+		Kind:    "ClusterPolicyReport",
+	}
+)
+
+// PolicyReportInstalled returns true if the policy report crd is installed on the cluster, e.g. by kyverno
+func PolicyReportInstalled(mgr ctrl.Manager) (bool, error) {
+	_, err := mgr.GetRESTMapper().RESTMapping(PolicyReportGVK.GroupKind(), PolicyReportGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// LaunchPolicyReportSyncer reports the compliance of the kyverno policies on the cluster, which is collected from the
+// PolicyReports and ClusterPolicyReports
+func LaunchPolicyReportSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	return generic.LaunchGenericEventSyncer(
+		"status.policy_report",
+		mgr,
+		nil,
+		producer,
+		config.GetComplianceDuration,
+		NewPolicyReportEmitter(mgr.GetAPIReader()),
+	)
+}
+
+var _ generic.Emitter = &policyReportEmitter{}
+
+func NewPolicyReportEmitter(reader client.Reader) *policyReportEmitter {
+	return &policyReportEmitter{
+		log:             ctrl.Log.WithName("policy-report"),
+		reader:          reader,
+		eventType:       enum.PolicyReportType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+}
+
+type policyReportEmitter struct {
+	log             logr.Logger
+	reader          client.Reader
+	eventType       enum.EventType
+	currentVersion  *eventversion.Version
+	lastSentVersion eventversion.Version
+	compliances     grc.PolicyReportBundle
+}
+
+// the reports are listed from the cache on each sync, not updated by the event controllers
+func (s *policyReportEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *policyReportEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *policyReportEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, s.compliances)
+	return &e, err
+}
+
+func (s *policyReportEmitter) Topic() string { return "" }
+
+// ShouldSend sends the compliances once the agent is started, so the removed policies are cleaned up on the global
+// hub, then only when they're changed
+func (s *policyReportEmitter) ShouldSend() bool {
+	compliances, err := s.collectCompliances(context.Background())
+	if err != nil {
+		s.log.Error(err, "failed to list the policy reports")
+		return false
+	}
+	if s.compliances == nil || !reflect.DeepEqual(compliances, s.compliances) {
+		s.compliances = compliances
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *policyReportEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+// collectCompliances lists the reports from the apiserver page by page, and aggregates their results into the
+// compliances, rather than caching all the reports of the cluster in the informer
+func (s *policyReportEmitter) collectCompliances(ctx context.Context) (grc.PolicyReportBundle, error) {
+	aggregator := newPolicyReportAggregator(config.GetLeafHubName())
+	for _, gvk := range []schema.GroupVersionKind{PolicyReportGVK, ClusterPolicyReportGVK} {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		err := generic.PagedList(ctx, s.reader, list, func(object runtime.Object) error {
+			aggregator.add(object.(*unstructured.Unstructured))
+			return nil
+		})
+		if meta.IsNoMatchError(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return aggregator.compliances(), nil
+}
+
+// NormalizePolicyReports aggregates the results of the kyverno policies in the reports, the policy is non compliant if
+// any of its results is failed, compliant if it's passed, otherwise it's unknown, e.g. all the results are skipped.
+func NormalizePolicyReports(clusterName string, reports []unstructured.Unstructured) grc.PolicyReportBundle {
+	aggregator := newPolicyReportAggregator(clusterName)
+	for i := range reports {
+		aggregator.add(&reports[i])
+	}
+	return aggregator.compliances()
+}
+
+// policyReportAggregator accumulates the results of the reports one by one, so only the compliances are kept in memory
+type policyReportAggregator struct {
+	clusterName         string
+	compliancesByPolicy map[string]*grc.PolicyReportCompliance
+}
+
+func newPolicyReportAggregator(clusterName string) *policyReportAggregator {
+	return &policyReportAggregator{
+		clusterName:         clusterName,
+		compliancesByPolicy: map[string]*grc.PolicyReportCompliance{},
+	}
+}
+
+func (a *policyReportAggregator) add(report *unstructured.Unstructured) {
+	results, _, _ := unstructured.NestedSlice(report.Object, "results")
+	for _, item := range results {
+		result, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		source, _ := result["source"].(string)
+		policy, _ := result["policy"].(string)
+		if source != grc.PolicyReportSource || policy == "" {
+			continue
+		}
+
+		compliance, found := a.compliancesByPolicy[policy]
+		if !found {
+			// the result of the namespaced policy refers to it by namespace/name
+			namespace, name, isNamespaced := strings.Cut(policy, "/")
+			if !isNamespaced {
+				namespace, name = "", policy
+			}
+			compliance = &grc.PolicyReportCompliance{
+				PolicyID:    policyID(a.clusterName, policy),
+				Namespace:   namespace,
+				Name:        name,
+				ClusterName: a.clusterName,
+				Results:     map[string]int{},
+			}
+			a.compliancesByPolicy[policy] = compliance
+		}
+		if compliance.Category == "" {
+			compliance.Category, _ = result["category"].(string)
+		}
+		if compliance.Severity == "" {
+			compliance.Severity, _ = result["severity"].(string)
+		}
+		status, _ := result["result"].(string)
+		compliance.Results[status]++
+	}
+}
+
+func (a *policyReportAggregator) compliances() grc.PolicyReportBundle {
+	compliances := make(grc.PolicyReportBundle, 0, len(a.compliancesByPolicy))
+	for _, compliance := range a.compliancesByPolicy {
+		switch {
+		case compliance.Results["fail"]+compliance.Results["error"] > 0:
+			compliance.Compliance = grc.PolicyReportNonCompliant
+		case compliance.Results["pass"]+compliance.Results["warn"] > 0:
+			compliance.Compliance = grc.PolicyReportCompliant
+		default:
+			compliance.Compliance = grc.PolicyReportUnknown
+		}
+		compliances = append(compliances, *compliance)
+	}
+	sort.Slice(compliances, func(i, j int) bool {
+		if compliances[i].Namespace != compliances[j].Namespace {
+			return compliances[i].Namespace < compliances[j].Namespace
+		}
+		return compliances[i].Name < compliances[j].Name
+	})
+	return compliances
+}
+
+// policyID generates the stable id of the kyverno policy on the hub, the results don't contain the uid of the policy
+func policyID(clusterName, policy string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(grc.PolicyReportAPIVersion+"/"+clusterName+"/"+policy)).String()
+}
@@ -0,0 +1,83 @@
+package policyreport
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+)
+
+func newReport(kind string, results ...map[string]interface{}) unstructured.Unstructured {
+	items := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		items = append(items, result)
+	}
+	report := unstructured.Unstructured{Object: map[string]interface{}{"results": items}}
+	report.SetGroupVersionKind(PolicyReportGVK.GroupVersion().WithKind(kind))
+	return report
+}
+
+func newResult(policy, rule, result string) map[string]interface{} {
+	return map[string]interface{}{
+		"source":   grc.PolicyReportSource,
+		"policy":   policy,
+		"rule":     rule,
+		"result":   result,
+		"category": "Best Practices",
+		"severity": "medium",
+	}
+}
+
+func TestNormalizePolicyReports(t *testing.T) {
+	reports := []unstructured.Unstructured{
+		newReport("PolicyReport",
+			newResult("require-labels", "check-labels", "pass"),
+			newResult("require-labels", "check-labels", "fail"),
+			newResult("default/disallow-latest-tag", "check-tag", "pass"),
+			// the results of the other tools are ignored
+			map[string]interface{}{"source": "kube-bench", "policy": "cis", "result": "fail"},
+		),
+		newReport("ClusterPolicyReport",
+			newResult("require-labels", "check-labels", "pass"),
+			newResult("restrict-image-registries", "check-registry", "skip"),
+		),
+	}
+
+	compliances := NormalizePolicyReports("hub1", reports)
+	if len(compliances) != 3 {
+		t.Fatalf("expected 3 kyverno policies, but got %v", compliances)
+	}
+
+	expected := map[string]struct {
+		namespace  string
+		compliance string
+		results    int
+	}{
+		"require-labels":            {"", grc.PolicyReportNonCompliant, 3},
+		"restrict-image-registries": {"", grc.PolicyReportUnknown, 1},
+		"disallow-latest-tag":       {"default", grc.PolicyReportCompliant, 1},
+	}
+	for _, compliance := range compliances {
+		e, ok := expected[compliance.Name]
+		if !ok {
+			t.Fatalf("unexpected policy %s", compliance.Name)
+		}
+		results := 0
+		for _, count := range compliance.Results {
+			results += count
+		}
+		if compliance.Namespace != e.namespace || compliance.Compliance != e.compliance || results != e.results ||
+			compliance.ClusterName != "hub1" || compliance.Category != "Best Practices" {
+			t.Fatalf("unexpected compliance of the policy %s: %+v", compliance.Name, compliance)
+		}
+	}
+
+	// the policy id is stable across the reports
+	if again := NormalizePolicyReports("hub1", reports); again[0].PolicyID != compliances[0].PolicyID {
+		t.Fatalf("the policy id should be stable, but got %s and %s", compliances[0].PolicyID, again[0].PolicyID)
+	}
+	if other := NormalizePolicyReports("hub2", reports); other[0].PolicyID == compliances[0].PolicyID {
+		t.Fatal("the policy id should be unique across the hubs")
+	}
+}
@@ -0,0 +1,289 @@
+package security
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/config"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/controller/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	ComplianceScanGVK = schema.GroupVersionKind{
+		Group:   "compliance.openshift.io",
+		Version: "v1alpha1",
+		Kind:    "ComplianceScan",
+	}
+	ComplianceCheckResultGVK = schema.GroupVersionKind{
+		Group:   "compliance.openshift.io",
+		Version: "v1alpha1",
+		Kind:    "ComplianceCheckResult",
+	}
+	ImageManifestVulnGVK = schema.GroupVersionKind{
+		Group:   "secscan.quay.redhat.com",
+		Version: "v1alpha1",
+		Kind:    "ImageManifestVuln",
+	}
+)
+
+// the label of the check results referring to their scan
+const scanNameLabel = "compliance.openshift.io/scan-name"
+
+// the severities of the image vulnerabilities from the highest, Defcon1 is regarded as Critical
+var imageSeverities = []string{"Critical", "High", "Medium", "Low", "Negligible", "Unknown"}
+
+// LaunchSecurityPostureSyncer reports the security posture of the cluster, which is aggregated from the results of the
+// compliance operator scans and the image vulnerabilities found by the container security operator. The syncer is
+// skipped if neither of them is installed on the cluster.
+func LaunchSecurityPostureSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	log := ctrl.Log.WithName("status.security_posture")
+	complianceInstalled, err := installed(mgr, ComplianceScanGVK)
+	if err != nil {
+		return err
+	}
+	imageVulnInstalled, err := installed(mgr, ImageManifestVulnGVK)
+	if err != nil {
+		return err
+	}
+	if !complianceInstalled && !imageVulnInstalled {
+		log.Info("skip the security posture syncer, neither the compliance operator nor the container security " +
+			"operator is installed")
+		return nil
+	}
+
+	emitter := NewSecurityPostureEmitter(mgr.GetAPIReader())
+	emitter.complianceInstalled, emitter.imageVulnInstalled = complianceInstalled, imageVulnInstalled
+	return generic.LaunchGenericEventSyncer(
+		"status.security_posture",
+		mgr,
+		nil,
+		producer,
+		config.GetComplianceDuration,
+		emitter,
+	)
+}
+
+func installed(mgr ctrl.Manager, gvk schema.GroupVersionKind) (bool, error) {
+	_, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+var _ generic.Emitter = &securityPostureEmitter{}
+
+func NewSecurityPostureEmitter(reader client.Reader) *securityPostureEmitter {
+	return &securityPostureEmitter{
+		log:             ctrl.Log.WithName("security-posture"),
+		reader:          reader,
+		eventType:       enum.SecurityPostureType,
+		currentVersion:  eventversion.NewVersion(),
+		lastSentVersion: *eventversion.NewVersion(),
+	}
+}
+
+type securityPostureEmitter struct {
+	log                 logr.Logger
+	reader              client.Reader
+	eventType           enum.EventType
+	complianceInstalled bool
+	imageVulnInstalled  bool
+	currentVersion      *eventversion.Version
+	lastSentVersion     eventversion.Version
+	posture             *grc.SecurityPostureBundle
+}
+
+// the posture is collected on each sync, not updated by the event controllers
+func (s *securityPostureEmitter) ShouldUpdate(object client.Object) bool { return false }
+
+func (s *securityPostureEmitter) PostUpdate() {
+	s.currentVersion.Incr()
+}
+
+func (s *securityPostureEmitter) ToCloudEvent() (*cloudevents.Event, error) {
+	e := cloudevents.NewEvent()
+	e.SetSource(config.GetLeafHubName())
+	e.SetType(string(s.eventType))
+	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	err := e.SetData(cloudevents.ApplicationJSON, s.posture)
+	return &e, err
+}
+
+func (s *securityPostureEmitter) Topic() string { return "" }
+
+// ShouldSend sends the posture once the agent is started, so the removed scans are cleaned up on the global hub, then
+// only when it's changed, e.g. by a new scan
+func (s *securityPostureEmitter) ShouldSend() bool {
+	ctx := context.Background()
+	posture := &grc.SecurityPostureBundle{ClusterName: config.GetLeafHubName()}
+	if s.complianceInstalled {
+		scans, checkResults, err := s.listCompliance(ctx)
+		if err != nil {
+			s.log.Error(err, "failed to list the compliance scans")
+			return false
+		}
+		posture.ComplianceScans = SummarizeComplianceScans(scans, checkResults)
+	}
+	if s.imageVulnInstalled {
+		vulns := &unstructured.UnstructuredList{}
+		vulns.SetGroupVersionKind(ImageManifestVulnGVK.GroupVersion().WithKind(ImageManifestVulnGVK.Kind + "List"))
+		if err := s.reader.List(ctx, vulns); err != nil {
+			s.log.Error(err, "failed to list the image manifest vulnerabilities")
+			return false
+		}
+		posture.ImageVulnerabilities = SummarizeImageVulnerabilities(vulns.Items)
+	}
+
+	if s.posture == nil || !reflect.DeepEqual(posture, s.posture) {
+		s.posture = posture
+		s.PostUpdate()
+	}
+	return s.currentVersion.NewerThan(&s.lastSentVersion)
+}
+
+func (s *securityPostureEmitter) PostSend() {
+	s.currentVersion.Next()
+	s.lastSentVersion = *s.currentVersion
+}
+
+func (s *securityPostureEmitter) listCompliance(ctx context.Context) (
+	[]unstructured.Unstructured, []unstructured.Unstructured, error,
+) {
+	scans := &unstructured.UnstructuredList{}
+	scans.SetGroupVersionKind(ComplianceScanGVK.GroupVersion().WithKind(ComplianceScanGVK.Kind + "List"))
+	if err := s.reader.List(ctx, scans); err != nil {
+		return nil, nil, err
+	}
+	checkResults := &unstructured.UnstructuredList{}
+	checkResults.SetGroupVersionKind(
+		ComplianceCheckResultGVK.GroupVersion().WithKind(ComplianceCheckResultGVK.Kind + "List"))
+	if err := s.reader.List(ctx, checkResults); err != nil {
+		return nil, nil, err
+	}
+	return scans.Items, checkResults.Items, nil
+}
+
+// SummarizeComplianceScans counts the check results of each scan by the status, and the failed ones by the severity.
+func SummarizeComplianceScans(scans, checkResults []unstructured.Unstructured) []grc.ComplianceScanResult {
+	results := map[string]*grc.ComplianceScanResult{}
+	for _, scan := range scans {
+		result := &grc.ComplianceScanResult{Name: scan.GetName()}
+		result.Phase, _, _ = unstructured.NestedString(scan.Object, "status", "phase")
+		result.Result, _, _ = unstructured.NestedString(scan.Object, "status", "result")
+		timestamp, _, _ := unstructured.NestedString(scan.Object, "status", "endTimestamp")
+		if endTime, err := time.Parse(time.RFC3339, timestamp); err == nil {
+			result.EndTimestamp = &endTime
+		}
+		results[result.Name] = result
+	}
+
+	for _, checkResult := range checkResults {
+		// the check results of the removed scans are garbage collected
+		result, found := results[checkResult.GetLabels()[scanNameLabel]]
+		if !found {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(checkResult.Object, "status")
+		switch status {
+		case "PASS":
+			result.Passed++
+		case "MANUAL":
+			result.Manual++
+		case "FAIL":
+			result.Failed++
+			severity, _, _ := unstructured.NestedString(checkResult.Object, "severity")
+			switch strings.ToLower(severity) {
+			case "high":
+				result.FailedHigh++
+			case "medium":
+				result.FailedMedium++
+			case "low":
+				result.FailedLow++
+			}
+		default:
+			result.Others++
+		}
+	}
+
+	summaries := make([]grc.ComplianceScanResult, 0, len(results))
+	for _, result := range results {
+		summaries = append(summaries, *result)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// SummarizeImageVulnerabilities counts the vulnerabilities in the image manifests by the severity, and the images, the
+// fixable vulnerabilities and the affected pods by the highest severity of the images.
+func SummarizeImageVulnerabilities(vulns []unstructured.Unstructured) []grc.ImageVulnerabilitySummary {
+	summaries := map[string]*grc.ImageVulnerabilitySummary{}
+	summaryOf := func(severity string) *grc.ImageVulnerabilitySummary {
+		severity = normalizeImageSeverity(severity)
+		if _, found := summaries[severity]; !found {
+			summaries[severity] = &grc.ImageVulnerabilitySummary{Severity: severity}
+		}
+		return summaries[severity]
+	}
+
+	for _, vuln := range vulns {
+		for _, field := range []string{"defcon1", "critical", "high", "medium", "low", "negligible", "unknown"} {
+			count, _, _ := unstructured.NestedInt64(vuln.Object, "status", field+"Count")
+			if count > 0 {
+				summaryOf(field).Vulnerabilities += int(count)
+			}
+		}
+
+		highest, _, _ := unstructured.NestedString(vuln.Object, "status", "highestSeverity")
+		summary := summaryOf(highest)
+		summary.Images++
+		fixable, _, _ := unstructured.NestedInt64(vuln.Object, "status", "fixableCount")
+		summary.Fixable += int(fixable)
+		pods, _, _ := unstructured.NestedMap(vuln.Object, "status", "affectedPods")
+		for _, podList := range pods {
+			if list, ok := podList.([]interface{}); ok {
+				summary.AffectedPods += len(list)
+			}
+		}
+	}
+
+	results := []grc.ImageVulnerabilitySummary{}
+	for _, severity := range imageSeverities {
+		if summary, found := summaries[severity]; found {
+			results = append(results, *summary)
+		}
+	}
+	return results
+}
+
+func normalizeImageSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "defcon1", "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	case "negligible":
+		return "Negligible"
+	default:
+		return "Unknown"
+	}
+}
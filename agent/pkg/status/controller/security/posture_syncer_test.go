@@ -0,0 +1,103 @@
+package security
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newObject(kind, name string, labels map[string]string, fields map[string]interface{}) unstructured.Unstructured {
+	obj := unstructured.Unstructured{Object: fields}
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestSummarizeComplianceScans(t *testing.T) {
+	scans := []unstructured.Unstructured{
+		newObject("ComplianceScan", "ocp4-cis", nil, map[string]interface{}{
+			"status": map[string]interface{}{
+				"phase": "DONE", "result": "NON-COMPLIANT", "endTimestamp": "2024-01-01T00:00:00Z",
+			},
+		}),
+		// the scan which is still running
+		newObject("ComplianceScan", "ocp4-moderate", nil, map[string]interface{}{
+			"status": map[string]interface{}{"phase": "RUNNING"},
+		}),
+	}
+	checkResult := func(name, scan, status, severity string) unstructured.Unstructured {
+		return newObject("ComplianceCheckResult", name, map[string]string{scanNameLabel: scan},
+			map[string]interface{}{"status": status, "severity": severity})
+	}
+	checkResults := []unstructured.Unstructured{
+		checkResult("check1", "ocp4-cis", "PASS", "medium"),
+		checkResult("check2", "ocp4-cis", "FAIL", "high"),
+		checkResult("check3", "ocp4-cis", "FAIL", "medium"),
+		checkResult("check4", "ocp4-cis", "MANUAL", "medium"),
+		checkResult("check5", "ocp4-cis", "ERROR", "low"),
+		// the check result of the removed scan
+		checkResult("check6", "ocp4-e8", "FAIL", "high"),
+	}
+
+	results := SummarizeComplianceScans(scans, checkResults)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 scans, but got %v", results)
+	}
+	cis := results[0]
+	if cis.Name != "ocp4-cis" || cis.Phase != "DONE" || cis.Result != "NON-COMPLIANT" || cis.EndTimestamp == nil {
+		t.Errorf("unexpected scan: %v", cis)
+	}
+	if cis.Passed != 1 || cis.Failed != 2 || cis.Manual != 1 || cis.Others != 1 ||
+		cis.FailedHigh != 1 || cis.FailedMedium != 1 || cis.FailedLow != 0 {
+		t.Errorf("unexpected check results of the scan: %v", cis)
+	}
+	moderate := results[1]
+	if moderate.Name != "ocp4-moderate" || moderate.Phase != "RUNNING" || moderate.EndTimestamp != nil ||
+		moderate.Passed+moderate.Failed+moderate.Manual+moderate.Others != 0 {
+		t.Errorf("unexpected running scan: %v", moderate)
+	}
+}
+
+func TestSummarizeImageVulnerabilities(t *testing.T) {
+	vulns := []unstructured.Unstructured{
+		newObject("ImageManifestVuln", "sha256.1", nil, map[string]interface{}{
+			"status": map[string]interface{}{
+				"highestSeverity": "Defcon1",
+				"defcon1Count":    int64(1),
+				"highCount":       int64(2),
+				"fixableCount":    int64(3),
+				"affectedPods": map[string]interface{}{
+					"default/pod1": []interface{}{"container1"},
+					"default/pod2": []interface{}{"container1"},
+				},
+			},
+		}),
+		newObject("ImageManifestVuln", "sha256.2", nil, map[string]interface{}{
+			"status": map[string]interface{}{
+				"highestSeverity": "High",
+				"highCount":       int64(4),
+				"lowCount":        int64(1),
+				"affectedPods": map[string]interface{}{
+					"default/pod3": []interface{}{"container1"},
+				},
+			},
+		}),
+	}
+
+	results := SummarizeImageVulnerabilities(vulns)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 severities, but got %v", results)
+	}
+	critical, high, low := results[0], results[1], results[2]
+	if critical.Severity != "Critical" || critical.Images != 1 || critical.Vulnerabilities != 1 ||
+		critical.Fixable != 3 || critical.AffectedPods != 2 {
+		t.Errorf("unexpected critical vulnerabilities: %v", critical)
+	}
+	if high.Severity != "High" || high.Images != 1 || high.Vulnerabilities != 6 || high.AffectedPods != 1 {
+		t.Errorf("unexpected high vulnerabilities: %v", high)
+	}
+	if low.Severity != "Low" || low.Images != 0 || low.Vulnerabilities != 1 {
+		t.Errorf("unexpected low vulnerabilities: %v", low)
+	}
+}
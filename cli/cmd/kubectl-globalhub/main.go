@@ -0,0 +1,21 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stolostron/multicluster-global-hub/cli/pkg/commands"
+)
+
+// kubectl-globalhub is the kubectl plugin to troubleshoot the global hub, it's invoked by "kubectl globalhub"
+// once the binary is in the PATH
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	os.Exit(commands.Run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
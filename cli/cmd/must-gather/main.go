@@ -0,0 +1,21 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stolostron/multicluster-global-hub/cli/pkg/commands"
+)
+
+// must-gather runs the "kubectl globalhub gather" with the same flags, it's the entrypoint of the must-gather image,
+// e.g. "oc adm must-gather --image=<image> -- must-gather --dest /must-gather/global-hub.tar.gz"
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	os.Exit(commands.Run(ctx, append([]string{"gather"}, os.Args[1:]...), os.Stdout, os.Stderr))
+}
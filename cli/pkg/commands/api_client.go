@@ -0,0 +1,139 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	managerRouteName = "multicluster-global-hub-manager"
+	apiBasePath      = "/global-hub-api/v1"
+)
+
+// apiClient requests the global hub manager api with the bearer token, which is authenticated by the openshift
+// oauth of the global hub cluster
+type apiClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func newAPIClient(ctx context.Context, o *Options) (*apiClient, error) {
+	server, token := o.Server, o.Token
+	if server == "" {
+		kubeClient, err := o.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		route := &routev1.Route{}
+		err = kubeClient.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: managerRouteName}, route)
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("the route %s/%s isn't found, it's created with the global resource enabled, or "+
+				"specify the api by --server", o.Namespace, managerRouteName)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get the route of the manager: %w", err)
+		}
+		server = "https://" + route.Spec.Host
+	}
+	if token == "" {
+		restConfig, err := o.RestConfig()
+		if err != nil {
+			return nil, err
+		}
+		token = restConfig.BearerToken
+		if token == "" && restConfig.BearerTokenFile != "" {
+			data, err := os.ReadFile(filepath.Clean(restConfig.BearerTokenFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the token file: %w", err)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+		if token == "" {
+			return nil, fmt.Errorf("the api requires the bearer token, log in with \"oc login\" or specify --token")
+		}
+	}
+
+	return &apiClient{
+		baseURL: strings.TrimSuffix(server, "/") + apiBasePath,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: o.Timeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				// #nosec G402
+				TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: o.InsecureSkipTLSVerify},
+			},
+		},
+	}, nil
+}
+
+// get decodes the json response of the path into the out
+func (c *apiClient) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	body, err := c.do(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode the response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// post sends the body as json, the body is skipped if it's nil
+func (c *apiClient) post(ctx context.Context, path string, in interface{}) error {
+	var payload io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	_, err := c.do(ctx, http.MethodPost, c.baseURL+path, payload)
+	return err
+}
+
+func (c *apiClient) do(ctx context.Context, method, endpoint string, payload io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("%s %s: %s %s", method, strings.TrimPrefix(endpoint, c.baseURL), resp.Status,
+			strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
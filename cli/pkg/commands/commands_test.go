@@ -0,0 +1,264 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var testNow = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeAPI serves the manager api and records the resync requests
+func fakeAPI(t *testing.T, resyncs map[string][]string) *httptest.Server {
+	hubs := []managedHub{
+		{Name: "hub1", Status: "active", LastHeartbeat: testNow.Add(-30 * time.Second)},
+		{Name: "hub2", Status: "inactive", LastHeartbeat: testNow.Add(-10 * time.Minute)},
+	}
+	agents := []agent{
+		{
+			Name:         "hub1",
+			AgentVersion: "v1.2.0",
+			Collectors:   []string{"managedcluster", "policy"},
+			LastSyncTimes: map[string]time.Time{
+				"managedcluster": testNow.Add(-1 * time.Minute),
+				"policy":         testNow.Add(-20 * time.Minute),
+			},
+			ReportedAt: testNow.Add(-2 * time.Minute),
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(apiBasePath+"/managedhubs", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		filtered := []managedHub{}
+		for _, hub := range hubs {
+			if status := r.URL.Query().Get("status"); status == "" || status == hub.Status {
+				filtered = append(filtered, hub)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(filtered)
+	})
+	mux.HandleFunc(apiBasePath+"/agents", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(agents)
+	})
+	mux.HandleFunc(apiBasePath+"/managedhub/", func(w http.ResponseWriter, r *http.Request) {
+		hub := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, apiBasePath+"/managedhub/"), "/resync")
+		if r.Method != http.MethodPost || hub != "hub1" {
+			http.Error(w, "managed hub "+hub+" is not found", http.StatusNotFound)
+			return
+		}
+		request := &resyncRequest{}
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, request))
+		resyncs[hub] = request.EventTypes
+	})
+	return httptest.NewServer(mux)
+}
+
+func runCommand(server string, args ...string) (int, string, string) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	args = append(args, "--server", server, "--token", "test-token")
+	code := Run(context.Background(), args, out, errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestAPICommands(t *testing.T) {
+	now = func() time.Time { return testNow }
+	defer func() { now = time.Now }()
+
+	resyncs := map[string][]string{}
+	server := fakeAPI(t, resyncs)
+	defer server.Close()
+
+	code, out, errOut := runCommand(server.URL, "hubs")
+	require.Equal(t, 0, code, errOut)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"NAME", "STATUS", "LAST", "HEARTBEAT", "AGENT", "VERSION", "AGENT", "REPORTED"},
+		strings.Fields(lines[0]))
+	assert.Equal(t, []string{"hub1", "active", "30s", "v1.2.0", "2m"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"hub2", "inactive", "10m", noneValue, noneValue}, strings.Fields(lines[2]))
+
+	code, out, errOut = runCommand(server.URL, "hubs", "--status", "inactive")
+	require.Equal(t, 0, code, errOut)
+	assert.NotContains(t, out, "hub1")
+
+	code, out, errOut = runCommand(server.URL, "freshness", "--older-than", "10m")
+	require.Equal(t, 0, code, errOut)
+	lines = strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	fields := strings.Fields(lines[1])
+	assert.Equal(t, []string{"hub1", "policy"}, fields[:2])
+	assert.Equal(t, "20m", fields[3])
+
+	code, _, errOut = runCommand(server.URL, "freshness", "--hub", "hub2")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "the agent of the managed hub hub2 isn't found")
+
+	code, out, errOut = runCommand(server.URL, "resync", "hub1", "--event-types", "a,b")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "hub1")
+	assert.Equal(t, []string{"a", "b"}, resyncs["hub1"])
+
+	code, _, errOut = runCommand(server.URL, "resync", "hub3")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "404")
+
+	code, _, errOut = runCommand(server.URL, "resync")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "exactly one managed hub")
+}
+
+func TestRunUsage(t *testing.T) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 0, Run(context.Background(), nil, out, errOut))
+	for _, c := range newCommands() {
+		assert.Contains(t, out.String(), c.name)
+	}
+	assert.Equal(t, 1, Run(context.Background(), []string{"unknown"}, out, errOut))
+	assert.Contains(t, errOut.String(), "unknown command \"unknown\"")
+}
+
+func TestFreshnessRows(t *testing.T) {
+	now = func() time.Time { return testNow }
+	defer func() { now = time.Now }()
+
+	rows := freshnessRows(agent{
+		Collectors: []string{"policy", "event"},
+		LastSyncTimes: map[string]time.Time{
+			"policy":         testNow.Add(-5 * time.Minute),
+			"managedcluster": testNow.Add(-1 * time.Hour),
+		},
+	}, 0)
+	require.Len(t, rows, 3)
+	assert.Equal(t, freshnessRow{collector: "event", lastSync: "<never>", age: noneValue}, rows[0])
+	assert.Equal(t, "managedcluster", rows[1].collector)
+	assert.Equal(t, "60m", rows[1].age)
+	assert.Equal(t, "5m", rows[2].age)
+}
+
+func TestParseArgs(t *testing.T) {
+	args := parseArgs([]string{
+		"--kafka-bootstrap-server=kafka-kafka-bootstrap.multicluster-global-hub.svc:9092",
+		"--kafka-consumer-topic=^status.*",
+		"--lease-duration",
+		"-v=2",
+	})
+	assert.Equal(t, map[string]string{
+		"kafka-bootstrap-server": "kafka-kafka-bootstrap.multicluster-global-hub.svc:9092",
+		"kafka-consumer-topic":   "^status.*",
+		"v":                      "2",
+	}, args)
+	assert.Equal(t, "b", firstNonEmpty("", "b", "c"))
+}
+
+func TestComputeLag(t *testing.T) {
+	rows, total := computeLag(map[partitionKey]int64{
+		{topic: "status.hub1", partition: 0}: 90,
+		{topic: "event", partition: 1}:       5,
+		{topic: "event", partition: 0}:       -1001,
+	}, map[partitionKey]int64{
+		{topic: "status.hub1", partition: 0}: 100,
+		{topic: "event", partition: 1}:       5,
+		{topic: "event", partition: 0}:       12,
+	})
+	require.Len(t, rows, 3)
+	assert.Equal(t, partitionKey{topic: "event", partition: 0}, rows[0].partitionKey)
+	assert.Equal(t, int64(-1), rows[0].lag)
+	assert.Equal(t, int64(0), rows[1].lag)
+	assert.Equal(t, int64(10), rows[2].lag)
+	assert.Equal(t, int64(10), total)
+	assert.Equal(t, "-", offsetString(rows[0].lag))
+}
+
+func TestStartOffset(t *testing.T) {
+	assert.Equal(t, int64(100), startOffset(10, 100, &tailOptions{}))
+	assert.Equal(t, int64(10), startOffset(10, 100, &tailOptions{fromBeginning: true}))
+	assert.Equal(t, int64(95), startOffset(10, 100, &tailOptions{last: 5}))
+	assert.Equal(t, int64(10), startOffset(10, 100, &tailOptions{last: 500}))
+}
+
+func TestFormatEvent(t *testing.T) {
+	topic := "event"
+	tp := kafka.TopicPartition{Topic: &topic, Partition: 1, Offset: 7}
+	evt := cloudevents.New()
+	evt.SetID("123")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.event.managedcluster")
+	evt.SetSource("hub1")
+	evt.SetTime(testNow)
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, map[string]string{"name": "cluster1"}))
+
+	assert.True(t, matchEvent(&evt, &tailOptions{hub: "hub1", eventType: "managedcluster"}))
+	assert.False(t, matchEvent(&evt, &tailOptions{hub: "hub2"}))
+	assert.False(t, matchEvent(&evt, &tailOptions{eventType: "policy"}))
+
+	out := formatEvent(tp, &evt, false)
+	assert.Contains(t, out, "event[1]@7 type=io.open-cluster-management.operator.multiclusterglobalhubs.event."+
+		"managedcluster source=hub1 id=123\n")
+	assert.Contains(t, out, "\"name\": \"cluster1\"")
+	assert.NotContains(t, formatEvent(tp, &evt, true), "cluster1")
+
+	evt.SetExtension(transport.ChunkSizeKey, 100)
+	evt.SetExtension(transport.ChunkOffsetKey, 20)
+	out = formatEvent(tp, &evt, false)
+	assert.Contains(t, out, "chunk=20/100")
+	assert.Contains(t, out, "bytes>")
+}
+
+func TestGatherArchive(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:          "multicluster-global-hub-manager",
+		Namespace:     "multicluster-global-hub",
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "operator"}},
+	}}
+	kubeClient := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(deployment).Build()
+
+	buf := &bytes.Buffer{}
+	a := newArchive(buf, "must-gather")
+	for _, gvk := range namespacedResources {
+		gatherResources(context.Background(), kubeClient, a, gvk, "multicluster-global-hub")
+	}
+	a.recordError("kafka/offsets.txt", fmt.Errorf("kafka isn't reachable"))
+	require.NoError(t, a.close())
+
+	files := map[string]string{}
+	gzipReader, err := gzip.NewReader(buf)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tarReader)
+		require.NoError(t, err)
+		files[header.Name] = string(data)
+	}
+
+	// the kinds without the objects and the kinds that aren't installed are skipped
+	require.Len(t, files, 2)
+	deployments := files["must-gather/namespaces/multicluster-global-hub/apps/deployment.yaml"]
+	assert.Contains(t, deployments, "name: multicluster-global-hub-manager")
+	assert.NotContains(t, deployments, "managedFields")
+	assert.Equal(t, "kafka/offsets.txt: kafka isn't reachable\n", files["must-gather/errors.txt"])
+}
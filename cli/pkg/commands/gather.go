@@ -0,0 +1,328 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// namespacedResources are gathered from the namespaces of the global hub and the agent, the secrets are never gathered
+var namespacedResources = []schema.GroupVersionKind{
+	{Group: "operator.open-cluster-management.io", Version: "v1alpha4", Kind: "MulticlusterGlobalHub"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "ServiceAccount"},
+	{Version: "v1", Kind: "PersistentVolumeClaim"},
+	{Version: "v1", Kind: "Event"},
+	{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+	{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"},
+	// the kafka cluster, the topics and the users with their ACLs of the built-in kafka
+	{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "Kafka"},
+	{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaTopic"},
+	{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaUser"},
+}
+
+var managedClusterAddOnGVK = schema.GroupVersionKind{
+	Group: "addon.open-cluster-management.io", Version: "v1alpha1", Kind: "ManagedClusterAddOn",
+}
+
+type gatherOptions struct {
+	dest           string
+	since          time.Duration
+	agentNamespace string
+}
+
+func newGatherCommand() *command {
+	k := &kafkaOptions{}
+	g := &gatherOptions{}
+	fs := pflag.NewFlagSet("gather", pflag.ContinueOnError)
+	k.addFlags(fs)
+	fs.StringVar(&g.dest, "dest", "", "the archive to write, it's must-gather-<time>.tar.gz in the current "+
+		"directory if it's empty.")
+	fs.DurationVar(&g.since, "since", 6*time.Hour, "only gather the logs newer than the duration.")
+	fs.StringVar(&g.agentNamespace, "agent-namespace", constants.GHAgentNamespace,
+		"the namespace of the agent, it's gathered as well if it exists, e.g. on the managed hub.")
+	return &command{
+		name:        "gather",
+		usage:       "gather [--dest <file>] [--since <duration>] [--agent-namespace <namespace>]",
+		description: "Gather the global hub resources, the kafka state and the recent logs into an archive.",
+		flags:       fs,
+		run: func(ctx context.Context, o *Options, args []string) error {
+			dest := g.dest
+			if dest == "" {
+				dest = fmt.Sprintf("must-gather-%s.tar.gz", now().UTC().Format("20060102-150405"))
+			}
+			file, err := os.Create(filepath.Clean(dest))
+			if err != nil {
+				return fmt.Errorf("failed to create the archive: %w", err)
+			}
+			defer file.Close()
+
+			a := newArchive(file, strings.TrimSuffix(filepath.Base(dest), ".tar.gz"))
+			gather(ctx, o, k, g, a)
+			if err := a.close(); err != nil {
+				return fmt.Errorf("failed to write the archive: %w", err)
+			}
+			fmt.Fprintf(o.Out, "the global hub is gathered into %s\n", dest)
+			if len(a.errors) > 0 {
+				fmt.Fprintf(o.ErrOut, "%d items aren't gathered, see errors.txt in the archive\n", len(a.errors))
+			}
+			return nil
+		},
+	}
+}
+
+// gather writes everything it can into the archive, the failures are recorded in the errors.txt rather than
+// stopping the gathering, e.g. the kafka isn't reachable out of the cluster
+func gather(ctx context.Context, o *Options, k *kafkaOptions, g *gatherOptions, a *archive) {
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		a.recordError("client", err)
+		return
+	}
+	restConfig, err := o.RestConfig()
+	if err != nil {
+		a.recordError("client", err)
+		return
+	}
+	kubeClientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		a.recordError("client", err)
+		return
+	}
+
+	for _, namespace := range []string{o.Namespace, g.agentNamespace} {
+		ns := &corev1.Namespace{}
+		if err := kubeClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			a.recordError("namespaces/"+namespace, err)
+			continue
+		}
+		for _, gvk := range namespacedResources {
+			gatherResources(ctx, kubeClient, a, gvk, namespace)
+		}
+		gatherLogs(ctx, kubeClientset, a, namespace, g.since)
+	}
+	gatherManagedClusterAddOns(ctx, kubeClient, a)
+	gatherOffsets(ctx, o, k, a)
+}
+
+// gatherResources writes the resources of the kind in the namespace into namespaces/<namespace>/<group>/<kind>.yaml,
+// the kind that isn't installed on the cluster is skipped, e.g. the route on the kubernetes
+func gatherResources(ctx context.Context, kubeClient client.Client, a *archive, gvk schema.GroupVersionKind,
+	namespace string,
+) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	name := path.Join("namespaces", namespace, resourceDir(gvk), strings.ToLower(gvk.Kind)+".yaml")
+	if err := kubeClient.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		if !meta.IsNoMatchError(err) {
+			a.recordError(name, err)
+		}
+		return
+	}
+	if len(list.Items) == 0 {
+		return
+	}
+	a.addObjects(name, list)
+}
+
+// gatherManagedClusterAddOns writes the global hub addons of the managed hubs, which report the status of the agents
+func gatherManagedClusterAddOns(ctx context.Context, kubeClient client.Client, a *archive) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(managedClusterAddOnGVK.GroupVersion().WithKind(managedClusterAddOnGVK.Kind + "List"))
+	name := path.Join("cluster-scoped-resources", resourceDir(managedClusterAddOnGVK), "managedclusteraddon.yaml")
+	if err := kubeClient.List(ctx, list); err != nil {
+		if !meta.IsNoMatchError(err) {
+			a.recordError(name, err)
+		}
+		return
+	}
+	addons := list.Items[:0]
+	for _, addon := range list.Items {
+		if addon.GetName() == constants.GHManagedClusterAddonName {
+			addons = append(addons, addon)
+		}
+	}
+	if len(addons) == 0 {
+		return
+	}
+	list.Items = addons
+	a.addObjects(name, list)
+}
+
+// gatherLogs writes the logs of the containers in the namespace into namespaces/<namespace>/logs/<pod>/<container>.log,
+// the logs before the last restart are gathered as well
+func gatherLogs(ctx context.Context, kubeClientset kubernetes.Interface, a *archive, namespace string,
+	since time.Duration,
+) {
+	pods, err := kubeClientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		a.recordError(path.Join("namespaces", namespace, "logs"), err)
+		return
+	}
+	sinceSeconds := int64(since.Seconds())
+	for _, pod := range pods.Items {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			logOptions := []*corev1.PodLogOptions{{Container: status.Name, SinceSeconds: &sinceSeconds}}
+			if status.RestartCount > 0 {
+				logOptions = append(logOptions, &corev1.PodLogOptions{Container: status.Name, Previous: true})
+			}
+			for _, opts := range logOptions {
+				name := status.Name + ".log"
+				if opts.Previous {
+					name = status.Name + ".previous.log"
+				}
+				name = path.Join("namespaces", namespace, "logs", pod.Name, name)
+				logs, err := kubeClientset.CoreV1().Pods(namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+				if err != nil {
+					a.recordError(name, err)
+					continue
+				}
+				a.add(name, logs)
+			}
+		}
+	}
+}
+
+// gatherOffsets writes the committed offsets and the lag of the manager's consumer group into kafka/offsets.txt
+func gatherOffsets(ctx context.Context, o *Options, k *kafkaOptions, a *archive) {
+	const name = "kafka/offsets.txt"
+	m, err := loadManagerKafka(ctx, o, k)
+	if err != nil {
+		a.recordError(name, err)
+		return
+	}
+	defer m.cleanup()
+	configMap, err := m.configMap()
+	if err != nil {
+		a.recordError(name, err)
+		return
+	}
+	admin, err := kafka.NewAdminClient(configMap)
+	if err != nil {
+		a.recordError(name, err)
+		return
+	}
+	defer admin.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+	group := m.config.ConsumerConfig.ConsumerID
+	committed, err := committedOffsets(ctx, admin, group)
+	if err != nil {
+		a.recordError(name, err)
+		return
+	}
+	ends, err := endOffsets(ctx, admin, committed)
+	if err != nil {
+		a.recordError(name, err)
+		return
+	}
+	rows, total := computeLag(committed, ends)
+	out := &bytes.Buffer{}
+	if err := printLag(out, group, rows, total); err != nil {
+		a.recordError(name, err)
+		return
+	}
+	a.add(name, out.Bytes())
+}
+
+// resourceDir is the directory of the kind in the archive, it's "core" for the kinds of the core group
+func resourceDir(gvk schema.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return "core"
+	}
+	return gvk.Group
+}
+
+// archive writes the gathered files into a gzipped tarball under the root directory, the errors are written into
+// the errors.txt once it's closed
+type archive struct {
+	root   string
+	gzip   *gzip.Writer
+	tar    *tar.Writer
+	errors []string
+	// err is the first failure of writing the archive, the following files are skipped once it's set
+	err error
+}
+
+func newArchive(w io.Writer, root string) *archive {
+	gzipWriter := gzip.NewWriter(w)
+	return &archive{root: root, gzip: gzipWriter, tar: tar.NewWriter(gzipWriter)}
+}
+
+func (a *archive) add(name string, data []byte) {
+	if a.err != nil {
+		return
+	}
+	if a.err = a.tar.WriteHeader(&tar.Header{
+		Name:    path.Join(a.root, name),
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: now(),
+	}); a.err != nil {
+		return
+	}
+	_, a.err = a.tar.Write(data)
+}
+
+// addObjects writes the objects of the list as a yaml list without the managed fields
+func (a *archive) addObjects(name string, list *unstructured.UnstructuredList) {
+	for i := range list.Items {
+		list.Items[i].SetManagedFields(nil)
+	}
+	data, err := yaml.Marshal(list.UnstructuredContent())
+	if err != nil {
+		a.recordError(name, err)
+		return
+	}
+	a.add(name, data)
+}
+
+func (a *archive) recordError(name string, err error) {
+	a.errors = append(a.errors, fmt.Sprintf("%s: %v", name, err))
+}
+
+func (a *archive) close() error {
+	if len(a.errors) > 0 {
+		a.add("errors.txt", []byte(strings.Join(a.errors, "\n")+"\n"))
+	}
+	if a.err != nil {
+		return a.err
+	}
+	if err := a.tar.Close(); err != nil {
+		return err
+	}
+	return a.gzip.Close()
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// managedHub and agent are the responses of the manager api, they're copied here since the cli mustn't import the
+// manager
+type managedHub struct {
+	Name          string    `json:"name"`
+	Status        string    `json:"status"`
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+}
+
+type agent struct {
+	Name           string               `json:"name"`
+	AgentVersion   string               `json:"agentVersion"`
+	Collectors     []string             `json:"collectors"`
+	LastSyncTimes  map[string]time.Time `json:"lastSyncTimes,omitempty"`
+	ResourceCounts map[string]int       `json:"resourceCounts,omitempty"`
+	ReportedAt     time.Time            `json:"reportedAt"`
+}
+
+const noneValue = "<none>"
+
+// now is replaced by the tests to get the stable ages
+var now = time.Now
+
+func newHubsCommand() *command {
+	var status string
+	fs := pflag.NewFlagSet("hubs", pflag.ContinueOnError)
+	fs.StringVar(&status, "status", "", "filter the hubs by the status, active, inactive or detached.")
+	return &command{
+		name:        "hubs",
+		usage:       "hubs [--status active|inactive|detached]",
+		description: "Show the heartbeat of the managed hubs and the version of their agents.",
+		flags:       fs,
+		run: func(ctx context.Context, o *Options, args []string) error {
+			client, err := newAPIClient(ctx, o)
+			if err != nil {
+				return err
+			}
+			query := url.Values{}
+			if status != "" {
+				query.Set("status", status)
+			}
+			hubs := []managedHub{}
+			if err := client.get(ctx, "/managedhubs", query, &hubs); err != nil {
+				return err
+			}
+			agents, err := listAgents(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSTATUS\tLAST HEARTBEAT\tAGENT VERSION\tAGENT REPORTED")
+			for _, hub := range hubs {
+				version, reported := noneValue, noneValue
+				if a, ok := agents[hub.Name]; ok {
+					if a.AgentVersion != "" {
+						version = a.AgentVersion
+					}
+					reported = age(a.ReportedAt)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", hub.Name, hub.Status, age(hub.LastHeartbeat), version, reported)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newFreshnessCommand() *command {
+	var hub string
+	var olderThan time.Duration
+	fs := pflag.NewFlagSet("freshness", pflag.ContinueOnError)
+	fs.StringVar(&hub, "hub", "", "only show the collectors of the managed hub.")
+	fs.DurationVar(&olderThan, "older-than", 0, "only show the collectors which haven't synced within the duration, "+
+		"e.g. 10m.")
+	return &command{
+		name:        "freshness",
+		usage:       "freshness [--hub <name>] [--older-than <duration>]",
+		description: "Show when the status of each collector was last synced by the managed hubs.",
+		flags:       fs,
+		run: func(ctx context.Context, o *Options, args []string) error {
+			client, err := newAPIClient(ctx, o)
+			if err != nil {
+				return err
+			}
+			agents, err := listAgents(ctx, client)
+			if err != nil {
+				return err
+			}
+			if hub != "" {
+				if _, ok := agents[hub]; !ok {
+					return fmt.Errorf("the agent of the managed hub %s isn't found", hub)
+				}
+			}
+
+			names := make([]string, 0, len(agents))
+			for name := range agents {
+				if hub == "" || name == hub {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "HUB\tCOLLECTOR\tLAST SYNC\tAGE")
+			for _, name := range names {
+				for _, row := range freshnessRows(agents[name], olderThan) {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, row.collector, row.lastSync, row.age)
+				}
+			}
+			return w.Flush()
+		},
+	}
+}
+
+type freshnessRow struct {
+	collector string
+	lastSync  string
+	age       string
+}
+
+// freshnessRows lists the collectors of the agent, the collector is skipped if it has synced within the olderThan
+func freshnessRows(a agent, olderThan time.Duration) []freshnessRow {
+	collectors := append([]string{}, a.Collectors...)
+	// the collectors which aren't reported as enabled might still have synced before
+	for collector := range a.LastSyncTimes {
+		found := false
+		for _, c := range collectors {
+			if c == collector {
+				found = true
+				break
+			}
+		}
+		if !found {
+			collectors = append(collectors, collector)
+		}
+	}
+	sort.Strings(collectors)
+
+	rows := []freshnessRow{}
+	for _, collector := range collectors {
+		lastSync, ok := a.LastSyncTimes[collector]
+		if !ok || lastSync.IsZero() {
+			rows = append(rows, freshnessRow{collector: collector, lastSync: "<never>", age: noneValue})
+			continue
+		}
+		if olderThan > 0 && now().Sub(lastSync) < olderThan {
+			continue
+		}
+		rows = append(rows, freshnessRow{
+			collector: collector,
+			lastSync:  lastSync.Local().Format(time.RFC3339),
+			age:       age(lastSync),
+		})
+	}
+	return rows
+}
+
+func listAgents(ctx context.Context, client *apiClient) (map[string]agent, error) {
+	agents := []agent{}
+	if err := client.get(ctx, "/agents", nil, &agents); err != nil {
+		return nil, err
+	}
+	agentMap := make(map[string]agent, len(agents))
+	for _, a := range agents {
+		agentMap[a.Name] = a
+	}
+	return agentMap, nil
+}
+
+// age returns the elapsed time in the format of kubectl, e.g. 5m10s
+func age(t time.Time) string {
+	if t.IsZero() {
+		return noneValue
+	}
+	return duration.HumanDuration(now().Sub(t))
+}
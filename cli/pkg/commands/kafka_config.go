@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/pflag"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+const (
+	managerDeploymentName = "multicluster-global-hub-manager"
+	// defaultManagerConsumerID is the default value of the --kafka-consumer-id flag of the manager
+	defaultManagerConsumerID = "multicluster-global-hub-manager"
+)
+
+// kafkaOptions are the flags of the commands connecting to the kafka cluster of the global hub
+type kafkaOptions struct {
+	bootstrapServer string
+	group           string
+}
+
+func (k *kafkaOptions) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&k.bootstrapServer, "bootstrap-server", "", "the kafka bootstrap server, it's read from the manager "+
+		"if it's empty. Use it with the port-forward if the server isn't reachable out of the cluster.")
+	fs.StringVar(&k.group, "group", "", "the consumer group of the manager, it's read from the manager if it's empty.")
+}
+
+// managerKafka is the kafka connection of the manager, which is read from the args of the manager deployment
+type managerKafka struct {
+	config      *transport.KafkaConfig
+	statusTopic string
+	eventTopic  string
+	// certDir keeps the certificates from the kafka-certs-secret, it's removed by the cleanup
+	certDir string
+}
+
+func (m *managerKafka) cleanup() {
+	if m.certDir != "" {
+		_ = os.RemoveAll(m.certDir)
+	}
+}
+
+// topics returns the topics consumed by the manager, the status topic might be a regex, e.g. ^status.*
+func (m *managerKafka) topics() []string {
+	return []string{m.eventTopic, m.statusTopic}
+}
+
+// configMap returns the confluent config of a consumer which never commits the offsets of the group
+func (m *managerKafka) configMap() (*kafka.ConfigMap, error) {
+	configMap, err := config.GetConfluentConfigMap(m.config, false)
+	if err != nil {
+		return nil, err
+	}
+	_ = configMap.SetKey("enable.auto.commit", "false")
+	_ = configMap.SetKey("enable.auto.offset.store", "false")
+	_ = configMap.SetKey("client.id", "kubectl-globalhub")
+	return configMap, nil
+}
+
+func loadManagerKafka(ctx context.Context, o *Options, k *kafkaOptions) (*managerKafka, error) {
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return nil, err
+	}
+	deployment := &appsv1.Deployment{}
+	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: managerDeploymentName},
+		deployment); err != nil {
+		return nil, fmt.Errorf("failed to get the manager deployment: %w", err)
+	}
+	var args map[string]string
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == managerDeploymentName {
+			args = parseArgs(container.Args)
+		}
+	}
+	if args == nil {
+		return nil, fmt.Errorf("the container %s isn't found in the manager deployment", managerDeploymentName)
+	}
+
+	m := &managerKafka{
+		config: &transport.KafkaConfig{
+			BootstrapServer: firstNonEmpty(k.bootstrapServer, args["kafka-bootstrap-server"]),
+			EnableTLS:       true,
+			ConsumerConfig: &transport.KafkaConsumerConfig{
+				ConsumerID: firstNonEmpty(k.group, args["kafka-consumer-id"], defaultManagerConsumerID),
+			},
+		},
+		statusTopic: firstNonEmpty(args["kafka-consumer-topic"], "status"),
+		eventTopic:  firstNonEmpty(args["kafka-event-topic"], "event"),
+	}
+	if m.config.BootstrapServer == "" {
+		return nil, fmt.Errorf("the kafka bootstrap server isn't found in the manager, specify it by --bootstrap-server")
+	}
+
+	secret := &corev1.Secret{}
+	if err := kubeClient.Get(ctx, types.NamespacedName{Namespace: o.Namespace, Name: constants.KafkaCertSecretName},
+		secret); err != nil {
+		return nil, fmt.Errorf("failed to get the kafka certificates: %w", err)
+	}
+	m.certDir, err = os.MkdirTemp("", "kubectl-globalhub-")
+	if err != nil {
+		return nil, err
+	}
+	for key, path := range map[string]*string{
+		"ca.crt":     &m.config.CaCertPath,
+		"client.crt": &m.config.ClientCertPath,
+		"client.key": &m.config.ClientKeyPath,
+	} {
+		*path = filepath.Join(m.certDir, key)
+		if err := os.WriteFile(*path, secret.Data[key], 0o600); err != nil {
+			m.cleanup()
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// parseArgs returns the values of the "--name=value" args, the args without the value are skipped
+func parseArgs(args []string) map[string]string {
+	values := map[string]string{}
+	for _, arg := range args {
+		name, value, found := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if found {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/pflag"
+)
+
+func newLagCommand() *command {
+	k := &kafkaOptions{}
+	fs := pflag.NewFlagSet("lag", pflag.ContinueOnError)
+	k.addFlags(fs)
+	return &command{
+		name:        "lag",
+		usage:       "lag [--group <group>] [--bootstrap-server <host:port>]",
+		description: "Show the consumer lag of the manager on the status and event topics.",
+		flags:       fs,
+		run: func(ctx context.Context, o *Options, args []string) error {
+			m, err := loadManagerKafka(ctx, o, k)
+			if err != nil {
+				return err
+			}
+			defer m.cleanup()
+
+			configMap, err := m.configMap()
+			if err != nil {
+				return err
+			}
+			admin, err := kafka.NewAdminClient(configMap)
+			if err != nil {
+				return fmt.Errorf("failed to create the kafka admin client: %w", err)
+			}
+			defer admin.Close()
+
+			ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+			defer cancel()
+			group := m.config.ConsumerConfig.ConsumerID
+			committed, err := committedOffsets(ctx, admin, group)
+			if err != nil {
+				return err
+			}
+			if len(committed) == 0 {
+				fmt.Fprintf(o.Out, "the consumer group %s hasn't committed any offsets\n", group)
+				return nil
+			}
+			ends, err := endOffsets(ctx, admin, committed)
+			if err != nil {
+				return err
+			}
+
+			rows, total := computeLag(committed, ends)
+			return printLag(o.Out, group, rows, total)
+		},
+	}
+}
+
+func printLag(out io.Writer, group string, rows []lagRow, total int64) error {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "GROUP: %s\n", group)
+	fmt.Fprintln(w, "TOPIC\tPARTITION\tCOMMITTED\tEND\tLAG")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\n", row.topic, row.partition, offsetString(row.committed), row.end,
+			offsetString(row.lag))
+	}
+	fmt.Fprintf(w, "TOTAL LAG: %d\n", total)
+	return w.Flush()
+}
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type lagRow struct {
+	partitionKey
+	committed int64
+	end       int64
+	// lag is -1 if the partition hasn't been committed by the group
+	lag int64
+}
+
+// computeLag returns the lag of each partition ordered by the topic and partition, and the total lag of the committed
+// partitions
+func computeLag(committed, ends map[partitionKey]int64) ([]lagRow, int64) {
+	rows := make([]lagRow, 0, len(committed))
+	total := int64(0)
+	for key, offset := range committed {
+		row := lagRow{partitionKey: key, committed: offset, end: ends[key], lag: -1}
+		if offset >= 0 {
+			row.lag = row.end - offset
+			if row.lag < 0 {
+				row.lag = 0
+			}
+			total += row.lag
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].topic != rows[j].topic {
+			return rows[i].topic < rows[j].topic
+		}
+		return rows[i].partition < rows[j].partition
+	})
+	return rows, total
+}
+
+func committedOffsets(ctx context.Context, admin *kafka.AdminClient, group string) (map[partitionKey]int64, error) {
+	// the nil partitions list the offsets of all the partitions committed by the group
+	result, err := admin.ListConsumerGroupOffsets(ctx, []kafka.ConsumerGroupTopicPartitions{{Group: group}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the offsets of the consumer group %s: %w", group, err)
+	}
+	offsets := map[partitionKey]int64{}
+	for _, groupPartitions := range result.ConsumerGroupsTopicPartitions {
+		for _, tp := range groupPartitions.Partitions {
+			if tp.Error != nil {
+				return nil, fmt.Errorf("failed to get the committed offset of %s: %w", tp, tp.Error)
+			}
+			offsets[partitionKey{topic: *tp.Topic, partition: tp.Partition}] = int64(tp.Offset)
+		}
+	}
+	return offsets, nil
+}
+
+func endOffsets(ctx context.Context, admin *kafka.AdminClient, partitions map[partitionKey]int64,
+) (map[partitionKey]int64, error) {
+	specs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	for key := range partitions {
+		topic := key.topic
+		specs[kafka.TopicPartition{Topic: &topic, Partition: key.partition}] = kafka.LatestOffsetSpec
+	}
+	result, err := admin.ListOffsets(ctx, specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the end offsets: %w", err)
+	}
+	// the topic of the result is a new pointer, so the result is keyed by the topic name instead
+	offsets := map[partitionKey]int64{}
+	for tp, info := range result.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return nil, fmt.Errorf("failed to get the end offset of %s: %w", tp, info.Error)
+		}
+		offsets[partitionKey{topic: *tp.Topic, partition: tp.Partition}] = int64(info.Offset)
+	}
+	return offsets, nil
+}
+
+func offsetString(offset int64) string {
+	if offset < 0 {
+		return "-"
+	}
+	return strconv.FormatInt(offset, 10)
+}
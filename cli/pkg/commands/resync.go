@@ -0,0 +1,46 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/pflag"
+)
+
+type resyncRequest struct {
+	EventTypes []string `json:"eventTypes,omitempty"`
+}
+
+func newResyncCommand() *command {
+	var eventTypes []string
+	fs := pflag.NewFlagSet("resync", pflag.ContinueOnError)
+	fs.StringSliceVar(&eventTypes, "event-types", nil, "the status event types to resync, e.g. "+
+		"io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.info, all the event types are resynced "+
+		"if it's empty.")
+	return &command{
+		name:        "resync",
+		usage:       "resync <hub> [--event-types <type>,...]",
+		description: "Trigger the managed hub to resend its full status to the global hub.",
+		flags:       fs,
+		run: func(ctx context.Context, o *Options, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("resync requires exactly one managed hub name")
+			}
+			hub := args[0]
+			client, err := newAPIClient(ctx, o)
+			if err != nil {
+				return err
+			}
+			if err := client.post(ctx, "/managedhub/"+url.PathEscape(hub)+"/resync",
+				&resyncRequest{EventTypes: eventTypes}); err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "the resync request is sent to the managed hub %s\n", hub)
+			return nil
+		},
+	}
+}
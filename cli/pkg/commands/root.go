@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// command is a subcommand of the plugin, the flags of the command are bound to the variables captured by the run
+type command struct {
+	name        string
+	usage       string
+	description string
+	flags       *pflag.FlagSet
+	run         func(ctx context.Context, o *Options, args []string) error
+}
+
+func newCommands() []*command {
+	return []*command{
+		newHubsCommand(),
+		newFreshnessCommand(),
+		newResyncCommand(),
+		newLagCommand(),
+		newTailCommand(),
+		newGatherCommand(),
+	}
+}
+
+// Options are the flags shared by the commands, which locate the global hub by the kubeconfig
+type Options struct {
+	Kubeconfig string
+	Context    string
+	Namespace  string
+	// Server is the url of the global hub manager api, it's discovered from the route of the manager if it's empty
+	Server string
+	// Token authenticates the requests of the api, the token of the kubeconfig is used if it's empty
+	Token                 string
+	InsecureSkipTLSVerify bool
+	Timeout               time.Duration
+
+	Out    io.Writer
+	ErrOut io.Writer
+
+	restConfig *rest.Config
+}
+
+func (o *Options) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", "", "path to the kubeconfig file of the global hub cluster.")
+	fs.StringVar(&o.Context, "context", "", "the kubeconfig context to use.")
+	fs.StringVarP(&o.Namespace, "namespace", "n", constants.GHDefaultNamespace,
+		"the namespace of the global hub.")
+	fs.StringVar(&o.Server, "server", "", "the url of the global hub manager api, e.g. https://<host>, it's "+
+		"discovered from the route of the manager if it's empty.")
+	fs.StringVar(&o.Token, "token", "", "the bearer token of the api, the token of the kubeconfig is used if "+
+		"it's empty.")
+	fs.BoolVar(&o.InsecureSkipTLSVerify, "insecure-skip-tls-verify", false,
+		"skip the verification of the api and kafka server certificates.")
+	fs.DurationVar(&o.Timeout, "request-timeout", 30*time.Second, "the timeout of the requests.")
+}
+
+// RestConfig returns the config of the global hub cluster from the kubeconfig
+func (o *Options) RestConfig() (*rest.Config, error) {
+	if o.restConfig != nil {
+		return o.restConfig, nil
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: o.Context}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	o.restConfig = restConfig
+	return restConfig, nil
+}
+
+// KubeClient returns the client of the global hub cluster, which reads the manager and its route and secrets
+func (o *Options) KubeClient() (client.Client, error) {
+	restConfig, err := o.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := routev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{Scheme: scheme})
+}
+
+// Run runs the command of the args and returns the exit code
+func Run(ctx context.Context, args []string, out, errOut io.Writer) int {
+	commands := newCommands()
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(out, commands)
+		return 0
+	}
+
+	var cmd *command
+	for _, c := range commands {
+		if c.name == args[0] {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(errOut, "unknown command %q\n\n", args[0])
+		printUsage(errOut, commands)
+		return 1
+	}
+
+	o := &Options{Out: out, ErrOut: errOut}
+	o.addFlags(cmd.flags)
+	cmd.flags.SetOutput(errOut)
+	cmd.flags.Usage = func() {
+		fmt.Fprintf(errOut, "%s\n\nUsage:\n  kubectl globalhub %s\n\nFlags:\n%s", cmd.description, cmd.usage,
+			cmd.flags.FlagUsages())
+	}
+	if err := cmd.flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if err := cmd.run(ctx, o, cmd.flags.Args()); err != nil {
+		fmt.Fprintf(errOut, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer, commands []*command) {
+	fmt.Fprintf(w, "Troubleshoot the multicluster global hub.\n\nUsage:\n  kubectl globalhub <command> [flags]\n\n"+
+		"Commands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.description)
+	}
+	fmt.Fprintf(w, "\nUse \"kubectl globalhub <command> --help\" for more information about a command.\n")
+}
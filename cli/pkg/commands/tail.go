@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/spf13/pflag"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
+)
+
+const tailPollTimeoutMs = 500
+
+type tailOptions struct {
+	hub           string
+	eventType     string
+	last          int64
+	fromBeginning bool
+	noData        bool
+}
+
+func newTailCommand() *command {
+	k := &kafkaOptions{}
+	t := &tailOptions{}
+	fs := pflag.NewFlagSet("tail", pflag.ContinueOnError)
+	k.addFlags(fs)
+	fs.StringVar(&t.hub, "hub", "", "only show the events from the managed hub.")
+	fs.StringVar(&t.eventType, "type", "", "only show the events whose type contains the value, e.g. managedcluster.")
+	fs.Int64Var(&t.last, "last", 0, "start from the last number of messages of each partition instead of the new ones.")
+	fs.BoolVar(&t.fromBeginning, "from-beginning", false, "start from the earliest messages of the topic.")
+	fs.BoolVar(&t.noData, "no-data", false, "only show the attributes of the events without the data.")
+	return &command{
+		name:        "tail",
+		usage:       "tail [<topic>] [--hub <name>] [--type <type>] [--last <n> | --from-beginning] [--no-data]",
+		description: "Tail the decoded CloudEvents of a topic, it's the event topic of the manager by default.",
+		flags:       fs,
+		run: func(ctx context.Context, o *Options, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("tail accepts at most one topic")
+			}
+			if t.last < 0 {
+				return fmt.Errorf("--last must not be negative")
+			}
+			m, err := loadManagerKafka(ctx, o, k)
+			if err != nil {
+				return err
+			}
+			defer m.cleanup()
+			topic := m.eventTopic
+			if len(args) == 1 {
+				topic = args[0]
+			}
+			return tail(ctx, o, m, topic, t)
+		},
+	}
+}
+
+// tail assigns the partitions of the topic to the consumer at the explicit offsets, so it doesn't join the consumer
+// group of the manager, and it never commits the offsets
+func tail(ctx context.Context, o *Options, m *managerKafka, topic string, t *tailOptions) error {
+	configMap, err := m.configMap()
+	if err != nil {
+		return err
+	}
+	consumer, err := kafka.NewConsumer(configMap)
+	if err != nil {
+		return fmt.Errorf("failed to create the kafka consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	timeoutMs := int(o.Timeout.Milliseconds())
+	metadata, err := consumer.GetMetadata(&topic, false, timeoutMs)
+	if err != nil {
+		return fmt.Errorf("failed to get the metadata of the topic %s: %w", topic, err)
+	}
+	topicMetadata, ok := metadata.Topics[topic]
+	if !ok || topicMetadata.Error.Code() == kafka.ErrUnknownTopicOrPart {
+		return fmt.Errorf("the topic %s isn't found", topic)
+	}
+
+	partitions := []kafka.TopicPartition{}
+	for _, p := range topicMetadata.Partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(topic, p.ID, timeoutMs)
+		if err != nil {
+			return fmt.Errorf("failed to query the offsets of %s[%d]: %w", topic, p.ID, err)
+		}
+		partitions = append(partitions, kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: p.ID,
+			Offset:    kafka.Offset(startOffset(low, high, t)),
+		})
+	}
+	if err := consumer.Assign(partitions); err != nil {
+		return fmt.Errorf("failed to assign the partitions of %s: %w", topic, err)
+	}
+	fmt.Fprintf(o.ErrOut, "tailing the topic %s, press Ctrl+C to stop\n", topic)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+		switch e := consumer.Poll(tailPollTimeoutMs).(type) {
+		case *kafka.Message:
+			evt, err := binding.ToEvent(ctx, kafka_confluent.NewMessage(e))
+			if err != nil {
+				fmt.Fprintf(o.ErrOut, "skip the message %s: %v\n", e.TopicPartition, err)
+				continue
+			}
+			if !matchEvent(evt, t) {
+				continue
+			}
+			fmt.Fprint(o.Out, formatEvent(e.TopicPartition, evt, t.noData))
+		case kafka.Error:
+			if e.IsFatal() {
+				return e
+			}
+			fmt.Fprintf(o.ErrOut, "kafka error: %v\n", e)
+		}
+	}
+}
+
+// startOffset returns the offset of the partition to start from, it's the end of the partition by default
+func startOffset(low, high int64, t *tailOptions) int64 {
+	switch {
+	case t.fromBeginning:
+		return low
+	case t.last > 0 && high-t.last > low:
+		return high - t.last
+	case t.last > 0:
+		return low
+	default:
+		return high
+	}
+}
+
+func matchEvent(evt *cloudevents.Event, t *tailOptions) bool {
+	if t.hub != "" && evt.Source() != t.hub {
+		return false
+	}
+	return t.eventType == "" || strings.Contains(evt.Type(), t.eventType)
+}
+
+// formatEvent prints the summary line of the event followed by the indented json data, the chunk of a large event is
+// marked since its data can't be decoded until all the chunks are assembled
+func formatEvent(tp kafka.TopicPartition, evt *cloudevents.Event, noData bool) string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%s %s[%d]@%d type=%s source=%s id=%s", evt.Time().Local().Format(time.RFC3339),
+		*tp.Topic, tp.Partition, tp.Offset, evt.Type(), evt.Source(), evt.ID())
+	if v, ok := evt.Extensions()[version.ExtVersion]; ok {
+		fmt.Fprintf(buf, " version=%v", v)
+	}
+
+	size, sizeErr := types.ToInteger(evt.Extensions()[transport.ChunkSizeKey])
+	offset, offsetErr := types.ToInteger(evt.Extensions()[transport.ChunkOffsetKey])
+	chunked := sizeErr == nil && offsetErr == nil
+	if chunked {
+		fmt.Fprintf(buf, " chunk=%d/%d", offset, size)
+	}
+	buf.WriteString("\n")
+
+	if noData || len(evt.Data()) == 0 {
+		return buf.String()
+	}
+	data := &bytes.Buffer{}
+	if chunked || json.Indent(data, evt.Data(), "  ", "  ") != nil {
+		fmt.Fprintf(buf, "  <%d bytes>\n", len(evt.Data()))
+		return buf.String()
+	}
+	buf.WriteString("  ")
+	buf.Write(data.Bytes())
+	buf.WriteString("\n")
+	return buf.String()
+}
@@ -11,6 +11,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -31,22 +32,38 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/clustergroup"
 	managerconfig "github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/datacollection"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/enrichment"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/eventexporter"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/inventory"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/notification"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/reporting"
 	managerscheme "github.com/stolostron/multicluster-global-hub/manager/pkg/scheme"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer"
 	statussyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/freshness"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/ratelimit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/versionskew"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/upstream"
 	mgrwebhook "github.com/stolostron/multicluster-global-hub/manager/pkg/webhook"
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/diagnostics"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -81,8 +98,10 @@ func init() {
 
 func parseFlags() *managerconfig.ManagerConfig {
 	managerConfig := &managerconfig.ManagerConfig{
-		SyncerConfig:   &managerconfig.SyncerConfig{},
-		DatabaseConfig: &managerconfig.DatabaseConfig{},
+		SyncerConfig:    &managerconfig.SyncerConfig{},
+		SchedulerConfig: &managerconfig.SchedulerConfig{},
+		ArchiveConfig:   &archive.S3Config{},
+		DatabaseConfig:  &managerconfig.DatabaseConfig{},
 		TransportConfig: &transport.TransportConfig{
 			KafkaConfig: &transport.KafkaConfig{
 				EnableTLS:      true,
@@ -95,6 +114,17 @@ func parseFlags() *managerconfig.ManagerConfig {
 		NonK8sAPIServerConfig: &nonk8sapi.NonK8sAPIServerConfig{},
 		ElectionConfig:        &commonobjects.LeaderElectionConfig{},
 		LaunchJobNames:        "",
+		UpstreamConfig: &upstream.UpstreamConfig{
+			TransportConfig: &transport.TransportConfig{
+				TransportType: string(transport.Kafka),
+				KafkaConfig: &transport.KafkaConfig{
+					EnableTLS:      true,
+					Topics:         &transport.ClusterTopic{},
+					ProducerConfig: &transport.KafkaProducerConfig{},
+				},
+			},
+		},
+		InventoryConfig: &inventory.InventoryConfig{},
 	}
 
 	// add zap flags
@@ -110,14 +140,36 @@ func parseFlags() *managerconfig.ManagerConfig {
 	pflag.StringVar(&managerConfig.SchedulerInterval, "scheduler-interval", "day",
 		"The job scheduler interval for moving policy compliance history, "+
 			"can be 'month', 'week', 'day', 'hour', 'minute' or 'second', default value is 'day'.")
+	pflag.StringVar(&managerConfig.SchedulerConfig.TimeZone, "scheduler-timezone", "",
+		"the IANA time zone of the job schedules, e.g. America/New_York, the local time zone is used if it's empty")
+	pflag.StringVar(&managerConfig.SchedulerConfig.LocalComplianceHistorySchedule,
+		"local-compliance-history-schedule", "",
+		"the cron schedule of the local compliance history job, it overrides the scheduler-interval if it's set")
+	pflag.StringVar(&managerConfig.SchedulerConfig.DataRetentionSchedule, "data-retention-schedule", "",
+		"the cron schedule of the data retention job, it runs on the 1st, 15th and 28th of every month by default")
 	pflag.DurationVar(&managerConfig.SyncerConfig.SpecSyncInterval, "spec-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in spec.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.StatusSyncInterval, "status-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in status.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.DeletedLabelsTrimmingInterval, "deleted-labels-trimming-interval",
 		5*time.Second, "The trimming interval of deleted labels.")
+	pflag.DurationVar(&managerConfig.SyncerConfig.SpecDriftDetectionInterval, "spec-drift-detection-interval",
+		5*time.Minute, "The interval to compare the global resources with the managed hubs, 0 disables it.")
+	pflag.BoolVar(&managerConfig.SyncerConfig.SpecDriftRemediation, "spec-drift-remediation", false,
+		"Resend the global resources to the managed hubs once the drift is detected twice in a row.")
 	pflag.IntVar(&managerConfig.DatabaseConfig.MaxOpenConns, "database-pool-size", 10,
 		"The size of database connection pool for the process user.")
+	pflag.IntVar(&managerConfig.DatabaseConfig.RetryAttempts, "database-retry-attempts",
+		database.DefaultRetryConfig.MaxAttempts,
+		"The max attempts of the database operation when the database is failed transiently, e.g. the failover.")
+	pflag.IntVar(&managerConfig.DatabaseConfig.CircuitFailureThreshold, "database-circuit-failure-threshold",
+		database.DefaultRetryConfig.FailureThreshold,
+		"The consecutive transient failures of the database to open the circuit breaker.")
+	pflag.DurationVar(&managerConfig.DatabaseConfig.CircuitOpenDuration, "database-circuit-open-duration",
+		database.DefaultRetryConfig.OpenDuration,
+		"The duration of the open circuit breaker to fail the database operations fast before probing it again.")
+	pflag.DurationVar(&managerConfig.DatabaseConfig.SlowQueryThreshold, "database-slow-query-threshold", 0,
+		"The queries which take longer than it are logged without the bound parameters, it's disabled if it's 0.")
 	pflag.StringVar(&managerConfig.DatabaseConfig.ProcessDatabaseURL, "process-database-url", "",
 		"The URL of database server for the process user.")
 	pflag.StringVar(&managerConfig.DatabaseConfig.TransportBridgeDatabaseURL,
@@ -130,6 +182,9 @@ func parseFlags() *managerconfig.ManagerConfig {
 		40*time.Second, "The committer interval for transport layer.")
 	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.BootstrapServer, "kafka-bootstrap-server",
 		"kafka-kafka-bootstrap.kafka.svc:9092", "The bootstrap server for kafka.")
+	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.BootstrapServerPath, "kafka-bootstrap-server-path", "",
+		"The path of the file containing the bootstrap server for kafka, it overrides the kafka-bootstrap-server and "+
+			"the kafka clients reconnect once it's changed.")
 	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.ClusterIdentity, "kafka-cluster-identity",
 		"", "The identity for kafka cluster.")
 	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.CaCertPath, "kafka-ca-cert-path", "",
@@ -146,12 +201,20 @@ func parseFlags() *managerconfig.ManagerConfig {
 		"spec", "Topic for the kafka producer.")
 	pflag.IntVar(&managerConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB,
 		"kafka-message-size-limit", 940, "The limit for kafka message size in KB.")
+	pflag.BoolVar(&managerConfig.TransportConfig.KafkaConfig.ProducerConfig.DisableIdempotence,
+		"kafka-disable-idempotence", false, "Disable the idempotent kafka producer, e.g. the kafka cluster doesn't "+
+			"grant the idempotent write to the user.")
 	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.ConsumerConfig.ConsumerID,
 		"kafka-consumer-id", "multicluster-global-hub-manager", "ID for the kafka consumer.")
+	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.ConsumerConfig.OffsetReset, "kafka-offset-reset",
+		transport.OffsetResetEarliest, "The policy to reset the offset when the stored position is out of range "+
+			"or missing, earliest or latest.")
 	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.Topics.StatusTopic,
 		"kafka-consumer-topic", "status", "Topic for the kafka consumer.")
 	pflag.StringVar(&managerConfig.TransportConfig.KafkaConfig.Topics.EventTopic,
 		"kafka-event-topic", "event", "Event topic for the event message")
+	pflag.StringVar(&managerConfig.AdditionalKafkaConfigPath, "additional-kafka-config-path", "",
+		"the file of the additional kafka clusters which the status is consumed from besides the default one.")
 	pflag.StringVar(&managerConfig.StatisticsConfig.LogInterval, "statistics-log-interval", "1m",
 		"The log interval for statistics.")
 	pflag.StringVar(&managerConfig.NonK8sAPIServerConfig.ClusterAPIURL, "cluster-api-url",
@@ -167,10 +230,102 @@ func parseFlags() *managerconfig.ManagerConfig {
 		"data retention indicates how many months the expired data will kept in the database")
 	pflag.BoolVar(&managerConfig.EnableGlobalResource, "enable-global-resource", false,
 		"enable the global resource feature.")
+	pflag.StringVar(&managerConfig.NotificationConfigPath, "notification-config-path", "",
+		"the file of the notification sinks and rules for the compliance changes, empty means disabled.")
+	pflag.StringVar(&managerConfig.EventExporterConfigPath, "event-exporter-config-path", "",
+		"the file of the sinks which the fleet events are exported to as the cloudevents, empty means disabled.")
+	pflag.StringVar(&managerConfig.EnrichmentConfigPath, "enrichment-config-path", "",
+		"the file of the fields computed from the labels and claims of the managed clusters, empty means disabled.")
+	pflag.StringVar(&managerConfig.ReportConfigPath, "report-config-path", "",
+		"the file of the scheduled compliance reports delivered to the object storage or email, empty means disabled.")
+	pflag.BoolVar(&managerConfig.EnableHubSharding, "enable-hub-sharding", false,
+		"partition the hubs among the manager replicas, each replica only consumes the status of its own hubs.")
+	pflag.BoolVar(&managerConfig.EnableWarmStandby, "enable-warm-standby", false,
+		"keep the status consumer of the standby replicas assigned but paused until they're elected.")
+	pflag.DurationVar(&managerConfig.HubInactiveTimeout, "hub-inactive-timeout", hubmanagement.ActiveTimeout,
+		"the hub is marked as inactive if it doesn't send the heartbeat within the timeout.")
+	pflag.DurationVar(&managerConfig.DetachedHubRetention, "detached-hub-retention", 7*24*time.Hour,
+		"the data of the detached hub is purged from the database after the retention.")
+	pflag.DurationVar(&managerConfig.MigrationPhaseTimeout, "migration-phase-timeout", migration.PhaseTimeout,
+		"the managed cluster migration is failed if any of its phases isn't completed within the timeout.")
+	pflag.StringVar(&managerConfig.SigningPublicKeysDir, "signing-public-keys-dir", "",
+		"the directory of the public keys of the managed hubs to verify the signed status events, "+
+			"the events aren't verified if it's empty.")
+	pflag.IntVar(&managerConfig.MaxAgentMinorVersionSkew, "max-agent-version-skew", versionskew.DefaultMaxMinorSkew,
+		"the number of the minor versions the agents may fall behind the manager, the other agents are incompatible.")
+	pflag.BoolVar(&managerConfig.QuarantineIncompatibleAgents, "quarantine-incompatible-agents", false,
+		"don't persist the status events of the incompatible agents except the heartbeat.")
+	pflag.BoolVar(&managerConfig.EnableSchemaValidation, "enable-schema-validation", true,
+		"reject the status events which don't match the schemas of their versions and save them as the dead letters.")
+	pflag.StringVar(&managerConfig.DataCollectionProfile, "data-collection-profile", specbundle.DataCollectionFull,
+		"the profile deciding the status bundles collected by the agents, it's Minimal, Standard or Full.")
+	pflag.Float64Var(&managerConfig.HubEventsPerSecond, "hub-events-per-second", ratelimit.DefaultEventsPerSecond,
+		"the rate of the status events admitted from each managed hub, the rate isn't limited if it's 0(default).")
+	pflag.IntVar(&managerConfig.HubEventBurst, "hub-event-burst", ratelimit.DefaultBurst,
+		"the number of the status events admitted from each managed hub at once beyond the rate.")
+	pflag.StringToStringVar(&managerConfig.RawDataFreshnessExpectedIntervals, "data-freshness-expected-intervals",
+		freshness.DefaultExpectedIntervals, "the intervals the bundles are expected to be received in, keyed by the "+
+			"bundle types, e.g. managedhub.heartbeat=1m. The bundles behind them are stale.")
+	pflag.StringVar(&managerConfig.LogLevelConfigMap, "log-level-configmap",
+		"multicluster-global-hub-manager-logging", "the configmap of the log levels in the manager namespace, e.g. "+
+			"the transport key sets the level of the transport layer, the levels aren't watched if it's empty.")
+	pflag.StringVar(&managerConfig.ConflationConfigMap, "conflation-configmap",
+		"multicluster-global-hub-manager-conflation", "the configmap of the conflation parameters in the manager "+
+			"namespace, e.g. strategy.managedcluster=sequential, the parameters aren't watched if it's empty.")
+	pflag.BoolVar(&managerConfig.MetricsSecure, "metrics-secure", false,
+		"serve the metrics with https, the requests are authenticated and authorized by the kube-apiserver.")
+	pflag.StringVar(&managerConfig.MetricsCertDir, "metrics-cert-dir", "",
+		"the directory of the tls.crt and tls.key to serve the secure metrics, the self-signed certificate is used "+
+			"if it's empty.")
+	pflag.BoolVar(&managerConfig.EnableDiagnostics, "enable-diagnostics", false,
+		"serve the pprof, expvar and the diagnostics bundle under /debug of the metrics server, the requests are "+
+			"authenticated and authorized by the kube-apiserver.")
+	pflag.StringVar(&managerConfig.ArchiveConfig.Endpoint, "archive-endpoint", "",
+		"the host of the S3-compatible storage to archive the expired partitions, e.g. s3.us-east-1.amazonaws.com. "+
+			"The credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.")
+	pflag.StringVar(&managerConfig.ArchiveConfig.Bucket, "archive-bucket", "",
+		"the bucket to archive the expired partitions, the archive is disabled if it's empty.")
+	pflag.StringVar(&managerConfig.ArchiveConfig.Region, "archive-region", "us-east-1",
+		"the region of the archive bucket.")
+	pflag.StringVar(&managerConfig.ArchiveConfig.Prefix, "archive-prefix", "",
+		"the prefix of the archived objects in the bucket.")
+	pflag.BoolVar(&managerConfig.ArchiveConfig.Insecure, "archive-insecure", false,
+		"access the archive storage with http instead of https.")
+	pflag.StringVar(&managerConfig.UpstreamConfig.RegionalHubName, "regional-hub-name", "",
+		"the name of the global hub as a regional global hub, which is the source of its summaries on the upstream.")
+	pflag.DurationVar(&managerConfig.UpstreamConfig.SyncInterval, "upstream-sync-interval", time.Minute,
+		"the interval to forward the summary of the managed hubs to the upstream global hub.")
+	pflag.StringVar(&managerConfig.UpstreamConfig.TransportConfig.KafkaConfig.BootstrapServer,
+		"upstream-kafka-bootstrap-server", "",
+		"the kafka bootstrap server of the upstream global hub, the summaries aren't forwarded if it's empty.")
+	pflag.StringVar(&managerConfig.UpstreamConfig.TransportConfig.KafkaConfig.CaCertPath,
+		"upstream-kafka-ca-cert-path", "", "the path of the CA certificate of the upstream kafka.")
+	pflag.StringVar(&managerConfig.UpstreamConfig.TransportConfig.KafkaConfig.ClientCertPath,
+		"upstream-kafka-client-cert-path", "", "the path of the client certificate of the upstream kafka.")
+	pflag.StringVar(&managerConfig.UpstreamConfig.TransportConfig.KafkaConfig.ClientKeyPath,
+		"upstream-kafka-client-key-path", "", "the path of the client key of the upstream kafka.")
+	pflag.StringVar(&managerConfig.UpstreamConfig.TransportConfig.KafkaConfig.Topics.StatusTopic,
+		"upstream-kafka-status-topic", "status", "the status topic of the upstream global hub.")
+	pflag.StringVar(&managerConfig.InventoryConfig.ServerURL, "inventory-server-url", "",
+		"the url of the inventory API, the clusters and policies aren't exported if it's empty.")
+	pflag.StringVar(&managerConfig.InventoryConfig.CACertPath, "inventory-ca-cert-path", "",
+		"the path of the CA certificate of the inventory API.")
+	pflag.StringVar(&managerConfig.InventoryConfig.ClientCertPath, "inventory-client-cert-path", "",
+		"the path of the client certificate of the inventory API.")
+	pflag.StringVar(&managerConfig.InventoryConfig.ClientKeyPath, "inventory-client-key-path", "",
+		"the path of the client key of the inventory API.")
+	pflag.StringVar(&managerConfig.InventoryConfig.TokenPath, "inventory-token-path", "",
+		"the path of the bearer token of the inventory API.")
+	pflag.StringVar(&managerConfig.InventoryConfig.ReporterInstanceID, "inventory-reporter-instance-id",
+		"multicluster-global-hub", "the id of the global hub as the reporter of the inventory.")
+	pflag.StringVar(&managerConfig.InventoryConfig.WorkspaceID, "inventory-workspace-id", "",
+		"the workspace of the exported resources, the default workspace is used if it's empty.")
+	pflag.DurationVar(&managerConfig.InventoryConfig.SyncInterval, "inventory-sync-interval", 5*time.Minute,
+		"the interval to export the clusters and policies to the inventory API.")
 
 	pflag.Parse()
 	// set zap logger
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctrl.SetLogger(logger.New(&opts))
 
 	pflag.Visit(func(f *pflag.Flag) {
 		// set enableSimulation to be true when manually set 'scheduler-interval' flag
@@ -190,6 +345,23 @@ func completeConfig(managerConfig *managerconfig.ManagerConfig) error {
 		return fmt.Errorf("%w - size must not exceed %d : %s", errFlagParameterIllegalValue,
 			managerConfig.TransportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB, "kafka-message-size-limit")
 	}
+	if !specbundle.IsValidDataCollectionProfile(managerConfig.DataCollectionProfile) {
+		return fmt.Errorf("%w - profile must be Minimal, Standard or Full : %s", errFlagParameterIllegalValue,
+			"data-collection-profile")
+	}
+	expectedIntervals, err := freshness.ParseExpectedIntervals(managerConfig.RawDataFreshnessExpectedIntervals)
+	if err != nil {
+		return fmt.Errorf("%w - %v : %s", errFlagParameterIllegalValue, err, "data-freshness-expected-intervals")
+	}
+	managerConfig.DataFreshnessExpectedIntervals = expectedIntervals
+	if managerConfig.AdditionalKafkaConfigPath != "" {
+		transportConfigs, err := managerconfig.LoadAdditionalTransportConfigs(managerConfig.AdditionalKafkaConfigPath,
+			managerConfig.TransportConfig)
+		if err != nil {
+			return err
+		}
+		managerConfig.AdditionalTransportConfigs = transportConfigs
+	}
 	// the specified jobs(concatenate multiple jobs with ',') runs when the container starts
 	val, ok := os.LookupEnv(launchJobNamesEnv)
 	if ok && val != "" {
@@ -198,6 +370,19 @@ func completeConfig(managerConfig *managerconfig.ManagerConfig) error {
 	return nil
 }
 
+// metricsCertDir returns the directory of the serving certificate of the secure metrics, it's empty to generate the
+// self-signed certificate if the certificate isn't found, e.g. the serving certificate isn't issued by the service CA
+func metricsCertDir(certDir string) string {
+	if certDir == "" {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(certDir, "tls.crt")); err != nil {
+		setupLog.Info("the metrics certificate isn't found, use the self-signed certificate", "dir", certDir)
+		return ""
+	}
+	return certDir
+}
+
 func createManager(ctx context.Context,
 	restConfig *rest.Config,
 	managerConfig *managerconfig.ManagerConfig,
@@ -218,6 +403,33 @@ func createManager(ctx context.Context,
 		RenewDeadline:           &renewDeadline,
 		RetryPeriod:             &retryPeriod,
 		NewCache:                initCache,
+		// release the lease on shutdown, so the warm standby is elected without waiting for the lease to expire
+		LeaderElectionReleaseOnCancel: managerConfig.EnableWarmStandby,
+	}
+
+	if managerConfig.MetricsSecure {
+		options.Metrics.SecureServing = true
+		options.Metrics.FilterProvider = filters.WithAuthenticationAndAuthorization
+		options.Metrics.CertDir = metricsCertDir(managerConfig.MetricsCertDir)
+	}
+
+	if managerConfig.EnableDiagnostics {
+		// the extra handlers are filtered by the secure metrics server, protect them by themselves otherwise
+		options.Metrics.ExtraHandlers = diagnostics.Handlers()
+		if !managerConfig.MetricsSecure {
+			handlers, err := diagnostics.ProtectedHandlers(restConfig, setupLog)
+			if err != nil {
+				return nil, err
+			}
+			options.Metrics.ExtraHandlers = handlers
+		}
+		diagnostics.Register("database", func(ctx context.Context) (interface{}, error) {
+			sqlDB := database.GetSqlDb()
+			if sqlDB == nil {
+				return nil, fmt.Errorf("the database connection isn't initialized")
+			}
+			return sqlDB.Stats(), nil
+		})
 	}
 
 	if managerConfig.EnableGlobalResource {
@@ -254,9 +466,11 @@ func createManager(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a new manager: %w", err)
 	}
-
-	if err := nonk8sapi.AddNonK8sApiServer(mgr, managerConfig.NonK8sAPIServerConfig); err != nil {
-		return nil, fmt.Errorf("failed to add non-k8s-api-server: %w", err)
+	if managerConfig.LogLevelConfigMap != "" {
+		if err := logger.AddLevelWatcher(mgr, managerConfig.ManagerNamespace,
+			managerConfig.LogLevelConfigMap); err != nil {
+			return nil, fmt.Errorf("failed to add the log level watcher: %w", err)
+		}
 	}
 
 	producer, err := producer.NewGenericProducer(managerConfig.TransportConfig,
@@ -264,21 +478,66 @@ func createManager(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("failed to init spec transport bridge: %w", err)
 	}
+
+	if managerConfig.TransportConfig.TransportType == string(transport.Kafka) {
+		managerConfig.NonK8sAPIServerConfig.KafkaConfig = managerConfig.TransportConfig.KafkaConfig
+	}
+	if err := nonk8sapi.AddNonK8sApiServer(mgr, managerConfig.NonK8sAPIServerConfig, producer); err != nil {
+		return nil, fmt.Errorf("failed to add non-k8s-api-server: %w", err)
+	}
 	if managerConfig.EnableGlobalResource {
 		if err := specsyncer.AddGlobalResourceSpecSyncers(mgr, managerConfig, producer); err != nil {
 			return nil, fmt.Errorf("failed to add global resource spec syncers: %w", err)
 		}
 	}
 
-	if err := statussyncer.AddStatusSyncers(mgr, managerConfig); err != nil {
+	// the enrichers are registered before the status is consumed
+	if err := enrichment.AddEnrichment(managerConfig.EnrichmentConfigPath); err != nil {
+		return nil, fmt.Errorf("failed to add enrichment: %w", err)
+	}
+
+	if err := statussyncer.AddStatusSyncers(mgr, managerConfig, producer); err != nil {
 		return nil, fmt.Errorf("failed to add transport-to-db syncers: %w", err)
 	}
 
+	if err := notification.AddNotificationEngine(mgr, managerConfig.NotificationConfigPath,
+		managerConfig.ManagerNamespace); err != nil {
+		return nil, fmt.Errorf("failed to add notification engine: %w", err)
+	}
+
+	if err := eventexporter.AddEventExporter(mgr, managerConfig.EventExporterConfigPath); err != nil {
+		return nil, fmt.Errorf("failed to add event exporter: %w", err)
+	}
+
+	if err := reporting.AddReportScheduler(mgr, managerConfig.ReportConfigPath); err != nil {
+		return nil, fmt.Errorf("failed to add report scheduler: %w", err)
+	}
+
 	// add hub management
-	if err := hubmanagement.AddHubManagement(mgr, producer); err != nil {
+	if err := hubmanagement.AddHubManagement(mgr, producer, managerConfig.HubInactiveTimeout); err != nil {
 		return nil, fmt.Errorf("failed to add hubmanagement to manager - %w", err)
 	}
 
+	if err := datacollection.AddProfilePublisher(mgr, producer, managerConfig.DataCollectionProfile); err != nil {
+		return nil, fmt.Errorf("failed to add the data collection profile publisher to manager: %w", err)
+	}
+
+	if err := migration.AddMigrationController(mgr, producer, managerConfig.MigrationPhaseTimeout); err != nil {
+		return nil, fmt.Errorf("failed to add the managed cluster migration controller to manager: %w", err)
+	}
+
+	if err := clustergroup.AddMembersRefresher(mgr); err != nil {
+		return nil, fmt.Errorf("failed to add the cluster group members refresher to manager: %w", err)
+	}
+
+	if err := upstream.AddUpstreamForwarder(mgr, managerConfig.UpstreamConfig); err != nil {
+		return nil, fmt.Errorf("failed to add upstream forwarder to manager: %w", err)
+	}
+
+	if err := inventory.AddInventoryExporter(mgr, managerConfig.InventoryConfig); err != nil {
+		return nil, fmt.Errorf("failed to add inventory exporter to manager: %w", err)
+	}
+
 	if err := cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, enableSimulation); err != nil {
 		return nil, fmt.Errorf("failed to add scheduler to manager: %w", err)
 	}
@@ -306,6 +565,14 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 		CaCertPath: managerConfig.DatabaseConfig.CACertPath,
 		PoolSize:   managerConfig.DatabaseConfig.MaxOpenConns,
 	}
+	database.SetRetryConfig(database.RetryConfig{
+		MaxAttempts:      managerConfig.DatabaseConfig.RetryAttempts,
+		InitialBackoff:   database.DefaultRetryConfig.InitialBackoff,
+		MaxBackoff:       database.DefaultRetryConfig.MaxBackoff,
+		FailureThreshold: managerConfig.DatabaseConfig.CircuitFailureThreshold,
+		OpenDuration:     managerConfig.DatabaseConfig.CircuitOpenDuration,
+	})
+	database.SetSlowQueryThreshold(managerConfig.DatabaseConfig.SlowQueryThreshold)
 	// Init the default gorm instance, it's used to sync data to db
 	err := database.InitGormInstance(databaseConfig)
 	if err != nil {
@@ -314,6 +581,13 @@ func doMain(ctx context.Context, restConfig *rest.Config) int {
 	}
 	defer database.CloseGorm(database.GetSqlDb())
 
+	// Init the pgx pool, it's used by the hot path of the status ingestion
+	if err := database.InitPgxPool(ctx, databaseConfig); err != nil {
+		setupLog.Error(err, "failed to initialize pgx pool")
+		return 1
+	}
+	defer database.ClosePgxPool()
+
 	// Init the backup gorm instance, it's used to add lock when backup database
 	_, sqlBackupConn, err := database.NewGormConn(databaseConfig)
 	if err != nil {
@@ -0,0 +1,152 @@
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var log = ctrl.Log.WithName("archiver")
+
+// Archiver exports the aged partitions to the parquet files in the object storage, and records them in the
+// history.archived_partitions table, so the data beyond the online retention can be retrieved by the auditors
+type Archiver struct {
+	client *S3Client
+}
+
+func NewArchiver(config *S3Config) (*Archiver, error) {
+	client, err := NewS3Client(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Archiver{client: client}, nil
+}
+
+// ObjectKey returns the key of the archived partition: <prefix>/<table>/<partition>.parquet
+func (a *Archiver) ObjectKey(tableName, partitionName string) string {
+	return path.Join(a.client.config.Prefix, tableName, partitionName+".parquet")
+}
+
+// ArchivePartition exports the partition of the table, it's skipped if the partition doesn't exist or it has been
+// archived. The partition must not be dropped if it returns an error.
+func (a *Archiver) ArchivePartition(ctx context.Context, db *gorm.DB, tableName, partitionName string) error {
+	var exists bool
+	if err := db.Raw("SELECT to_regclass(?) IS NOT NULL", partitionName).Scan(&exists).Error; err != nil {
+		return fmt.Errorf("failed to check the partition %s: %w", partitionName, err)
+	}
+	if !exists {
+		return nil
+	}
+
+	var archived int64
+	err := db.Model(&models.ArchivedPartition{}).Where("partition_name = ?", partitionName).Count(&archived).Error
+	if err != nil {
+		return fmt.Errorf("failed to get the manifest of the partition %s: %w", partitionName, err)
+	}
+	if archived > 0 {
+		log.Info("the partition has been archived", "partition", partitionName)
+		return nil
+	}
+
+	file, err := os.CreateTemp("", "archive-*.parquet")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	hash := sha256.New()
+	rowCount, err := exportPartition(db, partitionName, io.MultiWriter(file, hash))
+	if err != nil {
+		return fmt.Errorf("failed to export the partition %s: %w", partitionName, err)
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	manifest := &models.ArchivedPartition{
+		PartitionName: partitionName,
+		Table:         tableName,
+		Bucket:        a.client.config.Bucket,
+		ObjectKey:     a.ObjectKey(tableName, partitionName),
+		RowCount:      rowCount,
+		SizeBytes:     size,
+		Checksum:      hex.EncodeToString(hash.Sum(nil)),
+	}
+	err = a.client.PutObject(ctx, manifest.ObjectKey, parquetContentType, file, size, manifest.Checksum)
+	if err != nil {
+		return err
+	}
+	log.Info("archive the partition", "partition", partitionName, "object", manifest.ObjectKey, "rows", rowCount)
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(manifest).Error
+}
+
+// exportPartition writes all the rows of the partition into the parquet file, the values are in the text format of
+// the database, e.g. the jsonb is the json string
+func exportPartition(db *gorm.DB, partitionName string, w io.Writer) (int64, error) {
+	schemaTable := strings.Split(partitionName, ".")
+	if len(schemaTable) != 2 {
+		return 0, fmt.Errorf("invalid partition name: %s", partitionName)
+	}
+	var columns []string
+	err := db.Raw(`SELECT column_name FROM information_schema.columns WHERE table_schema = ? AND table_name = ?
+		ORDER BY ordinal_position`, schemaTable[0], schemaTable[1]).Scan(&columns).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("no column found in the partition %s", partitionName)
+	}
+
+	selects := make([]string, 0, len(columns))
+	for _, column := range columns {
+		selects = append(selects, fmt.Sprintf(`%q::text`, column))
+	}
+	rows, err := db.Raw(fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), partitionName)).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	writer, err := newParquetWriter(w, columns, defaultRowGroupSize)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, err
+		}
+		if err := writer.Write(values); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
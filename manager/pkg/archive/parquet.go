@@ -0,0 +1,266 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The archived partitions are written in the parquet format, so they can be queried by the common analytics tools
+// (e.g. Athena, Spark, DuckDB) without restoring them into the database. Only the subset of the format needed by the
+// archive is implemented: all the columns are the optional UTF8 strings, which are the text representation of the
+// database values, and each column chunk is a single gzip compressed data page with the PLAIN encoding.
+// See https://github.com/apache/parquet-format for the details of the format.
+
+const (
+	parquetMagic   = "PAR1"
+	parquetVersion = 1
+	createdBy      = "multicluster-global-hub-manager"
+	// the content type of the archived objects
+	parquetContentType = "application/vnd.apache.parquet"
+
+	// the rows are buffered in memory until the row group is flushed
+	defaultRowGroupSize = 10000
+
+	// parquet types and enums
+	typeByteArray       = 6
+	repetitionRequired  = 0
+	repetitionOptional  = 1
+	convertedTypeUTF8   = 0
+	encodingPlain       = 0
+	encodingRLE         = 3
+	codecGzip           = 2
+	pageTypeDataPage    = 0
+	definitionLevelNull = 0
+	definitionLevelSet  = 1
+)
+
+// parquetWriter writes the rows of the nullable strings into the parquet file
+type parquetWriter struct {
+	writer       *countingWriter
+	columns      []string
+	rowGroupSize int
+	// buffered values of the current row group per column
+	values    [][]sql.NullString
+	rowGroups []rowGroupMeta
+	numRows   int64
+}
+
+type rowGroupMeta struct {
+	columns       []columnChunkMeta
+	totalByteSize int64
+	numRows       int64
+}
+
+type columnChunkMeta struct {
+	fileOffset       int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+func newParquetWriter(w io.Writer, columns []string, rowGroupSize int) (*parquetWriter, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("the parquet file requires at least one column")
+	}
+	if rowGroupSize <= 0 {
+		rowGroupSize = defaultRowGroupSize
+	}
+	p := &parquetWriter{
+		writer:       &countingWriter{writer: w},
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+		values:       make([][]sql.NullString, len(columns)),
+	}
+	if _, err := p.writer.Write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parquetWriter) Write(row []sql.NullString) error {
+	if len(row) != len(p.columns) {
+		return fmt.Errorf("expect %d values in the row, but got %d", len(p.columns), len(row))
+	}
+	for i, value := range row {
+		p.values[i] = append(p.values[i], value)
+	}
+	if len(p.values[0]) >= p.rowGroupSize {
+		return p.flush()
+	}
+	return nil
+}
+
+// Close flushes the buffered rows and writes the footer, it doesn't close the underlying writer
+func (p *parquetWriter) Close() error {
+	if len(p.values[0]) > 0 {
+		if err := p.flush(); err != nil {
+			return err
+		}
+	}
+
+	footer := p.fileMetadata()
+	if _, err := p.writer.Write(footer); err != nil {
+		return err
+	}
+	if err := binary.Write(p.writer, binary.LittleEndian, uint32(len(footer))); err != nil {
+		return err
+	}
+	_, err := p.writer.Write([]byte(parquetMagic))
+	return err
+}
+
+// flush writes the buffered rows as a row group, each column is a column chunk with a single data page
+func (p *parquetWriter) flush() error {
+	rowGroup := rowGroupMeta{numRows: int64(len(p.values[0]))}
+	for i := range p.columns {
+		chunk, err := p.writeColumnChunk(p.values[i])
+		if err != nil {
+			return fmt.Errorf("failed to write the column %s: %w", p.columns[i], err)
+		}
+		rowGroup.columns = append(rowGroup.columns, chunk)
+		rowGroup.totalByteSize += chunk.uncompressedSize
+		p.values[i] = p.values[i][:0]
+	}
+	p.rowGroups = append(p.rowGroups, rowGroup)
+	p.numRows += rowGroup.numRows
+	return nil
+}
+
+func (p *parquetWriter) writeColumnChunk(values []sql.NullString) (columnChunkMeta, error) {
+	// the page is the definition levels followed by the non-null values
+	var page bytes.Buffer
+	levels := encodeDefinitionLevels(values)
+	_ = binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+	page.Write(levels)
+	for _, value := range values {
+		if value.Valid {
+			_ = binary.Write(&page, binary.LittleEndian, uint32(len(value.String)))
+			page.WriteString(value.String)
+		}
+	}
+
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	if _, err := gzipWriter.Write(page.Bytes()); err != nil {
+		return columnChunkMeta{}, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return columnChunkMeta{}, err
+	}
+
+	header := &compactWriter{}
+	header.structBegin()
+	header.i32Field(1, pageTypeDataPage)
+	header.i32Field(2, int32(page.Len()))
+	header.i32Field(3, int32(compressed.Len()))
+	header.structField(5)
+	header.i32Field(1, int32(len(values)))
+	header.i32Field(2, encodingPlain)
+	header.i32Field(3, encodingRLE)
+	header.i32Field(4, encodingRLE)
+	header.structEnd()
+	header.structEnd()
+
+	chunk := columnChunkMeta{
+		fileOffset:       p.writer.count,
+		numValues:        int64(len(values)),
+		uncompressedSize: int64(header.Len() + page.Len()),
+		compressedSize:   int64(header.Len() + compressed.Len()),
+	}
+	if _, err := p.writer.Write(header.Bytes()); err != nil {
+		return chunk, err
+	}
+	if _, err := p.writer.Write(compressed.Bytes()); err != nil {
+		return chunk, err
+	}
+	return chunk, nil
+}
+
+// fileMetadata encodes the FileMetaData of the footer with the thrift compact protocol
+func (p *parquetWriter) fileMetadata() []byte {
+	w := &compactWriter{}
+	w.structBegin()
+	w.i32Field(1, parquetVersion)
+
+	// the schema is the flattened tree, the root is followed by the leaf columns
+	w.listField(2, compactStruct, len(p.columns)+1)
+	w.structBegin()
+	w.i32Field(3, repetitionRequired)
+	w.stringField(4, "schema")
+	w.i32Field(5, int32(len(p.columns)))
+	w.structEnd()
+	for _, column := range p.columns {
+		w.structBegin()
+		w.i32Field(1, typeByteArray)
+		w.i32Field(3, repetitionOptional)
+		w.stringField(4, column)
+		w.i32Field(6, convertedTypeUTF8)
+		w.structEnd()
+	}
+
+	w.i64Field(3, p.numRows)
+	w.listField(4, compactStruct, len(p.rowGroups))
+	for _, rowGroup := range p.rowGroups {
+		w.structBegin()
+		w.listField(1, compactStruct, len(rowGroup.columns))
+		for i, chunk := range rowGroup.columns {
+			w.structBegin()
+			w.i64Field(2, chunk.fileOffset)
+			w.structField(3)
+			w.i32Field(1, typeByteArray)
+			w.listField(2, compactI32, 2)
+			w.i32(encodingPlain)
+			w.i32(encodingRLE)
+			w.listField(3, compactBinary, 1)
+			w.binary([]byte(p.columns[i]))
+			w.i32Field(4, codecGzip)
+			w.i64Field(5, chunk.numValues)
+			w.i64Field(6, chunk.uncompressedSize)
+			w.i64Field(7, chunk.compressedSize)
+			w.i64Field(9, chunk.fileOffset)
+			w.structEnd()
+			w.structEnd()
+		}
+		w.i64Field(2, rowGroup.totalByteSize)
+		w.i64Field(3, rowGroup.numRows)
+		w.structEnd()
+	}
+	w.stringField(6, createdBy)
+	w.structEnd()
+	return w.Bytes()
+}
+
+// encodeDefinitionLevels encodes the levels with the RLE runs of the RLE/bit-packing hybrid encoding, the bit width
+// is 1 since the max definition level of the optional column is 1
+func encodeDefinitionLevels(values []sql.NullString) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].Valid == values[i].Valid {
+			j++
+		}
+		level := byte(definitionLevelNull)
+		if values[i].Valid {
+			level = definitionLevelSet
+		}
+		writeUvarint(&buf, uint64(j-i)<<1)
+		buf.WriteByte(level)
+		i = j
+	}
+	return buf.Bytes()
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.writer.Write(b)
+	w.count += int64(n)
+	return n, err
+}
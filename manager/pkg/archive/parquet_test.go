@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := newParquetWriter(&buf, []string{"name", "payload"}, 2)
+	require.NoError(t, err)
+
+	rows := [][]sql.NullString{
+		{{String: "event1", Valid: true}, {String: `{"a": 1}`, Valid: true}},
+		{{String: "event2", Valid: true}, {}},
+		{{String: "event3", Valid: true}, {String: `{"c": 3}`, Valid: true}},
+	}
+	for _, row := range rows {
+		require.NoError(t, writer.Write(row))
+	}
+	require.Error(t, writer.Write([]sql.NullString{{}}))
+	require.NoError(t, writer.Close())
+
+	data := buf.Bytes()
+	assert.Equal(t, parquetMagic, string(data[:4]))
+	assert.Equal(t, parquetMagic, string(data[len(data)-4:]))
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4]))
+	footer := readCompactStruct(t, bytes.NewReader(data[len(data)-8-footerLen:len(data)-8]))
+
+	// the 3 rows are split into 2 row groups by the row group size
+	assert.Equal(t, int64(3), footer[3])
+	schema := footer[2].([]interface{})
+	require.Len(t, schema, 3)
+	assert.Equal(t, "payload", string(schema[2].(map[int16]interface{})[4].([]byte)))
+	rowGroups := footer[4].([]interface{})
+	require.Len(t, rowGroups, 2)
+	assert.Equal(t, int64(2), rowGroups[0].(map[int16]interface{})[3])
+	assert.Equal(t, int64(1), rowGroups[1].(map[int16]interface{})[3])
+
+	// decode the payload column of the first row group: the definition levels are [1, 0] and the value is {"a": 1}
+	chunk := rowGroups[0].(map[int16]interface{})[1].([]interface{})[1].(map[int16]interface{})
+	offset := chunk[3].(map[int16]interface{})[9].(int64)
+	pageReader := bytes.NewReader(data[offset:])
+	header := readCompactStruct(t, pageReader)
+	compressed := make([]byte, header[3].(int32))
+	_, err = io.ReadFull(pageReader, compressed)
+	require.NoError(t, err)
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	page, err := io.ReadAll(gzipReader)
+	require.NoError(t, err)
+	assert.Equal(t, int(header[2].(int32)), len(page))
+
+	levelsLen := binary.LittleEndian.Uint32(page[:4])
+	assert.Equal(t, []byte{1 << 1, 1, 1 << 1, 0}, page[4:4+levelsLen])
+	values := page[4+levelsLen:]
+	valueLen := binary.LittleEndian.Uint32(values[:4])
+	assert.Equal(t, `{"a": 1}`, string(values[4:4+valueLen]))
+}
+
+// readCompactStruct decodes the thrift compact struct into the map of the field id to the value, the nested struct
+// is the map and the list is the slice of the elements
+func readCompactStruct(t *testing.T, r *bytes.Reader) map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var lastID int16
+	for {
+		header, err := r.ReadByte()
+		require.NoError(t, err)
+		if header == 0 {
+			return fields
+		}
+		id := lastID + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(readZigzag(t, r))
+		}
+		fields[id] = readCompactValue(t, r, header&0x0f)
+		lastID = id
+	}
+}
+
+func readCompactValue(t *testing.T, r *bytes.Reader, valueType byte) interface{} {
+	switch valueType {
+	case compactI32:
+		return int32(readZigzag(t, r))
+	case compactI64:
+		return readZigzag(t, r)
+	case compactBinary:
+		size, err := binary.ReadUvarint(r)
+		require.NoError(t, err)
+		value := make([]byte, size)
+		_, err = io.ReadFull(r, value)
+		require.NoError(t, err)
+		return value
+	case compactList:
+		header, err := r.ReadByte()
+		require.NoError(t, err)
+		size := uint64(header >> 4)
+		if size == 15 {
+			size, err = binary.ReadUvarint(r)
+			require.NoError(t, err)
+		}
+		elems := []interface{}{}
+		for i := uint64(0); i < size; i++ {
+			elems = append(elems, readCompactValue(t, r, header&0x0f))
+		}
+		return elems
+	case compactStruct:
+		return readCompactStruct(t, r)
+	}
+	t.Fatalf("unexpected thrift type %d", valueType)
+	return nil
+}
+
+func readZigzag(t *testing.T, r *bytes.Reader) int64 {
+	value, err := binary.ReadUvarint(r)
+	require.NoError(t, err)
+	return int64(value>>1) ^ -int64(value&1)
+}
@@ -0,0 +1,148 @@
+package archive
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRegion    = "us-east-1"
+	amzDateFormat    = "20060102T150405Z"
+	amzDayFormat     = "20060102"
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+)
+
+// S3Config is the S3-compatible object storage of the archive, e.g. AWS S3, MinIO or ODF
+type S3Config struct {
+	// Endpoint is the host[:port] of the storage, e.g. s3.us-east-1.amazonaws.com
+	Endpoint string
+	Bucket   string
+	Region   string
+	// Prefix is prepended to the object keys of the archives
+	Prefix string
+	// Insecure uses the http instead of the https to access the storage
+	Insecure bool
+	// the credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Client puts the objects into the bucket with the path-style requests, which are signed with the signature v4
+type S3Client struct {
+	config     *S3Config
+	httpClient *http.Client
+}
+
+func NewS3Client(config *S3Config) (*S3Client, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("the endpoint and bucket of the archive storage are required")
+	}
+	if config.AccessKeyID == "" {
+		config.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if config.SecretAccessKey == "" {
+		config.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("the credentials of the archive storage are required")
+	}
+	if config.Region == "" {
+		config.Region = defaultRegion
+	}
+	return &S3Client{config: config, httpClient: &http.Client{Timeout: 10 * time.Minute}}, nil
+}
+
+// objectURL returns the path-style url of the object
+func (c *S3Client) objectURL(key string) string {
+	scheme := "https"
+	if c.config.Insecure {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/%s/%s", scheme, c.config.Endpoint, c.config.Bucket, escapePath(key))
+}
+
+// PutObject uploads the content with the size and sha256 checksum in hex
+func (c *S3Client) PutObject(ctx context.Context, key, contentType string, body io.Reader, size int64,
+	checksum string,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, checksum, time.Now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to put the object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to put the object %s: %s %s", key, resp.Status, string(message))
+	}
+	return nil
+}
+
+// Config returns the storage of the client, the defaults are completed
+func (c *S3Client) Config() *S3Config {
+	return c.config
+}
+
+// sign adds the authorization header of the signature v4 to the request
+// see https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func (c *S3Client) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format(amzDateFormat)
+	day := now.Format(amzDayFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		req.Header.Get("Content-Type"), req.URL.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonicalHeaders, signedHeaders, payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", day, c.config.Region)
+	stringToSign := strings.Join([]string{signingAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), day)
+	key = hmacSHA256(key, c.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapePath escapes each segment of the object key, the slashes are kept as the delimiters
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
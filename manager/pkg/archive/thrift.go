@@ -0,0 +1,95 @@
+package archive
+
+import (
+	"bytes"
+)
+
+// the element types of the thrift compact protocol
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the parquet metadata with the thrift compact protocol. The fields of a struct must be written
+// in the ascending order of their ids, since the field header is the delta from the previous field id.
+type compactWriter struct {
+	bytes.Buffer
+	lastFieldIDs []int16
+}
+
+func (w *compactWriter) structBegin() {
+	w.lastFieldIDs = append(w.lastFieldIDs, 0)
+}
+
+func (w *compactWriter) structEnd() {
+	w.WriteByte(0)
+	w.lastFieldIDs = w.lastFieldIDs[:len(w.lastFieldIDs)-1]
+}
+
+func (w *compactWriter) fieldHeader(id int16, fieldType byte) {
+	last := len(w.lastFieldIDs) - 1
+	delta := id - w.lastFieldIDs[last]
+	if delta > 0 && delta <= 15 {
+		w.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.WriteByte(fieldType)
+		writeUvarint(&w.Buffer, zigzag(int64(id)))
+	}
+	w.lastFieldIDs[last] = id
+}
+
+func (w *compactWriter) i32Field(id int16, value int32) {
+	w.fieldHeader(id, compactI32)
+	w.i32(value)
+}
+
+func (w *compactWriter) i64Field(id int16, value int64) {
+	w.fieldHeader(id, compactI64)
+	writeUvarint(&w.Buffer, zigzag(value))
+}
+
+func (w *compactWriter) stringField(id int16, value string) {
+	w.fieldHeader(id, compactBinary)
+	w.binary([]byte(value))
+}
+
+// structField writes the header of the nested struct field, it must be ended by the structEnd
+func (w *compactWriter) structField(id int16) {
+	w.fieldHeader(id, compactStruct)
+	w.structBegin()
+}
+
+// listField writes the header of the list field, it's followed by the size elements
+func (w *compactWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.WriteByte(0xf0 | elemType)
+		writeUvarint(&w.Buffer, uint64(size))
+	}
+}
+
+func (w *compactWriter) i32(value int32) {
+	writeUvarint(&w.Buffer, zigzag(int64(value)))
+}
+
+func (w *compactWriter) binary(value []byte) {
+	writeUvarint(&w.Buffer, uint64(len(value)))
+	w.Write(value)
+}
+
+func zigzag(value int64) uint64 {
+	return uint64((value << 1) ^ (value >> 63))
+}
+
+func writeUvarint(buf *bytes.Buffer, value uint64) {
+	for value >= 0x80 {
+		buf.WriteByte(byte(value) | 0x80)
+		value >>= 7
+	}
+	buf.WriteByte(byte(value))
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clustergroup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// ErrInvalidGroup is returned if the cluster group is malformed
+var ErrInvalidGroup = errors.New("invalid cluster group")
+
+// RefreshInterval is the interval to refresh the members of all the groups, so they follow the changes of the
+// managed clusters, e.g. the labels of them are changed
+const RefreshInterval = time.Minute
+
+// Group is the cluster group, the members are the managed clusters matching the label selector, and the explicit
+// clusters, which are either "<hub>/<cluster>" or "<cluster>" of any hub
+type Group struct {
+	Name          string
+	Description   string
+	LabelSelector string
+	Clusters      []string
+}
+
+// FromModel converts the database row to the group
+func FromModel(row *models.ClusterGroup) (*Group, error) {
+	group := &Group{
+		Name:          row.Name,
+		Description:   row.Description,
+		LabelSelector: row.LabelSelector,
+		Clusters:      []string{},
+	}
+	if len(row.Clusters) > 0 {
+		if err := json.Unmarshal(row.Clusters, &group.Clusters); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal the clusters of the group %s: %w", row.Name, err)
+		}
+	}
+	return group, nil
+}
+
+// ToModel converts the group to the database row
+func (g *Group) ToModel() (*models.ClusterGroup, error) {
+	clusters := g.Clusters
+	if clusters == nil {
+		clusters = []string{}
+	}
+	data, err := json.Marshal(clusters)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ClusterGroup{
+		Name:          g.Name,
+		Description:   g.Description,
+		LabelSelector: g.LabelSelector,
+		Clusters:      data,
+	}, nil
+}
+
+// Validate returns ErrInvalidGroup if the name, the label selector or the explicit clusters are malformed, the group
+// must select the clusters by at least one of them
+func (g *Group) Validate() error {
+	if errs := validation.IsDNS1123Label(g.Name); len(errs) > 0 {
+		return fmt.Errorf("%w: name %q: %s", ErrInvalidGroup, g.Name, strings.Join(errs, ", "))
+	}
+	if g.LabelSelector == "" && len(g.Clusters) == 0 {
+		return fmt.Errorf("%w: either labelSelector or clusters is required", ErrInvalidGroup)
+	}
+	if _, err := labels.Parse(g.LabelSelector); err != nil {
+		return fmt.Errorf("%w: labelSelector %q: %v", ErrInvalidGroup, g.LabelSelector, err)
+	}
+	for _, cluster := range g.Clusters {
+		parts := strings.Split(cluster, "/")
+		if len(parts) > 2 || parts[0] == "" || parts[len(parts)-1] == "" {
+			return fmt.Errorf("%w: cluster %q must be <hub>/<cluster> or <cluster>", ErrInvalidGroup, cluster)
+		}
+	}
+	return nil
+}
+
+// cluster is the managed cluster to be matched by the groups
+type cluster struct {
+	LeafHubName string
+	ClusterID   string
+	ClusterName string
+	Labels      map[string]string
+}
+
+// match returns the members of the group from the clusters
+func (g *Group) match(clusters []cluster) ([]models.ClusterGroupMember, error) {
+	var selector labels.Selector
+	if g.LabelSelector != "" {
+		var err error
+		if selector, err = labels.Parse(g.LabelSelector); err != nil {
+			return nil, fmt.Errorf("%w: labelSelector %q: %v", ErrInvalidGroup, g.LabelSelector, err)
+		}
+	}
+	explicit := make(map[string]bool, len(g.Clusters))
+	for _, name := range g.Clusters {
+		explicit[name] = true
+	}
+
+	members := []models.ClusterGroupMember{}
+	for _, c := range clusters {
+		if !explicit[c.ClusterName] && !explicit[c.LeafHubName+"/"+c.ClusterName] &&
+			(selector == nil || !selector.Matches(labels.Set(c.Labels))) {
+			continue
+		}
+		members = append(members, models.ClusterGroupMember{
+			GroupName:   g.Name,
+			LeafHubName: c.LeafHubName,
+			ClusterID:   c.ClusterID,
+			ClusterName: c.ClusterName,
+		})
+	}
+	return members, nil
+}
+
+// listClusters returns the managed clusters which aren't deleted
+func listClusters(ctx context.Context, db *gorm.DB) ([]cluster, error) {
+	var rows []struct {
+		LeafHubName string
+		ClusterID   string
+		ClusterName string
+		Labels      []byte
+	}
+	err := db.WithContext(ctx).Model(&models.ManagedCluster{}).
+		Select("leaf_hub_name, cluster_id, payload->'metadata'->>'name' AS cluster_name, " +
+			"payload->'metadata'->'labels' AS labels").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the managed clusters: %w", err)
+	}
+	clusters := make([]cluster, 0, len(rows))
+	for _, row := range rows {
+		c := cluster{LeafHubName: row.LeafHubName, ClusterID: row.ClusterID, ClusterName: row.ClusterName}
+		if len(row.Labels) > 0 {
+			if err := json.Unmarshal(row.Labels, &c.Labels); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal the labels of the cluster %s: %w", row.ClusterName, err)
+			}
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters, nil
+}
+
+// Refresh replaces the members of the groups with the current managed clusters, all the groups are refreshed if
+// the names are empty
+func Refresh(ctx context.Context, db *gorm.DB, names ...string) error {
+	query := db.WithContext(ctx)
+	if len(names) > 0 {
+		query = query.Where("name IN ?", names)
+	}
+	var rows []models.ClusterGroup
+	if err := query.Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to list the cluster groups: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	clusters, err := listClusters(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		group, err := FromModel(&rows[i])
+		if err != nil {
+			return err
+		}
+		members, err := group.match(clusters)
+		if err != nil {
+			return err
+		}
+		err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("group_name = ?", group.Name).Delete(&models.ClusterGroupMember{}).Error; err != nil {
+				return err
+			}
+			if len(members) == 0 {
+				return nil
+			}
+			return tx.CreateInBatches(members, 500).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to refresh the members of the cluster group %s: %w", group.Name, err)
+		}
+	}
+	return nil
+}
+
+// membersRefresher refreshes the members of all the groups periodically
+type membersRefresher struct {
+	log      logr.Logger
+	interval time.Duration
+}
+
+// AddMembersRefresher adds the refresher of the cluster group members into the manager
+func AddMembersRefresher(mgr ctrl.Manager) error {
+	return mgr.Add(&membersRefresher{
+		log:      ctrl.Log.WithName("cluster-group"),
+		interval: RefreshInterval,
+	})
+}
+
+func (r *membersRefresher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := Refresh(ctx, database.GetGorm()); err != nil {
+				r.log.Error(err, "failed to refresh the members of the cluster groups")
+			}
+		}
+	}
+}
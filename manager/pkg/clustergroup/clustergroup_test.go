@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clustergroup
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		group Group
+		valid bool
+	}{
+		{"label selector", Group{Name: "prod", LabelSelector: "env=prod,region in (us,eu)"}, true},
+		{"explicit clusters", Group{Name: "canary", Clusters: []string{"hub1/cluster1", "cluster2"}}, true},
+		{"invalid name", Group{Name: "Prod_Clusters", LabelSelector: "env=prod"}, false},
+		{"no members", Group{Name: "empty"}, false},
+		{"invalid selector", Group{Name: "prod", LabelSelector: "env in prod"}, false},
+		{"invalid cluster", Group{Name: "canary", Clusters: []string{"hub1/"}}, false},
+		{"nested cluster", Group{Name: "canary", Clusters: []string{"a/b/c"}}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.group.Validate()
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidGroup), "unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	clusters := []cluster{
+		{LeafHubName: "hub1", ClusterID: "1", ClusterName: "cluster1", Labels: map[string]string{"env": "prod"}},
+		{LeafHubName: "hub1", ClusterID: "2", ClusterName: "cluster2", Labels: map[string]string{"env": "dev"}},
+		{LeafHubName: "hub2", ClusterID: "3", ClusterName: "cluster3", Labels: map[string]string{"env": "prod"}},
+		{LeafHubName: "hub2", ClusterID: "4", ClusterName: "cluster2"},
+	}
+	memberIDs := func(members []models.ClusterGroupMember) []string {
+		ids := []string{}
+		for _, member := range members {
+			ids = append(ids, member.ClusterID)
+		}
+		return ids
+	}
+
+	members, err := (&Group{Name: "prod", LabelSelector: "env=prod"}).match(clusters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, memberIDs(members))
+	assert.Equal(t, "prod", members[0].GroupName)
+	assert.Equal(t, "hub2", members[1].LeafHubName)
+
+	// the cluster name without the hub matches the clusters of all the hubs
+	members, err = (&Group{Name: "cluster2", Clusters: []string{"cluster2"}}).match(clusters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "4"}, memberIDs(members))
+
+	// the members are the union of the label selector and the explicit clusters
+	members, err = (&Group{Name: "mixed", LabelSelector: "env=dev", Clusters: []string{"hub2/cluster2"}}).
+		match(clusters)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "4"}, memberIDs(members))
+}
+
+func TestModelConversion(t *testing.T) {
+	group := &Group{Name: "canary", Description: "the canary clusters", Clusters: []string{"hub1/cluster1"}}
+	row, err := group.ToModel()
+	require.NoError(t, err)
+	assert.JSONEq(t, `["hub1/cluster1"]`, string(row.Clusters))
+
+	converted, err := FromModel(row)
+	require.NoError(t, err)
+	assert.Equal(t, group, converted)
+
+	converted, err = FromModel(&models.ClusterGroup{Name: "prod", LabelSelector: "env=prod"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{}, converted.Clusters)
+}
@@ -6,7 +6,10 @@ package config
 import (
 	"time"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/archive"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/inventory"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/upstream"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -16,6 +19,7 @@ type ManagerConfig struct {
 	ManagerNamespace      string
 	WatchNamespace        string
 	SchedulerInterval     string
+	SchedulerConfig       *SchedulerConfig
 	SyncerConfig          *SyncerConfig
 	DatabaseConfig        *DatabaseConfig
 	TransportConfig       *transport.TransportConfig
@@ -24,12 +28,95 @@ type ManagerConfig struct {
 	ElectionConfig        *commonobjects.LeaderElectionConfig
 	EnableGlobalResource  bool
 	LaunchJobNames        string
+	// NotificationConfigPath is the file of the notification sinks and rules, empty means disabled
+	NotificationConfigPath string
+	// EventExporterConfigPath is the file of the sinks which the fleet events are exported to as the cloudevents, empty
+	// means disabled
+	EventExporterConfigPath string
+	// ReportConfigPath is the file of the scheduled compliance reports, empty means the reports are only generated by
+	// the api
+	ReportConfigPath string
+	// EnableHubSharding partitions the hubs among the manager replicas, each replica only consumes the status of its
+	// own hubs. It requires the status topic per hub
+	EnableHubSharding bool
+	// EnableWarmStandby runs the status consumer on the standby replicas with the paused partitions, so the standby
+	// takes over the status in seconds once it's elected as the leader
+	EnableWarmStandby bool
+	// HubInactiveTimeout is the silence window of the hub heartbeat, the hub is marked as inactive after it
+	HubInactiveTimeout time.Duration
+	// DetachedHubRetention is how long the data of the detached hub is kept before it's purged from the database
+	DetachedHubRetention time.Duration
+	// MigrationPhaseTimeout is how long each phase of the managed cluster migration may take before it's failed
+	MigrationPhaseTimeout time.Duration
+	// ArchiveConfig is the object storage to archive the expired partitions, the archive is disabled if the bucket is
+	// empty
+	ArchiveConfig *archive.S3Config
+	// UpstreamConfig is the upstream global hub which the summaries of the managed hubs are forwarded to, the global
+	// hub is a regional global hub of the hierarchical topology if it's configured
+	UpstreamConfig *upstream.UpstreamConfig
+	// InventoryConfig is the inventory API which the managed clusters and the policies are exported to, the export is
+	// disabled if the server url is empty
+	InventoryConfig *inventory.InventoryConfig
+	// SigningPublicKeysDir is the directory of the public keys named by the managed hubs, the status events are
+	// verified with them before they're persisted if it's specified
+	SigningPublicKeysDir string
+	// MaxAgentMinorVersionSkew is the number of the minor versions the agents may fall behind the manager, the agents
+	// out of it are incompatible
+	MaxAgentMinorVersionSkew int
+	// QuarantineIncompatibleAgents doesn't persist the status events of the incompatible agents except the heartbeat
+	QuarantineIncompatibleAgents bool
+	// EnableSchemaValidation rejects the status events which don't match the schemas of their versions, and saves
+	// them to the dead letter table
+	EnableSchemaValidation bool
+	// DataCollectionProfile decides the status bundles collected by the agents, it's Minimal, Standard or Full
+	DataCollectionProfile string
+	// HubEventsPerSecond is the rate of the status events admitted from each managed hub, the complete state events
+	// beyond it and the HubEventBurst are dropped. The rate isn't limited if it's not positive, which is the default
+	HubEventsPerSecond float64
+	HubEventBurst      int
+	// DataFreshnessExpectedIntervals are the intervals the bundles are expected to be received in, keyed by the bundle
+	// types without the common prefix, e.g. managedhub.heartbeat. It's parsed from the raw intervals of the flag
+	DataFreshnessExpectedIntervals    map[string]time.Duration
+	RawDataFreshnessExpectedIntervals map[string]string
+	// LogLevelConfigMap is the configmap of the log levels in the manager namespace, they're changed at runtime by it
+	LogLevelConfigMap string
+	// ConflationConfigMap is the configmap of the conflation parameters in the manager namespace, they're changed at
+	// runtime by it
+	ConflationConfigMap string
+	// MetricsSecure serves the metrics with https, and only the requests authenticated by the TokenReview and
+	// authorized by the SubjectAccessReview of the "/metrics" are allowed
+	MetricsSecure bool
+	// MetricsCertDir is the directory of the serving certificate(tls.crt and tls.key) of the secure metrics, the
+	// self-signed certificate is generated if it's empty or the certificate isn't found
+	MetricsCertDir string
+	// EnableDiagnostics serves the pprof, expvar and the diagnostics bundle under /debug of the metrics server, the
+	// requests are authenticated and authorized as the metrics
+	EnableDiagnostics bool
+	// AdditionalKafkaConfigPath is the file of the additional kafka clusters which the status is consumed from, empty
+	// means only the default kafka cluster is consumed
+	AdditionalKafkaConfigPath string
+	// AdditionalTransportConfigs are loaded from the AdditionalKafkaConfigPath
+	AdditionalTransportConfigs []*transport.TransportConfig
+	// EnrichmentConfigPath is the file of the fields computed from the labels and claims of the managed clusters, empty
+	// means only the enrichers registered in the code are run
+	EnrichmentConfigPath string
+}
+
+// SchedulerConfig is the cron schedules of the jobs, the default schedule of the job is used if it's empty
+type SchedulerConfig struct {
+	TimeZone                       string
+	LocalComplianceHistorySchedule string
+	DataRetentionSchedule          string
 }
 
 type SyncerConfig struct {
 	SpecSyncInterval              time.Duration
 	StatusSyncInterval            time.Duration
 	DeletedLabelsTrimmingInterval time.Duration
+	// SpecDriftDetectionInterval is the interval to compare the global resources with the managed hubs, 0 disables it
+	SpecDriftDetectionInterval time.Duration
+	// SpecDriftRemediation resends the global resources to the managed hubs once the drift is detected twice in a row
+	SpecDriftRemediation bool
 }
 
 type DatabaseConfig struct {
@@ -38,4 +125,10 @@ type DatabaseConfig struct {
 	CACertPath                 string
 	MaxOpenConns               int
 	DataRetention              int
+	// the retry and circuit breaker of the database access
+	RetryAttempts           int
+	CircuitFailureThreshold int
+	CircuitOpenDuration     time.Duration
+	// the queries which take longer than it are logged, it's disabled if it's 0
+	SlowQueryThreshold time.Duration
 }
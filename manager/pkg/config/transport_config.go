@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// AdditionalKafkaConfig is the additional kafka clusters which the status of the managed hubs is consumed from besides
+// the default one, e.g. each region has its own brokers while the status is persisted in the same database
+//
+//	clusters:
+//	- identity: region-east
+//	  bootstrapServer: east-kafka.example.com:9093
+//	  caCertPath: /kafka/east/ca.crt
+//	  clientCertPath: /kafka/east/client.crt
+//	  clientKeyPath: /kafka/east/client.key
+type AdditionalKafkaConfig struct {
+	Clusters []KafkaClusterConfig `json:"clusters"`
+}
+
+type KafkaClusterConfig struct {
+	// Identity tells the offsets of the cluster from the ones of the other clusters, it must be unique
+	Identity        string `json:"identity"`
+	BootstrapServer string `json:"bootstrapServer"`
+	CACertPath      string `json:"caCertPath,omitempty"`
+	ClientCertPath  string `json:"clientCertPath,omitempty"`
+	ClientKeyPath   string `json:"clientKeyPath,omitempty"`
+	// the topics are the same as the ones of the default kafka cluster if they're empty
+	StatusTopic string `json:"statusTopic,omitempty"`
+	EventTopic  string `json:"eventTopic,omitempty"`
+}
+
+// LoadAdditionalTransportConfigs reads the additional kafka clusters from the file, the consumer of them inherits the
+// consumer id, the offset reset policy and the topics from the default transport config
+func LoadAdditionalTransportConfigs(path string, defaultConfig *transport.TransportConfig,
+) ([]*transport.TransportConfig, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the additional kafka config %s: %w", path, err)
+	}
+	config := &AdditionalKafkaConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the additional kafka config %s: %w", path, err)
+	}
+
+	defaultKafka := defaultConfig.KafkaConfig
+	identities := map[string]bool{defaultKafka.ClusterIdentity: true}
+	transportConfigs := []*transport.TransportConfig{}
+	for _, cluster := range config.Clusters {
+		if cluster.Identity == "" || cluster.BootstrapServer == "" {
+			return nil, fmt.Errorf("the identity and bootstrap server of the additional kafka cluster are required")
+		}
+		if identities[cluster.Identity] {
+			return nil, fmt.Errorf("the identity %s of the additional kafka cluster is duplicated", cluster.Identity)
+		}
+		identities[cluster.Identity] = true
+
+		topics := &transport.ClusterTopic{
+			StatusTopic: defaultKafka.Topics.StatusTopic,
+			EventTopic:  defaultKafka.Topics.EventTopic,
+		}
+		if cluster.StatusTopic != "" {
+			topics.StatusTopic = cluster.StatusTopic
+		}
+		if cluster.EventTopic != "" {
+			topics.EventTopic = cluster.EventTopic
+		}
+		transportConfigs = append(transportConfigs, &transport.TransportConfig{
+			TransportType:          string(transport.Kafka),
+			MessageCompressionType: defaultConfig.MessageCompressionType,
+			CommitterInterval:      defaultConfig.CommitterInterval,
+			KafkaConfig: &transport.KafkaConfig{
+				ClusterIdentity: cluster.Identity,
+				BootstrapServer: cluster.BootstrapServer,
+				CaCertPath:      cluster.CACertPath,
+				ClientCertPath:  cluster.ClientCertPath,
+				ClientKeyPath:   cluster.ClientKeyPath,
+				EnableTLS:       cluster.CACertPath != "",
+				Topics:          topics,
+				ConsumerConfig: &transport.KafkaConsumerConfig{
+					ConsumerID:  defaultKafka.ConsumerConfig.ConsumerID,
+					OffsetReset: defaultKafka.ConsumerConfig.OffsetReset,
+				},
+			},
+		})
+	}
+	return transportConfigs, nil
+}
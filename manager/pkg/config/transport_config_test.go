@@ -0,0 +1,58 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestLoadAdditionalTransportConfigs(t *testing.T) {
+	defaultConfig := &transport.TransportConfig{
+		TransportType: string(transport.Kafka),
+		KafkaConfig: &transport.KafkaConfig{
+			ClusterIdentity: "default",
+			Topics:          &transport.ClusterTopic{StatusTopic: "^status.*", EventTopic: "event"},
+			ConsumerConfig:  &transport.KafkaConsumerConfig{ConsumerID: "global-hub-manager"},
+		},
+	}
+	writeConfig := func(content string) string {
+		path := filepath.Join(t.TempDir(), "kafka.yaml")
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	configs, err := LoadAdditionalTransportConfigs(writeConfig(`
+clusters:
+- identity: region-east
+  bootstrapServer: east-kafka.example.com:9093
+  caCertPath: /kafka/east/ca.crt
+- identity: region-west
+  bootstrapServer: west-kafka.example.com:9092
+  eventTopic: west.event
+`), defaultConfig)
+	assert.Nil(t, err)
+	assert.Len(t, configs, 2)
+	assert.Equal(t, "region-east", configs[0].KafkaConfig.ClusterIdentity)
+	assert.True(t, configs[0].KafkaConfig.EnableTLS)
+	assert.Equal(t, "^status.*", configs[0].KafkaConfig.Topics.StatusTopic)
+	assert.Equal(t, "global-hub-manager", configs[0].KafkaConfig.ConsumerConfig.ConsumerID)
+	assert.False(t, configs[1].KafkaConfig.EnableTLS)
+	assert.Equal(t, "west.event", configs[1].KafkaConfig.Topics.EventTopic)
+
+	_, err = LoadAdditionalTransportConfigs(writeConfig(`
+clusters:
+- identity: default
+  bootstrapServer: east-kafka.example.com:9093
+`), defaultConfig)
+	assert.ErrorContains(t, err, "duplicated")
+
+	_, err = LoadAdditionalTransportConfigs(writeConfig(`
+clusters:
+- bootstrapServer: east-kafka.example.com:9093
+`), defaultConfig)
+	assert.ErrorContains(t, err, "required")
+}
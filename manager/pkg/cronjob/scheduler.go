@@ -2,6 +2,7 @@ package cronjob
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
@@ -33,23 +35,39 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	managerConfig *config.ManagerConfig, enableSimulation bool,
 ) error {
 	log := ctrl.Log.WithName("cronjob-scheduler")
+	schedulerConfig := managerConfig.SchedulerConfig
+	if schedulerConfig == nil {
+		schedulerConfig = &config.SchedulerConfig{}
+	}
 	// Scheduler timezone:
-	// The cluster may be in a different timezones, Here we choose to be consistent with the local GH timezone.
-	scheduler := gocron.NewScheduler(time.Local)
+	// The cluster may be in a different timezones, Here we choose to be consistent with the local GH timezone unless
+	// the timezone is specified.
+	location := time.Local
+	if schedulerConfig.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedulerConfig.TimeZone); err != nil {
+			return fmt.Errorf("invalid scheduler timezone %s: %w", schedulerConfig.TimeZone, err)
+		}
+	}
+	scheduler := gocron.NewScheduler(location)
 
-	switch managerConfig.SchedulerInterval {
-	case EveryMonth:
-		scheduler = scheduler.Every(1).Month(1)
-	case EveryWeek:
-		scheduler = scheduler.Every(1).Week()
-	case EveryHour:
-		scheduler = scheduler.Every(1).Hour()
-	case EveryMinute:
-		scheduler = scheduler.Every(1).Minute()
-	case EverySecond:
-		scheduler = scheduler.Every(1).Second()
-	default:
-		scheduler = scheduler.Every(1).Day().At("00:00")
+	if schedulerConfig.LocalComplianceHistorySchedule != "" {
+		scheduler = scheduler.Cron(schedulerConfig.LocalComplianceHistorySchedule)
+	} else {
+		switch managerConfig.SchedulerInterval {
+		case EveryMonth:
+			scheduler = scheduler.Every(1).Month(1)
+		case EveryWeek:
+			scheduler = scheduler.Every(1).Week()
+		case EveryHour:
+			scheduler = scheduler.Every(1).Hour()
+		case EveryMinute:
+			scheduler = scheduler.Every(1).Minute()
+		case EverySecond:
+			scheduler = scheduler.Every(1).Second()
+		default:
+			scheduler = scheduler.Every(1).Day().At("00:00")
+		}
 	}
 	complianceJob, err := scheduler.Tag(task.LocalComplianceTaskName).DoWithJobDetails(
 		task.SyncLocalCompliance, ctx, enableSimulation)
@@ -58,13 +76,54 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	}
 	log.Info("set SyncLocalCompliance job", "scheduleAt", complianceJob.ScheduledAtTime())
 
-	dataRetentionJob, err := scheduler.Every(1).Month(1, 15, 28).At("00:00").Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+	if schedulerConfig.DataRetentionSchedule != "" {
+		scheduler = scheduler.Cron(schedulerConfig.DataRetentionSchedule)
+	} else {
+		scheduler = scheduler.Every(1).Month(1, 15, 28).At("00:00")
+	}
+	var archiver *archive.Archiver
+	if managerConfig.ArchiveConfig != nil && managerConfig.ArchiveConfig.Bucket != "" {
+		if archiver, err = archive.NewArchiver(managerConfig.ArchiveConfig); err != nil {
+			return fmt.Errorf("failed to create the archiver: %w", err)
+		}
+		log.Info("archive the expired partitions", "endpoint", managerConfig.ArchiveConfig.Endpoint,
+			"bucket", managerConfig.ArchiveConfig.Bucket)
+	}
+	dataRetentionJob, err := scheduler.Tag(task.RetentionTaskName).
+		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention, archiver)
 	if err != nil {
 		return err
 	}
 	log.Info("set DataRetention job", "scheduleAt", dataRetentionJob.ScheduledAtTime())
 
+	detachedHubJob, err := scheduler.Every(1).Hour().Tag(task.DetachedHubCleanupTaskName).
+		DoWithJobDetails(task.CleanupDetachedHubs, ctx, mgr.GetAPIReader(), managerConfig.DetachedHubRetention)
+	if err != nil {
+		return err
+	}
+	log.Info("set CleanupDetachedHubs job", "scheduleAt", detachedHubJob.ScheduledAtTime())
+
+	partitionJob, err := scheduler.Every(1).Hour().Tag(task.PartitionManagerTaskName).
+		DoWithJobDetails(task.ManagePartitions, ctx, managerConfig.DatabaseConfig.DataRetention)
+	if err != nil {
+		return err
+	}
+	log.Info("set ManagePartitions job", "scheduleAt", partitionJob.ScheduledAtTime())
+
+	consistencyJob, err := scheduler.Every(10).Minutes().Tag(task.DataConsistencyTaskName).
+		DoWithJobDetails(task.CheckDataConsistency, ctx)
+	if err != nil {
+		return err
+	}
+	log.Info("set CheckDataConsistency job", "scheduleAt", consistencyJob.ScheduledAtTime())
+
+	storageUsageJob, err := scheduler.Every(1).Day().At("02:00").Tag(task.StorageUsageTaskName).
+		DoWithJobDetails(task.MeasureStorageUsage, ctx)
+	if err != nil {
+		return err
+	}
+	log.Info("set MeasureStorageUsage job", "scheduleAt", storageUsageJob.ScheduledAtTime())
+
 	return mgr.Add(&GlobalHubJobScheduler{
 		log:                   log,
 		scheduler:             scheduler,
@@ -77,6 +136,10 @@ func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 	// Set the status of the job to 0 (success) when the job is started.
 	monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(task.RetentionTaskName).Set(0)
 	monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(task.LocalComplianceTaskName).Set(0)
+	monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(task.DetachedHubCleanupTaskName).Set(0)
+	monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(task.DataConsistencyTaskName).Set(0)
+	monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(task.PartitionManagerTaskName).Set(0)
+	monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(task.StorageUsageTaskName).Set(0)
 	s.scheduler.StartAsync()
 	if err := s.execJobs(ctx); err != nil {
 		return err
@@ -89,7 +152,8 @@ func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 func (s *GlobalHubJobScheduler) execJobs(ctx context.Context) error {
 	for _, job := range s.launchImmediatelyJobs {
 		switch job {
-		case task.LocalComplianceTaskName, task.RetentionTaskName:
+		case task.LocalComplianceTaskName, task.RetentionTaskName, task.DetachedHubCleanupTaskName,
+			task.DataConsistencyTaskName, task.PartitionManagerTaskName, task.StorageUsageTaskName:
 			s.log.Info("launch the job", "name", job)
 			if err := s.scheduler.RunByTag(job); err != nil {
 				return err
@@ -63,6 +63,19 @@ func TestScheduler(t *testing.T) {
 	managerConfig.SchedulerInterval = "second"
 	assert.Nil(t, AddSchedulerToManager(ctx, mgr, managerConfig, false))
 
+	managerConfig.SchedulerConfig = &config.SchedulerConfig{
+		TimeZone:                       "America/New_York",
+		LocalComplianceHistorySchedule: "30 1 * * *",
+		DataRetentionSchedule:          "0 2 1 * *",
+	}
+	assert.Nil(t, AddSchedulerToManager(ctx, mgr, managerConfig, false))
+	managerConfig.SchedulerConfig.TimeZone = "Unknown/Zone"
+	assert.NotNil(t, AddSchedulerToManager(ctx, mgr, managerConfig, false))
+	managerConfig.SchedulerConfig.TimeZone = ""
+	managerConfig.SchedulerConfig.DataRetentionSchedule = "every month"
+	assert.NotNil(t, AddSchedulerToManager(ctx, mgr, managerConfig, false))
+	managerConfig.SchedulerConfig = nil
+
 	scheduler := gocron.NewScheduler(time.Local)
 	_, err = scheduler.Every(1).Day().At("00:00").Tag(task.LocalComplianceTaskName).DoWithJobDetails(
 		task.SyncLocalCompliance, ctx, false)
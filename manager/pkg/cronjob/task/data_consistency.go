@@ -0,0 +1,103 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var (
+	// The job compares the resource counts reported by the agents with the records of the hubs in the database, the
+	// discrepancies are recorded in the status.hub_resource_counts table and the metric, so that the lost messages are
+	// detected rather than discovered during an audit.
+	DataConsistencyTaskName = "data-consistency"
+
+	// the counts reported within the grace period are checked in the next run, since the resources might not be
+	// persisted yet
+	consistencyGracePeriod = 2 * time.Minute
+
+	// the queries to count the records of the hub in the database for each resource type
+	storedCountQueries = map[string]string{
+		cluster.ResourceManagedClusters: `SELECT count(*) FROM status.managed_clusters
+			WHERE leaf_hub_name = ? AND deleted_at IS NULL`,
+		// the kyverno policies collected from the policy reports aren't counted by the agent
+		cluster.ResourceLocalPolicies: `SELECT count(*) FROM local_spec.policies
+			WHERE leaf_hub_name = ? AND deleted_at IS NULL
+			AND payload->>'apiVersion' IS DISTINCT FROM '` + grc.PolicyReportAPIVersion + `'`,
+	}
+	consistencyLog = ctrl.Log.WithName(DataConsistencyTaskName)
+)
+
+func CheckDataConsistency(ctx context.Context, job gocron.Job) {
+	startAt := time.Now()
+	var err error
+	defer func() {
+		updateJobStatus(DataConsistencyTaskName, startAt, job, err)
+	}()
+
+	db := database.GetGorm()
+	// the inactive and detached hubs aren't checked, their records are removed from the tables
+	activeHubs := db.Model(&models.LeafHubHeartbeat{}).Select("leaf_hub_name").
+		Where("status = ?", hubmanagement.HubActive)
+	var counts []models.HubResourceCount
+	if err = db.Where("leaf_hub_name IN (?)", activeHubs).Find(&counts).Error; err != nil {
+		consistencyLog.Error(err, "failed to list the resource counts of the hubs")
+		return
+	}
+
+	monitoring.GlobalHubDataInconsistencyGaugeVec.Reset()
+	inconsistent := 0
+	for _, count := range counts {
+		query, found := storedCountQueries[count.ResourceType]
+		if !found {
+			continue
+		}
+		if count.ReportedAt.After(startAt.Add(-consistencyGracePeriod)) {
+			// keep the result of the last check
+			if count.StoredCount != nil {
+				monitoring.GlobalHubDataInconsistencyGaugeVec.WithLabelValues(count.LeafHubName, count.ResourceType).
+					Set(float64(count.ReportedCount - *count.StoredCount))
+			}
+			continue
+		}
+
+		var stored int
+		if e := db.Raw(query, count.LeafHubName).Scan(&stored).Error; e != nil {
+			err = e
+			consistencyLog.Error(e, "failed to count the records", "hub", count.LeafHubName,
+				"type", count.ResourceType)
+			continue
+		}
+		consistent := stored == count.ReportedCount
+		if !consistent {
+			inconsistent++
+			consistencyLog.Info("the records are inconsistent with the hub", "hub", count.LeafHubName,
+				"type", count.ResourceType, "reported", count.ReportedCount, "stored", stored)
+		}
+		monitoring.GlobalHubDataInconsistencyGaugeVec.WithLabelValues(count.LeafHubName, count.ResourceType).
+			Set(float64(count.ReportedCount - stored))
+
+		e := db.Model(&models.HubResourceCount{}).
+			Where("leaf_hub_name = ? AND resource_type = ?", count.LeafHubName, count.ResourceType).
+			Updates(map[string]interface{}{
+				"stored_count": stored,
+				"consistent":   consistent,
+				"checked_at":   startAt,
+			}).Error
+		if e != nil {
+			err = e
+			consistencyLog.Error(e, "failed to update the consistency", "hub", count.LeafHubName,
+				"type", count.ResourceType)
+		}
+	}
+	consistencyLog.Info("finish running", "inconsistent", inconsistent, "nextRun", job.NextRun().Format(timeFormat))
+}
@@ -0,0 +1,80 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var _ = Describe("data consistency job", Ordered, func() {
+	hubName := "consistency-hub"
+
+	BeforeAll(func() {
+		By("Create the active hub with 2 managed clusters in the database")
+		Expect(db.Create(&models.LeafHubHeartbeat{
+			Name:         hubName,
+			Status:       hubmanagement.HubActive,
+			LastUpdateAt: time.Now(),
+		}).Error).To(Succeed())
+		Expect(db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			(?, gen_random_uuid(), '{"metadata": {"name": "cluster1"}}', 'none'),
+			(?, gen_random_uuid(), '{"metadata": {"name": "cluster2"}}', 'none')`, hubName, hubName).Error).
+			To(Succeed())
+
+		By("Create the counts reported by the agent")
+		reportedAt := time.Now().Add(-10 * time.Minute)
+		Expect(db.Create(&[]models.HubResourceCount{
+			{
+				LeafHubName:   hubName,
+				ResourceType:  cluster.ResourceManagedClusters,
+				ReportedCount: 3,
+				ReportedAt:    reportedAt,
+			},
+			{
+				LeafHubName:   hubName,
+				ResourceType:  cluster.ResourceLocalPolicies,
+				ReportedCount: 0,
+				ReportedAt:    reportedAt,
+			},
+		}).Error).To(Succeed())
+	})
+
+	It("should flag the inconsistent resource counts", func() {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Week().DoWithJobDetails(CheckDataConsistency, ctx)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() error {
+			counts := []models.HubResourceCount{}
+			if err := db.Where("leaf_hub_name = ?", hubName).Find(&counts).Error; err != nil {
+				return err
+			}
+			for _, count := range counts {
+				if count.Consistent == nil || count.StoredCount == nil {
+					return fmt.Errorf("the %s of the hub isn't checked", count.ResourceType)
+				}
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).Should(Succeed())
+
+		count := models.HubResourceCount{}
+		Expect(db.Where("leaf_hub_name = ? AND resource_type = ?", hubName, cluster.ResourceManagedClusters).
+			First(&count).Error).To(Succeed())
+		Expect(*count.StoredCount).To(Equal(2))
+		Expect(*count.Consistent).To(BeFalse())
+
+		Expect(db.Where("leaf_hub_name = ? AND resource_type = ?", hubName, cluster.ResourceLocalPolicies).
+			First(&count).Error).To(Succeed())
+		Expect(*count.StoredCount).To(Equal(0))
+		Expect(*count.Consistent).To(BeTrue())
+	})
+})
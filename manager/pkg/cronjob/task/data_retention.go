@@ -9,8 +9,8 @@ import (
 	"github.com/go-co-op/gocron"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/archive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -20,6 +20,7 @@ var (
 	// 1. create partition tables for days in the future, the partition table for the next month is created
 	// 2. delete partition tables that are no longer needed, the partition table for the previous 18 month is deleted
 	// 3. completely delete the soft deleted records from database after retainedMonths
+	// 4. delete the expired records of the non-partitioned tables, e.g. the dead letter events
 	RetentionTaskName = "data-retention"
 
 	// after the record is marked as deleted, retentionMonth is used to indicate how long it will be retained
@@ -39,11 +40,14 @@ var (
 		"event.local_policies",
 		"event.local_root_policies",
 		"history.local_compliance",
+		"history.hub_metrics",
 	}
 	retentionLog = ctrl.Log.WithName(RetentionTaskName)
 )
 
-func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
+// DataRetention maintains the partition tables and deletes the expired data, the expired partition is exported to the
+// object storage by the archiver before it's dropped, the archive is disabled if the archiver is nil
+func DataRetention(ctx context.Context, retentionMonth int, archiver *archive.Archiver, job gocron.Job) {
 	now := time.Now()
 	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
@@ -58,17 +62,13 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 	defer database.Unlock(conn)
 
 	defer func() {
-		if err != nil {
-			monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(RetentionTaskName).Set(1)
-		} else {
-			monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(RetentionTaskName).Set(0)
-		}
+		updateJobStatus(RetentionTaskName, now, job, err)
 	}()
 
 	createMonth := currentMonth.AddDate(0, 1, 0)
 	deleteMonth := currentMonth.AddDate(0, -(retentionMonth + 1), 0)
 	for _, tableName := range partitionTables {
-		err = updatePartitionTables(tableName, createMonth, deleteMonth)
+		err = updatePartitionTables(ctx, tableName, createMonth, deleteMonth, archiver)
 		if e := traceDataRetentionLog(tableName, currentMonth, err, true); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
@@ -96,10 +96,23 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 		retentionLog.Error(err, "failed to delete the expired leaf hub heartbeat")
 		return
 	}
+	err = db.Where("received_at < ?", minTime).Delete(&models.DeadLetterEvent{}).Error
+	if err != nil {
+		retentionLog.Error(err, "failed to delete the expired dead letter events")
+		return
+	}
+	// the details are replaced by the active hubs, so only the ones of the inactive hubs are expired
+	err = db.Where("updated_at < ?", minTime).Delete(&models.PolicyTemplateDetail{}).Error
+	if err != nil {
+		retentionLog.Error(err, "failed to delete the expired policy template details")
+		return
+	}
 	retentionLog.Info("finish running", "nextRun", job.NextRun().Format(timeFormat))
 }
 
-func updatePartitionTables(tableName string, createTime, deleteTime time.Time) error {
+func updatePartitionTables(ctx context.Context, tableName string, createTime, deleteTime time.Time,
+	archiver *archive.Archiver,
+) error {
 	db := database.GetGorm()
 
 	// create the partition tables for the next month
@@ -107,9 +120,8 @@ func updatePartitionTables(tableName string, createTime, deleteTime time.Time) e
 	endTime := startTime.AddDate(0, 1, 0)
 	createPartitionTableName := fmt.Sprintf("%s_%s", tableName, startTime.Format(partitionDateFormat))
 
-	creationSql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
-		createPartitionTableName, tableName, startTime.Format(dateFormat), endTime.Format(dateFormat))
-	if result := db.Exec(creationSql); result.Error != nil {
+	if result := db.Exec("SELECT create_monthly_range_partitioned_table(?, ?)", tableName,
+		startTime.Format(dateFormat)); result.Error != nil {
 		return fmt.Errorf("failed to create partition table %s: %w", tableName, result.Error)
 	}
 	retentionLog.Info("create partition table", "table", createPartitionTableName, "start", startTime.Format(dateFormat),
@@ -117,6 +129,12 @@ func updatePartitionTables(tableName string, createTime, deleteTime time.Time) e
 
 	// delete the partition tables that are expired
 	deletePartitionTableName := fmt.Sprintf("%s_%s", tableName, deleteTime.Format(partitionDateFormat))
+	if archiver != nil {
+		// keep the partition until it's archived, so that it's retried in the next run
+		if err := archiver.ArchivePartition(ctx, db, tableName, deletePartitionTableName); err != nil {
+			return fmt.Errorf("failed to archive partition table %s: %w", deletePartitionTableName, err)
+		}
+	}
 	deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", deletePartitionTableName)
 	if result := db.Exec(deletionSql); result.Error != nil {
 		return fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
@@ -183,6 +201,7 @@ func getMinMaxPartitions(tableName string) (string, string, error) {
 		WHERE
 			nmsp_parent.nspname='%s'
 			AND parent.relname='%s'
+			AND child.relname NOT LIKE '%%\_default'
 		ORDER BY
 			child.relname ASC;`,
 		schemaTable[0], schemaTable[1])
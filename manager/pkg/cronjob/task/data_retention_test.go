@@ -15,6 +15,7 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/archive"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -109,7 +110,7 @@ var _ = Describe("data retention job", Ordered, func() {
 	It("the data retention job should work", func() {
 		By("Create the data retention job")
 		s := gocron.NewScheduler(time.UTC)
-		_, err := s.Every(1).Week().DoWithJobDetails(DataRetention, ctx, retentionMonth)
+		_, err := s.Every(1).Week().DoWithJobDetails(DataRetention, ctx, retentionMonth, (*archive.Archiver)(nil))
 		Expect(err).ToNot(HaveOccurred())
 		s.StartAsync()
 		defer s.Clear()
@@ -0,0 +1,165 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/api/errors"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var (
+	// The main tasks of this job are:
+	// 1. tombstone the hub once it's detached: the managed cluster of the hub is deleted, or the global hub addon is
+	//    removed from it. The data of the hub is soft deleted and the heartbeat status is switched to detached
+	// 2. restore the tombstone to inactive if the hub is attached again, then it's resynced once the heartbeat is back
+	// 3. purge all the data of the hub from the database after it's detached for the retention
+	DetachedHubCleanupTaskName = "detached-hub-cleanup"
+
+	// the tables with the leaf_hub_name column, the records of the detached hub are purged from them
+	detachedHubTables = []string{
+		"status.managed_clusters",
+		"status.managed_cluster_addons",
+		"status.managed_service_accounts",
+		"status.managed_cluster_enrichments",
+		"status.leaf_hubs",
+		"status.argocd_applications",
+		"status.argocd_applicationsets",
+		"status.gatekeeper_constraints",
+		"status.gatekeeper_violations",
+		"status.compliance_scans",
+		"status.image_vulnerabilities",
+		"status.policy_template_details",
+		"local_spec.policies",
+		"local_status.compliance",
+		"event.local_policies",
+		"event.local_root_policies",
+		"history.local_compliance",
+		"history.hub_metrics",
+		"status.hub_resource_counts",
+		"status.leaf_hub_heartbeats",
+		"status.agent_health",
+		"status.agent_versions",
+		"status.dead_letter_events",
+		"status.data_freshness",
+	}
+	detachedHubLog = ctrl.Log.WithName(DetachedHubCleanupTaskName)
+)
+
+func CleanupDetachedHubs(ctx context.Context, reader client.Reader, retention time.Duration, job gocron.Job) {
+	startAt := time.Now()
+	var err error
+	defer func() {
+		updateJobStatus(DetachedHubCleanupTaskName, startAt, job, err)
+	}()
+
+	conn := database.GetConn()
+	err = database.Lock(conn)
+	if err != nil {
+		detachedHubLog.Error(err, "failed to run detached hub cleanup")
+		return
+	}
+	defer database.Unlock(conn)
+
+	db := database.GetGorm()
+	var hubs []models.LeafHubHeartbeat
+	if err = db.Find(&hubs).Error; err != nil {
+		detachedHubLog.Error(err, "failed to list the hub heartbeats")
+		return
+	}
+
+	purgeTime := time.Now().Add(-retention)
+	for _, hub := range hubs {
+		attached, e := isHubAttached(ctx, reader, hub.Name)
+		if e != nil {
+			// don't tombstone the hub if it's unknown whether the hub is attached
+			err = e
+			detachedHubLog.Error(e, "failed to check whether the hub is attached", "hub", hub.Name)
+			continue
+		}
+
+		switch {
+		case !attached && hub.Status != hubmanagement.HubDetached:
+			e = tombstoneHub(db, hub.Name)
+		case attached && hub.Status == hubmanagement.HubDetached:
+			detachedHubLog.Info("restore the detached hub", "hub", hub.Name)
+			e = db.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hub.Name).
+				Update("status", hubmanagement.HubInactive).Error
+		case !attached && hub.LastUpdateAt.Before(purgeTime):
+			e = purgeHub(db, hub.Name)
+		}
+		if e != nil {
+			err = e
+			detachedHubLog.Error(e, "failed to clean up the detached hub", "hub", hub.Name)
+		}
+	}
+	detachedHubLog.Info("finish running", "nextRun", job.NextRun().Format(timeFormat))
+}
+
+// isHubAttached returns false if the managed cluster of the hub is deleted or the global hub addon isn't installed
+func isHubAttached(ctx context.Context, reader client.Reader, hubName string) (bool, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	err := reader.Get(ctx, client.ObjectKey{Name: hubName}, cluster)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !cluster.DeletionTimestamp.IsZero() {
+		return false, nil
+	}
+
+	addon := &addonv1alpha1.ManagedClusterAddOn{}
+	err = reader.Get(ctx, client.ObjectKey{Namespace: hubName, Name: constants.GHManagedClusterAddonName}, addon)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return addon.DeletionTimestamp.IsZero(), nil
+}
+
+// tombstoneHub soft deletes the data of the hub, and records the detached time in the last_timestamp of the
+// heartbeat, which isn't updated since the agent is removed with the addon
+func tombstoneHub(db *gorm.DB, hubName string) error {
+	detachedHubLog.Info("tombstone the detached hub", "hub", hubName)
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := hubmanagement.CleanupHubResources(tx, hubName); err != nil {
+			return err
+		}
+		return tx.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hubName).
+			Updates(map[string]interface{}{
+				"status":         hubmanagement.HubDetached,
+				"last_timestamp": time.Now(),
+			}).Error
+	})
+}
+
+// purgeHub deletes all the records of the hub, including the committed offset of its status topic
+func purgeHub(db *gorm.DB, hubName string) error {
+	detachedHubLog.Info("purge the detached hub", "hub", hubName)
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, tableName := range detachedHubTables {
+			sql := fmt.Sprintf("DELETE FROM %s WHERE leaf_hub_name = ?", tableName)
+			if err := tx.Exec(sql, hubName).Error; err != nil {
+				return fmt.Errorf("failed to purge the hub %s from %s: %w", hubName, tableName, err)
+			}
+		}
+		// the offsets of the hub topic on the additional kafka clusters are qualified by the cluster identities
+		return tx.Exec("DELETE FROM status.transport WHERE name = ? OR name LIKE ?", "status."+hubName,
+			"status."+hubName+"@%").Error
+	})
+}
@@ -0,0 +1,104 @@
+package task
+
+import (
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var _ = Describe("detached hub cleanup job", Ordered, func() {
+	var runtimeClient client.Client
+
+	BeforeAll(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+		Expect(addonv1alpha1.AddToScheme(scheme)).To(Succeed())
+		var err error
+		runtimeClient, err = client.New(cfg, client.Options{Scheme: scheme})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Create the attached hub with the global hub addon")
+		Expect(runtimeClient.Create(ctx, &clusterv1.ManagedCluster{
+			ObjectMeta: v1.ObjectMeta{Name: "attached-hub"},
+			Spec:       clusterv1.ManagedClusterSpec{HubAcceptsClient: true},
+		})).To(Succeed())
+		Expect(runtimeClient.Create(ctx, &corev1.Namespace{
+			ObjectMeta: v1.ObjectMeta{Name: "attached-hub"},
+		})).To(Succeed())
+		Expect(runtimeClient.Create(ctx, &addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: v1.ObjectMeta{Name: constants.GHManagedClusterAddonName, Namespace: "attached-hub"},
+			Spec:       addonv1alpha1.ManagedClusterAddOnSpec{InstallNamespace: constants.GHAgentNamespace},
+		})).To(Succeed())
+
+		By("Create the heartbeats of the attached and detached hubs")
+		for _, hubName := range []string{"attached-hub", "detached-hub"} {
+			Expect(db.Create(&models.LeafHubHeartbeat{
+				Name:         hubName,
+				Status:       hubmanagement.HubActive,
+				LastUpdateAt: time.Now(),
+			}).Error).To(Succeed())
+		}
+		Expect(db.Exec(`INSERT INTO status.transport (name, payload) VALUES ('status.detached-hub', '{}')`).Error).
+			To(Succeed())
+	})
+
+	It("should tombstone the detached hub", func() {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Week().DoWithJobDetails(CleanupDetachedHubs, ctx, client.Reader(runtimeClient),
+			time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() string {
+			hub := models.LeafHubHeartbeat{}
+			if err := db.Where("leaf_hub_name = ?", "detached-hub").First(&hub).Error; err != nil {
+				return err.Error()
+			}
+			return hub.Status
+		}, 10*time.Second, 1*time.Second).Should(Equal(hubmanagement.HubDetached))
+
+		hub := models.LeafHubHeartbeat{}
+		Expect(db.Where("leaf_hub_name = ?", "attached-hub").First(&hub).Error).To(Succeed())
+		Expect(hub.Status).To(Equal(hubmanagement.HubActive))
+	})
+
+	It("should purge the detached hub after the retention", func() {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Week().DoWithJobDetails(CleanupDetachedHubs, ctx, client.Reader(runtimeClient),
+			time.Duration(0))
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() int64 {
+			var count int64
+			if err := db.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", "detached-hub").
+				Count(&count).Error; err != nil {
+				return -1
+			}
+			return count
+		}, 10*time.Second, 1*time.Second).Should(BeZero())
+
+		var count int64
+		Expect(db.Raw(`SELECT COUNT(*) FROM status.transport WHERE name = 'status.detached-hub'`).
+			Scan(&count).Error).To(Succeed())
+		Expect(count).To(BeZero())
+		Expect(db.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", "attached-hub").
+			Count(&count).Error).To(Succeed())
+		Expect(count).To(Equal(int64(1)))
+	})
+})
@@ -0,0 +1,40 @@
+package task
+
+import (
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	JobSucceeded = "Succeeded"
+	JobFailed    = "Failed"
+)
+
+// updateJobStatus records the result of the job run into the metrics and the status.cron_jobs table, the operator
+// reports the table on the status of the MulticlusterGlobalHub
+func updateJobStatus(name string, startAt time.Time, job gocron.Job, err error) {
+	cronJob := &models.CronJob{
+		Name:      name,
+		LastRunAt: startAt,
+		NextRunAt: job.NextRun(),
+		Result:    JobSucceeded,
+	}
+	if err != nil {
+		monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(name).Set(1)
+		cronJob.Result = JobFailed
+		cronJob.Error = err.Error()
+	} else {
+		monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(name).Set(0)
+	}
+
+	if e := database.GetGorm().Clauses(clause.OnConflict{UpdateAll: true}).Create(cronJob).Error; e != nil {
+		ctrl.Log.WithName(name).Error(e, "failed to update the job status")
+	}
+}
@@ -8,10 +8,8 @@ import (
 
 	"github.com/go-co-op/gocron"
 	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -52,11 +50,7 @@ func SyncLocalCompliance(ctx context.Context, enableSimulation bool, job gocron.
 
 	var err error
 	defer func() {
-		if err != nil {
-			monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(LocalComplianceTaskName).Set(1)
-		} else {
-			monitoring.GlobalHubCronJobGaugeVec.WithLabelValues(LocalComplianceTaskName).Set(0)
-		}
+		updateJobStatus(LocalComplianceTaskName, startTime, job, err)
 	}()
 
 	historyDate := startTime.AddDate(0, 0, -interval)
@@ -154,8 +148,8 @@ func insertToLocalComplianceHistoryByLocalStatus(ctx context.Context, tableName
 			log.Info("trace compliance job failed, retrying", "error", e)
 		}
 	}()
-	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 10*time.Minute, true,
-		func(ctx context.Context) (done bool, err error) {
+	err = database.Retry(ctx,
+		func(ctx context.Context) error {
 			selectInsertSQLTemplate := `
 			INSERT INTO history.local_compliance (policy_id, cluster_id, leaf_hub_name, compliance, compliance_date) 
 				(
@@ -193,12 +187,12 @@ func insertToLocalComplianceHistoryByLocalStatus(ctx context.Context, tableName
 			selectInsertSQL := fmt.Sprintf(selectInsertSQLTemplate, interval, tableName, batchSize, offset)
 			result := db.Exec(selectInsertSQL)
 			if result.Error != nil {
-				log.Info("exec failed, retrying", "error", result.Error)
-				return false, nil
+				log.Info("exec failed", "error", result.Error)
+				return result.Error
 			}
 			insertCount = result.RowsAffected
 			log.V(2).Info("from local_status.compliance", "batch", batchSize, "insert", insertCount, "offset", offset)
-			return true, nil
+			return nil
 		})
 	return insertCount, err
 }
@@ -233,8 +227,8 @@ func syncToLocalComplianceHistoryByPolicyEvent(ctx context.Context, batchSize in
 func insertToLocalComplianceHistoryByPolicyEvent(ctx context.Context, totalCount, batchSize, offset int64,
 ) (int64, error) {
 	insertCount := int64(0)
-	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 30*time.Minute, true,
-		func(ctx context.Context) (done bool, err error) {
+	err := database.Retry(ctx,
+		func(ctx context.Context) error {
 			var insertError error
 			defer func() {
 				if e := traceComplianceHistoryLog(ctx,
@@ -283,12 +277,12 @@ func insertToLocalComplianceHistoryByPolicyEvent(ctx context.Context, totalCount
 			result := db.Exec(selectInsertStatement, batchSize, offset)
 			insertError = result.Error
 			if insertError != nil {
-				log.Info("insert failed, retrying", "error", insertError)
-				return false, nil
+				log.Info("insert failed", "error", insertError)
+				return insertError
 			}
 			insertCount = result.RowsAffected
 			log.V(2).Info("from event.local_policies", "batch", batchSize, "insert", insertCount, "offset", offset)
-			return true, nil
+			return nil
 		})
 	return insertCount, err
 }
@@ -0,0 +1,115 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+var (
+	// The main tasks of this job are:
+	// 1. pre-create the monthly partitions of the partition tables for the next months, so the inserts don't fail at
+	//    the month rollover even if the data retention job is lagged
+	// 2. attach the default partition to the partition tables as the safety net, the rows out of the monthly
+	//    partitions are kept in it instead of failing the inserts
+	// 3. create the monthly partitions for the rows in the default partition, they're moved into the partitions, and
+	//    the rows older than the retention are deleted
+	PartitionManagerTaskName = "partition-manager"
+
+	// the number of the months to create the partitions ahead of the current month
+	partitionPremakeMonths = 2
+
+	// the partition key of the partition tables
+	partitionKeys = map[string]string{
+		"event.local_policies":      "created_at",
+		"event.local_root_policies": "created_at",
+		"history.local_compliance":  "compliance_date",
+	}
+	partitionLog = ctrl.Log.WithName(PartitionManagerTaskName)
+)
+
+func ManagePartitions(ctx context.Context, retentionMonth int, job gocron.Job) {
+	startAt := time.Now()
+	var err error
+	defer func() {
+		updateJobStatus(PartitionManagerTaskName, startAt, job, err)
+	}()
+
+	conn := database.GetConn()
+	err = database.Lock(conn)
+	if err != nil {
+		partitionLog.Error(err, "failed to run partition manager")
+		return
+	}
+	defer database.Unlock(conn)
+
+	currentMonth := time.Date(startAt.Year(), startAt.Month(), 1, 0, 0, 0, 0, startAt.Location())
+	for _, tableName := range partitionTables {
+		if e := managePartitions(tableName, currentMonth, retentionMonth); e != nil {
+			// continue with the other tables
+			err = e
+			partitionLog.Error(e, "failed to manage the partitions", "table", tableName)
+		}
+	}
+	partitionLog.V(2).Info("finish running", "nextRun", job.NextRun().Format(timeFormat))
+}
+
+func managePartitions(tableName string, currentMonth time.Time, retentionMonth int) error {
+	db := database.GetGorm()
+	partitionKey, ok := partitionKeys[tableName]
+	if !ok {
+		return fmt.Errorf("the partition key of the table %s isn't found", tableName)
+	}
+
+	defaultPartition := tableName + "_default"
+	err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s DEFAULT", defaultPartition,
+		tableName)).Error
+	if err != nil {
+		return fmt.Errorf("failed to create the default partition of %s: %w", tableName, err)
+	}
+
+	// the rows older than the retention would be dropped along with their partitions
+	minMonth := currentMonth.AddDate(0, -retentionMonth, 0)
+	result := db.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s < ?", defaultPartition, partitionKey),
+		minMonth.Format(dateFormat))
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete the expired rows from %s: %w", defaultPartition, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		partitionLog.Info("delete the expired rows from the default partition", "table", defaultPartition,
+			"before", minMonth.Format(dateFormat), "count", result.RowsAffected)
+	}
+
+	months := map[string]bool{}
+	for i := 0; i <= partitionPremakeMonths; i++ {
+		months[currentMonth.AddDate(0, i, 0).Format(dateFormat)] = true
+	}
+	var defaultMonths []string
+	err = db.Raw(fmt.Sprintf("SELECT DISTINCT to_char(%s, 'YYYY-MM-01') FROM %s", partitionKey,
+		defaultPartition)).Scan(&defaultMonths).Error
+	if err != nil {
+		return fmt.Errorf("failed to list the months in %s: %w", defaultPartition, err)
+	}
+	for _, month := range defaultMonths {
+		partitionLog.Info("found the rows in the default partition", "table", defaultPartition, "month", month)
+		months[month] = true
+	}
+
+	sortedMonths := make([]string, 0, len(months))
+	for month := range months {
+		sortedMonths = append(sortedMonths, month)
+	}
+	sort.Strings(sortedMonths)
+	for _, month := range sortedMonths {
+		if err := db.Exec("SELECT create_monthly_range_partitioned_table(?, ?)", tableName, month).Error; err != nil {
+			return fmt.Errorf("failed to create the partition of %s for %s: %w", tableName, month, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package task
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("partition manager job", Ordered, func() {
+	now := time.Now()
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	retentionMonth := 18
+	// the month without the partition, its rows are kept in the default partition
+	missingMonth := currentMonth.AddDate(0, -3, 0)
+	expiredMonth := currentMonth.AddDate(0, -(retentionMonth + 2), 0)
+	tableName := "event.local_policies"
+
+	countRows := func(table string) (int64, error) {
+		var count int64
+		err := db.Raw(fmt.Sprintf("SELECT COUNT(1) FROM %s", table)).Scan(&count).Error
+		return count, err
+	}
+
+	insertEvent := func(createdAt time.Time) error {
+		return db.Exec(`INSERT INTO event.local_policies (event_name, policy_id, cluster_id, leaf_hub_name,
+			compliance, created_at) VALUES (?, ?, ?, 'hub-partition', 'compliant', ?)`,
+			"event-"+uuid.New().String(), uuid.New().String(), uuid.New().String(),
+			createdAt.AddDate(0, 0, 1).Format(timeFormat)).Error
+	}
+
+	AfterAll(func() {
+		By("Drop the partitions created ahead of the next month")
+		for _, table := range partitionTables {
+			for i := 2; i <= partitionPremakeMonths; i++ {
+				month := currentMonth.AddDate(0, i, 0)
+				Expect(db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s_%s", table,
+					month.Format(partitionDateFormat))).Error).To(Succeed())
+			}
+		}
+	})
+
+	It("should keep the rows out of the monthly partitions in the default partition", func() {
+		Expect(db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s_default PARTITION OF %s DEFAULT", tableName,
+			tableName)).Error).To(Succeed())
+		Expect(db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s_%s", tableName,
+			missingMonth.Format(partitionDateFormat))).Error).To(Succeed())
+
+		Expect(insertEvent(missingMonth)).To(Succeed())
+		Expect(insertEvent(expiredMonth)).To(Succeed())
+		Expect(countRows(tableName + "_default")).To(BeEquivalentTo(2))
+	})
+
+	It("should create the partitions for the next months and the rows in the default partition", func() {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Week().DoWithJobDetails(ManagePartitions, ctx, retentionMonth)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() error {
+			for _, table := range partitionTables {
+				for i := 0; i <= partitionPremakeMonths; i++ {
+					partition := fmt.Sprintf("%s_%s", table, currentMonth.AddDate(0, i, 0).Format(partitionDateFormat))
+					var exists bool
+					if err := db.Raw("SELECT to_regclass(?) IS NOT NULL", partition).Scan(&exists).Error; err != nil {
+						return err
+					}
+					if !exists {
+						return fmt.Errorf("the partition %s isn't created", partition)
+					}
+				}
+			}
+
+			// the row of the missing month is moved into its partition, and the expired row is deleted
+			count, err := countRows(tableName + "_default")
+			if err != nil {
+				return err
+			}
+			if count != 0 {
+				return fmt.Errorf("expected the empty default partition, got %d rows", count)
+			}
+			count, err = countRows(fmt.Sprintf("%s_%s", tableName, missingMonth.Format(partitionDateFormat)))
+			if err != nil {
+				return err
+			}
+			if count != 1 {
+				return fmt.Errorf("expected the row moved into the partition of the missing month, got %d rows", count)
+			}
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,134 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var (
+	// The job measures the records of each managed hub in the tables with the leaf_hub_name column and the bytes they
+	// take, the records are sampled so the large tables, e.g. the events, aren't scanned for the sizes of all the rows.
+	// The bytes of the table, including the indexes and the toast, are attributed to the hubs by the sampled sizes.
+	StorageUsageTaskName = "storage-usage"
+
+	// the number of the rows sampled from each table, the table with fewer rows is measured completely
+	storageSampleRows = 10000
+
+	storageUsageLog = ctrl.Log.WithName(StorageUsageTaskName)
+)
+
+// hubSample is the records of the hub in the sample of the table and the bytes of them
+type hubSample struct {
+	LeafHubName string
+	Records     int64
+	Bytes       int64
+}
+
+func MeasureStorageUsage(ctx context.Context, job gocron.Job) {
+	startAt := time.Now()
+	var err error
+	defer func() {
+		updateJobStatus(StorageUsageTaskName, startAt, job, err)
+	}()
+
+	db := database.GetGorm().WithContext(ctx)
+	usage := []models.HubStorageUsage{}
+	for _, tableName := range detachedHubTables {
+		tableUsage, e := measureTable(db, tableName, startAt)
+		if e != nil {
+			err = e
+			storageUsageLog.Error(e, "failed to measure the storage usage", "table", tableName)
+			continue
+		}
+		usage = append(usage, tableUsage...)
+	}
+
+	monitoring.GlobalHubStorageBytesGaugeVec.Reset()
+	for _, item := range usage {
+		monitoring.GlobalHubStorageBytesGaugeVec.WithLabelValues(item.LeafHubName, item.Table).Set(float64(item.Bytes))
+	}
+	if len(usage) > 0 {
+		if e := db.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(usage, 100).Error; e != nil {
+			err = e
+			storageUsageLog.Error(e, "failed to save the storage usage")
+			return
+		}
+	}
+	// the hubs without records, e.g. the purged ones, aren't measured any more
+	if err == nil {
+		if e := db.Where("measured_at < ?", startAt).Delete(&models.HubStorageUsage{}).Error; e != nil {
+			err = e
+			storageUsageLog.Error(e, "failed to delete the stale storage usage")
+		}
+	}
+	storageUsageLog.Info("finish running", "measured", len(usage), "nextRun", job.NextRun().Format(timeFormat))
+}
+
+// measureTable estimates the records and the bytes of the hubs in the table by the sample of the rows. The partitions
+// of the partitioned table are measured together.
+func measureTable(db *gorm.DB, tableName string, measuredAt time.Time) ([]models.HubStorageUsage, error) {
+	var table struct {
+		Rows  float64
+		Bytes int64
+	}
+	err := db.Raw(`SELECT COALESCE(sum(GREATEST(c.reltuples, 0)), 0) AS rows,
+		COALESCE(sum(pg_total_relation_size(p.relid)), 0) AS bytes
+		FROM pg_partition_tree(?::regclass) p JOIN pg_class c ON c.oid = p.relid WHERE p.isleaf`, tableName).
+		Scan(&table).Error
+	if err != nil {
+		return nil, err
+	}
+
+	percent := samplePercent(table.Rows, storageSampleRows)
+	var samples []hubSample
+	err = db.Raw(fmt.Sprintf(`SELECT leaf_hub_name, count(*) AS records, sum(pg_column_size(t.*)) AS bytes
+		FROM %s t TABLESAMPLE BERNOULLI (?) GROUP BY leaf_hub_name`, tableName), percent).Scan(&samples).Error
+	if err != nil {
+		return nil, err
+	}
+	return estimateUsage(tableName, samples, percent, table.Bytes, measuredAt), nil
+}
+
+// samplePercent returns the percentage of the rows to sample, the estimated rows are unknown if the table isn't
+// analyzed yet, then it's measured completely
+func samplePercent(rows float64, sampleRows int) float64 {
+	if rows <= float64(sampleRows) {
+		return 100
+	}
+	return float64(sampleRows) * 100 / rows
+}
+
+// estimateUsage scales the sampled records by the percentage, and attributes the bytes of the table to the hubs by
+// the bytes of their sampled rows
+func estimateUsage(tableName string, samples []hubSample, percent float64, tableBytes int64, measuredAt time.Time,
+) []models.HubStorageUsage {
+	var sampledBytes int64
+	for _, sample := range samples {
+		sampledBytes += sample.Bytes
+	}
+	usage := make([]models.HubStorageUsage, 0, len(samples))
+	for _, sample := range samples {
+		item := models.HubStorageUsage{
+			LeafHubName: sample.LeafHubName,
+			Table:       tableName,
+			Records:     int64(math.Round(float64(sample.Records) * 100 / percent)),
+			MeasuredAt:  measuredAt,
+		}
+		if sampledBytes > 0 {
+			item.Bytes = int64(math.Round(float64(tableBytes) * float64(sample.Bytes) / float64(sampledBytes)))
+		}
+		usage = append(usage, item)
+	}
+	return usage
+}
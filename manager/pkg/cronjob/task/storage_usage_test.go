@@ -0,0 +1,75 @@
+package task
+
+import (
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+var _ = Describe("storage usage job", Ordered, func() {
+	BeforeAll(func() {
+		By("Create the managed clusters of the hubs in the database")
+		Expect(db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('usage-hub1', gen_random_uuid(), '{"metadata": {"name": "cluster1"}}', 'none'),
+			('usage-hub1', gen_random_uuid(), '{"metadata": {"name": "cluster2"}}', 'none'),
+			('usage-hub2', gen_random_uuid(), '{"metadata": {"name": "cluster3"}}', 'none')`).Error).
+			To(Succeed())
+
+		By("Create the stale usage of the purged hub")
+		Expect(db.Create(&models.HubStorageUsage{
+			LeafHubName: "usage-purged-hub",
+			Table:       "status.managed_clusters",
+			Records:     1,
+			Bytes:       100,
+			MeasuredAt:  time.Now().Add(-24 * time.Hour),
+		}).Error).To(Succeed())
+	})
+
+	It("should measure the records and the bytes of the hubs", func() {
+		s := gocron.NewScheduler(time.UTC)
+		_, err := s.Every(1).Week().DoWithJobDetails(MeasureStorageUsage, ctx)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		Eventually(func() error {
+			usage := models.HubStorageUsage{}
+			return db.Where("leaf_hub_name = ? AND table_name = ?", "usage-hub1", "status.managed_clusters").
+				First(&usage).Error
+		}, 10*time.Second, 1*time.Second).Should(Succeed())
+
+		hub1, hub2 := models.HubStorageUsage{}, models.HubStorageUsage{}
+		Expect(db.Where("leaf_hub_name = ? AND table_name = ?", "usage-hub1", "status.managed_clusters").
+			First(&hub1).Error).To(Succeed())
+		Expect(db.Where("leaf_hub_name = ? AND table_name = ?", "usage-hub2", "status.managed_clusters").
+			First(&hub2).Error).To(Succeed())
+		// the small table is measured completely
+		Expect(hub1.Records).To(Equal(int64(2)))
+		Expect(hub2.Records).To(Equal(int64(1)))
+		Expect(hub1.Bytes).To(BeNumerically(">", hub2.Bytes))
+
+		var stale int64
+		Expect(db.Model(&models.HubStorageUsage{}).Where("leaf_hub_name = ?", "usage-purged-hub").
+			Count(&stale).Error).To(Succeed())
+		Expect(stale).To(BeZero())
+	})
+
+	It("should estimate the usage from the sample", func() {
+		Expect(samplePercent(0, 100)).To(Equal(float64(100)))
+		Expect(samplePercent(1000, 100)).To(Equal(float64(10)))
+
+		usage := estimateUsage("event.local_policies", []hubSample{
+			{LeafHubName: "hub1", Records: 30, Bytes: 3000},
+			{LeafHubName: "hub2", Records: 10, Bytes: 1000},
+		}, 10, 80000, time.Now())
+		Expect(usage).To(HaveLen(2))
+		Expect(usage[0].Records).To(Equal(int64(300)))
+		Expect(usage[0].Bytes).To(Equal(int64(60000)))
+		Expect(usage[1].Records).To(Equal(int64(100)))
+		Expect(usage[1].Bytes).To(Equal(int64(20000)))
+	})
+})
@@ -0,0 +1,64 @@
+package datacollection
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// PublishInterval is the interval of republishing the profile, the agents commit the offsets of the spec messages
+// once they're received, so the agent restarted or joined later only gets the profile from the next publishing
+var PublishInterval = 1 * time.Minute
+
+// profilePublisher broadcasts the data collection profile to the agents, which decides the status bundles collected
+// by them
+type profilePublisher struct {
+	log      logr.Logger
+	producer transport.Producer
+	profile  string
+}
+
+// AddProfilePublisher adds the publisher of the data collection profile into the manager, it only runs on the leader
+func AddProfilePublisher(mgr ctrl.Manager, producer transport.Producer, profile string) error {
+	return mgr.Add(&profilePublisher{
+		log:      ctrl.Log.WithName("data-collection-profile"),
+		producer: producer,
+		profile:  profile,
+	})
+}
+
+func (p *profilePublisher) Start(ctx context.Context) error {
+	p.log.Info("publish the data collection profile", "profile", p.profile, "interval", PublishInterval)
+	ticker := time.NewTicker(PublishInterval)
+	defer ticker.Stop()
+	for {
+		if err := p.publish(ctx); err != nil {
+			p.log.Error(err, "failed to publish the data collection profile")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *profilePublisher) publish(ctx context.Context) error {
+	payloadBytes, err := json.Marshal(specbundle.DataCollectionBundle{Profile: p.profile})
+	if err != nil {
+		return err
+	}
+	e := cloudevents.NewEvent()
+	e.SetType(constants.DataCollectionMsgKey)
+	e.SetSource(transport.Broadcast)
+	_ = e.SetData(cloudevents.ApplicationJSON, payloadBytes)
+	return p.producer.SendEvent(ctx, e)
+}
@@ -0,0 +1,57 @@
+package datacollection
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+
+	specbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type recordingProducer struct {
+	lock   sync.Mutex
+	events []cloudevents.Event
+}
+
+func (p *recordingProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *recordingProducer) sent() []cloudevents.Event {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]cloudevents.Event{}, p.events...)
+}
+
+func TestProfilePublisher(t *testing.T) {
+	PublishInterval = 50 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	producer := &recordingProducer{}
+	publisher := &profilePublisher{log: logr.Discard(), producer: producer, profile: specbundle.DataCollectionMinimal}
+	go func() {
+		_ = publisher.Start(ctx)
+	}()
+
+	// the profile is published on start and republished periodically
+	assert.Eventually(t, func() bool { return len(producer.sent()) >= 2 }, 2*time.Second, 10*time.Millisecond)
+
+	evt := producer.sent()[0]
+	assert.Equal(t, constants.DataCollectionMsgKey, evt.Type())
+	assert.Equal(t, transport.Broadcast, evt.Source())
+	bundle := specbundle.DataCollectionBundle{}
+	assert.Nil(t, json.Unmarshal(evt.Data(), &bundle))
+	assert.Equal(t, specbundle.DataCollectionMinimal, bundle.Profile)
+}
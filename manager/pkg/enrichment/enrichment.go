@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package enrichment
+
+import (
+	"encoding/json"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// Enricher computes the fields of the managed cluster between the consumption and the persistence of the status, the
+// fields are stored in the status.managed_cluster_enrichments, e.g. the business unit from the cluster labels
+type Enricher interface {
+	// Name identifies the enricher in the logs
+	Name() string
+	// Enrich returns the fields computed from the cluster of the managed hub, it must not modify the cluster
+	Enrich(leafHubName string, cluster *clusterv1.ManagedCluster) map[string]string
+}
+
+var (
+	enrichersLock sync.RWMutex
+	enrichers     []Enricher
+)
+
+// Register adds the enricher to the pipeline, the enrichers are run in the order of the registration and the field
+// computed by the later enricher overrides the one of the earlier enricher
+func Register(enricher Enricher) {
+	enrichersLock.Lock()
+	defer enrichersLock.Unlock()
+	enrichers = append(enrichers, enricher)
+	ctrl.Log.WithName("enrichment").Info("the enricher is registered", "name", enricher.Name())
+}
+
+// Enabled returns whether any enricher is registered, the enrichments aren't persisted if it's false
+func Enabled() bool {
+	enrichersLock.RLock()
+	defer enrichersLock.RUnlock()
+	return len(enrichers) > 0
+}
+
+// Enrich runs the registered enrichers on the cluster and merges the computed fields
+func Enrich(leafHubName string, cluster *clusterv1.ManagedCluster) map[string]string {
+	enrichersLock.RLock()
+	defer enrichersLock.RUnlock()
+	fields := map[string]string{}
+	for _, enricher := range enrichers {
+		for name, value := range enricher.Enrich(leafHubName, cluster) {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// ClusterEnrichment returns the row of the fields computed from the cluster, it's nil if no enricher is registered
+func ClusterEnrichment(leafHubName, clusterID string, cluster *clusterv1.ManagedCluster,
+) (*models.ManagedClusterEnrichment, error) {
+	if !Enabled() {
+		return nil, nil
+	}
+	fields, err := json.Marshal(Enrich(leafHubName, cluster))
+	if err != nil {
+		return nil, err
+	}
+	return &models.ManagedClusterEnrichment{
+		ClusterID:   clusterID,
+		LeafHubName: leafHubName,
+		ClusterName: cluster.GetName(),
+		Fields:      fields,
+	}, nil
+}
+
+// Save upserts the enrichments in batches, the existing row is only rewritten if its fields or cluster are changed
+func Save(db *gorm.DB, enrichments []models.ManagedClusterEnrichment, batchSize int) error {
+	if len(enrichments) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cluster_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"leaf_hub_name", "cluster_name", "fields", "updated_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL: "(managed_cluster_enrichments.leaf_hub_name, managed_cluster_enrichments.cluster_name, " +
+				"managed_cluster_enrichments.fields) IS DISTINCT FROM " +
+				"(excluded.leaf_hub_name, excluded.cluster_name, excluded.fields)",
+		}}},
+	}).CreateInBatches(enrichments, batchSize).Error
+}
@@ -0,0 +1,80 @@
+package enrichment
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+type hubEnricher struct{}
+
+func (e *hubEnricher) Name() string {
+	return "hub-enricher"
+}
+
+func (e *hubEnricher) Enrich(leafHubName string, cluster *clusterv1.ManagedCluster) map[string]string {
+	return map[string]string{"hub": leafHubName, "region": "overridden"}
+}
+
+func TestEnrich(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "enrichment.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+fields:
+  businessUnit:
+    label: acme.com/business-unit
+    default: unassigned
+  region:
+    claim: region.open-cluster-management.io
+    mapping:
+      us-east-1: americas
+`), 0o600))
+
+	defer func() { enrichers = nil }()
+	assert.False(t, Enabled())
+	enrichment, err := ClusterEnrichment("hub1", "id1", &clusterv1.ManagedCluster{})
+	assert.NoError(t, err)
+	assert.Nil(t, enrichment)
+
+	require.NoError(t, AddEnrichment(path))
+	assert.True(t, Enabled())
+
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"acme.com/business-unit": "payments"}},
+		Status: clusterv1.ManagedClusterStatus{ClusterClaims: []clusterv1.ManagedClusterClaim{
+			{Name: "region.open-cluster-management.io", Value: "us-east-1"},
+		}},
+	}
+	assert.Equal(t, map[string]string{"businessUnit": "payments", "region": "americas"}, Enrich("hub1", cluster))
+
+	// the default value is used for the missing label, the unmapped value is kept
+	cluster.Labels = nil
+	cluster.Status.ClusterClaims[0].Value = "ap-south-1"
+	assert.Equal(t, map[string]string{"businessUnit": "unassigned", "region": "ap-south-1"}, Enrich("hub1", cluster))
+
+	// the later enricher overrides the field
+	Register(&hubEnricher{})
+	enrichment, err = ClusterEnrichment("hub1", "id1", cluster)
+	assert.NoError(t, err)
+	assert.Equal(t, "cluster1", enrichment.ClusterName)
+	fields := map[string]string{}
+	require.NoError(t, json.Unmarshal(enrichment.Fields, &fields))
+	assert.Equal(t, map[string]string{"businessUnit": "unassigned", "region": "overridden", "hub": "hub1"}, fields)
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "enrichment.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+fields:
+  region:
+    label: region
+    claim: region.open-cluster-management.io
+`), 0o600))
+	_, err := LoadConfig(path)
+	assert.ErrorContains(t, err, "either a label or a claim")
+}
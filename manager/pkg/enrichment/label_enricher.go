@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package enrichment
+
+import (
+	"fmt"
+	"os"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the fields computed by the built-in enricher from the labels and claims of the managed clusters, e.g.
+//
+//	fields:
+//	  businessUnit:
+//	    label: acme.com/business-unit
+//	    default: unassigned
+//	  region:
+//	    claim: region.open-cluster-management.io
+//	    mapping:
+//	      us-east-1: americas
+//	      eu-west-1: emea
+type Config struct {
+	// Fields is the field name to how it's computed, the businessUnit and region fields have their own columns
+	Fields map[string]FieldConfig `json:"fields"`
+}
+
+// FieldConfig computes the field from either the label or the claim of the cluster.
+type FieldConfig struct {
+	Label string `json:"label,omitempty"`
+	Claim string `json:"claim,omitempty"`
+	// Mapping translates the value of the label or claim, the value is kept if it isn't in the mapping
+	Mapping map[string]string `json:"mapping,omitempty"`
+	// Default is the value of the field if the cluster doesn't have the label or claim
+	Default string `json:"default,omitempty"`
+}
+
+// LoadConfig reads the fields of the built-in enricher from the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the enrichment config %s: %w", path, err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the enrichment config %s: %w", path, err)
+	}
+	for name, field := range config.Fields {
+		if (field.Label == "") == (field.Claim == "") {
+			return nil, fmt.Errorf("the field %s must be computed from either a label or a claim", name)
+		}
+	}
+	return config, nil
+}
+
+// AddEnrichment registers the built-in enricher of the fields in the config file, it's disabled if the path is empty
+func AddEnrichment(configPath string) error {
+	if configPath == "" {
+		return nil
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	Register(&labelEnricher{fields: config.Fields})
+	return nil
+}
+
+type labelEnricher struct {
+	fields map[string]FieldConfig
+}
+
+func (e *labelEnricher) Name() string {
+	return "label-enricher"
+}
+
+func (e *labelEnricher) Enrich(leafHubName string, cluster *clusterv1.ManagedCluster) map[string]string {
+	fields := map[string]string{}
+	for name, field := range e.fields {
+		value, found := "", false
+		if field.Label != "" {
+			value, found = cluster.GetLabels()[field.Label]
+		} else {
+			for _, claim := range cluster.Status.ClusterClaims {
+				if claim.Name == field.Claim {
+					value, found = claim.Value, true
+					break
+				}
+			}
+		}
+		if !found || value == "" {
+			if field.Default != "" {
+				fields[name] = field.Default
+			}
+			continue
+		}
+		if mapped, ok := field.Mapping[value]; ok {
+			value = mapped
+		}
+		fields[name] = value
+	}
+	return fields
+}
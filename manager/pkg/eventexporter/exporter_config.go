@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package eventexporter
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// the supported sink types
+const (
+	KafkaSinkType = "kafka"
+	HTTPSinkType  = "http"
+)
+
+// Config is the egress configuration of the manager, the fleet events are republished to the sinks in the
+// CloudEvents format, e.g.
+//
+//	source: multicluster-global-hub
+//	sinks:
+//	- name: siem
+//	  type: kafka
+//	  kafka:
+//	    bootstrapServer: siem-kafka.example.com:9093
+//	    topic: fleet-events
+//	    caCertPath: /egress/siem/ca.crt
+//	- name: datalake
+//	  type: http
+//	  url: https://datalake.example.com/events
+//	  eventTypes: ["io.open-cluster-management.globalhub.policy.violation"]
+type Config struct {
+	// Source is the source of the exported events, the managed hub is set as the subject prefix of the event
+	Source string       `json:"source,omitempty"`
+	Sinks  []SinkConfig `json:"sinks"`
+}
+
+// SinkConfig describes where the events are republished to.
+type SinkConfig struct {
+	Name string `json:"name"`
+	// Type is one of the kafka and http
+	Type string `json:"type"`
+	// URL is the endpoint of the http sink, the events are posted in the binary content mode
+	URL string `json:"url,omitempty"`
+	// Headers are added to the requests of the http sink
+	Headers map[string]string `json:"headers,omitempty"`
+	// Kafka is the external kafka topic of the kafka sink
+	Kafka *KafkaSinkConfig `json:"kafka,omitempty"`
+	// EventTypes are the types of the events delivered to the sink, empty means all the types
+	EventTypes []string `json:"eventTypes,omitempty"`
+}
+
+type KafkaSinkConfig struct {
+	BootstrapServer string `json:"bootstrapServer"`
+	Topic           string `json:"topic"`
+	// the CA certificate of the kafka cluster, and the client certificate and key of the mutual TLS
+	CACertPath     string `json:"caCertPath,omitempty"`
+	ClientCertPath string `json:"clientCertPath,omitempty"`
+	ClientKeyPath  string `json:"clientKeyPath,omitempty"`
+}
+
+// LoadConfig reads the egress configuration from the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the event exporter config %s: %w", path, err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the event exporter config %s: %w", path, err)
+	}
+	if config.Source == "" {
+		config.Source = defaultSource
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	names := map[string]bool{}
+	for _, sink := range c.Sinks {
+		if sink.Name == "" {
+			return fmt.Errorf("the name of the event sink is required")
+		}
+		if names[sink.Name] {
+			return fmt.Errorf("the event sink %s is duplicated", sink.Name)
+		}
+		names[sink.Name] = true
+
+		switch sink.Type {
+		case KafkaSinkType:
+			if sink.Kafka == nil || sink.Kafka.BootstrapServer == "" || sink.Kafka.Topic == "" {
+				return fmt.Errorf("the bootstrap server and topic of the kafka event sink %s are required", sink.Name)
+			}
+		case HTTPSinkType:
+			if sink.URL == "" {
+				return fmt.Errorf("the url of the http event sink %s is required", sink.Name)
+			}
+		default:
+			return fmt.Errorf("unsupported type %q of the event sink %s", sink.Type, sink.Name)
+		}
+
+		for _, eventType := range sink.EventTypes {
+			if !utils.ContainsString(EventTypes, eventType) {
+				return fmt.Errorf("unsupported event type %q of the event sink %s", eventType, sink.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Accept returns whether the event type is delivered to the sink.
+func (s *SinkConfig) Accept(eventType string) bool {
+	return len(s.EventTypes) == 0 || utils.ContainsString(s.EventTypes, eventType)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package eventexporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	eventQueueSize = 1000
+	sendTimeout    = 10 * time.Second
+)
+
+// the exporter is nil if the egress isn't configured
+var exporter *eventExporter
+
+type eventExporter struct {
+	log    logr.Logger
+	source string
+	sinks  []SinkConfig
+	// sink name to the sink
+	senders map[string]Sink
+	events  chan *fleetEvent
+	// policyID to the namespace and name of the policy
+	policyNames map[string][2]string
+}
+
+// AddEventExporter loads the egress configuration and adds the exporter to the manager. The export is disabled if
+// the config path is empty.
+func AddEventExporter(mgr ctrl.Manager, configPath string) error {
+	if configPath == "" {
+		return nil
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	e, err := newEventExporter(config, &http.Client{Timeout: sendTimeout})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(e); err != nil {
+		return fmt.Errorf("failed to add the event exporter to the manager: %w", err)
+	}
+	exporter = e
+	return nil
+}
+
+func newEventExporter(config *Config, httpClient *http.Client) (*eventExporter, error) {
+	e := &eventExporter{
+		log:         ctrl.Log.WithName("event-exporter"),
+		source:      config.Source,
+		sinks:       config.Sinks,
+		senders:     map[string]Sink{},
+		events:      make(chan *fleetEvent, eventQueueSize),
+		policyNames: map[string][2]string{},
+	}
+	for _, sinkConfig := range config.Sinks {
+		sink, err := NewSink(sinkConfig, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		e.senders[sinkConfig.Name] = sink
+	}
+	return e, nil
+}
+
+// Enabled returns whether the events are exported, so the caller can skip collecting them
+func Enabled() bool {
+	return exporter != nil
+}
+
+// ExportClustersCreated queues the cluster created events of the managed hub
+func ExportClustersCreated(clusters ...ClusterEvent) {
+	for i := range clusters {
+		enqueue(ClusterCreatedType, clusters[i].LeafHubName+"/"+clusters[i].ClusterName, &clusters[i])
+	}
+}
+
+// ExportClustersDeleted queues the cluster deleted events of the managed hub
+func ExportClustersDeleted(clusters ...ClusterEvent) {
+	for i := range clusters {
+		enqueue(ClusterDeletedType, clusters[i].LeafHubName+"/"+clusters[i].ClusterName, &clusters[i])
+	}
+}
+
+// ExportPolicyViolations queues the policy violation events, the policy name is resolved by the policy id before
+// the event is sent
+func ExportPolicyViolations(violations ...PolicyViolationEvent) {
+	for i := range violations {
+		enqueue(PolicyViolationType, violations[i].LeafHubName+"/"+violations[i].ClusterName, &violations[i])
+	}
+}
+
+// enqueue doesn't block the caller, the event is dropped if the queue is full
+func enqueue(eventType, subject string, data interface{}) {
+	if exporter == nil {
+		return
+	}
+	select {
+	case exporter.events <- &fleetEvent{eventType: eventType, subject: subject, time: time.Now(), data: data}:
+	default:
+		exporter.log.Info("the event queue is full, drop the event", "type", eventType, "subject", subject)
+	}
+}
+
+// NeedLeaderElection is false since the events are collected by the status handlers on all the replicas, e.g. with
+// the hub sharding
+func (e *eventExporter) NeedLeaderElection() bool {
+	return false
+}
+
+func (e *eventExporter) Start(ctx context.Context) error {
+	e.log.Info("start event exporter", "sinks", len(e.sinks))
+	for {
+		select {
+		case <-ctx.Done():
+			e.log.Info("event exporter is stopped")
+			return nil
+		case evt := <-e.events:
+			e.dispatch(ctx, evt)
+		}
+	}
+}
+
+func (e *eventExporter) dispatch(ctx context.Context, evt *fleetEvent) {
+	if violation, ok := evt.data.(*PolicyViolationEvent); ok && violation.PolicyName == "" {
+		if err := e.resolvePolicyName(violation); err != nil {
+			e.log.Error(err, "failed to get the policy name", "policyID", violation.PolicyID)
+		}
+	}
+
+	cloudEvent, err := evt.toCloudEvent(e.source)
+	if err != nil {
+		e.log.Error(err, "failed to build the cloudevent", "type", evt.eventType, "subject", evt.subject)
+		return
+	}
+	for _, sink := range e.sinks {
+		if !sink.Accept(evt.eventType) {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		if err := e.senders[sink.Name].Send(sendCtx, cloudEvent); err != nil {
+			e.log.Error(err, "failed to export the event", "sink", sink.Name, "type", evt.eventType,
+				"subject", evt.subject)
+		}
+		cancel()
+	}
+}
+
+func (e *eventExporter) resolvePolicyName(evt *PolicyViolationEvent) error {
+	if names, found := e.policyNames[evt.PolicyID]; found {
+		evt.PolicyNamespace, evt.PolicyName = names[0], names[1]
+		return nil
+	}
+
+	sql := `SELECT payload->'metadata'->>'namespace' AS namespace, payload->'metadata'->>'name' AS name
+		FROM spec.policies WHERE id = ?`
+	if evt.LocalPolicy {
+		sql = `SELECT payload->'metadata'->>'namespace' AS namespace, payload->'metadata'->>'name' AS name
+			FROM local_spec.policies WHERE policy_id = ?`
+	}
+	var policy struct {
+		Namespace string
+		Name      string
+	}
+	if err := database.GetGorm().Raw(sql, evt.PolicyID).Scan(&policy).Error; err != nil {
+		return err
+	}
+	if policy.Name == "" {
+		return fmt.Errorf("the policy isn't found")
+	}
+	e.policyNames[evt.PolicyID] = [2]string{policy.Namespace, policy.Name}
+	evt.PolicyNamespace, evt.PolicyName = policy.Namespace, policy.Name
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package eventexporter
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+)
+
+const defaultSource = "multicluster-global-hub"
+
+// the types of the exported events, they're normalized from the status of the managed hubs
+const (
+	ClusterCreatedType  = "io.open-cluster-management.globalhub.cluster.created"
+	ClusterDeletedType  = "io.open-cluster-management.globalhub.cluster.deleted"
+	PolicyViolationType = "io.open-cluster-management.globalhub.policy.violation"
+)
+
+var EventTypes = []string{ClusterCreatedType, ClusterDeletedType, PolicyViolationType}
+
+// ClusterEvent is the data of the cluster created and deleted events. The cluster id is empty if the deleted cluster
+// is reported by the name.
+type ClusterEvent struct {
+	LeafHubName string `json:"leafHubName"`
+	ClusterName string `json:"clusterName"`
+	ClusterID   string `json:"clusterId,omitempty"`
+}
+
+// PolicyViolationEvent is the data of the policy violation event, it's exported once the cluster turns into non
+// compliant.
+type PolicyViolationEvent struct {
+	LeafHubName        string `json:"leafHubName"`
+	PolicyID           string `json:"policyId"`
+	PolicyName         string `json:"policyName,omitempty"`
+	PolicyNamespace    string `json:"policyNamespace,omitempty"`
+	ClusterName        string `json:"clusterName"`
+	PreviousCompliance string `json:"previousCompliance,omitempty"`
+	LocalPolicy        bool   `json:"localPolicy"`
+}
+
+// fleetEvent is the event queued in the exporter, it's converted into the cloudevent once it's dispatched
+type fleetEvent struct {
+	eventType string
+	// subject is the resource of the event in the global hub, e.g. <managed hub>/<cluster>
+	subject string
+	time    time.Time
+	data    interface{}
+}
+
+func (e *fleetEvent) toCloudEvent(source string) (cloudevents.Event, error) {
+	evt := cloudevents.NewEvent()
+	evt.SetID(uuid.New().String())
+	evt.SetType(e.eventType)
+	evt.SetSource(source)
+	evt.SetSubject(e.subject)
+	evt.SetTime(e.time)
+	err := evt.SetData(cloudevents.ApplicationJSON, e.data)
+	return evt, err
+}
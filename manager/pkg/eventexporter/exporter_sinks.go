@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package eventexporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+)
+
+// Sink republishes the cloudevent to the external system.
+type Sink interface {
+	Send(ctx context.Context, evt cloudevents.Event) error
+}
+
+func NewSink(config SinkConfig, httpClient *http.Client) (Sink, error) {
+	switch config.Type {
+	case KafkaSinkType:
+		return newKafkaSink(config)
+	case HTTPSinkType:
+		return newHTTPSink(config, httpClient)
+	default:
+		return nil, fmt.Errorf("unsupported type %q of the event sink %s", config.Type, config.Name)
+	}
+}
+
+// newKafkaSink sends the events to the external kafka topic with the generic producer, so the rotated credentials
+// are picked up in place
+func newKafkaSink(config SinkConfig) (Sink, error) {
+	transportConfig := &transport.TransportConfig{
+		TransportType: string(transport.Kafka),
+		KafkaConfig: &transport.KafkaConfig{
+			BootstrapServer: config.Kafka.BootstrapServer,
+			EnableTLS:       config.Kafka.CACertPath != "",
+			CaCertPath:      config.Kafka.CACertPath,
+			ClientCertPath:  config.Kafka.ClientCertPath,
+			ClientKeyPath:   config.Kafka.ClientKeyPath,
+			Topics:          &transport.ClusterTopic{},
+			ProducerConfig:  &transport.KafkaProducerConfig{},
+		},
+	}
+	kafkaProducer, err := producer.NewGenericProducer(transportConfig, config.Kafka.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the producer of the event sink %s: %w", config.Name, err)
+	}
+	return &kafkaSink{producer: kafkaProducer}, nil
+}
+
+type kafkaSink struct {
+	producer transport.Producer
+}
+
+// Send keys the message by the subject, so the events of the same resource are kept in order
+func (s *kafkaSink) Send(ctx context.Context, evt cloudevents.Event) error {
+	return s.producer.SendEvent(kafka_confluent.WithMessageKey(ctx, evt.Subject()), evt)
+}
+
+// httpSink posts the events in the binary content mode of the CloudEvents HTTP binding
+type httpSink struct {
+	client cloudevents.Client
+}
+
+func newHTTPSink(config SinkConfig, httpClient *http.Client) (Sink, error) {
+	options := []cehttp.Option{cehttp.WithTarget(config.URL), cehttp.WithClient(*httpClient)}
+	for key, val := range config.Headers {
+		options = append(options, cehttp.WithHeader(key, val))
+	}
+	protocol, err := cehttp.New(options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the http protocol of the event sink %s: %w", config.Name, err)
+	}
+	client, err := cloudevents.NewClient(protocol)
+	if err != nil {
+		return nil, err
+	}
+	return &httpSink{client: client}, nil
+}
+
+func (s *httpSink) Send(ctx context.Context, evt cloudevents.Event) error {
+	result := s.client.Send(ctx, evt)
+	if !cloudevents.IsACK(result) {
+		return fmt.Errorf("failed to send the event: %w", result)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package eventexporter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid config",
+			content: `
+sinks:
+- name: siem
+  type: kafka
+  kafka:
+    bootstrapServer: kafka.example.com:9093
+    topic: fleet-events
+- name: datalake
+  type: http
+  url: https://datalake.example.com/events
+  eventTypes: ["io.open-cluster-management.globalhub.policy.violation"]
+`,
+		},
+		{
+			name: "kafka sink without topic",
+			content: `
+sinks:
+- name: siem
+  type: kafka
+  kafka:
+    bootstrapServer: kafka.example.com:9093
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported sink type",
+			content: `
+sinks:
+- name: siem
+  type: syslog
+  url: https://example.com
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported event type",
+			content: `
+sinks:
+- name: datalake
+  type: http
+  url: https://example.com
+  eventTypes: ["cluster.created"]
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "exporter.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			config, err := LoadConfig(path)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			if err == nil {
+				assert.Equal(t, defaultSource, config.Source)
+			}
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	type request struct {
+		headers http.Header
+		body    []byte
+	}
+	requests := map[string][]request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests[r.URL.Path] = append(requests[r.URL.Path], request{headers: r.Header, body: body})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	e, err := newEventExporter(&Config{
+		Source: "global-hub-east",
+		Sinks: []SinkConfig{
+			{Name: "siem", Type: HTTPSinkType, URL: server.URL + "/siem", Headers: map[string]string{"X-Token": "xxx"}},
+			{Name: "datalake", Type: HTTPSinkType, URL: server.URL + "/datalake", EventTypes: []string{ClusterCreatedType}},
+		},
+	}, server.Client())
+	require.NoError(t, err)
+
+	exporter = e
+	defer func() { exporter = nil }()
+	ExportClustersCreated(ClusterEvent{LeafHubName: "hub1", ClusterName: "cluster1", ClusterID: "1234"})
+	ExportPolicyViolations(PolicyViolationEvent{
+		LeafHubName: "hub1", PolicyID: "5678", PolicyName: "policy-x", PolicyNamespace: "default",
+		ClusterName: "cluster1", PreviousCompliance: "compliant",
+	})
+	require.Len(t, e.events, 2)
+	e.dispatch(context.Background(), <-e.events)
+	e.dispatch(context.Background(), <-e.events)
+
+	// the siem receives all the events, and the datalake only receives the cluster created event
+	require.Len(t, requests["/siem"], 2)
+	require.Len(t, requests["/datalake"], 1)
+
+	created := requests["/datalake"][0]
+	assert.Equal(t, ClusterCreatedType, created.headers.Get("Ce-Type"))
+	assert.Equal(t, "global-hub-east", created.headers.Get("Ce-Source"))
+	assert.Equal(t, "hub1/cluster1", created.headers.Get("Ce-Subject"))
+	cluster := &ClusterEvent{}
+	require.NoError(t, json.Unmarshal(created.body, cluster))
+	assert.Equal(t, "1234", cluster.ClusterID)
+
+	violation := requests["/siem"][1]
+	assert.Equal(t, PolicyViolationType, violation.headers.Get("Ce-Type"))
+	assert.Equal(t, "xxx", violation.headers.Get("X-Token"))
+	policy := &PolicyViolationEvent{}
+	require.NoError(t, json.Unmarshal(violation.body, policy))
+	assert.Equal(t, "policy-x", policy.PolicyName)
+}
@@ -25,6 +25,8 @@ import (
 const (
 	HubActive   = "active"
 	HubInactive = "inactive"
+	// HubDetached is the tombstone of the hub which is detached from the global hub, its data is purged later
+	HubDetached = "detached"
 
 	// heartbeatInterval = 1 * time.Minute
 	ActiveTimeout = 5 * time.Minute // if heartbeat < (now - ActiveTimeout), then status = inactive, vice versa
@@ -39,12 +41,22 @@ type hubManagement struct {
 	activeTimeout time.Duration
 }
 
-func AddHubManagement(mgr ctrl.Manager, producer transport.Producer) error {
+// AddHubManagement adds the hub management into the manager, the hub is marked as inactive once it doesn't send
+// the heartbeat within the activeTimeout, the default ActiveTimeout is used if it isn't positive
+func AddHubManagement(mgr ctrl.Manager, producer transport.Producer, activeTimeout time.Duration) error {
+	if activeTimeout <= 0 {
+		activeTimeout = ActiveTimeout
+	}
+	// probe at least twice within the timeout, so the dark hub is detected no later than 1.5 * activeTimeout
+	probeDuration := ProbeDuration
+	if probeDuration > activeTimeout/2 {
+		probeDuration = activeTimeout / 2
+	}
 	return mgr.Add(&hubManagement{
 		log:           ctrl.Log.WithName("hub-management"),
 		producer:      producer,
-		probeDuration: ProbeDuration,
-		activeTimeout: ActiveTimeout,
+		probeDuration: probeDuration,
+		activeTimeout: activeTimeout,
 	})
 }
 
@@ -98,14 +110,13 @@ func (h *hubManagement) update(ctx context.Context) error {
 
 func (h *hubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeartbeat, thresholdTime time.Time) error {
 	for _, hub := range hubs {
-		err := wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, true,
-			func(ctx context.Context) (bool, error) {
-				if e := h.cleanup(hub.Name); e != nil {
-					h.log.Info("cleanup the hub resource failed, retrying...", "name", hub.Name, "err", e.Error())
-					return false, nil
-				}
-				return true, nil
-			})
+		err := database.Retry(ctx, func(ctx context.Context) error {
+			if e := h.cleanup(hub.Name); e != nil {
+				h.log.Info("cleanup the hub resource failed", "name", hub.Name, "err", e.Error())
+				return e
+			}
+			return nil
+		})
 		if err != nil {
 			return err
 		}
@@ -116,41 +127,45 @@ func (h *hubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeart
 func (h *hubManagement) cleanup(hubName string) error {
 	db := database.GetGorm()
 	return db.Transaction(func(tx *gorm.DB) error {
-		// soft delete the cluster
-		e := tx.Where(&models.ManagedCluster{
-			LeafHubName: hubName,
-		}).Delete(&models.ManagedCluster{}).Error
-		if e != nil {
-			return e
-		}
-
-		// soft delete the hub info
-		e = tx.Where(&models.LeafHub{
-			LeafHubName: hubName,
-		}).Delete(&models.LeafHub{}).Error
-		if e != nil {
+		if e := CleanupHubResources(tx, hubName); e != nil {
 			return e
 		}
-		// soft delete the policy from the hub
-		e = tx.Where(&models.LocalSpecPolicy{
-			LeafHubName: hubName,
-		}).Delete(&models.LocalSpecPolicy{}).Error
-		if e != nil {
-			return e
-		}
-		// delete the compliance
-		e = tx.Where(&models.LocalStatusCompliance{
-			LeafHubName: hubName,
-		}).Delete(&models.LocalStatusCompliance{}).Error
-		if e != nil {
-			return e
-		}
-
 		// inactive the hub status
 		return tx.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hubName).Update("status", HubInactive).Error
 	})
 }
 
+// CleanupHubResources soft deletes the clusters, hub info and policies of the hub, and deletes its compliance. The
+// soft deleted records are removed by the data retention job, or restored once the hub resyncs them.
+func CleanupHubResources(tx *gorm.DB, hubName string) error {
+	// soft delete the cluster
+	e := tx.Where(&models.ManagedCluster{
+		LeafHubName: hubName,
+	}).Delete(&models.ManagedCluster{}).Error
+	if e != nil {
+		return e
+	}
+
+	// soft delete the hub info
+	e = tx.Where(&models.LeafHub{
+		LeafHubName: hubName,
+	}).Delete(&models.LeafHub{}).Error
+	if e != nil {
+		return e
+	}
+	// soft delete the policy from the hub
+	e = tx.Where(&models.LocalSpecPolicy{
+		LeafHubName: hubName,
+	}).Delete(&models.LocalSpecPolicy{}).Error
+	if e != nil {
+		return e
+	}
+	// delete the compliance
+	return tx.Where(&models.LocalStatusCompliance{
+		LeafHubName: hubName,
+	}).Delete(&models.LocalStatusCompliance{}).Error
+}
+
 func (h *hubManagement) reactive(ctx context.Context, hubs []models.LeafHubHeartbeat, thresholdTime time.Time) error {
 	// resync hub resources
 	db := database.GetGorm()
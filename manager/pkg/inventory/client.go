@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const apiPrefix = "/api/inventory/v1beta1/resources/"
+
+var (
+	errConflict = errors.New("the resource already exists")
+	errNotFound = errors.New("the resource isn't found")
+)
+
+// inventoryClient reports the resources to the inventory API with the REST requests, the bearer token is read from
+// the file on each request so the rotated token is picked up
+type inventoryClient struct {
+	serverURL  string
+	tokenPath  string
+	httpClient *http.Client
+}
+
+func newInventoryClient(config *InventoryConfig) (*inventoryClient, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CACertPath != "" {
+		caCert, err := os.ReadFile(filepath.Clean(config.CACertPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA certificate of the inventory: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to append the CA certificate of the inventory")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.ClientCertPath != "" || config.ClientKeyPath != "" {
+		cert, err := tls.LoadX509KeyPair(config.ClientCertPath, config.ClientKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate of the inventory: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &inventoryClient{
+		serverURL: strings.TrimSuffix(config.ServerURL, "/"),
+		tokenPath: config.TokenPath,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// Create reports the new resource, it returns errConflict if the resource is already reported
+func (c *inventoryClient) Create(ctx context.Context, res *resource) error {
+	return c.do(ctx, http.MethodPost, res.kind.path, map[string]interface{}{res.kind.field: res.body()})
+}
+
+// Update reports the changed resource, it returns errNotFound if the resource isn't reported
+func (c *inventoryClient) Update(ctx context.Context, res *resource) error {
+	return c.do(ctx, http.MethodPut, res.kind.path, map[string]interface{}{res.kind.field: res.body()})
+}
+
+// Delete removes the resource reported by the global hub, the deleted resource is ignored
+func (c *inventoryClient) Delete(ctx context.Context, res *resource) error {
+	err := c.do(ctx, http.MethodDelete, res.kind.path, map[string]interface{}{"reporter_data": res.ReporterData})
+	if errors.Is(err, errNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (c *inventoryClient) do(ctx context.Context, method, path string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.serverURL+apiPrefix+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.tokenPath != "" {
+		token, err := os.ReadFile(filepath.Clean(c.tokenPath))
+		if err != nil {
+			return fmt.Errorf("failed to read the token of the inventory: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection is reused
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusConflict:
+		return errConflict
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	default:
+		return fmt.Errorf("failed to %s the %s: %s %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+}
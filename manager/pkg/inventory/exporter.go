@@ -0,0 +1,196 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// InventoryConfig is the Kessel/common inventory API which the clusters and policies are exported to, the export is
+// disabled if the server url is empty
+type InventoryConfig struct {
+	// ServerURL is the url of the inventory API, e.g. https://kessel-inventory.example.com
+	ServerURL string
+	// the CA certificate of the server, and the client certificate and key if the server requires the mutual TLS
+	CACertPath     string
+	ClientCertPath string
+	ClientKeyPath  string
+	// TokenPath is the file of the bearer token, it's read on each request
+	TokenPath string
+	// ReporterInstanceID identifies the global hub as the reporter of the resources
+	ReporterInstanceID string
+	// WorkspaceID is the workspace of the reported resources, the default workspace is used if it's empty
+	WorkspaceID  string
+	SyncInterval time.Duration
+}
+
+func (c *InventoryConfig) Enabled() bool {
+	return c != nil && c.ServerURL != ""
+}
+
+type reporter interface {
+	Create(ctx context.Context, res *resource) error
+	Update(ctx context.Context, res *resource) error
+	Delete(ctx context.Context, res *resource) error
+}
+
+// exporter keeps the inventory in sync with the managed clusters and the policies of the managed hubs, it reports the
+// new and changed resources and deletes the removed ones on each interval. The reported resources are tracked in
+// memory, so all the resources are reported again after the restart, and the existing ones are updated.
+type exporter struct {
+	log          logr.Logger
+	config       *InventoryConfig
+	client       reporter
+	syncInterval time.Duration
+	// reported is the digest of the reported resources by the key
+	reported map[string]*reportedResource
+}
+
+type reportedResource struct {
+	resource *resource
+	digest   string
+}
+
+// AddInventoryExporter adds the exporter into the manager if the inventory API is configured, it only runs on the
+// leader replica
+func AddInventoryExporter(mgr ctrl.Manager, config *InventoryConfig) error {
+	if !config.Enabled() {
+		return nil
+	}
+	if config.ReporterInstanceID == "" {
+		return fmt.Errorf("the reporter instance id is required to export the inventory")
+	}
+	client, err := newInventoryClient(config)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(newExporter(config, client))
+}
+
+func newExporter(config *InventoryConfig, client reporter) *exporter {
+	return &exporter{
+		log:          ctrl.Log.WithName("inventory-exporter"),
+		config:       config,
+		client:       client,
+		syncInterval: config.SyncInterval,
+		reported:     map[string]*reportedResource{},
+	}
+}
+
+func (e *exporter) Start(ctx context.Context) error {
+	e.log.Info("export the inventory", "server", e.config.ServerURL, "interval", e.syncInterval)
+	ticker := time.NewTicker(e.syncInterval)
+	defer ticker.Stop()
+	for {
+		resources, err := loadResources(database.GetGorm(), e.config)
+		if err != nil {
+			e.log.Error(err, "failed to load the inventory from the database")
+		} else if err := e.sync(ctx, resources); err != nil {
+			e.log.Error(err, "failed to export the inventory")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync reports the resources which are new or changed since the last sync, and deletes the ones which are removed.
+// The failed resource is retried on the next sync.
+func (e *exporter) sync(ctx context.Context, resources []*resource) error {
+	var errs []error
+	desired := map[string]bool{}
+	created, updated, deleted := 0, 0, 0
+	for _, res := range resources {
+		key, digest := res.key(), res.digest()
+		desired[key] = true
+		reported, ok := e.reported[key]
+		if ok && reported.digest == digest {
+			continue
+		}
+		if err := e.report(ctx, res, ok); err != nil {
+			errs = append(errs, fmt.Errorf("failed to report %s: %w", key, err))
+			continue
+		}
+		if ok {
+			updated++
+		} else {
+			created++
+		}
+		e.reported[key] = &reportedResource{resource: res, digest: digest}
+	}
+
+	for key, reported := range e.reported {
+		if desired[key] {
+			continue
+		}
+		if err := e.client.Delete(ctx, reported.resource); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", key, err))
+			continue
+		}
+		deleted++
+		delete(e.reported, key)
+	}
+
+	if created+updated+deleted > 0 {
+		e.log.V(2).Info("exported the inventory", "created", created, "updated", updated, "deleted", deleted)
+	}
+	return errors.Join(errs...)
+}
+
+// report creates the resource if it isn't reported, otherwise updates it. The resource reported before the restart is
+// updated if it already exists, and the resource removed from the inventory is created again.
+func (e *exporter) report(ctx context.Context, res *resource, reported bool) error {
+	if !reported {
+		err := e.client.Create(ctx, res)
+		if !errors.Is(err, errConflict) {
+			return err
+		}
+	}
+	err := e.client.Update(ctx, res)
+	if errors.Is(err, errNotFound) {
+		return e.client.Create(ctx, res)
+	}
+	return err
+}
+
+// loadResources returns the managed clusters and the policies of the managed hubs, the deleted ones are excluded
+func loadResources(db *gorm.DB, config *InventoryConfig) ([]*resource, error) {
+	var clusters []models.ManagedCluster
+	if err := db.Find(&clusters).Error; err != nil {
+		return nil, err
+	}
+	var policies []models.LocalSpecPolicy
+	if err := db.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	resources := make([]*resource, 0, len(clusters)+len(policies))
+	for _, cluster := range clusters {
+		res, err := newClusterResource(config, cluster.LeafHubName, cluster.ClusterID, cluster.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the cluster %s: %w", cluster.ClusterID, err)
+		}
+		resources = append(resources, res)
+	}
+	for _, policy := range policies {
+		res, err := newPolicyResource(config, policy.LeafHubName, policy.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the policy %s: %w", policy.PolicyID, err)
+		}
+		resources = append(resources, res)
+	}
+	return resources, nil
+}
@@ -0,0 +1,209 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+)
+
+// fakeInventory records the requests and keeps the reported resources by the local resource id
+type fakeInventory struct {
+	mu        sync.Mutex
+	requests  []string
+	resources map[string]bool
+	tokens    []string
+}
+
+func (f *fakeInventory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = append(f.tokens, r.Header.Get("Authorization"))
+
+	body := map[string]map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	reporter := map[string]interface{}{}
+	for _, field := range body {
+		if data, ok := field["reporter_data"].(map[string]interface{}); ok {
+			reporter = data
+		} else if _, ok := field["local_resource_id"]; ok {
+			reporter = field
+		}
+	}
+	id, _ := reporter["local_resource_id"].(string)
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path+" "+id)
+
+	switch r.Method {
+	case http.MethodPost:
+		if f.resources[id] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		f.resources[id] = true
+	case http.MethodPut, http.MethodDelete:
+		if !f.resources[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(f.resources, id)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeInventory) popRequests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	requests := f.requests
+	f.requests = nil
+	return requests
+}
+
+func TestExporterSync(t *testing.T) {
+	inventory := &fakeInventory{resources: map[string]bool{"hub1/default/policy1": true}}
+	server := httptest.NewServer(inventory)
+	defer server.Close()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := &InventoryConfig{ServerURL: server.URL, TokenPath: tokenPath, ReporterInstanceID: "global-hub"}
+	client, err := newInventoryClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newExporter(config, client)
+
+	cluster := newCluster(t, config, "cluster1", metav1.ConditionTrue)
+	policy := newPolicy(t, config, false)
+
+	// the policy reported before the restart is updated
+	if err := e.sync(context.Background(), []*resource{cluster, policy}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, inventory.popRequests(), []string{
+		"POST /api/inventory/v1beta1/resources/k8s-clusters hub1/cluster1",
+		"POST /api/inventory/v1beta1/resources/k8s-policies hub1/default/policy1",
+		"PUT /api/inventory/v1beta1/resources/k8s-policies hub1/default/policy1",
+	})
+	for _, token := range inventory.tokens {
+		if token != "Bearer secret" {
+			t.Errorf("got the authorization %q, want %q", token, "Bearer secret")
+		}
+	}
+
+	// the unchanged resources aren't reported again
+	if err := e.sync(context.Background(), []*resource{cluster, policy}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, inventory.popRequests(), nil)
+
+	// the changed cluster is updated and the removed policy is deleted
+	cluster = newCluster(t, config, "cluster1", metav1.ConditionFalse)
+	if err := e.sync(context.Background(), []*resource{cluster}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, inventory.popRequests(), []string{
+		"PUT /api/inventory/v1beta1/resources/k8s-clusters hub1/cluster1",
+		"DELETE /api/inventory/v1beta1/resources/k8s-policies hub1/default/policy1",
+	})
+
+	// the cluster removed from the inventory is created again once it's changed
+	delete(inventory.resources, "hub1/cluster1")
+	cluster = newCluster(t, config, "cluster1", metav1.ConditionTrue)
+	if err := e.sync(context.Background(), []*resource{cluster}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, inventory.popRequests(), []string{
+		"PUT /api/inventory/v1beta1/resources/k8s-clusters hub1/cluster1",
+		"POST /api/inventory/v1beta1/resources/k8s-clusters hub1/cluster1",
+	})
+}
+
+func TestNewResources(t *testing.T) {
+	config := &InventoryConfig{ReporterInstanceID: "global-hub", WorkspaceID: "fleet"}
+
+	cluster := newCluster(t, config, "cluster1", metav1.ConditionTrue)
+	data := cluster.ResourceData.(clusterData)
+	if data.ClusterStatus != "READY" || data.KubeVendor != "OPENSHIFT" || data.CloudPlatform != "AWS_UPI" ||
+		data.VendorVersion != "4.15.0" || data.ExternalClusterID != "cluster1-id" {
+		t.Errorf("unexpected cluster data: %+v", data)
+	}
+	if cluster.body()["metadata"].(map[string]interface{})["workspace_id"] != "fleet" {
+		t.Errorf("the workspace isn't set: %v", cluster.body())
+	}
+
+	policy := newPolicy(t, config, true)
+	if data := policy.ResourceData.(policyData); !data.Disabled || data.Severity != "HIGH" {
+		t.Errorf("unexpected policy data: %+v", data)
+	}
+}
+
+func newCluster(t *testing.T, config *InventoryConfig, name string, available metav1.ConditionStatus) *resource {
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"vendor": "OpenShift", "cloud": "Amazon", "openshiftVersion": "4.15.0"},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{{Type: clusterv1.ManagedClusterConditionAvailable, Status: available}},
+		},
+	}
+	payload, err := json.Marshal(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := newClusterResource(config, "hub1", name+"-id", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func newPolicy(t *testing.T, config *InventoryConfig, disabled bool) *resource {
+	policy := &policyv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy1", Namespace: "default"},
+		Spec: policyv1.PolicySpec{
+			Disabled: disabled,
+			PolicyTemplates: []*policyv1.PolicyTemplate{
+				{ObjectDefinition: runtime.RawExtension{Raw: []byte(`{"spec":{"severity":"low"}}`)}},
+				{ObjectDefinition: runtime.RawExtension{Raw: []byte(`{"spec":{"severity":"high"}}`)}},
+			},
+		},
+	}
+	payload, err := json.Marshal(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := newPolicyResource(config, "hub1", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func assertRequests(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got the requests %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got the request %q, want %q", got[i], want[i])
+		}
+	}
+}
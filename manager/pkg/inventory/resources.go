@@ -0,0 +1,201 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package inventory
+
+import (
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+)
+
+const reporterType = "ACM"
+
+type resourceKind struct {
+	// path is the resources path of the kind in the inventory API
+	path string
+	// field is the field of the resource in the request body
+	field        string
+	resourceType string
+}
+
+var (
+	clusterKind = resourceKind{path: "k8s-clusters", field: "k8s_cluster", resourceType: "k8s_cluster"}
+	policyKind  = resourceKind{path: "k8s-policies", field: "k8s_policy", resourceType: "k8s_policy"}
+)
+
+type reporterData struct {
+	ReporterType       string `json:"reporter_type"`
+	ReporterInstanceID string `json:"reporter_instance_id"`
+	// LocalResourceID is the id of the resource in the global hub, e.g. <managed hub>/<cluster>
+	LocalResourceID string `json:"local_resource_id"`
+}
+
+type clusterData struct {
+	ExternalClusterID string `json:"external_cluster_id"`
+	ClusterStatus     string `json:"cluster_status"`
+	KubeVersion       string `json:"kube_version,omitempty"`
+	KubeVendor        string `json:"kube_vendor,omitempty"`
+	VendorVersion     string `json:"vendor_version,omitempty"`
+	CloudPlatform     string `json:"cloud_platform,omitempty"`
+}
+
+type policyData struct {
+	Disabled bool   `json:"disabled"`
+	Severity string `json:"severity"`
+}
+
+// resource is a cluster or a policy reported to the inventory
+type resource struct {
+	kind         resourceKind
+	WorkspaceID  string
+	ReporterData reporterData
+	ResourceData interface{}
+}
+
+func (r *resource) key() string {
+	return r.kind.resourceType + "/" + r.ReporterData.LocalResourceID
+}
+
+func (r *resource) body() map[string]interface{} {
+	metadata := map[string]interface{}{"resource_type": r.kind.resourceType}
+	if r.WorkspaceID != "" {
+		metadata["workspace_id"] = r.WorkspaceID
+	}
+	return map[string]interface{}{
+		"metadata":      metadata,
+		"reporter_data": r.ReporterData,
+		"resource_data": r.ResourceData,
+	}
+}
+
+// digest is compared with the reported one to skip the unchanged resources
+func (r *resource) digest() string {
+	data, _ := json.Marshal(r.body())
+	return string(data)
+}
+
+// newClusterResource converts the managed cluster of the managed hub to the k8s cluster of the inventory
+func newClusterResource(config *InventoryConfig, hubName, clusterID string, payload []byte) (*resource, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	if err := json.Unmarshal(payload, cluster); err != nil {
+		return nil, err
+	}
+	labels := cluster.GetLabels()
+	return &resource{
+		kind:        clusterKind,
+		WorkspaceID: config.WorkspaceID,
+		ReporterData: reporterData{
+			ReporterType:       reporterType,
+			ReporterInstanceID: config.ReporterInstanceID,
+			LocalResourceID:    hubName + "/" + cluster.GetName(),
+		},
+		ResourceData: clusterData{
+			ExternalClusterID: clusterID,
+			ClusterStatus:     clusterStatus(cluster.Status.Conditions),
+			KubeVersion:       cluster.Status.Version.Kubernetes,
+			KubeVendor:        kubeVendor(labels["vendor"]),
+			VendorVersion:     labels["openshiftVersion"],
+			CloudPlatform:     cloudPlatform(labels["cloud"]),
+		},
+	}, nil
+}
+
+// newPolicyResource converts the policy of the managed hub to the k8s policy of the inventory
+func newPolicyResource(config *InventoryConfig, hubName string, payload []byte) (*resource, error) {
+	policy := &policyv1.Policy{}
+	if err := json.Unmarshal(payload, policy); err != nil {
+		return nil, err
+	}
+	return &resource{
+		kind:        policyKind,
+		WorkspaceID: config.WorkspaceID,
+		ReporterData: reporterData{
+			ReporterType:       reporterType,
+			ReporterInstanceID: config.ReporterInstanceID,
+			LocalResourceID:    hubName + "/" + policy.GetNamespace() + "/" + policy.GetName(),
+		},
+		ResourceData: policyData{
+			Disabled: policy.Spec.Disabled,
+			Severity: policySeverity(policy),
+		},
+	}, nil
+}
+
+func clusterStatus(conditions []metav1.Condition) string {
+	available := meta.FindStatusCondition(conditions, clusterv1.ManagedClusterConditionAvailable)
+	switch {
+	case available == nil || available.Status == metav1.ConditionUnknown:
+		return "OFFLINE"
+	case available.Status == metav1.ConditionTrue:
+		return "READY"
+	default:
+		return "FAILED"
+	}
+}
+
+func kubeVendor(vendor string) string {
+	switch strings.ToLower(vendor) {
+	case "openshift":
+		return "OPENSHIFT"
+	case "eks":
+		return "EKS"
+	case "aks":
+		return "AKS"
+	case "gke":
+		return "GKE"
+	case "iks":
+		return "IKS"
+	default:
+		return "KUBE_VENDOR_OTHER"
+	}
+}
+
+func cloudPlatform(cloud string) string {
+	switch strings.ToLower(cloud) {
+	case "amazon":
+		return "AWS_UPI"
+	case "azure":
+		return "AZURE_UPI"
+	case "google":
+		return "GCP_UPI"
+	case "ibm":
+		return "IBMCLOUD_UPI"
+	case "baremetal":
+		return "BAREMETAL_UPI"
+	default:
+		return "CLOUD_PLATFORM_OTHER"
+	}
+}
+
+var severityRanks = map[string]int{"low": 1, "medium": 2, "high": 3, "critical": 4}
+
+// policySeverity returns the highest severity of the policy templates
+func policySeverity(policy *policyv1.Policy) string {
+	severity := ""
+	for _, template := range policy.Spec.PolicyTemplates {
+		if template == nil {
+			continue
+		}
+		object := struct {
+			Spec struct {
+				Severity string `json:"severity"`
+			} `json:"spec"`
+		}{}
+		if err := json.Unmarshal(template.ObjectDefinition.Raw, &object); err != nil {
+			continue
+		}
+		current := strings.ToLower(object.Spec.Severity)
+		if severityRanks[current] > severityRanks[severity] {
+			severity = current
+		}
+	}
+	if severity == "" {
+		return "SEVERITY_OTHER"
+	}
+	return strings.ToUpper(severity)
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// ErrInvalidMigration is returned if the cluster can't be migrated from the source hub to the target hub
+var ErrInvalidMigration = errors.New("invalid migration")
+
+const (
+	// PhaseTimeout is the default duration for each phase of the migration to complete, otherwise it's failed
+	PhaseTimeout = 10 * time.Minute
+	// the current phase of the migration is requested repeatedly within the interval until it's completed
+	requestInterval = 10 * time.Second
+)
+
+// migrationController moves the managed clusters between the managed hubs. it requests the agent to run the current
+// phase of each migration, and the migration moves to the next phase once the agent reports the phase is completed:
+// Pending -> Registering(target hub) -> Deploying(source hub) -> Joining(target hub) -> Cleaning(source hub)
+type migrationController struct {
+	log          logr.Logger
+	producer     transport.Producer
+	phaseTimeout time.Duration
+}
+
+// AddMigrationController adds the migration controller into the manager, the default PhaseTimeout is used if the
+// phaseTimeout isn't positive
+func AddMigrationController(mgr ctrl.Manager, producer transport.Producer, phaseTimeout time.Duration) error {
+	if phaseTimeout <= 0 {
+		phaseTimeout = PhaseTimeout
+	}
+	return mgr.Add(&migrationController{
+		log:          ctrl.Log.WithName("managed-cluster-migration"),
+		producer:     producer,
+		phaseTimeout: phaseTimeout,
+	})
+}
+
+func (c *migrationController) Start(ctx context.Context) error {
+	ticker := time.NewTicker(requestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := c.reconcile(ctx); err != nil {
+				c.log.Error(err, "failed to reconcile the managed cluster migrations")
+			}
+		}
+	}
+}
+
+func (c *migrationController) reconcile(ctx context.Context) error {
+	var migrations []models.ManagedClusterMigration
+	err := database.GetGorm().WithContext(ctx).
+		Where("phase NOT IN ?", []string{spec.MigrationCompleted, spec.MigrationFailed}).
+		Order("id").Find(&migrations).Error
+	if err != nil {
+		return err
+	}
+	for i := range migrations {
+		if err := c.reconcileMigration(ctx, &migrations[i]); err != nil {
+			c.log.Error(err, "failed to reconcile the migration", "id", migrations[i].ID,
+				"cluster", migrations[i].ClusterName, "phase", migrations[i].Phase)
+		}
+	}
+	return nil
+}
+
+func (c *migrationController) reconcileMigration(ctx context.Context, m *models.ManagedClusterMigration) error {
+	db := database.GetGorm().WithContext(ctx)
+	if m.Phase == spec.MigrationPending {
+		// the hubs may be changed since the migration is created
+		if err := Validate(db, m.ClusterName, m.FromHub, m.ToHub); errors.Is(err, ErrInvalidMigration) {
+			return setPhase(db, m, spec.MigrationFailed, err.Error())
+		} else if err != nil {
+			return err
+		}
+		return setPhase(db, m, spec.MigrationRegistering, "")
+	}
+
+	if time.Since(m.PhaseStartedAt) > c.phaseTimeout {
+		message := fmt.Sprintf("the %s phase isn't completed within %s", m.Phase, c.phaseTimeout)
+		if m.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, m.Message)
+		}
+		c.log.Info("the migration is failed", "id", m.ID, "cluster", m.ClusterName, "message", message)
+		return setPhase(db, m, spec.MigrationFailed, message)
+	}
+
+	switch m.Phase {
+	case spec.MigrationRegistering, spec.MigrationDeploying:
+		// the bootstrap kubeconfig isn't persisted, so it's requested from the target hub again to deploy it
+		return request(ctx, c.producer, m, spec.MigrationRegistering, nil)
+	case spec.MigrationJoining:
+		return request(ctx, c.producer, m, spec.MigrationJoining, nil)
+	case spec.MigrationCleaning:
+		return request(ctx, c.producer, m, spec.MigrationCleaning, nil)
+	}
+	return nil
+}
+
+// UpdateMigration moves the migration to the next phase once the current phase is completed on the hub, otherwise it
+// records the error of the phase. the status of the other phases, e.g. the completed ones, is ignored.
+func UpdateMigration(ctx context.Context, producer transport.Producer, hubName string,
+	status spec.ManagedClusterMigrationStatus,
+) error {
+	db := database.GetGorm().WithContext(ctx)
+	m := &models.ManagedClusterMigration{}
+	err := db.First(m, status.MigrationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if m.ClusterName != status.ClusterName || phaseHub(m, status.Phase) != hubName {
+		return nil
+	}
+
+	if !status.Completed {
+		if status.Phase != m.Phase || status.Message == m.Message {
+			return nil
+		}
+		return db.Model(m).Where("phase = ?", m.Phase).Update("message", status.Message).Error
+	}
+
+	switch {
+	case status.Phase == spec.MigrationRegistering &&
+		(m.Phase == spec.MigrationRegistering || m.Phase == spec.MigrationDeploying):
+		if m.Phase == spec.MigrationRegistering {
+			if err := setPhase(db, m, spec.MigrationDeploying, ""); err != nil {
+				return err
+			}
+		}
+		// the bootstrap kubeconfig is passed to the source hub without persisting it
+		return request(ctx, producer, m, spec.MigrationDeploying, status.BootstrapKubeconfig)
+	case status.Phase == spec.MigrationDeploying && m.Phase == spec.MigrationDeploying:
+		return setPhase(db, m, spec.MigrationJoining, "")
+	case status.Phase == spec.MigrationJoining && m.Phase == spec.MigrationJoining:
+		return setPhase(db, m, spec.MigrationCleaning, "")
+	case status.Phase == spec.MigrationCleaning && m.Phase == spec.MigrationCleaning:
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := moveClusterData(tx, m); err != nil {
+				return fmt.Errorf("failed to move the data of the cluster %s to the hub %s: %w",
+					m.ClusterName, m.ToHub, err)
+			}
+			return setPhase(tx, m, spec.MigrationCompleted, "")
+		})
+	}
+	return nil
+}
+
+// Validate returns the error if the cluster can't be migrated from the source hub to the target hub
+func Validate(db *gorm.DB, clusterName, fromHub, toHub string) error {
+	if fromHub == toHub {
+		return fmt.Errorf("%w: the cluster %s is already on the hub %s", ErrInvalidMigration, clusterName, toHub)
+	}
+	var count int64
+	err := db.Model(&models.ManagedCluster{}).
+		Where("leaf_hub_name = ? AND payload->'metadata'->>'name' = ?", fromHub, clusterName).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: the cluster %s isn't found on the hub %s", ErrInvalidMigration, clusterName, fromHub)
+	}
+	err = db.Model(&models.ManagedCluster{}).
+		Where("leaf_hub_name = ? AND payload->'metadata'->>'name' = ?", toHub, clusterName).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: the cluster %s already exists on the hub %s", ErrInvalidMigration, clusterName, toHub)
+	}
+
+	for _, hubName := range []string{fromHub, toHub} {
+		heartbeat := &models.LeafHubHeartbeat{}
+		err := db.Where(&models.LeafHubHeartbeat{Name: hubName}).First(heartbeat).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: the hub %s isn't found", ErrInvalidMigration, hubName)
+		} else if err != nil {
+			return err
+		}
+		if heartbeat.Status != hubmanagement.HubActive {
+			return fmt.Errorf("%w: the hub %s is %s", ErrInvalidMigration, hubName, heartbeat.Status)
+		}
+	}
+	return nil
+}
+
+// moveClusterData moves the cluster to the target hub in the database, and its compliance history and policy events
+// as well, so they're kept once the source hub is detached
+func moveClusterData(tx *gorm.DB, m *models.ManagedClusterMigration) error {
+	var clusterIDs []string
+	err := tx.Unscoped().Model(&models.ManagedCluster{}).
+		Where("leaf_hub_name IN ? AND payload->'metadata'->>'name' = ?", []string{m.FromHub, m.ToHub}, m.ClusterName).
+		Pluck("cluster_id", &clusterIDs).Error
+	if err != nil || len(clusterIDs) == 0 {
+		return err
+	}
+	err = tx.Unscoped().Model(&models.ManagedCluster{}).Where("cluster_id IN ?", clusterIDs).
+		Updates(map[string]interface{}{"leaf_hub_name": m.ToHub, "deleted_at": nil}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Table("history.local_compliance").
+		Where("leaf_hub_name = ? AND cluster_id IN ?", m.FromHub, clusterIDs).
+		Update("leaf_hub_name", m.ToHub).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.LocalClusterPolicyEvent{}).
+		Where("leaf_hub_name = ? AND cluster_id IN ?", m.FromHub, clusterIDs).
+		Update("leaf_hub_name", m.ToHub).Error
+}
+
+// setPhase moves the migration to the phase if it isn't moved by the others
+func setPhase(db *gorm.DB, m *models.ManagedClusterMigration, phase, message string) error {
+	now := time.Now()
+	result := db.Model(&models.ManagedClusterMigration{}).Where("id = ? AND phase = ?", m.ID, m.Phase).
+		Updates(map[string]interface{}{
+			"phase":            phase,
+			"message":          message,
+			"phase_started_at": now,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		m.Phase, m.Message, m.PhaseStartedAt = phase, message, now
+	}
+	return nil
+}
+
+// phaseHub returns the hub running the phase of the migration
+func phaseHub(m *models.ManagedClusterMigration, phase string) string {
+	switch phase {
+	case spec.MigrationRegistering, spec.MigrationJoining:
+		return m.ToHub
+	case spec.MigrationDeploying, spec.MigrationCleaning:
+		return m.FromHub
+	}
+	return ""
+}
+
+func request(ctx context.Context, producer transport.Producer, m *models.ManagedClusterMigration, phase string,
+	bootstrapKubeconfig []byte,
+) error {
+	migrationSpec := &spec.ManagedClusterMigrationSpec{
+		MigrationID:         m.ID,
+		Phase:               phase,
+		ClusterName:         m.ClusterName,
+		FromHub:             m.FromHub,
+		ToHub:               m.ToHub,
+		BootstrapKubeconfig: bootstrapKubeconfig,
+	}
+	evt := utils.ToCloudEvent(constants.ManagedClusterMigrationMsgKey, phaseHub(m, phase), migrationSpec)
+	if err := producer.SendEvent(ctx, evt); err != nil {
+		return fmt.Errorf("failed to request the %s phase of the migration %d: %w", phase, m.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/test/pkg/testpostgres"
+)
+
+func TestMigration(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	testPostgres, err := testpostgres.NewTestPostgres()
+	assert.Nil(t, err)
+	err = testpostgres.InitDatabase(testPostgres.URI)
+	assert.Nil(t, err)
+
+	db := database.GetGorm()
+	err = db.Create(&[]models.LeafHubHeartbeat{
+		{Name: "hub1", LastUpdateAt: time.Now(), Status: hubmanagement.HubActive},
+		{Name: "hub2", LastUpdateAt: time.Now(), Status: hubmanagement.HubActive},
+		{Name: "hub3", LastUpdateAt: time.Now(), Status: hubmanagement.HubInactive},
+	}).Error
+	assert.Nil(t, err)
+	clusterID := uuid.New().String()
+	err = db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+		('hub1', ?, '{"metadata": {"name": "cluster1"}}', 'none')`, clusterID).Error
+	assert.Nil(t, err)
+
+	// validate
+	assert.Nil(t, Validate(db, "cluster1", "hub1", "hub2"))
+	assert.True(t, errors.Is(Validate(db, "cluster1", "hub1", "hub1"), ErrInvalidMigration))
+	assert.True(t, errors.Is(Validate(db, "cluster2", "hub1", "hub2"), ErrInvalidMigration))
+	assert.True(t, errors.Is(Validate(db, "cluster1", "hub1", "hub3"), ErrInvalidMigration))
+
+	producer := &tmpProducer{}
+	controller := &migrationController{
+		log:          ctrl.Log.WithName("managed-cluster-migration"),
+		producer:     producer,
+		phaseTimeout: PhaseTimeout,
+	}
+	m := &models.ManagedClusterMigration{
+		ClusterName:    "cluster1",
+		FromHub:        "hub1",
+		ToHub:          "hub2",
+		Phase:          spec.MigrationPending,
+		PhaseStartedAt: time.Now(),
+	}
+	assert.Nil(t, db.Create(m).Error)
+
+	// Pending -> Registering: the target hub is requested to register the cluster
+	assert.Nil(t, controller.reconcileMigration(ctx, m))
+	assert.Equal(t, spec.MigrationRegistering, m.Phase)
+	assert.Nil(t, controller.reconcileMigration(ctx, m))
+	assert.Equal(t, "hub2", producer.last(t).ToHub)
+	assert.Equal(t, spec.MigrationRegistering, producer.last(t).Phase)
+
+	// the status from the unexpected hub is ignored
+	status := spec.ManagedClusterMigrationStatus{
+		MigrationID:         m.ID,
+		Phase:               spec.MigrationRegistering,
+		ClusterName:         "cluster1",
+		Completed:           true,
+		BootstrapKubeconfig: []byte("kubeconfig"),
+	}
+	assert.Nil(t, UpdateMigration(ctx, producer, "hub1", status))
+	assertPhase(t, m.ID, spec.MigrationRegistering)
+
+	// Registering -> Deploying: the bootstrap kubeconfig is passed to the source hub
+	assert.Nil(t, UpdateMigration(ctx, producer, "hub2", status))
+	assertPhase(t, m.ID, spec.MigrationDeploying)
+	assert.Equal(t, spec.MigrationDeploying, producer.last(t).Phase)
+	assert.Equal(t, []byte("kubeconfig"), producer.last(t).BootstrapKubeconfig)
+
+	// the error of the phase is recorded
+	status = spec.ManagedClusterMigrationStatus{
+		MigrationID: m.ID,
+		Phase:       spec.MigrationDeploying,
+		ClusterName: "cluster1",
+		Message:     "manifestwork isn't applied",
+	}
+	assert.Nil(t, UpdateMigration(ctx, producer, "hub1", status))
+	updated := assertPhase(t, m.ID, spec.MigrationDeploying)
+	assert.Equal(t, "manifestwork isn't applied", updated.Message)
+
+	// Deploying -> Joining -> Cleaning -> Completed
+	for _, phase := range []struct{ name, hub, next string }{
+		{spec.MigrationDeploying, "hub1", spec.MigrationJoining},
+		{spec.MigrationJoining, "hub2", spec.MigrationCleaning},
+		{spec.MigrationCleaning, "hub1", spec.MigrationCompleted},
+	} {
+		status := spec.ManagedClusterMigrationStatus{
+			MigrationID: m.ID,
+			Phase:       phase.name,
+			ClusterName: "cluster1",
+			Completed:   true,
+		}
+		assert.Nil(t, UpdateMigration(ctx, producer, phase.hub, status))
+		updated := assertPhase(t, m.ID, phase.next)
+		assert.Empty(t, updated.Message)
+	}
+
+	// the cluster is moved to the target hub
+	cluster := &models.ManagedCluster{}
+	assert.Nil(t, db.Where("cluster_id = ?", clusterID).First(cluster).Error)
+	assert.Equal(t, "hub2", cluster.LeafHubName)
+
+	// the phase is failed once it isn't completed within the timeout
+	m = &models.ManagedClusterMigration{
+		ClusterName:    "cluster1",
+		FromHub:        "hub2",
+		ToHub:          "hub1",
+		Phase:          spec.MigrationJoining,
+		PhaseStartedAt: time.Now().Add(-2 * PhaseTimeout),
+	}
+	assert.Nil(t, db.Create(m).Error)
+	assert.Nil(t, controller.reconcileMigration(ctx, m))
+	assertPhase(t, m.ID, spec.MigrationFailed)
+
+	err = testPostgres.Stop()
+	assert.Nil(t, err)
+}
+
+func assertPhase(t *testing.T, id int64, phase string) *models.ManagedClusterMigration {
+	m := &models.ManagedClusterMigration{}
+	assert.Nil(t, database.GetGorm().First(m, id).Error)
+	assert.Equal(t, phase, m.Phase)
+	return m
+}
+
+type tmpProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *tmpProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *tmpProducer) last(t *testing.T) *spec.ManagedClusterMigrationSpec {
+	assert.NotEmpty(t, p.events)
+	migration := &spec.ManagedClusterMigrationSpec{}
+	assert.Nil(t, json.Unmarshal(p.events[len(p.events)-1].Data(), migration))
+	return migration
+}
@@ -3,6 +3,8 @@ package monitoring
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
 
 var GlobalHubCronJobGaugeVec = prometheus.NewGaugeVec(
@@ -15,7 +17,235 @@ var GlobalHubCronJobGaugeVec = prometheus.NewGaugeVec(
 	},
 )
 
+var GlobalHubStatusLastProcessedGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_status_last_processed_timestamp_seconds",
+		Help: "The unix timestamp of the last status bundle from the managed hub persisted to the database.",
+	},
+	[]string{
+		"hub", // The name of the managed hub.
+	},
+)
+
+var GlobalHubStatusHandlerErrorsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_status_handler_errors_total",
+		Help: "The number of failures to handle the status bundles, including the decoding and database errors.",
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var GlobalHubStatusLastReceivedGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_status_last_received_timestamp_seconds",
+		Help: "The unix timestamp of the last status bundle received from the managed hub.",
+	},
+	[]string{
+		"hub", // The name of the managed hub.
+	},
+)
+
+var GlobalHubBundleProcessingDurationHistogramVec = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_bundle_processing_duration_seconds",
+		Help:    "The duration from the status bundle received by the manager to it's persisted to the database.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var GlobalHubBundlesPersistedCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_bundles_persisted_total",
+		Help: "The number of status bundles persisted to the database.",
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var GlobalHubDatabaseWriteDurationHistogramVec = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_database_write_duration_seconds",
+		Help:    "The duration of a single attempt to write the status bundle to the database.",
+		Buckets: prometheus.DefBuckets,
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var GlobalHubConflationQueueDepthGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_conflation_ready_queue_depth",
+		Help: "The number of items waiting in the conflation ready queue to be dispatched to the database workers.",
+	},
+	[]string{
+		"queue", // The queue name, "conflation_unit" for the complete bundles and "delta_event" for the delta bundles.
+	},
+)
+
+var GlobalHubConflatedBundlesCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_conflated_bundles_total",
+		Help: "The number of intermediate status bundles conflated away by the newer ones before being persisted.",
+	},
+	[]string{
+		"type", // The event type of the status bundle.
+	},
+)
+
+var GlobalHubDatabaseAvailableGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_database_available",
+		Help: "Whether the database is available for the status pipeline. 1 == available, 0 == unavailable.",
+	},
+)
+
+var GlobalHubDataInconsistencyGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_data_inconsistency",
+		Help: "The number of the resources reported by the managed hub minus the number of them in the database.",
+	},
+	[]string{
+		"hub",  // The name of the managed hub.
+		"type", // The resource type, e.g. managedclusters and localpolicies.
+	},
+)
+
+var GlobalHubSignatureFailuresCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_signature_verification_failures_total",
+		Help: "The number of the status events rejected since they fail the signature verification.",
+	},
+	[]string{
+		"hub", // The source of the event, it might not be a managed hub if the event is forged.
+	},
+)
+
+var GlobalHubQuarantinedEventsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_quarantined_events_total",
+		Help: "The number of the status events which aren't persisted since the agent is incompatible.",
+	},
+	[]string{
+		"hub", // The name of the managed hub.
+	},
+)
+
+var GlobalHubThrottledEventsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_throttled_events_total",
+		Help: "The number of the status events which aren't persisted since they exceed the rate limit of the hub.",
+	},
+	[]string{
+		"hub", // The name of the managed hub.
+	},
+)
+
+var GlobalHubIngestionThrottledGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_ingestion_throttled",
+		Help: "Whether the status events of the managed hub are throttled. 1 == throttled, 0 == not throttled.",
+	},
+	[]string{
+		"hub", // The name of the managed hub.
+	},
+)
+
+var GlobalHubDroppedEventsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_out_of_order_events_total",
+		Help: "The number of the status events which are dropped since they're stale or duplicate.",
+	},
+	[]string{
+		"hub",    // The name of the managed hub.
+		"reason", // The reason of dropping the event, "stale" or "duplicate".
+	},
+)
+
+var GlobalHubEventSchemaVersionsCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_status_event_schema_versions_total",
+		Help: "The number of the status events received by the version of their schemas.",
+	},
+	[]string{
+		"type",    // The event type without the common prefix, e.g. managedcluster.
+		"version", // The version of the schema claimed by the event, e.g. v1.
+	},
+)
+
+var GlobalHubSchemaValidationFailuresCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_schema_validation_failures_total",
+		Help: "The number of the status events rejected since they don't match the schemas of their versions.",
+	},
+	[]string{
+		"hub",     // The name of the managed hub.
+		"type",    // The event type without the common prefix, e.g. managedcluster.
+		"version", // The version of the schema claimed by the event, e.g. v1.
+	},
+)
+
+var GlobalHubSpecDriftsGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_spec_drifts",
+		Help: "The number of the global resources on the managed hub which drift from the spec of the global hub.",
+	},
+	[]string{
+		"hub",  // The name of the managed hub.
+		"type", // The drift type, e.g. missing, failed, conflicted and unexpected.
+	},
+)
+
+var GlobalHubConsumedBytesCounterVec = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_consumed_bytes_total",
+		Help: "The bytes of the status bundles consumed from the managed hub, before they're admitted to the database.",
+	},
+	[]string{
+		"hub",  // The name of the managed hub.
+		"type", // The event type without the common prefix, e.g. managedcluster.
+	},
+)
+
+var GlobalHubStorageBytesGaugeVec = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_storage_bytes",
+		Help: "The estimated bytes of the records of the managed hub in the database table.",
+	},
+	[]string{
+		"hub",   // The name of the managed hub.
+		"table", // The table of the records, e.g. status.managed_clusters.
+	},
+)
+
 // RegisterMetrics will register metrics with the global prometheus registry
 func RegisterMetrics() {
 	metrics.Registry.MustRegister(GlobalHubCronJobGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubStatusLastProcessedGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubStatusHandlerErrorsCounterVec)
+	metrics.Registry.MustRegister(GlobalHubStatusLastReceivedGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubBundleProcessingDurationHistogramVec)
+	metrics.Registry.MustRegister(GlobalHubBundlesPersistedCounterVec)
+	metrics.Registry.MustRegister(GlobalHubDatabaseWriteDurationHistogramVec)
+	metrics.Registry.MustRegister(GlobalHubConflationQueueDepthGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubConflatedBundlesCounterVec)
+	metrics.Registry.MustRegister(GlobalHubDatabaseAvailableGauge)
+	metrics.Registry.MustRegister(GlobalHubDataInconsistencyGaugeVec)
+	metrics.Registry.MustRegister(database.QueryDurationHistogramVec)
+	metrics.Registry.MustRegister(GlobalHubSignatureFailuresCounterVec)
+	metrics.Registry.MustRegister(GlobalHubQuarantinedEventsCounterVec)
+	metrics.Registry.MustRegister(GlobalHubThrottledEventsCounterVec)
+	metrics.Registry.MustRegister(GlobalHubIngestionThrottledGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubDroppedEventsCounterVec)
+	metrics.Registry.MustRegister(GlobalHubEventSchemaVersionsCounterVec)
+	metrics.Registry.MustRegister(GlobalHubSchemaValidationFailuresCounterVec)
+	metrics.Registry.MustRegister(GlobalHubSpecDriftsGaugeVec)
+	metrics.Registry.MustRegister(GlobalHubConsumedBytesCounterVec)
+	metrics.Registry.MustRegister(GlobalHubStorageBytesGaugeVec)
 }
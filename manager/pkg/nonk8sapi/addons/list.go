@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package addons
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// addonSummary is the number of the managed clusters in each health status of the addon across all the managed hubs
+type addonSummary struct {
+	Name        string `json:"name"`
+	Total       int    `json:"total"`
+	Available   int    `json:"available"`
+	Degraded    int    `json:"degraded"`
+	Progressing int    `json:"progressing"`
+	Unknown     int    `json:"unknown"`
+}
+
+// clusterAddon is the health of the addon on the managed cluster
+type clusterAddon struct {
+	LeafHubName        string     `json:"leafHubName"`
+	ClusterName        string     `json:"clusterName"`
+	Name               string     `json:"name"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ListAddons godoc
+// @summary list addons
+// @description list the addons with the number of the managed clusters in each health status, the addons degraded on
+// @description more clusters are listed first
+// @accept json
+// @produce json
+// @param        hub    query    string    false    "filter the clusters of the addons by the managed hub"
+// @success      200  {array}   addonSummary
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /addons [get]
+func ListAddons() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.ManagedClusterAddon{}).
+			Where(&models.ManagedClusterAddon{LeafHubName: ginCtx.Query("hub")})
+		var rows []struct {
+			AddonName string
+			Status    string
+			Count     int
+		}
+		err := query.Select("addon_name, status, COUNT(*) AS count").Group("addon_name, status").Find(&rows).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to summarize the managed cluster addons: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		summaries := []*addonSummary{}
+		summaryByName := map[string]*addonSummary{}
+		for _, row := range rows {
+			summary, found := summaryByName[row.AddonName]
+			if !found {
+				summary = &addonSummary{Name: row.AddonName}
+				summaryByName[row.AddonName] = summary
+				summaries = append(summaries, summary)
+			}
+			summary.Total += row.Count
+			switch row.Status {
+			case cluster.AddonStatusAvailable:
+				summary.Available += row.Count
+			case cluster.AddonStatusDegraded:
+				summary.Degraded += row.Count
+			case cluster.AddonStatusProgressing:
+				summary.Progressing += row.Count
+			default:
+				summary.Unknown += row.Count
+			}
+		}
+		sortSummaries(summaries)
+		ginCtx.JSON(http.StatusOK, summaries)
+	}
+}
+
+// ListClusterAddons godoc
+// @summary list the addons on the managed clusters
+// @description list the health of the addons on the managed clusters, e.g. the clusters with the degraded addon
+// @accept json
+// @produce json
+// @param        hub        query    string    false    "filter the addons by the managed hub"
+// @param        cluster    query    string    false    "filter the addons by the managed cluster"
+// @param        addon      query    string    false    "filter the addons by the name, e.g. observability-controller"
+// @param        status     query    string    false    "filter the addons by the status: available, degraded, progressing or unknown"
+// @success      200  {array}   clusterAddon
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /addons/clusters [get]
+func ListClusterAddons() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.ManagedClusterAddon{}).Where(&models.ManagedClusterAddon{
+			LeafHubName: ginCtx.Query("hub"),
+			ClusterName: ginCtx.Query("cluster"),
+			AddonName:   ginCtx.Query("addon"),
+			Status:      ginCtx.Query("status"),
+		})
+		var rows []models.ManagedClusterAddon
+		if err := query.Order("leaf_hub_name, cluster_name, addon_name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the managed cluster addons: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		addons := make([]clusterAddon, 0, len(rows))
+		for _, row := range rows {
+			addons = append(addons, clusterAddon{
+				LeafHubName:        row.LeafHubName,
+				ClusterName:        row.ClusterName,
+				Name:               row.AddonName,
+				Status:             row.Status,
+				Reason:             row.Reason,
+				Message:            row.Message,
+				LastTransitionTime: row.LastTransitionTime,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, addons)
+	}
+}
+
+func sortSummaries(summaries []*addonSummary) {
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Degraded != summaries[j].Degraded {
+			return summaries[i].Degraded > summaries[j].Degraded
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+}
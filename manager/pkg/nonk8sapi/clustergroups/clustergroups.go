@@ -0,0 +1,350 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clustergroups
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/clustergroup"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+type clusterGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// LabelSelector selects the managed clusters of all the hubs by the labels, e.g. env=prod,region in (us,eu)
+	LabelSelector string `json:"labelSelector"`
+	// Clusters are the explicit members, which are either <hub>/<cluster> or <cluster> of any hub
+	Clusters []string `json:"clusters"`
+}
+
+// clusterGroup is the named group of the managed clusters spanning the managed hubs
+type clusterGroup struct {
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	LabelSelector string    `json:"labelSelector,omitempty"`
+	Clusters      []string  `json:"clusters"`
+	Members       int       `json:"members"`
+	Hubs          []string  `json:"hubs"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// clusterGroupMember is the managed cluster selected by the group
+type clusterGroupMember struct {
+	Hub         string `json:"hub"`
+	ClusterID   string `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+}
+
+// clusterGroupDetail is the group with its members
+type clusterGroupDetail struct {
+	clusterGroup
+	MemberClusters []clusterGroupMember `json:"memberClusters"`
+}
+
+// ListClusterGroups godoc
+// @summary list cluster groups
+// @description list the cluster groups with the number of their members and the hubs of them
+// @accept json
+// @produce json
+// @success      200  {array}   clusterGroup
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /clustergroups [get]
+func ListClusterGroups() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		var rows []models.ClusterGroup
+		if err := db.Order("name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the cluster groups: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		var members []models.ClusterGroupMember
+		if err := db.Order("group_name, leaf_hub_name, cluster_name").Find(&members).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the cluster group members: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		membersOfGroup := map[string][]models.ClusterGroupMember{}
+		for _, member := range members {
+			membersOfGroup[member.GroupName] = append(membersOfGroup[member.GroupName], member)
+		}
+
+		groups := make([]clusterGroup, 0, len(rows))
+		for i := range rows {
+			group, err := toClusterGroup(&rows[i], membersOfGroup[rows[i].Name])
+			if err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "failed to convert the cluster group: %v\n", err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+			groups = append(groups, group)
+		}
+		ginCtx.JSON(http.StatusOK, groups)
+	}
+}
+
+// GetClusterGroup godoc
+// @summary get cluster group
+// @description get the cluster group with its member clusters
+// @accept json
+// @produce json
+// @param        name    path    string    true    "the name of the cluster group"
+// @success      200  {object}  clusterGroupDetail
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /clustergroup/{name} [get]
+func GetClusterGroup() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		row, ok := getClusterGroupRow(ginCtx)
+		if !ok {
+			return
+		}
+		respondClusterGroupDetail(ginCtx, http.StatusOK, row)
+	}
+}
+
+// CreateClusterGroup godoc
+// @summary create cluster group
+// @description create the cluster group, the members are selected by the label selector and the explicit clusters
+// @accept json
+// @produce json
+// @param        group    body    clusterGroupRequest    true    "The cluster group"
+// @success      201  {object}  clusterGroupDetail
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /clustergroups [post]
+func CreateClusterGroup() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		request := &clusterGroupRequest{}
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid cluster group request: %s", err.Error()))
+			return
+		}
+		row, ok := toModel(ginCtx, request)
+		if !ok {
+			return
+		}
+
+		db := database.GetGorm()
+		var existing int64
+		if err := db.Model(&models.ClusterGroup{}).Where("name = ?", row.Name).Count(&existing).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the cluster group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if existing > 0 {
+			ginCtx.String(http.StatusConflict, fmt.Sprintf("cluster group %s already exists", row.Name))
+			return
+		}
+		if err := db.Create(row).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to create the cluster group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if err := clustergroup.Refresh(ginCtx.Request.Context(), db, row.Name); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to refresh the cluster group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "create the cluster group %s\n", row.Name)
+		respondClusterGroupDetail(ginCtx, http.StatusCreated, row)
+	}
+}
+
+// UpdateClusterGroup godoc
+// @summary update cluster group
+// @description replace the description, label selector and explicit clusters of the cluster group
+// @accept json
+// @produce json
+// @param        name     path    string                 true    "the name of the cluster group"
+// @param        group    body    clusterGroupRequest    true    "The cluster group"
+// @success      200  {object}  clusterGroupDetail
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /clustergroup/{name} [put]
+func UpdateClusterGroup() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		existing, ok := getClusterGroupRow(ginCtx)
+		if !ok {
+			return
+		}
+		request := &clusterGroupRequest{}
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid cluster group request: %s", err.Error()))
+			return
+		}
+		if request.Name != "" && request.Name != existing.Name {
+			ginCtx.String(http.StatusBadRequest, "the name of the cluster group can't be changed")
+			return
+		}
+		request.Name = existing.Name
+		row, ok := toModel(ginCtx, request)
+		if !ok {
+			return
+		}
+		row.CreatedAt = existing.CreatedAt
+
+		db := database.GetGorm()
+		if err := db.Select("description", "label_selector", "clusters", "updated_at").Updates(row).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to update the cluster group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if err := clustergroup.Refresh(ginCtx.Request.Context(), db, row.Name); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to refresh the cluster group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "update the cluster group %s\n", row.Name)
+		respondClusterGroupDetail(ginCtx, http.StatusOK, row)
+	}
+}
+
+// DeleteClusterGroup godoc
+// @summary delete cluster group
+// @description delete the cluster group and its members
+// @accept json
+// @produce json
+// @param        name    path    string    true    "the name of the cluster group"
+// @success      204
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /clustergroup/{name} [delete]
+func DeleteClusterGroup() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		name := ginCtx.Param("name")
+		result := database.GetGorm().Where("name = ?", name).Delete(&models.ClusterGroup{})
+		if result.Error != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to delete the cluster group: %v\n", result.Error)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if result.RowsAffected == 0 {
+			ginCtx.String(http.StatusNotFound, fmt.Sprintf("cluster group %s is not found", name))
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "delete the cluster group %s\n", name)
+		ginCtx.Status(http.StatusNoContent)
+	}
+}
+
+// toModel validates the request and converts it to the database row, the bad request is responded if it's invalid
+func toModel(ginCtx *gin.Context, request *clusterGroupRequest) (*models.ClusterGroup, bool) {
+	group := &clustergroup.Group{
+		Name:          request.Name,
+		Description:   request.Description,
+		LabelSelector: request.LabelSelector,
+		Clusters:      request.Clusters,
+	}
+	if err := group.Validate(); err != nil {
+		ginCtx.String(http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	row, err := group.ToModel()
+	if err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "failed to convert the cluster group: %v\n", err)
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		return nil, false
+	}
+	return row, true
+}
+
+// getClusterGroupRow returns the group of the name in the path, the not found is responded if it doesn't exist
+func getClusterGroupRow(ginCtx *gin.Context) (*models.ClusterGroup, bool) {
+	name := ginCtx.Param("name")
+	row := &models.ClusterGroup{}
+	err := database.GetGorm().Where("name = ?", name).First(row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ginCtx.String(http.StatusNotFound, fmt.Sprintf("cluster group %s is not found", name))
+		return nil, false
+	} else if err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "failed to get the cluster group: %v\n", err)
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		return nil, false
+	}
+	return row, true
+}
+
+func respondClusterGroupDetail(ginCtx *gin.Context, code int, row *models.ClusterGroup) {
+	var members []models.ClusterGroupMember
+	err := database.GetGorm().Where("group_name = ?", row.Name).Order("leaf_hub_name, cluster_name").
+		Find(&members).Error
+	if err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "failed to list the cluster group members: %v\n", err)
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		return
+	}
+	group, err := toClusterGroup(row, members)
+	if err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "failed to convert the cluster group: %v\n", err)
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		return
+	}
+	detail := clusterGroupDetail{clusterGroup: group, MemberClusters: make([]clusterGroupMember, 0, len(members))}
+	for _, member := range members {
+		detail.MemberClusters = append(detail.MemberClusters, clusterGroupMember{
+			Hub:         member.LeafHubName,
+			ClusterID:   member.ClusterID,
+			ClusterName: member.ClusterName,
+		})
+	}
+	ginCtx.JSON(code, detail)
+}
+
+// toClusterGroup converts the row with the members ordered by the hub
+func toClusterGroup(row *models.ClusterGroup, members []models.ClusterGroupMember) (clusterGroup, error) {
+	group, err := clustergroup.FromModel(row)
+	if err != nil {
+		return clusterGroup{}, err
+	}
+	hubs := []string{}
+	for _, member := range members {
+		if len(hubs) == 0 || hubs[len(hubs)-1] != member.LeafHubName {
+			hubs = append(hubs, member.LeafHubName)
+		}
+	}
+	return clusterGroup{
+		Name:          group.Name,
+		Description:   group.Description,
+		LabelSelector: group.LabelSelector,
+		Clusters:      group.Clusters,
+		Members:       len(members),
+		Hubs:          hubs,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}, nil
+}
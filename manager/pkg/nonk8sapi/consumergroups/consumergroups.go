@@ -0,0 +1,603 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumergroups
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	adminTimeout           = 30 * time.Second
+	metadataTimeoutMs      = 10000
+
+	ResetToEarliest  = "earliest"
+	ResetToLatest    = "latest"
+	ResetToTimestamp = "timestamp"
+	ResetToOffset    = "offset"
+)
+
+// GroupAdmin is the kafka admin operations on the consumer groups, it's implemented by the kafka.AdminClient
+type GroupAdmin interface {
+	ListConsumerGroups(ctx context.Context, options ...kafka.ListConsumerGroupsAdminOption) (
+		kafka.ListConsumerGroupsResult, error)
+	DescribeConsumerGroups(ctx context.Context, groups []string, options ...kafka.DescribeConsumerGroupsAdminOption) (
+		kafka.DescribeConsumerGroupsResult, error)
+	ListConsumerGroupOffsets(ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions,
+		options ...kafka.ListConsumerGroupOffsetsAdminOption) (kafka.ListConsumerGroupOffsetsResult, error)
+	AlterConsumerGroupOffsets(ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions,
+		options ...kafka.AlterConsumerGroupOffsetsAdminOption) (kafka.AlterConsumerGroupOffsetsResult, error)
+	ListOffsets(ctx context.Context, topicPartitionOffsets map[kafka.TopicPartition]kafka.OffsetSpec,
+		options ...kafka.ListOffsetsAdminOption) (kafka.ListOffsetsResult, error)
+	GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error)
+	Close()
+}
+
+// AdminFunc creates the admin client of the kafka cluster, it's closed once the request is handled
+type AdminFunc func() (GroupAdmin, error)
+
+// NewAdminFunc returns the AdminFunc creating the admin client by the configmap of the kafka cluster
+func NewAdminFunc(configMap *kafka.ConfigMap) AdminFunc {
+	return func() (GroupAdmin, error) {
+		return kafka.NewAdminClient(configMap)
+	}
+}
+
+// consumerGroup is the consumer group of the kafka cluster
+type consumerGroup struct {
+	GroupID string `json:"groupId"`
+	State   string `json:"state"`
+	Simple  bool   `json:"simple"`
+}
+
+type topicPartition struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+}
+
+// groupMember is the consumer in the group with the partitions assigned to it
+type groupMember struct {
+	ClientID        string           `json:"clientId"`
+	ConsumerID      string           `json:"consumerId"`
+	GroupInstanceID string           `json:"groupInstanceId,omitempty"`
+	Host            string           `json:"host"`
+	Assignment      []topicPartition `json:"assignment"`
+}
+
+// partitionLag is the committed offset of the partition and the number of the messages behind the end of it, the
+// committed offset is -1 if the group hasn't committed it, then the lag is all the messages retained in the partition
+type partitionLag struct {
+	Topic           string `json:"topic"`
+	Partition       int32  `json:"partition"`
+	CommittedOffset int64  `json:"committedOffset"`
+	EndOffset       int64  `json:"endOffset"`
+	Lag             int64  `json:"lag"`
+	ConsumerID      string `json:"consumerId,omitempty"`
+}
+
+// consumerGroupDetail is the consumer group with its members, partition assignments and lag
+type consumerGroupDetail struct {
+	consumerGroup
+	PartitionAssignor string         `json:"partitionAssignor"`
+	Coordinator       int            `json:"coordinator"`
+	Members           []groupMember  `json:"members"`
+	Partitions        []partitionLag `json:"partitions"`
+	TotalLag          int64          `json:"totalLag"`
+}
+
+// resetOffsetsRequest resets the committed offsets of the partitions of the topic, all the partitions of the topic
+// are reset if the partitions are empty
+type resetOffsetsRequest struct {
+	Topic      string  `json:"topic"`
+	Partitions []int32 `json:"partitions"`
+	// To is earliest, latest, timestamp or offset
+	To string `json:"to"`
+	// Timestamp resets to the first messages at or after it for the timestamp, or the end of the partitions
+	Timestamp *time.Time `json:"timestamp"`
+	// Offset resets to the offset, which must be within the range of the partitions
+	Offset *int64 `json:"offset"`
+	// DryRun returns the offsets to reset without committing them
+	DryRun bool `json:"dryRun"`
+}
+
+type resetOffsetsResult struct {
+	GroupID    string         `json:"groupId"`
+	DryRun     bool           `json:"dryRun"`
+	Partitions []resetOffsets `json:"partitions"`
+}
+
+type resetOffsets struct {
+	Topic          string `json:"topic"`
+	Partition      int32  `json:"partition"`
+	PreviousOffset int64  `json:"previousOffset"`
+	Offset         int64  `json:"offset"`
+}
+
+// ListConsumerGroups godoc
+// @summary list consumer groups
+// @description list the consumer groups of the kafka cluster with their states
+// @accept json
+// @produce json
+// @success      200  {array}   consumerGroup
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /consumergroups [get]
+func ListConsumerGroups(adminFunc AdminFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		admin, ok := newAdmin(ginCtx, adminFunc)
+		if !ok {
+			return
+		}
+		defer admin.Close()
+
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), adminTimeout)
+		defer cancel()
+		result, err := admin.ListConsumerGroups(ctx)
+		if err == nil && len(result.Errors) > 0 {
+			err = result.Errors[0]
+		}
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the consumer groups: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		groups := make([]consumerGroup, 0, len(result.Valid))
+		for _, listing := range result.Valid {
+			groups = append(groups, consumerGroup{
+				GroupID: listing.GroupID,
+				State:   listing.State.String(),
+				Simple:  listing.IsSimpleConsumerGroup,
+			})
+		}
+		sort.Slice(groups, func(i, j int) bool { return groups[i].GroupID < groups[j].GroupID })
+		ginCtx.JSON(http.StatusOK, groups)
+	}
+}
+
+// GetConsumerGroup godoc
+// @summary get consumer group
+// @description get the consumer group with its members, partition assignments and the lag of the partitions
+// @accept json
+// @produce json
+// @param        groupID    path    string    true    "the id of the consumer group"
+// @success      200  {object}  consumerGroupDetail
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /consumergroup/{groupID} [get]
+func GetConsumerGroup(adminFunc AdminFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		admin, ok := newAdmin(ginCtx, adminFunc)
+		if !ok {
+			return
+		}
+		defer admin.Close()
+
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), adminTimeout)
+		defer cancel()
+		description, ok := describeGroup(ctx, ginCtx, admin, ginCtx.Param("groupID"))
+		if !ok {
+			return
+		}
+		committed, err := committedOffsets(ctx, admin, description.GroupID)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the offsets of the consumer group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		detail, err := groupDetail(ctx, admin, description, committed)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the lag of the consumer group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, detail)
+	}
+}
+
+// ResetConsumerGroupOffsets godoc
+// @summary reset consumer group offsets
+// @description reset the committed offsets of the consumer group on the partitions of the topic, the group must not
+// @description have any active members
+// @accept json
+// @produce json
+// @param        groupID    path    string                 true    "the id of the consumer group"
+// @param        reset      body    resetOffsetsRequest    true    "The offsets to reset to"
+// @success      200  {object}  resetOffsetsResult
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /consumergroup/{groupID}/offsets [post]
+func ResetConsumerGroupOffsets(adminFunc AdminFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		request := &resetOffsetsRequest{}
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid reset offsets request: %s", err.Error()))
+			return
+		}
+		if err := validateResetRequest(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		admin, ok := newAdmin(ginCtx, adminFunc)
+		if !ok {
+			return
+		}
+		defer admin.Close()
+
+		ctx, cancel := context.WithTimeout(ginCtx.Request.Context(), adminTimeout)
+		defer cancel()
+		description, ok := describeGroup(ctx, ginCtx, admin, ginCtx.Param("groupID"))
+		if !ok {
+			return
+		}
+		// kafka rejects the offsets of the group with the active members, which overwrite them by their commits
+		if len(description.Members) > 0 {
+			ginCtx.String(http.StatusConflict, fmt.Sprintf(
+				"consumer group %s has %d active members, stop them before resetting the offsets",
+				description.GroupID, len(description.Members)))
+			return
+		}
+
+		partitions, err := topicPartitions(admin, request.Topic, request.Partitions)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		offsets, err := resolveOffsets(ctx, admin, request, partitions)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		committed, err := committedOffsets(ctx, admin, description.GroupID)
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the offsets of the consumer group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		result := resetOffsetsResult{GroupID: description.GroupID, DryRun: request.DryRun}
+		toCommit := make([]kafka.TopicPartition, 0, len(partitions))
+		for _, partition := range partitions {
+			previous, found := committed[partition]
+			if !found {
+				previous = -1
+			}
+			offset := offsets[partition]
+			result.Partitions = append(result.Partitions, resetOffsets{
+				Topic:          partition.Topic,
+				Partition:      partition.Partition,
+				PreviousOffset: previous,
+				Offset:         offset,
+			})
+			topic := partition.Topic
+			toCommit = append(toCommit, kafka.TopicPartition{
+				Topic: &topic, Partition: partition.Partition, Offset: kafka.Offset(offset),
+			})
+		}
+		if request.DryRun {
+			ginCtx.JSON(http.StatusOK, result)
+			return
+		}
+
+		altered, err := admin.AlterConsumerGroupOffsets(ctx, []kafka.ConsumerGroupTopicPartitions{
+			{Group: description.GroupID, Partitions: toCommit},
+		})
+		if err == nil {
+			for _, group := range altered.ConsumerGroupsTopicPartitions {
+				for _, partition := range group.Partitions {
+					if partition.Error != nil {
+						err = fmt.Errorf("%s[%d]: %w", *partition.Topic, partition.Partition, partition.Error)
+						break
+					}
+				}
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to reset the offsets of the consumer group: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "reset the offsets of the consumer group %s on the topic %s to %s\n",
+			description.GroupID, request.Topic, request.To)
+		ginCtx.JSON(http.StatusOK, result)
+	}
+}
+
+func newAdmin(ginCtx *gin.Context, adminFunc AdminFunc) (GroupAdmin, bool) {
+	admin, err := adminFunc()
+	if err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "failed to create the kafka admin client: %v\n", err)
+		ginCtx.String(http.StatusServiceUnavailable, "kafka is unavailable")
+		return nil, false
+	}
+	return admin, true
+}
+
+// describeGroup returns the description of the group, kafka describes the unknown group as a dead one without members
+func describeGroup(ctx context.Context, ginCtx *gin.Context, admin GroupAdmin, groupID string,
+) (*kafka.ConsumerGroupDescription, bool) {
+	result, err := admin.DescribeConsumerGroups(ctx, []string{groupID})
+	if err == nil && len(result.ConsumerGroupDescriptions) == 0 {
+		err = fmt.Errorf("no description of the consumer group %s", groupID)
+	}
+	if err == nil && result.ConsumerGroupDescriptions[0].Error.Code() != kafka.ErrNoError {
+		err = result.ConsumerGroupDescriptions[0].Error
+	}
+	if err != nil {
+		fmt.Fprintf(gin.DefaultWriter, "failed to describe the consumer group: %v\n", err)
+		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+		return nil, false
+	}
+	description := result.ConsumerGroupDescriptions[0]
+	if description.State == kafka.ConsumerGroupStateDead {
+		ginCtx.String(http.StatusNotFound, fmt.Sprintf("consumer group %s not found", groupID))
+		return nil, false
+	}
+	return &description, true
+}
+
+// committedOffsets returns the committed offsets of the group on all the partitions
+func committedOffsets(ctx context.Context, admin GroupAdmin, groupID string) (map[topicPartition]int64, error) {
+	result, err := admin.ListConsumerGroupOffsets(ctx, []kafka.ConsumerGroupTopicPartitions{{Group: groupID}})
+	if err != nil {
+		return nil, err
+	}
+	committed := map[topicPartition]int64{}
+	for _, group := range result.ConsumerGroupsTopicPartitions {
+		for _, partition := range group.Partitions {
+			if partition.Topic == nil || partition.Error != nil {
+				continue
+			}
+			committed[topicPartition{Topic: *partition.Topic, Partition: partition.Partition}] = int64(partition.Offset)
+		}
+	}
+	return committed, nil
+}
+
+// groupDetail returns the members of the group and the lag of the partitions, which are the partitions with the
+// committed offsets and the ones assigned to the members
+func groupDetail(ctx context.Context, admin GroupAdmin, description *kafka.ConsumerGroupDescription,
+	committed map[topicPartition]int64,
+) (*consumerGroupDetail, error) {
+	detail := &consumerGroupDetail{
+		consumerGroup: consumerGroup{
+			GroupID: description.GroupID,
+			State:   description.State.String(),
+			Simple:  description.IsSimpleConsumerGroup,
+		},
+		PartitionAssignor: description.PartitionAssignor,
+		Coordinator:       description.Coordinator.ID,
+		Members:           []groupMember{},
+		Partitions:        []partitionLag{},
+	}
+
+	consumerOf := map[topicPartition]string{}
+	for _, member := range description.Members {
+		assignment := []topicPartition{}
+		for _, partition := range member.Assignment.TopicPartitions {
+			if partition.Topic == nil {
+				continue
+			}
+			key := topicPartition{Topic: *partition.Topic, Partition: partition.Partition}
+			assignment = append(assignment, key)
+			consumerOf[key] = member.ConsumerID
+		}
+		sortPartitions(assignment)
+		detail.Members = append(detail.Members, groupMember{
+			ClientID:        member.ClientID,
+			ConsumerID:      member.ConsumerID,
+			GroupInstanceID: member.GroupInstanceID,
+			Host:            member.Host,
+			Assignment:      assignment,
+		})
+	}
+	sort.Slice(detail.Members, func(i, j int) bool {
+		return detail.Members[i].ConsumerID < detail.Members[j].ConsumerID
+	})
+
+	partitions := []topicPartition{}
+	for partition := range committed {
+		partitions = append(partitions, partition)
+	}
+	for partition := range consumerOf {
+		if _, found := committed[partition]; !found {
+			partitions = append(partitions, partition)
+		}
+	}
+	if len(partitions) == 0 {
+		return detail, nil
+	}
+	sortPartitions(partitions)
+
+	endOffsets, err := listOffsets(ctx, admin, partitions, kafka.LatestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+	uncommitted := []topicPartition{}
+	for _, partition := range partitions {
+		if offset, found := committed[partition]; !found || offset < 0 {
+			uncommitted = append(uncommitted, partition)
+		}
+	}
+	startOffsets := map[topicPartition]int64{}
+	if len(uncommitted) > 0 {
+		if startOffsets, err = listOffsets(ctx, admin, uncommitted, kafka.EarliestOffsetSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, partition := range partitions {
+		lag := partitionLag{
+			Topic:           partition.Topic,
+			Partition:       partition.Partition,
+			CommittedOffset: -1,
+			EndOffset:       endOffsets[partition],
+			ConsumerID:      consumerOf[partition],
+		}
+		if offset, found := committed[partition]; found && offset >= 0 {
+			lag.CommittedOffset = offset
+			lag.Lag = lag.EndOffset - offset
+		} else {
+			lag.Lag = lag.EndOffset - startOffsets[partition]
+		}
+		if lag.Lag < 0 {
+			lag.Lag = 0
+		}
+		detail.TotalLag += lag.Lag
+		detail.Partitions = append(detail.Partitions, lag)
+	}
+	return detail, nil
+}
+
+func validateResetRequest(request *resetOffsetsRequest) error {
+	if request.Topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	switch request.To = strings.ToLower(request.To); request.To {
+	case ResetToEarliest, ResetToLatest:
+	case ResetToTimestamp:
+		if request.Timestamp == nil {
+			return fmt.Errorf("timestamp is required to reset to the timestamp")
+		}
+	case ResetToOffset:
+		if request.Offset == nil || *request.Offset < 0 {
+			return fmt.Errorf("a non-negative offset is required to reset to the offset")
+		}
+	default:
+		return fmt.Errorf("unknown reset target %q, it's %s, %s, %s or %s", request.To, ResetToEarliest,
+			ResetToLatest, ResetToTimestamp, ResetToOffset)
+	}
+	return nil
+}
+
+// topicPartitions returns the requested partitions of the topic, or all of them if none is requested
+func topicPartitions(admin GroupAdmin, topic string, requested []int32) ([]topicPartition, error) {
+	metadata, err := admin.GetMetadata(&topic, false, metadataTimeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the metadata of the topic %s: %w", topic, err)
+	}
+	topicMetadata, found := metadata.Topics[topic]
+	if !found || topicMetadata.Error.Code() != kafka.ErrNoError || len(topicMetadata.Partitions) == 0 {
+		return nil, fmt.Errorf("topic %s not found", topic)
+	}
+	existing := map[int32]bool{}
+	for _, partition := range topicMetadata.Partitions {
+		existing[partition.ID] = true
+	}
+
+	partitions := []topicPartition{}
+	if len(requested) == 0 {
+		for id := range existing {
+			partitions = append(partitions, topicPartition{Topic: topic, Partition: id})
+		}
+	}
+	for _, id := range requested {
+		if !existing[id] {
+			return nil, fmt.Errorf("partition %d of the topic %s not found", id, topic)
+		}
+		partitions = append(partitions, topicPartition{Topic: topic, Partition: id})
+	}
+	sortPartitions(partitions)
+	return partitions, nil
+}
+
+// resolveOffsets returns the offsets of the partitions to reset to
+func resolveOffsets(ctx context.Context, admin GroupAdmin, request *resetOffsetsRequest,
+	partitions []topicPartition,
+) (map[topicPartition]int64, error) {
+	switch request.To {
+	case ResetToEarliest:
+		return listOffsets(ctx, admin, partitions, kafka.EarliestOffsetSpec)
+	case ResetToLatest:
+		return listOffsets(ctx, admin, partitions, kafka.LatestOffsetSpec)
+	case ResetToTimestamp:
+		offsets, err := listOffsets(ctx, admin, partitions,
+			kafka.NewOffsetSpecForTimestamp(request.Timestamp.UnixMilli()))
+		if err != nil {
+			return nil, err
+		}
+		endOffsets, err := listOffsets(ctx, admin, partitions, kafka.LatestOffsetSpec)
+		if err != nil {
+			return nil, err
+		}
+		// no message is sent since the timestamp
+		for partition, offset := range offsets {
+			if offset < 0 {
+				offsets[partition] = endOffsets[partition]
+			}
+		}
+		return offsets, nil
+	default:
+		startOffsets, err := listOffsets(ctx, admin, partitions, kafka.EarliestOffsetSpec)
+		if err != nil {
+			return nil, err
+		}
+		endOffsets, err := listOffsets(ctx, admin, partitions, kafka.LatestOffsetSpec)
+		if err != nil {
+			return nil, err
+		}
+		offsets := map[topicPartition]int64{}
+		for _, partition := range partitions {
+			if *request.Offset < startOffsets[partition] || *request.Offset > endOffsets[partition] {
+				return nil, fmt.Errorf("offset %d is out of the range [%d, %d] of the partition %d", *request.Offset,
+					startOffsets[partition], endOffsets[partition], partition.Partition)
+			}
+			offsets[partition] = *request.Offset
+		}
+		return offsets, nil
+	}
+}
+
+func listOffsets(ctx context.Context, admin GroupAdmin, partitions []topicPartition, spec kafka.OffsetSpec,
+) (map[topicPartition]int64, error) {
+	specs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	for _, partition := range partitions {
+		topic := partition.Topic
+		specs[kafka.TopicPartition{Topic: &topic, Partition: partition.Partition}] = spec
+	}
+	result, err := admin.ListOffsets(ctx, specs)
+	if err != nil {
+		return nil, err
+	}
+	offsets := map[topicPartition]int64{}
+	for partition, info := range result.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return nil, fmt.Errorf("failed to list the offset of %s[%d]: %w", *partition.Topic, partition.Partition,
+				info.Error)
+		}
+		offsets[topicPartition{Topic: *partition.Topic, Partition: partition.Partition}] = int64(info.Offset)
+	}
+	return offsets, nil
+}
+
+func sortPartitions(partitions []topicPartition) {
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].Topic != partitions[j].Topic {
+			return partitions[i].Topic < partitions[j].Topic
+		}
+		return partitions[i].Partition < partitions[j].Partition
+	})
+}
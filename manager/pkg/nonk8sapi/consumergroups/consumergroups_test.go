@@ -0,0 +1,224 @@
+package consumergroups
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTopic = "gh-status"
+
+// fakeAdmin is a kafka cluster with the topic of 2 partitions, each of them retains the offsets [10, 100), the
+// mock cluster of the confluent client doesn't support the consumer group apis
+type fakeAdmin struct {
+	members   map[string][]kafka.MemberDescription
+	committed map[string]map[int32]int64
+	altered   []kafka.TopicPartition
+}
+
+func newFakeAdmin() *fakeAdmin {
+	return &fakeAdmin{
+		members: map[string][]kafka.MemberDescription{
+			"manager": {{
+				ClientID:   "manager-client",
+				ConsumerID: "manager-consumer",
+				Host:       "/10.0.0.1",
+				Assignment: kafka.MemberAssignment{TopicPartitions: []kafka.TopicPartition{
+					{Topic: stringPtr(testTopic), Partition: 0},
+					{Topic: stringPtr(testTopic), Partition: 1},
+				}},
+			}},
+			"inspector": {},
+		},
+		committed: map[string]map[int32]int64{
+			"manager":   {0: 90},
+			"inspector": {0: 40, 1: 50},
+		},
+	}
+}
+
+func (a *fakeAdmin) ListConsumerGroups(ctx context.Context, options ...kafka.ListConsumerGroupsAdminOption,
+) (kafka.ListConsumerGroupsResult, error) {
+	return kafka.ListConsumerGroupsResult{Valid: []kafka.ConsumerGroupListing{
+		{GroupID: "manager", State: kafka.ConsumerGroupStateStable},
+		{GroupID: "inspector", State: kafka.ConsumerGroupStateEmpty},
+	}}, nil
+}
+
+func (a *fakeAdmin) DescribeConsumerGroups(ctx context.Context, groups []string,
+	options ...kafka.DescribeConsumerGroupsAdminOption,
+) (kafka.DescribeConsumerGroupsResult, error) {
+	result := kafka.DescribeConsumerGroupsResult{}
+	for _, group := range groups {
+		members, found := a.members[group]
+		state := kafka.ConsumerGroupStateDead
+		if found {
+			state = kafka.ConsumerGroupStateEmpty
+		}
+		if len(members) > 0 {
+			state = kafka.ConsumerGroupStateStable
+		}
+		result.ConsumerGroupDescriptions = append(result.ConsumerGroupDescriptions, kafka.ConsumerGroupDescription{
+			GroupID:           group,
+			State:             state,
+			PartitionAssignor: "range",
+			Members:           members,
+		})
+	}
+	return result, nil
+}
+
+func (a *fakeAdmin) ListConsumerGroupOffsets(ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions,
+	options ...kafka.ListConsumerGroupOffsetsAdminOption,
+) (kafka.ListConsumerGroupOffsetsResult, error) {
+	result := kafka.ListConsumerGroupOffsetsResult{}
+	for _, group := range groupsPartitions {
+		partitions := []kafka.TopicPartition{}
+		for partition, offset := range a.committed[group.Group] {
+			partitions = append(partitions, kafka.TopicPartition{
+				Topic: stringPtr(testTopic), Partition: partition, Offset: kafka.Offset(offset),
+			})
+		}
+		result.ConsumerGroupsTopicPartitions = append(result.ConsumerGroupsTopicPartitions,
+			kafka.ConsumerGroupTopicPartitions{Group: group.Group, Partitions: partitions})
+	}
+	return result, nil
+}
+
+func (a *fakeAdmin) AlterConsumerGroupOffsets(ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions,
+	options ...kafka.AlterConsumerGroupOffsetsAdminOption,
+) (kafka.AlterConsumerGroupOffsetsResult, error) {
+	for _, group := range groupsPartitions {
+		a.altered = append(a.altered, group.Partitions...)
+	}
+	return kafka.AlterConsumerGroupOffsetsResult{ConsumerGroupsTopicPartitions: groupsPartitions}, nil
+}
+
+func (a *fakeAdmin) ListOffsets(ctx context.Context, topicPartitionOffsets map[kafka.TopicPartition]kafka.OffsetSpec,
+	options ...kafka.ListOffsetsAdminOption,
+) (kafka.ListOffsetsResult, error) {
+	result := kafka.ListOffsetsResult{ResultInfos: map[kafka.TopicPartition]kafka.ListOffsetsResultInfo{}}
+	for partition, spec := range topicPartitionOffsets {
+		offset := kafka.Offset(30) // the first message at or after the timestamp
+		switch spec {
+		case kafka.EarliestOffsetSpec:
+			offset = 10
+		case kafka.LatestOffsetSpec:
+			offset = 100
+		}
+		result.ResultInfos[partition] = kafka.ListOffsetsResultInfo{Offset: offset}
+	}
+	return result, nil
+}
+
+func (a *fakeAdmin) GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error) {
+	return &kafka.Metadata{Topics: map[string]kafka.TopicMetadata{
+		testTopic: {Topic: testTopic, Partitions: []kafka.PartitionMetadata{{ID: 0}, {ID: 1}}},
+	}}, nil
+}
+
+func (a *fakeAdmin) Close() {}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func setupRouter(admin *fakeAdmin) *gin.Engine {
+	adminFunc := func() (GroupAdmin, error) { return admin, nil }
+	router := gin.New()
+	router.GET("/consumergroups", ListConsumerGroups(adminFunc))
+	router.GET("/consumergroup/:groupID", GetConsumerGroup(adminFunc))
+	router.POST("/consumergroup/:groupID/offsets", ResetConsumerGroupOffsets(adminFunc))
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestListConsumerGroups(t *testing.T) {
+	w := serve(setupRouter(newFakeAdmin()), http.MethodGet, "/consumergroups", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	groups := []consumerGroup{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &groups))
+	require.Len(t, groups, 2)
+	assert.Equal(t, "inspector", groups[0].GroupID)
+	assert.Equal(t, "Empty", groups[0].State)
+	assert.Equal(t, "manager", groups[1].GroupID)
+	assert.Equal(t, "Stable", groups[1].State)
+}
+
+func TestGetConsumerGroup(t *testing.T) {
+	router := setupRouter(newFakeAdmin())
+
+	w := serve(router, http.MethodGet, "/consumergroup/manager", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	detail := consumerGroupDetail{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	require.Len(t, detail.Members, 1)
+	assert.Equal(t, "manager-consumer", detail.Members[0].ConsumerID)
+	assert.Len(t, detail.Members[0].Assignment, 2)
+	require.Len(t, detail.Partitions, 2)
+	// the committed partition lags behind the end, and the uncommitted one lags all the retained messages
+	assert.Equal(t, partitionLag{
+		Topic: testTopic, Partition: 0, CommittedOffset: 90, EndOffset: 100, Lag: 10, ConsumerID: "manager-consumer",
+	}, detail.Partitions[0])
+	assert.Equal(t, partitionLag{
+		Topic: testTopic, Partition: 1, CommittedOffset: -1, EndOffset: 100, Lag: 90, ConsumerID: "manager-consumer",
+	}, detail.Partitions[1])
+	assert.Equal(t, int64(100), detail.TotalLag)
+
+	w = serve(router, http.MethodGet, "/consumergroup/unknown", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestResetConsumerGroupOffsets(t *testing.T) {
+	admin := newFakeAdmin()
+	router := setupRouter(admin)
+
+	// the group with the active members isn't reset
+	w := serve(router, http.MethodPost, "/consumergroup/manager/offsets",
+		`{"topic": "gh-status", "to": "earliest"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = serve(router, http.MethodPost, "/consumergroup/inspector/offsets", `{"topic": "gh-status", "to": "now"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(router, http.MethodPost, "/consumergroup/inspector/offsets",
+		`{"topic": "gh-status", "to": "offset", "offset": 200}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(router, http.MethodPost, "/consumergroup/inspector/offsets",
+		`{"topic": "gh-status", "partitions": [2], "to": "latest"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the dry run doesn't commit the offsets
+	w = serve(router, http.MethodPost, "/consumergroup/inspector/offsets",
+		`{"topic": "gh-status", "to": "timestamp", "timestamp": "2024-01-01T00:00:00Z", "dryRun": true}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	result := resetOffsetsResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.DryRun)
+	assert.Equal(t, []resetOffsets{
+		{Topic: testTopic, Partition: 0, PreviousOffset: 40, Offset: 30},
+		{Topic: testTopic, Partition: 1, PreviousOffset: 50, Offset: 30},
+	}, result.Partitions)
+	assert.Empty(t, admin.altered)
+
+	w = serve(router, http.MethodPost, "/consumergroup/inspector/offsets",
+		`{"topic": "gh-status", "partitions": [1], "to": "offset", "offset": 60}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, admin.altered, 1)
+	assert.Equal(t, int32(1), admin.altered[0].Partition)
+	assert.Equal(t, kafka.Offset(60), admin.altered[0].Offset)
+}
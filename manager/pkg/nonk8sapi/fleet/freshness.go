@@ -0,0 +1,97 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fleet
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/freshness"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// dataFreshness is the freshness of the bundle of the type received from the managed hub, the lag is how long the
+// bundle falls behind the expected interval
+type dataFreshness struct {
+	Hub                     string    `json:"hub"`
+	BundleType              string    `json:"bundleType"`
+	LastReceivedAt          time.Time `json:"lastReceivedAt"`
+	AgeSeconds              int64     `json:"ageSeconds"`
+	ExpectedIntervalSeconds *int64    `json:"expectedIntervalSeconds,omitempty"`
+	LagSeconds              int64     `json:"lagSeconds"`
+	Stale                   bool      `json:"stale"`
+}
+
+// ListDataFreshness godoc
+// @summary list data freshness
+// @description list the last time the bundle of each type is received from the managed hubs, and how long it falls
+// @description behind the interval it's expected to be received in. The bundles only sent once they're changed don't
+// @description have the expected interval and they're never stale
+// @accept json
+// @produce json
+// @param        hub      query    string    false    "filter the bundles by the managed hub"
+// @param        stale    query    boolean   false    "only list the stale bundles if it's true"
+// @success      200  {array}   dataFreshness
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /fleet/freshness [get]
+func ListDataFreshness() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		staleOnly := false
+		if value := ginCtx.Query("stale"); value != "" {
+			var err error
+			if staleOnly, err = strconv.ParseBool(value); err != nil {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid stale %s, must be true or false", value))
+				return
+			}
+		}
+
+		query := database.GetGorm().Model(&models.DataFreshness{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		rows := []models.DataFreshness{}
+		if err := query.Order("leaf_hub_name, bundle_type").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the data freshness: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		now := time.Now()
+		items := []dataFreshness{}
+		for _, row := range rows {
+			item := toDataFreshness(row, now)
+			if staleOnly && !item.Stale {
+				continue
+			}
+			items = append(items, item)
+		}
+		ginCtx.JSON(http.StatusOK, items)
+	}
+}
+
+func toDataFreshness(row models.DataFreshness, now time.Time) dataFreshness {
+	item := dataFreshness{
+		Hub:                     row.LeafHubName,
+		BundleType:              row.BundleType,
+		LastReceivedAt:          row.LastReceivedAt,
+		AgeSeconds:              max(int64(now.Sub(row.LastReceivedAt).Seconds()), 0),
+		ExpectedIntervalSeconds: row.ExpectedIntervalSeconds,
+	}
+	if row.ExpectedIntervalSeconds != nil {
+		item.LagSeconds = max(item.AgeSeconds-*row.ExpectedIntervalSeconds, 0)
+		// the received bundles are persisted periodically, so the lag within the flush interval isn't stale
+		item.Stale = item.LagSeconds > int64(freshness.FlushInterval.Seconds())
+	}
+	return item
+}
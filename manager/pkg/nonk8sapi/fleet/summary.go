@@ -0,0 +1,222 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package fleet
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+
+	// the managed cluster is available if its ManagedClusterConditionAvailable condition is true
+	clusterAvailableSQL = `EXISTS (SELECT 1 FROM jsonb_array_elements(managed_clusters.payload->'status'->'conditions') ` +
+		`AS c WHERE c->>'type' = 'ManagedClusterConditionAvailable' AND c->>'status' = 'True')`
+)
+
+// fleetSummary is the overview of all the managed hubs and clusters, it backs the summary pages of the console
+type fleetSummary struct {
+	Hubs        hubCounts          `json:"hubs"`
+	Clusters    clusterCounts      `json:"clusters"`
+	Compliance  complianceCounts   `json:"compliance"`
+	Agents      agentCounts        `json:"agents"`
+	Enrichments []enrichmentCounts `json:"enrichments,omitempty"`
+}
+
+type hubCounts struct {
+	Total    int `json:"total"`
+	Active   int `json:"active"`
+	Inactive int `json:"inactive"`
+	Detached int `json:"detached"`
+}
+
+type clusterCounts struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+}
+
+// complianceCounts is the number of the policy statuses on the managed clusters in each compliance state
+type complianceCounts struct {
+	Compliant    int `json:"compliant"`
+	NonCompliant int `json:"nonCompliant"`
+	Pending      int `json:"pending"`
+	Unknown      int `json:"unknown"`
+}
+
+type agentCounts struct {
+	Incompatible int `json:"incompatible"`
+	Quarantined  int `json:"quarantined"`
+}
+
+// enrichmentCounts is the number of the clusters in the business unit and the region computed by the enrichers
+type enrichmentCounts struct {
+	BusinessUnit string `json:"businessUnit"`
+	Region       string `json:"region"`
+	Clusters     int    `json:"clusters"`
+}
+
+// hubSummary is the overview of the managed hub
+type hubSummary struct {
+	Name                 string    `json:"name"`
+	Status               string    `json:"status"`
+	LastHeartbeat        time.Time `json:"lastHeartbeat"`
+	Clusters             int       `json:"clusters"`
+	AvailableClusters    int       `json:"availableClusters"`
+	NonCompliantClusters int       `json:"nonCompliantClusters"`
+}
+
+// GetFleetSummary godoc
+// @summary get fleet summary
+// @description get the number of the managed hubs and clusters, the compliance and the incompatible agents of the
+// @description fleet, and the clusters grouped by the business unit and region if the enrichment is configured
+// @accept json
+// @produce json
+// @success      200  {object}  fleetSummary
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /fleet/summary [get]
+func GetFleetSummary() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		summary, err := summarizeFleet(database.GetGorm())
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to summarize the fleet: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, summary)
+	}
+}
+
+func summarizeFleet(db *gorm.DB) (*fleetSummary, error) {
+	summary := &fleetSummary{}
+
+	var hubRows []struct {
+		Status string
+		Count  int
+	}
+	err := db.Model(&models.LeafHubHeartbeat{}).Select("status, COUNT(*) AS count").Group("status").
+		Find(&hubRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the managed hubs: %w", err)
+	}
+	for _, row := range hubRows {
+		summary.Hubs.Total += row.Count
+		switch row.Status {
+		case "active":
+			summary.Hubs.Active = row.Count
+		case "inactive":
+			summary.Hubs.Inactive = row.Count
+		case "detached":
+			summary.Hubs.Detached = row.Count
+		}
+	}
+
+	err = db.Model(&models.ManagedCluster{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE " + clusterAvailableSQL + ") AS available").
+		Scan(&summary.Clusters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the managed clusters: %w", err)
+	}
+
+	var complianceRows []struct {
+		Compliance string
+		Count      int
+	}
+	err = db.Model(&models.LocalStatusCompliance{}).Select("compliance, COUNT(*) AS count").Group("compliance").
+		Find(&complianceRows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the compliance: %w", err)
+	}
+	for _, row := range complianceRows {
+		switch row.Compliance {
+		case "compliant":
+			summary.Compliance.Compliant = row.Count
+		case "non_compliant":
+			summary.Compliance.NonCompliant = row.Count
+		case "pending":
+			summary.Compliance.Pending = row.Count
+		default:
+			summary.Compliance.Unknown += row.Count
+		}
+	}
+
+	err = db.Model(&models.AgentVersion{}).
+		Select("COUNT(*) FILTER (WHERE compatibility = 'incompatible') AS incompatible, " +
+			"COUNT(*) FILTER (WHERE quarantined) AS quarantined").
+		Scan(&summary.Agents).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the incompatible agents: %w", err)
+	}
+
+	err = db.Model(&models.ManagedClusterEnrichment{}).
+		Select("COALESCE(business_unit, '') AS business_unit, COALESCE(region, '') AS region, COUNT(*) AS clusters").
+		Joins("JOIN status.managed_clusters ON managed_clusters.cluster_id = managed_cluster_enrichments.cluster_id " +
+			"AND managed_clusters.deleted_at IS NULL").
+		Group("business_unit, region").Order("clusters DESC, business_unit, region").
+		Scan(&summary.Enrichments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the enriched clusters: %w", err)
+	}
+	return summary, nil
+}
+
+// ListHubSummaries godoc
+// @summary list hub summaries
+// @description list the managed hubs with the number of their clusters, the available clusters and the clusters
+// @description which are non compliant with any policy, the inactive hubs are listed first
+// @accept json
+// @produce json
+// @param        status    query    string    false    "filter the hubs by the status, active, inactive or detached"
+// @success      200  {array}   hubSummary
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /fleet/hubs [get]
+func ListHubSummaries() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		status := ginCtx.Query("status")
+		if status != "" && status != "active" && status != "inactive" && status != "detached" {
+			ginCtx.String(http.StatusBadRequest,
+				fmt.Sprintf("invalid status %s, must be active, inactive or detached", status))
+			return
+		}
+
+		query := database.GetGorm().Model(&models.LeafHubHeartbeat{}).
+			Select("leaf_hub_heartbeats.leaf_hub_name AS name, leaf_hub_heartbeats.status, " +
+				"leaf_hub_heartbeats.last_timestamp AS last_heartbeat, " +
+				"COUNT(managed_clusters.cluster_id) AS clusters, " +
+				"COUNT(managed_clusters.cluster_id) FILTER (WHERE " + clusterAvailableSQL + ") AS available_clusters, " +
+				"(SELECT COUNT(DISTINCT c.cluster_name) FROM local_status.compliance c " +
+				"WHERE c.leaf_hub_name = leaf_hub_heartbeats.leaf_hub_name AND c.compliance = 'non_compliant') " +
+				"AS non_compliant_clusters").
+			Joins("LEFT JOIN status.managed_clusters ON managed_clusters.leaf_hub_name = " +
+				"leaf_hub_heartbeats.leaf_hub_name AND managed_clusters.deleted_at IS NULL").
+			Group("leaf_hub_heartbeats.leaf_hub_name, leaf_hub_heartbeats.status, leaf_hub_heartbeats.last_timestamp")
+		if status != "" {
+			query = query.Where("leaf_hub_heartbeats.status = ?", status)
+		}
+		hubs := []hubSummary{}
+		err := query.Order("leaf_hub_heartbeats.status DESC, leaf_hub_heartbeats.leaf_hub_name").Scan(&hubs).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to summarize the managed hubs: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, hubs)
+	}
+}
@@ -0,0 +1,40 @@
+package nonk8sapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithForwardedAccessToken(t *testing.T) {
+	cases := []struct {
+		name          string
+		authorization string
+		forwarded     string
+		expected      string
+	}{
+		{"bearer token", "Bearer user-token", "session-token", "Bearer user-token"},
+		{"forwarded access token", "", "session-token", "Bearer session-token"},
+		{"basic authorization", "Basic dXNlcg==", "session-token", "Bearer session-token"},
+		{"without token", "", "", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			authorization := ""
+			handler := withForwardedAccessToken(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				authorization = req.Header.Get("Authorization")
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/global-hub-api/v1/managedclusters", nil)
+			if c.authorization != "" {
+				req.Header.Set("Authorization", c.authorization)
+			}
+			if c.forwarded != "" {
+				req.Header.Set("X-Forwarded-Access-Token", c.forwarded)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if authorization != c.expected {
+				t.Errorf("expected the authorization %q, got %q", c.expected, authorization)
+			}
+		})
+	}
+}
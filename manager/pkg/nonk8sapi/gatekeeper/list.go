@@ -0,0 +1,141 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package gatekeeper
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// constraint is the audit result of the gatekeeper constraint on the cluster
+type constraint struct {
+	LeafHubName       string     `json:"leafHubName"`
+	ClusterName       string     `json:"clusterName"`
+	Kind              string     `json:"kind"`
+	Name              string     `json:"name"`
+	EnforcementAction string     `json:"enforcementAction"`
+	TotalViolations   int        `json:"totalViolations"`
+	AuditTimestamp    *time.Time `json:"auditTimestamp,omitempty"`
+}
+
+// violation is the resource which violates the gatekeeper constraint on the cluster
+type violation struct {
+	LeafHubName       string `json:"leafHubName"`
+	ClusterName       string `json:"clusterName"`
+	ConstraintKind    string `json:"constraintKind"`
+	ConstraintName    string `json:"constraintName"`
+	Kind              string `json:"kind"`
+	Namespace         string `json:"namespace,omitempty"`
+	Name              string `json:"name"`
+	Message           string `json:"message"`
+	EnforcementAction string `json:"enforcementAction"`
+}
+
+// ListConstraints godoc
+// @summary list gatekeeper constraints
+// @description list the audit results of the gatekeeper constraints, the constraints with more violations are listed first
+// @accept json
+// @produce json
+// @param        hub        query    string    false    "filter the constraints by the managed hub"
+// @param        cluster    query    string    false    "filter the constraints by the cluster"
+// @param        kind       query    string    false    "filter the constraints by the kind, e.g. K8sRequiredLabels"
+// @success      200  {array}   constraint
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /gatekeeper/constraints [get]
+func ListConstraints() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.GatekeeperConstraint{}).Where(&models.GatekeeperConstraint{
+			LeafHubName:    ginCtx.Query("hub"),
+			ClusterName:    ginCtx.Query("cluster"),
+			ConstraintKind: ginCtx.Query("kind"),
+		})
+		var rows []models.GatekeeperConstraint
+		err := query.Order("total_violations DESC, leaf_hub_name, cluster_name, constraint_kind, constraint_name").
+			Find(&rows).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the gatekeeper constraints: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		constraints := make([]constraint, 0, len(rows))
+		for _, row := range rows {
+			constraints = append(constraints, constraint{
+				LeafHubName:       row.LeafHubName,
+				ClusterName:       row.ClusterName,
+				Kind:              row.ConstraintKind,
+				Name:              row.ConstraintName,
+				EnforcementAction: row.EnforcementAction,
+				TotalViolations:   row.TotalViolations,
+				AuditTimestamp:    row.AuditTimestamp,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, constraints)
+	}
+}
+
+// ListViolations godoc
+// @summary list gatekeeper violations
+// @description list the resources violating the gatekeeper constraints
+// @accept json
+// @produce json
+// @param        hub           query    string    false    "filter the violations by the managed hub"
+// @param        cluster       query    string    false    "filter the violations by the cluster"
+// @param        kind          query    string    false    "filter the violations by the constraint kind"
+// @param        constraint    query    string    false    "filter the violations by the constraint name"
+// @param        namespace     query    string    false    "filter the violations by the namespace of the resource"
+// @success      200  {array}   violation
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /gatekeeper/violations [get]
+func ListViolations() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.GatekeeperViolation{}).Where(&models.GatekeeperViolation{
+			LeafHubName:       ginCtx.Query("hub"),
+			ClusterName:       ginCtx.Query("cluster"),
+			ConstraintKind:    ginCtx.Query("kind"),
+			ConstraintName:    ginCtx.Query("constraint"),
+			ResourceNamespace: ginCtx.Query("namespace"),
+		})
+		var rows []models.GatekeeperViolation
+		err := query.Order("leaf_hub_name, cluster_name, constraint_kind, constraint_name, resource_namespace, " +
+			"resource_name").Find(&rows).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the gatekeeper violations: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		violations := make([]violation, 0, len(rows))
+		for _, row := range rows {
+			violations = append(violations, violation{
+				LeafHubName:       row.LeafHubName,
+				ClusterName:       row.ClusterName,
+				ConstraintKind:    row.ConstraintKind,
+				ConstraintName:    row.ConstraintName,
+				Kind:              row.ResourceKind,
+				Namespace:         row.ResourceNamespace,
+				Name:              row.ResourceName,
+				Message:           row.Message,
+				EnforcementAction: row.EnforcementAction,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, violations)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package localpolicies
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	dateFormat             = "2006-01-02"
+	// the history of the last 30 days is returned if the start date isn't specified
+	defaultHistoryDays = 30
+	defaultFlapLimit   = 20
+)
+
+const (
+	// the cluster is joined with the soft deleted records, so that the history of the removed clusters is still named
+	complianceHistoryQuery = `SELECT h.leaf_hub_name, COALESCE(h.cluster_id::text, '') AS cluster_id,
+			COALESCE(mc.cluster_name, '') AS cluster_name,
+			h.compliance_date, h.compliance, h.compliance_changed_frequency
+		FROM history.local_compliance h
+		LEFT JOIN status.managed_clusters mc ON mc.cluster_id = h.cluster_id
+		WHERE h.policy_id = @policyID AND h.compliance_date BETWEEN @start AND @end
+			AND (@cluster = '' OR mc.cluster_name = @cluster)
+		ORDER BY h.leaf_hub_name, cluster_name, h.compliance_date`
+	complianceFlapQuery = `SELECT h.leaf_hub_name, COALESCE(h.cluster_id::text, '') AS cluster_id,
+			COALESCE(mc.cluster_name, '') AS cluster_name,
+			SUM(h.compliance_changed_frequency) AS flap_count,
+			COUNT(*) FILTER (WHERE h.compliance = 'non_compliant') AS non_compliant_days,
+			COUNT(*) AS days
+		FROM history.local_compliance h
+		LEFT JOIN status.managed_clusters mc ON mc.cluster_id = h.cluster_id
+		WHERE h.policy_id = @policyID AND h.compliance_date BETWEEN @start AND @end
+		GROUP BY h.leaf_hub_name, h.cluster_id, mc.cluster_name
+		ORDER BY flap_count DESC, h.leaf_hub_name, cluster_name
+		LIMIT @limit`
+)
+
+// clusterComplianceHistory is the daily compliance timeline of the local policy on a managed cluster
+type clusterComplianceHistory struct {
+	LeafHubName string            `json:"leafHubName"`
+	ClusterID   string            `json:"clusterID"`
+	ClusterName string            `json:"clusterName"`
+	History     []dailyCompliance `json:"history"`
+}
+
+type dailyCompliance struct {
+	Date       string `json:"date"`
+	Compliance string `json:"compliance"`
+	// ChangedFrequency is how many times the compliance is changed in the day
+	ChangedFrequency int `json:"changedFrequency"`
+}
+
+// clusterComplianceFlap summarizes how unstable the compliance of the local policy is on a managed cluster
+type clusterComplianceFlap struct {
+	LeafHubName      string `json:"leafHubName"`
+	ClusterID        string `json:"clusterID"`
+	ClusterName      string `json:"clusterName"`
+	FlapCount        int    `json:"flapCount"`
+	NonCompliantDays int    `json:"nonCompliantDays"`
+	Days             int    `json:"days"`
+}
+
+// GetComplianceHistory godoc
+// @summary get local policy compliance history
+// @description get the daily compliance timelines of the local policy on the managed clusters
+// @accept json
+// @produce json
+// @param        policyID    path     string    true     "Local policy ID"
+// @param        cluster     query    string    false    "only return the timeline of the managed cluster"
+// @param        start       query    string    false    "start date of the history, e.g. 2024-01-01, 30 days ago by default"
+// @param        end         query    string    false    "end date of the history, e.g. 2024-01-31, yesterday by default"
+// @success      200  {array}   clusterComplianceHistory
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /localpolicy/{policyID}/compliancehistory [get]
+func GetComplianceHistory() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		args, ok := parseHistoryArgs(ginCtx)
+		if !ok {
+			return
+		}
+		args["cluster"] = ginCtx.Query("cluster")
+
+		rows, err := database.GetGorm().Raw(complianceHistoryQuery, args).Rows()
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to query the local compliance history: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		defer rows.Close()
+
+		timelines := []clusterComplianceHistory{}
+		for rows.Next() {
+			var leafHubName, clusterID, clusterName, compliance string
+			var date time.Time
+			var frequency int
+			if err := rows.Scan(&leafHubName, &clusterID, &clusterName, &date, &compliance, &frequency); err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "failed to scan the local compliance history: %v\n", err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+			// the rows are ordered by the cluster, so the records of a cluster are adjacent
+			last := len(timelines) - 1
+			if last < 0 || timelines[last].LeafHubName != leafHubName || timelines[last].ClusterID != clusterID {
+				timelines = append(timelines, clusterComplianceHistory{
+					LeafHubName: leafHubName,
+					ClusterID:   clusterID,
+					ClusterName: clusterName,
+					History:     []dailyCompliance{},
+				})
+				last++
+			}
+			timelines[last].History = append(timelines[last].History, dailyCompliance{
+				Date:             date.Format(dateFormat),
+				Compliance:       compliance,
+				ChangedFrequency: frequency,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, timelines)
+	}
+}
+
+// GetComplianceFlaps godoc
+// @summary get local policy compliance flaps
+// @description get the managed clusters on which the compliance of the local policy changes most frequently
+// @accept json
+// @produce json
+// @param        policyID    path     string    true     "Local policy ID"
+// @param        start       query    string    false    "start date of the history, e.g. 2024-01-01, 30 days ago by default"
+// @param        end         query    string    false    "end date of the history, e.g. 2024-01-31, yesterday by default"
+// @param        limit       query    int       false    "maximum cluster number to receive, 20 by default"
+// @success      200  {array}   clusterComplianceFlap
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /localpolicy/{policyID}/flaps [get]
+func GetComplianceFlaps() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		args, ok := parseHistoryArgs(ginCtx)
+		if !ok {
+			return
+		}
+		args["limit"] = defaultFlapLimit
+		if limit := ginCtx.Query("limit"); limit != "" {
+			value, err := strconv.Atoi(limit)
+			if err != nil || value <= 0 {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid limit %s, must be a positive integer", limit))
+				return
+			}
+			args["limit"] = value
+		}
+
+		flaps := []clusterComplianceFlap{}
+		if err := database.GetGorm().Raw(complianceFlapQuery, args).Scan(&flaps).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to query the local compliance flaps: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, flaps)
+	}
+}
+
+// parseHistoryArgs returns the named arguments of the policy and date range, the bad request is responded if they're
+// invalid
+func parseHistoryArgs(ginCtx *gin.Context) (map[string]interface{}, bool) {
+	policyID := ginCtx.Param("policyID")
+	if _, err := uuid.Parse(policyID); err != nil {
+		ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid policy ID %s, must be a UUID", policyID))
+		return nil, false
+	}
+
+	// the history of the day is summarized after the midnight, so it ends at yesterday by default
+	end := time.Now().AddDate(0, 0, -1)
+	if value := ginCtx.Query("end"); value != "" {
+		date, err := time.Parse(dateFormat, value)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid end date %s, must be YYYY-MM-DD", value))
+			return nil, false
+		}
+		end = date
+	}
+	start := end.AddDate(0, 0, -defaultHistoryDays)
+	if value := ginCtx.Query("start"); value != "" {
+		date, err := time.Parse(dateFormat, value)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid start date %s, must be YYYY-MM-DD", value))
+			return nil, false
+		}
+		start = date
+	}
+	if start.After(end) {
+		ginCtx.String(http.StatusBadRequest, "the start date must not be after the end date")
+		return nil, false
+	}
+
+	return map[string]interface{}{
+		"policyID": policyID,
+		"start":    start.Format(dateFormat),
+		"end":      end.Format(dateFormat),
+	}, true
+}
@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
@@ -44,6 +45,7 @@ const (
 // @accept json
 // @produce json
 // @param        labelSelector    query     string  false  "list managed clusters by label selector"
+// @param        group            query     string  false  "list managed clusters by the cluster group"
 // @param        limit            query     int     false  "maximum managed cluster number to receive"
 // @param        continue         query     string  false  "continue token to request next request"
 // @success      200  {object}    clusterv1.ManagedClusterList
@@ -73,6 +75,16 @@ func ListManagedClusters() gin.HandlerFunc {
 			}
 		}
 
+		// the group name is validated as the DNS label, so it's safe to be embedded in the query
+		if group := ginCtx.Query("group"); group != "" {
+			if errs := validation.IsDNS1123Label(group); len(errs) > 0 {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid group %s: %v", group, errs))
+				return
+			}
+			selectorInSql += fmt.Sprintf(" AND cluster_id IN (SELECT cluster_id FROM status.cluster_group_members "+
+				"WHERE group_name = '%s')", group)
+		}
+
 		fmt.Fprintf(gin.DefaultWriter, "parsed selector: %s\n", selectorInSql)
 
 		limit := ginCtx.Query("limit")
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// agent is the version and the health of the agent reported with the heartbeat of the managed hub
+type agent struct {
+	Name           string               `json:"name"`
+	AgentVersion   string               `json:"agentVersion"`
+	Collectors     []string             `json:"collectors"`
+	LastSyncTimes  map[string]time.Time `json:"lastSyncTimes,omitempty"`
+	ResourceCounts map[string]int       `json:"resourceCounts,omitempty"`
+	ReportedAt     time.Time            `json:"reportedAt"`
+}
+
+// ListAgents godoc
+// @summary list agents
+// @description list the version and the health of the agents on the managed hubs, which are reported with the heartbeats
+// @accept json
+// @produce json
+// @param        version    query    string    false    "filter the agents by the version"
+// @success      200  {array}   agent
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /agents [get]
+func ListAgents() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.AgentHealth{}).
+			Where(&models.AgentHealth{AgentVersion: ginCtx.Query("version")})
+		var rows []models.AgentHealth
+		if err := query.Order("leaf_hub_name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the agent health: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		agents := make([]agent, 0, len(rows))
+		for _, row := range rows {
+			a := agent{
+				Name:         row.LeafHubName,
+				AgentVersion: row.AgentVersion,
+				ReportedAt:   row.ReportedAt,
+			}
+			if err := unmarshalHealth(row, &a); err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "failed to unmarshal the agent health of %s: %v\n", row.LeafHubName, err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+			agents = append(agents, a)
+		}
+		ginCtx.JSON(http.StatusOK, agents)
+	}
+}
+
+func unmarshalHealth(row models.AgentHealth, a *agent) error {
+	if err := json.Unmarshal(row.Collectors, &a.Collectors); err != nil {
+		return err
+	}
+	if len(row.LastSyncTimes) > 0 {
+		if err := json.Unmarshal(row.LastSyncTimes, &a.LastSyncTimes); err != nil {
+			return err
+		}
+	}
+	if len(row.ResourceCounts) > 0 {
+		if err := json.Unmarshal(row.ResourceCounts, &a.ResourceCounts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// agentVersion is the version of the agent detected from the bundles and its compatibility with the manager
+type agentVersion struct {
+	Name           string    `json:"name"`
+	AgentVersion   string    `json:"agentVersion"`
+	ManagerVersion string    `json:"managerVersion"`
+	Compatibility  string    `json:"compatibility"`
+	Reason         string    `json:"reason,omitempty"`
+	Quarantined    bool      `json:"quarantined"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// ListAgentVersions godoc
+// @summary list agent versions
+// @description list the versions of the agents detected from the bundles and their compatibility with the manager,
+// @description the bundles of the quarantined agents aren't persisted except the heartbeats
+// @accept json
+// @produce json
+// @param        compatibility    query    string    false    "filter the agents by the compatibility: compatible, incompatible or unknown"
+// @success      200  {array}   agentVersion
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /agents/versions [get]
+func ListAgentVersions() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.AgentVersion{}).
+			Where(&models.AgentVersion{Compatibility: ginCtx.Query("compatibility")})
+		var rows []models.AgentVersion
+		if err := query.Order("leaf_hub_name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the agent versions: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		versions := make([]agentVersion, 0, len(rows))
+		for _, row := range rows {
+			versions = append(versions, agentVersion{
+				Name:           row.LeafHubName,
+				AgentVersion:   row.AgentVersion,
+				ManagerVersion: row.ManagerVersion,
+				Compatibility:  row.Compatibility,
+				Reason:         row.Reason,
+				Quarantined:    row.Quarantined,
+				UpdatedAt:      row.UpdatedAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, versions)
+	}
+}
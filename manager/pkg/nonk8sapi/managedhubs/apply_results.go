@@ -0,0 +1,69 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// applyResult is the result of applying the global resource on the managed hub, reported by the agent
+type applyResult struct {
+	Hub        string    `json:"hub"`
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	Result     string    `json:"result"`
+	Reason     string    `json:"reason,omitempty"`
+	AppliedAt  time.Time `json:"appliedAt"`
+}
+
+// ListApplyResults godoc
+// @summary list apply results
+// @description list the results of applying the global resources on the managed hubs, e.g. the reason of the failed ones
+// @accept json
+// @produce json
+// @param        hub       query    string    false    "filter the results by the managed hub"
+// @param        result    query    string    false    "filter the results by the result: applied, failed or conflicted"
+// @success      200  {array}   applyResult
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /applyresults [get]
+func ListApplyResults() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.SpecApplyResult{}).
+			Where(&models.SpecApplyResult{LeafHubName: ginCtx.Query("hub"), Result: ginCtx.Query("result")})
+		var rows []models.SpecApplyResult
+		if err := query.Order("leaf_hub_name, kind, namespace, name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the spec apply results: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		results := make([]applyResult, 0, len(rows))
+		for _, row := range rows {
+			results = append(results, applyResult{
+				Hub:        row.LeafHubName,
+				APIVersion: row.APIVersion,
+				Kind:       row.Kind,
+				Namespace:  row.Namespace,
+				Name:       row.Name,
+				Result:     row.Result,
+				Reason:     row.Reason,
+				AppliedAt:  row.AppliedAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, results)
+	}
+}
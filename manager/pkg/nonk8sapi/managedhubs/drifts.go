@@ -0,0 +1,67 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// specDrift is the global resource on the managed hub which drifts from the spec of the global hub
+type specDrift struct {
+	Hub        string    `json:"hub"`
+	Kind       string    `json:"kind"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Reason     string    `json:"reason,omitempty"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// ListSpecDrifts godoc
+// @summary list spec drifts
+// @description list the global resources on the managed hubs which drift from the spec distributed by the global hub
+// @accept json
+// @produce json
+// @param        hub     query    string    false    "filter the drifts by the managed hub"
+// @param        type    query    string    false    "filter the drifts by the type: missing, failed, conflicted or unexpected"
+// @success      200  {array}   specDrift
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /drifts [get]
+func ListSpecDrifts() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.SpecDrift{}).
+			Where(&models.SpecDrift{LeafHubName: ginCtx.Query("hub"), DriftType: ginCtx.Query("type")})
+		var rows []models.SpecDrift
+		if err := query.Order("leaf_hub_name, kind, namespace, name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the spec drifts: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		drifts := make([]specDrift, 0, len(rows))
+		for _, row := range rows {
+			drifts = append(drifts, specDrift{
+				Hub:        row.LeafHubName,
+				Kind:       row.Kind,
+				Namespace:  row.Namespace,
+				Name:       row.Name,
+				Type:       row.DriftType,
+				Reason:     row.Reason,
+				DetectedAt: row.DetectedAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, drifts)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// managedHub is the heartbeat status of the managed hub, the status is switched to inactive by the manager once the
+// hub doesn't send the heartbeat within the inactive timeout
+type managedHub struct {
+	Name          string    `json:"name"`
+	Status        string    `json:"status"`
+	LastHeartbeat time.Time `json:"lastHeartbeat"`
+}
+
+// ListManagedHubs godoc
+// @summary list managed hubs
+// @description list the managed hubs with their heartbeat status, the inactive hubs are listed first
+// @accept json
+// @produce json
+// @param        status    query    string    false    "filter the hubs by the status, active, inactive or detached"
+// @success      200  {array}   managedHub
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedhubs [get]
+func ListManagedHubs() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		status := ginCtx.Query("status")
+		if status != "" && status != "active" && status != "inactive" && status != "detached" {
+			ginCtx.String(http.StatusBadRequest,
+				fmt.Sprintf("invalid status %s, must be active, inactive or detached", status))
+			return
+		}
+
+		db := database.GetGorm()
+		query := db.Model(&models.LeafHubHeartbeat{})
+		if status != "" {
+			query = query.Where(&models.LeafHubHeartbeat{Status: status})
+		}
+		var heartbeats []models.LeafHubHeartbeat
+		if err := query.Order("status DESC, leaf_hub_name").Find(&heartbeats).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the managed hub heartbeats: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		hubs := make([]managedHub, 0, len(heartbeats))
+		for _, heartbeat := range heartbeats {
+			hubs = append(hubs, managedHub{
+				Name:          heartbeat.Name,
+				Status:        heartbeat.Status,
+				LastHeartbeat: heartbeat.LastUpdateAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, hubs)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// resourceConflict is the global resource modified on the managed hub and kept there by the "report" conflict policy
+type resourceConflict struct {
+	Hub           string    `json:"hub"`
+	APIVersion    string    `json:"apiVersion"`
+	Kind          string    `json:"kind"`
+	Namespace     string    `json:"namespace,omitempty"`
+	Name          string    `json:"name"`
+	Policy        string    `json:"policy"`
+	FieldManagers []string  `json:"fieldManagers"`
+	DetectedAt    time.Time `json:"detectedAt"`
+}
+
+// ListResourceConflicts godoc
+// @summary list resource conflicts
+// @description list the global resources modified on the managed hubs, and the field managers of the modifications
+// @accept json
+// @produce json
+// @param        hub     query    string    false    "filter the conflicts by the managed hub"
+// @param        kind    query    string    false    "filter the conflicts by the kind of the resource"
+// @success      200  {array}   resourceConflict
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /resourceconflicts [get]
+func ListResourceConflicts() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.ResourceConflict{}).
+			Where(&models.ResourceConflict{LeafHubName: ginCtx.Query("hub"), Kind: ginCtx.Query("kind")})
+		var rows []models.ResourceConflict
+		if err := query.Order("leaf_hub_name, kind, namespace, name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the resource conflicts: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		conflicts := make([]resourceConflict, 0, len(rows))
+		for _, row := range rows {
+			fieldManagers := []string{}
+			if len(row.FieldManagers) > 0 {
+				if err := json.Unmarshal(row.FieldManagers, &fieldManagers); err != nil {
+					fmt.Fprintf(gin.DefaultWriter, "failed to unmarshal the field managers: %v\n", err)
+				}
+			}
+			conflicts = append(conflicts, resourceConflict{
+				Hub:           row.LeafHubName,
+				APIVersion:    row.APIVersion,
+				Kind:          row.Kind,
+				Namespace:     row.Namespace,
+				Name:          row.Name,
+				Policy:        row.Policy,
+				FieldManagers: fieldManagers,
+				DetectedAt:    row.DetectedAt,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, conflicts)
+	}
+}
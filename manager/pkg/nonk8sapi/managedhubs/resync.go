@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedhubs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+type resyncRequest struct {
+	// EventTypes are the status event types to resync, all the event types are resynced if it's empty
+	EventTypes []string `json:"eventTypes"`
+}
+
+// ResyncManagedHub godoc
+// @summary resync managed hub
+// @description force the managed hub to resend its full status to the global hub
+// @accept json
+// @produce json
+// @param        hubName    path    string           true     "Managed Hub Name"
+// @param        resync     body    resyncRequest    false    "The status event types to resync"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedhub/{hubName}/resync [post]
+func ResyncManagedHub(producer transport.Producer) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		hubName := ginCtx.Param("hubName")
+		fmt.Fprintf(gin.DefaultWriter, "resync the managed hub: %s\n", hubName)
+
+		request := &resyncRequest{}
+		if ginCtx.Request.ContentLength > 0 {
+			if err := ginCtx.ShouldBindJSON(request); err != nil {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid resync request: %s", err.Error()))
+				return
+			}
+		}
+		if len(request.EventTypes) == 0 {
+			request.EventTypes = []string{constants.ResyncAllEventTypes}
+		}
+
+		db := database.GetGorm()
+		err := db.Where(&models.LeafHubHeartbeat{Name: hubName}).First(&models.LeafHubHeartbeat{}).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ginCtx.String(http.StatusNotFound, fmt.Sprintf("managed hub %s is not found", hubName))
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the managed hub heartbeat: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		payloadBytes, err := json.Marshal(request.EventTypes)
+		if err != nil {
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		evt := utils.ToCloudEvent(constants.ResyncMsgKey, hubName, payloadBytes)
+		if err := producer.SendEvent(ginCtx.Request.Context(), evt); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to send the resync event to %s: %v\n", hubName, err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, gin.H{
+			"hub":        hubName,
+			"eventTypes": request.EventTypes,
+		})
+	}
+}
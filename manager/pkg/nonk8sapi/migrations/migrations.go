@@ -0,0 +1,174 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+type migrationRequest struct {
+	ClusterName string `json:"clusterName" binding:"required"`
+	// FromHub is the source hub of the cluster, it's detected from the cluster name if it's empty
+	FromHub string `json:"fromHub"`
+	ToHub   string `json:"toHub" binding:"required"`
+}
+
+// managedClusterMigration is the migration of the managed cluster from the source hub to the target hub
+type managedClusterMigration struct {
+	ID          int64     `json:"id"`
+	ClusterName string    `json:"clusterName"`
+	FromHub     string    `json:"fromHub"`
+	ToHub       string    `json:"toHub"`
+	Phase       string    `json:"phase"`
+	Message     string    `json:"message,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CreateMigration godoc
+// @summary create migration
+// @description migrate the managed cluster from the source hub to the target hub, it runs in the background
+// @accept json
+// @produce json
+// @param        migration    body    migrationRequest    true    "The cluster and the target hub"
+// @success      201  {object}  managedClusterMigration
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      409
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /migrations [post]
+func CreateMigration() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		request := &migrationRequest{}
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid migration request: %s", err.Error()))
+			return
+		}
+
+		db := database.GetGorm()
+		if request.FromHub == "" {
+			var hubs []string
+			err := db.Model(&models.ManagedCluster{}).
+				Where("payload->'metadata'->>'name' = ?", request.ClusterName).Pluck("leaf_hub_name", &hubs).Error
+			if err != nil {
+				fmt.Fprintf(gin.DefaultWriter, "failed to get the hub of the cluster: %v\n", err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+			if len(hubs) == 0 {
+				ginCtx.String(http.StatusNotFound, fmt.Sprintf("managed cluster %s is not found", request.ClusterName))
+				return
+			}
+			if len(hubs) > 1 {
+				ginCtx.String(http.StatusBadRequest, fmt.Sprintf("managed cluster %s is found on the hubs %v, "+
+					"the fromHub is required", request.ClusterName, hubs))
+				return
+			}
+			request.FromHub = hubs[0]
+		}
+
+		err := migration.Validate(db, request.ClusterName, request.FromHub, request.ToHub)
+		if errors.Is(err, migration.ErrInvalidMigration) {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		} else if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to validate the migration: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		var inProgress int64
+		err = db.Model(&models.ManagedClusterMigration{}).
+			Where("cluster_name = ? AND phase NOT IN ?", request.ClusterName,
+				[]string{spec.MigrationCompleted, spec.MigrationFailed}).
+			Count(&inProgress).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to get the migrations of the cluster: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if inProgress > 0 {
+			ginCtx.String(http.StatusConflict, fmt.Sprintf("managed cluster %s is being migrated", request.ClusterName))
+			return
+		}
+
+		row := &models.ManagedClusterMigration{
+			ClusterName:    request.ClusterName,
+			FromHub:        request.FromHub,
+			ToHub:          request.ToHub,
+			Phase:          spec.MigrationPending,
+			PhaseStartedAt: time.Now(),
+		}
+		if err := db.Create(row).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to create the migration: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		fmt.Fprintf(gin.DefaultWriter, "migrate the managed cluster %s from %s to %s\n", row.ClusterName,
+			row.FromHub, row.ToHub)
+		ginCtx.JSON(http.StatusCreated, toMigration(row))
+	}
+}
+
+// ListMigrations godoc
+// @summary list migrations
+// @description list the migrations of the managed clusters between the managed hubs
+// @accept json
+// @produce json
+// @param        cluster    query    string    false    "filter the migrations by the managed cluster"
+// @param        phase      query    string    false    "filter the migrations by the phase, e.g. Completed or Failed"
+// @success      200  {array}   managedClusterMigration
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /migrations [get]
+func ListMigrations() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.ManagedClusterMigration{}).
+			Where(&models.ManagedClusterMigration{ClusterName: ginCtx.Query("cluster"), Phase: ginCtx.Query("phase")})
+		var rows []models.ManagedClusterMigration
+		if err := query.Order("id DESC").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the migrations: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		migrations := make([]managedClusterMigration, 0, len(rows))
+		for i := range rows {
+			migrations = append(migrations, toMigration(&rows[i]))
+		}
+		ginCtx.JSON(http.StatusOK, migrations)
+	}
+}
+
+func toMigration(row *models.ManagedClusterMigration) managedClusterMigration {
+	return managedClusterMigration{
+		ID:          row.ID,
+		ClusterName: row.ClusterName,
+		FromHub:     row.FromHub,
+		ToHub:       row.ToHub,
+		Phase:       row.Phase,
+		Message:     row.Message,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
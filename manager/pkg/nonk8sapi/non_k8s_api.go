@@ -9,16 +9,31 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/addons"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/clustergroups"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/consumergroups"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/fleet"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/gatekeeper"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/localpolicies"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/managedclusters"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/managedhubs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/migrations"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/policies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/reports"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/search"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/serviceaccounts"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/subscriptions"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 )
 
 const secondsToFinishOnShutdown = 5
@@ -29,6 +44,8 @@ type NonK8sAPIServerConfig struct {
 	ClusterAPIURL          string
 	ClusterAPICABundlePath string
 	ServerBasePath         string
+	// KafkaConfig is set with the kafka transport to serve the consumer group endpoints
+	KafkaConfig *transport.KafkaConfig
 }
 
 // NeedLeaderElection implements the LeaderElectionRunnable interface, which indicates
@@ -59,17 +76,44 @@ func readCertificateAuthority(nonK8sAPIServerConfig *NonK8sAPIServerConfig) ([]b
 }
 
 // AddNonK8sApiServer adds the non-k8s-api-server to the Manager.
-func AddNonK8sApiServer(mgr ctrl.Manager, nonK8sAPIServerConfig *NonK8sAPIServerConfig) error {
-	router, err := SetupRouter(nonK8sAPIServerConfig)
+func AddNonK8sApiServer(mgr ctrl.Manager, nonK8sAPIServerConfig *NonK8sAPIServerConfig,
+	producer transport.Producer,
+) error {
+	var groupAdmin consumergroups.AdminFunc
+	if nonK8sAPIServerConfig.KafkaConfig != nil {
+		configMap, err := config.GetConfluentConfigMap(nonK8sAPIServerConfig.KafkaConfig, false)
+		if err != nil {
+			return fmt.Errorf("failed to get the kafka config of the consumer groups: %w", err)
+		}
+		groupAdmin = consumergroups.NewAdminFunc(configMap)
+	}
+
+	router, err := SetupRouter(nonK8sAPIServerConfig, producer, groupAdmin)
 	if err != nil {
 		return err
 	}
 
+	log := ctrl.Log.WithName("non-k8s-api-server")
+	var handler http.Handler = router
+	// the bearer token of each request is authenticated by the TokenReview, and the user is authorized to the path of
+	// the request, e.g. "get" the nonResourceURL "/global-hub-api/v1/managedclusters", by the SubjectAccessReview as
+	// the secure metrics. It's skipped if the ClusterAPIURL is empty for testing.
+	if nonK8sAPIServerConfig.ClusterAPIURL != "" {
+		filter, err := filters.WithAuthenticationAndAuthorization(mgr.GetConfig(), mgr.GetHTTPClient())
+		if err != nil {
+			return fmt.Errorf("failed to create the authentication filter of the non k8s api server: %w", err)
+		}
+		if handler, err = filter(log, router); err != nil {
+			return fmt.Errorf("failed to filter the non k8s api server: %w", err)
+		}
+		handler = withForwardedAccessToken(handler)
+	}
+
 	err = mgr.Add(&nonK8sApiServer{
-		log: ctrl.Log.WithName("non-k8s-api-server"),
+		log: log,
 		svr: &http.Server{
 			Addr:              ":8080",
-			Handler:           router,
+			Handler:           handler,
 			ReadHeaderTimeout: time.Minute * 1,
 		},
 	})
@@ -97,7 +141,9 @@ func AddNonK8sApiServer(mgr ctrl.Manager, nonK8sAPIServerConfig *NonK8sAPIServer
 // @in                          header
 // @name                        Authorization
 // @description					Authorization with user access token
-func SetupRouter(nonK8sAPIServerConfig *NonK8sAPIServerConfig) (*gin.Engine, error) {
+func SetupRouter(nonK8sAPIServerConfig *NonK8sAPIServerConfig, producer transport.Producer,
+	groupAdmin consumergroups.AdminFunc,
+) (*gin.Engine, error) {
 	router := gin.Default()
 	// add aythentication eith openshift oauth
 	// skip authentication middleware if ClusterAPIURL is empty for testing
@@ -115,8 +161,42 @@ func SetupRouter(nonK8sAPIServerConfig *NonK8sAPIServerConfig) (*gin.Engine, err
 		managedclusters.PatchManagedCluster())
 	routerGroup.GET("/policies", policies.ListPolicies())
 	routerGroup.GET("/policy/:policyID/status", policies.GetPolicyStatus())
+	routerGroup.GET("/policytemplates", policies.ListTemplateDetails())
+	routerGroup.GET("/localpolicy/:policyID/compliancehistory", localpolicies.GetComplianceHistory())
+	routerGroup.GET("/localpolicy/:policyID/flaps", localpolicies.GetComplianceFlaps())
+	routerGroup.GET("/compliancereport", reports.GetComplianceReport())
 	routerGroup.GET("/subscriptions", subscriptions.ListSubscriptions())
 	routerGroup.GET("/subscriptionreport/:subscriptionID", subscriptions.GetSubscriptionReport())
+	routerGroup.GET("/gatekeeper/constraints", gatekeeper.ListConstraints())
+	routerGroup.GET("/gatekeeper/violations", gatekeeper.ListViolations())
+	routerGroup.GET("/addons", addons.ListAddons())
+	routerGroup.GET("/addons/clusters", addons.ListClusterAddons())
+	routerGroup.GET("/managedserviceaccounts", serviceaccounts.ListManagedServiceAccounts())
+	routerGroup.GET("/managedhubs", managedhubs.ListManagedHubs())
+	routerGroup.GET("/agents", managedhubs.ListAgents())
+	routerGroup.GET("/agents/versions", managedhubs.ListAgentVersions())
+	routerGroup.GET("/applyresults", managedhubs.ListApplyResults())
+	routerGroup.GET("/resourceconflicts", managedhubs.ListResourceConflicts())
+	routerGroup.GET("/drifts", managedhubs.ListSpecDrifts())
+	routerGroup.POST("/managedhub/:hubName/resync", managedhubs.ResyncManagedHub(producer))
+	routerGroup.GET("/fleet/summary", fleet.GetFleetSummary())
+	routerGroup.GET("/fleet/hubs", fleet.ListHubSummaries())
+	routerGroup.GET("/fleet/freshness", fleet.ListDataFreshness())
+	routerGroup.GET("/migrations", migrations.ListMigrations())
+	routerGroup.POST("/migrations", migrations.CreateMigration())
+	routerGroup.GET("/clustergroups", clustergroups.ListClusterGroups())
+	routerGroup.POST("/clustergroups", clustergroups.CreateClusterGroup())
+	routerGroup.GET("/clustergroup/:name", clustergroups.GetClusterGroup())
+	routerGroup.PUT("/clustergroup/:name", clustergroups.UpdateClusterGroup())
+	routerGroup.DELETE("/clustergroup/:name", clustergroups.DeleteClusterGroup())
+	routerGroup.POST("/search", search.Search())
+
+	// the consumer groups are only served with the kafka transport
+	if groupAdmin != nil {
+		routerGroup.GET("/consumergroups", consumergroups.ListConsumerGroups(groupAdmin))
+		routerGroup.GET("/consumergroup/:groupID", consumergroups.GetConsumerGroup(groupAdmin))
+		routerGroup.POST("/consumergroup/:groupID/offsets", consumergroups.ResetConsumerGroupOffsets(groupAdmin))
+	}
 
 	return router, nil
 }
@@ -148,3 +228,16 @@ func (s *nonK8sApiServer) Start(ctx context.Context) error {
 	<-idleConnsClosed
 	return nil
 }
+
+// withForwardedAccessToken authenticates the requests proxied by the oauth-proxy with the access token of the session
+// if they don't have the bearer token, which is the same as the authentication middleware
+func withForwardedAccessToken(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+			if token := req.Header.Get("X-Forwarded-Access-Token"); token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		handler.ServeHTTP(w, req)
+	})
+}
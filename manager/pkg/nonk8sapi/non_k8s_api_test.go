@@ -12,6 +12,7 @@ import (
 	"net/http/httptest"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/nonk8sapi/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -50,6 +52,7 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 	var plc1ID string
 	var sub1ID string
 	var sub2ID string
+	producer := &testProducer{}
 
 	BeforeAll(func() {
 		var err error
@@ -68,7 +71,7 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 		router, err = nonk8sapi.SetupRouter(&nonk8sapi.NonK8sAPIServerConfig{
 			ServerBasePath: "/global-hub-api/v1",
 			ClusterAPIURL:  testAuthServer.URL,
-		})
+		}, producer, nil)
 		Expect(err).NotTo(HaveOccurred())
 	})
 
@@ -1442,7 +1445,695 @@ var _ = Describe("Nonk8s API Server", Ordered, func() {
 		Expect(w1.Body.String()).Should(MatchJSON(subscriptionReportStr))
 	})
 
+	It("Should be able to resync the managed hub", func() {
+		By("Check the unknown hub can't be resynced")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("POST", "/global-hub-api/v1/managedhub/unknown-hub/resync", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(404))
+
+		By("Check the resync event is sent to the hub")
+		err = db.Exec(`INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name) VALUES ('resync-hub')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("POST", "/global-hub-api/v1/managedhub/resync-hub/resync",
+			bytes.NewBufferString(`{"eventTypes": ["io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster"]}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+
+		Expect(producer.events).To(HaveLen(1))
+		evt := producer.events[0]
+		Expect(evt.Type()).To(Equal(constants.ResyncMsgKey))
+		Expect(evt.Source()).To(Equal("resync-hub"))
+		Expect(string(evt.Data())).To(MatchJSON(
+			`["io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster"]`))
+
+		By("Check all the event types are resynced without request body")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("POST", "/global-hub-api/v1/managedhub/resync-hub/resync", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(200))
+		Expect(producer.events).To(HaveLen(2))
+		Expect(string(producer.events[1].Data())).To(MatchJSON(`["*"]`))
+	})
+
+	It("Should be able to list the managed hubs", func() {
+		err := db.Exec(`INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name, status, last_timestamp) VALUES
+			('active-hub', 'active', '2024-01-01 00:00:00'), ('inactive-hub', 'inactive', '2024-01-01 00:00:00')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the inactive hubs are listed first")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/managedhubs", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		hubs := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &hubs)).To(Succeed())
+		Expect(len(hubs)).To(BeNumerically(">=", 2))
+		Expect(hubs[0]["name"]).To(Equal("inactive-hub"))
+		Expect(hubs[0]["status"]).To(Equal("inactive"))
+
+		By("Check the hubs are filtered by the status")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET", "/global-hub-api/v1/managedhubs?status=inactive", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		hubs = []map[string]interface{}{}
+		Expect(json.Unmarshal(w1.Body.Bytes(), &hubs)).To(Succeed())
+		Expect(hubs).To(HaveLen(1))
+		Expect(hubs[0]["name"]).To(Equal("inactive-hub"))
+
+		By("Check the invalid status is rejected")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("GET", "/global-hub-api/v1/managedhubs?status=unknown", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(400))
+	})
+
+	It("Should be able to get the compliance history of the local policy", func() {
+		policyID := uuid.New().String()
+		cluster1ID := uuid.New().String()
+		cluster2ID := uuid.New().String()
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		twoDaysAgo := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+
+		err := db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('hub1', ?, '{"metadata": {"name": "cluster1"}}', 'none'),
+			('hub1', ?, '{"metadata": {"name": "cluster2"}}', 'none')`, cluster1ID, cluster2ID).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO history.local_compliance (policy_id, cluster_id, leaf_hub_name, compliance_date,
+			compliance, compliance_changed_frequency) VALUES
+			(?, ?, 'hub1', ?, 'compliant', 0), (?, ?, 'hub1', ?, 'non_compliant', 3),
+			(?, ?, 'hub1', ?, 'compliant', 1)`,
+			policyID, cluster1ID, twoDaysAgo, policyID, cluster1ID, yesterday, policyID, cluster2ID, yesterday).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the timelines of the clusters")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET",
+			fmt.Sprintf("/global-hub-api/v1/localpolicy/%s/compliancehistory", policyID), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		Expect(w0.Body.String()).Should(MatchJSON(fmt.Sprintf(`[
+			{"leafHubName": "hub1", "clusterID": "%s", "clusterName": "cluster1", "history": [
+				{"date": "%s", "compliance": "compliant", "changedFrequency": 0},
+				{"date": "%s", "compliance": "non_compliant", "changedFrequency": 3}]},
+			{"leafHubName": "hub1", "clusterID": "%s", "clusterName": "cluster2", "history": [
+				{"date": "%s", "compliance": "compliant", "changedFrequency": 1}]}
+		]`, cluster1ID, twoDaysAgo, yesterday, cluster2ID, yesterday)))
+
+		By("Check the timeline is filtered by the cluster and the date")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET", fmt.Sprintf(
+			"/global-hub-api/v1/localpolicy/%s/compliancehistory?cluster=cluster1&start=%s", policyID, yesterday), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		timelines := []map[string]interface{}{}
+		Expect(json.Unmarshal(w1.Body.Bytes(), &timelines)).To(Succeed())
+		Expect(timelines).To(HaveLen(1))
+		Expect(timelines[0]["clusterName"]).To(Equal("cluster1"))
+		Expect(timelines[0]["history"]).To(HaveLen(1))
+
+		By("Check the clusters are ordered by the flap count")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("GET", fmt.Sprintf("/global-hub-api/v1/localpolicy/%s/flaps", policyID), nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(200))
+		Expect(w2.Body.String()).Should(MatchJSON(fmt.Sprintf(`[
+			{"leafHubName": "hub1", "clusterID": "%s", "clusterName": "cluster1",
+				"flapCount": 3, "nonCompliantDays": 1, "days": 2},
+			{"leafHubName": "hub1", "clusterID": "%s", "clusterName": "cluster2",
+				"flapCount": 1, "nonCompliantDays": 0, "days": 1}
+		]`, cluster1ID, cluster2ID)))
+
+		By("Check the invalid arguments are rejected")
+		for _, url := range []string{
+			"/global-hub-api/v1/localpolicy/invalid-id/flaps",
+			fmt.Sprintf("/global-hub-api/v1/localpolicy/%s/flaps?limit=0", policyID),
+			fmt.Sprintf("/global-hub-api/v1/localpolicy/%s/compliancehistory?start=2024/01/01", policyID),
+			fmt.Sprintf("/global-hub-api/v1/localpolicy/%s/compliancehistory?start=%s&end=%s",
+				policyID, yesterday, twoDaysAgo),
+		} {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", url, nil)
+			Expect(err).ToNot(HaveOccurred())
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(400), url)
+		}
+	})
+
+	It("Should be able to get the compliance report", func() {
+		policy1ID := uuid.New().String()
+		policy2ID := uuid.New().String()
+		cluster1ID := uuid.New().String()
+		cluster2ID := uuid.New().String()
+
+		err := db.Exec(`INSERT INTO local_spec.policies (leaf_hub_name, policy_id, payload) VALUES
+			('report-hub1', ?, '{"metadata": {"name": "policy1", "annotations":
+				{"policy.open-cluster-management.io/standards": "NIST SP 800-53,NIST-CSF"}}}'),
+			('report-hub1', ?, '{"metadata": {"name": "policy2"}}')`, policy1ID, policy2ID).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('report-hub1', ?, '{"metadata": {"name": "report-cluster1"}}', 'none'),
+			('report-hub1', ?, '{"metadata": {"name": "report-cluster2"}}', 'none')`, cluster1ID, cluster2ID).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO history.local_compliance (policy_id, cluster_id, leaf_hub_name, compliance_date,
+			compliance) VALUES
+			(?, ?, 'report-hub1', '2023-03-01', 'compliant'), (?, ?, 'report-hub1', '2023-03-02', 'non_compliant'),
+			(?, ?, 'report-hub1', '2023-03-01', 'compliant'), (?, ?, 'report-hub1', '2023-03-01', 'pending')`,
+			policy1ID, cluster1ID, policy1ID, cluster1ID, policy1ID, cluster2ID, policy2ID, cluster2ID).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the report is grouped by the standard")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET",
+			"/global-hub-api/v1/compliancereport?start=2023-03-01&end=2023-03-31", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		report := map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &report)).To(Succeed())
+		Expect(report["groupBy"]).To(Equal("standard"))
+		Expect(report["rows"]).Should(ConsistOf(
+			And(HaveKeyWithValue("name", "NIST SP 800-53"), HaveKeyWithValue("compliant", BeNumerically("==", 2)),
+				HaveKeyWithValue("nonCompliant", BeNumerically("==", 1))),
+			And(HaveKeyWithValue("name", "NIST-CSF"), HaveKeyWithValue("complianceRate", BeNumerically("==", 66.66))),
+			And(HaveKeyWithValue("name", "unspecified"), HaveKeyWithValue("unknown", BeNumerically("==", 1))),
+		))
+
+		By("Check the report of the clusters is exported as csv")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET",
+			"/global-hub-api/v1/compliancereport?start=2023-03-01&end=2023-03-01&groupBy=cluster&format=csv", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		Expect(w1.Header().Get("Content-Type")).To(Equal("text/csv"))
+		Expect(w1.Body.String()).To(Equal(
+			"hub,cluster,policies,clusters,compliant,non_compliant,unknown,compliance_rate\n" +
+				"report-hub1,report-cluster1,1,1,1,0,0,100.00\n" +
+				"report-hub1,report-cluster2,2,1,1,0,1,50.00\n"))
+
+		By("Check the report is exported as pdf")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("GET",
+			"/global-hub-api/v1/compliancereport?start=2023-03-01&end=2023-03-31&groupBy=hub&format=pdf", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(200))
+		Expect(w2.Header().Get("Content-Type")).To(Equal("application/pdf"))
+		Expect(w2.Body.String()).To(HavePrefix("%PDF-1.4"))
+
+		By("Check the invalid arguments are rejected")
+		for _, url := range []string{
+			"/global-hub-api/v1/compliancereport?groupBy=policy",
+			"/global-hub-api/v1/compliancereport?format=xlsx",
+			"/global-hub-api/v1/compliancereport?start=2023-03-31&end=2023-03-01",
+		} {
+			w := httptest.NewRecorder()
+			req, err := http.NewRequest("GET", url, nil)
+			Expect(err).ToNot(HaveOccurred())
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(400), url)
+		}
+	})
+
+	It("Should be able to list the agents", func() {
+		err := db.Exec(`INSERT INTO status.agent_health (leaf_hub_name, agent_version, collectors, last_sync_times,
+			resource_counts, reported_at) VALUES
+			('agent-hub1', 'v1.1.0', '["status.hub_cluster_heartbeat"]', '{}', '{"managedclusters": 2}', now()),
+			('agent-hub2', 'v1.2.0', '["status.hub_cluster_heartbeat"]', NULL, NULL, now())`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the agents are filtered by the version")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/agents?version=v1.1.0", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		agents := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &agents)).To(Succeed())
+		Expect(agents).To(HaveLen(1))
+		Expect(agents[0]["name"]).To(Equal("agent-hub1"))
+		Expect(agents[0]["resourceCounts"]).To(HaveKeyWithValue("managedclusters", BeNumerically("==", 2)))
+	})
+
+	It("Should be able to list the agent versions", func() {
+		err := db.Exec(`INSERT INTO status.agent_versions (leaf_hub_name, agent_version, manager_version,
+			compatibility, reason, quarantined) VALUES
+			('version-hub1', 'v1.1.0', 'v1.3.0', 'incompatible', 'the agent falls behind the manager', true),
+			('version-hub2', 'v1.3.0', 'v1.3.0', 'compatible', NULL, false)`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the agents are filtered by the compatibility")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/agents/versions?compatibility=incompatible", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		versions := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &versions)).To(Succeed())
+		Expect(versions).To(HaveLen(1))
+		Expect(versions[0]["name"]).To(Equal("version-hub1"))
+		Expect(versions[0]["quarantined"]).To(BeTrue())
+	})
+
+	It("Should be able to list the addons on the managed clusters", func() {
+		err := db.Exec(`INSERT INTO status.managed_cluster_addons (leaf_hub_name, cluster_name, addon_name, status,
+			reason, message) VALUES
+			('addon-hub1', 'cluster1', 'observability-controller', 'degraded', 'ManagedClusterAddOnLeaseUpdateStopped',
+			'the lease is not updated'),
+			('addon-hub1', 'cluster2', 'observability-controller', 'available', '', ''),
+			('addon-hub1', 'cluster1', 'work-manager', 'available', '', ''),
+			('addon-hub2', 'cluster3', 'observability-controller', 'degraded', '', '')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the addons degraded on more clusters are listed first")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/addons", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		summaries := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &summaries)).To(Succeed())
+		Expect(summaries).To(HaveLen(2))
+		Expect(summaries[0]["name"]).To(Equal("observability-controller"))
+		Expect(summaries[0]["total"]).To(BeNumerically("==", 3))
+		Expect(summaries[0]["degraded"]).To(BeNumerically("==", 2))
+
+		By("Check the clusters are filtered by the addon and the status")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET",
+			"/global-hub-api/v1/addons/clusters?hub=addon-hub1&addon=observability-controller&status=degraded", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		addons := []map[string]interface{}{}
+		Expect(json.Unmarshal(w1.Body.Bytes(), &addons)).To(Succeed())
+		Expect(addons).To(HaveLen(1))
+		Expect(addons[0]["clusterName"]).To(Equal("cluster1"))
+		Expect(addons[0]["reason"]).To(Equal("ManagedClusterAddOnLeaseUpdateStopped"))
+	})
+
+	It("Should be able to list the managed service accounts", func() {
+		err := db.Exec(`INSERT INTO status.managed_service_accounts (leaf_hub_name, cluster_name, name, status,
+			token_secret_name, expiration_time) VALUES
+			('msa-hub1', 'cluster1', 'automation', 'ready', 'automation', now() + interval '2 hours'),
+			('msa-hub1', 'cluster2', 'automation', 'ready', 'automation', now() + interval '30 days'),
+			('msa-hub2', 'cluster3', 'automation', 'failed', '', NULL)`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the accounts are filtered by the expiration")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/managedserviceaccounts?name=automation&expiresWithin=24h",
+			nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		accounts := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &accounts)).To(Succeed())
+		Expect(accounts).To(HaveLen(1))
+		Expect(accounts[0]["leafHubName"]).To(Equal("msa-hub1"))
+		Expect(accounts[0]["clusterName"]).To(Equal("cluster1"))
+		Expect(accounts[0]["tokenSecretName"]).To(Equal("automation"))
+
+		By("Check the accounts are filtered by the status")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET", "/global-hub-api/v1/managedserviceaccounts?status=failed", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		Expect(json.Unmarshal(w1.Body.Bytes(), &accounts)).To(Succeed())
+		Expect(accounts).To(HaveLen(1))
+		Expect(accounts[0]["leafHubName"]).To(Equal("msa-hub2"))
+
+		By("Check the invalid duration is rejected")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("GET", "/global-hub-api/v1/managedserviceaccounts?expiresWithin=1day", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(400))
+	})
+
+	It("Should be able to list the spec apply results", func() {
+		err := db.Exec(`INSERT INTO status.spec_apply_results (leaf_hub_name, api_version, kind, namespace, name,
+			result, reason) VALUES
+			('apply-hub1', 'policy.open-cluster-management.io/v1', 'Policy', 'default', 'policy1', 'applied', ''),
+			('apply-hub1', 'policy.open-cluster-management.io/v1', 'Policy', 'default', 'policy2', 'failed', 'denied'),
+			('apply-hub2', 'policy.open-cluster-management.io/v1', 'Policy', 'default', 'policy2', 'applied', '')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the results are filtered by the hub and the result")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/applyresults?hub=apply-hub1&result=failed", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		results := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &results)).To(Succeed())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0]["name"]).To(Equal("policy2"))
+		Expect(results[0]["reason"]).To(Equal("denied"))
+	})
+
+	It("Should be able to list the resource conflicts", func() {
+		err := db.Exec(`INSERT INTO status.resource_conflicts (leaf_hub_name, api_version, kind, namespace, name,
+			policy, field_managers) VALUES
+			('conflict-hub1', 'policy.open-cluster-management.io/v1', 'Policy', 'default', 'policy1', 'report',
+			'["kubectl-edit"]'),
+			('conflict-hub1', 'apps.open-cluster-management.io/v1', 'Subscription', 'default', 'sub1', 'report',
+			'["kubectl-client-side-apply"]'),
+			('conflict-hub2', 'policy.open-cluster-management.io/v1', 'Policy', 'default', 'policy1', 'report',
+			'["kubectl-edit"]')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the conflicts are filtered by the hub and the kind")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/resourceconflicts?hub=conflict-hub1&kind=Policy", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		conflicts := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &conflicts)).To(Succeed())
+		Expect(conflicts).To(HaveLen(1))
+		Expect(conflicts[0]["name"]).To(Equal("policy1"))
+		Expect(conflicts[0]["fieldManagers"]).To(Equal([]interface{}{"kubectl-edit"}))
+	})
+
+	It("Should be able to list the spec drifts", func() {
+		err := db.Exec(`INSERT INTO status.spec_drifts (leaf_hub_name, kind, namespace, name, drift_type, reason)
+			VALUES
+			('drift-hub1', 'Policy', 'default', 'policy1', 'missing', ''),
+			('drift-hub1', 'Policy', 'default', 'policy2', 'failed', 'denied'),
+			('drift-hub2', 'Policy', 'default', 'policy2', 'missing', '')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the drifts are filtered by the hub and the type")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/drifts?hub=drift-hub1&type=failed", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		drifts := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &drifts)).To(Succeed())
+		Expect(drifts).To(HaveLen(1))
+		Expect(drifts[0]["name"]).To(Equal("policy2"))
+		Expect(drifts[0]["reason"]).To(Equal("denied"))
+	})
+
+	It("Should be able to manage the cluster groups", func() {
+		err := db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('group-hub1', ?, '{"metadata": {"name": "cluster1", "labels": {"env": "prod"}}}', 'none'),
+			('group-hub1', ?, '{"metadata": {"name": "cluster2", "labels": {"env": "dev"}}}', 'none'),
+			('group-hub2', ?, '{"metadata": {"name": "cluster3"}}', 'none')`,
+			uuid.New().String(), uuid.New().String(), uuid.New().String()).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Create the group by the label selector and the explicit cluster")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("POST", "/global-hub-api/v1/clustergroups", bytes.NewBufferString(
+			`{"name": "prod", "labelSelector": "env=prod", "clusters": ["group-hub2/cluster3"]}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(201))
+		group := map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &group)).To(Succeed())
+		Expect(group["members"]).To(BeNumerically("==", 2))
+		Expect(group["hubs"]).To(ConsistOf("group-hub1", "group-hub2"))
+
+		By("Check the existing group can't be created again")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("POST", "/global-hub-api/v1/clustergroups",
+			bytes.NewBufferString(`{"name": "prod", "labelSelector": "env=dev"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(409))
+
+		By("Check the managed clusters are filtered by the group")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("GET", "/global-hub-api/v1/managedclusters?group=prod", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(200))
+		Expect(w2.Body.String()).To(ContainSubstring("cluster1"))
+		Expect(w2.Body.String()).To(ContainSubstring("cluster3"))
+		Expect(w2.Body.String()).NotTo(ContainSubstring("cluster2"))
+
+		By("Delete the group")
+		w3 := httptest.NewRecorder()
+		req3, err := http.NewRequest("DELETE", "/global-hub-api/v1/clustergroup/prod", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w3, req3)
+		Expect(w3.Code).To(Equal(204))
+		var count int64
+		Expect(db.Model(&models.ClusterGroupMember{}).Where("group_name = ?", "prod").Count(&count).Error).To(Succeed())
+		Expect(count).To(BeZero())
+	})
+
+	It("Should be able to search the resources across the managed hubs", func() {
+		err := db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('search-hub1', ?, '{"metadata": {"name": "search-cluster1", "labels": {"env": "prod"}}}', 'none'),
+			('search-hub2', ?, '{"metadata": {"name": "search-cluster2", "labels": {"env": "dev"}}}', 'none')`,
+			uuid.New().String(), uuid.New().String()).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO local_spec.policies (policy_id, leaf_hub_name, payload) VALUES
+			(?, 'search-hub1', '{"metadata": {"name": "search-policy1", "namespace": "default"},
+				"status": {"compliant": "NonCompliant"}}'),
+			(?, 'search-hub2', '{"metadata": {"name": "search-policy2", "namespace": "default"},
+				"status": {"compliant": "Compliant"}}')`, uuid.New().String(), uuid.New().String()).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Search the non-compliant policies and the production clusters of the hubs")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("POST", "/global-hub-api/v1/search", bytes.NewBufferString(`{
+			"operationName": "searchResultItems",
+			"variables": {"input": [
+				{"filters": [{"property": "kind", "values": ["Policy"]}, {"property": "compliant", "values": ["!Compliant"]},
+					{"property": "hub", "values": ["search-hub*"]}]},
+				{"keywords": ["search-cluster"], "filters": [{"property": "label", "values": ["env=prod"]}]}
+			]},
+			"query": "query searchResultItems($input: [SearchInput]) { searchResult: search(input: $input) { items } }"
+		}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		response := struct {
+			Data struct {
+				SearchResult []struct {
+					Count int                      `json:"count"`
+					Items []map[string]interface{} `json:"items"`
+				} `json:"searchResult"`
+			} `json:"data"`
+		}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Data.SearchResult).To(HaveLen(2))
+		Expect(response.Data.SearchResult[0].Count).To(Equal(1))
+		Expect(response.Data.SearchResult[0].Items[0]["name"]).To(Equal("search-policy1"))
+		Expect(response.Data.SearchResult[0].Items[0]["cluster"]).To(Equal("search-hub1"))
+		Expect(response.Data.SearchResult[1].Count).To(Equal(1))
+		Expect(response.Data.SearchResult[1].Items[0]["kind"]).To(Equal("Cluster"))
+		Expect(response.Data.SearchResult[1].Items[0]["cluster"]).To(Equal("search-cluster1"))
+		Expect(response.Data.SearchResult[1].Items[0]["hub"]).To(Equal("search-hub1"))
+
+		By("Check the other queries of the search-v2 API are rejected")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("POST", "/global-hub-api/v1/search",
+			bytes.NewBufferString(`{"query": "query searchSchema { searchSchema }"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(400))
+	})
+
+	It("Should be able to list the gatekeeper constraints and violations", func() {
+		err := db.Exec(`INSERT INTO status.gatekeeper_constraints (leaf_hub_name, cluster_name, constraint_kind,
+			constraint_name, enforcement_action, total_violations) VALUES
+			('hub1', 'hub1', 'K8sAllowedRepos', 'repo-is-quay', 'dryrun', 0),
+			('hub1', 'hub1', 'K8sRequiredLabels', 'ns-must-have-owner', 'deny', 2)`).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO status.gatekeeper_violations (leaf_hub_name, cluster_name, constraint_kind,
+			constraint_name, resource_kind, resource_namespace, resource_name, message, enforcement_action) VALUES
+			('hub1', 'hub1', 'K8sRequiredLabels', 'ns-must-have-owner', 'Namespace', '', 'default', 'no owner', 'deny'),
+			('hub1', 'hub1', 'K8sRequiredLabels', 'ns-must-have-owner', 'Namespace', '', 'test', 'no owner', 'deny')`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the constraints with more violations are listed first")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/gatekeeper/constraints?hub=hub1", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		constraints := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &constraints)).To(Succeed())
+		Expect(constraints).To(HaveLen(2))
+		Expect(constraints[0]["name"]).To(Equal("ns-must-have-owner"))
+		Expect(constraints[0]["totalViolations"]).To(BeNumerically("==", 2))
+
+		By("Check the violations are filtered by the constraint")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET",
+			"/global-hub-api/v1/gatekeeper/violations?kind=K8sRequiredLabels&constraint=ns-must-have-owner", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		violations := []map[string]interface{}{}
+		Expect(json.Unmarshal(w1.Body.Bytes(), &violations)).To(Succeed())
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0]["name"]).To(Equal("default"))
+		Expect(violations[0]["kind"]).To(Equal("Namespace"))
+	})
+
+	It("Should be able to migrate the managed cluster", func() {
+		err := db.Exec(`INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name, status, last_timestamp) VALUES
+			('migration-hub1', 'active', now()), ('migration-hub2', 'active', now())`).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('migration-hub1', ?, '{"metadata": {"name": "migration-cluster1"}}', 'none')`, uuid.New().String()).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the migration is created with the source hub of the cluster")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("POST", "/global-hub-api/v1/migrations",
+			bytes.NewBufferString(`{"clusterName": "migration-cluster1", "toHub": "migration-hub2"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(201))
+		created := map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &created)).To(Succeed())
+		Expect(created["fromHub"]).To(Equal("migration-hub1"))
+		Expect(created["phase"]).To(Equal("Pending"))
+
+		By("Check the cluster can't be migrated again until the migration is finished")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("POST", "/global-hub-api/v1/migrations",
+			bytes.NewBufferString(`{"clusterName": "migration-cluster1", "toHub": "migration-hub2"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(409))
+
+		By("Check the cluster can't be migrated to the hub it's on")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("POST", "/global-hub-api/v1/migrations",
+			bytes.NewBufferString(`{"clusterName": "migration-cluster1", "fromHub": "migration-hub1", "toHub": "migration-hub1"}`))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(400))
+
+		By("Check the migrations are listed by the cluster")
+		w3 := httptest.NewRecorder()
+		req3, err := http.NewRequest("GET", "/global-hub-api/v1/migrations?cluster=migration-cluster1", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w3, req3)
+		Expect(w3.Code).To(Equal(200))
+		migrations := []map[string]interface{}{}
+		Expect(json.Unmarshal(w3.Body.Bytes(), &migrations)).To(Succeed())
+		Expect(migrations).To(HaveLen(1))
+		Expect(migrations[0]["toHub"]).To(Equal("migration-hub2"))
+	})
+
+	It("Should be able to get the fleet summary", func() {
+		err := db.Exec(`INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name, status, last_timestamp) VALUES
+			('fleet-hub1', 'inactive', now())`).Error
+		Expect(err).ToNot(HaveOccurred())
+		err = db.Exec(`INSERT INTO status.managed_clusters (leaf_hub_name, cluster_id, payload, error) VALUES
+			('fleet-hub1', ?, '{"metadata": {"name": "fleet-cluster1"}, "status": {"conditions": [
+				{"type": "ManagedClusterConditionAvailable", "status": "True"}]}}', 'none'),
+			('fleet-hub1', ?, '{"metadata": {"name": "fleet-cluster2"}, "status": {"conditions": [
+				{"type": "ManagedClusterConditionAvailable", "status": "Unknown"}]}}', 'none')`,
+			uuid.New().String(), uuid.New().String()).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check the fleet is summarized")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/fleet/summary", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		summary := map[string]map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &summary)).To(Succeed())
+		Expect(summary["hubs"]["inactive"]).To(BeNumerically(">=", 1))
+		Expect(summary["clusters"]["total"]).To(BeNumerically(">=", 2))
+		Expect(summary["clusters"]["available"]).To(BeNumerically(">=", 1))
+
+		By("Check the clusters of the hub are counted")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET", "/global-hub-api/v1/fleet/hubs?status=inactive", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		hubs := []map[string]interface{}{}
+		Expect(json.Unmarshal(w1.Body.Bytes(), &hubs)).To(Succeed())
+		var fleetHub map[string]interface{}
+		for _, hub := range hubs {
+			if hub["name"] == "fleet-hub1" {
+				fleetHub = hub
+			}
+		}
+		Expect(fleetHub).NotTo(BeNil())
+		Expect(fleetHub["clusters"]).To(BeNumerically("==", 2))
+		Expect(fleetHub["availableClusters"]).To(BeNumerically("==", 1))
+	})
+
+	It("Should be able to list the data freshness", func() {
+		err := db.Exec(`INSERT INTO status.data_freshness (leaf_hub_name, bundle_type, last_received_at,
+			expected_interval_seconds) VALUES
+			('freshness-hub1', 'managedhub.heartbeat', now() - interval '10 minutes', 60),
+			('freshness-hub1', 'managedcluster', now() - interval '10 minutes', NULL),
+			('freshness-hub2', 'managedhub.heartbeat', now(), 60)`).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Check only the stale heartbeat is listed")
+		w0 := httptest.NewRecorder()
+		req0, err := http.NewRequest("GET", "/global-hub-api/v1/fleet/freshness?stale=true", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w0, req0)
+		Expect(w0.Code).To(Equal(200))
+		stale := []map[string]interface{}{}
+		Expect(json.Unmarshal(w0.Body.Bytes(), &stale)).To(Succeed())
+		Expect(stale).To(HaveLen(1))
+		Expect(stale[0]["hub"]).To(Equal("freshness-hub1"))
+		Expect(stale[0]["bundleType"]).To(Equal("managedhub.heartbeat"))
+		Expect(stale[0]["lagSeconds"]).To(BeNumerically(">=", 500))
+
+		By("Check the bundles of the hub are listed")
+		w1 := httptest.NewRecorder()
+		req1, err := http.NewRequest("GET", "/global-hub-api/v1/fleet/freshness?hub=freshness-hub1", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w1, req1)
+		Expect(w1.Code).To(Equal(200))
+		freshness := []map[string]interface{}{}
+		Expect(json.Unmarshal(w1.Body.Bytes(), &freshness)).To(Succeed())
+		Expect(freshness).To(HaveLen(2))
+		Expect(freshness[0]["bundleType"]).To(Equal("managedcluster"))
+		Expect(freshness[0]["stale"]).To(BeFalse())
+
+		By("Check the invalid stale is rejected")
+		w2 := httptest.NewRecorder()
+		req2, err := http.NewRequest("GET", "/global-hub-api/v1/fleet/freshness?stale=maybe", nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w2, req2)
+		Expect(w2.Code).To(Equal(400))
+	})
+
 	AfterAll(func() {
 		database.CloseGorm(database.GetSqlDb())
 	})
 })
+
+type testProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *testProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package policies
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// templateDetail is the latest result of the policy template on the cluster
+type templateDetail struct {
+	LeafHubName         string                      `json:"leafHubName"`
+	PolicyID            string                      `json:"policyID"`
+	PolicyNamespace     string                      `json:"policyNamespace"`
+	PolicyName          string                      `json:"policyName"`
+	ClusterName         string                      `json:"clusterName"`
+	TemplateKind        string                      `json:"templateKind"`
+	TemplateName        string                      `json:"templateName"`
+	Compliance          string                      `json:"compliance"`
+	Message             string                      `json:"message"`
+	LastTimestamp       *time.Time                  `json:"lastTimestamp,omitempty"`
+	TotalRelatedObjects int                         `json:"totalRelatedObjects"`
+	RelatedObjects      []grc.TemplateRelatedObject `json:"relatedObjects,omitempty"`
+}
+
+// ListTemplateDetails godoc
+// @summary list policy template details
+// @description list the latest results of the policy templates on the clusters, with the messages and the related objects
+// @accept json
+// @produce json
+// @param        policyID      query    string    false    "filter the templates by the policy ID"
+// @param        hub           query    string    false    "filter the templates by the managed hub"
+// @param        cluster       query    string    false    "filter the templates by the cluster"
+// @param        compliance    query    string    false    "filter the templates by the compliance, e.g. NonCompliant"
+// @success      200  {array}   templateDetail
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /policytemplates [get]
+func ListTemplateDetails() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		policyID := ginCtx.Query("policyID")
+		if policyID != "" {
+			if _, err := uuid.Parse(policyID); err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid policyID: %s", policyID)
+				return
+			}
+		}
+
+		query := database.GetGorm().Model(&models.PolicyTemplateDetail{}).Where(&models.PolicyTemplateDetail{
+			PolicyID:    policyID,
+			LeafHubName: ginCtx.Query("hub"),
+			ClusterName: ginCtx.Query("cluster"),
+			Compliance:  ginCtx.Query("compliance"),
+		})
+		var rows []models.PolicyTemplateDetail
+		err := query.Order("leaf_hub_name, policy_namespace, policy_name, cluster_name, template_kind, template_name").
+			Find(&rows).Error
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the policy template details: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+			return
+		}
+
+		details := make([]templateDetail, 0, len(rows))
+		for _, row := range rows {
+			detail := templateDetail{
+				LeafHubName:         row.LeafHubName,
+				PolicyID:            row.PolicyID,
+				PolicyNamespace:     row.PolicyNamespace,
+				PolicyName:          row.PolicyName,
+				ClusterName:         row.ClusterName,
+				TemplateKind:        row.TemplateKind,
+				TemplateName:        row.TemplateName,
+				Compliance:          row.Compliance,
+				Message:             row.Message,
+				LastTimestamp:       row.LastTimestamp,
+				TotalRelatedObjects: row.TotalRelatedObjects,
+			}
+			if len(row.RelatedObjects) > 0 {
+				if err := json.Unmarshal(row.RelatedObjects, &detail.RelatedObjects); err != nil {
+					fmt.Fprintf(gin.DefaultWriter, "failed to unmarshal the related objects: %v\n", err)
+				}
+			}
+			details = append(details, detail)
+		}
+		ginCtx.JSON(http.StatusOK, details)
+	}
+}
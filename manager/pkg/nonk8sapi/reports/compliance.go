@@ -0,0 +1,113 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/reporting"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	// the report of the last 30 days is returned if the start date isn't specified
+	defaultReportDays = 30
+)
+
+// GetComplianceReport godoc
+// @summary get fleet compliance report
+// @description get the compliance of the local policies on the fleet in the date range, which is counted by the daily
+// @description compliance records and grouped by the policy standard, managed hub or managed cluster
+// @accept json
+// @produce json
+// @produce text/csv
+// @produce application/pdf
+// @param        start       query    string    false    "start date of the report, e.g. 2024-01-01, 30 days ago by default"
+// @param        end         query    string    false    "end date of the report, e.g. 2024-01-31, yesterday by default"
+// @param        groupBy     query    string    false    "standard, hub or cluster, standard by default"
+// @param        group       query    string    false    "limit the report to the members of the cluster group"
+// @param        format      query    string    false    "json, csv or pdf, json by default"
+// @success      200  {object}  reporting.ComplianceReport
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /compliancereport [get]
+func GetComplianceReport() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		start, end, ok := parseDateRange(ginCtx)
+		if !ok {
+			return
+		}
+		groupBy := ginCtx.DefaultQuery("groupBy", reporting.GroupByStandard)
+		if !reporting.ValidGroupBy(groupBy) {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid groupBy %s, must be one of %v", groupBy,
+				reporting.GroupByValues))
+			return
+		}
+		format := ginCtx.DefaultQuery("format", reporting.FormatJSON)
+		if !reporting.ValidFormat(format) {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid format %s, must be one of %v", format,
+				reporting.Formats))
+			return
+		}
+
+		report, err := reporting.GenerateComplianceReport(ginCtx.Request.Context(), database.GetGorm(), start, end,
+			groupBy, ginCtx.Query("group"))
+		if err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to generate the compliance report: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if format == reporting.FormatJSON {
+			ginCtx.JSON(http.StatusOK, report)
+			return
+		}
+
+		buf := &bytes.Buffer{}
+		if err := report.Write(buf, format); err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to write the compliance report: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", report.FileName(format)))
+		ginCtx.Data(http.StatusOK, reporting.ContentType(format), buf.Bytes())
+	}
+}
+
+// parseDateRange returns the start and end dates of the report, the bad request is responded if they're invalid
+func parseDateRange(ginCtx *gin.Context) (time.Time, time.Time, bool) {
+	// the history of the day is summarized after the midnight, so it ends at yesterday by default
+	end := time.Now().AddDate(0, 0, -1)
+	if value := ginCtx.Query("end"); value != "" {
+		date, err := time.Parse(reporting.DateFormat, value)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid end date %s, must be YYYY-MM-DD", value))
+			return time.Time{}, time.Time{}, false
+		}
+		end = date
+	}
+	start := end.AddDate(0, 0, -defaultReportDays)
+	if value := ginCtx.Query("start"); value != "" {
+		date, err := time.Parse(reporting.DateFormat, value)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid start date %s, must be YYYY-MM-DD", value))
+			return time.Time{}, time.Time{}, false
+		}
+		start = date
+	}
+	if start.After(end) {
+		ginCtx.String(http.StatusBadRequest, "the start date must not be after the end date")
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
@@ -0,0 +1,324 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// the properties of the search-v2 items, the hub is the managed hub of the item, which isn't in the search-v2 API
+const (
+	propertyKind        = "kind"
+	propertyAPIGroup    = "apigroup"
+	propertyAPIVersion  = "apiversion"
+	propertyName        = "name"
+	propertyNamespace   = "namespace"
+	propertyCluster     = "cluster"
+	propertyCreated     = "created"
+	propertyLabel       = "label"
+	propertyUID         = "_uid"
+	propertyHubResource = "_hubClusterResource"
+	propertyHub         = "hub"
+)
+
+// the relative values of the created property, e.g. {"property": "created", "values": ["day"]} matches the items
+// created in the last day
+var createdPeriods = map[string]time.Duration{
+	"hour":  time.Hour,
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"year":  365 * 24 * time.Hour,
+}
+
+// item is the resource in the search result, the labels are "key=value" joined by "; " as the search-v2 API
+type item map[string]interface{}
+
+func (i item) str(property string) string {
+	value, ok := i[property]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// source lists the items of the kind from the database
+type source struct {
+	kind string
+	list func(db *gorm.DB) ([]item, error)
+}
+
+var sources = []source{
+	{kind: "Cluster", list: listClusters},
+	{kind: "Policy", list: listPolicies},
+	{kind: "Application", list: listArgoObjects(database.ArgoApplicationsTableName, "Application")},
+	{kind: "ApplicationSet", list: listArgoObjects(database.ArgoApplicationSetsTableName, "ApplicationSet")},
+	{kind: "ManagedClusterAddOn", list: listAddons},
+}
+
+// newItem returns the item of the object on the managed hub, the cluster of it is the hub itself
+func newItem(kind, apiGroup, apiVersion, hub string, payload []byte) (item, map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal the %s of the hub %s: %w", kind, hub, err)
+	}
+	name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+	i := item{
+		propertyKind:        kind,
+		propertyAPIGroup:    apiGroup,
+		propertyAPIVersion:  apiVersion,
+		propertyName:        name,
+		propertyCluster:     hub,
+		propertyHub:         hub,
+		propertyHubResource: true,
+	}
+	if namespace, _, _ := unstructured.NestedString(obj, "metadata", "namespace"); namespace != "" {
+		i[propertyNamespace] = namespace
+	}
+	if created, _, _ := unstructured.NestedString(obj, "metadata", "creationTimestamp"); created != "" {
+		i[propertyCreated] = created
+	}
+	if uid, _, _ := unstructured.NestedString(obj, "metadata", "uid"); uid != "" {
+		i[propertyUID] = hub + "/" + uid
+	}
+	if labels, _, _ := unstructured.NestedStringMap(obj, "metadata", "labels"); len(labels) > 0 {
+		pairs := make([]string, 0, len(labels))
+		for key, value := range labels {
+			pairs = append(pairs, key+"="+value)
+		}
+		sort.Strings(pairs)
+		i[propertyLabel] = strings.Join(pairs, "; ")
+	}
+	return i, obj, nil
+}
+
+// setNested sets the property of the item from the field of the object if it exists
+func setNested(i item, property string, obj map[string]interface{}, fields ...string) {
+	if value, found, _ := unstructured.NestedString(obj, fields...); found && value != "" {
+		i[property] = value
+	}
+}
+
+func listClusters(db *gorm.DB) ([]item, error) {
+	var rows []models.ManagedCluster
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make([]item, 0, len(rows))
+	for _, row := range rows {
+		i, obj, err := newItem("Cluster", "internal.open-cluster-management.io", "v1beta1", row.LeafHubName,
+			row.Payload)
+		if err != nil {
+			return nil, err
+		}
+		// the cluster of the managed cluster is itself
+		i[propertyCluster] = i[propertyName]
+		conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == "ManagedClusterConditionAvailable" {
+				i["ManagedClusterConditionAvailable"] = condition["status"]
+			}
+		}
+		setNested(i, "kubernetesVersion", obj, "status", "version", "kubernetes")
+		items = append(items, i)
+	}
+	return items, nil
+}
+
+func listPolicies(db *gorm.DB) ([]item, error) {
+	var rows []models.LocalSpecPolicy
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make([]item, 0, len(rows))
+	for _, row := range rows {
+		i, obj, err := newItem("Policy", "policy.open-cluster-management.io", "v1", row.LeafHubName, row.Payload)
+		if err != nil {
+			return nil, err
+		}
+		setNested(i, "compliant", obj, "status", "compliant")
+		setNested(i, "remediationAction", obj, "spec", "remediationAction")
+		if disabled, found, _ := unstructured.NestedBool(obj, "spec", "disabled"); found {
+			i["disabled"] = disabled
+		}
+		items = append(items, i)
+	}
+	return items, nil
+}
+
+func listArgoObjects(table, kind string) func(db *gorm.DB) ([]item, error) {
+	return func(db *gorm.DB) ([]item, error) {
+		var rows []struct {
+			LeafHubName string
+			Payload     []byte
+		}
+		err := db.Table(database.StatusSchema + "." + table).Select("leaf_hub_name, payload").Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		items := make([]item, 0, len(rows))
+		for _, row := range rows {
+			i, obj, err := newItem(kind, "argoproj.io", "v1alpha1", row.LeafHubName, row.Payload)
+			if err != nil {
+				return nil, err
+			}
+			setNested(i, "healthStatus", obj, "status", "health", "status")
+			setNested(i, "syncStatus", obj, "status", "sync", "status")
+			setNested(i, "destinationServer", obj, "spec", "destination", "server")
+			setNested(i, "destinationNamespace", obj, "spec", "destination", "namespace")
+			setNested(i, "repoURL", obj, "spec", "source", "repoURL")
+			items = append(items, i)
+		}
+		return items, nil
+	}
+}
+
+func listAddons(db *gorm.DB) ([]item, error) {
+	var rows []models.ManagedClusterAddon
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	items := make([]item, 0, len(rows))
+	for _, row := range rows {
+		// the addons are reported with their status rather than the payload, they're in the namespace of the cluster
+		items = append(items, item{
+			propertyKind:        "ManagedClusterAddOn",
+			propertyAPIGroup:    "addon.open-cluster-management.io",
+			propertyAPIVersion:  "v1alpha1",
+			propertyName:        row.AddonName,
+			propertyNamespace:   row.ClusterName,
+			propertyCluster:     row.LeafHubName,
+			propertyHub:         row.LeafHubName,
+			propertyHubResource: true,
+			"status":            row.Status,
+		})
+	}
+	return items, nil
+}
+
+// matchFilters returns true if the item matches all the filters, the filter without values matches any item
+func matchFilters(i item, filters []searchFilter, now time.Time) bool {
+	for _, filter := range filters {
+		if len(filter.Values) == 0 {
+			continue
+		}
+		matched := false
+		for _, value := range filter.Values {
+			if matchValue(i, filter.Property, value, now) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchValue matches the property of the item with the value, which supports the operators "=", "!", "!=", ">",
+// ">=", "<" and "<=" as the prefix, and the "*" wildcard for the equality
+func matchValue(i item, property, value string, now time.Time) bool {
+	operator := ""
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "!", "="} {
+		if strings.HasPrefix(value, op) {
+			operator, value = op, strings.TrimPrefix(value, op)
+			break
+		}
+	}
+	if _, ok := i[property]; !ok {
+		return operator == "!" || operator == "!="
+	}
+	actual := i.str(property)
+
+	if property == propertyCreated {
+		if period, ok := createdPeriods[value]; ok {
+			created, err := time.Parse(time.RFC3339, actual)
+			return err == nil && created.After(now.Add(-period))
+		}
+	}
+
+	switch operator {
+	case ">", ">=", "<", "<=":
+		return compare(actual, value, operator)
+	case "!", "!=":
+		return !matchEqual(property, actual, value)
+	default:
+		return matchEqual(property, actual, value)
+	}
+}
+
+// matchEqual returns true if the actual equals the value, the label matches if any of the labels equals the value
+func matchEqual(property, actual, value string) bool {
+	candidates := []string{actual}
+	if property == propertyLabel {
+		candidates = strings.Split(actual, "; ")
+	}
+	pattern := regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(value), `\*`, ".*") + "$")
+	for _, candidate := range candidates {
+		if pattern.MatchString(candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare compares the numbers, or the strings if either of them isn't a number, e.g. the RFC3339 timestamps
+func compare(actual, value, operator string) bool {
+	result := strings.Compare(actual, value)
+	actualNum, actualErr := strconv.ParseFloat(actual, 64)
+	valueNum, valueErr := strconv.ParseFloat(value, 64)
+	if actualErr == nil && valueErr == nil {
+		switch {
+		case actualNum < valueNum:
+			result = -1
+		case actualNum > valueNum:
+			result = 1
+		default:
+			result = 0
+		}
+	}
+	switch operator {
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	default:
+		return result <= 0
+	}
+}
+
+// matchKeywords returns true if each of the keywords is contained by any property of the item, ignoring the case,
+// the internal properties starting with "_" aren't matched
+func matchKeywords(i item, keywords []string) bool {
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		found := false
+		for property := range i {
+			if !strings.HasPrefix(property, "_") && strings.Contains(strings.ToLower(i.str(property)), keyword) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
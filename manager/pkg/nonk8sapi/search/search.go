@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package search
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+
+	// defaultLimit is the number of the items returned if the limit isn't set, it's the same as the search-v2 API,
+	// and the negative limit returns all the items
+	defaultLimit = 1000
+)
+
+// searchQueryPattern matches the search field of the GraphQL query, which is aliased to searchResult by the console,
+// the other fields of the search-v2 API, e.g. searchSchema and searchComplete, aren't resolved
+var searchQueryPattern = regexp.MustCompile(`\bsearch\s*\(`)
+
+// searchFilter is the filter of the search-v2 API, the item matches the filter if its property matches any of the
+// values, e.g. {"property": "kind", "values": ["Policy", "Cluster"]}
+type searchFilter struct {
+	Property string   `json:"property"`
+	Values   []string `json:"values"`
+}
+
+// searchInput is the input of the searchResult query of the search-v2 API, the item matches all the filters and
+// contains all the keywords
+type searchInput struct {
+	Keywords []string       `json:"keywords,omitempty"`
+	Filters  []searchFilter `json:"filters,omitempty"`
+	Limit    *int           `json:"limit,omitempty"`
+}
+
+type searchResult struct {
+	Count int    `json:"count"`
+	Items []item `json:"items"`
+}
+
+// searchRequest is the GraphQL request sent to the search-v2 API, only the input variables of the searchResult
+// query are resolved
+type searchRequest struct {
+	OperationName string `json:"operationName,omitempty"`
+	Variables     struct {
+		Input []searchInput `json:"input"`
+	} `json:"variables"`
+	Query string `json:"query,omitempty"`
+}
+
+type searchResponse struct {
+	Data struct {
+		SearchResult []searchResult `json:"searchResult"`
+	} `json:"data"`
+}
+
+// Search godoc
+// @summary search resources
+// @description resolve the searchResult query of the ACM search-v2 API from the inventory of all the managed hubs,
+// @description so the clients of the search-v2 API can search across the managed hubs by sending the same GraphQL
+// @description request. The items have the search-v2 properties and the "hub" of them, the kinds are Cluster, Policy,
+// @description Application, ApplicationSet and ManagedClusterAddOn
+// @accept json
+// @produce json
+// @param        request    body    searchRequest    true    "the GraphQL request of the searchResult query"
+// @success      200  {object}  searchResponse
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /search [post]
+func Search() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		request := &searchRequest{}
+		if err := ginCtx.ShouldBindJSON(request); err != nil {
+			ginCtx.String(http.StatusBadRequest, fmt.Sprintf("invalid search request: %v", err))
+			return
+		}
+		if request.Query != "" && !searchQueryPattern.MatchString(request.Query) {
+			ginCtx.String(http.StatusBadRequest, "only the search query is supported")
+			return
+		}
+
+		response := &searchResponse{}
+		response.Data.SearchResult = make([]searchResult, 0, len(request.Variables.Input))
+		now := time.Now()
+		for _, input := range request.Variables.Input {
+			items := []item{}
+			for _, src := range sources {
+				if !matchFilters(item{propertyKind: src.kind}, kindFilters(input.Filters), now) {
+					continue
+				}
+				srcItems, err := src.list(database.GetGorm())
+				if err != nil {
+					fmt.Fprintf(gin.DefaultWriter, "failed to list the %s items: %v\n", src.kind, err)
+					ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+					return
+				}
+				for _, i := range srcItems {
+					if matchFilters(i, input.Filters, now) && matchKeywords(i, input.Keywords) {
+						items = append(items, i)
+					}
+				}
+			}
+			sortItems(items)
+
+			result := searchResult{Count: len(items), Items: items}
+			limit := defaultLimit
+			if input.Limit != nil {
+				limit = *input.Limit
+			}
+			if limit >= 0 && len(items) > limit {
+				result.Items = items[:limit]
+			}
+			response.Data.SearchResult = append(response.Data.SearchResult, result)
+		}
+		ginCtx.JSON(http.StatusOK, response)
+	}
+}
+
+// kindFilters returns the kind filters of the input, they're matched before listing the items of the kind
+func kindFilters(filters []searchFilter) []searchFilter {
+	kinds := []searchFilter{}
+	for _, filter := range filters {
+		if filter.Property == propertyKind {
+			kinds = append(kinds, filter)
+		}
+	}
+	return kinds
+}
+
+// sortItems sorts the items by the hub, the kind, the namespace and the name, so the limited items are stable
+func sortItems(items []item) {
+	key := func(i item) string {
+		return strings.Join([]string{i.str(propertyHub), i.str(propertyKind), i.str(propertyCluster),
+			i.str(propertyNamespace), i.str(propertyName)}, "/")
+	}
+	sort.SliceStable(items, func(a, b int) bool { return key(items[a]) < key(items[b]) })
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package serviceaccounts
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// managedServiceAccount is the credential brokered by the managed service account on the managed cluster, the token
+// is read from the secret of the managed hub by the automation which has access to the hub
+type managedServiceAccount struct {
+	LeafHubName     string     `json:"leafHubName"`
+	ClusterName     string     `json:"clusterName"`
+	Name            string     `json:"name"`
+	Status          string     `json:"status"`
+	Reason          string     `json:"reason,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	TokenSecretName string     `json:"tokenSecretName,omitempty"`
+	LastRefreshTime *time.Time `json:"lastRefreshTime,omitempty"`
+	ExpirationTime  *time.Time `json:"expirationTime,omitempty"`
+}
+
+// ListManagedServiceAccounts godoc
+// @summary list the managed service accounts
+// @description list the credentials of the managed service accounts on the managed clusters, the tokens aren't
+// @description included, they're in the secrets of the managed hubs
+// @accept json
+// @produce json
+// @param        hub              query    string    false    "filter the accounts by the managed hub"
+// @param        cluster          query    string    false    "filter the accounts by the managed cluster"
+// @param        name             query    string    false    "filter the accounts by the name"
+// @param        status           query    string    false    "filter by the status: ready, failed or pending"
+// @param        expiresWithin    query    string    false    "filter the accounts expiring within, e.g. 24h"
+// @success      200  {array}   managedServiceAccount
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @failure      503
+// @security     ApiKeyAuth
+// @router /managedserviceaccounts [get]
+func ListManagedServiceAccounts() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		query := database.GetGorm().Model(&models.ManagedServiceAccount{}).Where(&models.ManagedServiceAccount{
+			LeafHubName: ginCtx.Query("hub"),
+			ClusterName: ginCtx.Query("cluster"),
+			Name:        ginCtx.Query("name"),
+			Status:      ginCtx.Query("status"),
+		})
+		if value := ginCtx.Query("expiresWithin"); value != "" {
+			expiresWithin, err := time.ParseDuration(value)
+			if err != nil || expiresWithin < 0 {
+				ginCtx.String(http.StatusBadRequest,
+					fmt.Sprintf("invalid expiresWithin %s, must be a positive duration, e.g. 24h", value))
+				return
+			}
+			query = query.Where("expiration_time < ?", time.Now().UTC().Add(expiresWithin))
+		}
+
+		var rows []models.ManagedServiceAccount
+		if err := query.Order("leaf_hub_name, cluster_name, name").Find(&rows).Error; err != nil {
+			fmt.Fprintf(gin.DefaultWriter, "failed to list the managed service accounts: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		accounts := make([]managedServiceAccount, 0, len(rows))
+		for _, row := range rows {
+			accounts = append(accounts, managedServiceAccount{
+				LeafHubName:     row.LeafHubName,
+				ClusterName:     row.ClusterName,
+				Name:            row.Name,
+				Status:          row.Status,
+				Reason:          row.Reason,
+				Message:         row.Message,
+				TokenSecretName: row.TokenSecretName,
+				LastRefreshTime: row.LastRefreshTime,
+				ExpirationTime:  row.ExpirationTime,
+			})
+		}
+		ginCtx.JSON(http.StatusOK, accounts)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notification
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// the supported sink types
+const (
+	WebhookSinkType    = "webhook"
+	SlackSinkType      = "slack"
+	ServiceNowSinkType = "servicenow"
+)
+
+// Config is the notification configuration of the manager, e.g.
+//
+//	sinks:
+//	- name: team-a
+//	  type: slack
+//	  url: https://hooks.slack.com/services/xxx
+//	- name: incidents
+//	  type: servicenow
+//	  url: https://example.service-now.com
+//	  credentialsSecret: servicenow-credentials
+//	rules:
+//	- name: policy-x-non-compliant
+//	  policies: ["default/policy-x"]
+//	  sinks: ["team-a"]
+type Config struct {
+	Sinks []SinkConfig `json:"sinks"`
+	Rules []Rule       `json:"rules"`
+}
+
+// SinkConfig describes where the notifications are delivered to.
+type SinkConfig struct {
+	Name string `json:"name"`
+	// Type is one of the webhook, slack and servicenow
+	Type string `json:"type"`
+	// URL is the endpoint of the webhook or slack incoming webhook, or the instance URL of the servicenow
+	URL string `json:"url"`
+	// Headers are added to the requests of the webhook
+	Headers map[string]string `json:"headers,omitempty"`
+	// CredentialsSecret is the name of the secret in the namespace of the manager, its "username" and "password" are
+	// the basic authentication of the servicenow. The secret is read when the notification is sent, so the rotated
+	// credentials are used without restarting the manager.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// Rule selects the compliance changes that are delivered to the sinks.
+type Rule struct {
+	Name string `json:"name"`
+	// Policies are the "namespace/name" or "name" of the policies, empty means all the policies
+	Policies []string `json:"policies,omitempty"`
+	// LeafHubs are the names of the managed hubs, empty means all the hubs
+	LeafHubs []string `json:"leafHubs,omitempty"`
+	// Compliance are the compliance states that the cluster turns into, the value is one of the compliant,
+	// non_compliant, pending and unknown. empty means non_compliant
+	Compliance []string `json:"compliance,omitempty"`
+	// Sinks are the names of the sinks receiving the notifications
+	Sinks []string `json:"sinks"`
+}
+
+// LoadConfig reads the notification configuration from the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the notification config %s: %w", path, err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the notification config %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	sinks := map[string]bool{}
+	for _, sink := range c.Sinks {
+		if sink.Name == "" || sink.URL == "" {
+			return fmt.Errorf("the name and url of the notification sink are required")
+		}
+		if sink.Type != WebhookSinkType && sink.Type != SlackSinkType && sink.Type != ServiceNowSinkType {
+			return fmt.Errorf("unsupported type %q of the notification sink %s", sink.Type, sink.Name)
+		}
+		if sink.Type == ServiceNowSinkType && sink.CredentialsSecret == "" {
+			return fmt.Errorf("the credentialsSecret of the servicenow sink %s is required", sink.Name)
+		}
+		sinks[sink.Name] = true
+	}
+
+	validCompliance := []string{
+		string(database.Compliant), string(database.NonCompliant),
+		string(database.Pending), string(database.Unknown),
+	}
+	for _, rule := range c.Rules {
+		if len(rule.Sinks) == 0 {
+			return fmt.Errorf("the notification rule %s doesn't have any sink", rule.Name)
+		}
+		for _, sink := range rule.Sinks {
+			if !sinks[sink] {
+				return fmt.Errorf("the sink %s of the notification rule %s isn't found", sink, rule.Name)
+			}
+		}
+		for _, compliance := range rule.Compliance {
+			if !utils.ContainsString(validCompliance, compliance) {
+				return fmt.Errorf("invalid compliance %q of the notification rule %s", compliance, rule.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns whether the compliance change is selected by the rule.
+func (r *Rule) Match(evt *ComplianceEvent) bool {
+	if len(r.LeafHubs) > 0 && !utils.ContainsString(r.LeafHubs, evt.LeafHubName) {
+		return false
+	}
+	if len(r.Policies) > 0 && !utils.ContainsString(r.Policies, evt.PolicyName) &&
+		!utils.ContainsString(r.Policies, fmt.Sprintf("%s/%s", evt.PolicyNamespace, evt.PolicyName)) {
+		return false
+	}
+	if len(r.Compliance) == 0 {
+		return evt.Compliance == string(database.NonCompliant)
+	}
+	return utils.ContainsString(r.Compliance, evt.Compliance)
+}
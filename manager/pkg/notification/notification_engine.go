@@ -0,0 +1,241 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	eventQueueSize = 1000
+	sendTimeout    = 10 * time.Second
+	// policyNameTTL is how long the name of the policy is cached, so the renamed and deleted policies are resolved
+	// again from the database
+	policyNameTTL = 10 * time.Minute
+	// maxPolicyNames bounds the cache, the names of the policies which aren't changed anymore expire but stay until
+	// they're evicted
+	maxPolicyNames = 10000
+)
+
+// the engine is nil if the notification isn't configured
+var engine *notificationEngine
+
+// ComplianceEvent is the compliance change of a cluster, it's delivered to the sinks selected by the rules.
+type ComplianceEvent struct {
+	LeafHubName        string    `json:"leafHubName"`
+	PolicyID           string    `json:"policyId"`
+	PolicyName         string    `json:"policyName"`
+	PolicyNamespace    string    `json:"policyNamespace"`
+	ClusterName        string    `json:"clusterName"`
+	Compliance         string    `json:"compliance"`
+	PreviousCompliance string    `json:"previousCompliance,omitempty"`
+	LocalPolicy        bool      `json:"localPolicy"`
+	Time               time.Time `json:"time"`
+}
+
+func (e *ComplianceEvent) policyFullName() string {
+	if e.PolicyNamespace == "" {
+		return e.PolicyName
+	}
+	return fmt.Sprintf("%s/%s", e.PolicyNamespace, e.PolicyName)
+}
+
+// Message is the human readable description of the compliance change.
+func (e *ComplianceEvent) Message() string {
+	previous := e.PreviousCompliance
+	if previous == "" {
+		previous = "none"
+	}
+	return fmt.Sprintf("The compliance of policy %s on cluster %s (hub %s) changed from %s to %s at %s",
+		e.policyFullName(), e.ClusterName, e.LeafHubName, previous, e.Compliance, e.Time.Format(time.RFC3339))
+}
+
+type notificationEngine struct {
+	log    logr.Logger
+	rules  []Rule
+	sinks  map[string]Sink
+	events chan *ComplianceEvent
+	// policyID to the namespace and name of the policy
+	policyNames map[string]policyName
+}
+
+type policyName struct {
+	namespace string
+	name      string
+	expiresAt time.Time
+}
+
+// AddNotificationEngine loads the notification configuration and adds the engine to the manager. The
+// notification is disabled if the config path is empty. The credentials of the sinks are read from the secrets in
+// the namespace of the manager.
+func AddNotificationEngine(mgr ctrl.Manager, configPath, namespace string) error {
+	if configPath == "" {
+		return nil
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	e, err := newNotificationEngine(config, &http.Client{Timeout: sendTimeout}, mgr.GetAPIReader(), namespace)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(e); err != nil {
+		return fmt.Errorf("failed to add the notification engine to the manager: %w", err)
+	}
+	engine = e
+	return nil
+}
+
+func newNotificationEngine(config *Config, httpClient *http.Client, secretReader client.Reader, namespace string,
+) (*notificationEngine, error) {
+	e := &notificationEngine{
+		log:         ctrl.Log.WithName("notification-engine"),
+		rules:       config.Rules,
+		sinks:       map[string]Sink{},
+		events:      make(chan *ComplianceEvent, eventQueueSize),
+		policyNames: map[string]policyName{},
+	}
+	for _, sinkConfig := range config.Sinks {
+		sink, err := NewSink(sinkConfig, httpClient, secretReader, namespace)
+		if err != nil {
+			return nil, err
+		}
+		e.sinks[sinkConfig.Name] = sink
+	}
+	return e, nil
+}
+
+// NotifyComplianceChanges queues the compliance changes to be delivered by the engine, it doesn't block the caller,
+// the changes are dropped if the queue is full.
+func NotifyComplianceChanges(events ...*ComplianceEvent) {
+	if engine == nil {
+		return
+	}
+	for _, evt := range events {
+		select {
+		case engine.events <- evt:
+		default:
+			engine.log.Info("the notification queue is full, drop the compliance change", "policyID", evt.PolicyID,
+				"cluster", evt.ClusterName, "compliance", evt.Compliance)
+		}
+	}
+}
+
+func (e *notificationEngine) Start(ctx context.Context) error {
+	e.log.Info("start notification engine", "rules", len(e.rules), "sinks", len(e.sinks))
+	for {
+		select {
+		case <-ctx.Done():
+			e.log.Info("notification engine is stopped")
+			return nil
+		case evt := <-e.events:
+			e.dispatch(ctx, evt)
+		}
+	}
+}
+
+func (e *notificationEngine) dispatch(ctx context.Context, evt *ComplianceEvent) {
+	if evt.PolicyName == "" {
+		if err := e.resolvePolicyName(evt); err != nil {
+			e.log.Error(err, "failed to get the policy name", "policyID", evt.PolicyID)
+		}
+	}
+
+	// the event is delivered to the sink only once even if it's selected by multiple rules
+	sent := map[string]bool{}
+	for i := range e.rules {
+		if !e.rules[i].Match(evt) {
+			continue
+		}
+		for _, sinkName := range e.rules[i].Sinks {
+			if sent[sinkName] {
+				continue
+			}
+			sent[sinkName] = true
+
+			sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			if err := e.sinks[sinkName].Send(sendCtx, evt); err != nil {
+				e.log.Error(err, "failed to send the notification", "sink", sinkName, "rule", e.rules[i].Name)
+			}
+			cancel()
+		}
+	}
+}
+
+func (e *notificationEngine) resolvePolicyName(evt *ComplianceEvent) error {
+	if cached, found := e.cachedPolicyName(evt.PolicyID); found {
+		evt.PolicyNamespace, evt.PolicyName = cached.namespace, cached.name
+		return nil
+	}
+
+	sql := `SELECT payload->'metadata'->>'namespace' AS namespace, payload->'metadata'->>'name' AS name
+		FROM spec.policies WHERE id = ?`
+	if evt.LocalPolicy {
+		sql = `SELECT payload->'metadata'->>'namespace' AS namespace, payload->'metadata'->>'name' AS name
+			FROM local_spec.policies WHERE policy_id = ?`
+	}
+	var policy struct {
+		Namespace string
+		Name      string
+	}
+	if err := database.GetGorm().Raw(sql, evt.PolicyID).Scan(&policy).Error; err != nil {
+		return err
+	}
+	if policy.Name == "" {
+		return fmt.Errorf("the policy isn't found")
+	}
+	e.cachePolicyName(evt.PolicyID, policyName{
+		namespace: policy.Namespace,
+		name:      policy.Name,
+		expiresAt: time.Now().Add(policyNameTTL),
+	})
+	evt.PolicyNamespace, evt.PolicyName = policy.Namespace, policy.Name
+	return nil
+}
+
+// cachedPolicyName returns the cached name of the policy, the expired one is removed from the cache
+func (e *notificationEngine) cachedPolicyName(policyID string) (policyName, bool) {
+	cached, found := e.policyNames[policyID]
+	if !found {
+		return policyName{}, false
+	}
+	if time.Now().After(cached.expiresAt) {
+		delete(e.policyNames, policyID)
+		return policyName{}, false
+	}
+	return cached, true
+}
+
+// cachePolicyName caches the name of the policy, the expired names are evicted once the cache is full, and the name
+// expiring first is evicted if none of them is expired
+func (e *notificationEngine) cachePolicyName(policyID string, name policyName) {
+	if _, found := e.policyNames[policyID]; !found && len(e.policyNames) >= maxPolicyNames {
+		now := time.Now()
+		oldestID := ""
+		for id, cached := range e.policyNames {
+			if now.After(cached.expiresAt) {
+				delete(e.policyNames, id)
+				continue
+			}
+			if oldestID == "" || cached.expiresAt.Before(e.policyNames[oldestID].expiresAt) {
+				oldestID = id
+			}
+		}
+		if len(e.policyNames) >= maxPolicyNames {
+			delete(e.policyNames, oldestID)
+		}
+	}
+	e.policyNames[policyID] = name
+}
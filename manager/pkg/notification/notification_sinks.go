@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const serviceNowIncidentPath = "/api/now/table/incident"
+
+// Sink delivers the compliance change to the external system.
+type Sink interface {
+	Send(ctx context.Context, evt *ComplianceEvent) error
+}
+
+// NewSink returns the sink of the config, the secretReader reads the credentials of the sink from the namespace.
+func NewSink(config SinkConfig, httpClient *http.Client, secretReader client.Reader, namespace string) (Sink, error) {
+	switch config.Type {
+	case WebhookSinkType:
+		return &webhookSink{config: config, client: httpClient}, nil
+	case SlackSinkType:
+		return &slackSink{config: config, client: httpClient}, nil
+	case ServiceNowSinkType:
+		return &serviceNowSink{config: config, client: httpClient, secretReader: secretReader, namespace: namespace}, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %q of the notification sink %s", config.Type, config.Name)
+	}
+}
+
+// webhookSink posts the compliance change as the json body
+type webhookSink struct {
+	config SinkConfig
+	client *http.Client
+}
+
+func (s *webhookSink) Send(ctx context.Context, evt *ComplianceEvent) error {
+	return postJSON(ctx, s.client, s.config.URL, s.config.Headers, "", "", evt)
+}
+
+// slackSink posts the compliance change to the slack incoming webhook
+type slackSink struct {
+	config SinkConfig
+	client *http.Client
+}
+
+func (s *slackSink) Send(ctx context.Context, evt *ComplianceEvent) error {
+	return postJSON(ctx, s.client, s.config.URL, nil, "", "", map[string]string{"text": evt.Message()})
+}
+
+// serviceNowSink creates an incident for the compliance change
+type serviceNowSink struct {
+	config       SinkConfig
+	client       *http.Client
+	secretReader client.Reader
+	namespace    string
+}
+
+func (s *serviceNowSink) Send(ctx context.Context, evt *ComplianceEvent) error {
+	incident := map[string]string{
+		"short_description": fmt.Sprintf("Policy %s is %s on cluster %s", evt.policyFullName(), evt.Compliance,
+			evt.ClusterName),
+		"description": evt.Message(),
+	}
+	secret := &corev1.Secret{}
+	if err := s.secretReader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.config.CredentialsSecret},
+		secret); err != nil {
+		return fmt.Errorf("failed to get the credentials of the servicenow sink %s: %w", s.config.Name, err)
+	}
+	url := strings.TrimSuffix(s.config.URL, "/") + serviceNowIncidentPath
+	return postJSON(ctx, s.client, url, nil, string(secret.Data["username"]), string(secret.Data["password"]),
+		incident)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string,
+	username, password string, body interface{},
+) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, val := range headers {
+		req.Header.Set(key, val)
+	}
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return nil
+}
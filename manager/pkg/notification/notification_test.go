@@ -0,0 +1,225 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid config",
+			content: `
+sinks:
+- name: team-a
+  type: slack
+  url: https://hooks.slack.com/services/xxx
+rules:
+- name: policy-x
+  policies: ["default/policy-x"]
+  sinks: ["team-a"]
+`,
+		},
+		{
+			name: "servicenow without the credentials secret",
+			content: `
+sinks:
+- name: incidents
+  type: servicenow
+  url: https://example.service-now.com
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported sink type",
+			content: `
+sinks:
+- name: team-a
+  type: email
+  url: https://example.com
+`,
+			wantErr: true,
+		},
+		{
+			name: "rule with unknown sink",
+			content: `
+sinks:
+- name: team-a
+  type: webhook
+  url: https://example.com
+rules:
+- name: policy-x
+  sinks: ["team-b"]
+`,
+			wantErr: true,
+		},
+		{
+			name: "rule with invalid compliance",
+			content: `
+sinks:
+- name: team-a
+  type: webhook
+  url: https://example.com
+rules:
+- name: policy-x
+  compliance: ["NonCompliant"]
+  sinks: ["team-a"]
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "notification.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			_, err := LoadConfig(path)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	evt := &ComplianceEvent{
+		LeafHubName:     "hub1",
+		PolicyNamespace: "default",
+		PolicyName:      "policy-x",
+		ClusterName:     "cluster1",
+		Compliance:      "non_compliant",
+	}
+	tests := []struct {
+		name     string
+		rule     Rule
+		expected bool
+	}{
+		{name: "match all non compliant", rule: Rule{}, expected: true},
+		{name: "match the policy name", rule: Rule{Policies: []string{"policy-x"}}, expected: true},
+		{name: "match the policy namespace and name", rule: Rule{Policies: []string{"default/policy-x"}}, expected: true},
+		{name: "mismatch the policy", rule: Rule{Policies: []string{"default/policy-y"}}, expected: false},
+		{name: "mismatch the hub", rule: Rule{LeafHubs: []string{"hub2"}}, expected: false},
+		{name: "mismatch the compliance", rule: Rule{Compliance: []string{"compliant"}}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rule.Match(evt))
+		})
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	requests := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests[r.URL.Path] = body
+		if r.URL.Path == serviceNowIncidentPath {
+			username, password, _ := r.BasicAuth()
+			assert.Equal(t, "admin", username)
+			assert.Equal(t, "secret", password)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer server.Close()
+
+	secretReader := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "snow-credentials", Namespace: "multicluster-global-hub"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}).Build()
+	e, err := newNotificationEngine(&Config{
+		Sinks: []SinkConfig{
+			{Name: "webhook", Type: WebhookSinkType, URL: server.URL + "/webhook"},
+			{Name: "slack", Type: SlackSinkType, URL: server.URL + "/slack"},
+			{Name: "snow", Type: ServiceNowSinkType, URL: server.URL, CredentialsSecret: "snow-credentials"},
+		},
+		Rules: []Rule{
+			{Name: "all", Sinks: []string{"webhook", "slack"}},
+			{Name: "policy-x", Policies: []string{"default/policy-x"}, Sinks: []string{"webhook", "snow"}},
+			{Name: "compliant", Compliance: []string{"compliant"}, Sinks: []string{"webhook"}},
+		},
+	}, server.Client(), secretReader, "multicluster-global-hub")
+	require.NoError(t, err)
+
+	e.dispatch(context.Background(), &ComplianceEvent{
+		LeafHubName:     "hub1",
+		PolicyNamespace: "default",
+		PolicyName:      "policy-x",
+		ClusterName:     "cluster1",
+		Compliance:      "non_compliant",
+		Time:            time.Now(),
+	})
+
+	assert.Len(t, requests, 3)
+	evt := &ComplianceEvent{}
+	require.NoError(t, json.Unmarshal(requests["/webhook"], evt))
+	assert.Equal(t, "cluster1", evt.ClusterName)
+
+	slackMessage := map[string]string{}
+	require.NoError(t, json.Unmarshal(requests["/slack"], &slackMessage))
+	assert.Contains(t, slackMessage["text"], "default/policy-x")
+
+	incident := map[string]string{}
+	require.NoError(t, json.Unmarshal(requests[serviceNowIncidentPath], &incident))
+	assert.Equal(t, "Policy default/policy-x is non_compliant on cluster cluster1", incident["short_description"])
+}
+
+func TestServiceNowSinkWithoutSecret(t *testing.T) {
+	sink, err := NewSink(SinkConfig{
+		Name: "snow", Type: ServiceNowSinkType, URL: "https://example.service-now.com", CredentialsSecret: "missing",
+	}, http.DefaultClient, fake.NewClientBuilder().Build(), "multicluster-global-hub")
+	require.NoError(t, err)
+
+	// the incident isn't created without the credentials
+	err = sink.Send(context.Background(), &ComplianceEvent{PolicyName: "policy-x", Compliance: "non_compliant"})
+	assert.ErrorContains(t, err, "failed to get the credentials of the servicenow sink snow")
+}
+
+func TestPolicyNameCache(t *testing.T) {
+	e, err := newNotificationEngine(&Config{}, http.DefaultClient, fake.NewClientBuilder().Build(), "")
+	require.NoError(t, err)
+
+	e.policyNames["policy-1"] = policyName{namespace: "default", name: "policy-x", expiresAt: time.Now().Add(time.Minute)}
+	e.policyNames["policy-2"] = policyName{namespace: "default", name: "policy-y", expiresAt: time.Now().Add(-time.Second)}
+
+	evt := &ComplianceEvent{PolicyID: "policy-1"}
+	require.NoError(t, e.resolvePolicyName(evt))
+	assert.Equal(t, "default/policy-x", evt.policyFullName())
+
+	// the expired name, e.g. the policy is renamed or deleted, is removed and resolved again
+	_, found := e.cachedPolicyName("policy-2")
+	assert.False(t, found)
+	assert.NotContains(t, e.policyNames, "policy-2")
+
+	// the cache is bounded, the expired names are evicted first, then the one expiring first
+	e.policyNames = map[string]policyName{}
+	for i := 0; i < maxPolicyNames; i++ {
+		e.policyNames[fmt.Sprintf("policy-%d", i)] = policyName{name: "policy", expiresAt: time.Now().Add(time.Hour)}
+	}
+	e.policyNames["policy-0"] = policyName{name: "policy", expiresAt: time.Now().Add(time.Minute)}
+	e.policyNames["policy-1"] = policyName{name: "policy", expiresAt: time.Now().Add(-time.Second)}
+
+	e.cachePolicyName("policy-new", policyName{name: "policy", expiresAt: time.Now().Add(time.Hour)})
+	assert.Len(t, e.policyNames, maxPolicyNames)
+	assert.NotContains(t, e.policyNames, "policy-1")
+	assert.Contains(t, e.policyNames, "policy-0")
+
+	e.cachePolicyName("policy-another", policyName{name: "policy", expiresAt: time.Now().Add(time.Hour)})
+	assert.Len(t, e.policyNames, maxPolicyNames)
+	assert.NotContains(t, e.policyNames, "policy-0")
+	assert.Contains(t, e.policyNames, "policy-new")
+}
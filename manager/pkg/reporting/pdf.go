@@ -0,0 +1,104 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package reporting
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// the landscape A4 page in points, the text is written with the 8pt Courier, so a line holds 158 characters
+const (
+	pdfPageWidth    = 842
+	pdfPageHeight   = 595
+	pdfMargin       = 40
+	pdfFontSize     = 8
+	pdfLineHeight   = 11
+	pdfLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+)
+
+// pdfDocument writes the lines of text into the pages of a PDF 1.4 file with the standard Courier font, which
+// doesn't need to be embedded, so the tabular reports are rendered without a PDF library
+type pdfDocument struct {
+	pages [][]string
+}
+
+func (d *pdfDocument) addLine(line string) {
+	if len(d.pages) == 0 || d.pageFull() {
+		d.pages = append(d.pages, []string{})
+	}
+	d.pages[len(d.pages)-1] = append(d.pages[len(d.pages)-1], line)
+}
+
+// pageFull returns whether the next line is added into a new page
+func (d *pdfDocument) pageFull() bool {
+	return len(d.pages) > 0 && len(d.pages[len(d.pages)-1]) >= pdfLinesPerPage
+}
+
+// WriteTo writes the objects of the document: the catalog, the page tree, the font, then the page and content of
+// each page, which are followed by the cross-reference table with the offsets of the objects
+func (d *pdfDocument) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = [][]string{{}}
+	}
+
+	buf := &bytes.Buffer{}
+	offsets := []int{}
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		// the page and its content are the objects 4+2i and 5+2i
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, lines := range pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 5+2*i))
+
+		content := &strings.Builder{}
+		fmt.Fprintf(content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin,
+			pdfPageHeight-pdfMargin)
+		for _, line := range lines {
+			fmt.Fprintf(content, "(%s) '\n", escapePDFText(line))
+		}
+		content.WriteString("ET")
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.WriteTo(w)
+}
+
+// escapePDFText escapes the delimiters of the string literal, and replaces the characters out of the ASCII since the
+// font isn't embedded
+func escapePDFText(text string) string {
+	escaped := &strings.Builder{}
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			escaped.WriteRune('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
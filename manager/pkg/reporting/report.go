@@ -0,0 +1,148 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const DateFormat = "2006-01-02"
+
+// the dimensions of the compliance report
+const (
+	GroupByStandard = "standard"
+	GroupByHub      = "hub"
+	GroupByCluster  = "cluster"
+)
+
+var GroupByValues = []string{GroupByStandard, GroupByHub, GroupByCluster}
+
+// the policy without the standards annotation is reported under it
+const unspecifiedStandard = "unspecified"
+
+// complianceReportQuery counts the daily compliance records of the local policies in the date range, the group
+// columns and the joins are filled by the dimension of the report
+const complianceReportQuery = `SELECT %s AS name, %s AS leaf_hub_name,
+		COUNT(DISTINCT h.policy_id) AS policies,
+		COUNT(DISTINCT h.cluster_id) AS clusters,
+		COUNT(*) FILTER (WHERE h.compliance = 'compliant') AS compliant,
+		COUNT(*) FILTER (WHERE h.compliance = 'non_compliant') AS non_compliant,
+		COUNT(*) FILTER (WHERE h.compliance IN ('pending', 'unknown')) AS unknown
+	FROM history.local_compliance h
+	%s
+	WHERE h.compliance_date BETWEEN @start AND @end
+		AND (@group = '' OR h.cluster_id IN (
+			SELECT cluster_id FROM status.cluster_group_members WHERE group_name = @group))
+	GROUP BY 1, 2
+	ORDER BY 2, 1`
+
+// the name, hub and joins of each dimension, the policy with multiple standards is counted in each of them, and the
+// cluster is joined with the soft deleted records, so that the removed clusters are still named
+var groupByClauses = map[string][3]string{
+	GroupByStandard: {
+		"trim(s.standard)", "''",
+		`LEFT JOIN local_spec.policies p ON p.policy_id = h.policy_id
+		CROSS JOIN LATERAL unnest(string_to_array(
+			COALESCE(NULLIF(p.policy_standard, ''), '` + unspecifiedStandard + `'), ',')) AS s(standard)`,
+	},
+	GroupByHub: {"h.leaf_hub_name", "h.leaf_hub_name", ""},
+	GroupByCluster: {
+		"COALESCE(mc.cluster_name, h.cluster_id::text, '')", "h.leaf_hub_name",
+		"LEFT JOIN status.managed_clusters mc ON mc.cluster_id = h.cluster_id",
+	},
+}
+
+// ComplianceReport summarizes the daily compliance of the local policies on the fleet in the date range
+type ComplianceReport struct {
+	Start        string                `json:"start"`
+	End          string                `json:"end"`
+	GroupBy      string                `json:"groupBy"`
+	ClusterGroup string                `json:"clusterGroup,omitempty"`
+	GeneratedAt  time.Time             `json:"generatedAt"`
+	Rows         []ComplianceReportRow `json:"rows"`
+}
+
+// ComplianceReportRow is the compliance of a standard, hub or cluster. The compliance is counted by the daily records
+// of the policies on the clusters, e.g. a policy non compliant on a cluster for 3 days is counted as 3
+type ComplianceReportRow struct {
+	// Name is the standard, hub or cluster name
+	Name         string `json:"name"`
+	LeafHubName  string `json:"leafHubName,omitempty"`
+	Policies     int    `json:"policies"`
+	Clusters     int    `json:"clusters"`
+	Compliant    int    `json:"compliant"`
+	NonCompliant int    `json:"nonCompliant"`
+	// Unknown is the pending and unknown records
+	Unknown int `json:"unknown"`
+	// ComplianceRate is the percentage of the compliant records
+	ComplianceRate float64 `json:"complianceRate"`
+}
+
+// GenerateComplianceReport queries the compliance history between the start and end dates, both are included. The
+// history is limited to the members of the cluster group if it isn't empty
+func GenerateComplianceReport(ctx context.Context, db *gorm.DB, start, end time.Time, groupBy, clusterGroup string,
+) (*ComplianceReport, error) {
+	clauses, ok := groupByClauses[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported group %q of the compliance report, must be one of %v", groupBy,
+			GroupByValues)
+	}
+	if start.After(end) {
+		return nil, fmt.Errorf("the start date must not be after the end date")
+	}
+
+	report := &ComplianceReport{
+		Start:        start.Format(DateFormat),
+		End:          end.Format(DateFormat),
+		GroupBy:      groupBy,
+		ClusterGroup: clusterGroup,
+		GeneratedAt:  time.Now().UTC(),
+		Rows:         []ComplianceReportRow{},
+	}
+	query := fmt.Sprintf(complianceReportQuery, clauses[0], clauses[1], clauses[2])
+	err := db.WithContext(ctx).Raw(query, map[string]interface{}{
+		"start": report.Start,
+		"end":   report.End,
+		"group": clusterGroup,
+	}).Scan(&report.Rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the compliance report: %w", err)
+	}
+	for i := range report.Rows {
+		report.Rows[i].ComplianceRate = complianceRate(report.Rows[i].Compliant,
+			report.Rows[i].Compliant+report.Rows[i].NonCompliant+report.Rows[i].Unknown)
+	}
+	return report, nil
+}
+
+// Total sums up the rows of the report, the policies and clusters aren't summed since they overlap among the rows
+func (r *ComplianceReport) Total() ComplianceReportRow {
+	total := ComplianceReportRow{Name: "total"}
+	for _, row := range r.Rows {
+		total.Compliant += row.Compliant
+		total.NonCompliant += row.NonCompliant
+		total.Unknown += row.Unknown
+	}
+	total.ComplianceRate = complianceRate(total.Compliant, total.Compliant+total.NonCompliant+total.Unknown)
+	return total
+}
+
+// ValidGroupBy returns whether the dimension of the report is supported
+func ValidGroupBy(groupBy string) bool {
+	return utils.ContainsString(GroupByValues, groupBy)
+}
+
+func complianceRate(compliant, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	// keep 2 decimals of the percentage
+	return float64(compliant*10000/total) / 100
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package reporting
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the scheduled compliance reports of the manager, each report is generated by the cron schedule for the
+// last days, and delivered to the object storage or the email, e.g.
+//
+//	timeZone: UTC
+//	reports:
+//	- name: weekly-by-standard
+//	  schedule: "0 6 * * 1"
+//	  groupBy: standard
+//	  days: 7
+//	  formats: ["csv", "pdf"]
+//	  objectStorage:
+//	    endpoint: s3.us-east-1.amazonaws.com
+//	    bucket: compliance-reports
+//	  email:
+//	    smtpServer: smtp.example.com:587
+//	    from: globalhub@example.com
+//	    to: ["auditor@example.com"]
+type Config struct {
+	// TimeZone is the location of the schedules and the report dates, the local timezone is used if it's empty
+	TimeZone string         `json:"timeZone,omitempty"`
+	Reports  []ReportConfig `json:"reports"`
+}
+
+// ReportConfig describes how the report is generated and where it's delivered to.
+type ReportConfig struct {
+	Name string `json:"name"`
+	// Schedule is the cron expression to generate the report
+	Schedule string `json:"schedule"`
+	// GroupBy is one of the standard, hub and cluster
+	GroupBy string `json:"groupBy"`
+	// ClusterGroup limits the report to the members of the cluster group, all the clusters are reported if it's empty
+	ClusterGroup string `json:"clusterGroup,omitempty"`
+	// Days is the period of the report, which ends at yesterday. 7 by default
+	Days int `json:"days,omitempty"`
+	// Formats are the files of the report, the value is one of the json, csv and pdf. csv by default
+	Formats       []string             `json:"formats,omitempty"`
+	ObjectStorage *ObjectStorageConfig `json:"objectStorage,omitempty"`
+	Email         *EmailConfig         `json:"email,omitempty"`
+}
+
+// ObjectStorageConfig is the S3-compatible bucket of the reports, the credentials are read from the
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+type ObjectStorageConfig struct {
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	Region   string `json:"region,omitempty"`
+	// Prefix is prepended to the object keys: <prefix>/<report name>/<file name>
+	Prefix   string `json:"prefix,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+}
+
+// EmailConfig sends the report files as the attachments by the SMTP server
+type EmailConfig struct {
+	// SMTPServer is the host:port of the server, the STARTTLS is used if the server supports it
+	SMTPServer string   `json:"smtpServer"`
+	From       string   `json:"from"`
+	To         []string `json:"to"`
+	// Username and Password are the plain authentication of the server, it's skipped if the username is empty
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// LoadConfig reads the report configuration from the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the report config %s: %w", path, err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the report config %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *Config) validate() error {
+	if c.TimeZone != "" {
+		if _, err := time.LoadLocation(c.TimeZone); err != nil {
+			return fmt.Errorf("invalid timezone %s of the reports: %w", c.TimeZone, err)
+		}
+	}
+	names := map[string]bool{}
+	for i := range c.Reports {
+		report := &c.Reports[i]
+		if report.Name == "" {
+			return fmt.Errorf("the name of the report is required")
+		}
+		if names[report.Name] {
+			return fmt.Errorf("the report %s is duplicated", report.Name)
+		}
+		names[report.Name] = true
+
+		if _, err := gocron.NewScheduler(time.UTC).Cron(report.Schedule).Do(func() {}); err != nil {
+			return fmt.Errorf("invalid schedule %q of the report %s: %w", report.Schedule, report.Name, err)
+		}
+		if !ValidGroupBy(report.GroupBy) {
+			return fmt.Errorf("unsupported group %q of the report %s", report.GroupBy, report.Name)
+		}
+		if report.Days < 0 {
+			return fmt.Errorf("the days of the report %s must not be negative", report.Name)
+		}
+		if report.Days == 0 {
+			report.Days = 7
+		}
+		if len(report.Formats) == 0 {
+			report.Formats = []string{FormatCSV}
+		}
+		for _, format := range report.Formats {
+			if !ValidFormat(format) {
+				return fmt.Errorf("unsupported format %q of the report %s", format, report.Name)
+			}
+		}
+
+		if report.ObjectStorage == nil && report.Email == nil {
+			return fmt.Errorf("the object storage or email of the report %s is required", report.Name)
+		}
+		if report.ObjectStorage != nil && (report.ObjectStorage.Endpoint == "" || report.ObjectStorage.Bucket == "") {
+			return fmt.Errorf("the endpoint and bucket of the report %s are required", report.Name)
+		}
+		if report.Email != nil && (report.Email.SMTPServer == "" || report.Email.From == "" ||
+			len(report.Email.To) == 0) {
+			return fmt.Errorf("the smtp server, sender and recipients of the report %s are required", report.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/archive"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// sendMail is replaced by the tests
+var sendMail = smtp.SendMail
+
+type reportScheduler struct {
+	log      logr.Logger
+	location *time.Location
+	reports  []ReportConfig
+	// report name to the client of its object storage
+	storages map[string]*archive.S3Client
+}
+
+// AddReportScheduler loads the report configuration and adds the scheduler to the manager. The scheduled reports are
+// disabled if the config path is empty.
+func AddReportScheduler(mgr ctrl.Manager, configPath string) error {
+	if configPath == "" {
+		return nil
+	}
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+	s, err := newReportScheduler(config)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(s); err != nil {
+		return fmt.Errorf("failed to add the report scheduler to the manager: %w", err)
+	}
+	return nil
+}
+
+func newReportScheduler(config *Config) (*reportScheduler, error) {
+	s := &reportScheduler{
+		log:      ctrl.Log.WithName("report-scheduler"),
+		location: time.Local,
+		reports:  config.Reports,
+		storages: map[string]*archive.S3Client{},
+	}
+	if config.TimeZone != "" {
+		location, err := time.LoadLocation(config.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		s.location = location
+	}
+	for _, report := range config.Reports {
+		if report.ObjectStorage == nil {
+			continue
+		}
+		client, err := archive.NewS3Client(&archive.S3Config{
+			Endpoint: report.ObjectStorage.Endpoint,
+			Bucket:   report.ObjectStorage.Bucket,
+			Region:   report.ObjectStorage.Region,
+			Prefix:   report.ObjectStorage.Prefix,
+			Insecure: report.ObjectStorage.Insecure,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the object storage client of the report %s: %w", report.Name, err)
+		}
+		s.storages[report.Name] = client
+	}
+	return s, nil
+}
+
+// Start runs the scheduler on the leader, so each report is only delivered once
+func (s *reportScheduler) Start(ctx context.Context) error {
+	scheduler := gocron.NewScheduler(s.location)
+	for _, report := range s.reports {
+		job, err := scheduler.Cron(report.Schedule).Tag(report.Name).Do(s.run, ctx, report)
+		if err != nil {
+			return fmt.Errorf("failed to schedule the report %s: %w", report.Name, err)
+		}
+		s.log.Info("schedule the compliance report", "name", report.Name, "scheduleAt", job.ScheduledAtTime())
+	}
+	scheduler.StartAsync()
+	<-ctx.Done()
+	scheduler.Stop()
+	s.log.Info("report scheduler is stopped")
+	return nil
+}
+
+// run generates the report of the last days, which ends at yesterday since the compliance history of the day is
+// summarized after the midnight
+func (s *reportScheduler) run(ctx context.Context, config ReportConfig) {
+	end := time.Now().In(s.location).AddDate(0, 0, -1)
+	start := end.AddDate(0, 0, 1-config.Days)
+	report, err := GenerateComplianceReport(ctx, database.GetGorm(), start, end, config.GroupBy,
+		config.ClusterGroup)
+	if err != nil {
+		s.log.Error(err, "failed to generate the compliance report", "name", config.Name)
+		return
+	}
+	if err := s.deliver(ctx, config, report); err != nil {
+		s.log.Error(err, "failed to deliver the compliance report", "name", config.Name)
+		return
+	}
+	s.log.Info("deliver the compliance report", "name", config.Name, "start", report.Start, "end", report.End,
+		"rows", len(report.Rows))
+}
+
+// reportFile is the report encoded in a format
+type reportFile struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+// deliver writes the report in the formats, then uploads them to the object storage and sends them by the email,
+// the email is still sent if the upload fails
+func (s *reportScheduler) deliver(ctx context.Context, config ReportConfig, report *ComplianceReport) error {
+	files := make([]reportFile, 0, len(config.Formats))
+	for _, format := range config.Formats {
+		buf := &bytes.Buffer{}
+		if err := report.Write(buf, format); err != nil {
+			return fmt.Errorf("failed to write the report in %s: %w", format, err)
+		}
+		files = append(files, reportFile{
+			name:        report.FileName(format),
+			contentType: ContentType(format),
+			content:     buf.Bytes(),
+		})
+	}
+
+	var errs []string
+	if client, ok := s.storages[config.Name]; ok {
+		for _, file := range files {
+			key := path.Join(client.Config().Prefix, config.Name, file.name)
+			sum := sha256.Sum256(file.content)
+			err := client.PutObject(ctx, key, file.contentType, bytes.NewReader(file.content),
+				int64(len(file.content)), hex.EncodeToString(sum[:]))
+			if err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if config.Email != nil {
+		subject := fmt.Sprintf("Fleet compliance report %s: %s to %s", config.Name, report.Start, report.End)
+		total := report.Total()
+		body := fmt.Sprintf("The compliance report %s of the global hub by %s from %s to %s is attached.\r\n\r\n"+
+			"Compliant: %d\r\nNon compliant: %d\r\nUnknown: %d\r\nCompliance rate: %.2f%%\r\n", config.Name,
+			report.GroupBy, report.Start, report.End, total.Compliant, total.NonCompliant, total.Unknown,
+			total.ComplianceRate)
+		if err := sendEmail(config.Email, subject, body, files); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to send the email: %v", err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func sendEmail(config *EmailConfig, subject, body string, files []reportFile) error {
+	message, err := buildEmail(config, subject, body, files)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if config.Username != "" {
+		host, _, err := net.SplitHostPort(config.SMTPServer)
+		if err != nil {
+			return fmt.Errorf("invalid smtp server %s: %w", config.SMTPServer, err)
+		}
+		auth = smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+	return sendMail(config.SMTPServer, auth, config.From, config.To, message)
+}
+
+// buildEmail returns the multipart message with the body and the report files as the attachments
+func buildEmail(config *EmailConfig, subject, body string, files []reportFile) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+	fmt.Fprintf(buf, "From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\n"+
+		"Content-Type: multipart/mixed; boundary=%s\r\n\r\n", config.From, strings.Join(config.To, ", "), subject,
+		time.Now().Format(time.RFC1123Z), writer.Boundary())
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {file.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", file.name)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		// the base64 lines of the attachment must not exceed 76 characters
+		encoded := base64.StdEncoding.EncodeToString(file.content)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return nil, err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package reporting
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// the formats of the compliance report
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatPDF  = "pdf"
+)
+
+var Formats = []string{FormatJSON, FormatCSV, FormatPDF}
+
+var contentTypes = map[string]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv",
+	FormatPDF:  "application/pdf",
+}
+
+// ValidFormat returns whether the report can be written in the format
+func ValidFormat(format string) bool {
+	return utils.ContainsString(Formats, format)
+}
+
+// ContentType returns the media type of the report format
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// FileName returns the name of the report file, e.g. compliance-standard-2024-01-01-2024-01-31.csv, and the cluster
+// group is appended to the dimension, e.g. compliance-standard-prod-2024-01-01-2024-01-31.csv
+func (r *ComplianceReport) FileName(format string) string {
+	if r.ClusterGroup != "" {
+		return fmt.Sprintf("compliance-%s-%s-%s-%s.%s", r.GroupBy, r.ClusterGroup, r.Start, r.End, format)
+	}
+	return fmt.Sprintf("compliance-%s-%s-%s.%s", r.GroupBy, r.Start, r.End, format)
+}
+
+// Write encodes the report in the format
+func (r *ComplianceReport) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(r)
+	case FormatCSV:
+		return r.writeCSV(w)
+	case FormatPDF:
+		return r.writePDF(w)
+	default:
+		return fmt.Errorf("unsupported format %q of the compliance report, must be one of %v", format, Formats)
+	}
+}
+
+// header returns the columns of the report, the hub of the cluster is added since the cluster names are only unique
+// in the hub
+func (r *ComplianceReport) header() []string {
+	header := []string{r.GroupBy}
+	if r.GroupBy == GroupByCluster {
+		header = []string{GroupByHub, GroupByCluster}
+	}
+	return append(header, "policies", "clusters", "compliant", "non_compliant", "unknown", "compliance_rate")
+}
+
+func (r *ComplianceReport) record(row ComplianceReportRow) []string {
+	record := []string{row.Name}
+	if r.GroupBy == GroupByCluster {
+		record = []string{row.LeafHubName, row.Name}
+	}
+	return append(record, strconv.Itoa(row.Policies), strconv.Itoa(row.Clusters), strconv.Itoa(row.Compliant),
+		strconv.Itoa(row.NonCompliant), strconv.Itoa(row.Unknown), strconv.FormatFloat(row.ComplianceRate, 'f', 2, 64))
+}
+
+func (r *ComplianceReport) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(r.header()); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		if err := writer.Write(r.record(row)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writePDF renders the report as the fixed-width table, the long names are truncated to fit the page
+func (r *ComplianceReport) writePDF(w io.Writer) error {
+	// the width of the name columns, the numbers are right aligned in 13 characters
+	nameWidths := []int{60}
+	if r.GroupBy == GroupByCluster {
+		nameWidths = []int{30, 40}
+	}
+	formatLine := func(columns []string) string {
+		line := ""
+		for i, column := range columns {
+			if i < len(nameWidths) {
+				line += fmt.Sprintf("%-*s ", nameWidths[i], truncate(column, nameWidths[i]))
+			} else {
+				line += fmt.Sprintf("%*s ", 13, column)
+			}
+		}
+		return line
+	}
+
+	doc := &pdfDocument{}
+	doc.addLine("Fleet Compliance Report")
+	doc.addLine(fmt.Sprintf("Period: %s to %s    Group by: %s    Generated at: %s", r.Start, r.End, r.GroupBy,
+		r.GeneratedAt.Format(time.RFC3339)))
+	if r.ClusterGroup != "" {
+		doc.addLine(fmt.Sprintf("Cluster group: %s", r.ClusterGroup))
+	}
+	total := r.Total()
+	doc.addLine(fmt.Sprintf("Compliant: %d    Non compliant: %d    Unknown: %d    Compliance rate: %.2f%%",
+		total.Compliant, total.NonCompliant, total.Unknown, total.ComplianceRate))
+	doc.addLine("")
+	header := formatLine(r.header())
+	doc.addLine(header)
+	for _, row := range r.Rows {
+		// repeat the header on each page
+		if doc.pageFull() {
+			doc.addLine(header)
+		}
+		doc.addLine(formatLine(r.record(row)))
+	}
+	if len(r.Rows) == 0 {
+		doc.addLine("No compliance history in the period.")
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func truncate(value string, width int) string {
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-3]) + "..."
+}
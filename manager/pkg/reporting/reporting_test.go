@@ -0,0 +1,278 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport(groupBy string) *ComplianceReport {
+	return &ComplianceReport{
+		Start:       "2024-01-01",
+		End:         "2024-01-07",
+		GroupBy:     groupBy,
+		GeneratedAt: time.Date(2024, 1, 8, 6, 0, 0, 0, time.UTC),
+		Rows: []ComplianceReportRow{
+			{
+				Name: "cluster1", LeafHubName: "hub1", Policies: 2, Clusters: 1, Compliant: 10, NonCompliant: 4,
+				ComplianceRate: 71.42,
+			},
+			{
+				Name: "cluster (2)", LeafHubName: "hub1", Policies: 1, Clusters: 1, Compliant: 6, Unknown: 1,
+				ComplianceRate: 85.71,
+			},
+		},
+	}
+}
+
+func TestComplianceRate(t *testing.T) {
+	assert.Equal(t, 0.0, complianceRate(0, 0))
+	assert.Equal(t, 71.42, complianceRate(10, 14))
+	assert.Equal(t, 100.0, complianceRate(3, 3))
+
+	total := testReport(GroupByHub).Total()
+	assert.Equal(t, 16, total.Compliant)
+	assert.Equal(t, 4, total.NonCompliant)
+	assert.Equal(t, 1, total.Unknown)
+	assert.Equal(t, 76.19, total.ComplianceRate)
+}
+
+func TestWriteCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, testReport(GroupByCluster).Write(buf, FormatCSV))
+	assert.Equal(t, "hub,cluster,policies,clusters,compliant,non_compliant,unknown,compliance_rate\n"+
+		"hub1,cluster1,2,1,10,4,0,71.42\n"+
+		"hub1,cluster (2),1,1,6,0,1,85.71\n", buf.String())
+
+	buf.Reset()
+	report := testReport(GroupByStandard)
+	report.Rows = report.Rows[:1]
+	report.Rows[0].Name = "NIST SP 800-53"
+	require.NoError(t, report.Write(buf, FormatCSV))
+	assert.Equal(t, "standard,policies,clusters,compliant,non_compliant,unknown,compliance_rate\n"+
+		"NIST SP 800-53,2,1,10,4,0,71.42\n", buf.String())
+
+	assert.Error(t, report.Write(buf, "xlsx"))
+	assert.Equal(t, "compliance-standard-2024-01-01-2024-01-07.csv", report.FileName(FormatCSV))
+}
+
+func TestWritePDF(t *testing.T) {
+	report := testReport(GroupByCluster)
+	for i := 0; i < pdfLinesPerPage; i++ {
+		report.Rows = append(report.Rows, ComplianceReportRow{Name: "cluster-x", LeafHubName: "hub2"})
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, report.Write(buf, FormatPDF))
+	content := buf.String()
+	assert.True(t, strings.HasPrefix(content, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(content, "%%EOF\n"))
+	assert.Contains(t, content, "/Count 2")
+	assert.Contains(t, content, "(Fleet Compliance Report) '")
+	// the parentheses of the text are escaped
+	assert.Contains(t, content, `cluster \(2\)`)
+
+	// the offsets in the cross-reference table point to the objects
+	xref := strings.Index(content, "xref\n")
+	lines := strings.Split(content[xref:], "\n")
+	for i, line := range lines[3:] {
+		if !strings.HasSuffix(line, " n ") {
+			break
+		}
+		offset, err := strconv.Atoi(line[:10])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(content[offset:], fmt.Sprintf("%d 0 obj", i+1)), line)
+	}
+
+	assert.Equal(t, "a\\(b\\)\\\\c?", escapePDFText("a(b)\\cé"))
+	assert.Equal(t, "cluster...", truncate("cluster-name", 10))
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "valid config",
+			content: `
+timeZone: UTC
+reports:
+- name: weekly
+  schedule: "0 6 * * 1"
+  groupBy: standard
+  formats: ["csv", "pdf"]
+  email:
+    smtpServer: smtp.example.com:587
+    from: globalhub@example.com
+    to: ["auditor@example.com"]
+`,
+		},
+		{
+			name: "invalid schedule",
+			content: `
+reports:
+- name: weekly
+  schedule: "every monday"
+  groupBy: standard
+  email:
+    smtpServer: smtp.example.com:587
+    from: globalhub@example.com
+    to: ["auditor@example.com"]
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported group",
+			content: `
+reports:
+- name: weekly
+  schedule: "0 6 * * 1"
+  groupBy: policy
+  email:
+    smtpServer: smtp.example.com:587
+    from: globalhub@example.com
+    to: ["auditor@example.com"]
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported format",
+			content: `
+reports:
+- name: weekly
+  schedule: "0 6 * * 1"
+  groupBy: hub
+  formats: ["xlsx"]
+  objectStorage:
+    endpoint: s3.us-east-1.amazonaws.com
+    bucket: reports
+`,
+			wantErr: true,
+		},
+		{
+			name: "missing delivery",
+			content: `
+reports:
+- name: weekly
+  schedule: "0 6 * * 1"
+  groupBy: hub
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			config, err := LoadConfig(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			// the defaults are completed
+			assert.Equal(t, 7, config.Reports[0].Days)
+		})
+	}
+}
+
+func TestDeliverReport(t *testing.T) {
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploaded[r.URL.Path] = r.Header.Get("Content-Type")
+		assert.NotEmpty(t, body)
+	}))
+	defer server.Close()
+
+	var sentFrom string
+	var sentTo []string
+	var sentMessage []byte
+	sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, a)
+		sentFrom, sentTo, sentMessage = from, to, msg
+		return nil
+	}
+	defer func() { sendMail = smtp.SendMail }()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	config := ReportConfig{
+		Name:    "weekly",
+		GroupBy: GroupByCluster,
+		Days:    7,
+		Formats: []string{FormatCSV, FormatPDF},
+		ObjectStorage: &ObjectStorageConfig{
+			Endpoint: strings.TrimPrefix(server.URL, "http://"),
+			Bucket:   "reports",
+			Prefix:   "globalhub",
+			Insecure: true,
+		},
+		Email: &EmailConfig{
+			SMTPServer: "smtp.example.com:587",
+			From:       "globalhub@example.com",
+			To:         []string{"auditor@example.com", "security@example.com"},
+			Username:   "globalhub",
+			Password:   "secret",
+		},
+	}
+	s, err := newReportScheduler(&Config{TimeZone: "UTC", Reports: []ReportConfig{config}})
+	require.NoError(t, err)
+	require.NoError(t, s.deliver(context.Background(), config, testReport(GroupByCluster)))
+
+	assert.Equal(t, map[string]string{
+		"/reports/globalhub/weekly/compliance-cluster-2024-01-01-2024-01-07.csv": "text/csv",
+		"/reports/globalhub/weekly/compliance-cluster-2024-01-01-2024-01-07.pdf": "application/pdf",
+	}, uploaded)
+
+	assert.Equal(t, "globalhub@example.com", sentFrom)
+	assert.Equal(t, config.Email.To, sentTo)
+	message, err := mail.ReadMessage(bytes.NewReader(sentMessage))
+	require.NoError(t, err)
+	assert.Equal(t, "Fleet compliance report weekly: 2024-01-01 to 2024-01-07", message.Header.Get("Subject"))
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	body, err := reader.NextPart()
+	require.NoError(t, err)
+	text, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Contains(t, string(text), "Compliance rate: 76.19%")
+
+	attachment, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "compliance-cluster-2024-01-01-2024-01-07.csv", attachment.FileName())
+	encoded, err := io.ReadAll(attachment)
+	require.NoError(t, err)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(decoded), "hub,cluster,"))
+
+	attachment, err = reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "compliance-cluster-2024-01-01-2024-01-07.pdf", attachment.FileName())
+	_, err = reader.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
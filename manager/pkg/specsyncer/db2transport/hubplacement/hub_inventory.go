@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubplacement
+
+import (
+	"context"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// ListHubCandidates returns the active managed hubs along with the number of their managed clusters and the cluster
+// groups of them.
+func ListHubCandidates(ctx context.Context) ([]HubCandidate, error) {
+	db := database.GetGorm()
+	var candidates []HubCandidate
+	err := db.WithContext(ctx).Raw(`
+		SELECT
+			h.leaf_hub_name AS name,
+			COUNT(c.cluster_id) AS managed_clusters
+		FROM
+			status.leaf_hub_heartbeats h
+			LEFT JOIN status.managed_clusters c ON c.leaf_hub_name = h.leaf_hub_name AND c.deleted_at IS NULL
+		WHERE
+			h.status = ?
+		GROUP BY
+			h.leaf_hub_name`, hubmanagement.HubActive).Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var memberships []struct {
+		LeafHubName string
+		GroupName   string
+	}
+	err = db.WithContext(ctx).Raw(`
+		SELECT DISTINCT leaf_hub_name, group_name
+		FROM status.cluster_group_members
+		ORDER BY leaf_hub_name, group_name`).Scan(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	groups := map[string][]string{}
+	for _, membership := range memberships {
+		groups[membership.LeafHubName] = append(groups[membership.LeafHubName], membership.GroupName)
+	}
+	for i := range candidates {
+		candidates[i].ClusterGroups = groups[candidates[i].Name]
+	}
+	return candidates, nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubplacement
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultHubParameters is the entry of the parameters shared by all the managed hubs
+	defaultHubParameters = "*"
+	// hubNameParameter is the built-in parameter of the managed hub name
+	hubNameParameter = "name"
+)
+
+// the placeholder can only be inside the json string, so it's replaced on the json of the object
+var hubParameterPlaceholder = regexp.MustCompile(`{{\s*hub\.([A-Za-z0-9_.-]+)\s*}}`)
+
+// HubParameters are the values of the placeholders in the global resource on each managed hub, keyed by the hub name.
+// The "*" entry holds the defaults, which are overridden by the values of the specific hub.
+type HubParameters map[string]map[string]string
+
+// values returns the parameters of the hub, including the built-in hub name
+func (p HubParameters) values(hubName string) map[string]string {
+	values := map[string]string{hubNameParameter: hubName}
+	for key, val := range p[defaultHubParameters] {
+		values[key] = val
+	}
+	for key, val := range p[hubName] {
+		values[key] = val
+	}
+	return values
+}
+
+// Render returns a copy of the object with the "{{hub.<parameter>}}" placeholders replaced by the parameters of the
+// hub, the object isn't propagated to the hub if any of the parameters is missing.
+func (p HubParameters) Render(object metav1.Object, hubName string) (metav1.Object, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	values := p.values(hubName)
+	missing := map[string]struct{}{}
+	rendered := hubParameterPlaceholder.ReplaceAllStringFunc(string(data), func(placeholder string) string {
+		key := hubParameterPlaceholder.FindStringSubmatch(placeholder)[1]
+		val, found := values[key]
+		if !found {
+			missing[key] = struct{}{}
+			return placeholder
+		}
+		// escape the value within the json string
+		escaped, _ := json.Marshal(val)
+		return string(escaped[1 : len(escaped)-1])
+	})
+	if len(missing) > 0 {
+		keys := make([]string, 0, len(missing))
+		for key := range missing {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("the parameters %s of %s/%s are missing on the hub %s", strings.Join(keys, ", "),
+			object.GetNamespace(), object.GetName(), hubName)
+	}
+
+	renderedObject, ok := reflect.New(reflect.TypeOf(object).Elem()).Interface().(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %T", object)
+	}
+	if err := json.Unmarshal([]byte(rendered), renderedObject); err != nil {
+		return nil, fmt.Errorf("failed to render %s/%s on the hub %s: %w", object.GetNamespace(), object.GetName(),
+			hubName, err)
+	}
+	return renderedObject, nil
+}
@@ -0,0 +1,93 @@
+package hubplacement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	subscriptionv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestRenderHubParameters(t *testing.T) {
+	parameters := HubParameters{
+		"*":    {"channel": "stable", "quote": `a"b`},
+		"hub1": {"channel": "dev"},
+	}
+	subscription := &subscriptionv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: subscriptionv1.SubscriptionSpec{
+			Channel: "channels/{{hub.channel}}",
+			Package: "{{ hub.name }}-{{hub.quote}}",
+		},
+	}
+
+	rendered, err := parameters.Render(subscription, "hub1")
+	require.NoError(t, err)
+	renderedSubscription, ok := rendered.(*subscriptionv1.Subscription)
+	require.True(t, ok)
+	assert.Equal(t, "channels/dev", renderedSubscription.Spec.Channel)
+	assert.Equal(t, `hub1-a"b`, renderedSubscription.Spec.Package)
+	// the origin object isn't changed
+	assert.Equal(t, "channels/{{hub.channel}}", subscription.Spec.Channel)
+
+	rendered, err = parameters.Render(subscription, "hub2")
+	require.NoError(t, err)
+	assert.Equal(t, "channels/stable", rendered.(*subscriptionv1.Subscription).Spec.Channel)
+
+	// the unstructured object
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "ApplicationSet",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "openshift-gitops"},
+		"spec":       map[string]interface{}{"revision": "{{hub.revision}}"},
+	}}
+	_, err = parameters.Render(obj, "hub1")
+	assert.ErrorContains(t, err, "the parameters revision of openshift-gitops/app are missing on the hub hub1")
+
+	parameters["hub1"]["revision"] = "v1"
+	rendered, err = parameters.Render(obj, "hub1")
+	require.NoError(t, err)
+	revision, _, _ := unstructured.NestedString(rendered.(*unstructured.Unstructured).Object, "spec", "revision")
+	assert.Equal(t, "v1", revision)
+}
+
+func TestRoutingBundleWithHubParameters(t *testing.T) {
+	routingBundle := NewRoutingBundle(func() bundle.ObjectsBundle {
+		return &testObjectsBundle{}
+	})
+
+	routingBundle.AddObject(&subscriptionv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				constants.HubParametersAnnotation: `{"hub1": {"channel": "dev"}, "hub2": {"channel": "prod"}}`,
+			},
+		},
+		Spec: subscriptionv1.SubscriptionSpec{Channel: "channels/{{hub.channel}}"},
+	}, "1")
+	assert.True(t, routingBundle.HasPlacedObjects())
+
+	hubBundles := routingBundle.HubBundles([]HubCandidate{
+		{Name: "hub1", ManagedClusters: 1},
+		{Name: "hub2", ManagedClusters: 2},
+		{Name: "hub3", ManagedClusters: 3},
+	})
+	hub1Bundle := hubBundles["hub1"].(*testObjectsBundle)
+	require.Len(t, hub1Bundle.Objects, 1)
+	assert.Equal(t, "channels/dev", hub1Bundle.Objects[0].(*subscriptionv1.Subscription).Spec.Channel)
+
+	hub2Bundle := hubBundles["hub2"].(*testObjectsBundle)
+	require.Len(t, hub2Bundle.Objects, 1)
+	assert.Equal(t, "channels/prod", hub2Bundle.Objects[0].(*subscriptionv1.Subscription).Spec.Channel)
+
+	// the parameter is missing on the hub3
+	hub3Bundle := hubBundles["hub3"].(*testObjectsBundle)
+	assert.Len(t, hub3Bundle.Objects, 0)
+	assert.Len(t, hub3Bundle.DeletedObjects, 0)
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubplacement
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// HubPlacement describes which managed hubs a global resource is propagated to. It's declared by the
+// annotations on the global resource, the resource without these annotations is propagated to all the hubs.
+type HubPlacement struct {
+	// TargetHubs is the explicit list of the managed hubs, empty means all the available hubs are candidates
+	TargetHubs []string
+	// TargetClusterGroups selects the managed hubs of the cluster group members, empty means all the available hubs
+	TargetClusterGroups []string
+	// NumberOfHubs limits the count of the selected hubs, 0 means no limit
+	NumberOfHubs int
+	// ClustersPerHub is the numberOfClusters of the placement on each selected hub, nil means unchanged
+	ClustersPerHub *int32
+	// Parameters renders the resource for each selected hub, nil means the resource is propagated as it is
+	Parameters HubParameters
+	// Rollout propagates the resource to the selected hubs in waves, nil means it's propagated to them at once
+	Rollout *Rollout
+}
+
+// HubCandidate is a managed hub which is able to receive the global resources.
+type HubCandidate struct {
+	Name            string
+	ManagedClusters int
+	// ClusterGroups are the groups which have the managed clusters of the hub
+	ClusterGroups []string `gorm:"-"`
+}
+
+// FromObject parses the hub placement from the object annotations, it returns nil if the object doesn't declare
+// any hub placement. The object with only the hub parameters or the rollout is placed on all the hubs, so it's rendered
+// or rolled out for each hub.
+func FromObject(obj metav1.Object) (*HubPlacement, error) {
+	annotations := obj.GetAnnotations()
+	targetHubs, hasTargetHubs := annotations[constants.HubPlacementTargetHubsAnnotation]
+	targetGroups, hasTargetGroups := annotations[constants.HubPlacementTargetClusterGroupsAnnotation]
+	numberOfHubs, hasNumberOfHubs := annotations[constants.HubPlacementNumberOfHubsAnnotation]
+	clustersPerHub, hasClustersPerHub := annotations[constants.HubPlacementClustersPerHubAnnotation]
+	parameters, hasParameters := annotations[constants.HubParametersAnnotation]
+	rollout, err := RolloutFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !hasTargetHubs && !hasTargetGroups && !hasNumberOfHubs && !hasClustersPerHub && !hasParameters &&
+		rollout == nil {
+		return nil, nil
+	}
+
+	placement := &HubPlacement{Rollout: rollout}
+	for _, hub := range strings.Split(targetHubs, ",") {
+		if hub = strings.TrimSpace(hub); hub != "" {
+			placement.TargetHubs = append(placement.TargetHubs, hub)
+		}
+	}
+	for _, group := range strings.Split(targetGroups, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			placement.TargetClusterGroups = append(placement.TargetClusterGroups, group)
+		}
+	}
+
+	if hasNumberOfHubs {
+		val, err := strconv.Atoi(numberOfHubs)
+		if err != nil || val < 0 {
+			return nil, fmt.Errorf("invalid %s value %q of %s/%s", constants.HubPlacementNumberOfHubsAnnotation,
+				numberOfHubs, obj.GetNamespace(), obj.GetName())
+		}
+		placement.NumberOfHubs = val
+	}
+
+	if hasClustersPerHub {
+		val, err := strconv.ParseInt(clustersPerHub, 10, 32)
+		if err != nil || val < 0 {
+			return nil, fmt.Errorf("invalid %s value %q of %s/%s", constants.HubPlacementClustersPerHubAnnotation,
+				clustersPerHub, obj.GetNamespace(), obj.GetName())
+		}
+		clusters := int32(val)
+		placement.ClustersPerHub = &clusters
+	}
+
+	if hasParameters {
+		placement.Parameters = HubParameters{}
+		if err := json.Unmarshal([]byte(parameters), &placement.Parameters); err != nil {
+			return nil, fmt.Errorf("invalid %s value of %s/%s: %w", constants.HubParametersAnnotation,
+				obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+	return placement, nil
+}
+
+// Decide returns the names of the hubs selected by the placement from the candidates. The candidates are
+// ordered by the number of the managed clusters(descending) and the name, so the decision is stable.
+func (p *HubPlacement) Decide(candidates []HubCandidate) []string {
+	ordered := make([]HubCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if len(p.TargetHubs) > 0 && !utils.ContainsString(p.TargetHubs, candidate.Name) {
+			continue
+		}
+		if len(p.TargetClusterGroups) > 0 && !containsAny(candidate.ClusterGroups, p.TargetClusterGroups) {
+			continue
+		}
+		ordered = append(ordered, candidate)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].ManagedClusters != ordered[j].ManagedClusters {
+			return ordered[i].ManagedClusters > ordered[j].ManagedClusters
+		}
+		return ordered[i].Name < ordered[j].Name
+	})
+
+	if p.NumberOfHubs > 0 && len(ordered) > p.NumberOfHubs {
+		ordered = ordered[:p.NumberOfHubs]
+	}
+
+	selected := make([]string, 0, len(ordered))
+	for _, candidate := range ordered {
+		selected = append(selected, candidate.Name)
+	}
+	return selected
+}
+
+// Fingerprint identifies the candidate hubs, it's used to detect whether the decisions need to be reevaluated.
+func Fingerprint(candidates []HubCandidate) string {
+	entries := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		entry := fmt.Sprintf("%s=%d", candidate.Name, candidate.ManagedClusters)
+		if len(candidate.ClusterGroups) > 0 {
+			groups := append([]string{}, candidate.ClusterGroups...)
+			sort.Strings(groups)
+			entry = fmt.Sprintf("%s[%s]", entry, strings.Join(groups, "|"))
+		}
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func containsAny(values, targets []string) bool {
+	for _, target := range targets {
+		if utils.ContainsString(values, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package hubplacement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestFromObject(t *testing.T) {
+	three := int32(3)
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    *HubPlacement
+		expectErr   bool
+	}{
+		{
+			name:        "no placement",
+			annotations: map[string]string{"foo": "bar"},
+			expected:    nil,
+		},
+		{
+			name: "target hubs",
+			annotations: map[string]string{
+				constants.HubPlacementTargetHubsAnnotation: "hub1, hub2,,",
+			},
+			expected: &HubPlacement{TargetHubs: []string{"hub1", "hub2"}},
+		},
+		{
+			name: "target cluster groups",
+			annotations: map[string]string{
+				constants.HubPlacementTargetClusterGroupsAnnotation: "prod, canary",
+			},
+			expected: &HubPlacement{TargetClusterGroups: []string{"prod", "canary"}},
+		},
+		{
+			name: "number of hubs and clusters per hub",
+			annotations: map[string]string{
+				constants.HubPlacementNumberOfHubsAnnotation:   "2",
+				constants.HubPlacementClustersPerHubAnnotation: "3",
+			},
+			expected: &HubPlacement{NumberOfHubs: 2, ClustersPerHub: &three},
+		},
+		{
+			name: "hub parameters",
+			annotations: map[string]string{
+				constants.HubParametersAnnotation: `{"*": {"channel": "stable"}, "hub1": {"channel": "dev"}}`,
+			},
+			expected: &HubPlacement{Parameters: HubParameters{
+				"*":    {"channel": "stable"},
+				"hub1": {"channel": "dev"},
+			}},
+		},
+		{
+			name: "invalid hub parameters",
+			annotations: map[string]string{
+				constants.HubParametersAnnotation: `["hub1"]`,
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid number of hubs",
+			annotations: map[string]string{
+				constants.HubPlacementNumberOfHubsAnnotation: "-1",
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid clusters per hub",
+			annotations: map[string]string{
+				constants.HubPlacementClustersPerHubAnnotation: "abc",
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: tc.annotations}
+			placement, err := FromObject(obj)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, placement)
+		})
+	}
+}
+
+func TestDecide(t *testing.T) {
+	candidates := []HubCandidate{
+		{Name: "hub1", ManagedClusters: 5, ClusterGroups: []string{"prod"}},
+		{Name: "hub2", ManagedClusters: 10},
+		{Name: "hub3", ManagedClusters: 10, ClusterGroups: []string{"canary", "prod"}},
+		{Name: "hub4", ManagedClusters: 1, ClusterGroups: []string{"canary"}},
+	}
+
+	testCases := []struct {
+		name      string
+		placement *HubPlacement
+		expected  []string
+	}{
+		{
+			name:      "all hubs",
+			placement: &HubPlacement{},
+			expected:  []string{"hub2", "hub3", "hub1", "hub4"},
+		},
+		{
+			name:      "target hubs",
+			placement: &HubPlacement{TargetHubs: []string{"hub4", "hub1", "hub5"}},
+			expected:  []string{"hub1", "hub4"},
+		},
+		{
+			name:      "number of hubs",
+			placement: &HubPlacement{NumberOfHubs: 2},
+			expected:  []string{"hub2", "hub3"},
+		},
+		{
+			name:      "target hubs with number of hubs",
+			placement: &HubPlacement{TargetHubs: []string{"hub1", "hub3", "hub4"}, NumberOfHubs: 2},
+			expected:  []string{"hub3", "hub1"},
+		},
+		{
+			name:      "target cluster groups",
+			placement: &HubPlacement{TargetClusterGroups: []string{"canary"}},
+			expected:  []string{"hub3", "hub4"},
+		},
+		{
+			name:      "target hubs with target cluster groups",
+			placement: &HubPlacement{TargetHubs: []string{"hub1", "hub4"}, TargetClusterGroups: []string{"prod"}},
+			expected:  []string{"hub1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.placement.Decide(candidates))
+		})
+	}
+}
+
+func TestRoutingBundle(t *testing.T) {
+	routingBundle := NewRoutingBundle(func() bundle.ObjectsBundle {
+		return &testObjectsBundle{}
+	})
+
+	broadcastPlacement := &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{Name: "broadcast", Namespace: "default"},
+	}
+	placedPlacement := &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "placed",
+			Namespace: "default",
+			Annotations: map[string]string{
+				constants.HubPlacementNumberOfHubsAnnotation:   "1",
+				constants.HubPlacementClustersPerHubAnnotation: "2",
+			},
+		},
+	}
+	routingBundle.AddObject(broadcastPlacement, "1")
+	routingBundle.AddObject(placedPlacement, "2")
+
+	assert.True(t, routingBundle.HasPlacedObjects())
+	assert.Equal(t, int32(2), *placedPlacement.Spec.NumberOfClusters)
+
+	broadcastBundle := routingBundle.BroadcastBundle().(*testObjectsBundle)
+	assert.Len(t, broadcastBundle.Objects, 1)
+	assert.Equal(t, "broadcast", broadcastBundle.Objects[0].GetName())
+
+	hubBundles := routingBundle.HubBundles([]HubCandidate{
+		{Name: "hub1", ManagedClusters: 1},
+		{Name: "hub2", ManagedClusters: 2},
+	})
+	assert.Len(t, hubBundles, 2)
+
+	selected := hubBundles["hub2"].(*testObjectsBundle)
+	assert.Len(t, selected.Objects, 1)
+	assert.Len(t, selected.DeletedObjects, 0)
+
+	unselected := hubBundles["hub1"].(*testObjectsBundle)
+	assert.Len(t, unselected.Objects, 0)
+	assert.Len(t, unselected.DeletedObjects, 1)
+}
+
+type testObjectsBundle struct {
+	Objects        []metav1.Object
+	DeletedObjects []metav1.Object
+}
+
+func (b *testObjectsBundle) AddObject(object metav1.Object, objectUID string) {
+	b.Objects = append(b.Objects, object)
+}
+
+func (b *testObjectsBundle) AddDeletedObject(object metav1.Object) {
+	b.DeletedObjects = append(b.DeletedObjects, object)
+}
+
+func TestFingerprintWithClusterGroups(t *testing.T) {
+	candidates := []HubCandidate{{Name: "hub1", ManagedClusters: 5}}
+	fingerprint := Fingerprint(candidates)
+	assert.Equal(t, "hub1=5", fingerprint)
+
+	// the decisions are reevaluated once the cluster groups of the hub are changed
+	candidates[0].ClusterGroups = []string{"prod", "canary"}
+	assert.Equal(t, "hub1=5[canary|prod]", Fingerprint(candidates))
+	assert.NotEqual(t, fingerprint, Fingerprint(candidates))
+}
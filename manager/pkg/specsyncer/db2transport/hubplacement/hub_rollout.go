@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubplacement
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const DefaultRolloutSoakTime = 10 * time.Minute
+
+type RolloutPhase string
+
+const (
+	// RolloutProgressing means the waves are rolled out one by one after the soak time
+	RolloutProgressing RolloutPhase = "Progressing"
+	// RolloutHalted means the non compliant clusters increased during the soak time, the next waves aren't rolled out
+	// until the spec is changed or the state is removed
+	RolloutHalted RolloutPhase = "Halted"
+	// RolloutCompleted means all the waves are rolled out
+	RolloutCompleted RolloutPhase = "Completed"
+)
+
+// RolloutState is the progress of the rollout, it's recorded in the annotation of the global policy by the manager.
+type RolloutState struct {
+	// SpecHash identifies the spec that is rolled out, the rollout restarts from the first wave once it's changed
+	SpecHash string `json:"specHash"`
+	// Wave is the index of the last rolled out wave
+	Wave          int         `json:"wave"`
+	WaveStartTime metav1.Time `json:"waveStartTime"`
+	// BaselineNonCompliant is the number of the non compliant clusters on the rolled out hubs once the wave starts
+	BaselineNonCompliant int          `json:"baselineNonCompliant"`
+	Phase                RolloutPhase `json:"phase"`
+	Message              string       `json:"message,omitempty"`
+}
+
+// Rollout propagates the global resource to the waves of the managed hubs in order, the hubs of the later waves keep
+// the previous version of the resource until their wave is rolled out.
+type Rollout struct {
+	// Waves are the hubs of the declared waves, the hubs out of them are the last wave
+	Waves           [][]string
+	SoakTime        time.Duration
+	MaxNonCompliant int
+	// SpecHash is the hash of the current spec of the resource
+	SpecHash string
+	// State is the progress recorded by the manager, nil means the rollout isn't started
+	State *RolloutState
+}
+
+// RolloutFromObject parses the rollout from the object annotations, it returns nil if the object doesn't declare the
+// rollout waves.
+func RolloutFromObject(obj metav1.Object) (*Rollout, error) {
+	annotations := obj.GetAnnotations()
+	waves, found := annotations[constants.RolloutWavesAnnotation]
+	if !found {
+		return nil, nil
+	}
+
+	rollout := &Rollout{SoakTime: DefaultRolloutSoakTime}
+	for _, wave := range strings.Split(waves, ";") {
+		hubs := []string{}
+		for _, hub := range strings.Split(wave, ",") {
+			if hub = strings.TrimSpace(hub); hub != "" {
+				hubs = append(hubs, hub)
+			}
+		}
+		if len(hubs) > 0 {
+			rollout.Waves = append(rollout.Waves, hubs)
+		}
+	}
+
+	if val, found := annotations[constants.RolloutSoakTimeAnnotation]; found {
+		soakTime, err := time.ParseDuration(val)
+		if err != nil || soakTime < 0 {
+			return nil, fmt.Errorf("invalid %s value %q of %s/%s", constants.RolloutSoakTimeAnnotation, val,
+				obj.GetNamespace(), obj.GetName())
+		}
+		rollout.SoakTime = soakTime
+	}
+
+	if val, found := annotations[constants.RolloutMaxNonCompliantAnnotation]; found {
+		maxNonCompliant, err := strconv.Atoi(val)
+		if err != nil || maxNonCompliant < 0 {
+			return nil, fmt.Errorf("invalid %s value %q of %s/%s", constants.RolloutMaxNonCompliantAnnotation, val,
+				obj.GetNamespace(), obj.GetName())
+		}
+		rollout.MaxNonCompliant = maxNonCompliant
+	}
+
+	specHash, err := SpecHash(obj)
+	if err != nil {
+		return nil, err
+	}
+	rollout.SpecHash = specHash
+
+	if val, found := annotations[constants.RolloutStateAnnotation]; found {
+		state := &RolloutState{}
+		// the invalid state is regarded as the rollout isn't started
+		if err := json.Unmarshal([]byte(val), state); err == nil {
+			rollout.State = state
+		}
+	}
+	return rollout, nil
+}
+
+// LastWave is the index of the last wave, which contains the hubs out of the declared waves.
+func (r *Rollout) LastWave() int {
+	return len(r.Waves)
+}
+
+// CurrentWave returns the last rolled out wave of the current spec, it's the first wave if the current spec isn't
+// recorded by the state yet.
+func (r *Rollout) CurrentWave() int {
+	if r.State == nil || r.State.SpecHash != r.SpecHash {
+		return 0
+	}
+	return r.State.Wave
+}
+
+// WaveOf returns the wave of the hub.
+func (r *Rollout) WaveOf(hubName string) int {
+	for i, hubs := range r.Waves {
+		if utils.ContainsString(hubs, hubName) {
+			return i
+		}
+	}
+	return r.LastWave()
+}
+
+// Released returns true if the current spec is rolled out to the hub.
+func (r *Rollout) Released(hubName string) bool {
+	return r.WaveOf(hubName) <= r.CurrentWave()
+}
+
+// ReleasedHubs returns the hubs of the waves until the given wave, nil means all the hubs once it's the last wave.
+func (r *Rollout) ReleasedHubs(wave int) []string {
+	if wave >= r.LastWave() {
+		return nil
+	}
+	hubs := []string{}
+	for i := 0; i <= wave; i++ {
+		hubs = append(hubs, r.Waves[i]...)
+	}
+	return hubs
+}
+
+// SpecHash returns the hash of the spec of the object, it isn't changed by the metadata.
+func SpecHash(obj metav1.Object) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(fields["spec"])
+	return hex.EncodeToString(sum[:8]), nil
+}
@@ -0,0 +1,105 @@
+package hubplacement
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestRolloutFromObject(t *testing.T) {
+	obj := &metav1.ObjectMeta{Name: "test", Namespace: "default", Annotations: map[string]string{"foo": "bar"}}
+	rollout, err := RolloutFromObject(obj)
+	require.NoError(t, err)
+	assert.Nil(t, rollout)
+
+	obj.Annotations[constants.RolloutWavesAnnotation] = "canary; hub1,hub2 ;;"
+	rollout, err = RolloutFromObject(obj)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"canary"}, {"hub1", "hub2"}}, rollout.Waves)
+	assert.Equal(t, DefaultRolloutSoakTime, rollout.SoakTime)
+	assert.Equal(t, 0, rollout.MaxNonCompliant)
+	assert.Nil(t, rollout.State)
+
+	obj.Annotations[constants.RolloutSoakTimeAnnotation] = "1h"
+	obj.Annotations[constants.RolloutMaxNonCompliantAnnotation] = "3"
+	rollout, err = RolloutFromObject(obj)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, rollout.SoakTime)
+	assert.Equal(t, 3, rollout.MaxNonCompliant)
+
+	obj.Annotations[constants.RolloutSoakTimeAnnotation] = "abc"
+	_, err = RolloutFromObject(obj)
+	assert.Error(t, err)
+
+	obj.Annotations[constants.RolloutSoakTimeAnnotation] = "1h"
+	obj.Annotations[constants.RolloutMaxNonCompliantAnnotation] = "-1"
+	_, err = RolloutFromObject(obj)
+	assert.Error(t, err)
+}
+
+func TestRolloutReleased(t *testing.T) {
+	rollout := &Rollout{Waves: [][]string{{"canary"}, {"hub1", "hub2"}}, SpecHash: "v2"}
+	assert.Equal(t, 2, rollout.LastWave())
+	assert.Equal(t, 1, rollout.WaveOf("hub2"))
+	assert.Equal(t, 2, rollout.WaveOf("hub3"))
+	assert.Equal(t, []string{"canary", "hub1", "hub2"}, rollout.ReleasedHubs(1))
+	assert.Nil(t, rollout.ReleasedHubs(2))
+
+	// the rollout isn't started
+	assert.True(t, rollout.Released("canary"))
+	assert.False(t, rollout.Released("hub1"))
+
+	rollout.State = &RolloutState{SpecHash: "v2", Wave: 1}
+	assert.True(t, rollout.Released("hub1"))
+	assert.False(t, rollout.Released("hub3"))
+
+	// the state of the previous spec
+	rollout.State.SpecHash = "v1"
+	assert.False(t, rollout.Released("hub1"))
+}
+
+func TestRoutingBundleWithRollout(t *testing.T) {
+	policy := &policyv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "policy",
+			Namespace: "default",
+			Annotations: map[string]string{
+				constants.RolloutWavesAnnotation: "hub1",
+			},
+		},
+		Spec: policyv1.PolicySpec{RemediationAction: policyv1.Inform},
+	}
+	specHash, err := SpecHash(policy)
+	require.NoError(t, err)
+	state, err := json.Marshal(&RolloutState{SpecHash: specHash, Wave: 0, Phase: RolloutProgressing})
+	require.NoError(t, err)
+	policy.Annotations[constants.RolloutStateAnnotation] = string(state)
+
+	// the spec hash isn't changed by the metadata
+	updatedHash, err := SpecHash(policy)
+	require.NoError(t, err)
+	assert.Equal(t, specHash, updatedHash)
+
+	routingBundle := NewRoutingBundle(func() bundle.ObjectsBundle {
+		return &testObjectsBundle{}
+	})
+	routingBundle.AddObject(policy, "1")
+	assert.True(t, routingBundle.HasPlacedObjects())
+
+	hubBundles := routingBundle.HubBundles([]HubCandidate{
+		{Name: "hub1", ManagedClusters: 1},
+		{Name: "hub2", ManagedClusters: 2},
+	})
+	assert.Len(t, hubBundles["hub1"].(*testObjectsBundle).Objects, 1)
+	// the hub of the later wave keeps the previous version
+	assert.Len(t, hubBundles["hub2"].(*testObjectsBundle).Objects, 0)
+	assert.Len(t, hubBundles["hub2"].(*testObjectsBundle).DeletedObjects, 0)
+}
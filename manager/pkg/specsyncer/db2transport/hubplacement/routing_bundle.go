@@ -0,0 +1,126 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubplacement
+
+import (
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var _ bundle.ObjectsBundle = &RoutingBundle{}
+
+type placedObject struct {
+	object    metav1.Object
+	objectUID string
+	placement *HubPlacement
+}
+
+// RoutingBundle splits the objects into the broadcast bundle and the objects placed on the specific managed hubs.
+// The deleted objects are always broadcasted, so that they are removed from all the hubs.
+type RoutingBundle struct {
+	log              logr.Logger
+	createBundleFunc bundle.CreateBundleFunction
+	broadcastBundle  bundle.ObjectsBundle
+	placedObjects    []placedObject
+}
+
+// NewRoutingBundle creates a new routing bundle, the createBundleFunc is used to create the underlying bundles.
+func NewRoutingBundle(createBundleFunc bundle.CreateBundleFunction) *RoutingBundle {
+	return &RoutingBundle{
+		log:              ctrl.Log.WithName("hub-placement"),
+		createBundleFunc: createBundleFunc,
+		broadcastBundle:  createBundleFunc(),
+		placedObjects:    make([]placedObject, 0),
+	}
+}
+
+// AddObject adds the object to the broadcast bundle, or holds it for the hub bundles if it declares a placement.
+func (b *RoutingBundle) AddObject(object metav1.Object, objectUID string) {
+	placement, err := FromObject(object)
+	if err != nil {
+		// don't propagate the object with invalid placement, otherwise it might land on the unexpected hubs
+		b.log.Error(err, "skip the object with invalid hub placement", "namespace", object.GetNamespace(),
+			"name", object.GetName())
+		return
+	}
+	if placement == nil {
+		b.broadcastBundle.AddObject(object, objectUID)
+		return
+	}
+	applyClustersPerHub(object, placement.ClustersPerHub)
+	b.placedObjects = append(b.placedObjects, placedObject{
+		object:    object,
+		objectUID: objectUID,
+		placement: placement,
+	})
+}
+
+// AddDeletedObject adds the deleted object to the broadcast bundle.
+func (b *RoutingBundle) AddDeletedObject(object metav1.Object) {
+	b.broadcastBundle.AddDeletedObject(object)
+}
+
+// BroadcastBundle returns the bundle which is sent to all the managed hubs.
+func (b *RoutingBundle) BroadcastBundle() bundle.ObjectsBundle {
+	return b.broadcastBundle
+}
+
+// HasPlacedObjects returns true if any object is placed on the specific managed hubs.
+func (b *RoutingBundle) HasPlacedObjects() bool {
+	return len(b.placedObjects) > 0
+}
+
+// HubBundles evaluates the placed objects against the candidates and returns the bundle of each candidate hub.
+// The placed object is added as a deleted object to the hubs which aren't selected, so it's removed from the hub
+// once the hub falls out of the decisions. The object with the hub parameters is rendered for each selected hub, and
+// the rolled out object isn't sent to the selected hubs of the later waves, so they keep the previous version.
+func (b *RoutingBundle) HubBundles(candidates []HubCandidate) map[string]bundle.ObjectsBundle {
+	hubBundles := make(map[string]bundle.ObjectsBundle, len(candidates))
+	for _, candidate := range candidates {
+		hubBundles[candidate.Name] = b.createBundleFunc()
+	}
+
+	for _, placed := range b.placedObjects {
+		selectedHubs := placed.placement.Decide(candidates)
+		for hubName, hubBundle := range hubBundles {
+			if utils.ContainsString(selectedHubs, hubName) {
+				if placed.placement.Rollout != nil && !placed.placement.Rollout.Released(hubName) {
+					continue
+				}
+				object := placed.object
+				if placed.placement.Parameters != nil {
+					rendered, err := placed.placement.Parameters.Render(placed.object, hubName)
+					if err != nil {
+						b.log.Error(err, "skip the object on the hub")
+						continue
+					}
+					object = rendered
+				}
+				hubBundle.AddObject(object, placed.objectUID)
+			} else {
+				hubBundle.AddDeletedObject(placed.object)
+			}
+		}
+	}
+	return hubBundles
+}
+
+// applyClustersPerHub overrides the number of clusters selected by the placement on each managed hub.
+func applyClustersPerHub(object metav1.Object, clustersPerHub *int32) {
+	if clustersPerHub == nil {
+		return
+	}
+	switch obj := object.(type) {
+	case *clusterv1beta1.Placement:
+		obj.Spec.NumberOfClusters = clustersPerHub
+	case *placementrulev1.PlacementRule:
+		obj.Spec.ClusterReplicas = clustersPerHub
+	}
+}
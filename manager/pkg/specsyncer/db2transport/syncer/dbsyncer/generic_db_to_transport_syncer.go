@@ -2,16 +2,23 @@ package dbsyncer
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/hubplacement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -89,31 +96,185 @@ func syncObjectsBundle(ctx context.Context, producer transport.Producer, eventTy
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
 
-	if !lastUpdateTimestamp.After(*lastSyncTimestampPtr) { // sync only if something has changed
-		return false, nil
+	// the placed objects should be reevaluated once the managed hubs are changed, even if the table isn't changed
+	var candidates []hubplacement.HubCandidate
+	lastFingerprint, hasPlacedObjects := getHubFingerprint(dbTableName)
+	// sync only if something has changed, or the table is requested to resend the drifted objects
+	if !takeForcedSync(dbTableName) && !lastUpdateTimestamp.After(*lastSyncTimestampPtr) {
+		if !hasPlacedObjects {
+			return false, nil
+		}
+		candidates, err = hubplacement.ListHubCandidates(ctx)
+		if err != nil {
+			return false, fmt.Errorf("unable to list the managed hubs - %w", err)
+		}
+		if hubplacement.Fingerprint(candidates) == lastFingerprint {
+			return false, nil
+		}
 	}
 
 	// if we got here, then the last update timestamp from db is after what we have in memory.
 	// this means something has changed in db, syncing all the objects to transport.
-	bundleResult := createBundleFunc()
-	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
+	routingBundle := hubplacement.NewRoutingBundle(createBundleFunc)
+	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, routingBundle)
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
 	}
 
 	// send message to transport
-	payloadBytes, err := json.Marshal(bundleResult)
-	if err != nil {
-		return false, fmt.Errorf("failed to sync marshal bundle(%s)", eventType)
+	if err := sendObjectsBundle(ctx, producer, eventType, dbTableName, transport.Broadcast,
+		routingBundle.BroadcastBundle()); err != nil {
+		return false, err
 	}
 
-	evt := utils.ToCloudEvent(eventType, transport.Broadcast, payloadBytes)
-	if err := producer.SendEvent(ctx, evt); err != nil {
-		return false, fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
-			eventType, dbTableName, transport.Broadcast, err)
+	if routingBundle.HasPlacedObjects() {
+		if candidates == nil {
+			if candidates, err = hubplacement.ListHubCandidates(ctx); err != nil {
+				return false, fmt.Errorf("unable to list the managed hubs - %w", err)
+			}
+		}
+		for hubName, hubBundle := range routingBundle.HubBundles(candidates) {
+			if err := sendObjectsBundle(ctx, producer, eventType, dbTableName, hubName, hubBundle); err != nil {
+				return false, err
+			}
+		}
+		setHubFingerprint(dbTableName, hubplacement.Fingerprint(candidates))
+	} else {
+		deleteHubFingerprint(dbTableName)
 	}
 
 	// updating value to retain same ptr between calls
 	*lastSyncTimestampPtr = *lastUpdateTimestamp
 	return true, nil
 }
+
+// sendObjectsBundle sends each object of the bundle in its own message keyed by the destination and the object UID,
+// so the compacted spec topic retains the latest state of every object, and the managed hub joining later bootstraps
+// the desired state from the topic instead of the full bundles. The objects unchanged since the last sync aren't resent.
+func sendObjectsBundle(ctx context.Context, producer transport.Producer, eventType, dbTableName string,
+	destination string, objectsBundle bundle.ObjectsBundle,
+) error {
+	payloadBytes, err := json.Marshal(objectsBundle)
+	if err != nil {
+		return fmt.Errorf("failed to sync marshal bundle(%s)", eventType)
+	}
+	genericBundle := &spec.GenericSpecBundle{}
+	if err := json.Unmarshal(payloadBytes, genericBundle); err != nil {
+		return fmt.Errorf("failed to sync unmarshal bundle(%s)", eventType)
+	}
+
+	objectBundles := make([]*spec.GenericSpecBundle, 0, len(genericBundle.Objects)+len(genericBundle.DeletedObjects))
+	for _, obj := range genericBundle.Objects {
+		objectBundles = append(objectBundles, &spec.GenericSpecBundle{Objects: []*unstructured.Unstructured{obj}})
+	}
+	for _, obj := range genericBundle.DeletedObjects {
+		objectBundles = append(objectBundles, &spec.GenericSpecBundle{DeletedObjects: []*unstructured.Unstructured{obj}})
+	}
+
+	for _, objectBundle := range objectBundles {
+		key := objectMessageKey(destination, objectBundle)
+		objectBytes, err := json.Marshal(objectBundle)
+		if err != nil {
+			return fmt.Errorf("failed to sync marshal object(%s) of bundle(%s)", key, eventType)
+		}
+		digest := sha256.Sum256(objectBytes)
+		if lastDigest, found := getSentDigest(key); found && lastDigest == digest {
+			continue
+		}
+
+		evt := utils.ToCloudEvent(eventType, destination, objectBytes)
+		if err := producer.SendEvent(kafka_confluent.WithMessageKey(ctx, key), evt); err != nil {
+			return fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+				eventType, dbTableName, destination, err)
+		}
+		setSentDigest(key, digest)
+	}
+	return nil
+}
+
+// objectMessageKey returns the message key of the single object bundle, the UID of the live object is moved to the
+// origin annotation, while the deleted object keeps it, so both of them are compacted into the same key
+func objectMessageKey(destination string, objectBundle *spec.GenericSpecBundle) string {
+	var obj *unstructured.Unstructured
+	if len(objectBundle.Objects) > 0 {
+		obj = objectBundle.Objects[0]
+	} else {
+		obj = objectBundle.DeletedObjects[0]
+	}
+	uid, found := obj.GetAnnotations()[constants.OriginOwnerReferenceAnnotation]
+	if !found {
+		uid = string(obj.GetUID())
+	}
+	return fmt.Sprintf("%s/%s", destination, uid)
+}
+
+// sentDigests records the digest of the last object sent with each message key
+var (
+	sentDigests     = map[string][sha256.Size]byte{}
+	sentDigestsLock sync.RWMutex
+)
+
+func getSentDigest(key string) ([sha256.Size]byte, bool) {
+	sentDigestsLock.RLock()
+	defer sentDigestsLock.RUnlock()
+	digest, found := sentDigests[key]
+	return digest, found
+}
+
+func setSentDigest(key string, digest [sha256.Size]byte) {
+	sentDigestsLock.Lock()
+	defer sentDigestsLock.Unlock()
+	sentDigests[key] = digest
+}
+
+func deleteSentDigest(key string) {
+	sentDigestsLock.Lock()
+	defer sentDigestsLock.Unlock()
+	delete(sentDigests, key)
+}
+
+// forcedSyncTables records the tables which are synced in the next round even if they aren't changed
+var (
+	forcedSyncTables     = map[string]bool{}
+	forcedSyncTablesLock sync.Mutex
+)
+
+func requestForcedSync(dbTableName string) {
+	forcedSyncTablesLock.Lock()
+	defer forcedSyncTablesLock.Unlock()
+	forcedSyncTables[dbTableName] = true
+}
+
+// takeForcedSync returns true if the table is requested to sync, and clears the request
+func takeForcedSync(dbTableName string) bool {
+	forcedSyncTablesLock.Lock()
+	defer forcedSyncTablesLock.Unlock()
+	forced := forcedSyncTables[dbTableName]
+	delete(forcedSyncTables, dbTableName)
+	return forced
+}
+
+// hubFingerprints records the managed hubs that the placed objects of each table were evaluated against
+var (
+	hubFingerprints     = map[string]string{}
+	hubFingerprintsLock sync.RWMutex
+)
+
+func getHubFingerprint(dbTableName string) (string, bool) {
+	hubFingerprintsLock.RLock()
+	defer hubFingerprintsLock.RUnlock()
+	fingerprint, found := hubFingerprints[dbTableName]
+	return fingerprint, found
+}
+
+func setHubFingerprint(dbTableName, fingerprint string) {
+	hubFingerprintsLock.Lock()
+	defer hubFingerprintsLock.Unlock()
+	hubFingerprints[dbTableName] = fingerprint
+}
+
+func deleteHubFingerprint(dbTableName string) {
+	hubFingerprintsLock.Lock()
+	defer hubFingerprintsLock.Unlock()
+	delete(hubFingerprints, dbTableName)
+}
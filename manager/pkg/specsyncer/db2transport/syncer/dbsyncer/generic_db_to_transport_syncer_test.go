@@ -0,0 +1,75 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
+)
+
+type keyedMessage struct {
+	key    string
+	bundle *spec.GenericSpecBundle
+}
+
+type recordingProducer struct {
+	messages []keyedMessage
+}
+
+func (p *recordingProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	objectBundle := &spec.GenericSpecBundle{}
+	if err := json.Unmarshal(evt.Data(), objectBundle); err != nil {
+		return err
+	}
+	p.messages = append(p.messages, keyedMessage{kafka_confluent.MessageKeyFrom(ctx), objectBundle})
+	return nil
+}
+
+func newPolicy(name string, uid types.UID) *policyv1.Policy {
+	return &policyv1.Policy{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "policy.open-cluster-management.io/v1", Kind: "Policy"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: uid},
+	}
+}
+
+func TestSendObjectsBundle(t *testing.T) {
+	ctx := context.Background()
+	producer := &recordingProducer{}
+
+	objectsBundle := bundle.NewBaseObjectsBundle()
+	objectsBundle.AddObject(newPolicy("policy1", ""), "uid-1")
+	objectsBundle.AddObject(newPolicy("policy2", ""), "uid-2")
+	assert.Nil(t, sendObjectsBundle(ctx, producer, "Policies", "policies", "hub1", objectsBundle))
+	assert.Len(t, producer.messages, 2)
+	assert.Equal(t, "hub1/uid-1", producer.messages[0].key)
+	assert.Equal(t, "policy1", producer.messages[0].bundle.Objects[0].GetName())
+	assert.Equal(t, "hub1/uid-2", producer.messages[1].key)
+
+	// the unchanged objects aren't resent, the deleted object is compacted into the key of the live one
+	producer.messages = nil
+	objectsBundle = bundle.NewBaseObjectsBundle()
+	objectsBundle.AddObject(newPolicy("policy1", ""), "uid-1")
+	objectsBundle.AddDeletedObject(newPolicy("policy2", "uid-2"))
+	assert.Nil(t, sendObjectsBundle(ctx, producer, "Policies", "policies", "hub1", objectsBundle))
+	assert.Len(t, producer.messages, 1)
+	assert.Equal(t, "hub1/uid-2", producer.messages[0].key)
+	assert.Empty(t, producer.messages[0].bundle.Objects)
+	assert.Equal(t, "policy2", producer.messages[0].bundle.DeletedObjects[0].GetName())
+
+	// the same object sent to the other destination has its own key
+	producer.messages = nil
+	objectsBundle = bundle.NewBaseObjectsBundle()
+	objectsBundle.AddObject(newPolicy("policy1", ""), "uid-1")
+	assert.Nil(t, sendObjectsBundle(ctx, producer, "Policies", "policies", "hub2", objectsBundle))
+	assert.Len(t, producer.messages, 1)
+	assert.Equal(t, "hub2/uid-1", producer.messages[0].key)
+}
@@ -0,0 +1,44 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/intervalpolicy"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	managedServiceAccountsTableName = "managedserviceaccounts"
+	managedServiceAccountsMsgKey    = "ManagedServiceAccounts"
+)
+
+// AddManagedServiceAccountsDBToTransportSyncer adds managedserviceaccounts db to transport syncer to the manager. The
+// account is only applied by the hub of the cluster in its namespace, so it isn't compared by the spec drift detector,
+// which expects the resource on all the hubs it's sent to.
+func AddManagedServiceAccountsDBToTransportSyncer(mgr ctrl.Manager, specDB db.SpecDB, producer transport.Producer,
+	specSyncInterval time.Duration,
+) error {
+	createObjFunc := func() metav1.Object { return &unstructured.Unstructured{} }
+	lastSyncTimestampPtr := &time.Time{}
+
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            ctrl.Log.WithName("db-to-transport-syncer-managedserviceaccounts"),
+		intervalPolicy: intervalpolicy.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: func(ctx context.Context) (bool, error) {
+			return syncObjectsBundle(ctx, producer, managedServiceAccountsMsgKey, specDB,
+				managedServiceAccountsTableName, createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to add managedserviceaccounts db to transport syncer - %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/hubplacement"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// SpecDriftMissing means the global resource isn't reported by the managed hub
+	SpecDriftMissing = "missing"
+	// SpecDriftFailed means the global resource failed to be applied on the managed hub
+	SpecDriftFailed = "failed"
+	// SpecDriftConflicted means the global resource conflicts with the local resource of the managed hub
+	SpecDriftConflicted = "conflicted"
+	// SpecDriftUnexpected means the deleted global resource still exists on the managed hub
+	SpecDriftUnexpected = "unexpected"
+)
+
+// specDriftTables are the spec tables compared with the managed hubs, and the kind of the resources in them
+var specDriftTables = map[string]string{
+	policiesTableName:                  "Policy",
+	placementRulesTableName:            "PlacementRule",
+	placementBindingsTableName:         "PlacementBinding",
+	applicationsTableName:              "Application",
+	applicationSetsTableName:           "ApplicationSet",
+	subscriptionsTableName:             "Subscription",
+	channelsTableName:                  "Channel",
+	placementsTableName:                "Placement",
+	managedClusterSetsTableName:        "ManagedClusterSet",
+	managedClusterSetBindingsTableName: "ManagedClusterSetBinding",
+}
+
+// specObjectKey identifies the global resource on the managed hub
+type specObjectKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// hubSpecState is the global resources distributed to the managed hub, the value is the UID of the resource, which
+// is the suffix of its message key
+type hubSpecState struct {
+	expected map[specObjectKey]string
+	deleted  map[specObjectKey]string
+	// tables are the spec tables of the resources, which are resynced to remediate the drifts
+	tables map[string]string
+}
+
+func newHubSpecState() *hubSpecState {
+	return &hubSpecState{
+		expected: map[specObjectKey]string{},
+		deleted:  map[specObjectKey]string{},
+		tables:   map[string]string{},
+	}
+}
+
+// AddSpecDriftDetector adds the detector which periodically compares the global resources distributed by the global
+// hub with the apply results reported by the agents, the drifts are recorded in the database. With the remediation,
+// the drifted resources are resent to the managed hubs once the drifts are detected twice in a row.
+func AddSpecDriftDetector(mgr ctrl.Manager, specDB db.SpecDB, interval time.Duration, remediation bool) error {
+	if err := mgr.Add(&specDriftDetector{
+		log:         ctrl.Log.WithName("spec-drift-detector"),
+		specDB:      specDB,
+		interval:    interval,
+		remediation: remediation,
+	}); err != nil {
+		return fmt.Errorf("failed to add spec drift detector - %w", err)
+	}
+	return nil
+}
+
+type specDriftDetector struct {
+	log         logr.Logger
+	specDB      db.SpecDB
+	interval    time.Duration
+	remediation bool
+}
+
+func (d *specDriftDetector) Start(ctx context.Context) error {
+	d.log.Info("initialized spec drift detector", "interval", d.interval, "remediation", d.remediation)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.log.Info("stopped spec drift detector")
+			return nil
+		case <-ticker.C:
+			if err := d.detect(ctx); err != nil {
+				d.log.Error(err, "failed to detect the spec drifts")
+			}
+		}
+	}
+}
+
+func (d *specDriftDetector) detect(ctx context.Context) error {
+	candidates, err := hubplacement.ListHubCandidates(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list the managed hubs - %w", err)
+	}
+	states, err := d.distributedStates(ctx, candidates)
+	if err != nil {
+		return err
+	}
+
+	gormDB := database.GetGorm().WithContext(ctx)
+	var results []models.SpecApplyResult
+	if err := gormDB.Find(&results).Error; err != nil {
+		return fmt.Errorf("unable to list the spec apply results - %w", err)
+	}
+	resultsPerHub := map[string][]models.SpecApplyResult{}
+	for _, result := range results {
+		resultsPerHub[result.LeafHubName] = append(resultsPerHub[result.LeafHubName], result)
+	}
+
+	// the drifts of the last detection, they are remediated once they are detected again
+	var existingDrifts []models.SpecDrift
+	if err := gormDB.Find(&existingDrifts).Error; err != nil {
+		return fmt.Errorf("unable to list the spec drifts - %w", err)
+	}
+	lastDrifts := map[string]models.SpecDrift{}
+	for _, drift := range existingDrifts {
+		lastDrifts[specDriftID(drift)] = drift
+	}
+
+	now := time.Now()
+	drifts := []models.SpecDrift{}
+	monitoring.GlobalHubSpecDriftsGaugeVec.Reset()
+	for _, candidate := range candidates {
+		state := states[candidate.Name]
+		for _, drift := range computeSpecDrifts(candidate.Name, state.expected, state.deleted,
+			resultsPerHub[candidate.Name]) {
+			drift.DetectedAt = now
+			lastDrift, found := lastDrifts[specDriftID(drift)]
+			if found && lastDrift.DriftType == drift.DriftType {
+				drift.DetectedAt = lastDrift.DetectedAt
+				if d.remediation {
+					d.remediate(candidate.Name, state, drift)
+				}
+			}
+			drifts = append(drifts, drift)
+			monitoring.GlobalHubSpecDriftsGaugeVec.WithLabelValues(candidate.Name, drift.DriftType).Inc()
+		}
+	}
+
+	// replace the drifts with the latest ones, the drifts of the inactive hubs are removed as well
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.SpecDrift{}).Error; err != nil {
+			return err
+		}
+		if len(drifts) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(drifts, 100).Error
+	})
+}
+
+// distributedStates evaluates the global resources of the spec tables against the managed hubs in the same way as
+// the db to transport syncers, and returns the resources distributed to each hub.
+func (d *specDriftDetector) distributedStates(ctx context.Context, candidates []hubplacement.HubCandidate,
+) (map[string]*hubSpecState, error) {
+	states := make(map[string]*hubSpecState, len(candidates))
+	for _, candidate := range candidates {
+		states[candidate.Name] = newHubSpecState()
+	}
+
+	for tableName, kind := range specDriftTables {
+		uids := map[specObjectKey]string{}
+		routingBundle := hubplacement.NewRoutingBundle(func() bundle.ObjectsBundle {
+			return newDriftObjectsBundle(tableName, kind, uids)
+		})
+		if _, err := d.specDB.GetObjectsBundle(ctx, tableName, func() metav1.Object {
+			return &unstructured.Unstructured{}
+		}, routingBundle); err != nil {
+			return nil, fmt.Errorf("unable to get the objects of table(%s) - %w", tableName, err)
+		}
+
+		broadcastBundle := routingBundle.BroadcastBundle().(*driftObjectsBundle)
+		var hubBundles map[string]bundle.ObjectsBundle
+		if routingBundle.HasPlacedObjects() {
+			hubBundles = routingBundle.HubBundles(candidates)
+		}
+		for hubName, state := range states {
+			broadcastBundle.mergeInto(state)
+			if hubBundle, found := hubBundles[hubName]; found {
+				hubBundle.(*driftObjectsBundle).mergeInto(state)
+			}
+		}
+	}
+	return states, nil
+}
+
+// remediate forgets the messages of the drifted resource which were sent to the hub, and requests the syncer of its
+// table to resend them, so the agent applies the resource again.
+func (d *specDriftDetector) remediate(hubName string, state *hubSpecState, drift models.SpecDrift) {
+	if drift.DriftType == SpecDriftConflicted {
+		// the conflict is resolved by the owner of the local resource, resending it doesn't help
+		return
+	}
+	key := specObjectKey{Kind: drift.Kind, Namespace: drift.Namespace, Name: drift.Name}
+	uid, found := state.expected[key]
+	if !found {
+		uid = state.deleted[key]
+	}
+	if uid != "" {
+		deleteSentDigest(fmt.Sprintf("%s/%s", hubName, uid))
+		deleteSentDigest(fmt.Sprintf("%s/%s", transport.Broadcast, uid))
+	}
+	requestForcedSync(state.tables[drift.Kind])
+	d.log.Info("resend the drifted resource", "hub", hubName, "kind", drift.Kind, "namespace", drift.Namespace,
+		"name", drift.Name, "type", drift.DriftType)
+}
+
+// computeSpecDrifts compares the resources distributed to the hub with the apply results reported by the hub. The
+// resource which is neither expected nor deleted, e.g. the one of the later rollout waves, isn't regarded as drift.
+func computeSpecDrifts(hubName string, expected, deleted map[specObjectKey]string,
+	results []models.SpecApplyResult,
+) []models.SpecDrift {
+	reported := make(map[specObjectKey]models.SpecApplyResult, len(results))
+	for _, result := range results {
+		reported[specObjectKey{Kind: result.Kind, Namespace: result.Namespace, Name: result.Name}] = result
+	}
+
+	drifts := []models.SpecDrift{}
+	newDrift := func(key specObjectKey, driftType, reason string) models.SpecDrift {
+		return models.SpecDrift{
+			LeafHubName: hubName,
+			Kind:        key.Kind,
+			Namespace:   key.Namespace,
+			Name:        key.Name,
+			DriftType:   driftType,
+			Reason:      reason,
+		}
+	}
+
+	for key := range expected {
+		result, found := reported[key]
+		switch {
+		case !found:
+			drifts = append(drifts, newDrift(key, SpecDriftMissing, ""))
+		case result.Result == spec.ApplyResultFailed:
+			drifts = append(drifts, newDrift(key, SpecDriftFailed, result.Reason))
+		case result.Result == spec.ApplyResultConflicted:
+			drifts = append(drifts, newDrift(key, SpecDriftConflicted, result.Reason))
+		}
+	}
+	for key := range deleted {
+		if _, found := expected[key]; found {
+			// the resource is recreated with the same name
+			continue
+		}
+		if _, found := reported[key]; found {
+			drifts = append(drifts, newDrift(key, SpecDriftUnexpected, "the resource is deleted on the global hub"))
+		}
+	}
+	return drifts
+}
+
+func specDriftID(drift models.SpecDrift) string {
+	return fmt.Sprintf("%s/%s/%s/%s", drift.LeafHubName, drift.Kind, drift.Namespace, drift.Name)
+}
+
+var _ bundle.ObjectsBundle = &driftObjectsBundle{}
+
+// driftObjectsBundle records the keys of the objects instead of the objects themselves
+type driftObjectsBundle struct {
+	tableName string
+	kind      string
+	objects   map[specObjectKey]string
+	deleted   map[specObjectKey]string
+	// uids are shared by the bundles of the table, the placed object deleted from the unselected hubs has no UID,
+	// so it's resolved by the same object added to the selected hubs
+	uids map[specObjectKey]string
+}
+
+func newDriftObjectsBundle(tableName, kind string, uids map[specObjectKey]string) *driftObjectsBundle {
+	return &driftObjectsBundle{
+		tableName: tableName,
+		kind:      kind,
+		objects:   map[specObjectKey]string{},
+		deleted:   map[specObjectKey]string{},
+		uids:      uids,
+	}
+}
+
+func (b *driftObjectsBundle) AddObject(object metav1.Object, objectUID string) {
+	key := specObjectKey{Kind: b.kind, Namespace: object.GetNamespace(), Name: object.GetName()}
+	b.objects[key] = objectUID
+	b.uids[key] = objectUID
+}
+
+func (b *driftObjectsBundle) AddDeletedObject(object metav1.Object) {
+	b.deleted[specObjectKey{Kind: b.kind, Namespace: object.GetNamespace(), Name: object.GetName()}] =
+		string(object.GetUID())
+}
+
+func (b *driftObjectsBundle) mergeInto(state *hubSpecState) {
+	for key, uid := range b.objects {
+		state.expected[key] = uid
+	}
+	for key, uid := range b.deleted {
+		if uid == "" {
+			uid = b.uids[key]
+		}
+		state.deleted[key] = uid
+	}
+	state.tables[b.kind] = b.tableName
+}
@@ -0,0 +1,87 @@
+package dbsyncer
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestComputeSpecDrifts(t *testing.T) {
+	policyKey := func(name string) specObjectKey {
+		return specObjectKey{Kind: "Policy", Namespace: "default", Name: name}
+	}
+	policyResult := func(name, result, reason string) models.SpecApplyResult {
+		return models.SpecApplyResult{
+			LeafHubName: "hub1", Kind: "Policy", Namespace: "default", Name: name,
+			Result: result, Reason: reason,
+		}
+	}
+
+	expected := map[specObjectKey]string{
+		policyKey("applied"):    "1",
+		policyKey("missing"):    "2",
+		policyKey("failed"):     "3",
+		policyKey("conflicted"): "4",
+		policyKey("recreated"):  "5",
+	}
+	deleted := map[specObjectKey]string{
+		policyKey("removed"):   "6",
+		policyKey("deleted"):   "7",
+		policyKey("recreated"): "8",
+	}
+	results := []models.SpecApplyResult{
+		policyResult("applied", spec.ApplyResultApplied, ""),
+		policyResult("failed", spec.ApplyResultFailed, "denied"),
+		policyResult("conflicted", spec.ApplyResultConflicted, "owned by the local"),
+		policyResult("recreated", spec.ApplyResultApplied, ""),
+		policyResult("deleted", spec.ApplyResultApplied, ""),
+		// the resource of the later rollout wave isn't a drift
+		policyResult("unreleased", spec.ApplyResultApplied, ""),
+	}
+
+	drifts := computeSpecDrifts("hub1", expected, deleted, results)
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Name < drifts[j].Name })
+	require.Len(t, drifts, 4)
+
+	assert.Equal(t, "conflicted", drifts[0].Name)
+	assert.Equal(t, SpecDriftConflicted, drifts[0].DriftType)
+	assert.Equal(t, "deleted", drifts[1].Name)
+	assert.Equal(t, SpecDriftUnexpected, drifts[1].DriftType)
+	assert.Equal(t, "failed", drifts[2].Name)
+	assert.Equal(t, SpecDriftFailed, drifts[2].DriftType)
+	assert.Equal(t, "denied", drifts[2].Reason)
+	assert.Equal(t, "missing", drifts[3].Name)
+	assert.Equal(t, SpecDriftMissing, drifts[3].DriftType)
+	for _, drift := range drifts {
+		assert.Equal(t, "hub1", drift.LeafHubName)
+	}
+}
+
+func TestDriftObjectsBundleResolvesUID(t *testing.T) {
+	uids := map[specObjectKey]string{}
+	selected := newDriftObjectsBundle(policiesTableName, "Policy", uids)
+	unselected := newDriftObjectsBundle(policiesTableName, "Policy", uids)
+
+	// the placed object deleted from the unselected hub has no UID
+	unselected.AddDeletedObject(&metav1.ObjectMeta{Name: "policy", Namespace: "default"})
+	selected.AddObject(&metav1.ObjectMeta{Name: "policy", Namespace: "default"}, "1")
+
+	state := newHubSpecState()
+	unselected.mergeInto(state)
+	key := specObjectKey{Kind: "Policy", Namespace: "default", Name: "policy"}
+	assert.Equal(t, "1", state.deleted[key])
+	assert.Equal(t, policiesTableName, state.tables["Policy"])
+}
+
+func TestForcedSync(t *testing.T) {
+	assert.False(t, takeForcedSync(policiesTableName))
+	requestForcedSync(policiesTableName)
+	assert.True(t, takeForcedSync(policiesTableName))
+	assert.False(t, takeForcedSync(policiesTableName))
+}
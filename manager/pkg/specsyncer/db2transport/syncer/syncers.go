@@ -23,12 +23,14 @@ func AddDB2TransportSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfi
 		dbsyncer.AddPlacementRulesDBToTransportSyncer,
 		dbsyncer.AddPlacementBindingsDBToTransportSyncer,
 		dbsyncer.AddApplicationsDBToTransportSyncer,
+		dbsyncer.AddApplicationSetsDBToTransportSyncer,
 		dbsyncer.AddSubscriptionsDBToTransportSyncer,
 		dbsyncer.AddChannelsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterLabelsDBToTransportSyncer,
 		dbsyncer.AddPlacementsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterSetsDBToTransportSyncer,
 		dbsyncer.AddManagedClusterSetBindingsDBToTransportSyncer,
+		dbsyncer.AddManagedServiceAccountsDBToTransportSyncer,
 	}
 	specDB := gorm.NewGormSpecDB()
 	for _, addDBSyncerFunction := range addDBSyncerFunctions {
@@ -36,6 +38,13 @@ func AddDB2TransportSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfi
 			return fmt.Errorf("failed to add DB Syncer: %w", err)
 		}
 	}
+
+	if interval := managerConfig.SyncerConfig.SpecDriftDetectionInterval; interval > 0 {
+		if err := dbsyncer.AddSpecDriftDetector(mgr, specDB, interval,
+			managerConfig.SyncerConfig.SpecDriftRemediation); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,89 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+var ManagedServiceAccountGVK = schema.GroupVersionKind{
+	Group:   "authentication.open-cluster-management.io",
+	Version: "v1beta1",
+	Kind:    "ManagedServiceAccount",
+}
+
+// AddManagedServiceAccountController syncs the global ManagedServiceAccounts into the database, they're requested in
+// the namespaces of the managed clusters and applied by the hubs of the clusters. The controller is skipped if the
+// managed-serviceaccount addon isn't installed on the global hub.
+func AddManagedServiceAccountController(mgr ctrl.Manager, specDB db.SpecDB) error {
+	log := ctrl.Log.WithName("managedserviceaccounts-spec-syncer")
+	_, err := mgr.GetRESTMapper().RESTMapping(ManagedServiceAccountGVK.GroupKind(), ManagedServiceAccountGVK.Version)
+	if meta.IsNoMatchError(err) {
+		log.Info("skip the managedserviceaccount controller, the resource isn't found on the global hub")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the managedserviceaccount mapping: %w", err)
+	}
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(newManagedServiceAccount()).
+		WithEventFilter(GlobalResourcePredicate()).
+		Complete(&genericSpecToDBReconciler{
+			client:         mgr.GetClient(),
+			specDB:         specDB,
+			log:            log,
+			tableName:      "managedserviceaccounts",
+			finalizerName:  constants.GlobalHubCleanupFinalizer,
+			createInstance: func() client.Object { return newManagedServiceAccount() },
+			cleanObject:    cleanManagedServiceAccountStatus,
+			areEqual:       areManagedServiceAccountsEqual,
+		}); err != nil {
+		return fmt.Errorf("failed to add managedserviceaccount controller to the manager: %w", err)
+	}
+
+	return nil
+}
+
+func newManagedServiceAccount() *unstructured.Unstructured {
+	account := &unstructured.Unstructured{}
+	account.SetGroupVersionKind(ManagedServiceAccountGVK)
+	return account
+}
+
+// cleanManagedServiceAccountStatus removes the status, the credential of the account on the global hub isn't
+// meaningful, since the cluster isn't managed by the global hub
+func cleanManagedServiceAccountStatus(instance client.Object) {
+	account, ok := instance.(*unstructured.Unstructured)
+	if !ok {
+		panic("wrong instance passed to cleanManagedServiceAccountStatus: not a ManagedServiceAccount")
+	}
+
+	unstructured.RemoveNestedField(account.Object, "status")
+}
+
+func areManagedServiceAccountsEqual(instance1, instance2 client.Object) bool {
+	account1, ok1 := instance1.(*unstructured.Unstructured)
+	account2, ok2 := instance2.(*unstructured.Unstructured)
+
+	if !ok1 || !ok2 {
+		return false
+	}
+
+	specMatch := equality.Semantic.DeepEqual(account1.Object["spec"], account2.Object["spec"])
+	annotationsMatch := equality.Semantic.DeepEqual(instance1.GetAnnotations(), instance2.GetAnnotations())
+	labelsMatch := equality.Semantic.DeepEqual(instance1.GetLabels(), instance2.GetLabels())
+
+	return specMatch && annotationsMatch && labelsMatch
+}
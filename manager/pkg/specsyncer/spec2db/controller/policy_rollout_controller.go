@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/db"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/hubplacement"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// AddPolicyRolloutController adds the controller which rolls out the global policies with the rollout waves, it
+// records the progress in the rollout state annotation of the policy, which is propagated by the db to transport
+// syncer to the hubs of the rolled out waves.
+func AddPolicyRolloutController(mgr ctrl.Manager, _ db.SpecDB) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("policy-rollout").
+		For(&policyv1.Policy{}).
+		WithEventFilter(GlobalResourcePredicate()).
+		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			_, found := obj.GetAnnotations()[constants.RolloutWavesAnnotation]
+			return found
+		})).
+		Complete(&policyRolloutReconciler{
+			client:            mgr.GetClient(),
+			log:               ctrl.Log.WithName("policy-rollout"),
+			countNonCompliant: countNonCompliantClusters,
+			now:               time.Now,
+		}); err != nil {
+		return fmt.Errorf("failed to add policy rollout controller to the manager: %w", err)
+	}
+	return nil
+}
+
+type policyRolloutReconciler struct {
+	client client.Client
+	log    logr.Logger
+	// countNonCompliant returns the number of the non compliant clusters of the policy on the hubs, nil means all hubs
+	countNonCompliant func(ctx context.Context, policyID string, hubs []string) (int, error)
+	now               func() time.Time
+}
+
+func (r *policyRolloutReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+	policy := &policyv1.Policy{}
+	if err := r.client.Get(ctx, request.NamespacedName, policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if isInstanceBeingDeleted(policy) {
+		return ctrl.Result{}, nil
+	}
+
+	rollout, err := hubplacement.RolloutFromObject(policy)
+	if err != nil {
+		// the invalid rollout is reconciled once the annotations are changed
+		reqLogger.Error(err, "skip the policy with invalid rollout")
+		return ctrl.Result{}, nil
+	}
+	if rollout == nil {
+		return ctrl.Result{}, nil
+	}
+
+	now := r.now()
+	policyID := string(policy.GetUID())
+	state := rollout.State
+	// the rollout starts from the first wave once the spec is changed
+	if state == nil || state.SpecHash != rollout.SpecHash {
+		baseline, err := r.countNonCompliant(ctx, policyID, rollout.ReleasedHubs(0))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		reqLogger.Info("start the rollout", "waves", rollout.LastWave()+1)
+		return r.updateState(ctx, policy, &hubplacement.RolloutState{
+			SpecHash:             rollout.SpecHash,
+			Wave:                 0,
+			WaveStartTime:        metav1.NewTime(now),
+			BaselineNonCompliant: baseline,
+			Phase:                hubplacement.RolloutProgressing,
+		}, rollout.SoakTime)
+	}
+
+	if state.Phase != hubplacement.RolloutProgressing {
+		return ctrl.Result{}, nil
+	}
+
+	if soaked := now.Sub(state.WaveStartTime.Time); soaked < rollout.SoakTime {
+		return ctrl.Result{RequeueAfter: rollout.SoakTime - soaked}, nil
+	}
+
+	nonCompliant, err := r.countNonCompliant(ctx, policyID, rollout.ReleasedHubs(state.Wave))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if nonCompliant-state.BaselineNonCompliant > rollout.MaxNonCompliant {
+		state.Phase = hubplacement.RolloutHalted
+		state.Message = fmt.Sprintf("the non compliant clusters increased from %d to %d during the soak of the wave %d",
+			state.BaselineNonCompliant, nonCompliant, state.Wave)
+		reqLogger.Info("halt the rollout", "message", state.Message)
+		return r.updateState(ctx, policy, state, 0)
+	}
+
+	if state.Wave >= rollout.LastWave() {
+		state.Phase = hubplacement.RolloutCompleted
+		state.Message = ""
+		reqLogger.Info("complete the rollout")
+		return r.updateState(ctx, policy, state, 0)
+	}
+
+	state.Wave++
+	state.WaveStartTime = metav1.NewTime(now)
+	state.BaselineNonCompliant, err = r.countNonCompliant(ctx, policyID, rollout.ReleasedHubs(state.Wave))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	reqLogger.Info("roll out the next wave", "wave", state.Wave)
+	return r.updateState(ctx, policy, state, rollout.SoakTime)
+}
+
+// updateState records the state in the annotation of the policy, and requeues the policy after the soak time
+func (r *policyRolloutReconciler) updateState(ctx context.Context, policy *policyv1.Policy,
+	state *hubplacement.RolloutState, soakTime time.Duration,
+) (ctrl.Result, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	patch := client.MergeFrom(policy.DeepCopy())
+	annotations := policy.GetAnnotations()
+	annotations[constants.RolloutStateAnnotation] = string(data)
+	policy.SetAnnotations(annotations)
+	if err := r.client.Patch(ctx, policy, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the rollout state: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: soakTime}, nil
+}
+
+func countNonCompliantClusters(ctx context.Context, policyID string, hubs []string) (int, error) {
+	var count int64
+	tx := database.GetGorm().WithContext(ctx).Table("status.compliance").
+		Where("policy_id = ? AND compliance = ?", policyID, "non_compliant")
+	if hubs != nil {
+		tx = tx.Where("leaf_hub_name IN ?", hubs)
+	}
+	if err := tx.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
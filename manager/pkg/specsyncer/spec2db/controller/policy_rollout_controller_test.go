@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/specsyncer/db2transport/hubplacement"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func TestPolicyRolloutReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, policyv1.AddToScheme(scheme))
+
+	policy := &policyv1.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "policy",
+			Namespace: "default",
+			UID:       "policy-uid",
+			Labels:    map[string]string{constants.GlobalHubGlobalResourceLabel: ""},
+			Annotations: map[string]string{
+				constants.RolloutWavesAnnotation:           "canary;hub1,hub2",
+				constants.RolloutSoakTimeAnnotation:        "30m",
+				constants.RolloutMaxNonCompliantAnnotation: "1",
+			},
+		},
+		Spec: policyv1.PolicySpec{RemediationAction: policyv1.Inform},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	nonCompliant := map[string]int{}
+	r := &policyRolloutReconciler{
+		client: fakeClient,
+		log:    ctrl.Log.WithName("policy-rollout"),
+		countNonCompliant: func(ctx context.Context, policyID string, hubs []string) (int, error) {
+			assert.Equal(t, "policy-uid", policyID)
+			count := 0
+			for hub, val := range nonCompliant {
+				if hubs == nil || utils.ContainsString(hubs, hub) {
+					count += val
+				}
+			}
+			return count, nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "policy"}}
+	reconcile := func() (ctrl.Result, *hubplacement.RolloutState) {
+		result, err := r.Reconcile(ctx, request)
+		require.NoError(t, err)
+		current := &policyv1.Policy{}
+		require.NoError(t, fakeClient.Get(ctx, request.NamespacedName, current))
+		state := &hubplacement.RolloutState{}
+		require.NoError(t, json.Unmarshal([]byte(current.Annotations[constants.RolloutStateAnnotation]), state))
+		return result, state
+	}
+
+	// start with the canary wave
+	result, state := reconcile()
+	assert.Equal(t, 30*time.Minute, result.RequeueAfter)
+	assert.Equal(t, 0, state.Wave)
+	assert.Equal(t, hubplacement.RolloutProgressing, state.Phase)
+
+	// the wave is soaking
+	now = now.Add(10 * time.Minute)
+	result, state = reconcile()
+	assert.Equal(t, 20*time.Minute, result.RequeueAfter)
+	assert.Equal(t, 0, state.Wave)
+
+	// the next wave is rolled out after the soak time
+	nonCompliant["canary"] = 1
+	now = now.Add(20 * time.Minute)
+	_, state = reconcile()
+	assert.Equal(t, 1, state.Wave)
+	assert.Equal(t, 1, state.BaselineNonCompliant)
+
+	// halt the rollout once the non compliant clusters increase by more than the max
+	nonCompliant["hub1"] = 2
+	now = now.Add(30 * time.Minute)
+	result, state = reconcile()
+	assert.Equal(t, time.Duration(0), result.RequeueAfter)
+	assert.Equal(t, 1, state.Wave)
+	assert.Equal(t, hubplacement.RolloutHalted, state.Phase)
+	assert.Contains(t, state.Message, "increased from 1 to 3")
+
+	// the halted rollout isn't progressing
+	now = now.Add(time.Hour)
+	_, state = reconcile()
+	assert.Equal(t, hubplacement.RolloutHalted, state.Phase)
+
+	// the rollout restarts once the spec is changed
+	current := &policyv1.Policy{}
+	require.NoError(t, fakeClient.Get(ctx, request.NamespacedName, current))
+	current.Spec.RemediationAction = policyv1.Enforce
+	require.NoError(t, fakeClient.Update(ctx, current))
+	nonCompliant = map[string]int{}
+	_, state = reconcile()
+	assert.Equal(t, 0, state.Wave)
+	assert.Equal(t, hubplacement.RolloutProgressing, state.Phase)
+
+	// roll out the last wave and complete
+	for _, wave := range []int{1, 2} {
+		now = now.Add(30 * time.Minute)
+		_, state = reconcile()
+		assert.Equal(t, wave, state.Wave)
+	}
+	now = now.Add(30 * time.Minute)
+	_, state = reconcile()
+	assert.Equal(t, 2, state.Wave)
+	assert.Equal(t, hubplacement.RolloutCompleted, state.Phase)
+}
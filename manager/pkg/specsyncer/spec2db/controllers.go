@@ -17,14 +17,17 @@ import (
 func AddSpec2DBControllers(mgr ctrl.Manager) error {
 	addControllerFunctions := []func(ctrl.Manager, db.SpecDB) error{
 		controller.AddPolicyController,
+		controller.AddPolicyRolloutController,
 		controller.AddPlacementRuleController,
 		controller.AddPlacementBindingController,
 		controller.AddApplicationController,
+		controller.AddApplicationSetController,
 		controller.AddSubscriptionController,
 		controller.AddChannelController,
 		controller.AddManagedClusterSetController,
 		controller.AddManagedClusterSetBindingController,
 		controller.AddPlacementController,
+		controller.AddManagedServiceAccountController,
 	}
 	specDB := gorm.NewGormSpecDB()
 	for _, addControllerFunction := range addControllerFunctions {
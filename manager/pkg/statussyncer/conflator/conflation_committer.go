@@ -10,30 +10,38 @@ import (
 	"gorm.io/gorm/clause"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
 
 type MetadataFunc func() []ConflationMetadata
 
 const KafkaPartitionDelimiter = "@"
 
-func positionKey(topic string, partition int32) string {
-	return fmt.Sprintf("%s@%d", topic, partition)
+// positionKey is qualified by the kafka cluster, since the topics of the additional kafka clusters might have the same
+// names as the ones of the default cluster
+func positionKey(topic string, partition int32, ownerIdentity string) string {
+	return fmt.Sprintf("%s@%d/%s", topic, partition, ownerIdentity)
 }
 
 type ConflationCommitter struct {
 	log                  logr.Logger
 	retrieveMetadataFunc MetadataFunc
 	committedPositions   map[string]int64
+	dbMonitor            *dbmonitor.DatabaseMonitor
 }
 
-func NewKafkaConflationCommitter(metadataFunc MetadataFunc) *ConflationCommitter {
+func NewKafkaConflationCommitter(metadataFunc MetadataFunc,
+	dbMonitor *dbmonitor.DatabaseMonitor,
+) *ConflationCommitter {
 	return &ConflationCommitter{
 		log:                  ctrl.Log.WithName("kafka-conflation-committer"),
 		retrieveMetadataFunc: metadataFunc,
 		committedPositions:   map[string]int64{},
+		dbMonitor:            dbMonitor,
 	}
 }
 
@@ -46,6 +54,10 @@ func (k *ConflationCommitter) Start(ctx context.Context) error {
 		for {
 			select {
 			case <-ticker.C: // wait for next time interval
+				// stop committing during the database outage, the positions are committed once it's back
+				if !k.dbMonitor.Available() {
+					continue
+				}
 				err := k.commit()
 				if err != nil {
 					k.log.Info("failed to commit offset", "error", err)
@@ -67,6 +79,7 @@ func (k *ConflationCommitter) commit() error {
 	transPositions := metadataToCommit(transportMetadatas)
 
 	databaseTransports := []models.Transport{}
+	positionsToCommit := map[string]int64{}
 	for key, transPosition := range transPositions {
 		// skip request if already committed this offset
 		committedOffset, found := k.committedPositions[key]
@@ -75,20 +88,12 @@ func (k *ConflationCommitter) commit() error {
 		}
 
 		k.log.V(2).Info("commit offset to database", "topic@partition", key, "offset", transPosition.Offset)
-		payload, err := json.Marshal(transport.EventPosition{
-			OwnerIdentity: transPosition.OwnerIdentity,
-			Topic:         transPosition.Topic,
-			Partition:     transPosition.Partition,
-			Offset:        int64(transPosition.Offset),
-		})
+		databaseTransport, err := toTransport(transPosition)
 		if err != nil {
 			return err
 		}
-		databaseTransports = append(databaseTransports, models.Transport{
-			Name:    transPosition.Topic,
-			Payload: payload,
-		})
-		k.committedPositions[key] = int64(transPosition.Offset)
+		databaseTransports = append(databaseTransports, databaseTransport)
+		positionsToCommit[key] = int64(transPosition.Offset)
 	}
 
 	db := database.GetGorm()
@@ -107,9 +112,32 @@ func (k *ConflationCommitter) commit() error {
 			return err
 		}
 	}
+	// the positions are recorded after they're written, so the failed positions are committed in the next round
+	for key, offset := range positionsToCommit {
+		k.committedPositions[key] = offset
+	}
 	return nil
 }
 
+// toTransport returns the record of the position in the database, it's keyed by the topic and the partition, so the
+// partitions of the same topic are committed in the same batch
+func toTransport(position *transport.EventPosition) (models.Transport, error) {
+	payload, err := json.Marshal(transport.EventPosition{
+		OwnerIdentity: position.OwnerIdentity,
+		Topic:         position.Topic,
+		Partition:     position.Partition,
+		Offset:        position.Offset,
+	})
+	if err != nil {
+		return models.Transport{}, err
+	}
+	return models.Transport{
+		Name:      consumer.OffsetName(position),
+		Partition: position.Partition,
+		Payload:   payload,
+	}, nil
+}
+
 func metadataToCommit(metadataArray []ConflationMetadata) map[string]*transport.EventPosition {
 	// extract the lowest per partition in the pending bundles, the highest per partition in the processed bundles
 	pendingLowestMetadataMap := make(map[string]*transport.EventPosition)
@@ -122,7 +150,7 @@ func metadataToCommit(metadataArray []ConflationMetadata) map[string]*transport.
 
 		// metadata := bundleStatus.GetTransportMetadata()
 		position := metadata.TransportPosition()
-		key := positionKey(position.Topic, position.Partition)
+		key := positionKey(position.Topic, position.Partition, position.OwnerIdentity)
 
 		if !metadata.Processed() {
 			// this belongs to a pending bundle, update the lowest-offsets-map
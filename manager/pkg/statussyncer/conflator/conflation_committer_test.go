@@ -1,12 +1,14 @@
 package conflator
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/metadata"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
 
 func TestCommitOffset(t *testing.T) {
@@ -19,14 +21,43 @@ func TestCommitOffset(t *testing.T) {
 	metadatas := metadataToCommit(transportMetadatas)
 	assert.Greater(t, len(metadatas), 0)
 
-	assert.Contains(t, metadatas, positionKey("topic1", 0))
-	assert.Contains(t, metadatas, positionKey("topic2", 0))
-	assert.Contains(t, metadatas, positionKey("topic3", 0))
+	assert.Contains(t, metadatas, positionKey("topic1", 0, ""))
+	assert.Contains(t, metadatas, positionKey("topic2", 0, ""))
+	assert.Contains(t, metadatas, positionKey("topic3", 0, ""))
 
 	// get the offset to commit
-	assert.Equal(t, metadatas[positionKey("topic1", 0)].Offset, int64(4))
-	assert.Equal(t, metadatas[positionKey("topic2", 0)].Offset, int64(14))
-	assert.Equal(t, metadatas[positionKey("topic3", 0)].Offset, int64(6))
+	assert.Equal(t, metadatas[positionKey("topic1", 0, "")].Offset, int64(4))
+	assert.Equal(t, metadatas[positionKey("topic2", 0, "")].Offset, int64(14))
+	assert.Equal(t, metadatas[positionKey("topic3", 0, "")].Offset, int64(6))
+}
+
+func TestCommitOffsetOfAdditionalCluster(t *testing.T) {
+	transportMetadatas := getTransportMetadatas("status", []int64{1, 2, 3}, nil)
+	transportMetadatas = append(transportMetadatas, metadata.NewThresholdMetadataFromPosition(0,
+		&transport.EventPosition{OwnerIdentity: "region-east", Topic: "status", Partition: 0, Offset: 8}))
+
+	// the same topic of the additional kafka cluster is committed separately
+	metadatas := metadataToCommit(transportMetadatas)
+	assert.Len(t, metadatas, 2)
+	assert.Equal(t, int64(4), metadatas[positionKey("status", 0, "")].Offset)
+	assert.Equal(t, int64(9), metadatas[positionKey("status", 0, "region-east")].Offset)
+	assert.Equal(t, "status@region-east", consumer.OffsetName(metadatas[positionKey("status", 0, "region-east")]))
+}
+
+func TestCommitOffsetOfPartitions(t *testing.T) {
+	transportMetadatas := getTransportMetadatas("event", []int64{1, 2}, nil)
+	transportMetadatas = append(transportMetadatas, metadata.NewThresholdMetadataFromPosition(0,
+		&transport.EventPosition{Topic: "event", Partition: 1, Offset: 5}))
+
+	// the partitions of the same topic are committed as the separate records in the same batch
+	records := map[string]int32{}
+	for _, position := range metadataToCommit(transportMetadatas) {
+		record, err := toTransport(position)
+		assert.NoError(t, err)
+		assert.Equal(t, "event", record.Name)
+		records[fmt.Sprintf("%s@%d", record.Name, record.Partition)] = record.Partition
+	}
+	assert.Equal(t, map[string]int32{"event@0": 0, "event@1": 1}, records)
 }
 
 func getTransportMetadatas(topic string, processedOffsets []int64, unprocessedOffsets []int64) []ConflationMetadata {
@@ -0,0 +1,189 @@
+package conflator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// ConflationStrategy decides how the pending bundles of a complete bundle type are merged before they're persisted.
+type ConflationStrategy string
+
+const (
+	// LatestStrategy keeps only the latest pending bundle, the intermediate bundles received during the burst are
+	// conflated away, so the database is written once for them.
+	LatestStrategy ConflationStrategy = "latest"
+	// SequentialStrategy keeps up to the max queued generations of the pending bundles and persists them in order, so
+	// the intermediate states are persisted at the cost of the throughput. Once the queue is full, the newest queued
+	// bundle is replaced by the received one.
+	SequentialStrategy ConflationStrategy = "sequential"
+)
+
+const (
+	// StrategyKey is the key of the default strategy in the configmap
+	StrategyKey = "strategy"
+	// StrategyKeyPrefix is the prefix of the keys overriding the strategy of the bundle types, e.g.
+	// strategy.managedcluster
+	StrategyKeyPrefix = "strategy."
+	// MaxQueuedGenerationsKey is the key of the max queued generations of the sequential strategy in the configmap
+	MaxQueuedGenerationsKey = "maxQueuedGenerations"
+
+	DefaultMaxQueuedGenerations = 5
+	// ConflationConfigWatchInterval is the interval to read the configmap of the conflation
+	ConflationConfigWatchInterval = 15 * time.Second
+)
+
+// ConflationConfig is the conflation parameters of the complete bundles, which is changed at runtime. The delta bundles
+// are never conflated, so they aren't affected.
+type ConflationConfig struct {
+	lock                 sync.RWMutex
+	defaultStrategy      ConflationStrategy
+	strategies           map[string]ConflationStrategy // keyed by the bundle type, e.g. managedcluster
+	maxQueuedGenerations int
+}
+
+func NewConflationConfig() *ConflationConfig {
+	return &ConflationConfig{
+		defaultStrategy:      LatestStrategy,
+		strategies:           map[string]ConflationStrategy{},
+		maxQueuedGenerations: DefaultMaxQueuedGenerations,
+	}
+}
+
+// Strategy returns the strategy of the event type and the number of the generations it keeps pending
+func (c *ConflationConfig) Strategy(eventType string) (ConflationStrategy, int) {
+	if c == nil {
+		return LatestStrategy, 1
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	strategy, ok := c.strategies[strings.TrimPrefix(eventType, enum.EventTypePrefix)]
+	if !ok {
+		strategy = c.defaultStrategy
+	}
+	if strategy == LatestStrategy {
+		return strategy, 1
+	}
+	return strategy, c.maxQueuedGenerations
+}
+
+// Set applies the parameters of the configmap, the current parameters are kept if any of them is invalid. It returns
+// whether the parameters are changed.
+func (c *ConflationConfig) Set(data map[string]string) (bool, error) {
+	defaultStrategy := LatestStrategy
+	strategies := map[string]ConflationStrategy{}
+	maxQueuedGenerations := DefaultMaxQueuedGenerations
+	for key, value := range data {
+		switch {
+		case key == MaxQueuedGenerationsKey:
+			generations, err := strconv.Atoi(value)
+			if err != nil || generations < 1 {
+				return false, fmt.Errorf("the %s %q isn't a positive integer", MaxQueuedGenerationsKey, value)
+			}
+			maxQueuedGenerations = generations
+		case key == StrategyKey:
+			strategy, err := parseStrategy(value)
+			if err != nil {
+				return false, err
+			}
+			defaultStrategy = strategy
+		case strings.HasPrefix(key, StrategyKeyPrefix):
+			strategy, err := parseStrategy(value)
+			if err != nil {
+				return false, err
+			}
+			strategies[strings.TrimPrefix(key, StrategyKeyPrefix)] = strategy
+		default:
+			return false, fmt.Errorf("unknown conflation parameter %s", key)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	changed := defaultStrategy != c.defaultStrategy || maxQueuedGenerations != c.maxQueuedGenerations ||
+		!reflect.DeepEqual(strategies, c.strategies)
+	c.defaultStrategy, c.strategies, c.maxQueuedGenerations = defaultStrategy, strategies, maxQueuedGenerations
+	return changed, nil
+}
+
+func parseStrategy(value string) (ConflationStrategy, error) {
+	switch strategy := ConflationStrategy(strings.ToLower(value)); strategy {
+	case LatestStrategy, SequentialStrategy:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown conflation strategy %q, it's %s or %s", value, LatestStrategy, SequentialStrategy)
+	}
+}
+
+// ConflationConfigWatcher reads the conflation parameters from the configmap periodically, so the aggressiveness of
+// the conflation is tuned at runtime without restarting the manager. The defaults are restored once the configmap is
+// deleted.
+type ConflationConfigWatcher struct {
+	log    logr.Logger
+	reader client.Reader
+	key    types.NamespacedName
+	config *ConflationConfig
+}
+
+func NewConflationConfigWatcher(reader client.Reader, namespace, name string, config *ConflationConfig,
+) *ConflationConfigWatcher {
+	return &ConflationConfigWatcher{
+		log:    ctrl.Log.WithName("conflation-config-watcher"),
+		reader: reader,
+		key:    types.NamespacedName{Namespace: namespace, Name: name},
+		config: config,
+	}
+}
+
+// NeedLeaderElection is false since the status pipeline runs on all the replicas with the hub sharding
+func (w *ConflationConfigWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *ConflationConfigWatcher) Start(ctx context.Context) error {
+	w.log.Info("watch the conflation parameters", "configmap", w.key.String())
+	ticker := time.NewTicker(ConflationConfigWatchInterval)
+	defer ticker.Stop()
+	for {
+		if err := w.reload(ctx); err != nil {
+			w.log.Error(err, "failed to reload the conflation parameters", "configmap", w.key.String())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ConflationConfigWatcher) reload(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	data := map[string]string{}
+	if err := w.reader.Get(ctx, w.key, configMap); err == nil {
+		data = configMap.Data
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	changed, err := w.config.Set(data)
+	if err != nil {
+		return err
+	}
+	if changed {
+		w.log.Info("the conflation parameters are changed", "parameters", fmt.Sprint(data))
+	}
+	return nil
+}
@@ -19,14 +19,15 @@ type ConflationManager struct {
 	// requireInitialDependencyChecks bool
 	registrations map[string]*ConflationRegistration
 	readyQueue    *ConflationReadyQueue
+	config        *ConflationConfig
 	lock          sync.Mutex
 	statistics    *statistics.Statistics
 }
 
-// NewConflationManager creates a new instance of ConflationManager.
-func NewConflationManager(statistics *statistics.Statistics) *ConflationManager {
-	// conflationReadyQueue is shared between conflation manager and dispatcher
-	conflationUnitsReadyQueue := NewConflationReadyQueue(statistics)
+// NewConflationManager creates a new instance of ConflationManager, the ready queue has a lane for each of the workers.
+func NewConflationManager(statistics *statistics.Statistics, workers int) *ConflationManager {
+	// conflationReadyQueue is shared between conflation manager and the worker pool
+	conflationUnitsReadyQueue := NewConflationReadyQueue(statistics, workers)
 
 	return &ConflationManager{
 		log:             ctrl.Log.WithName("conflation-manager"),
@@ -34,6 +35,7 @@ func NewConflationManager(statistics *statistics.Statistics) *ConflationManager
 		// requireInitialDependencyChecks: requireInitialDependencyChecks,
 		registrations: make(map[string]*ConflationRegistration),
 		readyQueue:    conflationUnitsReadyQueue,
+		config:        NewConflationConfig(),
 		lock:          sync.Mutex{}, // lock to be used to find/create conflation units
 		statistics:    statistics,
 	}
@@ -59,6 +61,7 @@ func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
 	}
 
 	cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
+	cm.readyQueue.ReportDepth()
 }
 
 // GetTransportMetadatas provides collections of the CU's bundle transport-metadata.
@@ -79,7 +82,7 @@ func (cm *ConflationManager) getConflationUnit(leafHubName string) *ConflationUn
 		return conflationUnit
 	}
 	// otherwise, need to create conflation unit
-	conflationUnit := newConflationUnit(leafHubName, cm.readyQueue, cm.registrations, cm.statistics)
+	conflationUnit := newConflationUnit(leafHubName, cm.readyQueue, cm.config, cm.registrations, cm.statistics)
 	cm.conflationUnits[leafHubName] = conflationUnit
 	cm.statistics.IncrementNumberOfConflations()
 	return conflationUnit
@@ -88,3 +91,8 @@ func (cm *ConflationManager) getConflationUnit(leafHubName string) *ConflationUn
 func (cm *ConflationManager) GetReadyQueue() *ConflationReadyQueue {
 	return cm.readyQueue
 }
+
+// GetConfig returns the conflation parameters shared by the conflation units, which is changed at runtime.
+func (cm *ConflationManager) GetConfig() *ConflationConfig {
+	return cm.config
+}
@@ -8,12 +8,26 @@ const (
 	HubClusterHeartbeatPriority        ConflationPriority = iota
 	HubClusterInfoPriority             ConflationPriority = iota
 	ManagedClustersPriority            ConflationPriority = iota
+	ManagedClustersDeltaPriority       ConflationPriority = iota
+	ManagedClusterAddonsPriority       ConflationPriority = iota
+	ManagedServiceAccountsPriority     ConflationPriority = iota
 	LocalPolicySpecPriority            ConflationPriority = iota
+	LocalPolicySpecDeltaPriority       ConflationPriority = iota
 	LocalCompliancePriority            ConflationPriority = iota
 	LocalCompleteCompliancePriority    ConflationPriority = iota
+	PolicyReportPriority               ConflationPriority = iota
+	GatekeeperConstraintPriority       ConflationPriority = iota
+	SecurityPosturePriority            ConflationPriority = iota
+	PolicyTemplateDetailPriority       ConflationPriority = iota
 	LocalEventRootPolicyPriority       ConflationPriority = iota
 	LocalReplicatedPolicyEventPriority ConflationPriority = iota
 	LocalPlacementRulesSpecPriority    ConflationPriority = iota
+	ArgoApplicationPriority            ConflationPriority = iota
+	ArgoApplicationSetPriority         ConflationPriority = iota
+	HubResourceCountsPriority          ConflationPriority = iota
+	HubMetricsPriority                 ConflationPriority = iota
+	RegionalHubSummaryPriority         ConflationPriority = iota
+	ManagedClusterMigrationPriority    ConflationPriority = iota
 
 	// enable global resource
 	CompliancePriority         ConflationPriority = iota
@@ -25,6 +39,12 @@ const (
 	PlacementPriority         ConflationPriority = iota
 	PlacementDecisionPriority ConflationPriority = iota
 
+	ManagedClusterSetPriority        ConflationPriority = iota
+	ManagedClusterSetBindingPriority ConflationPriority = iota
+
 	SubscriptionStatusPriority ConflationPriority = iota
 	SubscriptionReportPriority ConflationPriority = iota
+
+	ResourceConflictPriority ConflationPriority = iota
+	SpecApplyResultPriority  ConflationPriority = iota
 )
@@ -17,21 +17,24 @@ type ConflationUnit struct {
 	log                  logr.Logger
 	ElementPriorityQueue []ConflationElement
 	eventTypeToPriority  map[string]ConflationPriority
-	readyQueue           *ConflationReadyQueue
+	// the lane of the ready queue the hub is assigned to
+	lane   *ReadyLane
+	config *ConflationConfig
 	// requireInitialDependencyChecks bool
 	isInReadyQueue bool
 	lock           sync.Mutex
 	statistics     *statistics.Statistics
 }
 
-func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
+func newConflationUnit(name string, readyQueue *ConflationReadyQueue, config *ConflationConfig,
 	registrations map[string]*ConflationRegistration, statistics *statistics.Statistics,
 ) *ConflationUnit {
 	conflationUnit := &ConflationUnit{
 		log:                  ctrl.Log.WithName(name),
 		ElementPriorityQueue: make([]ConflationElement, len(registrations)),
 		eventTypeToPriority:  make(map[string]ConflationPriority),
-		readyQueue:           readyQueue,
+		lane:                 readyQueue.Lane(name),
+		config:               config,
 		// requireInitialDependencyChecks: requireInitialDependencyChecks,
 		isInReadyQueue: false,
 		lock:           sync.Mutex{},
@@ -55,20 +58,28 @@ func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 // insert is an internal function, new bundles are inserted only via conflation manager.
 func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata ConflationMetadata) {
 	cu.lock.Lock()
-	defer cu.lock.Unlock()
 
 	priority := cu.eventTypeToPriority[event.Type()]
 	conflationElement := cu.ElementPriorityQueue[priority]
 	if conflationElement == nil {
+		cu.lock.Unlock()
 		cu.log.Info("the conflationElement hasn't been registered to conflation unit", "eventType", event.Type())
 		return
 	}
 
 	if !conflationElement.Predicate(eventMetadata.Version()) {
+		cu.lock.Unlock()
 		return
 	}
 
-	// for the delta element, insert the ready queue directly and process one by one
+	// for the delta element, insert the lane directly and process one by one. the lock is released before that, since
+	// the lane might be full until its worker reports the result of the previous job to this conflation unit
+	if conflationElement.SyncMode() == enum.DeltaStateMode {
+		cu.lock.Unlock()
+		conflationElement.AddToReadyQueue(event, eventMetadata, cu)
+		return
+	}
+	defer cu.lock.Unlock()
 
 	// start conflation unit metric for specific bundle type - overwrite it each time new bundle arrives
 	// cu.statistics.StartConflationUnitMetrics(event)
@@ -122,7 +133,7 @@ func (cu *ConflationUnit) addCUToReadyQueueIfNeeded() {
 	// if we reached here, CU is not in RQ, then get next element(isn't processing)
 	element := cu.getNextReadyCompleteElement()
 	if element != nil { // there is a ready to be processed bundle
-		cu.readyQueue.ConflationUnitChan <- cu // let the dispatcher know this CU has a ready to be processed bundle
+		cu.lane.pushUnit(cu) // let the worker of the lane know this CU has a ready to be processed bundle
 		cu.isInReadyQueue = true
 	}
 }
@@ -0,0 +1,45 @@
+package dependency
+
+import "github.com/stolostron/multicluster-global-hub/pkg/enum"
+
+// prerequisites are the bundle types which are processed before the bundle type of the same hub once they're received
+// together, e.g. the managed clusters before the compliance of them, so the dependent bundle doesn't refer to the
+// clusters or the policies which aren't in the database yet. Unlike the Dependency, the dependent bundle isn't
+// generated from a version of the prerequisites, so it's only held while any of them is pending or in process.
+var prerequisites = map[string][]string{
+	string(enum.ManagedClusterAddonType): {
+		string(enum.ManagedClusterType),
+	},
+	string(enum.ManagedServiceAccountType): {
+		string(enum.ManagedClusterType),
+	},
+	string(enum.LocalComplianceType): {
+		string(enum.ManagedClusterType),
+		string(enum.LocalPolicySpecType),
+	},
+	string(enum.LocalCompleteComplianceType): {
+		string(enum.ManagedClusterType),
+		string(enum.LocalPolicySpecType),
+	},
+	string(enum.LocalRootPolicyEventType): {
+		string(enum.LocalPolicySpecType),
+	},
+	string(enum.LocalReplicatedPolicyEventType): {
+		string(enum.ManagedClusterType),
+		string(enum.LocalPolicySpecType),
+	},
+	string(enum.PolicyReportType): {
+		string(enum.ManagedClusterType),
+	},
+	string(enum.ComplianceType): {
+		string(enum.ManagedClusterType),
+	},
+	string(enum.CompleteComplianceType): {
+		string(enum.ManagedClusterType),
+	},
+}
+
+// Prerequisites returns the bundle types which should be processed before the bundle type
+func Prerequisites(eventType string) []string {
+	return prerequisites[eventType]
+}
@@ -9,4 +9,7 @@ const (
 	ExactMatch DependencyType = "ExactMatch"
 	// AtLeast used to specify that dependant bundle requires at least some version of the dependency to be processed.
 	AtLeast DependencyType = "AtLeast"
+	// Checkpoint used to specify that dependant bundle is the changes since the exact version of the dependency, e.g.
+	// the delta bundle since the full bundle. it's obsolete once a newer version of the dependency is processed.
+	Checkpoint DependencyType = "Checkpoint"
 )
@@ -8,6 +8,7 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/dependency"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
@@ -20,12 +21,22 @@ type completeElement struct {
 	syncMode             enum.EventSyncMode
 	handlerFunction      EventHandleFunc
 	dependency           *dependency.Dependency
+	prerequisites        []string
 	isInProcess          bool
 	lastProcessedVersion *version.Version
 
 	// payload
 	event    *cloudevents.Event
 	metadata ConflationMetadata
+	// the metadata of the event in process, the event might be replaced by the newer one in the meantime
+	processing ConflationMetadata
+	// the generations pending behind the event with the sequential strategy, they're persisted in order
+	queued []pendingEvent
+}
+
+type pendingEvent struct {
+	event    *cloudevents.Event
+	metadata ConflationMetadata
 }
 
 func NewCompleteElement(leafHubName string, registration *ConflationRegistration) *completeElement {
@@ -37,6 +48,7 @@ func NewCompleteElement(leafHubName string, registration *ConflationRegistration
 		syncMode:             registration.syncMode,
 		handlerFunction:      registration.handleFunc,
 		dependency:           registration.dependency, // nil if there is no dependency
+		prerequisites:        dependency.Prerequisites(registration.eventType),
 		isInProcess:          false,
 		lastProcessedVersion: version.NewVersion(),
 	}
@@ -63,6 +75,9 @@ func (e *completeElement) Predicate(eventVersion *version.Version) bool {
 		if e.metadata != nil {
 			e.metadata.Version().Reset()
 		}
+		for _, pending := range e.queued {
+			pending.metadata.Version().Reset()
+		}
 		e.log.Info("resetting element processed version", "version", eventVersion)
 	}
 	e.log.V(2).Info("inserting event", "version", eventVersion)
@@ -72,8 +87,12 @@ func (e *completeElement) Predicate(eventVersion *version.Version) bool {
 		return false // we got old event, a newer (or equal) event was already processed.
 	}
 
-	// version validation 2: the insertBundle with the hold conflation bundle(memory)
-	if e.metadata != nil && !eventVersion.NewerThan(e.metadata.Version()) {
+	// version validation 2: the insertBundle with the newest hold conflation bundle(memory)
+	newest := e.metadata
+	if len(e.queued) > 0 {
+		newest = e.queued[len(e.queued)-1].metadata
+	}
+	if newest != nil && !eventVersion.NewerThan(newest.Version()) {
 		return false // insert event only if version we got is newer than what we have in memory, otherwise do nothing.
 	}
 
@@ -81,18 +100,89 @@ func (e *completeElement) Predicate(eventVersion *version.Version) bool {
 }
 
 func (e *completeElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
-	e.event = event
-	e.metadata = metadata
+	strategy, maxGenerations := cu.config.Strategy(e.eventType)
+	switch {
+	case e.event == nil:
+		e.event, e.metadata = event, metadata
+
+	case strategy == LatestStrategy:
+		// the pending events are replaced by the latest one, the event in process isn't conflated
+		e.conflate(len(e.queued))
+		e.queued = nil
+		if !e.isHeadInProcess() {
+			e.conflate(1)
+		}
+		e.event, e.metadata = event, metadata
+
+	default:
+		// make room for the received event by conflating the newest pending ones
+		for e.pendingGenerations() >= maxGenerations {
+			e.conflate(1)
+			if len(e.queued) == 0 {
+				e.event, e.metadata = nil, nil
+				break
+			}
+			e.queued = e.queued[:len(e.queued)-1]
+		}
+		if e.event == nil {
+			e.event, e.metadata = event, metadata
+		} else {
+			e.queued = append(e.queued, pendingEvent{event: event, metadata: metadata})
+		}
+	}
 
 	cu.addCUToReadyQueueIfNeeded()
 }
 
+// isHeadInProcess returns whether the event of the element is the one in process
+func (e *completeElement) isHeadInProcess() bool {
+	return e.isInProcess && e.processing == e.metadata
+}
+
+// pendingGenerations returns the number of the events waiting to be processed
+func (e *completeElement) pendingGenerations() int {
+	pending := len(e.queued)
+	if e.event != nil && !e.isHeadInProcess() {
+		pending++
+	}
+	return pending
+}
+
+// next moves the next queued event to the head, the processed metadata is kept for committing the offset if there
+// isn't any
+func (e *completeElement) next() {
+	if len(e.queued) == 0 {
+		e.event = nil
+		return
+	}
+	e.event, e.metadata = e.queued[0].event, e.queued[0].metadata
+	e.queued[0] = pendingEvent{}
+	e.queued = e.queued[1:]
+}
+
+func (e *completeElement) conflate(count int) {
+	if count == 0 {
+		return
+	}
+	monitoring.GlobalHubConflatedBundlesCounterVec.WithLabelValues(
+		strings.TrimPrefix(e.eventType, enum.EventTypePrefix)).Add(float64(count))
+}
+
 func (e *completeElement) IsReadyToProcess(cu *ConflationUnit) bool {
+	for e.isObsolete(cu) {
+		// the event is covered by the newer checkpoint, release it so that it doesn't hold the transport offset
+		e.log.V(2).Info("skip the obsolete event", "version", e.metadata.Version(),
+			"dependencyVersion", e.metadata.DependencyVersion())
+		e.metadata.MarkAsProcessed()
+		e.conflate(1)
+		e.next()
+	}
 	return e.event != nil && e.metadata != nil &&
 		!e.isInProcess &&
 		!e.metadata.Processed() &&
 		!e.isCurrentOrAnyDependencyInProcess(cu) &&
-		e.matchDependency(cu)
+		e.matchDependency(cu) &&
+		!e.isAnyPrerequisitePending(cu)
 }
 
 func (e *completeElement) ProcessJob(cu *ConflationUnit) *ConflationJob {
@@ -100,6 +190,7 @@ func (e *completeElement) ProcessJob(cu *ConflationUnit) *ConflationJob {
 		return nil
 	}
 	e.isInProcess = true
+	e.processing = e.metadata
 	return NewConflationJob(e.event, e.metadata, e.handlerFunction, cu)
 }
 
@@ -107,6 +198,7 @@ func (e *completeElement) ProcessJob(cu *ConflationUnit) *ConflationJob {
 func (e *completeElement) PostProcess(metadata ConflationMetadata, err error) {
 	// finished processing bundle
 	e.isInProcess = false
+	e.processing = nil
 
 	if err != nil {
 		e.log.Error(err, "report error for the event", "type", e.eventType, "version", metadata.Version())
@@ -119,10 +211,10 @@ func (e *completeElement) PostProcess(metadata ConflationMetadata, err error) {
 	}
 
 	// update state: update the payload
-	// if this is the same event that was processed then release bundle pointer, otherwise leave
-	// the current (newer one) as pending.
+	// if this is the same event that was processed then release bundle pointer or move to the next queued one,
+	// otherwise leave the current (newer one) as pending.
 	if metadata.Version().Equals(e.metadata.Version()) {
-		e.event = nil
+		e.next()
 	}
 }
 
@@ -165,6 +257,14 @@ func (e *completeElement) matchDependency(cu *ConflationUnit) bool {
 	}
 
 	switch e.dependency.DependencyType {
+	case dependency.Checkpoint:
+		// the manager resumes from the committed offset after it's restarted, which is after the processed checkpoint,
+		// so the changes are applied on top of the database if no checkpoint is received since the manager started
+		if completeDependency.lastProcessedVersion.Equals(version.NewVersion()) && completeDependency.event == nil {
+			return true
+		}
+		return e.metadata.DependencyVersion().EqualValue(completeDependency.lastProcessedVersion)
+
 	case dependency.ExactMatch:
 		return e.metadata.DependencyVersion().EqualValue(completeDependency.lastProcessedVersion)
 
@@ -175,3 +275,43 @@ func (e *completeElement) matchDependency(cu *ConflationUnit) bool {
 		return !e.metadata.DependencyVersion().NewerValueThan(completeDependency.lastProcessedVersion)
 	}
 }
+
+// isObsolete returns whether a newer version of the checkpoint than the one the pending event depends on has been
+// processed, the checkpoint already contains the changes of the event.
+func (e *completeElement) isObsolete(cu *ConflationUnit) bool {
+	if e.dependency == nil || e.dependency.DependencyType != dependency.Checkpoint {
+		return false
+	}
+	if e.event == nil || e.metadata == nil || e.isInProcess || e.metadata.Processed() ||
+		e.metadata.DependencyVersion() == nil {
+		return false
+	}
+
+	dependencyIndex := cu.eventTypeToPriority[e.dependency.EventType]
+	completeDependency, ok := cu.ElementPriorityQueue[dependencyIndex].(*completeElement)
+	if !ok {
+		return false
+	}
+	return completeDependency.lastProcessedVersion.NewerValueThan(e.metadata.DependencyVersion())
+}
+
+// isAnyPrerequisitePending checks if any prerequisite of the element has a bundle waiting or in process, the
+// prerequisites which aren't registered, e.g. the global resources are disabled, are skipped.
+func (e *completeElement) isAnyPrerequisitePending(cu *ConflationUnit) bool {
+	for _, eventType := range e.prerequisites {
+		priority, found := cu.eventTypeToPriority[eventType]
+		if !found {
+			continue
+		}
+		prerequisite, ok := cu.ElementPriorityQueue[priority].(*completeElement)
+		if !ok {
+			continue
+		}
+		if prerequisite.isInProcess ||
+			(prerequisite.event != nil && prerequisite.metadata != nil && !prerequisite.metadata.Processed()) {
+			e.log.V(2).Info("hold the event until the prerequisite is processed", "prerequisite", eventType)
+			return true
+		}
+	}
+	return false
+}
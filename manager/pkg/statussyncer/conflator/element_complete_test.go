@@ -0,0 +1,223 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/metadata"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestCheckpointDependency(t *testing.T) {
+	handleFunc := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	fullRegistration := NewConflationRegistration(ManagedClustersPriority, enum.CompleteStateMode,
+		string(enum.ManagedClusterType), handleFunc)
+	deltaRegistration := NewConflationRegistration(ManagedClustersDeltaPriority, enum.CompleteStateMode,
+		string(enum.ManagedClusterDeltaType), handleFunc).
+		WithDependency(dependency.NewDependency(string(enum.ManagedClusterType), dependency.Checkpoint))
+
+	fullElement := NewCompleteElement("hub1", fullRegistration)
+	deltaElement := NewCompleteElement("hub1", deltaRegistration)
+	cu := &ConflationUnit{
+		ElementPriorityQueue: []ConflationElement{fullElement, deltaElement},
+		eventTypeToPriority: map[string]ConflationPriority{
+			string(enum.ManagedClusterType):      0,
+			string(enum.ManagedClusterDeltaType): 1,
+		},
+	}
+
+	newEvent := func(eventType enum.EventType, eventVersion, dependencyVersion string) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetType(string(eventType))
+		evt.SetExtension(version.ExtVersion, eventVersion)
+		if dependencyVersion != "" {
+			evt.SetExtension(version.ExtDependencyVersion, dependencyVersion)
+		}
+		return &evt
+	}
+	setEvent := func(element *completeElement, evt *cloudevents.Event) ConflationMetadata {
+		eventMetadata := metadata.NewThresholdMetadata("hub1", 3, evt)
+		element.event = evt
+		element.metadata = eventMetadata
+		return eventMetadata
+	}
+
+	// the delta is applied on top of the database if no checkpoint is received since the manager started
+	setEvent(deltaElement, newEvent(enum.ManagedClusterDeltaType, "3.10", "2.8"))
+	assert.True(t, deltaElement.IsReadyToProcess(cu))
+
+	// the delta waits for the checkpoint it depends on
+	fullMetadata := setEvent(fullElement, newEvent(enum.ManagedClusterType, "2.8", ""))
+	assert.False(t, deltaElement.IsReadyToProcess(cu))
+
+	fullMetadata.MarkAsProcessed()
+	fullElement.PostProcess(fullMetadata, nil)
+	assert.True(t, deltaElement.IsReadyToProcess(cu))
+
+	// the delta is obsolete once a newer checkpoint is processed
+	fullMetadata = setEvent(fullElement, newEvent(enum.ManagedClusterType, "4.12", ""))
+	fullMetadata.MarkAsProcessed()
+	fullElement.PostProcess(fullMetadata, nil)
+	assert.False(t, deltaElement.IsReadyToProcess(cu))
+	assert.True(t, deltaElement.Metadata().Processed())
+	assert.Nil(t, deltaElement.event)
+
+	// the delta since the new checkpoint is ready
+	setEvent(deltaElement, newEvent(enum.ManagedClusterDeltaType, "5.13", "4.12"))
+	assert.True(t, deltaElement.IsReadyToProcess(cu))
+}
+
+func TestPrerequisites(t *testing.T) {
+	handleFunc := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	clusterElement := NewCompleteElement("hub1", NewConflationRegistration(ManagedClustersPriority,
+		enum.CompleteStateMode, string(enum.ManagedClusterType), handleFunc))
+	complianceElement := NewCompleteElement("hub1", NewConflationRegistration(LocalCompliancePriority,
+		enum.CompleteStateMode, string(enum.LocalComplianceType), handleFunc))
+	// the local policy spec isn't registered, so it isn't waited for
+	cu := &ConflationUnit{
+		ElementPriorityQueue: []ConflationElement{clusterElement, complianceElement},
+		eventTypeToPriority: map[string]ConflationPriority{
+			string(enum.ManagedClusterType):  0,
+			string(enum.LocalComplianceType): 1,
+		},
+	}
+
+	setEvent := func(element *completeElement, eventType enum.EventType, eventVersion string) ConflationMetadata {
+		evt := cloudevents.NewEvent()
+		evt.SetType(string(eventType))
+		evt.SetExtension(version.ExtVersion, eventVersion)
+		eventMetadata := metadata.NewThresholdMetadata("hub1", 3, &evt)
+		element.event = &evt
+		element.metadata = eventMetadata
+		return eventMetadata
+	}
+
+	// the compliance is processed if no clusters are received
+	setEvent(complianceElement, enum.LocalComplianceType, "1.1")
+	assert.True(t, complianceElement.IsReadyToProcess(cu))
+
+	// the compliance is held until the clusters received with it are processed
+	clusterMetadata := setEvent(clusterElement, enum.ManagedClusterType, "1.1")
+	assert.False(t, complianceElement.IsReadyToProcess(cu))
+	assert.Equal(t, clusterElement, cu.getNextReadyCompleteElement())
+
+	clusterElement.ProcessJob(cu)
+	assert.False(t, complianceElement.IsReadyToProcess(cu))
+
+	clusterMetadata.MarkAsProcessed()
+	clusterElement.PostProcess(clusterMetadata, nil)
+	assert.True(t, complianceElement.IsReadyToProcess(cu))
+	assert.Equal(t, complianceElement, cu.getNextReadyCompleteElement())
+}
+
+func TestConflationStrategy(t *testing.T) {
+	handleFunc := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	registration := NewConflationRegistration(0, enum.CompleteStateMode, string(enum.ManagedClusterType), handleFunc)
+	config := NewConflationConfig()
+	cu := newConflationUnit("hub1", NewConflationReadyQueue(statistics.NewStatistics(&statistics.StatisticsConfig{}), 1),
+		config, map[string]*ConflationRegistration{registration.eventType: registration},
+		statistics.NewStatistics(&statistics.StatisticsConfig{}))
+	element := cu.ElementPriorityQueue[0].(*completeElement)
+
+	insert := func(eventVersion string) {
+		evt := cloudevents.NewEvent()
+		evt.SetType(string(enum.ManagedClusterType))
+		evt.SetExtension(version.ExtVersion, eventVersion)
+		cu.insert(&evt, metadata.NewThresholdMetadata("hub1", 3, &evt))
+	}
+	process := func() string {
+		job, err := cu.GetNext()
+		require.NoError(t, err)
+		job.Metadata.MarkAsProcessed()
+		cu.ReportResult(job.Metadata, nil)
+		return job.Metadata.Version().String()
+	}
+	conflated := func() float64 {
+		return testutil.ToFloat64(monitoring.GlobalHubConflatedBundlesCounterVec.WithLabelValues("managedcluster"))
+	}
+
+	// the latest strategy keeps only the latest pending event, the event in process isn't conflated
+	initial := conflated()
+	insert("1.1")
+	job, err := cu.GetNext()
+	require.NoError(t, err)
+	insert("1.2")
+	insert("1.3")
+	assert.Equal(t, initial+1, conflated())
+	job.Metadata.MarkAsProcessed()
+	cu.ReportResult(job.Metadata, nil)
+	assert.Equal(t, "1.3", process())
+
+	// the sequential strategy persists the queued generations in order, and conflates the newest queued one when the
+	// queue is full
+	_, err = config.Set(map[string]string{
+		StrategyKeyPrefix + "managedcluster": string(SequentialStrategy),
+		MaxQueuedGenerationsKey:              "3",
+	})
+	require.NoError(t, err)
+	initial = conflated()
+	insert("1.4")
+	insert("1.5")
+	insert("1.6")
+	insert("1.7")
+	assert.Equal(t, initial+1, conflated())
+	assert.Equal(t, "1.4", process())
+	assert.Equal(t, "1.5", process())
+	assert.Equal(t, "1.7", process())
+	assert.Nil(t, element.event)
+	assert.True(t, element.Metadata().Processed())
+
+	// the queued generations are conflated once it's switched back to the latest strategy
+	insert("1.8")
+	insert("1.9")
+	_, err = config.Set(map[string]string{})
+	require.NoError(t, err)
+	insert("1.10")
+	assert.Equal(t, initial+3, conflated())
+	assert.Equal(t, "1.10", process())
+}
+
+func TestConflationConfig(t *testing.T) {
+	config := NewConflationConfig()
+	strategy, generations := config.Strategy(string(enum.ManagedClusterType))
+	assert.Equal(t, LatestStrategy, strategy)
+	assert.Equal(t, 1, generations)
+
+	changed, err := config.Set(map[string]string{
+		StrategyKey:                          "Sequential",
+		StrategyKeyPrefix + "managedcluster": "latest",
+	})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	strategy, generations = config.Strategy(string(enum.ManagedClusterType))
+	assert.Equal(t, LatestStrategy, strategy)
+	assert.Equal(t, 1, generations)
+	strategy, generations = config.Strategy(string(enum.LocalComplianceType))
+	assert.Equal(t, SequentialStrategy, strategy)
+	assert.Equal(t, DefaultMaxQueuedGenerations, generations)
+
+	// the current parameters are kept if any of them is invalid
+	for _, data := range []map[string]string{
+		{MaxQueuedGenerationsKey: "0"},
+		{StrategyKey: "merge"},
+		{"unknown": "latest"},
+	} {
+		_, err = config.Set(data)
+		assert.Error(t, err)
+	}
+	strategy, _ = config.Strategy(string(enum.LocalComplianceType))
+	assert.Equal(t, SequentialStrategy, strategy)
+
+	changed, err = config.Set(map[string]string{StrategyKey: "sequential", StrategyKeyPrefix + "managedcluster": "latest"})
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
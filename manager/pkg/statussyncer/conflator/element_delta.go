@@ -60,7 +60,7 @@ func (e *deltaElement) Predicate(eventVersion *version.Version) bool {
 }
 
 func (e *deltaElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
-	cu.readyQueue.DeltaEventJobChan <- NewConflationJob(event, metadata, e.handlerFunction, cu)
+	cu.lane.DeltaEventJobChan <- NewConflationJob(event, metadata, e.handlerFunction, cu)
 }
 
 // Success is to update the conflation element state after processing the event
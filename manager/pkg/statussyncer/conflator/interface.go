@@ -1,6 +1,8 @@
 package conflator
 
 import (
+	"time"
+
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
@@ -22,6 +24,8 @@ type ConflationMetadata interface {
 	DependencyVersion() *version.Version
 	// the event type
 	EventType() string
+	// the time the event is received by the manager
+	ReceivedTime() time.Time
 	// the transport offset...
 	TransportPosition() *transport.EventPosition
 }
@@ -3,6 +3,7 @@ package metadata
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/types"
@@ -29,12 +30,20 @@ type ThresholdMetadata struct {
 	eventType              string
 	eventVersion           *eventversion.Version
 	eventDependencyVersion *eventversion.Version
+
+	// the time the event is received by the manager
+	receivedTime time.Time
 }
 
-// the retry times(max) when the bundle has been failed processed
+// the retry times(max) when the bundle has been failed processed, the clusterIdentity is overridden by the identity of
+// the additional kafka cluster which the event is received from
 func NewThresholdMetadata(clusterIdentity string, max int, evt *cloudevents.Event) *ThresholdMetadata {
 	log := ctrl.Log.WithName("event-metadata")
 
+	if identity, ok := evt.Extensions()[kafka_confluent.KafkaClusterKey].(string); ok && identity != "" {
+		clusterIdentity = identity
+	}
+
 	topic, err := types.ToString(evt.Extensions()[kafka_confluent.KafkaTopicKey])
 	if err != nil {
 		log.Info("failed to parse topic from event", "error", err)
@@ -79,6 +88,7 @@ func NewThresholdMetadata(clusterIdentity string, max int, evt *cloudevents.Even
 		eventType:              evt.Type(),
 		eventVersion:           eventVersion,
 		eventDependencyVersion: dependencyVersion,
+		receivedTime:           time.Now(),
 	}
 }
 
@@ -105,6 +115,10 @@ func (s *ThresholdMetadata) MarkAsUnprocessed() {
 	s.count++
 }
 
+func (s *ThresholdMetadata) ReceivedTime() time.Time {
+	return s.receivedTime
+}
+
 func (s *ThresholdMetadata) TransportPosition() *transport.EventPosition {
 	return s.kafkaPosition
 }
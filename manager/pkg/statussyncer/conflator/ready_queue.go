@@ -1,22 +1,107 @@
 package conflator
 
 import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
-// NewConflationReadyQueue creates a new instance of ConflationReadyQueue.
-func NewConflationReadyQueue(statistics *statistics.Statistics) *ConflationReadyQueue {
-	return &ConflationReadyQueue{
-		statistics:         statistics,
-		DeltaEventJobChan:  make(chan *ConflationJob, 1000),
-		ConflationUnitChan: make(chan *ConflationUnit, 100),
+// the delta jobs buffered in each lane, the transport is blocked once the lane is full instead of holding the events
+// in memory
+const laneDeltaJobSize = 100
+
+// NewConflationReadyQueue creates a new instance of ConflationReadyQueue with the given number of lanes.
+func NewConflationReadyQueue(statistics *statistics.Statistics, lanes int) *ConflationReadyQueue {
+	if lanes < 1 {
+		lanes = 1
+	}
+	readyQueue := &ConflationReadyQueue{
+		statistics: statistics,
+		Lanes:      make([]*ReadyLane, lanes),
+	}
+	for i := range readyQueue.Lanes {
+		readyQueue.Lanes[i] = &ReadyLane{
+			DeltaEventJobChan: make(chan *ConflationJob, laneDeltaJobSize),
+			UnitReadyChan:     make(chan struct{}, 1),
+		}
 	}
+	return readyQueue
 }
 
-// ConflationReadyQueue is a queue of conflation units that have at least one bundle to process.
+// ConflationReadyQueue is a queue of conflation units and delta jobs that are ready to process. It's partitioned into
+// lanes by the leaf hub, and each lane is processed by a single worker, so the bundles of a hub are persisted in order
+// while the hubs are persisted in parallel.
 type ConflationReadyQueue struct {
 	statistics *statistics.Statistics
-	// create a Job chan for the detal event
-	DeltaEventJobChan  chan *ConflationJob
-	ConflationUnitChan chan *ConflationUnit
+	Lanes      []*ReadyLane
+}
+
+// Lane returns the lane of the leaf hub, the hub is always in the same lane.
+func (rq *ConflationReadyQueue) Lane(leafHubName string) *ReadyLane {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(leafHubName))
+	return rq.Lanes[h.Sum32()%uint32(len(rq.Lanes))]
+}
+
+// ReportDepth exposes the number of the conflation units and delta jobs waiting to be processed.
+func (rq *ConflationReadyQueue) ReportDepth() {
+	units, deltaJobs := 0, 0
+	for _, lane := range rq.Lanes {
+		units += lane.unitSize()
+		deltaJobs += len(lane.DeltaEventJobChan)
+	}
+	monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("conflation_unit").Set(float64(units))
+	monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("delta_event").Set(float64(deltaJobs))
+}
+
+// ReadyLane holds the ready conflation units and delta jobs of the hubs assigned to it.
+type ReadyLane struct {
+	// the delta jobs are processed one by one in the order they're received
+	DeltaEventJobChan chan *ConflationJob
+	// UnitReadyChan is signaled when there is a conflation unit in the lane. The conflation unit appears at most once in
+	// the lane, so adding it never blocks, even from the worker reporting the result of the lane.
+	UnitReadyChan chan struct{}
+
+	lock  sync.Mutex
+	units []*ConflationUnit
+}
+
+// PopUnit returns the next ready conflation unit of the lane, or nil if there isn't any.
+func (l *ReadyLane) PopUnit() *ConflationUnit {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if len(l.units) == 0 {
+		return nil
+	}
+	cu := l.units[0]
+	l.units[0] = nil
+	l.units = l.units[1:]
+	if len(l.units) > 0 {
+		l.signal() // the remaining units are taken in turn with the delta jobs
+	}
+	return cu
+}
+
+func (l *ReadyLane) pushUnit(cu *ConflationUnit) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.units = append(l.units, cu)
+	l.signal()
+}
+
+func (l *ReadyLane) signal() {
+	select {
+	case l.UnitReadyChan <- struct{}{}:
+	default: // already signaled
+	}
+}
+
+func (l *ReadyLane) unitSize() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.units)
 }
@@ -0,0 +1,92 @@
+package conflator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/metadata"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestReadyQueueLanes(t *testing.T) {
+	readyQueue := NewConflationReadyQueue(statistics.NewStatistics(&statistics.StatisticsConfig{}), 4)
+	require.Len(t, readyQueue.Lanes, 4)
+
+	// the hub is always in the same lane, and the hubs are spread across the lanes
+	used := map[*ReadyLane]bool{}
+	for i := 0; i < 100; i++ {
+		hubName := fmt.Sprintf("hub%d", i)
+		assert.Same(t, readyQueue.Lane(hubName), readyQueue.Lane(hubName))
+		used[readyQueue.Lane(hubName)] = true
+	}
+	assert.Len(t, used, 4)
+
+	// adding the units never blocks, the signal is kept until all of them are taken
+	lane := readyQueue.Lanes[0]
+	cu1, cu2 := &ConflationUnit{}, &ConflationUnit{}
+	lane.pushUnit(cu1)
+	lane.pushUnit(cu2)
+	<-lane.UnitReadyChan
+	assert.Same(t, cu1, lane.PopUnit())
+	<-lane.UnitReadyChan
+	assert.Same(t, cu2, lane.PopUnit())
+	assert.Nil(t, lane.PopUnit())
+	assert.Empty(t, lane.UnitReadyChan)
+}
+
+func TestDeltaJobsInOrder(t *testing.T) {
+	handleFunc := func(ctx context.Context, evt *cloudevents.Event) error { return nil }
+	registration := NewConflationRegistration(0, enum.DeltaStateMode, string(enum.LocalReplicatedPolicyEventType),
+		handleFunc)
+	readyQueue := NewConflationReadyQueue(statistics.NewStatistics(&statistics.StatisticsConfig{}), 2)
+	cu := newConflationUnit("hub1", readyQueue, NewConflationConfig(),
+		map[string]*ConflationRegistration{registration.eventType: registration},
+		statistics.NewStatistics(&statistics.StatisticsConfig{}))
+
+	for i := 1; i <= 3; i++ {
+		evt := cloudevents.NewEvent()
+		evt.SetSource("hub1")
+		evt.SetType(string(enum.LocalReplicatedPolicyEventType))
+		evt.SetExtension(version.ExtVersion, fmt.Sprintf("1.%d", i))
+		cu.insert(&evt, metadata.NewThresholdMetadata("hub1", 3, &evt))
+	}
+
+	// the delta jobs of the hub are queued to its lane in the received order
+	lane := readyQueue.Lane("hub1")
+	require.Len(t, lane.DeltaEventJobChan, 3)
+	for i := 1; i <= 3; i++ {
+		job := <-lane.DeltaEventJobChan
+		assert.Equal(t, fmt.Sprintf("1.%d", i), job.Metadata.Version().String())
+	}
+}
+
+func TestReportDepth(t *testing.T) {
+	readyQueue := NewConflationReadyQueue(statistics.NewStatistics(&statistics.StatisticsConfig{}), 2)
+	readyQueue.Lanes[0].pushUnit(&ConflationUnit{})
+	readyQueue.Lanes[1].pushUnit(&ConflationUnit{})
+	readyQueue.Lanes[1].DeltaEventJobChan <- &ConflationJob{}
+
+	readyQueue.ReportDepth()
+	assert.Equal(t, float64(2),
+		testutil.ToFloat64(monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("conflation_unit")))
+	assert.Equal(t, float64(1),
+		testutil.ToFloat64(monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("delta_event")))
+
+	// the depth decreases once the workers take the units and jobs
+	readyQueue.Lanes[0].PopUnit()
+	<-readyQueue.Lanes[1].DeltaEventJobChan
+	readyQueue.ReportDepth()
+	assert.Equal(t, float64(1),
+		testutil.ToFloat64(monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("conflation_unit")))
+	assert.Equal(t, float64(0),
+		testutil.ToFloat64(monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("delta_event")))
+}
@@ -2,61 +2,74 @@ package workerpool
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
-// NewWorker creates a new instance of DBWorker.
-// jobsQueue is initialized with capacity of 1. this is done in order to make sure dispatcher isn't blocked when calling
-// to RunAsync, otherwise it will yield cpu to other go routines.
-func NewWorker(log logr.Logger, workerID int32, dbWorkersPool chan *Worker,
-	statistics *statistics.Statistics,
+// NewWorker creates a new instance of DBWorker, which processes the jobs of the given lane one by one.
+func NewWorker(log logr.Logger, workerID int32, lane *conflator.ReadyLane, idleWorkers *atomic.Int32,
+	statistics *statistics.Statistics, dbMonitor *dbmonitor.DatabaseMonitor,
 ) *Worker {
 	return &Worker{
-		log:        log,
-		workerID:   workerID,
-		workers:    dbWorkersPool,
-		jobsQueue:  make(chan *conflator.ConflationJob, 1),
-		statistics: statistics,
+		log:         log,
+		workerID:    workerID,
+		lane:        lane,
+		idleWorkers: idleWorkers,
+		statistics:  statistics,
+		dbMonitor:   dbMonitor,
 	}
 }
 
-// Worker worker within the DB Worker pool. runs as a goroutine and invokes DBJobs.
+// Worker worker within the DB Worker pool. runs as a goroutine and invokes the DBJobs of its lane.
 type Worker struct {
-	log        logr.Logger
-	workerID   int32
-	workers    chan *Worker
-	jobsQueue  chan *conflator.ConflationJob
-	statistics *statistics.Statistics
+	log         logr.Logger
+	workerID    int32
+	lane        *conflator.ReadyLane
+	idleWorkers *atomic.Int32
+	statistics  *statistics.Statistics
+	dbMonitor   *dbmonitor.DatabaseMonitor
 }
 
-// RunAsync runs DBJob and reports status to the given CU. once the job processing is finished worker returns to the
-// worker pool in order to run more jobs.
-func (worker *Worker) RunAsync(job *conflator.ConflationJob) {
-	worker.jobsQueue <- job
-}
+// errDatabaseUnavailable stops retrying the job, which is held until the database is back
+var errDatabaseUnavailable = errors.New("the database is unavailable")
 
 func (worker *Worker) start(ctx context.Context) {
 	worker.log.Info("started worker", "WorkerID", worker.workerID)
 	for {
-		// add worker into the dbWorkerPool to mark this worker as available.
-		// this is done in each iteration after the worker finished handling a job (or at startup),
-		// for receiving a new job to handle.
-		worker.workers <- worker
-		worker.statistics.SetNumberOfAvailableDBWorkers(len(worker.workers))
+		// mark this worker as available while waiting for the jobs of the lane
+		worker.statistics.SetNumberOfAvailableDBWorkers(int(worker.idleWorkers.Add(1)))
 
 		select {
 		case <-ctx.Done(): // we have received a signal to stop
-			close(worker.jobsQueue)
 			return
 
-		case job := <-worker.jobsQueue: // DBWorker received a job request.
+		case job := <-worker.lane.DeltaEventJobChan: // the delta jobs of the lane are handled in the received order
+			worker.idleWorkers.Add(-1)
+			worker.handleJob(ctx, job)
+
+		case <-worker.lane.UnitReadyChan:
+			worker.idleWorkers.Add(-1)
+			conflationUnit := worker.lane.PopUnit()
+			if conflationUnit == nil {
+				continue
+			}
+			job, err := conflationUnit.GetNext()
+			if err != nil {
+				worker.log.V(2).Info(err.Error()) // don't need to throw the error when bundle is not ready
+				continue
+			}
 			worker.handleJob(ctx, job)
 		}
 	}
@@ -64,35 +77,21 @@ func (worker *Worker) start(ctx context.Context) {
 
 func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJob) {
 	startTime := time.Now()
-	conn := database.GetConn()
-
-	err := database.Lock(conn)
 
-	defer database.Unlock(conn)
-
-	if err != nil {
-		worker.log.Error(err, "failed to get db lock")
-		return
+	var err error
+	for {
+		// hold the job until the database is back instead of failing it during the outage, so the bundles are still
+		// persisted in order
+		if err = worker.dbMonitor.WaitAvailable(ctx); err != nil {
+			break
+		}
+		if err = worker.handleJobWithRetry(ctx, job); !errors.Is(err, errDatabaseUnavailable) {
+			break
+		}
+		worker.log.Info("hold the DB job until the database is available", "LF", job.Event.Source(),
+			"WorkerID", worker.workerID, "type", job.Event.Type())
 	}
 
-	// handle the event until it's metadata is marked as processed
-	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, true,
-		func(ctx context.Context) (bool, error) {
-			err = job.Handle(ctx, job.Event) // db connection released to pool when done
-			if err != nil {
-				job.Metadata.MarkAsUnprocessed()
-				worker.log.Error(err, "failed to handle event", "type", job.Event.Type())
-			} else {
-				job.Metadata.MarkAsProcessed()
-			}
-			// retrying
-			if !job.Metadata.Processed() {
-				return false, nil
-			}
-			// success or up to retry threshold
-			return job.Metadata.Processed(), err
-		})
-
 	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), err)
 
 	job.Reporter.ReportResult(job.Metadata, err)
@@ -109,3 +108,63 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 			"version", job.Metadata.Version())
 	}
 }
+
+func (worker *Worker) handleJobWithRetry(ctx context.Context, job *conflator.ConflationJob) error {
+	conn := database.GetConn()
+
+	err := database.Lock(conn)
+
+	defer database.Unlock(conn)
+
+	if err != nil {
+		if !worker.dbMonitor.Check(ctx) {
+			return errDatabaseUnavailable
+		}
+		worker.log.Error(err, "failed to get db lock")
+		return err
+	}
+
+	// handle the event until it's metadata is marked as processed
+	eventType := strings.TrimPrefix(job.Event.Type(), enum.EventTypePrefix)
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, 5*time.Minute, true,
+		func(ctx context.Context) (bool, error) {
+			return worker.handleEvent(ctx, job, eventType)
+		})
+}
+
+// handleEvent invokes the handler of the job once and records the pipeline metrics of the result, it returns whether
+// the job is done and the error to stop the retrying
+func (worker *Worker) handleEvent(ctx context.Context, job *conflator.ConflationJob, eventType string) (bool, error) {
+	writeStartTime := time.Now()
+	// the transient failures are retried by the circuit breaker shared by the database access
+	err := worker.dbMonitor.Do(ctx, func(ctx context.Context) error {
+		return job.Handle(ctx, job.Event) // db connection released to pool when done
+	})
+	if errors.Is(err, database.ErrCircuitOpen) {
+		return false, errDatabaseUnavailable
+	}
+	monitoring.GlobalHubDatabaseWriteDurationHistogramVec.WithLabelValues(eventType).Observe(
+		time.Since(writeStartTime).Seconds())
+	if err != nil {
+		job.Metadata.MarkAsUnprocessed()
+		monitoring.GlobalHubStatusHandlerErrorsCounterVec.WithLabelValues(eventType).Inc()
+		if !worker.dbMonitor.Check(ctx) {
+			return false, errDatabaseUnavailable
+		}
+		worker.log.Error(err, "failed to handle event", "type", job.Event.Type())
+	} else {
+		job.Metadata.MarkAsProcessed()
+		monitoring.GlobalHubStatusLastProcessedGaugeVec.WithLabelValues(job.Event.Source()).SetToCurrentTime()
+		monitoring.GlobalHubBundlesPersistedCounterVec.WithLabelValues(eventType).Inc()
+		if receivedTime := job.Metadata.ReceivedTime(); !receivedTime.IsZero() {
+			monitoring.GlobalHubBundleProcessingDurationHistogramVec.WithLabelValues(eventType).Observe(
+				time.Since(receivedTime).Seconds())
+		}
+	}
+	// retrying
+	if !job.Metadata.Processed() {
+		return false, nil
+	}
+	// success or up to retry threshold
+	return job.Metadata.Processed(), err
+}
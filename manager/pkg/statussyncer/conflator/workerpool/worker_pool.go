@@ -2,78 +2,59 @@ package workerpool
 
 import (
 	"context"
-	"fmt"
-	"time"
+	"sync/atomic"
 
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
-// DBWorkerPool pool that registers all db workers and the assigns db jobs to available workers.
+// the minimal number of the db workers
+const minPoolSize = 5
+
+// PoolSize returns the number of the db workers by the size of the database connection pool.
+func PoolSize(maxOpenConns int) int {
+	if maxOpenConns < minPoolSize {
+		return minPoolSize
+	}
+	return maxOpenConns
+}
+
+// DBWorkerPool pool that starts a db worker for each lane of the ready queue. The leaf hub is always in the same lane,
+// so its jobs are processed in order by the same worker, while the jobs of the hubs in the other lanes are processed
+// in parallel.
 type DBWorkerPool struct {
-	log        logr.Logger
-	statistics *statistics.Statistics
-	workers    chan *Worker // A pool of workers that are registered within the workers pool
+	log         logr.Logger
+	statistics  *statistics.Statistics
+	readyQueue  *conflator.ConflationReadyQueue
+	idleWorkers atomic.Int32
+	dbMonitor   *dbmonitor.DatabaseMonitor
 }
 
-// NewDBWorkerPool returns a new db workers pool dispatcher.
-func NewDBWorkerPool(statistics *statistics.Statistics) (*DBWorkerPool, error) {
+// NewDBWorkerPool returns a new db workers pool for the lanes of the ready queue.
+func NewDBWorkerPool(statistics *statistics.Statistics, readyQueue *conflator.ConflationReadyQueue,
+	dbMonitor *dbmonitor.DatabaseMonitor,
+) (*DBWorkerPool, error) {
 	return &DBWorkerPool{
 		log:        ctrl.Log.WithName("worker-pool"),
 		statistics: statistics,
+		readyQueue: readyQueue,
+		dbMonitor:  dbMonitor,
 	}, nil
 }
 
 // Start function starts the db workers pool.
 func (pool *DBWorkerPool) Start(ctx context.Context) error {
-	sqlDB, err := database.GetGorm().DB()
-	if err != nil {
-		return err
-	}
-
-	stats := sqlDB.Stats()
-	pool.log.Info("connection stats", "open connection(worker)", stats.OpenConnections, "max", stats.MaxOpenConnections)
+	pool.log.Info("starting the db workers", "size", len(pool.readyQueue.Lanes))
 
-	workSize := stats.MaxOpenConnections
-	if workSize < 5 {
-		workSize = 5
-	}
-
-	// initialize workers pool
-	pool.workers = make(chan *Worker, workSize)
-
-	// start workers and register them within the workers pool
-	var i int32
-	for i = 1; i <= int32(workSize); i++ {
-		worker := NewWorker(pool.log, i, pool.workers, pool.statistics)
-		go worker.start(ctx) // each worker adds itself to the pool inside start function
+	for i, lane := range pool.readyQueue.Lanes {
+		worker := NewWorker(pool.log, int32(i+1), lane, &pool.idleWorkers, pool.statistics, pool.dbMonitor)
+		go worker.start(ctx)
 	}
 
 	<-ctx.Done() // blocking wait until getting context cancel event
-
-	close(pool.workers)
-
 	return nil
 }
-
-// Acquire tries to acquire an available worker. if no worker is available, blocking until a worker becomes available.
-func (pool *DBWorkerPool) Acquire() (*Worker, error) {
-	pool.statistics.SetNumberOfAvailableDBWorkers(len(pool.workers))
-	// blocking wait until a worker becomes available or timeout (60 seconds = 6 * 10 seconds)
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for i := 0; i < 6; i += 1 {
-		select {
-		case res := <-pool.workers:
-			return res, nil
-		case <-ticker.C:
-			pool.log.V(2).Info("the db workers are not available, retrying", "seconds", i*10+10)
-			continue
-		}
-	}
-	return nil, fmt.Errorf("timeout to get the DBWorker")
-}
@@ -0,0 +1,132 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/metadata"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestHandleEventMetrics(t *testing.T) {
+	var dbErr error
+	breaker := database.NewCircuitBreaker(database.DefaultRetryConfig)
+	worker := &Worker{
+		log: ctrl.Log.WithName("worker-test"),
+		dbMonitor: dbmonitor.NewDatabaseMonitor(breaker, func(ctx context.Context) error {
+			return dbErr
+		}),
+	}
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub-metrics")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.metrics.worker")
+	eventType := "metrics.worker"
+
+	handlerErrors := func() float64 {
+		return testutil.ToFloat64(monitoring.GlobalHubStatusHandlerErrorsCounterVec.WithLabelValues(eventType))
+	}
+
+	var handleErr error
+	job := &conflator.ConflationJob{
+		Event: &evt,
+		Metadata: &receivedMetadata{
+			ThresholdMetadata: metadata.NewThresholdMetadataFromPosition(3, &transport.EventPosition{}),
+			receivedTime:      time.Now().Add(-time.Second),
+		},
+		Handle: func(ctx context.Context, evt *cloudevents.Event) error {
+			return handleErr
+		},
+	}
+
+	// the decoding error is counted as the handler error, and the job is retried
+	handleErr = errors.New("failed to parse the event data")
+	done, err := worker.handleEvent(context.Background(), job, eventType)
+	if done || err != nil {
+		t.Fatalf("the job should be retried, got done %v, error %v", done, err)
+	}
+	if errs := handlerErrors(); errs != 1 {
+		t.Errorf("expected 1 handler error, got %f", errs)
+	}
+	persisted := testutil.ToFloat64(monitoring.GlobalHubBundlesPersistedCounterVec.WithLabelValues(eventType))
+	if persisted != 0 {
+		t.Errorf("expected no persisted bundle, got %f", persisted)
+	}
+
+	// the job is held once the database is unavailable
+	dbErr = errors.New("connection refused")
+	if _, err = worker.handleEvent(context.Background(), job, eventType); !errors.Is(err, errDatabaseUnavailable) {
+		t.Fatalf("expected the database unavailable error, got %v", err)
+	}
+	if errs := handlerErrors(); errs != 2 {
+		t.Errorf("expected 2 handler errors, got %f", errs)
+	}
+
+	// the job fails fast without accessing the database until the ping closes the circuit
+	dbErr, handleErr = nil, nil
+	if _, err = worker.handleEvent(context.Background(), job, eventType); !errors.Is(err, errDatabaseUnavailable) {
+		t.Fatalf("expected the database unavailable error with the open circuit, got %v", err)
+	}
+	if errs := handlerErrors(); errs != 2 {
+		t.Errorf("the handler errors shouldn't be counted with the open circuit, got %f", errs)
+	}
+	if !worker.dbMonitor.Check(context.Background()) || breaker.State() != database.CircuitClosed {
+		t.Fatalf("expected the closed circuit once the database is back, got %s", breaker.State())
+	}
+	done, err = worker.handleEvent(context.Background(), job, eventType)
+	if !done || err != nil {
+		t.Fatalf("the job should be done, got done %v, error %v", done, err)
+	}
+	if errs := handlerErrors(); errs != 2 {
+		t.Errorf("the handler errors shouldn't be counted on success, got %f", errs)
+	}
+	if processed := testutil.ToFloat64(
+		monitoring.GlobalHubStatusLastProcessedGaugeVec.WithLabelValues(evt.Source())); processed == 0 {
+		t.Error("the last processed time of the hub isn't recorded")
+	}
+	if persisted := testutil.ToFloat64(
+		monitoring.GlobalHubBundlesPersistedCounterVec.WithLabelValues(eventType)); persisted != 1 {
+		t.Errorf("expected 1 persisted bundle, got %f", persisted)
+	}
+
+	// every attempt is observed as the database write, but only the persisted bundle is observed as the processing
+	writeDuration := histogram(t, monitoring.GlobalHubDatabaseWriteDurationHistogramVec, eventType)
+	if writeDuration.GetSampleCount() != 3 {
+		t.Errorf("expected 3 observations of the write duration, got %d", writeDuration.GetSampleCount())
+	}
+	processingDuration := histogram(t, monitoring.GlobalHubBundleProcessingDurationHistogramVec, eventType)
+	if processingDuration.GetSampleCount() != 1 || processingDuration.GetSampleSum() < 1 {
+		t.Errorf("unexpected processing duration: count %d, sum %f", processingDuration.GetSampleCount(),
+			processingDuration.GetSampleSum())
+	}
+}
+
+// receivedMetadata is received by the manager at the given time
+type receivedMetadata struct {
+	*metadata.ThresholdMetadata
+	receivedTime time.Time
+}
+
+func (m *receivedMetadata) ReceivedTime() time.Time {
+	return m.receivedTime
+}
+
+func histogram(t *testing.T, vec *prometheus.HistogramVec, label string) *dto.Histogram {
+	metric := &dto.Metric{}
+	if err := vec.WithLabelValues(label).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatalf("failed to write the histogram: %v", err)
+	}
+	return metric.GetHistogram()
+}
@@ -0,0 +1,98 @@
+package dbmonitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	defaultCheckInterval = 5 * time.Second
+	pingTimeout          = 3 * time.Second
+)
+
+type PingFunc func(ctx context.Context) error
+
+// DatabaseMonitor checks the availability of the database periodically with the circuit breaker shared by the database
+// access. When the database is down, the status pipeline waits for it instead of failing every bundle: the workers
+// hold the jobs, the transport consumption is paused and the offsets aren't committed, then they continue from where
+// they stopped once the database is back. The circuit is opened either by the failed ping or by the transient
+// failures of the database operations, and it's closed once the ping succeeds.
+type DatabaseMonitor struct {
+	log      logr.Logger
+	ping     PingFunc
+	interval time.Duration
+	breaker  *database.CircuitBreaker
+}
+
+func NewDatabaseMonitor(breaker *database.CircuitBreaker, ping PingFunc) *DatabaseMonitor {
+	setAvailableGauge := func(state database.CircuitState) {
+		if state == database.CircuitClosed {
+			monitoring.GlobalHubDatabaseAvailableGauge.Set(1)
+		} else {
+			monitoring.GlobalHubDatabaseAvailableGauge.Set(0)
+		}
+	}
+	breaker.OnStateChange(setAvailableGauge)
+	setAvailableGauge(breaker.State())
+	return &DatabaseMonitor{
+		log:      ctrl.Log.WithName("database-monitor"),
+		ping:     ping,
+		interval: defaultCheckInterval,
+		breaker:  breaker,
+	}
+}
+
+func (m *DatabaseMonitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Check pings the database and updates the availability, it's also invoked once the database operation is failed
+// to find out the outage as soon as possible
+func (m *DatabaseMonitor) Check(ctx context.Context) bool {
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	err := m.ping(pingCtx)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// the manager is shutting down, it doesn't mean the database is unavailable
+	case err != nil:
+		if m.breaker.Available() {
+			m.log.Info("pause the status processing until the database is back")
+		}
+		m.breaker.Trip(err)
+	case !m.breaker.Available():
+		m.log.Info("resume the status processing")
+		m.breaker.Reset()
+	}
+	return m.breaker.Available()
+}
+
+// Do runs the database operation with the shared circuit breaker, it fails with database.ErrCircuitOpen without
+// accessing the database if the circuit is open
+func (m *DatabaseMonitor) Do(ctx context.Context, operation func(ctx context.Context) error) error {
+	return m.breaker.Do(ctx, operation)
+}
+
+func (m *DatabaseMonitor) Available() bool {
+	return m.breaker.Available()
+}
+
+// WaitAvailable blocks until the database is available, it returns the error only if the context is done
+func (m *DatabaseMonitor) WaitAvailable(ctx context.Context) error {
+	return m.breaker.WaitAvailable(ctx)
+}
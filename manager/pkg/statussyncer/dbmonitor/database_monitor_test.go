@@ -0,0 +1,77 @@
+package dbmonitor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+func TestDatabaseMonitor(t *testing.T) {
+	var down atomic.Bool
+	breaker := database.NewCircuitBreaker(database.RetryConfig{
+		MaxAttempts:      1,
+		FailureThreshold: 1,
+		OpenDuration:     time.Hour,
+	})
+	monitor := NewDatabaseMonitor(breaker, func(ctx context.Context) error {
+		if down.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := monitor.WaitAvailable(ctx); err != nil {
+		t.Fatalf("the database should be available by default: %v", err)
+	}
+
+	down.Store(true)
+	if monitor.Check(ctx) {
+		t.Fatal("the database should be unavailable")
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer waitCancel()
+	if err := monitor.WaitAvailable(waitCtx); err == nil {
+		t.Fatal("the waiting should be blocked until the database is available")
+	}
+
+	waited := make(chan error)
+	go func() {
+		waited <- monitor.WaitAvailable(ctx)
+	}()
+	down.Store(false)
+	if !monitor.Check(ctx) {
+		t.Fatal("the database should be available")
+	}
+	if err := <-waited; err != nil {
+		t.Fatalf("the waiting should be released once the database is available: %v", err)
+	}
+	if testutil.ToFloat64(monitoring.GlobalHubDatabaseAvailableGauge) != 1 {
+		t.Fatal("the database available gauge should be 1")
+	}
+
+	// the circuit opened by the transient failure of the operation pauses the pipeline, and the ping closes it
+	err := monitor.Do(ctx, func(ctx context.Context) error { return syscall.ECONNREFUSED })
+	if !errors.Is(err, syscall.ECONNREFUSED) || monitor.Available() {
+		t.Fatalf("expected the unavailable database after the transient failure, got %v", err)
+	}
+	if err := monitor.Do(ctx, func(ctx context.Context) error { return nil }); !errors.Is(err, database.ErrCircuitOpen) {
+		t.Fatalf("expected to fail fast with the open circuit, got %v", err)
+	}
+	if testutil.ToFloat64(monitoring.GlobalHubDatabaseAvailableGauge) != 0 {
+		t.Fatal("the database available gauge should be 0")
+	}
+	if !monitor.Check(ctx) {
+		t.Fatal("the database should be available once the ping succeeds")
+	}
+}
@@ -0,0 +1,39 @@
+package dispatcher
+
+import (
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
+)
+
+// AdmissionFilter decides whether the event received from the transport is forwarded to the conflation manager. The
+// filters are invoked in order and the event is dropped by the first filter returning false, so a filter only sees the
+// events admitted by the filters before it.
+type AdmissionFilter interface {
+	Admit(evt *cloudevents.Event) bool
+}
+
+// signatureFilter rejects the events which aren't signed by the managed hubs
+type signatureFilter struct {
+	log      logr.Logger
+	verifier *signature.Verifier
+}
+
+func NewSignatureFilter(verifier *signature.Verifier) AdmissionFilter {
+	return &signatureFilter{
+		log:      ctrl.Log.WithName("signature-filter"),
+		verifier: verifier,
+	}
+}
+
+func (f *signatureFilter) Admit(evt *cloudevents.Event) bool {
+	if err := f.verifier.Verify(evt); err != nil {
+		f.log.Error(err, "reject the event", "source", evt.Source(), "type", evt.Type(), "id", evt.ID())
+		monitoring.GlobalHubSignatureFailuresCounterVec.WithLabelValues(evt.Source()).Inc()
+		return false
+	}
+	return true
+}
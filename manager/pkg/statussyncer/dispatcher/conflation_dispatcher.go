@@ -1,104 +1,30 @@
 package dispatcher
 
 import (
-	"context"
 	"fmt"
-	"time"
 
-	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/workerpool"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
-// NewConflationDispatcher creates a new instance of Dispatcher.
-func NewConflationDispatcher(log logr.Logger, conflationReadyQueue *conflator.ConflationReadyQueue,
-	dbWorkerPool *workerpool.DBWorkerPool,
-) *ConflationDispatcher {
-	return &ConflationDispatcher{
-		log:                  log,
-		conflationReadyQueue: conflationReadyQueue,
-		dbWorkerPool:         dbWorkerPool,
-	}
-}
-
+// AddConflationDispatcher dispatches the ready conflation units and delta jobs to the db workers. The ready queue is
+// partitioned into lanes by the leaf hub, and each lane is drained by its own worker, so there is no single dispatcher
+// waiting for an available worker in front of all the hubs.
 func AddConflationDispatcher(mgr ctrl.Manager, conflationManager *conflator.ConflationManager,
-	managerConfig *config.ManagerConfig, stats *statistics.Statistics,
+	managerConfig *config.ManagerConfig, stats *statistics.Statistics, dbMonitor *dbmonitor.DatabaseMonitor,
 ) error {
 	// add work pool: database layer initialization - worker pool + connection pool
-	dbWorkerPool, err := workerpool.NewDBWorkerPool(stats)
+	dbWorkerPool, err := workerpool.NewDBWorkerPool(stats, conflationManager.GetReadyQueue(), dbMonitor)
 	if err != nil {
 		return fmt.Errorf("failed to initialize DBWorkerPool: %w", err)
 	}
 	if err := mgr.Add(dbWorkerPool); err != nil {
 		return fmt.Errorf("failed to add DB worker pool: %w", err)
 	}
-
-	// conflation dispatcher -> work pool
-	conflationDispatcher := &ConflationDispatcher{
-		log:                  ctrl.Log.WithName("conflation-dispatcher"),
-		conflationReadyQueue: conflationManager.GetReadyQueue(),
-		dbWorkerPool:         dbWorkerPool,
-	}
-	if err := mgr.Add(conflationDispatcher); err != nil {
-		return fmt.Errorf("failed to add conflation dispatcher: %w", err)
-	}
-	return nil
-}
-
-// ConflationDispatcher abstracts the dispatching of db jobs to db workers. this is done by reading ready CU
-// and getting from them a ready to process bundles.
-type ConflationDispatcher struct {
-	log                  logr.Logger
-	conflationReadyQueue *conflator.ConflationReadyQueue
-	dbWorkerPool         *workerpool.DBWorkerPool
-}
-
-// Start starts the dispatcher.
-func (dispatcher *ConflationDispatcher) Start(ctx context.Context) error {
-	dispatcher.log.Info("starting dispatcher")
-
-	go dispatcher.dispatch(ctx)
-
-	<-ctx.Done() // blocking wait until getting context cancel event
-	dispatcher.log.Info("stopped dispatcher")
-
 	return nil
 }
-
-func (dispatcher *ConflationDispatcher) dispatch(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done(): // if dispatcher was stopped do not process more bundles
-			return
-
-		case deltaEventJob := <-dispatcher.conflationReadyQueue.DeltaEventJobChan:
-			worker := dispatcher.getBlockingWorker(ctx)
-			worker.RunAsync(deltaEventJob)
-		case conflationUnit := <-dispatcher.conflationReadyQueue.ConflationUnitChan:
-			eventJob, err := conflationUnit.GetNext()
-			if err != nil {
-				dispatcher.log.Info(err.Error()) // don't need to throw the error when bundle is not ready
-				continue
-			}
-			worker := dispatcher.getBlockingWorker(ctx)
-			worker.RunAsync(eventJob)
-		}
-	}
-}
-
-func (dispatcher *ConflationDispatcher) getBlockingWorker(ctx context.Context) (worker *workerpool.Worker) {
-	_ = wait.PollUntilContextCancel(ctx, 10*time.Second, true, func(ctx context.Context) (done bool, err error) {
-		worker, err = dispatcher.dbWorkerPool.Acquire()
-		if err != nil {
-			dispatcher.log.Info("retrying to acquire db worker", "error", err)
-			return false, nil
-		}
-		return true, nil
-	})
-	return worker
-}
@@ -4,14 +4,15 @@ import (
 	"context"
 	"fmt"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
-	genericconsumer "github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 )
 
 // Get message from transport, convert it to bundle and forward it to conflation manager.
@@ -20,19 +21,17 @@ type TransportDispatcher struct {
 	consumer          transport.Consumer
 	conflationManager *conflator.ConflationManager
 	statistic         *statistics.Statistics
+	dbMonitor         *dbmonitor.DatabaseMonitor
+	// filters admit the received events in order, e.g. the signature verification, the schema validation, the
+	// sequence detection, the version skew quarantine and the rate limit
+	filters []AdmissionFilter
 }
 
-func AddTransportDispatcher(mgr ctrl.Manager, managerConfig *config.ManagerConfig,
-	conflationManager *conflator.ConflationManager, stats *statistics.Statistics,
+// AddTransportDispatcher adds the dispatcher forwarding the events admitted by the ordered filters to the conflation
+func AddTransportDispatcher(mgr ctrl.Manager, consumer transport.Consumer,
+	conflationManager *conflator.ConflationManager, stats *statistics.Statistics, dbMonitor *dbmonitor.DatabaseMonitor,
+	filters []AdmissionFilter,
 ) error {
-	// start a consumer
-	topics := managerConfig.TransportConfig.KafkaConfig.Topics
-	consumer, err := genericconsumer.NewGenericConsumer(managerConfig.TransportConfig,
-		[]string{topics.EventTopic, topics.StatusTopic},
-		genericconsumer.EnableDatabaseOffset(true))
-	if err != nil {
-		return fmt.Errorf("failed to initialize transport consumer: %w", err)
-	}
 	if err := mgr.Add(consumer); err != nil {
 		return fmt.Errorf("failed to add transport consumer to manager: %w", err)
 	}
@@ -42,6 +41,8 @@ func AddTransportDispatcher(mgr ctrl.Manager, managerConfig *config.ManagerConfi
 		consumer:          consumer,
 		conflationManager: conflationManager,
 		statistic:         stats,
+		dbMonitor:         dbMonitor,
+		filters:           filters,
 	}
 	if err := mgr.Add(transportDispatcher); err != nil {
 		return fmt.Errorf("failed to add transport dispatcher to runtime manager: %w", err)
@@ -63,13 +64,35 @@ func (d *TransportDispatcher) Start(ctx context.Context) error {
 
 func (d *TransportDispatcher) dispatch(ctx context.Context) {
 	for {
+		// pause consuming during the database outage, the bundles are kept in the transport until it's back
+		if !d.dbMonitor.Available() {
+			d.log.Info("pause consuming the events until the database is available")
+			if err := d.dbMonitor.WaitAvailable(ctx); err != nil {
+				return
+			}
+			d.log.Info("resume consuming the events")
+		}
+
 		select {
 		case <-ctx.Done():
 			return
 		case evt := <-d.consumer.EventChan():
+			if !d.admit(evt) {
+				continue
+			}
 			d.statistic.ReceivedEvent(evt)
+			monitoring.GlobalHubStatusLastReceivedGaugeVec.WithLabelValues(evt.Source()).SetToCurrentTime()
 			d.log.V(2).Info("forward received event to conflation", "event type", evt.Type())
 			d.conflationManager.Insert(evt)
 		}
 	}
 }
+
+func (d *TransportDispatcher) admit(evt *cloudevents.Event) bool {
+	for _, filter := range d.filters {
+		if !filter.Admit(evt) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package dispatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+type fakeConsumer struct {
+	eventChan chan *cloudevents.Event
+}
+
+func (c *fakeConsumer) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *fakeConsumer) EventChan() chan *cloudevents.Event {
+	return c.eventChan
+}
+
+func TestDispatchMetrics(t *testing.T) {
+	stats := statistics.NewStatistics(&statistics.StatisticsConfig{})
+	conflationManager := conflator.NewConflationManager(stats, 1)
+	conflationManager.Register(conflator.NewConflationRegistration(0, enum.DeltaStateMode,
+		string(enum.LocalReplicatedPolicyEventType), func(ctx context.Context, evt *cloudevents.Event) error {
+			return nil
+		}))
+
+	consumer := &fakeConsumer{eventChan: make(chan *cloudevents.Event)}
+	breaker := database.NewCircuitBreaker(database.DefaultRetryConfig)
+	d := &TransportDispatcher{
+		log:               ctrl.Log.WithName("dispatcher-test"),
+		consumer:          consumer,
+		conflationManager: conflationManager,
+		statistic:         stats,
+		dbMonitor:         dbmonitor.NewDatabaseMonitor(breaker, func(ctx context.Context) error { return nil }),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go d.dispatch(ctx)
+
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub-dispatch")
+	evt.SetType(string(enum.LocalReplicatedPolicyEventType))
+	evt.SetExtension(version.ExtVersion, "1.1")
+	consumer.eventChan <- &evt
+
+	// the received event is recorded and queued to the lane of the hub, which isn't taken by any worker
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(monitoring.GlobalHubConflationQueueDepthGaugeVec.WithLabelValues("delta_event")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NotZero(t, testutil.ToFloat64(monitoring.GlobalHubStatusLastReceivedGaugeVec.WithLabelValues("hub-dispatch")))
+}
+
+type recordingFilter struct {
+	name    string
+	admit   bool
+	invoked *[]string
+}
+
+func (f *recordingFilter) Admit(evt *cloudevents.Event) bool {
+	*f.invoked = append(*f.invoked, f.name)
+	return f.admit
+}
+
+func TestAdmitInOrder(t *testing.T) {
+	invoked := []string{}
+	d := &TransportDispatcher{
+		filters: []AdmissionFilter{
+			&recordingFilter{name: "signature", admit: true, invoked: &invoked},
+			&recordingFilter{name: "sequence", admit: false, invoked: &invoked},
+			&recordingFilter{name: "freshness", admit: true, invoked: &invoked},
+		},
+	}
+	evt := cloudevents.NewEvent()
+	assert.False(t, d.admit(&evt))
+	assert.Equal(t, []string{"signature", "sequence"}, invoked, "the filters after the rejecting one aren't invoked")
+
+	invoked = []string{}
+	d.filters = d.filters[:1]
+	assert.True(t, d.admit(&evt))
+	assert.Equal(t, []string{"signature"}, invoked)
+
+	assert.True(t, (&TransportDispatcher{}).admit(&evt), "the event is admitted without the filters")
+}
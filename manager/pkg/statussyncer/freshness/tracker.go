@@ -0,0 +1,142 @@
+package freshness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// FlushInterval is the interval to persist the last received time of the bundles, so the database isn't written on
+// every received bundle
+const FlushInterval = 30 * time.Second
+
+// DefaultExpectedIntervals are the bundle types sent periodically by the agents, the other bundles are only sent once
+// they're changed, so they don't have the expected interval unless it's configured
+var DefaultExpectedIntervals = map[string]string{
+	BundleType(string(enum.HubClusterHeartbeatType)): "1m",
+}
+
+// SaveFunc persists the freshness of the bundles
+type SaveFunc func(ctx context.Context, freshness []models.DataFreshness) error
+
+type bundleKey struct {
+	hub        string
+	bundleType string
+}
+
+// Tracker records the last time the bundle of each type is received from each managed hub, and persists them
+// periodically with the intervals the bundles are expected to be received in
+type Tracker struct {
+	log               logr.Logger
+	expectedIntervals map[string]time.Duration
+	save              SaveFunc
+
+	mutex sync.Mutex
+	// the bundles received since the last flush
+	received map[bundleKey]time.Time
+}
+
+func NewTracker(expectedIntervals map[string]time.Duration) *Tracker {
+	return &Tracker{
+		log:               ctrl.Log.WithName("data-freshness"),
+		expectedIntervals: expectedIntervals,
+		save:              saveDataFreshness,
+		received:          map[bundleKey]time.Time{},
+	}
+}
+
+// BundleType returns the event type without the common prefix, e.g. managedhub.heartbeat
+func BundleType(eventType string) string {
+	return strings.TrimPrefix(eventType, enum.EventTypePrefix)
+}
+
+// ParseExpectedIntervals parses the expected intervals keyed by the bundle types, e.g. managedhub.heartbeat=1m
+func ParseExpectedIntervals(intervals map[string]string) (map[string]time.Duration, error) {
+	parsed := map[string]time.Duration{}
+	for bundleType, value := range intervals {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("the expected interval %q of the bundle %s isn't a positive duration", value,
+				bundleType)
+		}
+		parsed[BundleType(bundleType)] = interval
+	}
+	return parsed, nil
+}
+
+// Admit records the event is received now, it never rejects the event, so it's the last filter to only record the
+// admitted events
+func (t *Tracker) Admit(evt *cloudevents.Event) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.received[bundleKey{hub: evt.Source(), bundleType: BundleType(evt.Type())}] = time.Now()
+	return true
+}
+
+func (t *Tracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := t.flush(ctx); err != nil {
+				t.log.Error(err, "failed to save the data freshness")
+			}
+		}
+	}
+}
+
+// flush persists the bundles received since the last flush, they're kept and retried with the next flush on failure
+func (t *Tracker) flush(ctx context.Context) error {
+	t.mutex.Lock()
+	received := t.received
+	t.received = map[bundleKey]time.Time{}
+	t.mutex.Unlock()
+	if len(received) == 0 {
+		return nil
+	}
+
+	freshness := make([]models.DataFreshness, 0, len(received))
+	for key, receivedAt := range received {
+		item := models.DataFreshness{
+			LeafHubName:    key.hub,
+			BundleType:     key.bundleType,
+			LastReceivedAt: receivedAt,
+			UpdatedAt:      time.Now(),
+		}
+		if interval, found := t.expectedIntervals[key.bundleType]; found {
+			seconds := int64(interval.Seconds())
+			item.ExpectedIntervalSeconds = &seconds
+		}
+		freshness = append(freshness, item)
+	}
+	if err := t.save(ctx, freshness); err != nil {
+		t.mutex.Lock()
+		for key, receivedAt := range received {
+			if _, found := t.received[key]; !found {
+				t.received[key] = receivedAt
+			}
+		}
+		t.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+func saveDataFreshness(ctx context.Context, freshness []models.DataFreshness) error {
+	return database.GetGorm().WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&freshness).Error
+}
@@ -0,0 +1,75 @@
+package freshness
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(source string, eventType enum.EventType) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(source)
+	evt.SetType(string(eventType))
+	return &evt
+}
+
+func TestParseExpectedIntervals(t *testing.T) {
+	intervals, err := ParseExpectedIntervals(map[string]string{
+		"managedhub.heartbeat":          "1m",
+		string(enum.ManagedClusterType): "10m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, intervals["managedhub.heartbeat"])
+	assert.Equal(t, 10*time.Minute, intervals[BundleType(string(enum.ManagedClusterType))])
+
+	_, err = ParseExpectedIntervals(map[string]string{"managedhub.heartbeat": "0s"})
+	assert.ErrorContains(t, err, "positive duration")
+}
+
+func TestTracker(t *testing.T) {
+	intervals, err := ParseExpectedIntervals(DefaultExpectedIntervals)
+	require.NoError(t, err)
+	tracker := NewTracker(intervals)
+	saved := map[string]models.DataFreshness{}
+	var saveErr error
+	tracker.save = func(ctx context.Context, freshness []models.DataFreshness) error {
+		if saveErr != nil {
+			return saveErr
+		}
+		for _, item := range freshness {
+			saved[item.LeafHubName+"/"+item.BundleType] = item
+		}
+		return nil
+	}
+
+	assert.True(t, tracker.Admit(newEvent("hub1", enum.HubClusterHeartbeatType)))
+	assert.True(t, tracker.Admit(newEvent("hub1", enum.ManagedClusterType)))
+	assert.True(t, tracker.Admit(newEvent("hub2", enum.HubClusterHeartbeatType)))
+
+	// the received bundles are kept until they're saved
+	saveErr = errors.New("the database is unavailable")
+	assert.Error(t, tracker.flush(context.Background()))
+	assert.Empty(t, saved)
+
+	saveErr = nil
+	require.NoError(t, tracker.flush(context.Background()))
+	assert.Len(t, saved, 3)
+	heartbeat := saved["hub1/managedhub.heartbeat"]
+	require.NotNil(t, heartbeat.ExpectedIntervalSeconds)
+	assert.Equal(t, int64(60), *heartbeat.ExpectedIntervalSeconds)
+	// the bundle only sent once it's changed doesn't have the expected interval
+	assert.Nil(t, saved["hub1/"+BundleType(string(enum.ManagedClusterType))].ExpectedIntervalSeconds)
+
+	// nothing is saved if no bundle is received since the last flush
+	saved = map[string]models.DataFreshness{}
+	require.NoError(t, tracker.flush(context.Background()))
+	assert.Empty(t, saved)
+}
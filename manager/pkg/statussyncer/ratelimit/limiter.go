@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const (
+	// DefaultEventsPerSecond is the number of the status events admitted from each managed hub per second by default,
+	// the rate isn't limited unless it's set explicitly
+	DefaultEventsPerSecond = 0
+	// DefaultBurst is the number of the status events admitted from each managed hub at once, e.g. on the resync
+	DefaultBurst = 100
+	// the throttling state of the hubs is synced to the metrics and the annotations within the interval
+	syncInterval = 30 * time.Second
+)
+
+// admittedEventTypes aren't throttled: the throttled hub is still active with its heartbeat, and the events of the
+// delta state mode carry the changes which aren't sent again, so they'd be lost if they're dropped
+var admittedEventTypes = map[string]bool{
+	string(enum.HubClusterHeartbeatType):        true,
+	string(enum.HubClusterInfoType):             true,
+	string(enum.LocalRootPolicyEventType):       true,
+	string(enum.LocalReplicatedPolicyEventType): true,
+	string(enum.DeltaComplianceType):            true,
+}
+
+// AnnotateFunc annotates the managed cluster of the hub with the time since it's throttled, the annotation is removed
+// if the time is zero
+type AnnotateFunc func(ctx context.Context, hubName string, throttledSince time.Time) error
+
+type hubLimiter struct {
+	limiter *rate.Limiter
+	// the number of the events dropped since the last sync
+	dropped int
+	// the time the hub is throttled since, it's zero if the hub isn't throttled
+	throttledSince time.Time
+}
+
+// Limiter limits the rate of the status events from each managed hub, so a misconfigured agent flooding the events
+// can't starve the ingestion of the other hubs. The complete state events beyond the rate are dropped, since they're
+// recovered by the following ones once the agent is back to normal. The heartbeat, the hub info and the delta state
+// events are always admitted.
+type Limiter struct {
+	log      logr.Logger
+	limit    rate.Limit
+	burst    int
+	annotate AnnotateFunc
+
+	mutex sync.Mutex
+	hubs  map[string]*hubLimiter
+}
+
+// NewLimiter returns the limiter admitting the eventsPerSecond with the burst from each hub, the rate isn't limited
+// if the eventsPerSecond isn't positive
+func NewLimiter(c client.Client, eventsPerSecond float64, burst int) *Limiter {
+	limit := rate.Inf
+	if eventsPerSecond > 0 {
+		limit = rate.Limit(eventsPerSecond)
+	}
+	return &Limiter{
+		log:      ctrl.Log.WithName("hub-rate-limiter"),
+		limit:    limit,
+		burst:    burst,
+		annotate: annotateFunc(c),
+		hubs:     map[string]*hubLimiter{},
+	}
+}
+
+// Admit returns false if the event exceeds the rate of its hub and it should be dropped
+func (l *Limiter) Admit(evt *cloudevents.Event) bool {
+	if l.limit == rate.Inf || admittedEventTypes[evt.Type()] {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	hub, found := l.hubs[evt.Source()]
+	if !found {
+		hub = &hubLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.hubs[evt.Source()] = hub
+	}
+	if hub.limiter.Allow() {
+		return true
+	}
+	hub.dropped++
+	monitoring.GlobalHubThrottledEventsCounterVec.WithLabelValues(evt.Source()).Inc()
+	l.log.V(2).Info("throttle the event", "source", evt.Source(), "type", evt.Type())
+	return false
+}
+
+// Start syncs the throttling state of the hubs to the metrics and the annotations periodically
+func (l *Limiter) Start(ctx context.Context) error {
+	if l.limit == rate.Inf {
+		return nil
+	}
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			l.sync(ctx)
+		}
+	}
+}
+
+// sync marks the hub as throttled if any of its events is dropped within the last interval, otherwise it's unmarked
+func (l *Limiter) sync(ctx context.Context) {
+	changed := map[string]time.Time{}
+	l.mutex.Lock()
+	for hubName, hub := range l.hubs {
+		if hub.dropped > 0 && hub.throttledSince.IsZero() {
+			l.log.Info("the hub is throttled", "hub", hubName, "dropped", hub.dropped)
+			hub.throttledSince = time.Now()
+			changed[hubName] = hub.throttledSince
+		} else if hub.dropped == 0 && !hub.throttledSince.IsZero() {
+			l.log.Info("the hub isn't throttled anymore", "hub", hubName)
+			hub.throttledSince = time.Time{}
+			changed[hubName] = hub.throttledSince
+		}
+		hub.dropped = 0
+	}
+	l.mutex.Unlock()
+
+	for hubName, throttledSince := range changed {
+		if throttledSince.IsZero() {
+			monitoring.GlobalHubIngestionThrottledGaugeVec.WithLabelValues(hubName).Set(0)
+		} else {
+			monitoring.GlobalHubIngestionThrottledGaugeVec.WithLabelValues(hubName).Set(1)
+		}
+		if err := l.annotate(ctx, hubName, throttledSince); err != nil {
+			l.log.Error(err, "failed to annotate the throttled hub", "hub", hubName)
+		}
+	}
+}
+
+func annotateFunc(c client.Client) AnnotateFunc {
+	return func(ctx context.Context, hubName string, throttledSince time.Time) error {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := c.Get(ctx, client.ObjectKey{Name: hubName}, cluster); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		patch := client.MergeFrom(cluster.DeepCopy())
+		if throttledSince.IsZero() {
+			if _, found := cluster.Annotations[constants.ManagedHubThrottledAnnotation]; !found {
+				return nil
+			}
+			delete(cluster.Annotations, constants.ManagedHubThrottledAnnotation)
+		} else {
+			if cluster.Annotations == nil {
+				cluster.Annotations = map[string]string{}
+			}
+			cluster.Annotations[constants.ManagedHubThrottledAnnotation] = throttledSince.UTC().Format(time.RFC3339)
+		}
+		return client.IgnoreNotFound(c.Patch(ctx, cluster, patch))
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(source string, eventType enum.EventType) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(source)
+	evt.SetType(string(eventType))
+	return &evt
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+	annotated := map[string]time.Time{}
+	limiter := NewLimiter(nil, 0.001, 2)
+	limiter.annotate = func(ctx context.Context, hubName string, throttledSince time.Time) error {
+		annotated[hubName] = throttledSince
+		return nil
+	}
+
+	// the burst is admitted, then the events of the flooding hub are dropped
+	assert.True(t, limiter.Admit(newEvent("hub1", enum.ManagedClusterType)))
+	assert.True(t, limiter.Admit(newEvent("hub1", enum.LocalPolicySpecType)))
+	assert.False(t, limiter.Admit(newEvent("hub1", enum.ManagedClusterType)))
+	// the heartbeat isn't throttled
+	assert.True(t, limiter.Admit(newEvent("hub1", enum.HubClusterHeartbeatType)))
+	// the other hubs aren't affected
+	assert.True(t, limiter.Admit(newEvent("hub2", enum.ManagedClusterType)))
+
+	limiter.sync(ctx)
+	assert.Len(t, annotated, 1)
+	assert.False(t, annotated["hub1"].IsZero())
+
+	// the hub is still throttled, so it isn't annotated again
+	annotated = map[string]time.Time{}
+	assert.False(t, limiter.Admit(newEvent("hub1", enum.ManagedClusterType)))
+	limiter.sync(ctx)
+	assert.Empty(t, annotated)
+
+	// the annotation is removed once no event is dropped within the interval
+	limiter.sync(ctx)
+	throttledSince, found := annotated["hub1"]
+	assert.True(t, found)
+	assert.True(t, throttledSince.IsZero())
+}
+
+func TestDeltaEventsNotDropped(t *testing.T) {
+	limiter := NewLimiter(nil, 0.001, 1)
+	limiter.annotate = func(ctx context.Context, hubName string, throttledSince time.Time) error {
+		return nil
+	}
+
+	// the hub is throttled once the burst is used up
+	assert.True(t, limiter.Admit(newEvent("hub1", enum.ManagedClusterType)))
+	assert.False(t, limiter.Admit(newEvent("hub1", enum.ManagedClusterType)))
+
+	// the delta state events are never dropped, even the hub is throttled
+	deltaTypes := []enum.EventType{
+		enum.LocalRootPolicyEventType,
+		enum.LocalReplicatedPolicyEventType,
+		enum.DeltaComplianceType,
+	}
+	for i := 0; i < 2*DefaultBurst; i++ {
+		for _, eventType := range deltaTypes {
+			assert.True(t, limiter.Admit(newEvent("hub1", eventType)), eventType)
+		}
+	}
+	// and they don't consume the rate of the hub
+	assert.Equal(t, 1, limiter.hubs["hub1"].dropped)
+}
+
+func TestUnlimited(t *testing.T) {
+	limiter := NewLimiter(nil, 0, DefaultBurst)
+	for i := 0; i < 2*DefaultBurst; i++ {
+		assert.True(t, limiter.Admit(newEvent("hub1", enum.ManagedClusterType)))
+	}
+	assert.Nil(t, limiter.Start(context.Background()))
+}
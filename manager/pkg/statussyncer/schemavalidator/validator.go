@@ -0,0 +1,80 @@
+package schemavalidator
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const (
+	// unregisteredVersion is the version label of the metrics for the event types without the schema
+	unregisteredVersion = "none"
+	// saveTimeout bounds the persisting of the dead letter, so a slow database doesn't block the dispatching
+	saveTimeout = 5 * time.Second
+)
+
+// DeadLetterFunc persists the rejected event, so it's investigated instead of lost silently
+type DeadLetterFunc func(ctx context.Context, deadLetter *models.DeadLetterEvent) error
+
+// Validator validates the data of the status events against the schemas of the versions claimed by them, the events
+// which don't match are rejected before they reach the handlers and are sent to the dead letter table. The schema
+// drift between the agent and the manager is caught here instead of failing the handlers or persisting wrong data.
+type Validator struct {
+	log        logr.Logger
+	deadLetter DeadLetterFunc
+}
+
+func NewValidator() *Validator {
+	return &Validator{
+		log:        ctrl.Log.WithName("schema-validator"),
+		deadLetter: saveDeadLetter,
+	}
+}
+
+// Admit returns false if the event doesn't match the schema of its version
+func (v *Validator) Admit(evt *cloudevents.Event) bool {
+	eventType := strings.TrimPrefix(evt.Type(), enum.EventTypePrefix)
+	version, err := schema.Validate(evt)
+	metricVersion := version
+	if metricVersion == "" {
+		metricVersion = unregisteredVersion
+	}
+	monitoring.GlobalHubEventSchemaVersionsCounterVec.WithLabelValues(eventType, metricVersion).Inc()
+	if err == nil {
+		return true
+	}
+
+	monitoring.GlobalHubSchemaValidationFailuresCounterVec.WithLabelValues(evt.Source(), eventType,
+		metricVersion).Inc()
+	v.log.Error(err, "reject the event", "source", evt.Source(), "type", eventType, "id", evt.ID(),
+		"version", version)
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+	if e := v.deadLetter(ctx, &models.DeadLetterEvent{
+		LeafHubName:   evt.Source(),
+		EventType:     evt.Type(),
+		EventID:       evt.ID(),
+		SchemaVersion: version,
+		Reason:        err.Error(),
+		Payload:       evt.Data(),
+		ReceivedAt:    time.Now(),
+	}); e != nil {
+		v.log.Error(e, "failed to save the dead letter event", "source", evt.Source(), "type", eventType,
+			"id", evt.ID())
+	}
+	return false
+}
+
+func saveDeadLetter(ctx context.Context, deadLetter *models.DeadLetterEvent) error {
+	return database.GetGorm().WithContext(ctx).Create(deadLetter).Error
+}
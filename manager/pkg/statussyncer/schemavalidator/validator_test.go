@@ -0,0 +1,49 @@
+package schemavalidator
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestValidatorAdmit(t *testing.T) {
+	deadLetters := []*models.DeadLetterEvent{}
+	validator := NewValidator()
+	validator.deadLetter = func(ctx context.Context, deadLetter *models.DeadLetterEvent) error {
+		deadLetters = append(deadLetters, deadLetter)
+		return nil
+	}
+	newEvent := func(data interface{}) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetID("1")
+		evt.SetType(string(enum.ComplianceType))
+		evt.SetSource("hub1")
+		evt.SetDataSchema(schema.DataSchema(string(enum.ComplianceType)))
+		assert.Nil(t, evt.SetData(cloudevents.ApplicationJSON, data))
+		return &evt
+	}
+
+	assert.True(t, validator.Admit(newEvent(grc.ComplianceBundle{{PolicyID: "1234"}})))
+	assert.Empty(t, deadLetters)
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		monitoring.GlobalHubEventSchemaVersionsCounterVec.WithLabelValues("policy.compliance", "v1")))
+
+	// the policy id is missing
+	assert.False(t, validator.Admit(newEvent([]map[string]string{{"policy": "1234"}})))
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, "hub1", deadLetters[0].LeafHubName)
+	assert.Equal(t, "v1", deadLetters[0].SchemaVersion)
+	assert.Contains(t, deadLetters[0].Reason, "policyId")
+	assert.JSONEq(t, `[{"policy":"1234"}]`, string(deadLetters[0].Payload))
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		monitoring.GlobalHubSchemaValidationFailuresCounterVec.WithLabelValues("hub1", "policy.compliance", "v1")))
+}
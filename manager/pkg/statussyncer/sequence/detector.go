@@ -0,0 +1,97 @@
+package sequence
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// Stale means a newer bundle of the same type has been received from the hub
+	Stale = "stale"
+	// Duplicate means the same bundle has been received from the hub, e.g. it's redelivered after the rebalance
+	Duplicate = "duplicate"
+)
+
+type sequence struct {
+	incarnation int64
+	version     eventversion.Version
+}
+
+// Detector drops the stale and the duplicate bundles of each managed hub, which are redelivered by the transport
+// after the rebalance or sent by the agent before it's restarted. The bundles are ordered by the incarnation of the
+// agent and then the version of the bundle. The first generation of the bundle is always admitted since it resets the
+// version, e.g. the agent without the incarnation is restarted.
+type Detector struct {
+	log   logr.Logger
+	mutex sync.Mutex
+	// the sequence of the last admitted bundle of each type from each hub
+	sequences map[string]sequence
+}
+
+func NewDetector() *Detector {
+	return &Detector{
+		log:       ctrl.Log.WithName("sequence-detector"),
+		sequences: map[string]sequence{},
+	}
+}
+
+// Admit returns false if the event is stale or duplicate and it should be dropped
+func (d *Detector) Admit(evt *cloudevents.Event) bool {
+	current, ok := sequenceOf(evt)
+	if !ok {
+		return true
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	key := fmt.Sprintf("%s/%s", evt.Source(), evt.Type())
+	last, found := d.sequences[key]
+
+	reason := ""
+	switch {
+	case !found || current.incarnation > last.incarnation:
+	case current.incarnation < last.incarnation:
+		reason = Stale
+	case current.version.InitGen():
+	case current.version.Equals(&last.version):
+		reason = Duplicate
+	case !current.version.NewerThan(&last.version):
+		reason = Stale
+	}
+	if reason != "" {
+		monitoring.GlobalHubDroppedEventsCounterVec.WithLabelValues(evt.Source(), reason).Inc()
+		d.log.V(2).Info("drop the event", "reason", reason, "source", evt.Source(), "type", evt.Type(),
+			"version", current.version.String(), "lastVersion", last.version.String())
+		return false
+	}
+	d.sequences[key] = current
+	return true
+}
+
+// sequenceOf returns the incarnation and the version of the event, the incarnation is 0 if the agent doesn't report it
+func sequenceOf(evt *cloudevents.Event) (sequence, bool) {
+	versionStr, ok := evt.Extensions()[eventversion.ExtVersion].(string)
+	if !ok {
+		return sequence{}, false
+	}
+	version, err := eventversion.VersionFrom(versionStr)
+	if err != nil {
+		return sequence{}, false
+	}
+	current := sequence{version: *version}
+	if incarnation, ok := evt.Extensions()[transport.IncarnationKey].(string); ok {
+		if current.incarnation, err = strconv.ParseInt(incarnation, 10, 64); err != nil {
+			return sequence{}, false
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,54 @@
+package sequence
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newEvent(source string, eventType enum.EventType, incarnation, version string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(source)
+	evt.SetType(string(eventType))
+	if incarnation != "" {
+		evt.SetExtension(transport.IncarnationKey, incarnation)
+	}
+	if version != "" {
+		evt.SetExtension(eventversion.ExtVersion, version)
+	}
+	return &evt
+}
+
+func TestDetector(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *cloudevents.Event
+		want  bool
+	}{
+		{"the first event", newEvent("hub1", enum.ManagedClusterType, "100", "0.1"), true},
+		{"the newer event", newEvent("hub1", enum.ManagedClusterType, "100", "2.5"), true},
+		{"the duplicate event", newEvent("hub1", enum.ManagedClusterType, "100", "2.5"), false},
+		{"the stale event", newEvent("hub1", enum.ManagedClusterType, "100", "1.3"), false},
+		{"the other type", newEvent("hub1", enum.LocalPolicySpecType, "100", "1.3"), true},
+		{"the other hub", newEvent("hub2", enum.ManagedClusterType, "100", "1.3"), true},
+		{"the restarted agent", newEvent("hub1", enum.ManagedClusterType, "200", "0.1"), true},
+		{"the event before the restart", newEvent("hub1", enum.ManagedClusterType, "100", "3.6"), false},
+		{"the event after the restart", newEvent("hub1", enum.ManagedClusterType, "200", "1.2"), true},
+		{"the event without version", newEvent("hub1", enum.ManagedClusterType, "200", ""), true},
+		{"the legacy agent", newEvent("hub3", enum.ManagedClusterType, "", "3.6"), true},
+		{"the stale event of the legacy agent", newEvent("hub3", enum.ManagedClusterType, "", "2.6"), false},
+		{"the restarted legacy agent", newEvent("hub3", enum.ManagedClusterType, "", "0.1"), true},
+	}
+
+	detector := NewDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detector.Admit(tt.event))
+		})
+	}
+}
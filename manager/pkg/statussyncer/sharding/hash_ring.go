@@ -0,0 +1,62 @@
+package sharding
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strconv"
+)
+
+// defaultVirtualNodes is the number of points of each member on the ring, it smooths the distribution of the hubs
+// when there are only a few manager replicas
+const defaultVirtualNodes = 100
+
+// HashRing is a consistent hash ring over the manager replicas, the hub is owned by the first member clockwise from
+// the hash of the hub name. Only the hubs of the leaving/joining member are moved when the membership changes.
+type HashRing struct {
+	hashes  []uint32
+	members map[uint32]string
+}
+
+func NewHashRing(members []string, virtualNodes int) *HashRing {
+	if virtualNodes <= 0 {
+		virtualNodes = defaultVirtualNodes
+	}
+	ring := &HashRing{
+		hashes:  make([]uint32, 0, len(members)*virtualNodes),
+		members: make(map[uint32]string, len(members)*virtualNodes),
+	}
+	for _, member := range members {
+		for i := 0; i < virtualNodes; i++ {
+			hash := hashKey(member + "#" + strconv.Itoa(i))
+			// keep the smaller member on the collision, so that all the replicas build the same ring
+			if existing, found := ring.members[hash]; found && existing < member {
+				continue
+			} else if !found {
+				ring.hashes = append(ring.hashes, hash)
+			}
+			ring.members[hash] = member
+		}
+	}
+	sort.Slice(ring.hashes, func(i, j int) bool { return ring.hashes[i] < ring.hashes[j] })
+	return ring
+}
+
+// Owner returns the member which owns the key, it's empty if there isn't any member on the ring
+func (r *HashRing) Owner(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	hash := hashKey(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+	return r.members[r.hashes[idx]]
+}
+
+// hashKey uses the sha256 instead of the fnv, since the fnv doesn't spread the similar keys(e.g. hub1, hub2) evenly
+func hashKey(key string) uint32 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
@@ -0,0 +1,47 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestHashRing(t *testing.T) {
+	empty := NewHashRing(nil, 0)
+	if owner := empty.Owner("hub1"); owner != "" {
+		t.Fatalf("expected no owner on the empty ring, but got %s", owner)
+	}
+
+	hubs := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		hubs = append(hubs, fmt.Sprintf("hub%d", i))
+	}
+
+	ring := NewHashRing([]string{"manager-a", "manager-b", "manager-c"}, 0)
+	// the ring is built in the same way regardless of the order of the members
+	reordered := NewHashRing([]string{"manager-c", "manager-a", "manager-b"}, 0)
+	owned := map[string]int{}
+	for _, hub := range hubs {
+		owner := ring.Owner(hub)
+		if owner != reordered.Owner(hub) {
+			t.Fatalf("the hub %s is owned by different members", hub)
+		}
+		owned[owner]++
+	}
+	for member, count := range owned {
+		if count < 50 {
+			t.Errorf("the member %s only owns %d of the %d hubs", member, count, len(hubs))
+		}
+	}
+
+	// only the hubs of the leaving member are moved to the others
+	shrunk := NewHashRing([]string{"manager-a", "manager-b"}, 0)
+	for _, hub := range hubs {
+		before, after := ring.Owner(hub), shrunk.Owner(hub)
+		if before != "manager-c" && before != after {
+			t.Errorf("the hub %s is moved from %s to %s", hub, before, after)
+		}
+		if after == "manager-c" {
+			t.Errorf("the hub %s is still owned by the leaving member", hub)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	genericconsumer "github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+)
+
+const (
+	// the status topic of each hub is "status.<hub>" in the multiple topics mode
+	statusTopicPrefix = "status."
+
+	topicRefreshInterval = 30 * time.Second
+	metadataTimeoutMs    = 10000
+)
+
+// ShardConsumer consumes the event topic and the status topics of the hubs owned by the current manager replica.
+// The replicas are in the same consumer group, so the partitions of the shared event topic are balanced among them
+// by kafka. The consumer is recreated with the new topics once the owned hubs are changed.
+type ShardConsumer struct {
+	log             logr.Logger
+	transportConfig *transport.TransportConfig
+	coordinator     *ShardCoordinator
+	eventChan       chan *cloudevents.Event
+	membersChanged  chan struct{}
+
+	mutex sync.RWMutex
+	// the running consumer of the owned topics, it's nil while the consumer is being recreated
+	consumer *genericconsumer.GenericConsumer
+}
+
+func NewShardConsumer(transportConfig *transport.TransportConfig, coordinator *ShardCoordinator,
+) (*ShardConsumer, error) {
+	if transportConfig.TransportType != string(transport.Kafka) {
+		return nil, fmt.Errorf("the hub sharding isn't supported by the transport %s", transportConfig.TransportType)
+	}
+	if !strings.HasPrefix(transportConfig.KafkaConfig.Topics.StatusTopic, "^") {
+		return nil, fmt.Errorf("the hub sharding requires the status topic per hub, but got the shared topic %s",
+			transportConfig.KafkaConfig.Topics.StatusTopic)
+	}
+	c := &ShardConsumer{
+		log:             ctrl.Log.WithName("shard-consumer"),
+		transportConfig: transportConfig,
+		coordinator:     coordinator,
+		eventChan:       make(chan *cloudevents.Event),
+		membersChanged:  make(chan struct{}, 1),
+	}
+	coordinator.OnChange(func() {
+		select {
+		case c.membersChanged <- struct{}{}:
+		default:
+		}
+	})
+	return c, nil
+}
+
+func (c *ShardConsumer) EventChan() chan *cloudevents.Event {
+	return c.eventChan
+}
+
+func (c *ShardConsumer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(topicRefreshInterval)
+	defer ticker.Stop()
+
+	var subscribedTopics []string
+	var stopConsumer func()
+	defer func() {
+		if stopConsumer != nil {
+			stopConsumer()
+		}
+	}()
+
+	for {
+		topics, err := c.ownedTopics()
+		if err != nil {
+			c.log.Error(err, "failed to list the status topics of the owned hubs")
+		} else if !reflect.DeepEqual(topics, subscribedTopics) {
+			c.log.Info("subscribe to the topics of the owned hubs", "topics", topics)
+			if stopConsumer != nil {
+				stopConsumer()
+			}
+			stopConsumer, err = c.startConsumer(ctx, topics)
+			if err != nil {
+				c.log.Error(err, "failed to start the consumer", "topics", topics)
+				subscribedTopics = nil
+			} else {
+				subscribedTopics = topics
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			c.log.Info("stopped shard consumer")
+			return nil
+		case <-ticker.C:
+		case <-c.membersChanged:
+		}
+	}
+}
+
+func (c *ShardConsumer) NeedLeaderElection() bool {
+	return false
+}
+
+// startConsumer starts a generic consumer with the topics, and forwards the received events until it's stopped by
+// the returned function
+func (c *ShardConsumer) startConsumer(ctx context.Context, topics []string) (func(), error) {
+	consumer, err := genericconsumer.NewGenericConsumer(c.transportConfig, topics,
+		genericconsumer.EnableDatabaseOffset(true))
+	if err != nil {
+		return nil, err
+	}
+
+	c.setConsumer(consumer)
+	consumerCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := consumer.Start(consumerCtx); err != nil {
+			c.log.Error(err, "the consumer is stopped", "topics", topics)
+		}
+	}()
+	go func() {
+		// keep draining the events until the receiver is stopped, otherwise the receiver is blocked forever
+		for {
+			select {
+			case <-stopped:
+				return
+			case evt := <-consumer.EventChan():
+				select {
+				case c.eventChan <- evt:
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	return func() {
+		c.setConsumer(nil)
+		cancel()
+		<-stopped
+	}, nil
+}
+
+func (c *ShardConsumer) setConsumer(consumer *genericconsumer.GenericConsumer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.consumer = consumer
+}
+
+// Assignment returns the partitions currently assigned to the replica keyed by the topics, it's empty while the
+// consumer is being recreated
+func (c *ShardConsumer) Assignment() (map[string][]int32, error) {
+	c.mutex.RLock()
+	consumer := c.consumer
+	c.mutex.RUnlock()
+	if consumer == nil {
+		return map[string][]int32{}, nil
+	}
+	return consumer.Assignment()
+}
+
+// OwnsPartition returns true if the offset of the partition is committed by the current replica. The status topics
+// are owned by the replicas of their hubs, and the partitions of the shared event topic are owned by the replicas
+// assigned with them by kafka, so the replica revoked with a partition doesn't move its offset backwards.
+func (c *ShardConsumer) OwnsPartition(assignment map[string][]int32, topic string, partition int32) bool {
+	if hubName := HubOfTopic(topic); hubName != "" {
+		return c.coordinator.OwnsHub(hubName)
+	}
+	return slices.Contains(assignment[topic], partition)
+}
+
+// ownedTopics returns the sorted topics to consume, including the event topic and the status topics of the owned
+// hubs which are discovered from the kafka metadata
+func (c *ShardConsumer) ownedTopics() ([]string, error) {
+	configMap, err := config.GetConfluentConfigMap(c.transportConfig.KafkaConfig, false)
+	if err != nil {
+		return nil, err
+	}
+	adminClient, err := kafka.NewAdminClient(configMap)
+	if err != nil {
+		return nil, err
+	}
+	defer adminClient.Close()
+
+	metadata, err := adminClient.GetMetadata(nil, true, metadataTimeoutMs)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := []string{}
+	for topic := range metadata.Topics {
+		hubName, found := strings.CutPrefix(topic, statusTopicPrefix)
+		if found && c.coordinator.OwnsHub(hubName) {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+	return append([]string{c.transportConfig.KafkaConfig.Topics.EventTopic}, topics...), nil
+}
+
+// HubOfTopic returns the hub name of the status topic, it's empty if the topic doesn't belong to any hub
+func HubOfTopic(topic string) string {
+	if hubName, found := strings.CutPrefix(topic, statusTopicPrefix); found {
+		return hubName
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package sharding
+
+import (
+	"testing"
+)
+
+func TestOwnsPartition(t *testing.T) {
+	coordinator := NewShardCoordinator(nil, "test-ns", "manager-a")
+	coordinator.members = []string{"manager-a", "manager-b"}
+	coordinator.ring = NewHashRing(coordinator.members, defaultVirtualNodes)
+	consumer := &ShardConsumer{coordinator: coordinator}
+
+	// the members can't be changed by the callers
+	members := coordinator.Members()
+	members[0] = "manager-c"
+	if coordinator.Members()[0] != "manager-a" {
+		t.Fatalf("the members of the coordinator are changed by the caller: %v", coordinator.Members())
+	}
+
+	var ownedHub, otherHub string
+	for i := 0; ownedHub == "" || otherHub == ""; i++ {
+		hubName := "hub" + string(rune('a'+i))
+		if coordinator.OwnsHub(hubName) {
+			ownedHub = hubName
+		} else {
+			otherHub = hubName
+		}
+	}
+
+	assignment := map[string][]int32{"event": {0, 2}}
+	cases := []struct {
+		topic     string
+		partition int32
+		owned     bool
+	}{
+		{statusTopicPrefix + ownedHub, 0, true},
+		{statusTopicPrefix + otherHub, 0, false},
+		// the partitions of the shared event topic are owned by the kafka assignment
+		{"event", 0, true},
+		{"event", 1, false},
+		{"event", 2, true},
+	}
+	for _, c := range cases {
+		if owned := consumer.OwnsPartition(assignment, c.topic, c.partition); owned != c.owned {
+			t.Fatalf("expected the partition %d of the topic %s owned: %v, but got %v", c.partition, c.topic,
+				c.owned, owned)
+		}
+	}
+
+	// nothing of the event topic is owned while the consumer is being recreated
+	assignment, err := consumer.Assignment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if consumer.OwnsPartition(assignment, "event", 0) {
+		t.Fatalf("expected the event topic isn't owned without the consumer")
+	}
+}
@@ -0,0 +1,186 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// the lease of each manager replica is labeled with it, the live leases are the members of the hash ring
+	shardLeaseLabelKey = "global-hub.open-cluster-management.io/manager-shard"
+	shardLeasePrefix   = "multicluster-global-hub-manager-shard-"
+
+	defaultLeaseDuration = 30 * time.Second
+	defaultRenewInterval = 10 * time.Second
+)
+
+// ShardCoordinator renews the lease of the current manager replica, and partitions the managed hubs among the
+// replicas with the live leases by the consistent hashing. It runs on all the replicas instead of the leader only.
+type ShardCoordinator struct {
+	log           logr.Logger
+	kubeClient    kubernetes.Interface
+	namespace     string
+	identity      string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+
+	mutex     sync.RWMutex
+	members   []string
+	ring      *HashRing
+	listeners []func()
+}
+
+func NewShardCoordinator(kubeClient kubernetes.Interface, namespace, identity string) *ShardCoordinator {
+	return &ShardCoordinator{
+		log:           ctrl.Log.WithName("shard-coordinator"),
+		kubeClient:    kubeClient,
+		namespace:     namespace,
+		identity:      identity,
+		leaseDuration: defaultLeaseDuration,
+		renewInterval: defaultRenewInterval,
+		// the replica owns nothing until it joins the ring
+		ring: NewHashRing(nil, defaultVirtualNodes),
+	}
+}
+
+// OnChange registers the function which is invoked when the members of the ring are changed
+func (c *ShardCoordinator) OnChange(fn func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
+// OwnsHub returns true if the hub is assigned to the current manager replica
+func (c *ShardCoordinator) OwnsHub(hubName string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.ring.Owner(hubName) == c.identity
+}
+
+// Members returns a copy of the live members of the ring
+func (c *ShardCoordinator) Members() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]string{}, c.members...)
+}
+
+func (c *ShardCoordinator) Start(ctx context.Context) error {
+	c.log.Info("start shard coordinator", "identity", c.identity, "namespace", c.namespace)
+	ticker := time.NewTicker(c.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.renewLease(ctx); err != nil {
+			c.log.Error(err, "failed to renew the shard lease")
+		} else if err := c.syncMembers(ctx); err != nil {
+			c.log.Error(err, "failed to sync the shard members")
+		}
+
+		select {
+		case <-ctx.Done():
+			// release the lease, so the other replicas take over the hubs without waiting for the expiration
+			leaseName := shardLeasePrefix + c.identity
+			err := c.kubeClient.CoordinationV1().Leases(c.namespace).Delete(context.Background(), leaseName,
+				metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				c.log.Error(err, "failed to release the shard lease")
+			}
+			c.log.Info("stopped shard coordinator")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ShardCoordinator) NeedLeaderElection() bool {
+	return false
+}
+
+func (c *ShardCoordinator) renewLease(ctx context.Context) error {
+	leases := c.kubeClient.CoordinationV1().Leases(c.namespace)
+	leaseName := shardLeasePrefix + c.identity
+	now := metav1.NewMicroTime(time.Now())
+
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      leaseName,
+				Namespace: c.namespace,
+				Labels:    map[string]string{shardLeaseLabelKey: "true"},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(c.identity),
+				LeaseDurationSeconds: ptr.To(int32(c.leaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = ptr.To(c.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(c.leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// syncMembers rebuilds the ring with the holders of the live leases, and notifies the listeners if they're changed
+func (c *ShardCoordinator) syncMembers(ctx context.Context) error {
+	leaseList, err := c.kubeClient.CoordinationV1().Leases(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", shardLeaseLabelKey),
+	})
+	if err != nil {
+		return err
+	}
+	members := liveMembers(leaseList.Items, time.Now())
+
+	c.mutex.Lock()
+	if reflect.DeepEqual(members, c.members) {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.log.Info("shard members changed", "previous", c.members, "current", members)
+	c.members = members
+	c.ring = NewHashRing(members, defaultVirtualNodes)
+	listeners := c.listeners
+	c.mutex.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+	return nil
+}
+
+// liveMembers returns the sorted holders of the leases which aren't expired
+func liveMembers(leases []coordinationv1.Lease, now time.Time) []string {
+	members := []string{}
+	for _, lease := range leases {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil ||
+			lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiration := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if now.After(expiration) {
+			continue
+		}
+		members = append(members, *lease.Spec.HolderIdentity)
+	}
+	sort.Strings(members)
+	return members
+}
@@ -2,57 +2,254 @@ package statussyncer
 
 import (
 	"fmt"
+	"os"
 
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/workerpool"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dbmonitor"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/dispatcher"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/freshness"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/ratelimit"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/schemavalidator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/sequence"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/sharding"
 	dbsyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/syncers"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/usage"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/versionskew"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	genericconsumer "github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
+	"github.com/stolostron/multicluster-global-hub/pkg/version"
 )
 
 // AddStatusSyncers performs the initial setup required before starting the runtime manager.
 // adds controllers and/or runnables to the manager, registers handler to conflation manager
-func AddStatusSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig) error {
+// the producer requests the managed hubs to move on once the status is handled, e.g. the migration phases
+func AddStatusSyncers(mgr ctrl.Manager, managerConfig *config.ManagerConfig, producer transport.Producer) error {
+	var coordinator *sharding.ShardCoordinator
+	var electedChan <-chan struct{}
+	if managerConfig.EnableHubSharding {
+		var err error
+		if coordinator, err = addShardCoordinator(mgr, managerConfig); err != nil {
+			return err
+		}
+		// the status pipeline runs on all the replicas, each of them handles the status of its own hubs
+		mgr = &allReplicasManager{Manager: mgr}
+	} else if managerConfig.EnableWarmStandby {
+		// the status pipeline runs on the standby replicas as well, but the consumer is paused until it's elected
+		electedChan = mgr.Elected()
+		mgr = &allReplicasManager{Manager: mgr}
+	}
+
 	// create statistics
 	stats := statistics.NewStatistics(managerConfig.StatisticsConfig)
 	if err := mgr.Add(stats); err != nil {
 		return err
 	}
 
-	// manage all Conflation Units and handlers
-	conflationManager := conflator.NewConflationManager(stats)
-	registerHandler(conflationManager, managerConfig.EnableGlobalResource)
+	// pause the status pipeline during the database outage
+	dbMonitor := dbmonitor.NewDatabaseMonitor(database.GetCircuitBreaker(), database.GetSqlDb().PingContext)
+	if err := mgr.Add(dbMonitor); err != nil {
+		return err
+	}
+
+	// manage all Conflation Units and handlers, the ready queue has a lane for each of the db workers
+	conflationManager := conflator.NewConflationManager(stats,
+		workerpool.PoolSize(managerConfig.DatabaseConfig.MaxOpenConns))
+	registerHandler(conflationManager, managerConfig.EnableGlobalResource, producer)
+	if managerConfig.ConflationConfigMap != "" {
+		if err := mgr.Add(conflator.NewConflationConfigWatcher(mgr.GetAPIReader(), managerConfig.ManagerNamespace,
+			managerConfig.ConflationConfigMap, conflationManager.GetConfig())); err != nil {
+			return err
+		}
+	}
 
 	// start consume message from transport to conflation manager
-	if err := dispatcher.AddTransportDispatcher(mgr, managerConfig, conflationManager, stats); err != nil {
+	consumer, err := newStatusConsumer(managerConfig, coordinator, electedChan)
+	if err != nil {
+		return fmt.Errorf("failed to initialize transport consumer: %w", err)
+	}
+	// the received events are admitted by the filters in order
+	filters := []dispatcher.AdmissionFilter{}
+	if managerConfig.SigningPublicKeysDir != "" {
+		verifier, err := signature.NewVerifier(managerConfig.SigningPublicKeysDir)
+		if err != nil {
+			return fmt.Errorf("failed to initialize the signature verifier: %w", err)
+		}
+		filters = append(filters, dispatcher.NewSignatureFilter(verifier))
+	}
+	// the verified events are accounted to their hubs even if they aren't admitted, since they're consumed
+	usageRecorder := usage.NewRecorder()
+	if err := mgr.Add(usageRecorder); err != nil {
+		return err
+	}
+	filters = append(filters, usageRecorder)
+	if managerConfig.EnableSchemaValidation {
+		filters = append(filters, schemavalidator.NewValidator())
+	}
+	filters = append(filters, sequence.NewDetector())
+	filters = append(filters, versionskew.NewChecker(version.Get(), managerConfig.MaxAgentMinorVersionSkew,
+		managerConfig.QuarantineIncompatibleAgents))
+	rateLimiter := ratelimit.NewLimiter(mgr.GetClient(), managerConfig.HubEventsPerSecond, managerConfig.HubEventBurst)
+	if err := mgr.Add(rateLimiter); err != nil {
 		return err
 	}
+	filters = append(filters, rateLimiter)
+	// the freshness is only recorded for the admitted events
+	freshnessTracker := freshness.NewTracker(managerConfig.DataFreshnessExpectedIntervals)
+	if err := mgr.Add(freshnessTracker); err != nil {
+		return err
+	}
+	filters = append(filters, freshnessTracker)
+	if err := dispatcher.AddTransportDispatcher(mgr, consumer, conflationManager, stats, dbMonitor,
+		filters); err != nil {
+		return err
+	}
+
+	// the status of the managed hubs connected to the additional kafka clusters is conflated into the same database
+	for _, transportConfig := range managerConfig.AdditionalTransportConfigs {
+		if coordinator != nil {
+			return fmt.Errorf("the additional kafka clusters aren't supported with the hub sharding")
+		}
+		additionalConsumer, err := newAdditionalStatusConsumer(transportConfig, electedChan)
+		if err != nil {
+			return fmt.Errorf("failed to initialize the consumer of the kafka cluster %s: %w",
+				transportConfig.KafkaConfig.ClusterIdentity, err)
+		}
+		if err := dispatcher.AddTransportDispatcher(mgr, additionalConsumer, conflationManager, stats, dbMonitor,
+			filters); err != nil {
+			return err
+		}
+	}
 
 	// start persist event from conflation manager to database with registered handlers
-	if err := dispatcher.AddConflationDispatcher(mgr, conflationManager, managerConfig, stats); err != nil {
+	if err := dispatcher.AddConflationDispatcher(mgr, conflationManager, managerConfig, stats, dbMonitor); err != nil {
 		return err
 	}
 
 	// add kafka offset to the database periodically
-	committer := conflator.NewKafkaConflationCommitter(conflationManager.GetMetadatas)
+	metadataFunc := conflationManager.GetMetadatas
+	if shardConsumer, ok := consumer.(*sharding.ShardConsumer); ok {
+		// only commit the offsets of the owned partitions, the ones moved to the other replicas are committed by them
+		metadataFunc = func() []conflator.ConflationMetadata {
+			metadatas := []conflator.ConflationMetadata{}
+			assignment, err := shardConsumer.Assignment()
+			if err != nil {
+				ctrl.Log.WithName("status-syncers").Error(err, "failed to get the assigned partitions")
+				return metadatas
+			}
+			for _, metadata := range conflationManager.GetMetadatas() {
+				position := metadata.TransportPosition()
+				if shardConsumer.OwnsPartition(assignment, position.Topic, position.Partition) {
+					metadatas = append(metadatas, metadata)
+				}
+			}
+			return metadatas
+		}
+	}
+	committer := conflator.NewKafkaConflationCommitter(metadataFunc, dbMonitor)
 	if err := mgr.Add(committer); err != nil {
 		return fmt.Errorf("failed to start the offset committer: %w", err)
 	}
 	return nil
 }
 
-func registerHandler(cmr *conflator.ConflationManager, enableGlobalResource bool) {
+func addShardCoordinator(mgr ctrl.Manager, managerConfig *config.ManagerConfig) (*sharding.ShardCoordinator, error) {
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the kube client: %w", err)
+	}
+	// the hostname is the pod name, which is unique among the manager replicas
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the identity of the manager replica: %w", err)
+	}
+	coordinator := sharding.NewShardCoordinator(kubeClient, managerConfig.ManagerNamespace, identity)
+	if err := mgr.Add(coordinator); err != nil {
+		return nil, fmt.Errorf("failed to add the shard coordinator: %w", err)
+	}
+	return coordinator, nil
+}
+
+// newStatusConsumer consumes the event topic and the status topics, only the status topics of the owned hubs are
+// consumed when the hubs are sharded across the manager replicas
+func newStatusConsumer(managerConfig *config.ManagerConfig, coordinator *sharding.ShardCoordinator,
+	electedChan <-chan struct{},
+) (transport.Consumer, error) {
+	if coordinator != nil {
+		return sharding.NewShardConsumer(managerConfig.TransportConfig, coordinator)
+	}
+	opts := []genericconsumer.GenericConsumeOption{genericconsumer.EnableDatabaseOffset(true)}
+	if electedChan != nil {
+		opts = append(opts, genericconsumer.EnableWarmStandby(electedChan))
+	}
+	topics := managerConfig.TransportConfig.KafkaConfig.Topics
+	return genericconsumer.NewGenericConsumer(managerConfig.TransportConfig,
+		[]string{topics.EventTopic, topics.StatusTopic}, opts...)
+}
+
+// newAdditionalStatusConsumer consumes the event topic and the status topics of the additional kafka cluster
+func newAdditionalStatusConsumer(transportConfig *transport.TransportConfig, electedChan <-chan struct{},
+) (transport.Consumer, error) {
+	opts := []genericconsumer.GenericConsumeOption{
+		genericconsumer.EnableDatabaseOffset(true),
+		genericconsumer.AsAdditionalCluster(),
+	}
+	if electedChan != nil {
+		opts = append(opts, genericconsumer.EnableWarmStandby(electedChan))
+	}
+	topics := transportConfig.KafkaConfig.Topics
+	return genericconsumer.NewGenericConsumer(transportConfig, []string{topics.EventTopic, topics.StatusTopic}, opts...)
+}
+
+// allReplicasManager adds the runnables which run on all the manager replicas instead of the leader only
+type allReplicasManager struct {
+	ctrl.Manager
+}
+
+func (m *allReplicasManager) Add(runnable manager.Runnable) error {
+	return m.Manager.Add(&allReplicasRunnable{Runnable: runnable})
+}
+
+type allReplicasRunnable struct {
+	manager.Runnable
+}
+
+func (r *allReplicasRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+func registerHandler(cmr *conflator.ConflationManager, enableGlobalResource bool, producer transport.Producer) {
 	dbsyncer.NewHubClusterHeartbeatHandler().RegisterHandler(cmr)
 	dbsyncer.NewHubClusterInfoHandler().RegisterHandler(cmr)
 	dbsyncer.NewManagedClusterHandler().RegisterHandler(cmr)
+	dbsyncer.NewManagedClusterDeltaHandler().RegisterHandler(cmr)
+	dbsyncer.NewManagedClusterAddonHandler().RegisterHandler(cmr)
+	dbsyncer.NewManagedServiceAccountHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalPolicySpecHandler().RegisterHandler(cmr)
+	dbsyncer.NewLocalPolicySpecDeltaHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalPolicyComplianceHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalPolicyCompleteHandler().RegisterHandler(cmr)
+	dbsyncer.NewPolicyReportHandler().RegisterHandler(cmr)
+	dbsyncer.NewGatekeeperConstraintHandler().RegisterHandler(cmr)
+	dbsyncer.NewSecurityPostureHandler().RegisterHandler(cmr)
+	dbsyncer.NewPolicyTemplateDetailHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalEventPolicyHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalPolicyEventHandler().RegisterHandler(cmr)
 	dbsyncer.NewLocalPlacementRuleSpecHandler().RegisterHandler(cmr)
+	dbsyncer.NewArgoApplicationHandler().RegisterHandler(cmr)
+	dbsyncer.NewArgoApplicationSetHandler().RegisterHandler(cmr)
+	dbsyncer.NewHubResourceCountsHandler().RegisterHandler(cmr)
+	dbsyncer.NewHubMetricsHandler().RegisterHandler(cmr)
+	dbsyncer.NewRegionalHubSummaryHandler().RegisterHandler(cmr)
+	dbsyncer.NewManagedClusterMigrationHandler(producer).RegisterHandler(cmr)
 	if enableGlobalResource {
 		dbsyncer.NewPolicyComplianceHandler().RegisterHandler(cmr)
 		dbsyncer.NewPolicyCompleteHandler().RegisterHandler(cmr)
@@ -63,7 +260,13 @@ func registerHandler(cmr *conflator.ConflationManager, enableGlobalResource bool
 		dbsyncer.NewPlacementHandler().RegisterHandler(cmr)
 		dbsyncer.NewPlacementDecisionHandler().RegisterHandler(cmr)
 
+		dbsyncer.NewManagedClusterSetHandler().RegisterHandler(cmr)
+		dbsyncer.NewManagedClusterSetBindingHandler().RegisterHandler(cmr)
+
 		dbsyncer.NewSubscriptionReportHandler().RegisterHandler(cmr)
 		dbsyncer.NewSubscriptionStatusHandler().RegisterHandler(cmr)
+
+		dbsyncer.NewResourceConflictHandler().RegisterHandler(cmr)
+		dbsyncer.NewSpecApplyResultHandler().RegisterHandler(cmr)
 	}
 }
@@ -0,0 +1,29 @@
+package dbsyncer
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// NewArgoApplicationHandler stores the argocd applications from the managed hubs, the sync and health status of
+// them are exposed by the status.argocd_application_status view.
+func NewArgoApplicationHandler() conflator.Handler {
+	return NewGenericHandler[*unstructured.Unstructured](
+		string(enum.ArgoApplicationType),
+		conflator.ArgoApplicationPriority,
+		enum.CompleteStateMode,
+		fmt.Sprintf("%s.%s", database.StatusSchema, database.ArgoApplicationsTableName))
+}
+
+func NewArgoApplicationSetHandler() conflator.Handler {
+	return NewGenericHandler[*unstructured.Unstructured](
+		string(enum.ArgoApplicationSetType),
+		conflator.ArgoApplicationSetPriority,
+		enum.CompleteStateMode,
+		fmt.Sprintf("%s.%s", database.StatusSchema, database.ArgoApplicationSetsTableName))
+}
@@ -0,0 +1,94 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "ArgoApplicationHandler"
+var _ = Describe("ArgoApplicationHandler", Ordered, func() {
+	leafHubName := "hub1"
+
+	It("should be able to sync argocd applicationset and application event", func() {
+		By("Create applicationset event")
+		appSet := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "ApplicationSet",
+			"metadata": map[string]interface{}{
+				"name":            "guestbook",
+				"namespace":       "openshift-gitops",
+				"uid":             "d0ac7e2b-5a4c-4d4b-9ff0-9c5c5e6f2a01",
+				"resourceVersion": "1",
+			},
+			"status": map[string]interface{}{
+				"resources": []interface{}{
+					map[string]interface{}{
+						"kind":      "Application",
+						"name":      "cluster1-guestbook",
+						"namespace": "openshift-gitops",
+					},
+				},
+			},
+		}}
+		version := eventversion.NewVersion()
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ArgoApplicationSetType), version, []interface{}{appSet})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Create application event")
+		app := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "argoproj.io/v1alpha1",
+			"kind":       "Application",
+			"metadata": map[string]interface{}{
+				"name":            "cluster1-guestbook",
+				"namespace":       "openshift-gitops",
+				"uid":             "4b1f0a0e-2d3c-4e8e-8f8a-3a3a2f1e9c02",
+				"resourceVersion": "1",
+			},
+			"spec": map[string]interface{}{
+				"destination": map[string]interface{}{
+					"name": "cluster1",
+				},
+			},
+			"status": map[string]interface{}{
+				"sync":   map[string]interface{}{"status": "Synced"},
+				"health": map[string]interface{}{"status": "Healthy"},
+			},
+		}}
+		version = eventversion.NewVersion()
+		version.Incr()
+		evt = ToCloudEvent(leafHubName, string(enum.ArgoApplicationType), version, []interface{}{app})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the application status view")
+		Eventually(func() error {
+			type appStatus struct {
+				ApplicationsetName string
+				Destination        string
+				SyncStatus         string
+				HealthStatus       string
+			}
+			var statuses []appStatus
+			err := database.GetGorm().Raw(fmt.Sprintf(
+				`SELECT applicationset_name, destination, sync_status, health_status FROM %s.%s
+				WHERE leaf_hub_name = ? AND name = ?`, database.StatusSchema, database.ArgoApplicationStatusViewName),
+				leafHubName, app.GetName()).Scan(&statuses).Error
+			if err != nil {
+				return err
+			}
+			expected := appStatus{"guestbook", "cluster1", "Synced", "Healthy"}
+			if len(statuses) != 1 || statuses[0] != expected {
+				return fmt.Errorf("unexpected application status: %v", statuses)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
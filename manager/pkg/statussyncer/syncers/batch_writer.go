@@ -0,0 +1,22 @@
+package dbsyncer
+
+import (
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/dao"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// batchSize is the max number of the rows written by a single statement, it keeps the bind parameters of the
+// statement far below the postgres limit(65535)
+const batchSize = 1000
+
+// newComplianceWriter returns the pgx writer of the compliance table. The compliance per cluster is the largest
+// part of the status ingestion, so it's written with the prepared statements instead of gorm.
+func newComplianceWriter(local bool) *dao.ComplianceWriter {
+	if local {
+		return dao.NewComplianceWriter(database.GetPgxPool(), models.LocalStatusCompliance{}.TableName(),
+			"local_status.compliance_type")
+	}
+	return dao.NewComplianceWriter(database.GetPgxPool(), models.StatusCompliance{}.TableName(),
+		"status.compliance_type")
+}
@@ -0,0 +1,50 @@
+package dbsyncer
+
+import (
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/eventexporter"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/notification"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// notifyComplianceChanges sends the compliances which are different from the previous ones to the notification
+// engine, and exports the ones turning into non compliant as the policy violations. The cluster missing in the
+// previous compliances is regarded as the defaultPrevious.
+func notifyComplianceChanges(localPolicy bool, previous map[string]database.ComplianceStatus,
+	defaultPrevious database.ComplianceStatus, compliances []models.StatusCompliance,
+) {
+	now := time.Now()
+	events := []*notification.ComplianceEvent{}
+	violations := []eventexporter.PolicyViolationEvent{}
+	for _, compliance := range compliances {
+		previousCompliance, found := previous[compliance.ClusterName]
+		if !found {
+			previousCompliance = defaultPrevious
+		}
+		if previousCompliance == compliance.Compliance {
+			continue
+		}
+		events = append(events, &notification.ComplianceEvent{
+			LeafHubName:        compliance.LeafHubName,
+			PolicyID:           compliance.PolicyID,
+			ClusterName:        compliance.ClusterName,
+			Compliance:         string(compliance.Compliance),
+			PreviousCompliance: string(previousCompliance),
+			LocalPolicy:        localPolicy,
+			Time:               now,
+		})
+		if compliance.Compliance == database.NonCompliant {
+			violations = append(violations, eventexporter.PolicyViolationEvent{
+				LeafHubName:        compliance.LeafHubName,
+				PolicyID:           compliance.PolicyID,
+				ClusterName:        compliance.ClusterName,
+				PreviousCompliance: string(previousCompliance),
+				LocalPolicy:        localPolicy,
+			})
+		}
+	}
+	notification.NotifyComplianceChanges(events...)
+	eventexporter.ExportPolicyViolations(violations...)
+}
@@ -0,0 +1,25 @@
+package dbsyncer
+
+import (
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// normalizePolicyEvent sets the normalized taxonomy of the policy event, the involved object is the policy named by
+// the event if the agent of the older version doesn't report it.
+func normalizePolicyEvent(evt *event.BaseEvent, compliance string, model *models.BaseLocalPolicyEvent) {
+	normalized := event.Normalize(evt, event.InvolvedObject{
+		Kind:      policiesv1.Kind,
+		Namespace: evt.EventNamespace,
+		Name:      event.PolicyNameOfEvent(evt.EventName),
+	}, compliance)
+
+	model.EventType = normalized.Type
+	model.Severity = string(normalized.Severity)
+	model.Category = string(normalized.Category)
+	model.InvolvedKind = normalized.Kind
+	model.InvolvedNamespace = normalized.Namespace
+	model.InvolvedName = normalized.Name
+}
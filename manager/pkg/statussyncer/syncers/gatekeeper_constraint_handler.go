@@ -0,0 +1,112 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type gatekeeperConstraintHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewGatekeeperConstraintHandler stores the audit results of the gatekeeper constraints reported by the agent into
+// the gatekeeper constraints and violations tables.
+func NewGatekeeperConstraintHandler() conflator.Handler {
+	eventType := string(enum.GatekeeperConstraintType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &gatekeeperConstraintHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.GatekeeperConstraintPriority,
+	}
+}
+
+func (h *gatekeeperConstraintHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *gatekeeperConstraintHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := grc.GatekeeperConstraintBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	constraints := make([]models.GatekeeperConstraint, 0, len(data))
+	violations := []models.GatekeeperViolation{}
+	for _, constraint := range data {
+		constraints = append(constraints, models.GatekeeperConstraint{
+			LeafHubName:       leafHubName,
+			ClusterName:       constraint.ClusterName,
+			ConstraintKind:    constraint.Kind,
+			ConstraintName:    constraint.Name,
+			EnforcementAction: constraint.EnforcementAction,
+			TotalViolations:   constraint.TotalViolations,
+			AuditTimestamp:    constraint.AuditTimestamp,
+		})
+		for _, violation := range constraint.Violations {
+			violations = append(violations, models.GatekeeperViolation{
+				LeafHubName:       leafHubName,
+				ClusterName:       constraint.ClusterName,
+				ConstraintKind:    constraint.Kind,
+				ConstraintName:    constraint.Name,
+				ResourceKind:      violation.Kind,
+				ResourceNamespace: violation.Namespace,
+				ResourceName:      violation.Name,
+				Message:           violation.Message,
+				EnforcementAction: violation.EnforcementAction,
+			})
+		}
+	}
+
+	// the bundle contains all the constraints of the hub, so the records of the hub are replaced by them
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.GatekeeperViolation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.GatekeeperConstraint{}).Error; err != nil {
+			return err
+		}
+		if len(constraints) > 0 {
+			if err := tx.CreateInBatches(constraints, batchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(violations) > 0 {
+			return tx.CreateInBatches(violations, batchSize).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the gatekeeper constraints of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
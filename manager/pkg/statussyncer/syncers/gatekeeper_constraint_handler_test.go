@@ -0,0 +1,91 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "GatekeeperConstraintHandler"
+var _ = Describe("GatekeeperConstraintHandler", Ordered, func() {
+	leafHubName := "hub-gatekeeper"
+	version := eventversion.NewVersion()
+	auditTime := time.Now().UTC().Truncate(time.Second)
+	requiredLabels := grc.GatekeeperConstraint{
+		Kind:              "K8sRequiredLabels",
+		Name:              "ns-must-have-owner",
+		ClusterName:       leafHubName,
+		EnforcementAction: "deny",
+		TotalViolations:   2,
+		AuditTimestamp:    &auditTime,
+		Violations: []grc.GatekeeperViolation{
+			{Kind: "Namespace", Name: "default", Message: "no owner", EnforcementAction: "deny"},
+			{Kind: "Namespace", Name: "test", Message: "no owner", EnforcementAction: "deny"},
+		},
+	}
+	allowedRepos := grc.GatekeeperConstraint{
+		Kind:              "K8sAllowedRepos",
+		Name:              "repo-is-quay",
+		ClusterName:       leafHubName,
+		EnforcementAction: "dryrun",
+	}
+
+	checkTables := func(expectedConstraints, expectedViolations int) {
+		Eventually(func() error {
+			constraints := []models.GatekeeperConstraint{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&constraints).Error
+			if err != nil {
+				return err
+			}
+			if len(constraints) != expectedConstraints {
+				return fmt.Errorf("unexpected constraints: %v", constraints)
+			}
+
+			violations := []models.GatekeeperViolation{}
+			err = database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&violations).Error
+			if err != nil {
+				return err
+			}
+			if len(violations) != expectedViolations {
+				return fmt.Errorf("unexpected violations: %v", violations)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	}
+
+	It("should be able to sync the gatekeeper constraints", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.GatekeeperConstraintType), version,
+			grc.GatekeeperConstraintBundle{allowedRepos, requiredLabels})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the tables")
+		checkTables(2, 2)
+	})
+
+	It("should replace the constraints which are changed by the audit", func() {
+		By("Create event")
+		version.Incr()
+		requiredLabels.TotalViolations = 1
+		requiredLabels.Violations = requiredLabels.Violations[:1]
+		evt := ToCloudEvent(leafHubName, string(enum.GatekeeperConstraintType), version,
+			grc.GatekeeperConstraintBundle{requiredLabels})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the tables")
+		checkTables(1, 1)
+	})
+})
@@ -86,15 +86,14 @@ func (h *genericObjectHandler[T]) handleEvent(ctx context.Context, evt *cloudeve
 	err = db.Transaction(func(tx *gorm.DB) error {
 		genericDao := dao.NewGenericDao(tx, h.table)
 
+		insertedObjects := make(map[string]interface{})
 		for _, object := range data {
 			specificObj := object
 			uid := getGenericObjectUID(specificObj)
 			resourceVersionFromDB, objExistsInDB := idToVersionMapFromDB[uid]
 
 			if !objExistsInDB { // object not found in the db table
-				if e := genericDao.Insert(leafHubName, uid, object); e != nil {
-					return e
-				}
+				insertedObjects[uid] = object
 				continue
 			}
 
@@ -109,13 +108,16 @@ func (h *genericObjectHandler[T]) handleEvent(ctx context.Context, evt *cloudeve
 			}
 		}
 
+		if e := genericDao.BatchInsert(leafHubName, insertedObjects, batchSize); e != nil {
+			return e
+		}
+
 		// delete objects that in the db but were not sent in the bundle (leaf hub sends only living resources).
+		deletedIds := make([]string, 0, len(idToVersionMapFromDB))
 		for uid := range idToVersionMapFromDB {
-			if e := genericDao.Delete(leafHubName, uid); e != nil {
-				return e
-			}
+			deletedIds = append(deletedIds, uid)
 		}
-		return nil
+		return genericDao.BatchDelete(leafHubName, deletedIds, batchSize)
 	})
 	if err != nil {
 		return err
@@ -2,6 +2,7 @@ package dbsyncer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
@@ -54,5 +56,42 @@ func handleHeartbeatEvent(ctx context.Context, evt *cloudevents.Event) error {
 	if err != nil {
 		return fmt.Errorf("failed to update heartbeat %v", err)
 	}
+
+	// the heartbeat of the previous agent is an empty list, which doesn't contain the health of the agent
+	payload := cluster.HubHeartbeat{}
+	if err := evt.DataAs(&payload); err != nil || payload.AgentVersion == "" {
+		return nil
+	}
+	health, err := agentHealth(evt.Source(), &payload)
+	if err != nil {
+		return err
+	}
+	err = db.Clauses(clause.OnConflict{UpdateAll: true}).Create(health).Error
+	if err != nil {
+		return fmt.Errorf("failed to update agent health %v", err)
+	}
 	return nil
 }
+
+func agentHealth(leafHubName string, payload *cluster.HubHeartbeat) (*models.AgentHealth, error) {
+	collectors, err := json.Marshal(payload.Collectors)
+	if err != nil {
+		return nil, err
+	}
+	lastSyncTimes, err := json.Marshal(payload.LastSyncTimes)
+	if err != nil {
+		return nil, err
+	}
+	resourceCounts, err := json.Marshal(payload.ResourceCounts)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AgentHealth{
+		LeafHubName:    leafHubName,
+		AgentVersion:   payload.AgentVersion,
+		Collectors:     collectors,
+		LastSyncTimes:  lastSyncTimes,
+		ResourceCounts: resourceCounts,
+		ReportedAt:     time.Now(),
+	}, nil
+}
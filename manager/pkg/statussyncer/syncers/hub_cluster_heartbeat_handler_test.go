@@ -8,6 +8,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -48,6 +49,35 @@ var _ = Describe("HubClusterHeartbeatHandler", Ordered, func() {
 			return fmt.Errorf("not found heartbeat record on the table")
 		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
 	})
+
+	It("sync the agent health with the hubClusterHeartbeat bundle", func() {
+		By("Create hubClusterHeartbeat event")
+		version := eventversion.NewVersion()
+		version.Incr()
+		leafHubName := "hub-agent-health"
+		evt := ToCloudEvent(leafHubName, string(enum.HubClusterHeartbeatType), version, cluster.HubHeartbeat{
+			AgentVersion:   "v1.2.0",
+			Collectors:     []string{"status.hub_cluster_heartbeat", "status.managed_cluster"},
+			LastSyncTimes:  map[string]time.Time{"managedcluster": time.Now()},
+			ResourceCounts: map[string]int{cluster.ResourceManagedClusters: 3},
+		})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the agent health table")
+		Eventually(func() error {
+			health := models.AgentHealth{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).First(&health).Error
+			if err != nil {
+				return err
+			}
+			if health.AgentVersion != "v1.2.0" {
+				return fmt.Errorf("unexpected agent version: %s", health.AgentVersion)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
 })
 
 func ToCloudEvent(source, eventType string, version *eventversion.Version, data interface{}) *cloudevents.Event {
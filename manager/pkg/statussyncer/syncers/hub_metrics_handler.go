@@ -0,0 +1,83 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type hubMetricsHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewHubMetricsHandler appends the metric samples of the managed hubs to the history, so the fleet-level metrics are
+// kept centrally for the dashboards. The samples which are conflated by the newer ones are skipped.
+func NewHubMetricsHandler() conflator.Handler {
+	eventType := string(enum.HubMetricsType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &hubMetricsHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.HubMetricsPriority,
+	}
+}
+
+func (h *hubMetricsHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *hubMetricsHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	metrics := cluster.HubMetrics{}
+	if err := evt.DataAs(&metrics); err != nil {
+		return err
+	}
+	if len(metrics.Samples) == 0 {
+		return nil
+	}
+
+	rows := make([]models.HubMetric, 0, len(metrics.Samples))
+	for _, sample := range metrics.Samples {
+		labels, err := json.Marshal(sample.Labels)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, models.HubMetric{
+			LeafHubName: leafHubName,
+			MetricName:  sample.Name,
+			Labels:      labels,
+			Value:       sample.Value,
+			SampledAt:   metrics.SampledAt,
+		})
+	}
+	if err := database.GetGorm().CreateInBatches(rows, batchSize).Error; err != nil {
+		return fmt.Errorf("failed to store the metrics of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
@@ -0,0 +1,58 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "HubMetricsHandler"
+var _ = Describe("HubMetricsHandler", Ordered, func() {
+	leafHubName := "hub1"
+	version := eventversion.NewVersion()
+
+	It("should be able to append the metric samples", func() {
+		By("Create event")
+		version.Incr()
+		data := cluster.HubMetrics{
+			Samples: []cluster.HubMetricSample{
+				{Name: "policy_evaluation_latency_seconds", Value: 0.25},
+				{Name: "apiserver_request_latency_seconds", Labels: map[string]string{"verb": "LIST"}, Value: 1.5},
+			},
+			SampledAt: time.Now(),
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.HubMetricsType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			metrics := []models.HubMetric{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("metric_name").
+				Find(&metrics).Error; err != nil {
+				return err
+			}
+			if len(metrics) != 2 {
+				return fmt.Errorf("unexpected metrics: %v", metrics)
+			}
+			labels := map[string]string{}
+			if err := json.Unmarshal(metrics[0].Labels, &labels); err != nil {
+				return err
+			}
+			if metrics[0].Value != 1.5 || labels["verb"] != "LIST" {
+				return fmt.Errorf("unexpected sample: %v", metrics[0])
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
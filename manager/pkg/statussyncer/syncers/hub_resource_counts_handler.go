@@ -0,0 +1,97 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type hubResourceCountsHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewHubResourceCountsHandler stores the resource counts reported by the agent, they're compared with the records in
+// the database by the data consistency job.
+func NewHubResourceCountsHandler() conflator.Handler {
+	eventType := string(enum.HubResourceCountsType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &hubResourceCountsHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.HubResourceCountsPriority,
+	}
+}
+
+func (h *hubResourceCountsHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *hubResourceCountsHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	counts := cluster.HubResourceCounts{}
+	if err := evt.DataAs(&counts); err != nil {
+		return err
+	}
+
+	rows := make([]models.HubResourceCount, 0, len(counts.Counts))
+	resourceTypes := make([]string, 0, len(counts.Counts))
+	for resourceType, count := range counts.Counts {
+		rows = append(rows, models.HubResourceCount{
+			LeafHubName:   leafHubName,
+			ResourceType:  resourceType,
+			ReportedCount: count,
+			ReportedAt:    counts.CountedAt,
+		})
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+
+	// the reported counts replace the existing ones, the result of the last check is kept until the next check
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		deletion := tx.Where("leaf_hub_name = ?", leafHubName)
+		if len(resourceTypes) > 0 {
+			deletion = deletion.Where("resource_type NOT IN ?", resourceTypes)
+		}
+		if err := deletion.Delete(&models.HubResourceCount{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "leaf_hub_name"}, {Name: "resource_type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reported_count", "reported_at"}),
+		}).Create(&rows).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the resource counts of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
@@ -0,0 +1,80 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "HubResourceCountsHandler"
+var _ = Describe("HubResourceCountsHandler", Ordered, func() {
+	leafHubName := "hub1"
+	version := eventversion.NewVersion()
+
+	It("should be able to sync the resource counts", func() {
+		By("Create event")
+		version.Incr()
+		data := cluster.HubResourceCounts{
+			Counts: map[string]int{
+				cluster.ResourceManagedClusters: 3,
+				cluster.ResourceLocalPolicies:   2,
+			},
+			CountedAt: time.Now(),
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.HubResourceCountsType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			counts := []models.HubResourceCount{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&counts).Error; err != nil {
+				return err
+			}
+			if len(counts) != 2 {
+				return fmt.Errorf("unexpected resource counts: %v", counts)
+			}
+			for _, count := range counts {
+				if count.ReportedCount != data.Counts[count.ResourceType] {
+					return fmt.Errorf("unexpected count of %s: %d", count.ResourceType, count.ReportedCount)
+				}
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove the resource type which isn't reported", func() {
+		By("Create event")
+		version.Incr()
+		data := cluster.HubResourceCounts{
+			Counts:    map[string]int{cluster.ResourceManagedClusters: 4},
+			CountedAt: time.Now(),
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.HubResourceCountsType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			counts := []models.HubResourceCount{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&counts).Error; err != nil {
+				return err
+			}
+			if len(counts) != 1 || counts[0].ResourceType != cluster.ResourceManagedClusters ||
+				counts[0].ReportedCount != 4 {
+				return fmt.Errorf("unexpected resource counts: %v", counts)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
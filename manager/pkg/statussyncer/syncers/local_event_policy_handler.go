@@ -60,11 +60,11 @@ func (h *localEventPolicyHandler) handleEvent(ctx context.Context, evt *cloudeve
 	}
 
 	localRootPolicyEvent := []models.LocalRootPolicyEvent{}
-	for _, element := range data {
+	for i, element := range data {
 		if element.PolicyID == "" {
 			continue
 		}
-		localRootPolicyEvent = append(localRootPolicyEvent, models.LocalRootPolicyEvent{
+		rootPolicyEvent := models.LocalRootPolicyEvent{
 			BaseLocalPolicyEvent: models.BaseLocalPolicyEvent{
 				LeafHubName: leafHubName,
 				EventName:   element.EventName,
@@ -75,7 +75,9 @@ func (h *localEventPolicyHandler) handleEvent(ctx context.Context, evt *cloudeve
 				Compliance:  string(common.GetDatabaseCompliance(element.Compliance)),
 				CreatedAt:   element.CreatedAt.Time,
 			},
-		})
+		}
+		normalizePolicyEvent(&data[i].BaseEvent, element.Compliance, &rootPolicyEvent.BaseLocalPolicyEvent)
+		localRootPolicyEvent = append(localRootPolicyEvent, rootPolicyEvent)
 	}
 
 	db := database.GetGorm()
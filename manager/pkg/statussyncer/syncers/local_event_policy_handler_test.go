@@ -58,6 +58,12 @@ var _ = Describe("LocalEventPolicyHandler", Ordered, func() {
 			count := 0
 			for _, item := range items {
 				fmt.Println(item.LeafHubName, item.EventName, item.Message)
+				// the taxonomy is inferred from the reason and the compliance
+				if item.Severity != string(event.SeverityWarning) || item.Category != string(event.CategoryPropagation) ||
+					item.InvolvedName != "policy-limitrange" {
+					return fmt.Errorf("unexpected taxonomy of the event: %s/%s/%s", item.Severity, item.Category,
+						item.InvolvedName)
+				}
 				count++
 			}
 			if count > 0 {
@@ -62,8 +62,8 @@ func handleCompleteCompliance(log logr.Logger, ctx context.Context, evt *cloudev
 	db := database.GetGorm()
 
 	// policyID: {  nonCompliance: (cluster3, cluster4), unknowns: (cluster5) }
-	allCompleteRowsFromDB, err := getLocalComplianceClusterSets(db, "leaf_hub_name = ? AND compliance <> ?",
-		leafHub, database.Compliant)
+	allCompleteRowsFromDB, err := getLocalComplianceClusterSets(db,
+		"leaf_hub_name = ? AND compliance <> ? AND "+nonPolicyReportComplianceCondition, leafHub, database.Compliant)
 	if err != nil {
 		return err
 	}
@@ -143,18 +143,15 @@ func handleCompleteCompliance(log logr.Logger, ctx context.Context, evt *cloudev
 			})
 		}
 
-		err = db.Transaction(func(tx *gorm.DB) error {
-			for _, compliance := range batchLocalCompliance {
-				e := tx.Updates(compliance).Error
-				if e != nil {
-					return e
-				}
-			}
-			return nil
-		})
-		if err != nil {
+		compliances := make([]models.StatusCompliance, 0, len(batchLocalCompliance))
+		for _, compliance := range batchLocalCompliance {
+			compliances = append(compliances, models.StatusCompliance(compliance))
+		}
+		if err = newComplianceWriter(true).Update(ctx, compliances); err != nil {
 			return fmt.Errorf("failed to update compliances by complete event - %w", err)
 		}
+		notifyComplianceChanges(true, nonComplianceClusterSetsFromDB.GetComplianceByCluster(), database.Compliant,
+			compliances)
 
 		// for policies that are found in the db but not in the bundle - all clusters are Compliant (implicitly)
 		delete(allCompleteRowsFromDB, policyID)
@@ -9,7 +9,6 @@ import (
 	set "github.com/deckarep/golang-set"
 	"github.com/go-logr/logr"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
@@ -63,11 +62,13 @@ func handleCompliance(log logr.Logger, ctx context.Context, evt *cloudevents.Eve
 
 	db := database.GetGorm()
 	// policyID: { compliance: (cluster1, cluster2), nonCompliance: (cluster3, cluster4), unknowns: (cluster5) }
-	allComplianceClustersFromDB, err := getLocalComplianceClusterSets(db, "leaf_hub_name = ?", leafHub)
+	allComplianceClustersFromDB, err := getLocalComplianceClusterSets(db, "leaf_hub_name = ? AND "+nonPolicyReportComplianceCondition,
+		leafHub)
 	if err != nil {
 		return err
 	}
 
+	writer := newComplianceWriter(true)
 	for _, eventCompliance := range data { // every object is clusters list per policy with full state
 
 		policyID := eventCompliance.PolicyID
@@ -76,6 +77,7 @@ func handleCompliance(log logr.Logger, ctx context.Context, evt *cloudevents.Eve
 			complianceClustersFromDB = NewPolicyClusterSets()
 		}
 
+		previousCompliances := complianceClustersFromDB.GetComplianceByCluster()
 		allClustersOnDB := complianceClustersFromDB.GetAllClusters()
 
 		// handle compliant clusters of the policy
@@ -101,31 +103,23 @@ func handleCompliance(log logr.Logger, ctx context.Context, evt *cloudevents.Eve
 		batchLocalCompliances = append(batchLocalCompliances, pendingCompliances...)
 
 		// batch upsert
-		err = db.Clauses(clause.OnConflict{
-			UpdateAll: true,
-		}).CreateInBatches(batchLocalCompliances, 100).Error
-		if err != nil {
+		compliances := make([]models.StatusCompliance, 0, len(batchLocalCompliances))
+		for _, compliance := range batchLocalCompliances {
+			compliances = append(compliances, models.StatusCompliance(compliance))
+		}
+		if err = writer.Upsert(ctx, compliances); err != nil {
 			return err
 		}
+		notifyComplianceChanges(true, previousCompliances, "", compliances)
 
 		// delete
-		err = db.Transaction(func(tx *gorm.DB) error {
-			for _, name := range allClustersOnDB.ToSlice() {
-				clusterName, ok := name.(string)
-				if !ok {
-					continue
-				}
-				err := tx.Where(&models.LocalStatusCompliance{
-					LeafHubName: leafHub,
-					PolicyID:    policyID,
-					ClusterName: clusterName,
-				}).Delete(&models.LocalStatusCompliance{}).Error
-				if err != nil {
-					return err
-				}
+		clusterNames := make([]string, 0, allClustersOnDB.Cardinality())
+		for _, name := range allClustersOnDB.ToSlice() {
+			if clusterName, ok := name.(string); ok {
+				clusterNames = append(clusterNames, clusterName)
 			}
-			return nil
-		})
+		}
+		err = writer.DeleteClusters(ctx, leafHub, policyID, clusterNames)
 		if err != nil {
 			return fmt.Errorf("failed to handle clusters per policy bundle - %w", err)
 		}
@@ -57,8 +57,8 @@ func (h *localPolicyEventHandler) handleEvent(ctx context.Context, evt *cloudeve
 	}
 
 	batchLocalPolicyEvents := []models.LocalClusterPolicyEvent{}
-	for _, policyStatusEvent := range data {
-		batchLocalPolicyEvents = append(batchLocalPolicyEvents, models.LocalClusterPolicyEvent{
+	for i, policyStatusEvent := range data {
+		localPolicyEvent := models.LocalClusterPolicyEvent{
 			BaseLocalPolicyEvent: models.BaseLocalPolicyEvent{
 				EventName:   policyStatusEvent.EventName,
 				PolicyID:    policyStatusEvent.PolicyID,
@@ -71,7 +71,10 @@ func (h *localPolicyEventHandler) handleEvent(ctx context.Context, evt *cloudeve
 				CreatedAt:   policyStatusEvent.CreatedAt.Time,
 			},
 			ClusterID: policyStatusEvent.ClusterID,
-		})
+		}
+		normalizePolicyEvent(&data[i].BaseEvent, policyStatusEvent.Compliance,
+			&localPolicyEvent.BaseLocalPolicyEvent)
+		batchLocalPolicyEvents = append(batchLocalPolicyEvents, localPolicyEvent)
 	}
 
 	if len(batchLocalPolicyEvents) <= 0 {
@@ -0,0 +1,107 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	policiesv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type localPolicySpecDeltaHandler struct {
+	log            logr.Logger
+	eventType      string
+	dependencyType string
+	eventSyncMode  enum.EventSyncMode
+	eventPriority  conflator.ConflationPriority
+}
+
+// NewLocalPolicySpecDeltaHandler applies the changes of the local policies since the full bundle, the checkpoint.
+func NewLocalPolicySpecDeltaHandler() conflator.Handler {
+	eventType := string(enum.LocalPolicySpecDeltaType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &localPolicySpecDeltaHandler{
+		log:            ctrl.Log.WithName(logName),
+		eventType:      eventType,
+		dependencyType: string(enum.LocalPolicySpecType),
+		eventSyncMode:  enum.CompleteStateMode,
+		eventPriority:  conflator.LocalPolicySpecDeltaPriority,
+	}
+}
+
+func (h *localPolicySpecDeltaHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	registration := conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	)
+	registration.WithDependency(dependency.NewDependency(h.dependencyType, dependency.Checkpoint))
+	conflationManager.Register(registration)
+}
+
+func (h *localPolicySpecDeltaHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := generic.DeltaBundle[policiesv1.Policy]{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	batchLocalPolicySpec := []models.LocalSpecPolicy{}
+	for _, policy := range data.Updated {
+		payload, err := json.Marshal(policy)
+		if err != nil {
+			return err
+		}
+		batchLocalPolicySpec = append(batchLocalPolicySpec, models.LocalSpecPolicy{
+			PolicyID:    string(policy.GetUID()),
+			LeafHubName: leafHubName,
+			Payload:     payload,
+		})
+	}
+
+	deletedPolicyIds := make([]string, 0, len(data.Deleted))
+	for _, deleted := range data.Deleted {
+		deletedPolicyIds = append(deletedPolicyIds, string(deleted.UID))
+	}
+
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if len(batchLocalPolicySpec) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				UpdateAll: true,
+			}).CreateInBatches(batchLocalPolicySpec, 100).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(deletedPolicyIds) == 0 {
+			return nil
+		}
+		return tx.Where("leaf_hub_name = ? AND policy_id IN ?", leafHubName, deletedPolicyIds).
+			Delete(&models.LocalSpecPolicy{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed applying the delta of local_spec.policies - %w", err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
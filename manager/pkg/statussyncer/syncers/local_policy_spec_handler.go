@@ -138,6 +138,7 @@ func getPolicyIdToVersionMap(db *gorm.DB, leafHubName string) (map[string]string
 		Where(&models.LocalSpecPolicy{ // Find soft deleted records: db.Unscoped().Where(...).Find(...)
 			LeafHubName: leafHubName,
 		}).
+		Where(nonPolicyReportSpecCondition).
 		Find(&models.LocalSpecPolicy{}).Scan(&resourceVersions).Error
 	if err != nil {
 		return nil, err
@@ -0,0 +1,90 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type managedClusterAddonHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewManagedClusterAddonHandler stores the health of the addons on the managed clusters of the hub, so the addons
+// can be aggregated across all the managed hubs.
+func NewManagedClusterAddonHandler() conflator.Handler {
+	eventType := string(enum.ManagedClusterAddonType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &managedClusterAddonHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.ManagedClusterAddonsPriority,
+	}
+}
+
+func (h *managedClusterAddonHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *managedClusterAddonHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := cluster.ManagedClusterAddonBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	addons := make([]models.ManagedClusterAddon, 0, len(data))
+	for _, addon := range data {
+		addons = append(addons, models.ManagedClusterAddon{
+			LeafHubName:        leafHubName,
+			ClusterName:        addon.ClusterName,
+			AddonName:          addon.AddonName,
+			Status:             addon.Status,
+			Reason:             addon.Reason,
+			Message:            addon.Message,
+			LastTransitionTime: addon.LastTransitionTime,
+		})
+	}
+
+	// the bundle contains the addons of all the clusters of the hub, so the records of the hub are replaced by them
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.ManagedClusterAddon{}).Error; err != nil {
+			return err
+		}
+		if len(addons) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(addons, batchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the managed cluster addons of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
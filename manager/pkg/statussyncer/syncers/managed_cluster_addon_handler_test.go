@@ -0,0 +1,85 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "ManagedClusterAddonHandler"
+var _ = Describe("ManagedClusterAddonHandler", Ordered, func() {
+	leafHubName := "hub-addon"
+	version := eventversion.NewVersion()
+	transitionTime := time.Now().UTC().Truncate(time.Second)
+	observability := cluster.ManagedClusterAddon{
+		ClusterName:        "cluster1",
+		AddonName:          "observability-controller",
+		Status:             cluster.AddonStatusDegraded,
+		Reason:             "ManagedClusterAddOnLeaseUpdateStopped",
+		Message:            "observability-controller add-on is not available.",
+		LastTransitionTime: &transitionTime,
+	}
+	workManager := cluster.ManagedClusterAddon{
+		ClusterName: "cluster1",
+		AddonName:   "work-manager",
+		Status:      cluster.AddonStatusAvailable,
+	}
+
+	checkTable := func(expected map[string]string) {
+		Eventually(func() error {
+			addons := []models.ManagedClusterAddon{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&addons).Error
+			if err != nil {
+				return err
+			}
+			if len(addons) != len(expected) {
+				return fmt.Errorf("unexpected addons: %v", addons)
+			}
+			for _, addon := range addons {
+				if expected[addon.AddonName] != addon.Status {
+					return fmt.Errorf("unexpected addon: %v", addon)
+				}
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	}
+
+	It("should be able to sync the managed cluster addons", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterAddonType), version,
+			cluster.ManagedClusterAddonBundle{observability, workManager})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		checkTable(map[string]string{
+			observability.AddonName: cluster.AddonStatusDegraded,
+			workManager.AddonName:   cluster.AddonStatusAvailable,
+		})
+	})
+
+	It("should replace the addons which are changed on the hub", func() {
+		By("Create event")
+		version.Incr()
+		observability.Status = cluster.AddonStatusAvailable
+		observability.Reason, observability.Message = "", ""
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterAddonType), version,
+			cluster.ManagedClusterAddonBundle{observability})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		checkTable(map[string]string{observability.AddonName: cluster.AddonStatusAvailable})
+	})
+})
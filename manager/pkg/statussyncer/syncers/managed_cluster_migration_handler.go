@@ -0,0 +1,69 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type managedClusterMigrationHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+	producer      transport.Producer
+}
+
+// NewManagedClusterMigrationHandler moves the managed cluster migrations forward with the results of running their
+// phases on the managed hub, the next phase is requested by the producer.
+func NewManagedClusterMigrationHandler(producer transport.Producer) conflator.Handler {
+	eventType := string(enum.ManagedClusterMigrationType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &managedClusterMigrationHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.ManagedClusterMigrationPriority,
+		producer:      producer,
+	}
+}
+
+func (h *managedClusterMigrationHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *managedClusterMigrationHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	statuses := spec.ManagedClusterMigrationBundle{}
+	if err := evt.DataAs(&statuses); err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if err := migration.UpdateMigration(ctx, h.producer, leafHubName, status); err != nil {
+			return fmt.Errorf("failed to update the migration %d with the %s phase on the hub %s: %w",
+				status.MigrationID, status.Phase, leafHubName, err)
+		}
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
@@ -0,0 +1,29 @@
+package dbsyncer
+
+import (
+	"fmt"
+
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// NewManagedClusterSetHandler stores the global managed cluster sets from the managed hubs, the members of the sets
+// are aggregated by the status.managed_cluster_set_members view.
+func NewManagedClusterSetHandler() conflator.Handler {
+	return NewGenericHandler[*clusterv1beta2.ManagedClusterSet](
+		string(enum.ManagedClusterSetSpecType),
+		conflator.ManagedClusterSetPriority,
+		enum.CompleteStateMode,
+		fmt.Sprintf("%s.%s", database.StatusSchema, database.ManagedClusterSetsTableName))
+}
+
+func NewManagedClusterSetBindingHandler() conflator.Handler {
+	return NewGenericHandler[*clusterv1beta2.ManagedClusterSetBinding](
+		string(enum.ManagedClusterSetBindingSpecType),
+		conflator.ManagedClusterSetBindingPriority,
+		enum.CompleteStateMode,
+		fmt.Sprintf("%s.%s", database.StatusSchema, database.ManagedClusterSetBindingsTableName))
+}
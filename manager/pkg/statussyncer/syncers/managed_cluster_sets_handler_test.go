@@ -0,0 +1,109 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "ManagedClusterSetHandler"
+var _ = Describe("ManagedClusterSetHandler", Ordered, func() {
+	leafHubName := "hub1"
+	clusterSet := &clusterv1beta2.ManagedClusterSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testclusterset",
+			Annotations: map[string]string{
+				constants.OriginOwnerReferenceAnnotation: "b8b3e164-377e-4be1-a870-992265f31f7c",
+			},
+		},
+	}
+
+	It("should be able to sync managed cluster set event", func() {
+		By("Create event")
+		version := eventversion.NewVersion()
+		version.Incr()
+
+		data := generic.GenericObjectBundle{}
+		data = append(data, clusterSet)
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterSetSpecType), version, data)
+
+		By("Sync event with transport")
+		err := producer.SendEvent(ctx, *evt)
+		Expect(err).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			sql := fmt.Sprintf("SELECT leaf_hub_name,payload FROM %s.%s", database.StatusSchema,
+				database.ManagedClusterSetsTableName)
+
+			rows, err := database.GetGorm().Raw(sql).Rows()
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var hubName string
+				var payload []byte
+				set := &clusterv1beta2.ManagedClusterSet{}
+				if err := rows.Scan(&hubName, &payload); err != nil {
+					return err
+				}
+				if err := json.Unmarshal(payload, set); err != nil {
+					return err
+				}
+				if hubName == leafHubName && set.Name == clusterSet.Name {
+					return nil
+				}
+			}
+			return fmt.Errorf("not found expected resource on the table")
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should aggregate the members of the managed cluster set", func() {
+		By("Insert the managed clusters of the hub")
+		for i, setName := range []string{clusterSet.Name, "other"} {
+			cluster := &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("clusterset-member-%d", i),
+					Labels: map[string]string{
+						clusterv1beta2.ClusterSetLabel: setName,
+					},
+				},
+			}
+			payload, err := json.Marshal(cluster)
+			Expect(err).Should(Succeed())
+			err = database.GetGorm().Exec(fmt.Sprintf(
+				"INSERT INTO %s.%s (leaf_hub_name, cluster_id, payload, error) VALUES (?, gen_random_uuid(), ?, 'none')",
+				database.StatusSchema, database.ManagedClustersTableName), leafHubName, payload).Error
+			Expect(err).Should(Succeed())
+		}
+
+		By("Check the members view")
+		Eventually(func() error {
+			var members []string
+			err := database.GetGorm().Raw(fmt.Sprintf(
+				"SELECT cluster_name FROM %s.%s WHERE cluster_set_name = ? AND leaf_hub_name = ?",
+				database.StatusSchema, database.ManagedClusterSetMembersViewName),
+				clusterSet.Name, leafHubName).Scan(&members).Error
+			if err != nil {
+				return err
+			}
+			if len(members) != 1 || members[0] != "clusterset-member-0" {
+				return fmt.Errorf("unexpected members of the cluster set: %v", members)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,181 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/enrichment"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/eventexporter"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator/dependency"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type managedClusterDeltaHandler struct {
+	log            logr.Logger
+	eventType      string
+	dependencyType string
+	eventSyncMode  enum.EventSyncMode
+	eventPriority  conflator.ConflationPriority
+}
+
+// NewManagedClusterDeltaHandler applies the changes of the managed clusters since the full bundle, the checkpoint.
+func NewManagedClusterDeltaHandler() conflator.Handler {
+	eventType := string(enum.ManagedClusterDeltaType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &managedClusterDeltaHandler{
+		log:            ctrl.Log.WithName(logName),
+		eventType:      eventType,
+		dependencyType: string(enum.ManagedClusterType),
+		eventSyncMode:  enum.CompleteStateMode,
+		eventPriority:  conflator.ManagedClustersDeltaPriority,
+	}
+}
+
+func (h *managedClusterDeltaHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	registration := conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	)
+	registration.WithDependency(dependency.NewDependency(h.dependencyType, dependency.Checkpoint))
+	conflationManager.Register(registration)
+}
+
+func (h *managedClusterDeltaHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := generic.DeltaBundle[clusterv1.ManagedCluster]{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	batchManagedClusters := []models.ManagedCluster{}
+	updatedClusters := []eventexporter.ClusterEvent{}
+	enrichments := []models.ManagedClusterEnrichment{}
+	for _, cluster := range data.Updated {
+		// skip the cluster until the clusterID is reported by the ClusterClaim
+		clusterId := ""
+		for _, claim := range cluster.Status.ClusterClaims {
+			if claim.Name == "id.k8s.io" {
+				clusterId = claim.Value
+				break
+			}
+		}
+		if clusterId == "" {
+			continue
+		}
+
+		payload, err := json.Marshal(cluster)
+		if err != nil {
+			return err
+		}
+		clusterEnrichment, err := enrichment.ClusterEnrichment(leafHubName, clusterId, &cluster)
+		if err != nil {
+			return err
+		}
+		if clusterEnrichment != nil {
+			enrichments = append(enrichments, *clusterEnrichment)
+		}
+		batchManagedClusters = append(batchManagedClusters, models.ManagedCluster{
+			ClusterID:   clusterId,
+			LeafHubName: leafHubName,
+			Payload:     payload,
+			Error:       database.ErrorNone,
+		})
+		updatedClusters = append(updatedClusters, eventexporter.ClusterEvent{
+			LeafHubName: leafHubName,
+			ClusterName: cluster.GetName(),
+			ClusterID:   clusterId,
+		})
+	}
+
+	deletedClusterNames := make([]string, 0, len(data.Deleted))
+	for _, deleted := range data.Deleted {
+		deletedClusterNames = append(deletedClusterNames, deleted.Name)
+	}
+
+	db := database.GetGorm()
+	createdClusters, err := filterCreatedClusters(db, leafHubName, updatedClusters)
+	if err != nil {
+		return err
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if len(batchManagedClusters) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				UpdateAll: true,
+			}).CreateInBatches(batchManagedClusters, batchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := enrichment.Save(tx, enrichments, batchSize); err != nil {
+			return err
+		}
+		if len(deletedClusterNames) == 0 {
+			return nil
+		}
+		err := tx.Where("leaf_hub_name = ? AND payload->'metadata'->>'name' IN ?", leafHubName,
+			deletedClusterNames).Delete(&models.ManagedCluster{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("leaf_hub_name = ? AND cluster_name IN ?", leafHubName, deletedClusterNames).
+			Delete(&models.ManagedClusterEnrichment{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed applying the delta of managed clusters - %w", err)
+	}
+	eventexporter.ExportClustersCreated(createdClusters...)
+	for _, name := range deletedClusterNames {
+		eventexporter.ExportClustersDeleted(eventexporter.ClusterEvent{LeafHubName: leafHubName, ClusterName: name})
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+// filterCreatedClusters returns the updated clusters which aren't in the database yet, it's only queried if the
+// events are exported
+func filterCreatedClusters(db *gorm.DB, leafHubName string, updated []eventexporter.ClusterEvent,
+) ([]eventexporter.ClusterEvent, error) {
+	if !eventexporter.Enabled() || len(updated) == 0 {
+		return nil, nil
+	}
+	clusterIds := make([]string, 0, len(updated))
+	for _, cluster := range updated {
+		clusterIds = append(clusterIds, cluster.ClusterID)
+	}
+	var existingIds []string
+	err := db.Model(&models.ManagedCluster{}).Where("leaf_hub_name = ? AND cluster_id IN ?", leafHubName,
+		clusterIds).Pluck("cluster_id", &existingIds).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching the existing managed clusters - %w", err)
+	}
+	existing := sets.NewString(existingIds...)
+
+	created := []eventexporter.ClusterEvent{}
+	for _, cluster := range updated {
+		if !existing.Has(cluster.ClusterID) {
+			created = append(created, cluster)
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,79 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "ManagedClusterDeltaHandler"
+var _ = Describe("ManagedClusterDeltaHandler", Ordered, func() {
+	leafHubName := "delta-hub"
+	version := eventversion.NewVersion()
+
+	newCluster := func(name, clusterID, env string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"env": env},
+			},
+			Status: clusterv1.ManagedClusterStatus{
+				ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: "id.k8s.io", Value: clusterID}},
+			},
+		}
+	}
+	cluster1 := newCluster("delta-cluster1", "9c7b2f4e-5b1a-4a8e-9d3e-2c1f0e6a7b01", "dev")
+	cluster2 := newCluster("delta-cluster2", "9c7b2f4e-5b1a-4a8e-9d3e-2c1f0e6a7b02", "dev")
+
+	It("should apply the delta on top of the full bundle", func() {
+		By("Sync the full bundle as the checkpoint")
+		version.Incr()
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterType), version,
+			generic.GenericObjectBundle{cluster1, cluster2})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+		checkpoint := *version
+		version.Next()
+
+		By("Sync the delta bundle since the checkpoint")
+		version.Incr()
+		delta := generic.GenericObjectDeltaBundle{
+			Updated: []client.Object{newCluster("delta-cluster2", "9c7b2f4e-5b1a-4a8e-9d3e-2c1f0e6a7b02", "prod")},
+			Deleted: []generic.ObjectIdentity{{Name: cluster1.Name}},
+		}
+		evt = ToCloudEvent(leafHubName, string(enum.ManagedClusterDeltaType), version, delta)
+		evt.SetExtension(eventversion.ExtDependencyVersion, checkpoint.String())
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the managed clusters table")
+		Eventually(func() error {
+			items := []models.ManagedCluster{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&items).Error; err != nil {
+				return err
+			}
+			if len(items) != 1 || items[0].ClusterID != "9c7b2f4e-5b1a-4a8e-9d3e-2c1f0e6a7b02" {
+				return fmt.Errorf("unexpected managed clusters: %v", items)
+			}
+			cluster := clusterv1.ManagedCluster{}
+			if err := json.Unmarshal(items[0].Payload, &cluster); err != nil {
+				return err
+			}
+			if cluster.Labels["env"] != "prod" {
+				return fmt.Errorf("the delta isn't applied to the cluster: %v", cluster.Labels)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -13,6 +13,8 @@ import (
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/enrichment"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/eventexporter"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -65,6 +67,9 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 
 	// batch update/insert managed clusters
 	batchManagedClusters := []models.ManagedCluster{}
+	createdClusters := []eventexporter.ClusterEvent{}
+	// the unchanged clusters are enriched as well, so the new fields of the enrichers are applied to them
+	enrichments := []models.ManagedClusterEnrichment{}
 	for _, object := range data {
 		cluster := object
 
@@ -85,6 +90,14 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 			return err
 		}
 
+		clusterEnrichment, err := enrichment.ClusterEnrichment(leafHubName, clusterId, &cluster)
+		if err != nil {
+			return err
+		}
+		if clusterEnrichment != nil {
+			enrichments = append(enrichments, *clusterEnrichment)
+		}
+
 		clusterVersionFromDB, exist := clusterIdToVersionMapFromDB[clusterId]
 		if !exist {
 			batchManagedClusters = append(batchManagedClusters, models.ManagedCluster{
@@ -93,6 +106,11 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 				Payload:     payload,
 				Error:       database.ErrorNone,
 			})
+			createdClusters = append(createdClusters, eventexporter.ClusterEvent{
+				LeafHubName: leafHubName,
+				ClusterName: cluster.GetName(),
+				ClusterID:   clusterId,
+			})
 			continue
 		}
 
@@ -112,19 +130,37 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 	}
 	err = db.Clauses(clause.OnConflict{
 		UpdateAll: true,
-	}).CreateInBatches(batchManagedClusters, 100).Error
+	}).CreateInBatches(batchManagedClusters, batchSize).Error
 	if err != nil {
 		return err
 	}
+	if err := enrichment.Save(db, enrichments, batchSize); err != nil {
+		return fmt.Errorf("failed saving the enrichments of managed clusters - %w", err)
+	}
 
 	// delete objects that in the db but were not sent in the bundle (leaf hub sends only living resources).
 	// https://gorm.io/docs/delete.html#Soft-Delete
+	deletedClusterIds := make([]string, 0, len(clusterIdToVersionMapFromDB))
+	for clusterId := range clusterIdToVersionMapFromDB {
+		deletedClusterIds = append(deletedClusterIds, clusterId)
+	}
+	deletedClusters, err := getDeletedClusterEvents(db, leafHubName, deletedClusterIds)
+	if err != nil {
+		return err
+	}
 	err = db.Transaction(func(tx *gorm.DB) error {
-		for clusterId := range clusterIdToVersionMapFromDB {
-			e := tx.Where(&models.ManagedCluster{
-				LeafHubName: leafHubName,
-				ClusterID:   clusterId,
-			}).Delete(&models.ManagedCluster{}).Error
+		for start := 0; start < len(deletedClusterIds); start += batchSize {
+			end := start + batchSize
+			if end > len(deletedClusterIds) {
+				end = len(deletedClusterIds)
+			}
+			e := tx.Where("leaf_hub_name = ? AND cluster_id IN ?", leafHubName, deletedClusterIds[start:end]).
+				Delete(&models.ManagedCluster{}).Error
+			if e != nil {
+				return e
+			}
+			e = tx.Where("leaf_hub_name = ? AND cluster_id IN ?", leafHubName, deletedClusterIds[start:end]).
+				Delete(&models.ManagedClusterEnrichment{}).Error
 			if e != nil {
 				return e
 			}
@@ -134,6 +170,8 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 	if err != nil {
 		return fmt.Errorf("failed deleting managed clusters - %w", err)
 	}
+	eventexporter.ExportClustersCreated(createdClusters...)
+	eventexporter.ExportClustersDeleted(deletedClusters...)
 
 	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
 	return nil
@@ -155,3 +193,32 @@ func getClusterIdToVersionMap(db *gorm.DB, leafHubName string) (map[string]strin
 	}
 	return nameToVersionMap, nil
 }
+
+// getDeletedClusterEvents returns the names of the clusters which are going to be deleted, it's only queried if the
+// events are exported
+func getDeletedClusterEvents(db *gorm.DB, leafHubName string, clusterIds []string) ([]eventexporter.ClusterEvent,
+	error,
+) {
+	if !eventexporter.Enabled() || len(clusterIds) == 0 {
+		return nil, nil
+	}
+	var clusters []struct {
+		ClusterID   string
+		ClusterName string
+	}
+	err := db.Model(&models.ManagedCluster{}).
+		Select("cluster_id, payload->'metadata'->>'name' AS cluster_name").
+		Where("leaf_hub_name = ? AND cluster_id IN ?", leafHubName, clusterIds).Scan(&clusters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching the deleted managed clusters - %w", err)
+	}
+	events := make([]eventexporter.ClusterEvent, 0, len(clusters))
+	for _, cluster := range clusters {
+		events = append(events, eventexporter.ClusterEvent{
+			LeafHubName: leafHubName,
+			ClusterName: cluster.ClusterName,
+			ClusterID:   cluster.ClusterID,
+		})
+	}
+	return events, nil
+}
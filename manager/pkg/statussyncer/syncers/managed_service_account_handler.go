@@ -0,0 +1,92 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type managedServiceAccountHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewManagedServiceAccountHandler stores the credentials of the managed service accounts on the managed clusters of the
+// hub, so the automation can find the hub which brokers the access to each cluster and when the token expires.
+func NewManagedServiceAccountHandler() conflator.Handler {
+	eventType := string(enum.ManagedServiceAccountType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &managedServiceAccountHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.ManagedServiceAccountsPriority,
+	}
+}
+
+func (h *managedServiceAccountHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *managedServiceAccountHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := cluster.ManagedServiceAccountBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	accounts := make([]models.ManagedServiceAccount, 0, len(data))
+	for _, account := range data {
+		accounts = append(accounts, models.ManagedServiceAccount{
+			LeafHubName:     leafHubName,
+			ClusterName:     account.ClusterName,
+			Name:            account.Name,
+			Status:          account.Status,
+			Reason:          account.Reason,
+			Message:         account.Message,
+			TokenSecretName: account.TokenSecretName,
+			LastRefreshTime: account.LastRefreshTime,
+			ExpirationTime:  account.ExpirationTime,
+		})
+	}
+
+	// the bundle contains the accounts of all the clusters of the hub, so the records of the hub are replaced by them
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.ManagedServiceAccount{}).Error; err != nil {
+			return err
+		}
+		if len(accounts) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(accounts, batchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the managed service accounts of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
@@ -0,0 +1,89 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "ManagedServiceAccountHandler"
+var _ = Describe("ManagedServiceAccountHandler", Ordered, func() {
+	leafHubName := "hub-msa"
+	version := eventversion.NewVersion()
+	refreshTime := time.Now().UTC().Truncate(time.Second)
+	expirationTime := refreshTime.Add(24 * time.Hour)
+	automation := cluster.ManagedServiceAccount{
+		ClusterName:     "cluster1",
+		Name:            "automation",
+		Status:          cluster.ServiceAccountStatusReady,
+		Reason:          "SecretCreated",
+		TokenSecretName: "automation",
+		LastRefreshTime: &refreshTime,
+		ExpirationTime:  &expirationTime,
+	}
+	backup := cluster.ManagedServiceAccount{
+		ClusterName: "cluster2",
+		Name:        "backup",
+		Status:      cluster.ServiceAccountStatusPending,
+	}
+
+	checkTable := func(expected map[string]string) {
+		Eventually(func() error {
+			accounts := []models.ManagedServiceAccount{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&accounts).Error
+			if err != nil {
+				return err
+			}
+			if len(accounts) != len(expected) {
+				return fmt.Errorf("unexpected accounts: %v", accounts)
+			}
+			for _, account := range accounts {
+				if expected[account.Name] != account.Status {
+					return fmt.Errorf("unexpected account: %v", account)
+				}
+				if account.Name == automation.Name && (account.TokenSecretName != automation.TokenSecretName ||
+					account.ExpirationTime == nil || !account.ExpirationTime.Equal(expirationTime)) {
+					return fmt.Errorf("unexpected credential of the account: %v", account)
+				}
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	}
+
+	It("should be able to sync the managed service accounts", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedServiceAccountType), version,
+			cluster.ManagedServiceAccountBundle{automation, backup})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		checkTable(map[string]string{
+			automation.Name: cluster.ServiceAccountStatusReady,
+			backup.Name:     cluster.ServiceAccountStatusPending,
+		})
+	})
+
+	It("should replace the accounts which are changed on the hub", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedServiceAccountType), version,
+			cluster.ManagedServiceAccountBundle{automation})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		checkTable(map[string]string{automation.Name: cluster.ServiceAccountStatusReady})
+	})
+})
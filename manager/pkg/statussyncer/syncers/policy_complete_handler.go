@@ -138,18 +138,11 @@ func (h *policyCompleteHandler) handleEvent(ctx context.Context, evt *cloudevent
 			})
 		}
 
-		err = db.Transaction(func(tx *gorm.DB) error {
-			for _, compliance := range batchCompliance {
-				e := tx.Updates(compliance).Error
-				if e != nil {
-					return e
-				}
-			}
-			return nil
-		})
-		if err != nil {
+		if err = newComplianceWriter(false).Update(ctx, batchCompliance); err != nil {
 			return fmt.Errorf("failed to update compliances by complete event - %w", err)
 		}
+		notifyComplianceChanges(false, nonComplianceClusterSetsFromDB.GetComplianceByCluster(), database.Compliant,
+			batchCompliance)
 
 		// for policies that are found in the db but not in the bundle - all clusters are Compliant (implicitly)
 		delete(allCompleteRowsFromDB, policyID)
@@ -9,7 +9,6 @@ import (
 	set "github.com/deckarep/golang-set"
 	"github.com/go-logr/logr"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
@@ -76,6 +75,7 @@ func (h *policyComplianceHandler) handleEvent(ctx context.Context, evt *cloudeve
 		return err
 	}
 
+	writer := newComplianceWriter(false)
 	for _, eventCompliance := range data { // every object is clusters list per policy with full state
 
 		policyID := eventCompliance.PolicyID
@@ -84,6 +84,7 @@ func (h *policyComplianceHandler) handleEvent(ctx context.Context, evt *cloudeve
 			complianceClustersFromDB = NewPolicyClusterSets()
 		}
 
+		previousCompliances := complianceClustersFromDB.GetComplianceByCluster()
 		allClustersOnDB := complianceClustersFromDB.GetAllClusters()
 		// handle compliant clusters of the policy
 		compliantCompliances := newCompliances(leafHubName, policyID, database.Compliant,
@@ -108,31 +109,19 @@ func (h *policyComplianceHandler) handleEvent(ctx context.Context, evt *cloudeve
 		batchCompliances = append(batchCompliances, pendingCompliances...)
 
 		// batch upsert
-		err = db.Clauses(clause.OnConflict{
-			UpdateAll: true,
-		}).CreateInBatches(batchCompliances, 100).Error
-		if err != nil {
+		if err = writer.Upsert(ctx, batchCompliances); err != nil {
 			return err
 		}
+		notifyComplianceChanges(false, previousCompliances, "", batchCompliances)
 
 		// delete
-		err = db.Transaction(func(tx *gorm.DB) error {
-			for _, name := range allClustersOnDB.ToSlice() {
-				clusterName, ok := name.(string)
-				if !ok {
-					continue
-				}
-				err := tx.Where(&models.StatusCompliance{
-					LeafHubName: leafHubName,
-					PolicyID:    policyID,
-					ClusterName: clusterName,
-				}).Delete(&models.StatusCompliance{}).Error
-				if err != nil {
-					return err
-				}
+		clusterNames := make([]string, 0, allClustersOnDB.Cardinality())
+		for _, name := range allClustersOnDB.ToSlice() {
+			if clusterName, ok := name.(string); ok {
+				clusterNames = append(clusterNames, clusterName)
 			}
-			return nil
-		})
+		}
+		err = writer.DeleteClusters(ctx, leafHubName, policyID, clusterNames)
 		if err != nil {
 			return fmt.Errorf("failed to handle clusters per policy bundle - %w", err)
 		}
@@ -231,3 +220,16 @@ func (sets *PolicyClustersSets) GetAllClusters() set.Set {
 func (sets *PolicyClustersSets) GetClusters(complianceStatus database.ComplianceStatus) set.Set {
 	return sets.complianceToSetMap[complianceStatus]
 }
+
+// GetComplianceByCluster returns the compliance status of each cluster in the sets.
+func (sets *PolicyClustersSets) GetComplianceByCluster() map[string]database.ComplianceStatus {
+	complianceByCluster := map[string]database.ComplianceStatus{}
+	for compliance, clusters := range sets.complianceToSetMap {
+		for _, cluster := range clusters.ToSlice() {
+			if clusterName, ok := cluster.(string); ok {
+				complianceByCluster[clusterName] = compliance
+			}
+		}
+	}
+	return complianceByCluster
+}
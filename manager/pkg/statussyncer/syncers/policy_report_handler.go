@@ -0,0 +1,174 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// the kyverno policies are stored in the local policies by the policy report handler, so the handlers of the local
+// policy bundles only touch the policies of the managed hub with the conditions
+var (
+	nonPolicyReportSpecCondition = fmt.Sprintf("payload->>'apiVersion' IS DISTINCT FROM '%s'",
+		grc.PolicyReportAPIVersion)
+	nonPolicyReportComplianceCondition = fmt.Sprintf("policy_id NOT IN (SELECT policy_id FROM local_spec.policies "+
+		"WHERE payload->>'apiVersion' = '%s')", grc.PolicyReportAPIVersion)
+)
+
+type policyReportHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewPolicyReportHandler stores the kyverno policies collected from the policy reports as the local policies, and
+// their compliance as the local compliance, so they're reported along with the policies of the managed hubs.
+func NewPolicyReportHandler() conflator.Handler {
+	eventType := string(enum.PolicyReportType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &policyReportHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.PolicyReportPriority,
+	}
+}
+
+func (h *policyReportHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *policyReportHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := grc.PolicyReportBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	db := database.GetGorm()
+	var policyIDsFromDB []string
+	err := db.Model(&models.LocalSpecPolicy{}).
+		Where("leaf_hub_name = ? AND payload->>'apiVersion' = ?", leafHubName, grc.PolicyReportAPIVersion).
+		Pluck("policy_id", &policyIDsFromDB).Error
+	if err != nil {
+		return err
+	}
+	deletedPolicyIDs := map[string]bool{}
+	for _, policyID := range policyIDsFromDB {
+		deletedPolicyIDs[policyID] = true
+	}
+
+	specs := make([]models.LocalSpecPolicy, 0, len(data))
+	compliances := make([]models.LocalStatusCompliance, 0, len(data))
+	for _, compliance := range data {
+		payload, err := json.Marshal(policyReportPayload(compliance).Object)
+		if err != nil {
+			return err
+		}
+		specs = append(specs, models.LocalSpecPolicy{
+			PolicyID:    compliance.PolicyID,
+			LeafHubName: leafHubName,
+			Payload:     payload,
+		})
+		compliances = append(compliances, models.LocalStatusCompliance{
+			PolicyID:    compliance.PolicyID,
+			ClusterName: compliance.ClusterName,
+			LeafHubName: leafHubName,
+			Error:       database.ErrorNone,
+			Compliance:  database.ComplianceStatus(compliance.Compliance),
+		})
+		delete(deletedPolicyIDs, compliance.PolicyID)
+	}
+
+	// the bundle contains all the kyverno policies of the hub, the policies not in it are deleted
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if len(specs) > 0 {
+			err := tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(specs, batchSize).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(compliances, batchSize).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(deletedPolicyIDs) == 0 {
+			return nil
+		}
+		policyIDs := make([]string, 0, len(deletedPolicyIDs))
+		for policyID := range deletedPolicyIDs {
+			policyIDs = append(policyIDs, policyID)
+		}
+		if err := tx.Where("policy_id IN ?", policyIDs).Delete(&models.LocalStatusCompliance{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("policy_id IN ?", policyIDs).Delete(&models.LocalSpecPolicy{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the policy reports of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+// policyReportPayload builds the kyverno policy stored in the local policies, the category is also set to the
+// annotation of the policy framework, so it's shown with the categories of the other policies
+func policyReportPayload(compliance grc.PolicyReportCompliance) *unstructured.Unstructured {
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	policy.SetAPIVersion(grc.PolicyReportAPIVersion)
+	policy.SetKind("ClusterPolicy")
+	if compliance.Namespace != "" {
+		policy.SetKind("Policy")
+		policy.SetNamespace(compliance.Namespace)
+	}
+	policy.SetName(compliance.Name)
+	policy.SetUID(types.UID(compliance.PolicyID))
+
+	annotations := map[string]string{}
+	if compliance.Category != "" {
+		annotations["policies.kyverno.io/category"] = compliance.Category
+		annotations["policy.open-cluster-management.io/categories"] = compliance.Category
+	}
+	if compliance.Severity != "" {
+		annotations["policies.kyverno.io/severity"] = compliance.Severity
+	}
+	if len(annotations) > 0 {
+		policy.SetAnnotations(annotations)
+	}
+
+	results := map[string]interface{}{}
+	for result, count := range compliance.Results {
+		results[result] = int64(count)
+	}
+	policy.Object["status"] = map[string]interface{}{
+		"compliant": compliance.Compliance,
+		"results":   results,
+	}
+	return policy
+}
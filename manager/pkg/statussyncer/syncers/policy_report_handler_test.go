@@ -0,0 +1,134 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "PolicyReportHandler"
+var _ = Describe("PolicyReportHandler", Ordered, func() {
+	leafHubName := "hub-policyreport"
+	version := eventversion.NewVersion()
+	requireLabels := grc.PolicyReportCompliance{
+		PolicyID:    "8c6d8e2a-1f0a-5b3e-9a7d-3a1b2c3d4e5f",
+		Name:        "require-labels",
+		Category:    "Best Practices",
+		Severity:    "medium",
+		ClusterName: leafHubName,
+		Compliance:  grc.PolicyReportNonCompliant,
+		Results:     map[string]int{"pass": 2, "fail": 1},
+	}
+	disallowLatestTag := grc.PolicyReportCompliance{
+		PolicyID:    "0b7e3c1d-2e4f-5a6b-8c9d-0e1f2a3b4c5d",
+		Namespace:   "default",
+		Name:        "disallow-latest-tag",
+		ClusterName: leafHubName,
+		Compliance:  grc.PolicyReportCompliant,
+		Results:     map[string]int{"pass": 1},
+	}
+
+	It("should be able to sync the kyverno policies as the local policies", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.PolicyReportType), version,
+			grc.PolicyReportBundle{requireLabels, disallowLatestTag})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the tables")
+		Eventually(func() error {
+			policies := []models.LocalSpecPolicy{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&policies).Error; err != nil {
+				return err
+			}
+			if len(policies) != 2 {
+				return fmt.Errorf("unexpected local policies: %v", policies)
+			}
+			for _, policy := range policies {
+				if policy.PolicyID == requireLabels.PolicyID &&
+					(policy.PolicyName != requireLabels.Name || policy.PolicyCategory != requireLabels.Category) {
+					return fmt.Errorf("unexpected kyverno policy: %s", string(policy.Payload))
+				}
+			}
+
+			compliances := []models.LocalStatusCompliance{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&compliances).Error
+			if err != nil {
+				return err
+			}
+			if len(compliances) != 2 {
+				return fmt.Errorf("unexpected local compliances: %v", compliances)
+			}
+			for _, compliance := range compliances {
+				if compliance.PolicyID == requireLabels.PolicyID && compliance.Compliance != database.NonCompliant {
+					return fmt.Errorf("unexpected compliance: %v", compliance)
+				}
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should keep the kyverno policies when the local policies of the hub are synced", func() {
+		By("Create event")
+		localVersion := eventversion.NewVersion()
+		localVersion.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.LocalComplianceType), localVersion, grc.ComplianceBundle{})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the kyverno compliances are kept")
+		Consistently(func() error {
+			compliances := []models.LocalStatusCompliance{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&compliances).Error
+			if err != nil {
+				return err
+			}
+			if len(compliances) != 2 {
+				return fmt.Errorf("unexpected local compliances: %v", compliances)
+			}
+			return nil
+		}, 3*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should delete the kyverno policy which isn't reported", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.PolicyReportType), version,
+			grc.PolicyReportBundle{requireLabels})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the tables")
+		Eventually(func() error {
+			policies := []models.LocalSpecPolicy{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&policies).Error; err != nil {
+				return err
+			}
+			if len(policies) != 1 || policies[0].PolicyID != requireLabels.PolicyID {
+				return fmt.Errorf("unexpected local policies: %v", policies)
+			}
+
+			compliances := []models.LocalStatusCompliance{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&compliances).Error
+			if err != nil {
+				return err
+			}
+			if len(compliances) != 1 || compliances[0].PolicyID != requireLabels.PolicyID {
+				return fmt.Errorf("unexpected local compliances: %v", compliances)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,110 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type policyTemplateDetailHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewPolicyTemplateDetailHandler stores the latest results of the policy templates reported by the agent, with the
+// messages and the related objects, into the policy template details table.
+func NewPolicyTemplateDetailHandler() conflator.Handler {
+	eventType := string(enum.PolicyTemplateDetailType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &policyTemplateDetailHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.PolicyTemplateDetailPriority,
+	}
+}
+
+func (h *policyTemplateDetailHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *policyTemplateDetailHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := grc.PolicyTemplateDetailBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	details := make([]models.PolicyTemplateDetail, 0, len(data))
+	for _, detail := range data {
+		// the size is bounded by the agent, it's enforced again in case the agent is outdated
+		message := detail.Message
+		if len(message) > grc.MaxTemplateMessageLength {
+			message = strings.ToValidUTF8(message[:grc.MaxTemplateMessageLength], "")
+		}
+		relatedObjects := detail.RelatedObjects
+		if len(relatedObjects) > grc.MaxTemplateRelatedObjects {
+			relatedObjects = relatedObjects[:grc.MaxTemplateRelatedObjects]
+		}
+		payload, err := json.Marshal(relatedObjects)
+		if err != nil {
+			return err
+		}
+		details = append(details, models.PolicyTemplateDetail{
+			LeafHubName:         leafHubName,
+			PolicyID:            detail.PolicyID,
+			ClusterName:         detail.ClusterName,
+			TemplateKind:        detail.TemplateKind,
+			TemplateName:        detail.TemplateName,
+			PolicyNamespace:     detail.PolicyNamespace,
+			PolicyName:          detail.PolicyName,
+			ClusterID:           detail.ClusterID,
+			Compliance:          detail.Compliance,
+			Message:             message,
+			TotalRelatedObjects: detail.TotalRelatedObjects,
+			RelatedObjects:      payload,
+			LastTimestamp:       detail.LastTimestamp,
+		})
+	}
+
+	// the bundle contains all the templates of the hub, so the records of the hub are replaced by them
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.PolicyTemplateDetail{}).Error; err != nil {
+			return err
+		}
+		if len(details) > 0 {
+			return tx.CreateInBatches(details, batchSize).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the policy template details of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
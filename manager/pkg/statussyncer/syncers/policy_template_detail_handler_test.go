@@ -0,0 +1,113 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "PolicyTemplateDetailHandler"
+var _ = Describe("PolicyTemplateDetailHandler", Ordered, func() {
+	leafHubName := "hub-template-detail"
+	version := eventversion.NewVersion()
+	lastTimestamp := time.Now().UTC().Truncate(time.Second)
+	details := grc.PolicyTemplateDetailBundle{
+		{
+			PolicyID:        "b8b3e164-377e-4be1-a870-992265f31f7c",
+			PolicyNamespace: "default",
+			PolicyName:      "policy-namespace",
+			ClusterName:     "cluster1",
+			TemplateKind:    "ConfigurationPolicy",
+			TemplateName:    "namespace-template",
+			Compliance:      "NonCompliant",
+			Message:         "NonCompliant; violation - namespaces [test] not found",
+			LastTimestamp:   &lastTimestamp,
+			RelatedObjects: []grc.TemplateRelatedObject{
+				{
+					APIVersion: "v1", Kind: "Namespace", Name: "test", Compliance: "NonCompliant",
+					Reason: "Resource not found but should exist",
+				},
+			},
+			TotalRelatedObjects: 1,
+		},
+		{
+			PolicyID:        "b8b3e164-377e-4be1-a870-992265f31f7c",
+			PolicyNamespace: "default",
+			PolicyName:      "policy-namespace",
+			ClusterName:     "cluster1",
+			TemplateKind:    "CertificatePolicy",
+			TemplateName:    "certificate-template",
+			Compliance:      "Compliant",
+			Message:         "Compliant; notification - no non-compliant certificates",
+			LastTimestamp:   &lastTimestamp,
+		},
+	}
+
+	listDetails := func() ([]models.PolicyTemplateDetail, error) {
+		rows := []models.PolicyTemplateDetail{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("template_name").Find(&rows).Error
+		return rows, err
+	}
+
+	It("should be able to sync the policy template details", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.PolicyTemplateDetailType), version, details)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			rows, err := listDetails()
+			if err != nil {
+				return err
+			}
+			if len(rows) != 2 {
+				return fmt.Errorf("unexpected policy template details: %v", rows)
+			}
+			if rows[1].TemplateName != "namespace-template" || rows[1].Compliance != "NonCompliant" ||
+				rows[1].TotalRelatedObjects != 1 {
+				return fmt.Errorf("unexpected policy template detail: %v", rows[1])
+			}
+			relatedObjects := []grc.TemplateRelatedObject{}
+			if err := json.Unmarshal(rows[1].RelatedObjects, &relatedObjects); err != nil {
+				return err
+			}
+			if len(relatedObjects) != 1 || relatedObjects[0].Name != "test" {
+				return fmt.Errorf("unexpected related objects: %v", relatedObjects)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should replace the details once the template is removed", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.PolicyTemplateDetailType), version, details[1:])
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			rows, err := listDetails()
+			if err != nil {
+				return err
+			}
+			if len(rows) != 1 || rows[0].TemplateName != "certificate-template" {
+				return fmt.Errorf("unexpected policy template details: %v", rows)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,103 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type regionalHubSummaryHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewRegionalHubSummaryHandler stores the summaries of the managed hubs forwarded by the regional global hubs, which
+// are received by the upstream global hub in the hierarchical topology.
+func NewRegionalHubSummaryHandler() conflator.Handler {
+	eventType := string(enum.RegionalHubSummaryType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &regionalHubSummaryHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.RegionalHubSummaryPriority,
+	}
+}
+
+func (h *regionalHubSummaryHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *regionalHubSummaryHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	regionalHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	summary := cluster.RegionalHubSummary{}
+	if err := evt.DataAs(&summary); err != nil {
+		return err
+	}
+
+	rows := make([]models.RegionalHubSummary, 0, len(summary.ManagedHubs))
+	hubNames := make([]string, 0, len(summary.ManagedHubs))
+	for _, hub := range summary.ManagedHubs {
+		compliance, err := json.Marshal(hub.Compliance)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, models.RegionalHubSummary{
+			RegionalHubName: regionalHubName,
+			LeafHubName:     hub.Name,
+			Status:          hub.Status,
+			LastHeartbeat:   hub.LastHeartbeat,
+			ManagedClusters: hub.ManagedClusters,
+			Policies:        hub.Policies,
+			Compliance:      compliance,
+			ReportedAt:      summary.ReportedAt,
+		})
+		hubNames = append(hubNames, hub.Name)
+	}
+
+	// the summary contains all the managed hubs of the regional global hub, the hubs not in it are removed
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		deletion := tx.Where("regional_hub_name = ?", regionalHubName)
+		if len(hubNames) > 0 {
+			deletion = deletion.Where("leaf_hub_name NOT IN ?", hubNames)
+		}
+		if err := deletion.Delete(&models.RegionalHubSummary{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows, batchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the summary of the regional hub %s: %w", regionalHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
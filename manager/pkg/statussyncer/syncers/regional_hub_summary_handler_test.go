@@ -0,0 +1,114 @@
+package dbsyncer_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/upstream"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "RegionalHubSummaryHandler"
+var _ = Describe("RegionalHubSummaryHandler", Ordered, func() {
+	regionalHubName := "regional-hub1"
+	version := eventversion.NewVersion()
+
+	It("should summarize the managed hubs of the regional global hub", func() {
+		db := database.GetGorm()
+		Expect(db.Create(&models.LeafHubHeartbeat{
+			Name: "hub-summary", Status: "active", LastUpdateAt: time.Now(),
+		}).Error).Should(Succeed())
+		Expect(db.Create(&models.LocalStatusCompliance{
+			PolicyID:    "1b2a1d3c-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+			ClusterName: "cluster1",
+			LeafHubName: "hub-summary",
+			Error:       database.ErrorNone,
+			Compliance:  database.NonCompliant,
+		}).Error).Should(Succeed())
+
+		summary, err := upstream.Summarize(db)
+		Expect(err).ShouldNot(HaveOccurred())
+		found := false
+		for _, hub := range summary.ManagedHubs {
+			if hub.Name == "hub-summary" {
+				found = true
+				Expect(hub.Status).To(Equal("active"))
+				Expect(hub.Compliance[string(database.NonCompliant)]).To(Equal(1))
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("should be able to sync the summary of the regional global hub", func() {
+		By("Create event")
+		version.Incr()
+		data := cluster.RegionalHubSummary{
+			ManagedHubs: []cluster.ManagedHubSummary{
+				{Name: "hub1", Status: "active", ManagedClusters: 3, Policies: 2, Compliance: map[string]int{
+					"compliant": 4, "non_compliant": 2,
+				}},
+				{Name: "hub2", Status: "inactive", ManagedClusters: 1},
+			},
+			ReportedAt: time.Now(),
+		}
+		evt := ToCloudEvent(regionalHubName, string(enum.RegionalHubSummaryType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			summaries := []models.RegionalHubSummary{}
+			err := database.GetGorm().Where("regional_hub_name = ?", regionalHubName).Order("leaf_hub_name").
+				Find(&summaries).Error
+			if err != nil {
+				return err
+			}
+			if len(summaries) != 2 || summaries[0].ManagedClusters != 3 || summaries[1].Status != "inactive" {
+				return fmt.Errorf("unexpected summaries: %v", summaries)
+			}
+			compliance := map[string]int{}
+			if err := json.Unmarshal(summaries[0].Compliance, &compliance); err != nil {
+				return err
+			}
+			if compliance["non_compliant"] != 2 {
+				return fmt.Errorf("unexpected compliance: %v", compliance)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove the managed hub which isn't in the summary", func() {
+		By("Create event")
+		version.Incr()
+		data := cluster.RegionalHubSummary{
+			ManagedHubs: []cluster.ManagedHubSummary{{Name: "hub1", Status: "active", ManagedClusters: 4}},
+			ReportedAt:  time.Now(),
+		}
+		evt := ToCloudEvent(regionalHubName, string(enum.RegionalHubSummaryType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			summaries := []models.RegionalHubSummary{}
+			err := database.GetGorm().Where("regional_hub_name = ?", regionalHubName).Find(&summaries).Error
+			if err != nil {
+				return err
+			}
+			if len(summaries) != 1 || summaries[0].LeafHubName != "hub1" || summaries[0].ManagedClusters != 4 {
+				return fmt.Errorf("unexpected summaries: %v", summaries)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,96 @@
+package dbsyncer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/conflict"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type resourceConflictHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewResourceConflictHandler stores the global resources which are modified on the managed hub and kept there by
+// the "report" conflict policy.
+func NewResourceConflictHandler() conflator.Handler {
+	eventType := string(enum.ResourceConflictType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &resourceConflictHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.ResourceConflictPriority,
+	}
+}
+
+func (h *resourceConflictHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *resourceConflictHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	conflicts := conflict.ResourceConflictBundle{}
+	if err := evt.DataAs(&conflicts); err != nil {
+		return err
+	}
+
+	rows := make([]models.ResourceConflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		fieldManagers, err := json.Marshal(c.FieldManagers)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, models.ResourceConflict{
+			LeafHubName:   leafHubName,
+			APIVersion:    c.APIVersion,
+			Kind:          c.Kind,
+			Namespace:     c.Namespace,
+			Name:          c.Name,
+			Policy:        c.Policy,
+			FieldManagers: fieldManagers,
+			DetectedAt:    c.DetectedAt,
+		})
+	}
+
+	// the bundle contains all the conflicts of the hub, so replace the existing ones with it
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.ResourceConflict{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, batchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the resource conflicts of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
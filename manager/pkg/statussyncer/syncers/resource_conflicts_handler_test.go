@@ -0,0 +1,77 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/conflict"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "ResourceConflictHandler"
+var _ = Describe("ResourceConflictHandler", Ordered, func() {
+	leafHubName := "hub1"
+	version := eventversion.NewVersion()
+
+	It("should be able to sync the resource conflicts", func() {
+		By("Create event")
+		version.Incr()
+		data := conflict.ResourceConflictBundle{
+			{
+				APIVersion:    "policy.open-cluster-management.io/v1",
+				Kind:          "Policy",
+				Namespace:     "default",
+				Name:          "policy1",
+				Policy:        constants.ConflictPolicyReport,
+				FieldManagers: []string{"kubectl-edit"},
+				DetectedAt:    time.Now(),
+			},
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.ResourceConflictType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			conflicts := []models.ResourceConflict{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&conflicts).Error; err != nil {
+				return err
+			}
+			if len(conflicts) != 1 || conflicts[0].Name != "policy1" || conflicts[0].Kind != "Policy" {
+				return fmt.Errorf("unexpected conflicts: %v", conflicts)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove the resolved resource conflicts", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ResourceConflictType), version, conflict.ResourceConflictBundle{})
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			var count int64
+			err := database.GetGorm().Model(&models.ResourceConflict{}).Where("leaf_hub_name = ?", leafHubName).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count != 0 {
+				return fmt.Errorf("the resource conflicts should be removed, but got %d", count)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,116 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type securityPostureHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewSecurityPostureHandler stores the security posture reported by the agent into the compliance scans and the image
+// vulnerabilities tables.
+func NewSecurityPostureHandler() conflator.Handler {
+	eventType := string(enum.SecurityPostureType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &securityPostureHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.SecurityPosturePriority,
+	}
+}
+
+func (h *securityPostureHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *securityPostureHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	data := grc.SecurityPostureBundle{}
+	if err := evt.DataAs(&data); err != nil {
+		return err
+	}
+
+	scans := make([]models.ComplianceScan, 0, len(data.ComplianceScans))
+	for _, scan := range data.ComplianceScans {
+		scans = append(scans, models.ComplianceScan{
+			LeafHubName:  leafHubName,
+			ClusterName:  data.ClusterName,
+			ScanName:     scan.Name,
+			Phase:        scan.Phase,
+			Result:       scan.Result,
+			Passed:       scan.Passed,
+			Failed:       scan.Failed,
+			Manual:       scan.Manual,
+			Others:       scan.Others,
+			FailedHigh:   scan.FailedHigh,
+			FailedMedium: scan.FailedMedium,
+			FailedLow:    scan.FailedLow,
+			EndTimestamp: scan.EndTimestamp,
+		})
+	}
+	vulnerabilities := make([]models.ImageVulnerability, 0, len(data.ImageVulnerabilities))
+	for _, summary := range data.ImageVulnerabilities {
+		vulnerabilities = append(vulnerabilities, models.ImageVulnerability{
+			LeafHubName:     leafHubName,
+			ClusterName:     data.ClusterName,
+			Severity:        summary.Severity,
+			Images:          summary.Images,
+			Vulnerabilities: summary.Vulnerabilities,
+			Fixable:         summary.Fixable,
+			AffectedPods:    summary.AffectedPods,
+		})
+	}
+
+	// the bundle contains the whole posture of the hub, so the records of the hub are replaced by it
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.ComplianceScan{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.ImageVulnerability{}).Error; err != nil {
+			return err
+		}
+		if len(scans) > 0 {
+			if err := tx.CreateInBatches(scans, batchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(vulnerabilities) > 0 {
+			return tx.CreateInBatches(vulnerabilities, batchSize).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the security posture of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
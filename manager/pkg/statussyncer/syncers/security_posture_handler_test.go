@@ -0,0 +1,84 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "SecurityPostureHandler"
+var _ = Describe("SecurityPostureHandler", Ordered, func() {
+	leafHubName := "hub-security"
+	version := eventversion.NewVersion()
+	endTime := time.Now().UTC().Truncate(time.Second)
+	posture := grc.SecurityPostureBundle{
+		ClusterName: leafHubName,
+		ComplianceScans: []grc.ComplianceScanResult{
+			{
+				Name: "ocp4-cis", Phase: "DONE", Result: "NON-COMPLIANT", EndTimestamp: &endTime,
+				Passed: 80, Failed: 5, Manual: 10, FailedHigh: 1, FailedMedium: 3, FailedLow: 1,
+			},
+			{Name: "ocp4-cis-node-worker", Phase: "DONE", Result: "COMPLIANT", EndTimestamp: &endTime, Passed: 60},
+		},
+		ImageVulnerabilities: []grc.ImageVulnerabilitySummary{
+			{Severity: "Critical", Images: 1, Vulnerabilities: 2, Fixable: 2, AffectedPods: 3},
+			{Severity: "High", Images: 2, Vulnerabilities: 7, Fixable: 4, AffectedPods: 2},
+		},
+	}
+
+	checkTables := func(expectedScans, expectedSeverities int) {
+		Eventually(func() error {
+			scans := []models.ComplianceScan{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&scans).Error; err != nil {
+				return err
+			}
+			if len(scans) != expectedScans {
+				return fmt.Errorf("unexpected compliance scans: %v", scans)
+			}
+
+			vulnerabilities := []models.ImageVulnerability{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&vulnerabilities).Error
+			if err != nil {
+				return err
+			}
+			if len(vulnerabilities) != expectedSeverities {
+				return fmt.Errorf("unexpected image vulnerabilities: %v", vulnerabilities)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	}
+
+	It("should be able to sync the security posture", func() {
+		By("Create event")
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.SecurityPostureType), version, posture)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the tables")
+		checkTables(2, 2)
+	})
+
+	It("should replace the posture once the scans are removed", func() {
+		By("Create event")
+		version.Incr()
+		posture.ComplianceScans = posture.ComplianceScans[:1]
+		posture.ImageVulnerabilities = nil
+		evt := ToCloudEvent(leafHubName, string(enum.SecurityPostureType), version, posture)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the tables")
+		checkTables(1, 0)
+	})
+})
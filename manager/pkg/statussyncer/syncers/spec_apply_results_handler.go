@@ -0,0 +1,91 @@
+package dbsyncer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type specApplyResultHandler struct {
+	log           logr.Logger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+// NewSpecApplyResultHandler stores the results of applying the global resources on the managed hub, e.g. the reason
+// of the resource failed to be applied.
+func NewSpecApplyResultHandler() conflator.Handler {
+	eventType := string(enum.SpecApplyResultType)
+	logName := strings.Replace(eventType, enum.EventTypePrefix, "", -1)
+	return &specApplyResultHandler{
+		log:           ctrl.Log.WithName(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.SpecApplyResultPriority,
+	}
+}
+
+func (h *specApplyResultHandler) RegisterHandler(conflationManager *conflator.ConflationManager) {
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *specApplyResultHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.V(2).Info(startMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	results := spec.ApplyResultBundle{}
+	if err := evt.DataAs(&results); err != nil {
+		return err
+	}
+
+	rows := make([]models.SpecApplyResult, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, models.SpecApplyResult{
+			LeafHubName: leafHubName,
+			APIVersion:  r.APIVersion,
+			Kind:        r.Kind,
+			Namespace:   r.Namespace,
+			Name:        r.Name,
+			Result:      r.Result,
+			Reason:      r.Reason,
+			AppliedAt:   r.AppliedAt,
+		})
+	}
+
+	// the bundle contains the results of all the global resources on the hub, so replace the existing ones with it
+	db := database.GetGorm()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leaf_hub_name = ?", leafHubName).Delete(&models.SpecApplyResult{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, batchSize).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the spec apply results of the hub %s: %w", leafHubName, err)
+	}
+
+	h.log.V(2).Info(finishMessage, "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
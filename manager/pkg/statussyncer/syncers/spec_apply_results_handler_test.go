@@ -0,0 +1,94 @@
+package dbsyncer_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./manager/pkg/statussyncer/syncers -v -ginkgo.focus "SpecApplyResultHandler"
+var _ = Describe("SpecApplyResultHandler", Ordered, func() {
+	leafHubName := "hub-applyresult"
+	version := eventversion.NewVersion()
+
+	It("should be able to sync the spec apply results", func() {
+		By("Create event")
+		version.Incr()
+		data := spec.ApplyResultBundle{
+			{
+				APIVersion: "policy.open-cluster-management.io/v1",
+				Kind:       "Policy",
+				Namespace:  "default",
+				Name:       "policy1",
+				Result:     spec.ApplyResultApplied,
+				AppliedAt:  time.Now(),
+			},
+			{
+				APIVersion: "apps.open-cluster-management.io/v1",
+				Kind:       "Subscription",
+				Namespace:  "default",
+				Name:       "subscription1",
+				Result:     spec.ApplyResultFailed,
+				Reason:     "admission webhook denied the request",
+				AppliedAt:  time.Now(),
+			},
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.SpecApplyResultType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			results := []models.SpecApplyResult{}
+			err := database.GetGorm().Where("leaf_hub_name = ? AND result = ?", leafHubName, spec.ApplyResultFailed).
+				Find(&results).Error
+			if err != nil {
+				return err
+			}
+			if len(results) != 1 || results[0].Name != "subscription1" || results[0].Reason == "" {
+				return fmt.Errorf("unexpected failed results: %v", results)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove the results of the deleted resources", func() {
+		By("Create event")
+		version.Incr()
+		data := spec.ApplyResultBundle{
+			{
+				APIVersion: "policy.open-cluster-management.io/v1",
+				Kind:       "Policy",
+				Namespace:  "default",
+				Name:       "policy1",
+				Result:     spec.ApplyResultApplied,
+				AppliedAt:  time.Now(),
+			},
+		}
+		evt := ToCloudEvent(leafHubName, string(enum.SpecApplyResultType), version, data)
+
+		By("Sync event with transport")
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		By("Check the table")
+		Eventually(func() error {
+			results := []models.SpecApplyResult{}
+			if err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&results).Error; err != nil {
+				return err
+			}
+			if len(results) != 1 || results[0].Name != "policy1" {
+				return fmt.Errorf("unexpected results: %v", results)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+	})
+})
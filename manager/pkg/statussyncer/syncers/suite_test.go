@@ -105,8 +105,12 @@ var _ = BeforeSuite(func() {
 	producer, err = genericproducer.NewGenericProducer(managerConfig.TransportConfig, "event")
 	Expect(err).NotTo(HaveOccurred())
 
+	// the spec events requested by the handlers aren't consumed in the tests
+	specProducer, err := genericproducer.NewGenericProducer(managerConfig.TransportConfig, "spec")
+	Expect(err).NotTo(HaveOccurred())
+
 	By("Add controllers to manager")
-	err = statussyncer.AddStatusSyncers(mgr, managerConfig)
+	err = statussyncer.AddStatusSyncers(mgr, managerConfig, specProducer)
 	Expect(err).ToNot(HaveOccurred())
 
 	By("Start the manager")
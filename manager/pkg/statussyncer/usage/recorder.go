@@ -0,0 +1,137 @@
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/statussyncer/freshness"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// FlushInterval is the interval to add the consumed bundles up into the database, so the database isn't written on
+// every received bundle
+const FlushInterval = time.Minute
+
+// SaveFunc adds the usage to the existing usage of the hubs in the database
+type SaveFunc func(ctx context.Context, usage []models.HubTransportUsage) error
+
+type usageKey struct {
+	hub        string
+	bundleType string
+	date       string
+}
+
+type usageValue struct {
+	bundles int64
+	bytes   int64
+}
+
+// Recorder accounts the bundles and the bytes consumed from each managed hub by the bundle type, they're exposed by
+// the metric and rolled up into the database per day for the capacity planning and the chargeback
+type Recorder struct {
+	log  logr.Logger
+	save SaveFunc
+
+	mutex sync.Mutex
+	// the usage consumed since the last flush
+	consumed map[usageKey]usageValue
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{
+		log:      ctrl.Log.WithName("hub-usage"),
+		save:     saveTransportUsage,
+		consumed: map[usageKey]usageValue{},
+	}
+}
+
+// Admit records the bytes of the event consumed now, the date is in UTC so the rollups of the replicas in different
+// timezones are added up to the same day. It never rejects the event, the consumed events are accounted to their hubs
+// even if they're rejected by the following filters.
+func (r *Recorder) Admit(evt *cloudevents.Event) bool {
+	bundleType := freshness.BundleType(evt.Type())
+	size := int64(len(evt.Data()))
+	monitoring.GlobalHubConsumedBytesCounterVec.WithLabelValues(evt.Source(), bundleType).Add(float64(size))
+
+	key := usageKey{hub: evt.Source(), bundleType: bundleType, date: time.Now().UTC().Format(time.DateOnly)}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	value := r.consumed[key]
+	value.bundles++
+	value.bytes += size
+	r.consumed[key] = value
+	return true
+}
+
+func (r *Recorder) Start(ctx context.Context) error {
+	ticker := time.NewTicker(FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.flush(ctx); err != nil {
+				r.log.Error(err, "failed to save the hub usage")
+			}
+		}
+	}
+}
+
+// flush adds the usage consumed since the last flush into the database, it's merged into the usage consumed in the
+// meantime and retried with the next flush on failure
+func (r *Recorder) flush(ctx context.Context) error {
+	r.mutex.Lock()
+	consumed := r.consumed
+	r.consumed = map[usageKey]usageValue{}
+	r.mutex.Unlock()
+	if len(consumed) == 0 {
+		return nil
+	}
+
+	usage := make([]models.HubTransportUsage, 0, len(consumed))
+	for key, value := range consumed {
+		date, err := time.Parse(time.DateOnly, key.date)
+		if err != nil {
+			return err
+		}
+		usage = append(usage, models.HubTransportUsage{
+			LeafHubName: key.hub,
+			BundleType:  key.bundleType,
+			UsageDate:   date,
+			Bundles:     value.bundles,
+			Bytes:       value.bytes,
+		})
+	}
+	if err := r.save(ctx, usage); err != nil {
+		r.mutex.Lock()
+		for key, value := range consumed {
+			current := r.consumed[key]
+			current.bundles += value.bundles
+			current.bytes += value.bytes
+			r.consumed[key] = current
+		}
+		r.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+func saveTransportUsage(ctx context.Context, usage []models.HubTransportUsage) error {
+	return database.GetGorm().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "bundle_type"}, {Name: "usage_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"bundles": gorm.Expr("hub_transport_usage.bundles + EXCLUDED.bundles"),
+			"bytes":   gorm.Expr("hub_transport_usage.bytes + EXCLUDED.bytes"),
+		}),
+	}).Create(&usage).Error
+}
@@ -0,0 +1,68 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(source string, eventType enum.EventType, data string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(source)
+	evt.SetType(string(eventType))
+	_ = evt.SetData(cloudevents.ApplicationJSON, []byte(data))
+	return &evt
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	saved := map[string]models.HubTransportUsage{}
+	var saveErr error
+	recorder.save = func(ctx context.Context, usage []models.HubTransportUsage) error {
+		if saveErr != nil {
+			return saveErr
+		}
+		for _, item := range usage {
+			key := item.LeafHubName + "/" + item.BundleType
+			existing := saved[key]
+			item.Bundles += existing.Bundles
+			item.Bytes += existing.Bytes
+			saved[key] = item
+		}
+		return nil
+	}
+
+	assert.True(t, recorder.Admit(newEvent("hub1", enum.ManagedClusterType, `{"a": 1}`)))
+	assert.True(t, recorder.Admit(newEvent("hub1", enum.ManagedClusterType, `{"b": 22}`)))
+	assert.True(t, recorder.Admit(newEvent("hub2", enum.HubClusterHeartbeatType, `{}`)))
+
+	// the consumed usage is kept until it's saved
+	saveErr = errors.New("the database is unavailable")
+	assert.Error(t, recorder.flush(context.Background()))
+	assert.Empty(t, saved)
+
+	// the usage consumed in the meantime is added up with the kept one
+	assert.True(t, recorder.Admit(newEvent("hub1", enum.ManagedClusterType, `{}`)))
+	saveErr = nil
+	require.NoError(t, recorder.flush(context.Background()))
+	assert.Len(t, saved, 2)
+
+	clusters := saved["hub1/managedcluster"]
+	assert.Equal(t, int64(3), clusters.Bundles)
+	assert.Equal(t, int64(8+9+2), clusters.Bytes)
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), clusters.UsageDate.Format(time.DateOnly))
+	assert.Equal(t, int64(1), saved["hub2/managedhub.heartbeat"].Bundles)
+
+	// nothing is saved if no bundle is consumed since the last flush
+	saved = map[string]models.HubTransportUsage{}
+	require.NoError(t, recorder.flush(context.Background()))
+	assert.Empty(t, saved)
+}
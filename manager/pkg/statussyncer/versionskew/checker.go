@@ -0,0 +1,172 @@
+package versionskew
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/monitoring"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	Compatible   = "compatible"
+	Incompatible = "incompatible"
+	// Unknown means the version of the agent or the manager isn't a release version, e.g. the agent before the
+	// version is embedded in the bundles or a development build, so the compatibility isn't enforced
+	Unknown = "unknown"
+)
+
+// DefaultMaxMinorSkew is the number of the minor versions the agents may fall behind the manager by default
+const DefaultMaxMinorSkew = 1
+
+// saveTimeout bounds the persisting of the agent version, so a slow database doesn't block the dispatching
+const saveTimeout = 5 * time.Second
+
+// admittedEventTypes are persisted even if the agent is incompatible, so the managed hub is still visible with its
+// heartbeat and the agent version
+var admittedEventTypes = map[string]bool{
+	string(enum.HubClusterHeartbeatType): true,
+	string(enum.HubClusterInfoType):      true,
+}
+
+var releaseVersionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)(\.\d+)?([-+].*)?$`)
+
+// SaveFunc persists the version of the agent once it's changed
+type SaveFunc func(ctx context.Context, agentVersion *models.AgentVersion) error
+
+// Checker detects the version of the agent from the bundles of each managed hub, and the agent is incompatible if
+// its major version differs from the manager or its minor version is newer than the manager or falls behind the
+// manager by more than the max skew. The bundles of the incompatible agents are quarantined, which means they aren't
+// persisted to protect the database schema, if the quarantine is enabled.
+type Checker struct {
+	log            logr.Logger
+	managerVersion string
+	maxMinorSkew   int
+	quarantine     bool
+	save           SaveFunc
+
+	mutex sync.Mutex
+	// the last persisted version of the agent on each managed hub
+	agents map[string]models.AgentVersion
+}
+
+func NewChecker(managerVersion string, maxMinorSkew int, quarantine bool) *Checker {
+	return &Checker{
+		log:            ctrl.Log.WithName("version-skew"),
+		managerVersion: managerVersion,
+		maxMinorSkew:   maxMinorSkew,
+		quarantine:     quarantine,
+		save:           saveAgentVersion,
+		agents:         map[string]models.AgentVersion{},
+	}
+}
+
+// Admit records the version of the agent sending the event, it returns false if the event should be quarantined
+func (c *Checker) Admit(evt *cloudevents.Event) bool {
+	agentVersion := ""
+	if value, found := evt.Extensions()[transport.ComponentVersionKey]; found {
+		agentVersion = fmt.Sprint(value)
+	}
+	compatibility, reason := Check(c.managerVersion, agentVersion, c.maxMinorSkew)
+	quarantined := c.quarantine && compatibility == Incompatible
+	c.record(models.AgentVersion{
+		LeafHubName:    evt.Source(),
+		AgentVersion:   agentVersion,
+		ManagerVersion: c.managerVersion,
+		Compatibility:  compatibility,
+		Reason:         reason,
+		Quarantined:    quarantined,
+	})
+
+	if !quarantined || admittedEventTypes[evt.Type()] {
+		return true
+	}
+	monitoring.GlobalHubQuarantinedEventsCounterVec.WithLabelValues(evt.Source()).Inc()
+	c.log.V(2).Info("quarantine the event of the incompatible agent", "source", evt.Source(), "type", evt.Type(),
+		"agentVersion", agentVersion)
+	return false
+}
+
+// record persists the version of the agent if it's changed, it's retried with the next event on failure
+func (c *Checker) record(agentVersion models.AgentVersion) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if last, found := c.agents[agentVersion.LeafHubName]; found && last == agentVersion {
+		return
+	}
+	if agentVersion.Compatibility == Incompatible {
+		c.log.Info("the agent is incompatible with the manager", "hub", agentVersion.LeafHubName,
+			"agentVersion", agentVersion.AgentVersion, "managerVersion", c.managerVersion,
+			"reason", agentVersion.Reason, "quarantined", agentVersion.Quarantined)
+	}
+	saved := agentVersion
+	saved.UpdatedAt = time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+	if err := c.save(ctx, &saved); err != nil {
+		c.log.Error(err, "failed to save the agent version", "hub", agentVersion.LeafHubName)
+		return
+	}
+	c.agents[agentVersion.LeafHubName] = agentVersion
+}
+
+// Check returns the compatibility of the agent version with the manager version and the reason if it isn't compatible
+func Check(managerVersion, agentVersion string, maxMinorSkew int) (string, string) {
+	if agentVersion == "" {
+		return Unknown, "the agent doesn't report its version"
+	}
+	managerMajor, managerMinor, ok := parseVersion(managerVersion)
+	if !ok {
+		return Unknown, fmt.Sprintf("the manager version %s isn't a release version", managerVersion)
+	}
+	agentMajor, agentMinor, ok := parseVersion(agentVersion)
+	if !ok {
+		return Unknown, fmt.Sprintf("the agent version %s isn't a release version", agentVersion)
+	}
+	if agentMajor != managerMajor {
+		return Incompatible, fmt.Sprintf("the major version of the agent %s differs from the manager %s",
+			agentVersion, managerVersion)
+	}
+	if agentMinor > managerMinor {
+		return Incompatible, fmt.Sprintf("the agent %s is newer than the manager %s", agentVersion, managerVersion)
+	}
+	if managerMinor-agentMinor > maxMinorSkew {
+		return Incompatible, fmt.Sprintf("the agent %s falls behind the manager %s by more than %d minor versions",
+			agentVersion, managerVersion, maxMinorSkew)
+	}
+	return Compatible, ""
+}
+
+// parseVersion returns the major and minor version of the release version, e.g. v1.2.0 or 1.2.0-rc1
+func parseVersion(version string) (int, int, bool) {
+	matches := releaseVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+func saveAgentVersion(ctx context.Context, agentVersion *models.AgentVersion) error {
+	return database.GetGorm().WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(agentVersion).Error
+}
@@ -0,0 +1,102 @@
+package versionskew
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		managerVersion string
+		agentVersion   string
+		want           string
+	}{
+		{name: "same version", managerVersion: "v1.3.0", agentVersion: "v1.3.0", want: Compatible},
+		{name: "patch skew", managerVersion: "v1.3.2", agentVersion: "1.3.0", want: Compatible},
+		{name: "one minor behind", managerVersion: "v1.3.0", agentVersion: "v1.2.5", want: Compatible},
+		{name: "pre-release", managerVersion: "v1.3.0-rc1", agentVersion: "v1.2", want: Compatible},
+		{name: "two minors behind", managerVersion: "v1.3.0", agentVersion: "v1.1.0", want: Incompatible},
+		{name: "newer agent", managerVersion: "v1.3.0", agentVersion: "v1.4.0", want: Incompatible},
+		{name: "major skew", managerVersion: "v2.0.0", agentVersion: "v1.9.0", want: Incompatible},
+		{name: "no agent version", managerVersion: "v1.3.0", agentVersion: "", want: Unknown},
+		{name: "development agent", managerVersion: "v1.3.0", agentVersion: "4f2a9c1e", want: Unknown},
+		{name: "development manager", managerVersion: "unknown", agentVersion: "v1.3.0", want: Unknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compatibility, reason := Check(tt.managerVersion, tt.agentVersion, DefaultMaxMinorSkew)
+			assert.Equal(t, tt.want, compatibility)
+			if compatibility == Compatible {
+				assert.Empty(t, reason)
+			} else {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func newEvent(source string, eventType enum.EventType, agentVersion string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource(source)
+	evt.SetType(string(eventType))
+	if agentVersion != "" {
+		evt.SetExtension(transport.ComponentVersionKey, agentVersion)
+	}
+	return &evt
+}
+
+func TestChecker(t *testing.T) {
+	saved := []models.AgentVersion{}
+	var saveErr error
+	newChecker := func(quarantine bool) *Checker {
+		checker := NewChecker("v1.3.0", DefaultMaxMinorSkew, quarantine)
+		checker.save = func(ctx context.Context, agentVersion *models.AgentVersion) error {
+			if saveErr != nil {
+				return saveErr
+			}
+			saved = append(saved, *agentVersion)
+			return nil
+		}
+		return checker
+	}
+
+	checker := newChecker(false)
+	assert.True(t, checker.Admit(newEvent("hub1", enum.ManagedClusterType, "v1.1.0")),
+		"the incompatible agent shouldn't be quarantined if the quarantine is disabled")
+	require.Len(t, saved, 1)
+	assert.Equal(t, Incompatible, saved[0].Compatibility)
+	assert.False(t, saved[0].Quarantined)
+
+	checker = newChecker(true)
+	assert.False(t, checker.Admit(newEvent("hub1", enum.ManagedClusterType, "v1.1.0")))
+	assert.True(t, checker.Admit(newEvent("hub1", enum.HubClusterHeartbeatType, "v1.1.0")),
+		"the heartbeat of the incompatible agent should be persisted")
+	require.Len(t, saved, 2, "the version should only be saved once it's changed")
+	assert.True(t, saved[1].Quarantined)
+
+	// the agent is upgraded
+	assert.True(t, checker.Admit(newEvent("hub1", enum.ManagedClusterType, "v1.3.0")))
+	assert.True(t, checker.Admit(newEvent("hub2", enum.ManagedClusterType, "")))
+	require.Len(t, saved, 4)
+	assert.Equal(t, Compatible, saved[2].Compatibility)
+	assert.Equal(t, "hub2", saved[3].LeafHubName)
+	assert.Equal(t, Unknown, saved[3].Compatibility)
+
+	// the version is saved again with the next event if it's failed to save
+	saveErr = errors.New("connection refused")
+	assert.True(t, checker.Admit(newEvent("hub3", enum.ManagedClusterType, "v1.3.0")))
+	saveErr = nil
+	assert.True(t, checker.Admit(newEvent("hub3", enum.ManagedClusterType, "v1.3.0")))
+	require.Len(t, saved, 5)
+	assert.Equal(t, "hub3", saved[4].LeafHubName)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/go-logr/logr"
+	"gorm.io/gorm"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+)
+
+// UpstreamConfig is the upstream global hub which the regional global hub forwards the summaries to, the forwarder
+// is disabled if the bootstrap server of the upstream kafka is empty
+type UpstreamConfig struct {
+	// RegionalHubName is the name of the regional global hub, it's the source of the summaries on the upstream
+	RegionalHubName string
+	SyncInterval    time.Duration
+	TransportConfig *transport.TransportConfig
+}
+
+func (c *UpstreamConfig) Enabled() bool {
+	return c != nil && c.TransportConfig != nil && c.TransportConfig.KafkaConfig.BootstrapServer != ""
+}
+
+// forwarder sends the summary of the managed hubs to the status topic of the upstream global hub periodically, so
+// the global hub acts as an agent of the upstream global hub, e.g. the regional global hubs roll up into a corporate
+// one. Only the aggregated summary is forwarded, the resources of the managed hubs stay on the regional global hub.
+type forwarder struct {
+	log             logr.Logger
+	producer        transport.Producer
+	regionalHubName string
+	syncInterval    time.Duration
+	currentVersion  *eventversion.Version
+}
+
+// AddUpstreamForwarder adds the forwarder into the manager if the upstream global hub is configured, it only runs on
+// the leader replica
+func AddUpstreamForwarder(mgr ctrl.Manager, config *UpstreamConfig) error {
+	if !config.Enabled() {
+		return nil
+	}
+	if config.RegionalHubName == "" {
+		return fmt.Errorf("the regional hub name is required to forward the summaries to the upstream global hub")
+	}
+	upstreamProducer, err := producer.NewGenericProducer(config.TransportConfig,
+		config.TransportConfig.KafkaConfig.Topics.StatusTopic)
+	if err != nil {
+		return fmt.Errorf("failed to create the producer of the upstream global hub: %w", err)
+	}
+	return mgr.Add(NewForwarder(upstreamProducer, config.RegionalHubName, config.SyncInterval))
+}
+
+func NewForwarder(producer transport.Producer, regionalHubName string, syncInterval time.Duration) *forwarder {
+	return &forwarder{
+		log:             ctrl.Log.WithName("upstream-forwarder"),
+		producer:        producer,
+		regionalHubName: regionalHubName,
+		syncInterval:    syncInterval,
+		currentVersion:  eventversion.NewVersion(),
+	}
+}
+
+func (f *forwarder) Start(ctx context.Context) error {
+	f.log.Info("forward the summaries to the upstream global hub", "interval", f.syncInterval)
+	ticker := time.NewTicker(f.syncInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.forward(ctx); err != nil {
+			f.log.Error(err, "failed to forward the summary to the upstream global hub")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// forward sends the summary on each interval even if it isn't changed, so the report time of the summary tells the
+// upstream global hub whether the regional global hub is alive
+func (f *forwarder) forward(ctx context.Context) error {
+	summary, err := Summarize(database.GetGorm())
+	if err != nil {
+		return err
+	}
+
+	f.currentVersion.Incr()
+	evt := cloudevents.NewEvent()
+	evt.SetSource(f.regionalHubName)
+	evt.SetType(string(enum.RegionalHubSummaryType))
+	evt.SetExtension(eventversion.ExtVersion, f.currentVersion.String())
+	if err := evt.SetData(cloudevents.ApplicationJSON, summary); err != nil {
+		return err
+	}
+	if err := f.producer.SendEvent(ctx, evt); err != nil {
+		return err
+	}
+	f.currentVersion.Next()
+	return nil
+}
+
+// Summarize aggregates the managed hubs of the global hub from the database, the detached hubs are excluded
+func Summarize(db *gorm.DB) (*cluster.RegionalHubSummary, error) {
+	var heartbeats []models.LeafHubHeartbeat
+	if err := db.Where("status <> ?", hubmanagement.HubDetached).Order("leaf_hub_name").
+		Find(&heartbeats).Error; err != nil {
+		return nil, err
+	}
+
+	type count struct {
+		LeafHubName string
+		Compliance  string
+		Count       int
+	}
+	var clusterCounts []count
+	if err := db.Model(&models.ManagedCluster{}).Select("leaf_hub_name, count(*) AS count").
+		Group("leaf_hub_name").Scan(&clusterCounts).Error; err != nil {
+		return nil, err
+	}
+	var policyCounts []count
+	if err := db.Model(&models.LocalSpecPolicy{}).Select("leaf_hub_name, count(*) AS count").
+		Group("leaf_hub_name").Scan(&policyCounts).Error; err != nil {
+		return nil, err
+	}
+	var complianceCounts []count
+	if err := db.Model(&models.LocalStatusCompliance{}).Select("leaf_hub_name, compliance, count(*) AS count").
+		Group("leaf_hub_name, compliance").Scan(&complianceCounts).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &cluster.RegionalHubSummary{
+		ManagedHubs: make([]cluster.ManagedHubSummary, 0, len(heartbeats)),
+		ReportedAt:  time.Now(),
+	}
+	hubs := map[string]*cluster.ManagedHubSummary{}
+	for _, heartbeat := range heartbeats {
+		summary.ManagedHubs = append(summary.ManagedHubs, cluster.ManagedHubSummary{
+			Name:          heartbeat.Name,
+			Status:        heartbeat.Status,
+			LastHeartbeat: heartbeat.LastUpdateAt,
+			Compliance:    map[string]int{},
+		})
+	}
+	for i := range summary.ManagedHubs {
+		hubs[summary.ManagedHubs[i].Name] = &summary.ManagedHubs[i]
+	}
+	for _, c := range clusterCounts {
+		if hub, ok := hubs[c.LeafHubName]; ok {
+			hub.ManagedClusters = c.Count
+		}
+	}
+	for _, c := range policyCounts {
+		if hub, ok := hubs[c.LeafHubName]; ok {
+			hub.Policies = c.Count
+		}
+	}
+	for _, c := range complianceCounts {
+		if hub, ok := hubs[c.LeafHubName]; ok {
+			hub.Compliance[c.Compliance] = c.Count
+		}
+	}
+	return summary, nil
+}
@@ -50,6 +50,39 @@ const (
 	HAHigh AvailabilityType = "High"
 )
 
+// PodSecurityProfile is the security context applied to the pods of the global hub components
+type PodSecurityProfile string
+
+const (
+	// PodSecurityRestricted runs the pods with the security context of the restricted pod security standard
+	PodSecurityRestricted PodSecurityProfile = "Restricted"
+	// PodSecurityCustom leaves the security context of the pods to the custom SecurityContextConstraints
+	PodSecurityCustom PodSecurityProfile = "Custom"
+)
+
+// DataCollectionProfile decides the status bundles collected by the agents of the managed hubs
+type DataCollectionProfile string
+
+const (
+	// DataCollectionMinimal only collects the managed clusters and the policy compliance
+	DataCollectionMinimal DataCollectionProfile = "Minimal"
+	// DataCollectionStandard collects everything but the raw events of the policies
+	DataCollectionStandard DataCollectionProfile = "Standard"
+	// DataCollectionFull collects all the status bundles
+	DataCollectionFull DataCollectionProfile = "Full"
+)
+
+// DisasterRecoveryRole is the role of the global hub in the full-site disaster recovery
+type DisasterRecoveryRole string
+
+const (
+	// DisasterRecoveryPrimary serves the managed hubs and publishes the database to the standby
+	DisasterRecoveryPrimary DisasterRecoveryRole = "Primary"
+	// DisasterRecoveryStandby replicates the database and mirrors the kafka topics of the primary without running the
+	// manager, so it takes over once it's promoted
+	DisasterRecoveryStandby DisasterRecoveryRole = "Standby"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName={mgh,mcgh}
@@ -96,6 +129,55 @@ type MulticlusterGlobalHubSpec struct {
 	// EnableMetrics enables the metrics for the global hub kafka components
 	// +optional
 	EnableMetrics bool `json:"enableMetrics,omitempty"`
+	// EnableNetworkPolicy renders the NetworkPolicies which only allow the required ingress traffic of the global hub
+	// components, e.g. the manager, the built-in postgres, the built-in grafana and the operator
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	EnableNetworkPolicy bool `json:"enableNetworkPolicy,omitempty"`
+	// EnableDiagnostics serves the pprof, expvar and the diagnostics bundle under /debug of the metrics endpoints of the
+	// manager and the agents, the requests are authorized by the kube-apiserver
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	EnableDiagnostics bool `json:"enableDiagnostics,omitempty"`
+	// PodSecurityProfile of the global hub components. Options are: Restricted (default) and Custom. The Restricted
+	// runs the pods as non-root with the RuntimeDefault seccomp profile and without any capabilities, the Custom doesn't
+	// set the security context so that it's decided by the custom SecurityContextConstraints
+	// +kubebuilder:default:="Restricted"
+	// +kubebuilder:validation:Enum=Restricted;Custom
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	PodSecurityProfile PodSecurityProfile `json:"podSecurityProfile,omitempty"`
+	// GrafanaAuth configures the built-in grafana to authenticate the users with the OAuth/OIDC provider or the LDAP
+	// server instead of the OpenShift oauth proxy
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	GrafanaAuth *GrafanaAuthConfig `json:"grafanaAuth,omitempty"`
+	// ExternalGrafana provisions the datasource and dashboards into the Grafana instances of the Grafana Operator
+	// instead of deploying the built-in grafana
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	ExternalGrafana *ExternalGrafanaConfig `json:"externalGrafana,omitempty"`
+	// Components enables or disables the optional components of the global hub
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Components *ComponentsConfig `json:"components,omitempty"`
+	// Scheduler configures the schedules of the jobs which summarize and clean up the data in the database
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+	// DataCollectionProfile decides the status bundles collected by the agents. Options are: Minimal, Standard and
+	// Full (default). The Minimal only collects the managed clusters and the policy compliance, the Standard collects
+	// everything but the raw events of the policies
+	// +kubebuilder:default:="Full"
+	// +kubebuilder:validation:Enum=Minimal;Standard;Full
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DataCollectionProfile DataCollectionProfile `json:"dataCollectionProfile,omitempty"`
+	// DisasterRecovery configures the global hub as the primary or the warm standby of the full-site disaster
+	// recovery, the standby is promoted by changing its role to Primary
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +optional
+	DisasterRecovery *DisasterRecoveryConfig `json:"disasterRecovery,omitempty"`
 }
 
 type AdvancedConfig struct {
@@ -168,6 +250,10 @@ type PostgresConfig struct {
 	// Specify the size for storage.
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
+	// TrustedCABundles are the additional CA bundles of the BYO postgres, they're merged with the ca.crt of the storage
+	// secret and trusted by the manager and grafana, e.g. the private enterprise CA which signs the database server
+	// +optional
+	TrustedCABundles []CABundleReference `json:"trustedCABundles,omitempty"`
 }
 
 // KafkaConfig defines the desired state of kafka
@@ -175,6 +261,205 @@ type KafkaConfig struct {
 	// Specify the size for storage.
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
+	// TopicAutoscaling increases the partitions of the status and event topics of the built-in kafka when their
+	// throughput or the consumer lag of the manager exceeds the thresholds, it's disabled if it isn't set
+	// +optional
+	TopicAutoscaling *TopicAutoscalingConfig `json:"topicAutoscaling,omitempty"`
+	// TrustedCABundles are the additional CA bundles of the BYO kafka, they're merged with the ca.crt of the transport
+	// secret and trusted by the manager and the agents, e.g. the private enterprise CA which signs the brokers
+	// +optional
+	TrustedCABundles []CABundleReference `json:"trustedCABundles,omitempty"`
+	// ReadOnly never creates the topics, users and ACLs of the BYO kafka, the topics are only validated to exist with
+	// the required configuration, e.g. the kafka governance forbids the automated admin operations
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// CABundleReference refers to the PEM encoded CA bundle in the configmap of the global hub namespace
+type CABundleReference struct {
+	// Name of the configmap
+	Name string `json:"name"`
+	// Key of the CA bundle in the configmap, it's the key of the trusted CA bundle injected by the OpenShift by default
+	// +kubebuilder:default:="ca-bundle.crt"
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// TopicAutoscalingConfig defines the thresholds and the limit to increase the partitions of the kafka topics
+type TopicAutoscalingConfig struct {
+	// MaxPartitions is the limit of the partitions of each topic, the partitions are doubled until reaching it
+	// +kubebuilder:default:=12
+	// +kubebuilder:validation:Minimum=1
+	MaxPartitions int32 `json:"maxPartitions,omitempty"`
+	// LagThreshold is the average number of the messages per partition which the manager falls behind
+	// +kubebuilder:default:=1000
+	// +kubebuilder:validation:Minimum=1
+	LagThreshold int64 `json:"lagThreshold,omitempty"`
+	// ThroughputThreshold is the average number of the messages per second produced to each partition
+	// +kubebuilder:default:=100
+	// +kubebuilder:validation:Minimum=1
+	ThroughputThreshold int64 `json:"throughputThreshold,omitempty"`
+	// SustainedDuration is how long either threshold has to be exceeded before the partitions are increased
+	// +kubebuilder:default:="10m"
+	SustainedDuration metav1.Duration `json:"sustainedDuration,omitempty"`
+}
+
+// DisasterRecoveryConfig defines the role of the global hub and the connections of the primary for the standby
+type DisasterRecoveryConfig struct {
+	// Role is Primary or Standby. The primary publishes the database by the logical replication, and the standby
+	// subscribes to it and mirrors the kafka topics of the primary. The standby is promoted by changing it to Primary
+	// +kubebuilder:default:="Primary"
+	// +kubebuilder:validation:Enum=Primary;Standby
+	Role DisasterRecoveryRole `json:"role,omitempty"`
+	// PrimaryPostgresSecret is the secret of the primary database which is required by the standby, it has the
+	// "database_uri" of the user with the REPLICATION privilege as the BYO storage secret
+	// +optional
+	PrimaryPostgresSecret string `json:"primaryPostgresSecret,omitempty"`
+	// PrimaryKafkaSecret is the secret of the primary kafka to mirror the topics from, it has the "bootstrap_server",
+	// "ca.crt", "client.crt" and "client.key" as the BYO transport secret. The topics aren't mirrored if it isn't set
+	// +optional
+	PrimaryKafkaSecret string `json:"primaryKafkaSecret,omitempty"`
+}
+
+// GrafanaAuthConfig defines the authentication of the built-in grafana
+type GrafanaAuthConfig struct {
+	// OAuth authenticates the users with the generic OAuth2/OIDC provider
+	// +optional
+	OAuth *GrafanaOAuthConfig `json:"oauth,omitempty"`
+	// LDAP authenticates the users with the LDAP server
+	// +optional
+	LDAP *GrafanaLDAPConfig `json:"ldap,omitempty"`
+	// RoleMapping maps the groups of the users to the grafana roles, the users not in any group are Viewer
+	// +optional
+	RoleMapping *GrafanaRoleMapping `json:"roleMapping,omitempty"`
+}
+
+// ExternalGrafanaConfig defines the Grafana instances managed by the user
+type ExternalGrafanaConfig struct {
+	// InstanceSelector selects the Grafana instances of the Grafana Operator
+	InstanceSelector metav1.LabelSelector `json:"instanceSelector"`
+	// Folder of the global hub dashboards in the Grafana instances
+	// +kubebuilder:default:="Multicluster Global Hub"
+	Folder string `json:"folder,omitempty"`
+	// DatasourceUID is the uid of the global hub datasource provisioned into the Grafana instances, the global hub
+	// dashboards are updated to refer to it. The default is the uid of the datasource in the built-in grafana
+	// +optional
+	DatasourceUID string `json:"datasourceUID,omitempty"`
+}
+
+// ComponentsConfig defines the optional components of the global hub
+type ComponentsConfig struct {
+	// Grafana is the built-in grafana or the dashboards provisioned into the external grafana. If it's disabled, the
+	// connection details of the postgres datasource are rendered in the secret for the user's visualization stack
+	// +optional
+	Grafana *ComponentConfig `json:"grafana,omitempty"`
+	// ConsolePlugin is the OpenShift console plugin showing the fleet summary pages, which are backed by the api of
+	// the manager. It's disabled by default
+	// +optional
+	ConsolePlugin *ConsolePluginConfig `json:"consolePlugin,omitempty"`
+}
+
+// ComponentConfig defines whether the component is deployed
+type ComponentConfig struct {
+	// Enabled deploys the component, it's enabled by default
+	// +kubebuilder:default:=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ConsolePluginConfig defines the OpenShift console plugin of the global hub
+type ConsolePluginConfig struct {
+	// Enabled deploys the console plugin and enables it in the OpenShift console
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// ServingCertSecret is the secret with the tls.crt and tls.key the plugin is served with, the certificate must be
+	// trusted by the console. The certificate issued by the service CA of OpenShift is used if it's not set
+	// +optional
+	ServingCertSecret string `json:"servingCertSecret,omitempty"`
+	// Route exposes the plugin out of the console, it isn't created if it's not set
+	// +optional
+	Route *ConsolePluginRouteConfig `json:"route,omitempty"`
+}
+
+// ConsolePluginRouteConfig defines the route of the console plugin
+type ConsolePluginRouteConfig struct {
+	// Host of the route, it's generated by the router if it's not set
+	// +optional
+	Host string `json:"host,omitempty"`
+	// CertificateSecret is the secret with the tls.crt and tls.key of the route, the default certificate of the
+	// router is used if it's not set
+	// +optional
+	CertificateSecret string `json:"certificateSecret,omitempty"`
+}
+
+// GrafanaOAuthConfig defines the generic OAuth2/OIDC provider of the grafana
+type GrafanaOAuthConfig struct {
+	// Name of the provider shown on the login page
+	// +kubebuilder:default:="SSO"
+	Name string `json:"name,omitempty"`
+	// ClientSecretName is the secret in the global hub namespace with the client_id and client_secret keys
+	ClientSecretName string `json:"clientSecretName"`
+	// AuthURL is the authorization endpoint of the provider
+	AuthURL string `json:"authURL"`
+	// TokenURL is the token endpoint of the provider
+	TokenURL string `json:"tokenURL"`
+	// APIURL is the user info endpoint of the provider
+	APIURL string `json:"apiURL"`
+	// Scopes requested from the provider
+	// +kubebuilder:default:={"openid","profile","email","groups"}
+	Scopes []string `json:"scopes,omitempty"`
+	// GroupsAttributePath is the JMESPath expression to get the groups from the user info or the id token
+	// +kubebuilder:default:="groups"
+	GroupsAttributePath string `json:"groupsAttributePath,omitempty"`
+}
+
+// GrafanaLDAPConfig defines the LDAP server of the grafana
+type GrafanaLDAPConfig struct {
+	// Host of the LDAP server
+	Host string `json:"host"`
+	// Port of the LDAP server
+	// +kubebuilder:default:=389
+	Port int32 `json:"port,omitempty"`
+	// UseSSL enables the LDAPS
+	// +optional
+	UseSSL bool `json:"useSSL,omitempty"`
+	// BindDN is the user to search the LDAP server
+	BindDN string `json:"bindDN"`
+	// BindPasswordSecretName is the secret in the global hub namespace with the password key of the BindDN
+	BindPasswordSecretName string `json:"bindPasswordSecretName"`
+	// SearchFilter is the user search filter, the %s is replaced with the login name
+	// +kubebuilder:default:="(uid=%s)"
+	SearchFilter string `json:"searchFilter,omitempty"`
+	// SearchBaseDNs are the base DNs to search the users
+	SearchBaseDNs []string `json:"searchBaseDNs"`
+}
+
+// GrafanaRoleMapping maps the groups of the users to the grafana roles. For OAuth, the groups are the values got by
+// the GroupsAttributePath. For LDAP, the groups are the DNs of the memberOf attribute.
+type GrafanaRoleMapping struct {
+	// AdminGroups are the groups whose users are Admin
+	// +optional
+	AdminGroups []string `json:"adminGroups,omitempty"`
+	// EditorGroups are the groups whose users are Editor
+	// +optional
+	EditorGroups []string `json:"editorGroups,omitempty"`
+}
+
+// SchedulerConfig defines the cron schedules of the jobs of the global hub manager. The schedule is in the standard
+// cron format with 5 fields, e.g. "0 0 * * *" runs the job at 00:00 every day.
+type SchedulerConfig struct {
+	// TimeZone of the schedules in the IANA time zone database, e.g. "America/New_York". The local time zone of the
+	// manager is used if it's empty
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// LocalComplianceHistory is the schedule of the job which summarizes the compliance of the day into the history,
+	// it runs at 00:00 every day by default
+	// +optional
+	LocalComplianceHistory string `json:"localComplianceHistory,omitempty"`
+	// DataRetention is the schedule of the job which maintains the partition tables and deletes the expired data, it
+	// runs at 00:00 on the 1st, 15th and 28th of every month by default
+	// +optional
+	DataRetention string `json:"dataRetention,omitempty"`
 }
 
 // MulticlusterGlobalHubStatus defines the observed state of MulticlusterGlobalHub
@@ -182,6 +467,67 @@ type MulticlusterGlobalHubStatus struct {
 	// MulticlusterGlobalHubStatus defines the observed state of MulticlusterGlobalHub
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Jobs is the last run of the scheduled jobs of the global hub manager
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Jobs []JobStatus `json:"jobs,omitempty"`
+	// Upgrade is the status of the migrations run by the operator upgrades
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// DisasterRecovery is the role of the global hub and the time it's promoted from the standby
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	DisasterRecovery *DisasterRecoveryStatus `json:"disasterRecovery,omitempty"`
+}
+
+// DisasterRecoveryStatus is the observed role of the global hub in the disaster recovery
+type DisasterRecoveryStatus struct {
+	// Role is the role the global hub is running as
+	Role DisasterRecoveryRole `json:"role,omitempty"`
+	// PromotedTime is the last time the standby is promoted to the primary
+	// +optional
+	PromotedTime *metav1.Time `json:"promotedTime,omitempty"`
+}
+
+// JobStatus is the last run of the scheduled job
+type JobStatus struct {
+	// Name of the job
+	Name string `json:"name"`
+	// LastRunTime is the start time of the last run
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// NextRunTime is the scheduled time of the next run
+	// +optional
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+	// Result of the last run, Succeeded or Failed
+	// +optional
+	Result string `json:"result,omitempty"`
+	// Message is the error of the last run if it's failed
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradeStatus is the status of the migrations run by the operator upgrades
+type UpgradeStatus struct {
+	// OperatorVersion is the version of the operator which has completed the migrations
+	// +optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+	// Migrations are the applied migrations in the applied order
+	// +optional
+	Migrations []MigrationStatus `json:"migrations,omitempty"`
+}
+
+// MigrationStatus is the applied migration of the database or the kafka
+type MigrationStatus struct {
+	// Name of the migration
+	Name string `json:"name"`
+	// OperatorVersion is the version of the operator which applied the migration
+	// +optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+	// AppliedTime is the time when the migration is applied
+	// +optional
+	AppliedTime *metav1.Time `json:"appliedTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -72,6 +72,21 @@ func (in *AdvancedConfig) DeepCopy() *AdvancedConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleReference) DeepCopyInto(out *CABundleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleReference.
+func (in *CABundleReference) DeepCopy() *CABundleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
 	*out = *in
@@ -92,11 +107,91 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentConfig) DeepCopyInto(out *ComponentConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentConfig.
+func (in *ComponentConfig) DeepCopy() *ComponentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentsConfig) DeepCopyInto(out *ComponentsConfig) {
+	*out = *in
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(ComponentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConsolePlugin != nil {
+		in, out := &in.ConsolePlugin, &out.ConsolePlugin
+		*out = new(ConsolePluginConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentsConfig.
+func (in *ComponentsConfig) DeepCopy() *ComponentsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsolePluginConfig) DeepCopyInto(out *ConsolePluginConfig) {
+	*out = *in
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(ConsolePluginRouteConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsolePluginConfig.
+func (in *ConsolePluginConfig) DeepCopy() *ConsolePluginConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsolePluginConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsolePluginRouteConfig) DeepCopyInto(out *ConsolePluginRouteConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsolePluginRouteConfig.
+func (in *ConsolePluginRouteConfig) DeepCopy() *ConsolePluginRouteConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsolePluginRouteConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataLayerConfig) DeepCopyInto(out *DataLayerConfig) {
 	*out = *in
-	out.Kafka = in.Kafka
-	out.Postgres = in.Postgres
+	in.Kafka.DeepCopyInto(&out.Kafka)
+	in.Postgres.DeepCopyInto(&out.Postgres)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataLayerConfig.
@@ -109,9 +204,187 @@ func (in *DataLayerConfig) DeepCopy() *DataLayerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryConfig) DeepCopyInto(out *DisasterRecoveryConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryConfig.
+func (in *DisasterRecoveryConfig) DeepCopy() *DisasterRecoveryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryStatus) DeepCopyInto(out *DisasterRecoveryStatus) {
+	*out = *in
+	if in.PromotedTime != nil {
+		in, out := &in.PromotedTime, &out.PromotedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryStatus.
+func (in *DisasterRecoveryStatus) DeepCopy() *DisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalGrafanaConfig) DeepCopyInto(out *ExternalGrafanaConfig) {
+	*out = *in
+	in.InstanceSelector.DeepCopyInto(&out.InstanceSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalGrafanaConfig.
+func (in *ExternalGrafanaConfig) DeepCopy() *ExternalGrafanaConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalGrafanaConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaAuthConfig) DeepCopyInto(out *GrafanaAuthConfig) {
+	*out = *in
+	if in.OAuth != nil {
+		in, out := &in.OAuth, &out.OAuth
+		*out = new(GrafanaOAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(GrafanaLDAPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RoleMapping != nil {
+		in, out := &in.RoleMapping, &out.RoleMapping
+		*out = new(GrafanaRoleMapping)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaAuthConfig.
+func (in *GrafanaAuthConfig) DeepCopy() *GrafanaAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaLDAPConfig) DeepCopyInto(out *GrafanaLDAPConfig) {
+	*out = *in
+	if in.SearchBaseDNs != nil {
+		in, out := &in.SearchBaseDNs, &out.SearchBaseDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaLDAPConfig.
+func (in *GrafanaLDAPConfig) DeepCopy() *GrafanaLDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaLDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaOAuthConfig) DeepCopyInto(out *GrafanaOAuthConfig) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaOAuthConfig.
+func (in *GrafanaOAuthConfig) DeepCopy() *GrafanaOAuthConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaOAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaRoleMapping) DeepCopyInto(out *GrafanaRoleMapping) {
+	*out = *in
+	if in.AdminGroups != nil {
+		in, out := &in.AdminGroups, &out.AdminGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EditorGroups != nil {
+		in, out := &in.EditorGroups, &out.EditorGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaRoleMapping.
+func (in *GrafanaRoleMapping) DeepCopy() *GrafanaRoleMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaRoleMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobStatus) DeepCopyInto(out *JobStatus) {
+	*out = *in
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRunTime != nil {
+		in, out := &in.NextRunTime, &out.NextRunTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
+func (in *JobStatus) DeepCopy() *JobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaConfig) DeepCopyInto(out *KafkaConfig) {
 	*out = *in
+	if in.TopicAutoscaling != nil {
+		in, out := &in.TopicAutoscaling, &out.TopicAutoscaling
+		*out = new(TopicAutoscalingConfig)
+		**out = **in
+	}
+	if in.TrustedCABundles != nil {
+		in, out := &in.TrustedCABundles, &out.TrustedCABundles
+		*out = make([]CABundleReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConfig.
@@ -124,6 +397,25 @@ func (in *KafkaConfig) DeepCopy() *KafkaConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
+	*out = *in
+	if in.AppliedTime != nil {
+		in, out := &in.AppliedTime, &out.AppliedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
+func (in *MigrationStatus) DeepCopy() *MigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MulticlusterGlobalHub) DeepCopyInto(out *MulticlusterGlobalHub) {
 	*out = *in
@@ -200,12 +492,37 @@ func (in *MulticlusterGlobalHubSpec) DeepCopyInto(out *MulticlusterGlobalHubSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.DataLayer = in.DataLayer
+	in.DataLayer.DeepCopyInto(&out.DataLayer)
 	if in.AdvancedConfig != nil {
 		in, out := &in.AdvancedConfig, &out.AdvancedConfig
 		*out = new(AdvancedConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GrafanaAuth != nil {
+		in, out := &in.GrafanaAuth, &out.GrafanaAuth
+		*out = new(GrafanaAuthConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalGrafana != nil {
+		in, out := &in.ExternalGrafana, &out.ExternalGrafana
+		*out = new(ExternalGrafanaConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = new(ComponentsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduler != nil {
+		in, out := &in.Scheduler, &out.Scheduler
+		*out = new(SchedulerConfig)
+		**out = **in
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubSpec.
@@ -228,6 +545,23 @@ func (in *MulticlusterGlobalHubStatus) DeepCopyInto(out *MulticlusterGlobalHubSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]JobStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisasterRecovery != nil {
+		in, out := &in.DisasterRecovery, &out.DisasterRecovery
+		*out = new(DisasterRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubStatus.
@@ -243,6 +577,11 @@ func (in *MulticlusterGlobalHubStatus) DeepCopy() *MulticlusterGlobalHubStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresConfig) DeepCopyInto(out *PostgresConfig) {
 	*out = *in
+	if in.TrustedCABundles != nil {
+		in, out := &in.TrustedCABundles, &out.TrustedCABundles
+		*out = make([]CABundleReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfig.
@@ -283,3 +622,56 @@ func (in *ResourceRequirements) DeepCopy() *ResourceRequirements {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerConfig) DeepCopyInto(out *SchedulerConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerConfig.
+func (in *SchedulerConfig) DeepCopy() *SchedulerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicAutoscalingConfig) DeepCopyInto(out *TopicAutoscalingConfig) {
+	*out = *in
+	out.SustainedDuration = in.SustainedDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicAutoscalingConfig.
+func (in *TopicAutoscalingConfig) DeepCopy() *TopicAutoscalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicAutoscalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.Migrations != nil {
+		in, out := &in.Migrations, &out.Migrations
+		*out = make([]MigrationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -26,6 +26,7 @@ import (
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	postgresv1beta1 "github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
 	routev1 "github.com/openshift/api/route/v1"
 	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	operatorsv1 "github.com/operator-framework/operator-lifecycle-manager/pkg/package-server/apis/operators/v1"
@@ -38,6 +39,7 @@ import (
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -52,6 +54,7 @@ import (
 	"k8s.io/client-go/rest"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
@@ -65,15 +68,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	hubofhubsaddon "github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/addon"
 	backupcontrollers "github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/backup"
 	hubofhubscontrollers "github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/hubofhubs"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -95,6 +99,7 @@ func init() {
 	utilruntime.Must(routev1.AddToScheme(scheme))
 	utilruntime.Must(operatorsv1.AddToScheme(scheme))
 	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	utilruntime.Must(clusterv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(clusterv1beta1.AddToScheme(scheme))
 	utilruntime.Must(clusterv1beta2.AddToScheme(scheme))
 	utilruntime.Must(workv1.AddToScheme(scheme))
@@ -116,6 +121,9 @@ func init() {
 	// add Kafka scheme
 	utilruntime.Must(kafkav1beta2.AddToScheme(scheme))
 
+	// add crunchy postgres scheme
+	utilruntime.Must(postgresv1beta1.AddToScheme(scheme))
+
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -126,6 +134,7 @@ type operatorConfig struct {
 	LeaderElection        bool
 	GlobalResourceEnabled bool
 	LogLevel              string
+	LogLevelConfigMap     string
 }
 
 func main() {
@@ -164,6 +173,9 @@ func doMain(ctx context.Context, cfg *rest.Config) int {
 		return 1
 	}
 
+	// the significant actions of the operator are recorded as the events of the mgh instance
+	config.SetEventRecorder(mgr.GetEventRecorderFor("multicluster-global-hub-operator"))
+
 	// middlewareCfg is shared between all controllers
 	middlewareCfg := &hubofhubscontrollers.MiddlewareConfig{}
 
@@ -223,6 +235,14 @@ func doMain(ctx context.Context, cfg *rest.Config) int {
 		return 1
 	}
 
+	if operatorConfig.LogLevelConfigMap != "" {
+		err := logger.AddLevelWatcher(mgr, operatorConfig.PodNamespace, operatorConfig.LogLevelConfigMap)
+		if err != nil {
+			setupLog.Error(err, "unable to add the log level watcher to manager")
+			return 1
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		return 1
@@ -259,6 +279,8 @@ func parseFlags() *operatorConfig {
 		"Enable leader election for controller manager. ")
 	pflag.BoolVar(&config.GlobalResourceEnabled, "global-resource-enabled", false,
 		"Enable the global resource. It is expermental feature. Do not support upgrade.")
+	pflag.StringVar(&config.LogLevelConfigMap, "log-level-configmap", "multicluster-global-hub-operator-logging",
+		"The configmap of the log levels in the operator namespace, the levels aren't watched if it's empty.")
 	pflag.Parse()
 
 	config.LogLevel = "info"
@@ -267,7 +289,7 @@ func parseFlags() *operatorConfig {
 	}
 
 	// set zap logger
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctrl.SetLogger(logger.New(&opts))
 	return config
 }
 
@@ -389,10 +411,16 @@ func initCache(config *rest.Config, cacheOpts cache.Options) (cache.Cache, error
 		&promv1.ServiceMonitor{}: {
 			Label: labelSelector,
 		},
+		&networkingv1.NetworkPolicy{}: {
+			Label: labelSelector,
+		},
 		&subv1alpha1.Subscription{}: {},
 		&corev1.PersistentVolumeClaim{}: {
 			Field: fields.OneTermEqualSelector(namespacePath, utils.GetDefaultNamespace()),
 		},
+		&postgresv1beta1.PostgresCluster{}: {
+			Field: fields.OneTermEqualSelector(namespacePath, utils.GetDefaultNamespace()),
+		},
 		&mchv1.MultiClusterHub{}: {},
 		&apiextensionsv1.CustomResourceDefinition{}: {
 			Field: fields.SelectorFromSet(fields.Set{"metadata.name": "kafkas.kafka.strimzi.io"}),
@@ -52,12 +52,56 @@ const (
 	CONDITION_MESSAGE_DATABASE_INIT = "Database has been initialized"
 )
 
+// NOTE: the status of MigrationCompleted can be True or False
+const (
+	CONDITION_TYPE_MIGRATION_COMPLETED   = "MigrationCompleted"
+	CONDITION_REASON_MIGRATION_COMPLETED = "MigrationCompleted"
+	CONDITION_REASON_MIGRATION_FAILED    = "MigrationFailed"
+)
+
+// NOTE: the status of PostgresConverted can be True or False, it's only reported once the built-in postgres is
+// converted between the statefulset and the crunchy postgres cluster
+const (
+	CONDITION_TYPE_POSTGRES_CONVERTED           = "PostgresConverted"
+	CONDITION_REASON_POSTGRES_CONVERTED         = "PostgresConverted"
+	CONDITION_REASON_POSTGRES_CONVERTING        = "PostgresConverting"
+	CONDITION_REASON_POSTGRES_CONVERSION_FAILED = "PostgresConversionFailed"
+)
+
+// NOTE: the status of PostgresWALHealthy can be True or False, it's only reported by the built-in postgres once the
+// WAL is archived or retained by the replication slots
+const (
+	CONDITION_TYPE_POSTGRES_WAL_HEALTHY    = "PostgresWALHealthy"
+	CONDITION_REASON_POSTGRES_WAL_HEALTHY  = "PostgresWALHealthy"
+	CONDITION_REASON_WAL_ARCHIVE_FAILING   = "WALArchiveFailing"
+	CONDITION_REASON_WAL_ARCHIVE_LAGGING   = "WALArchiveLagging"
+	CONDITION_REASON_WAL_RETAINED_BY_SLOTS = "WALRetainedBySlots"
+	CONDITION_MESSAGE_POSTGRES_WAL_HEALTHY = "The WAL is archived and retained by the slots within the threshold"
+)
+
 // NOTE: the status of Data Retention can be True or False
 const (
 	CONDITION_TYPE_RETENTION_PARSED   = "DataRetentionParsed"
 	CONDITION_REASON_RETENTION_PARSED = "DataRetentionParsed"
 )
 
+// NOTE: the status of KafkaTopicsValidated can be True or False, it's only reported in the read-only mode of the BYO
+// kafka
+const (
+	CONDITION_TYPE_KAFKA_TOPICS_VALIDATED    = "KafkaTopicsValidated"
+	CONDITION_REASON_KAFKA_TOPICS_VALIDATED  = "KafkaTopicsValidated"
+	CONDITION_REASON_KAFKA_TOPICS_INVALID    = "KafkaTopicsInvalid"
+	CONDITION_MESSAGE_KAFKA_TOPICS_VALIDATED = "The kafka topics exist with the required configuration"
+)
+
+// NOTE: the status of StandbyReplicating can be True or False, it's only reported by the disaster recovery standby
+const (
+	CONDITION_TYPE_STANDBY_REPLICATING    = "StandbyReplicating"
+	CONDITION_REASON_STANDBY_REPLICATING  = "StandbyReplicating"
+	CONDITION_REASON_STANDBY_FAILED       = "StandbyReplicationFailed"
+	CONDITION_MESSAGE_STANDBY_REPLICATING = "The database is replicated from the primary global hub"
+)
+
 // NOTE: the status of ManagerDeployed can only be True; otherwise there is no condition
 const (
 	CONDITION_TYPE_MANAGER_AVAILABLE    = "ManagerAvailable"
@@ -90,6 +134,14 @@ const (
 	CONDITION_MESSAGE_BACKUP_DISABLED = "Backup Disabled In RHACM"
 )
 
+const (
+	CONDITION_TYPE_IMPORT_LOOP    = "ImportLoopDetected"
+	CONDITION_REASON_IMPORT_LOOP  = "ImportLoopDetected"
+	CONDITION_REASON_NO_LOOP      = "NoImportLoop"
+	CONDITION_MESSAGE_IMPORT_LOOP = "The clusters are skipped as the managed hubs"
+	CONDITION_MESSAGE_NO_LOOP     = "No global hub cluster is imported as a managed hub"
+)
+
 // SetConditionFunc is function type that receives the concrete condition method
 type SetConditionFunc func(ctx context.Context, c client.Client,
 	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
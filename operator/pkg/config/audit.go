@@ -0,0 +1,41 @@
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// the reasons of the events emitted for the significant actions of the operator
+const (
+	ReasonKafkaTopicCreated   = "KafkaTopicCreated"
+	ReasonKafkaTopicDeleted   = "KafkaTopicDeleted"
+	ReasonKafkaTopicScaled    = "KafkaTopicScaled"
+	ReasonKafkaUserCreated    = "KafkaUserCreated"
+	ReasonKafkaUserDeleted    = "KafkaUserDeleted"
+	ReasonKafkaStorageResized = "KafkaStorageResized"
+	ReasonSigningKeyGenerated = "SigningKeyGenerated"
+	ReasonResourcePruned      = "ResourcePruned"
+	ReasonStandbyPromoted     = "StandbyPromoted"
+)
+
+var (
+	eventRecorder record.EventRecorder
+	auditLog      = ctrl.Log.WithName("audit")
+)
+
+func SetEventRecorder(recorder record.EventRecorder) {
+	eventRecorder = recorder
+}
+
+// RecordAction emits a normal Event on the object, e.g. the MulticlusterGlobalHub instance, and writes the audit log
+// entry with the reason and the key values, so the cluster admins are able to reconstruct what the operator did and
+// when by the events or the logs
+func RecordAction(obj runtime.Object, reason, message string, keysAndValues ...interface{}) {
+	auditLog.Info(message, append([]interface{}{"reason", reason}, keysAndValues...)...)
+	if eventRecorder == nil || obj == nil {
+		return
+	}
+	eventRecorder.Event(obj, corev1.EventTypeNormal, reason, message)
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+)
+
+func TestRecordAction(t *testing.T) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterglobalhub", Namespace: "multicluster-global-hub"},
+	}
+
+	// the action is only logged without the recorder
+	SetEventRecorder(nil)
+	RecordAction(mgh, ReasonKafkaTopicCreated, "Created the kafka topic spec", "topic", "spec")
+
+	recorder := record.NewFakeRecorder(1)
+	SetEventRecorder(recorder)
+	defer SetEventRecorder(nil)
+
+	RecordAction(mgh, ReasonKafkaTopicCreated, "Created the kafka topic spec", "topic", "spec")
+	select {
+	case event := <-recorder.Events:
+		if want := "Normal KafkaTopicCreated Created the kafka topic spec"; event != want {
+			t.Errorf("got the event %q, want %q", event, want)
+		}
+	default:
+		t.Error("the event isn't recorded")
+	}
+}
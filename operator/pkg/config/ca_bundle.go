@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+)
+
+// DefaultCABundleKey is the key of the trusted CA bundle injected into the configmap by the OpenShift
+const DefaultCABundleKey = "ca-bundle.crt"
+
+var (
+	// the configmaps referred by the trusted CA bundles of the mgh, the operator reconciles once they're changed
+	trustedCABundleConfigMaps     = sets.NewString()
+	trustedCABundleConfigMapsLock sync.RWMutex
+)
+
+// IsTrustedCABundleConfigMap returns true if the configmap is referred by the trusted CA bundles of the mgh
+func IsTrustedCABundleConfigMap(name string) bool {
+	trustedCABundleConfigMapsLock.RLock()
+	defer trustedCABundleConfigMapsLock.RUnlock()
+	return trustedCABundleConfigMaps.Has(name)
+}
+
+// SetTrustedCABundleConfigMaps records the configmaps referred by the kafka and postgres trusted CA bundles of the mgh
+func SetTrustedCABundleConfigMaps(mgh *globalhubv1alpha4.MulticlusterGlobalHub) {
+	names := sets.NewString()
+	for _, ref := range mgh.Spec.DataLayer.Kafka.TrustedCABundles {
+		names.Insert(ref.Name)
+	}
+	for _, ref := range mgh.Spec.DataLayer.Postgres.TrustedCABundles {
+		names.Insert(ref.Name)
+	}
+	trustedCABundleConfigMapsLock.Lock()
+	defer trustedCABundleConfigMapsLock.Unlock()
+	trustedCABundleConfigMaps = names
+}
+
+// GetTrustedCABundle returns the CA bundles of the references in the namespace concatenated in order, every bundle
+// must contain the PEM encoded certificates
+func GetTrustedCABundle(ctx context.Context, c client.Client, namespace string,
+	refs []globalhubv1alpha4.CABundleReference,
+) ([]byte, error) {
+	bundles := [][]byte{}
+	for _, ref := range refs {
+		key := ref.Key
+		if key == "" {
+			key = DefaultCABundleKey
+		}
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+			return nil, fmt.Errorf("failed to get the trusted CA bundle configmap %s: %w", ref.Name, err)
+		}
+		bundle := []byte(configMap.Data[key])
+		if _, err := certutil.ParseCertsPEM(bundle); err != nil {
+			return nil, fmt.Errorf("the %s of the configmap %s isn't a valid CA bundle: %w", key, ref.Name, err)
+		}
+		bundles = append(bundles, bundle)
+	}
+	return MergeCABundles(bundles...), nil
+}
+
+// MergeCABundles joins the PEM encoded CA bundles, the empty ones are skipped
+func MergeCABundles(bundles ...[]byte) []byte {
+	merged := [][]byte{}
+	for _, bundle := range bundles {
+		bundle = bytes.TrimSpace(bundle)
+		if len(bundle) > 0 {
+			merged = append(merged, bundle)
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return append(bytes.Join(merged, []byte("\n")), '\n')
+}
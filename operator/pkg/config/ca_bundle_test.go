@@ -0,0 +1,73 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+)
+
+func TestGetTrustedCABundle(t *testing.T) {
+	enterpriseCA, _, err := certutil.GenerateSelfSignedCertKey("enterprise-ca", nil, nil)
+	require.NoError(t, err)
+	rootCA, _, err := certutil.GenerateSelfSignedCertKey("root-ca", nil, nil)
+	require.NoError(t, err)
+
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "trusted-ca", Namespace: "multicluster-global-hub"},
+			Data:       map[string]string{DefaultCABundleKey: string(enterpriseCA)},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "root-ca", Namespace: "multicluster-global-hub"},
+			Data:       map[string]string{"root.pem": string(rootCA), "invalid.pem": "not a certificate"},
+		},
+	).Build()
+
+	bundle, err := GetTrustedCABundle(context.Background(), c, "multicluster-global-hub",
+		[]globalhubv1alpha4.CABundleReference{{Name: "trusted-ca"}, {Name: "root-ca", Key: "root.pem"}})
+	require.NoError(t, err)
+	certs, err := certutil.ParseCertsPEM(bundle)
+	require.NoError(t, err)
+	// every generated certificate is followed by its CA
+	require.Len(t, certs, 4)
+	assert.True(t, strings.HasPrefix(certs[0].Subject.CommonName, "enterprise-ca@"))
+	assert.True(t, strings.HasPrefix(certs[2].Subject.CommonName, "root-ca@"))
+
+	bundle, err = GetTrustedCABundle(context.Background(), c, "multicluster-global-hub", nil)
+	require.NoError(t, err)
+	assert.Empty(t, bundle)
+
+	_, err = GetTrustedCABundle(context.Background(), c, "multicluster-global-hub",
+		[]globalhubv1alpha4.CABundleReference{{Name: "root-ca", Key: "invalid.pem"}})
+	assert.ErrorContains(t, err, "isn't a valid CA bundle")
+
+	_, err = GetTrustedCABundle(context.Background(), c, "multicluster-global-hub",
+		[]globalhubv1alpha4.CABundleReference{{Name: "missing"}})
+	assert.ErrorContains(t, err, "failed to get the trusted CA bundle configmap missing")
+}
+
+func TestMergeCABundles(t *testing.T) {
+	assert.Nil(t, MergeCABundles(nil, []byte("  \n")))
+	assert.Equal(t, "a\nb\n", string(MergeCABundles([]byte("a\n"), nil, []byte("b"))))
+}
+
+func TestTrustedCABundleConfigMaps(t *testing.T) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+	mgh.Spec.DataLayer.Kafka.TrustedCABundles = []globalhubv1alpha4.CABundleReference{{Name: "kafka-ca"}}
+	mgh.Spec.DataLayer.Postgres.TrustedCABundles = []globalhubv1alpha4.CABundleReference{{Name: "postgres-ca"}}
+	SetTrustedCABundleConfigMaps(mgh)
+	defer SetTrustedCABundleConfigMaps(&globalhubv1alpha4.MulticlusterGlobalHub{})
+
+	assert.True(t, IsTrustedCABundleConfigMap("kafka-ca"))
+	assert.True(t, IsTrustedCABundleConfigMap("postgres-ca"))
+	assert.False(t, IsTrustedCABundleConfigMap("other"))
+}
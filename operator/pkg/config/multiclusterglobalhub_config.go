@@ -22,6 +22,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -57,12 +58,19 @@ const (
 	OauthProxyImageKey           = "oauth_proxy"
 	GrafanaImageKey              = "grafana"
 	PostgresImageKey             = "postgresql"
+	ConsolePluginImageKey        = "multicluster_global_hub_console_plugin"
 	GHPostgresDefaultStorageSize = "25Gi"
 	// default values for the global hub configured by the operator
 	// We may expose these as CRD fields in the future
-	AggregationLevel       = "full"
-	EnableLocalPolicies    = "true"
-	AgentHeartbeatInterval = "60s"
+	AggregationLevel                = "full"
+	EnableLocalPolicies             = "true"
+	AgentHeartbeatInterval          = "60s"
+	AgentManagedClusterSyncInterval = "5s"
+	AgentPolicySyncInterval         = "5s"
+	AgentComplianceSyncInterval     = "5s"
+	AgentEventSyncInterval          = "5s"
+	AgentHubClusterInfoSyncInterval = "60s"
+	AgentStatusBufferSizeMB         = 100
 )
 
 var (
@@ -75,6 +83,7 @@ var (
 		OauthProxyImageKey:       "quay.io/stolostron/origin-oauth-proxy:4.9",
 		GrafanaImageKey:          "quay.io/stolostron/grafana:globalhub-1.2",
 		PostgresImageKey:         "quay.io/stolostron/postgresql-13:1-101",
+		ConsolePluginImageKey:    "quay.io/stolostron/multicluster-global-hub-console-plugin:latest",
 	}
 	statisticLogInterval  = "1m"
 	metricsScrapeInterval = "1m"
@@ -232,6 +241,136 @@ func GetMetricsScrapeInterval(mgh *globalhubv1alpha4.MulticlusterGlobalHub) stri
 	return interval
 }
 
+// GetManagerShards returns the number of manager replicas which partition the managed hubs, 0 means the hubs aren't
+// sharded and the status is processed by the leader replica only
+func GetManagerShards(mgh *globalhubv1alpha4.MulticlusterGlobalHub) int32 {
+	shards, err := strconv.ParseInt(getAnnotation(mgh, operatorconstants.AnnotationManagerShards), 10, 32)
+	if err != nil || shards < 2 {
+		return 0
+	}
+	return int32(shards)
+}
+
+// GetAgentReplicas returns the number of agent replicas on each managed hub, only the leader replica reports the status
+// and the others are the standby, it's 1 if the annotation isn't a positive number
+func GetAgentReplicas(mgh *globalhubv1alpha4.MulticlusterGlobalHub) int32 {
+	replicas, err := strconv.ParseInt(getAnnotation(mgh, operatorconstants.AnnotationAgentReplicas), 10, 32)
+	if err != nil || replicas < 1 {
+		return 1
+	}
+	return int32(replicas)
+}
+
+// IsMessageSigningEnabled returns true if the status events of the agents are signed and verified by the manager
+func IsMessageSigningEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	return getAnnotation(mgh, operatorconstants.AnnotationMessageSigning) == "true"
+}
+
+// IsAgentQuarantineEnabled returns true if the manager doesn't persist the status events of the incompatible agents
+func IsAgentQuarantineEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	return getAnnotation(mgh, operatorconstants.AnnotationAgentQuarantine) == "true"
+}
+
+// IsSpecDriftRemediationEnabled returns true if the manager resends the drifted global resources to the managed hubs
+func IsSpecDriftRemediationEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	return getAnnotation(mgh, operatorconstants.AnnotationSpecDriftRemediation) == "true"
+}
+
+// IsHubMetricsEnabled returns true if the agents report the key metrics of the managed hubs to the global hub
+func IsHubMetricsEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	return getAnnotation(mgh, operatorconstants.AnnotationHubMetrics) == "true"
+}
+
+// IsChangeDataCaptureEnabled returns true if the changes of the global hub database are streamed into the kafka
+func IsChangeDataCaptureEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	return getAnnotation(mgh, operatorconstants.AnnotationChangeDataCapture) == "true"
+}
+
+// GetHubInactiveTimeout returns the heartbeat silence window of the managed hub, it's empty if the annotation isn't a
+// positive duration, then the default timeout of the manager is used
+func GetHubInactiveTimeout(mgh *globalhubv1alpha4.MulticlusterGlobalHub) string {
+	timeout, err := time.ParseDuration(getAnnotation(mgh, operatorconstants.AnnotationHubInactiveTimeout))
+	if err != nil || timeout <= 0 {
+		return ""
+	}
+	return timeout.String()
+}
+
+// GetPodSecurityProfile returns the security profile of the global hub components, it's restricted unless the custom
+// profile is specified
+func GetPodSecurityProfile(mgh *globalhubv1alpha4.MulticlusterGlobalHub) globalhubv1alpha4.PodSecurityProfile {
+	if mgh.Spec.PodSecurityProfile == globalhubv1alpha4.PodSecurityCustom {
+		return globalhubv1alpha4.PodSecurityCustom
+	}
+	return globalhubv1alpha4.PodSecurityRestricted
+}
+
+// GetTopicAutoscaling returns the autoscaling of the kafka topic partitions with the defaults of the unset fields, it's
+// nil if the autoscaling isn't enabled
+func GetTopicAutoscaling(mgh *globalhubv1alpha4.MulticlusterGlobalHub) *globalhubv1alpha4.TopicAutoscalingConfig {
+	if mgh.Spec.DataLayer.Kafka.TopicAutoscaling == nil {
+		return nil
+	}
+	autoscaling := mgh.Spec.DataLayer.Kafka.TopicAutoscaling.DeepCopy()
+	if autoscaling.MaxPartitions <= 0 {
+		autoscaling.MaxPartitions = 12
+	}
+	if autoscaling.LagThreshold <= 0 {
+		autoscaling.LagThreshold = 1000
+	}
+	if autoscaling.ThroughputThreshold <= 0 {
+		autoscaling.ThroughputThreshold = 100
+	}
+	if autoscaling.SustainedDuration.Duration <= 0 {
+		autoscaling.SustainedDuration.Duration = 10 * time.Minute
+	}
+	return autoscaling
+}
+
+// GetDataCollectionProfile returns the profile deciding the status bundles collected by the agents, it's full unless
+// the other profile is specified
+func GetDataCollectionProfile(mgh *globalhubv1alpha4.MulticlusterGlobalHub) globalhubv1alpha4.DataCollectionProfile {
+	switch mgh.Spec.DataCollectionProfile {
+	case globalhubv1alpha4.DataCollectionMinimal, globalhubv1alpha4.DataCollectionStandard:
+		return mgh.Spec.DataCollectionProfile
+	}
+	return globalhubv1alpha4.DataCollectionFull
+}
+
+// IsGrafanaEnabled returns true unless the grafana component is disabled, the built-in grafana isn't deployed and the
+// dashboards aren't provisioned into the external grafana if it's disabled
+func IsGrafanaEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	components := mgh.Spec.Components
+	if components == nil || components.Grafana == nil || components.Grafana.Enabled == nil {
+		return true
+	}
+	return *components.Grafana.Enabled
+}
+
+// IsConsolePluginEnabled returns true if the console plugin of the fleet summary pages is enabled, it's disabled by
+// default
+func IsConsolePluginEnabled(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	components := mgh.Spec.Components
+	return components != nil && components.ConsolePlugin != nil && components.ConsolePlugin.Enabled
+}
+
+// GetDisasterRecoveryRole returns the role of the global hub in the disaster recovery, it's empty if the disaster
+// recovery isn't configured, and it's primary unless the standby is specified
+func GetDisasterRecoveryRole(mgh *globalhubv1alpha4.MulticlusterGlobalHub) globalhubv1alpha4.DisasterRecoveryRole {
+	if mgh.Spec.DisasterRecovery == nil {
+		return ""
+	}
+	if mgh.Spec.DisasterRecovery.Role == globalhubv1alpha4.DisasterRecoveryStandby {
+		return globalhubv1alpha4.DisasterRecoveryStandby
+	}
+	return globalhubv1alpha4.DisasterRecoveryPrimary
+}
+
+// IsStandby returns true if the global hub is the warm standby, which doesn't run the manager until it's promoted
+func IsStandby(mgh *globalhubv1alpha4.MulticlusterGlobalHub) bool {
+	return GetDisasterRecoveryRole(mgh) == globalhubv1alpha4.DisasterRecoveryStandby
+}
+
 func GetPostgresStorageSize(mgh *globalhubv1alpha4.MulticlusterGlobalHub) string {
 	if mgh.Spec.DataLayer.Postgres.StorageSize != "" {
 		return mgh.Spec.DataLayer.Postgres.StorageSize
@@ -19,6 +19,7 @@ package config
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -124,3 +125,316 @@ func TestGetOauthSessionSecret(t *testing.T) {
 		t.Errorf("oauth session secret is not consistent")
 	}
 }
+
+func TestGetManagerShards(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       int32
+	}{
+		{name: "not set", annotation: "", want: 0},
+		{name: "invalid", annotation: "three", want: 0},
+		{name: "single replica", annotation: "1", want: 0},
+		{name: "multiple replicas", annotation: "3", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{operatorconstants.AnnotationManagerShards: tt.annotation},
+				},
+			}
+			if got := GetManagerShards(mgh); got != tt.want {
+				t.Errorf("GetManagerShards() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAgentReplicas(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       int32
+	}{
+		{name: "not set", annotation: "", want: 1},
+		{name: "invalid", annotation: "two", want: 1},
+		{name: "zero", annotation: "0", want: 1},
+		{name: "multiple replicas", annotation: "2", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{operatorconstants.AnnotationAgentReplicas: tt.annotation},
+				},
+			}
+			if got := GetAgentReplicas(mgh); got != tt.want {
+				t.Errorf("GetAgentReplicas() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHubInactiveTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       string
+	}{
+		{name: "not set", annotation: "", want: ""},
+		{name: "invalid", annotation: "ten minutes", want: ""},
+		{name: "negative", annotation: "-5m", want: ""},
+		{name: "valid", annotation: "10m", want: "10m0s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{operatorconstants.AnnotationHubInactiveTimeout: tt.annotation},
+				},
+			}
+			if got := GetHubInactiveTimeout(mgh); got != tt.want {
+				t.Errorf("GetHubInactiveTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMessageSigningEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       bool
+	}{
+		{name: "not set", annotation: "", want: false},
+		{name: "disabled", annotation: "false", want: false},
+		{name: "enabled", annotation: "true", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{operatorconstants.AnnotationMessageSigning: tt.annotation},
+				},
+			}
+			if got := IsMessageSigningEnabled(mgh); got != tt.want {
+				t.Errorf("IsMessageSigningEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHubMetricsEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       bool
+	}{
+		{name: "not set", annotation: "", want: false},
+		{name: "disabled", annotation: "false", want: false},
+		{name: "enabled", annotation: "true", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{operatorconstants.AnnotationHubMetrics: tt.annotation},
+				},
+			}
+			if got := IsHubMetricsEnabled(mgh); got != tt.want {
+				t.Errorf("IsHubMetricsEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsChangeDataCaptureEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		want       bool
+	}{
+		{name: "not set", annotation: "", want: false},
+		{name: "disabled", annotation: "false", want: false},
+		{name: "enabled", annotation: "true", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{operatorconstants.AnnotationChangeDataCapture: tt.annotation},
+				},
+			}
+			if got := IsChangeDataCaptureEnabled(mgh); got != tt.want {
+				t.Errorf("IsChangeDataCaptureEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPodSecurityProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile globalhubv1alpha4.PodSecurityProfile
+		want    globalhubv1alpha4.PodSecurityProfile
+	}{
+		{name: "not set", profile: "", want: globalhubv1alpha4.PodSecurityRestricted},
+		{name: "restricted", profile: globalhubv1alpha4.PodSecurityRestricted, want: globalhubv1alpha4.PodSecurityRestricted},
+		{name: "custom", profile: globalhubv1alpha4.PodSecurityCustom, want: globalhubv1alpha4.PodSecurityCustom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{PodSecurityProfile: tt.profile},
+			}
+			if got := GetPodSecurityProfile(mgh); got != tt.want {
+				t.Errorf("GetPodSecurityProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTopicAutoscaling(t *testing.T) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+	if got := GetTopicAutoscaling(mgh); got != nil {
+		t.Errorf("GetTopicAutoscaling() = %v, want nil", got)
+	}
+
+	mgh.Spec.DataLayer.Kafka.TopicAutoscaling = &globalhubv1alpha4.TopicAutoscalingConfig{MaxPartitions: 4}
+	got := GetTopicAutoscaling(mgh)
+	if got.MaxPartitions != 4 || got.LagThreshold != 1000 || got.ThroughputThreshold != 100 ||
+		got.SustainedDuration.Duration != 10*time.Minute {
+		t.Errorf("GetTopicAutoscaling() = %v, want the defaults besides the max partitions", got)
+	}
+	if mgh.Spec.DataLayer.Kafka.TopicAutoscaling.LagThreshold != 0 {
+		t.Errorf("GetTopicAutoscaling() changes the spec")
+	}
+}
+
+func TestGetDataCollectionProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile globalhubv1alpha4.DataCollectionProfile
+		want    globalhubv1alpha4.DataCollectionProfile
+	}{
+		{name: "not set", profile: "", want: globalhubv1alpha4.DataCollectionFull},
+		{name: "minimal", profile: globalhubv1alpha4.DataCollectionMinimal, want: globalhubv1alpha4.DataCollectionMinimal},
+		{name: "standard", profile: globalhubv1alpha4.DataCollectionStandard, want: globalhubv1alpha4.DataCollectionStandard},
+		{name: "full", profile: globalhubv1alpha4.DataCollectionFull, want: globalhubv1alpha4.DataCollectionFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{DataCollectionProfile: tt.profile},
+			}
+			if got := GetDataCollectionProfile(mgh); got != tt.want {
+				t.Errorf("GetDataCollectionProfile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGrafanaEnabled(t *testing.T) {
+	disabled, enabled := false, true
+	tests := []struct {
+		name       string
+		components *globalhubv1alpha4.ComponentsConfig
+		want       bool
+	}{
+		{name: "not set", components: nil, want: true},
+		{name: "grafana not set", components: &globalhubv1alpha4.ComponentsConfig{}, want: true},
+		{
+			name: "enabled",
+			components: &globalhubv1alpha4.ComponentsConfig{
+				Grafana: &globalhubv1alpha4.ComponentConfig{Enabled: &enabled},
+			},
+			want: true,
+		},
+		{
+			name: "disabled",
+			components: &globalhubv1alpha4.ComponentsConfig{
+				Grafana: &globalhubv1alpha4.ComponentConfig{Enabled: &disabled},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{Components: tt.components},
+			}
+			if got := IsGrafanaEnabled(mgh); got != tt.want {
+				t.Errorf("IsGrafanaEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConsolePluginEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		components *globalhubv1alpha4.ComponentsConfig
+		want       bool
+	}{
+		{name: "not set", components: nil, want: false},
+		{name: "console plugin not set", components: &globalhubv1alpha4.ComponentsConfig{}, want: false},
+		{
+			name: "enabled",
+			components: &globalhubv1alpha4.ComponentsConfig{
+				ConsolePlugin: &globalhubv1alpha4.ConsolePluginConfig{Enabled: true},
+			},
+			want: true,
+		},
+		{
+			name: "disabled",
+			components: &globalhubv1alpha4.ComponentsConfig{
+				ConsolePlugin: &globalhubv1alpha4.ConsolePluginConfig{ServingCertSecret: "cert"},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{Components: tt.components},
+			}
+			if got := IsConsolePluginEnabled(mgh); got != tt.want {
+				t.Errorf("IsConsolePluginEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDisasterRecoveryRole(t *testing.T) {
+	tests := []struct {
+		name             string
+		disasterRecovery *globalhubv1alpha4.DisasterRecoveryConfig
+		want             globalhubv1alpha4.DisasterRecoveryRole
+	}{
+		{name: "not set", disasterRecovery: nil, want: ""},
+		{
+			name:             "role not set",
+			disasterRecovery: &globalhubv1alpha4.DisasterRecoveryConfig{},
+			want:             globalhubv1alpha4.DisasterRecoveryPrimary,
+		},
+		{
+			name:             "standby",
+			disasterRecovery: &globalhubv1alpha4.DisasterRecoveryConfig{Role: globalhubv1alpha4.DisasterRecoveryStandby},
+			want:             globalhubv1alpha4.DisasterRecoveryStandby,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{DisasterRecovery: tt.disasterRecovery},
+			}
+			if got := GetDisasterRecoveryRole(mgh); got != tt.want {
+				t.Errorf("GetDisasterRecoveryRole() = %v, want %v", got, tt.want)
+			}
+			if got := IsStandby(mgh); got != (tt.want == globalhubv1alpha4.DisasterRecoveryStandby) {
+				t.Errorf("IsStandby() = %v", got)
+			}
+		})
+	}
+}
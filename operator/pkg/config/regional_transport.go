@@ -0,0 +1,64 @@
+package config
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var (
+	regionalTransportersLock sync.RWMutex
+	// regionalTransporters are the kafka clusters of the regions, which are bridged to the global hub
+	regionalTransporters = map[string]transport.Transporter{}
+)
+
+// SetRegionalTransporters replaces the transporters of the regional kafka clusters, keyed by the region
+func SetRegionalTransporters(transporters map[string]transport.Transporter) {
+	regionalTransportersLock.Lock()
+	defer regionalTransportersLock.Unlock()
+	regionalTransporters = map[string]transport.Transporter{}
+	for region, trans := range transporters {
+		regionalTransporters[region] = trans
+	}
+}
+
+// GetRegionalTransporters returns the transporters of the regional kafka clusters, keyed by the region
+func GetRegionalTransporters() map[string]transport.Transporter {
+	regionalTransportersLock.RLock()
+	defer regionalTransportersLock.RUnlock()
+	transporters := make(map[string]transport.Transporter, len(regionalTransporters))
+	for region, trans := range regionalTransporters {
+		transporters[region] = trans
+	}
+	return transporters
+}
+
+// GetClusterRegion returns the region of the managed hub if the region has its own kafka cluster, otherwise it's
+// empty, which means the hub connects to the default kafka cluster
+func GetClusterRegion(cluster client.Object) string {
+	region := cluster.GetLabels()[operatorconstants.GHRegionLabelKey]
+	if region == "" {
+		return ""
+	}
+	regionalTransportersLock.RLock()
+	defer regionalTransportersLock.RUnlock()
+	if _, found := regionalTransporters[region]; !found {
+		return ""
+	}
+	return region
+}
+
+// GetClusterTransporter returns the transporter of the kafka cluster the managed hub connects to, it's the one of the
+// region of the hub, or the default transporter if the hub isn't in any region with its own kafka cluster
+func GetClusterTransporter(cluster client.Object) transport.Transporter {
+	region := GetClusterRegion(cluster)
+	if region == "" {
+		return GetTransporter()
+	}
+	regionalTransportersLock.RLock()
+	defer regionalTransportersLock.RUnlock()
+	return regionalTransporters[region]
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type fakeTransporter struct {
+	transport.Transporter
+	name string
+}
+
+func TestGetClusterTransporter(t *testing.T) {
+	defaultTransporter := &fakeTransporter{name: "default"}
+	SetTransporter(defaultTransporter)
+	defer SetTransporter(nil)
+	eastTransporter := &fakeTransporter{name: "east"}
+	SetRegionalTransporters(map[string]transport.Transporter{"east": eastTransporter})
+	defer SetRegionalTransporters(nil)
+
+	clusterIn := func(region string) *clusterv1.ManagedCluster {
+		cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}}
+		if region != "" {
+			cluster.Labels = map[string]string{operatorconstants.GHRegionLabelKey: region}
+		}
+		return cluster
+	}
+
+	assert.Equal(t, "east", GetClusterRegion(clusterIn("east")))
+	assert.Equal(t, eastTransporter, GetClusterTransporter(clusterIn("east")))
+	// the hubs without the region or in the region without the kafka cluster connect to the default one
+	assert.Equal(t, "", GetClusterRegion(clusterIn("west")))
+	assert.Equal(t, defaultTransporter, GetClusterTransporter(clusterIn("west")))
+	assert.Equal(t, defaultTransporter, GetClusterTransporter(clusterIn("")))
+}
@@ -58,6 +58,30 @@ const (
 	AnnotationStatisticInterval = "mgh-statistic-interval"
 	// AnnotationMetricsScrapeInterval to set the scrape interval for metrics
 	AnnotationMetricsScrapeInterval = "mgh-metrics-scrape-interval"
+	// AnnotationManagerShards runs the number of manager replicas which partition the managed hubs among them,
+	// it only takes effect when the status topic per hub is enabled
+	AnnotationManagerShards = "mgh-manager-shards"
+	// AnnotationHubInactiveTimeout is the duration(e.g. 10m) without the heartbeat after which the manager marks the
+	// managed hub as inactive
+	AnnotationHubInactiveTimeout = "mgh-hub-inactive-timeout"
+	// AnnotationAgentReplicas runs the number of agent replicas on each managed hub, the standby replicas take over
+	// the status reporting once the leader is lost, e.g. by the node failure
+	AnnotationAgentReplicas = "mgh-agent-replicas"
+	// AnnotationMessageSigning signs the status events of the agents with the keys of the managed hubs, and the
+	// manager rejects the events which aren't signed by the managed hubs
+	AnnotationMessageSigning = "mgh-message-signing"
+	// AnnotationHubMetrics reports the key metrics, e.g. the policy evaluation latency, from the prometheus of the
+	// managed hubs, so they're aggregated on the global hub
+	AnnotationHubMetrics = "mgh-hub-metrics"
+	// AnnotationChangeDataCapture streams the changes of the global hub database into the kafka topics by the
+	// debezium connector, it's only supported with the built-in kafka
+	AnnotationChangeDataCapture = "mgh-change-data-capture"
+	// AnnotationAgentQuarantine quarantines the agents which are incompatible with the manager, the status events of
+	// them aren't persisted except the heartbeat
+	AnnotationAgentQuarantine = "mgh-quarantine-incompatible-agents"
+	// AnnotationSpecDriftRemediation resends the global resources to the managed hubs once they drift from the spec
+	// of the global hub, e.g. they're missing or failed to be applied on the managed hubs
+	AnnotationSpecDriftRemediation = "mgh-spec-drift-remediation"
 	// AnnotationONMulticlusterHub indicates the addons are running on a hub cluster
 	AnnotationONMulticlusterHub = "addon.open-cluster-management.io/on-multicluster-hub"
 	// AnnotationPolicyONMulticlusterHub indicates the policy spec sync is running on a hub cluster
@@ -85,6 +109,10 @@ const (
 const (
 	GHManagerDeploymentName = "multicluster-global-hub-manager"
 	GHGrafanaDeploymentName = "multicluster-global-hub-grafana"
+
+	// GHGrafanaDashboardLabelKey is to indicate the configmap in the global hub namespace contains the custom
+	// grafana dashboard, it's mounted into the global hub grafana.
+	GHGrafanaDashboardLabelKey = "global-hub.open-cluster-management.io/dashboard"
 )
 
 const (
@@ -109,6 +137,13 @@ const (
 
 	// GHAgentInstallACMHubLabelKey is to indicate whether to install ACM hub on the agent
 	GHAgentACMHubInstallLabelKey = "global-hub.open-cluster-management.io/hub-cluster-install"
+
+	// GHRegionLabelKey is to indicate the region of the managed hub, the agent connects to the kafka cluster of the
+	// region if its transport secret exists, otherwise it connects to the default kafka cluster
+	GHRegionLabelKey = "global-hub.open-cluster-management.io/region"
+	// GHTransportRegionLabelKey is to indicate the transport secret in the global hub namespace is the kafka cluster
+	// of the region
+	GHTransportRegionLabelKey = "global-hub.open-cluster-management.io/transport-region"
 )
 
 // AggregationLevel specifies the level of aggregation leaf hubs should do before sending the information
@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	mchv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -84,6 +85,7 @@ func (a *HoHAddonController) Start(ctx context.Context) error {
 	utilruntime.Must(globalhubv1alpha4.AddToScheme(addonScheme))
 	utilruntime.Must(operatorsv1.AddToScheme(addonScheme))
 	utilruntime.Must(operatorsv1alpha1.AddToScheme(addonScheme))
+	utilruntime.Must(promv1.AddToScheme(addonScheme))
 
 	kubeClient, err := kubernetes.NewForConfig(a.kubeConfig)
 	if err != nil {
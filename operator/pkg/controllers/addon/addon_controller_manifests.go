@@ -70,6 +70,34 @@ type ManifestsConfig struct {
 	AgentQPS               float32
 	AgentBurst             int
 	LogLevel               string
+	// the sync intervals of the agent, they can be overridden for a managed hub by the values annotation of the addon
+	// or the customized variables of the AddOnDeploymentConfig
+	ManagedClusterSyncInterval string
+	PolicySyncInterval         string
+	ComplianceSyncInterval     string
+	EventSyncInterval          string
+	HubClusterInfoSyncInterval string
+	HeartbeatSyncInterval      string
+	// the label selectors of the resources reported to the global hub, the empty selector reports all the resources
+	ManagedClusterSelector string
+	PolicySelector         string
+	NamespaceSelector      string
+	// the status events are buffered in the emptyDir during the transport outage, or in the claim if it's specified
+	StatusBufferSizeMB    int
+	StatusBufferClaimName string
+	// the metrics of the agent are scraped by the ServiceMonitor if the metrics are enabled for the global hub
+	EnableMetrics         bool
+	MetricsScrapeInterval string
+	// the agent runs with the standby replicas if the replicas are more than 1, they're spread across the nodes, and
+	// the status buffer isn't kept in the claim which can't be mounted by the replicas on the different nodes
+	AgentReplicas int32
+	EnableAgentHA bool
+	// the base64 encoded private key to sign the status events, the events aren't signed if it's empty
+	SigningKey string
+	// the agent reports the key metrics queried from the prometheus of the managed hub
+	EnableHubMetrics bool
+	// the agent serves the pprof, expvar and the diagnostics bundle under /debug of the metrics endpoint
+	EnableDiagnostics bool
 	// cannot use *corev1.ResourceRequirements, addonfactory.StructToValues removes the real value
 	Resources *Resources
 }
@@ -189,7 +217,8 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 	if err != nil {
 		log.Error(err, "failed to wait transporter")
 	}
-	transporter := config.GetTransporter()
+	// the agent of the hub in the region connects to the regional kafka cluster
+	transporter := config.GetClusterTransporter(cluster)
 
 	// will block until the credential is ready
 	kafkaConnection, err := transporter.GetConnCredential(transporter.GenerateUserName(cluster.Name))
@@ -213,7 +242,7 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 		HoHAgentImage:          image,
 		ImagePullPolicy:        string(imagePullPolicy),
 		LeafHubID:              cluster.Name,
-		KafkaBootstrapServer:   kafkaConnection.BootstrapServer,
+		KafkaBootstrapServer:   base64.StdEncoding.EncodeToString([]byte(kafkaConnection.BootstrapServer)),
 		KafkaCACert:            kafkaConnection.CACert,
 		KafkaClientCert:        kafkaConnection.ClientCert,
 		KafkaClientKey:         kafkaConnection.ClientKey,
@@ -242,6 +271,24 @@ func (a *HohAgentAddon) GetValues(cluster *clusterv1.ManagedCluster,
 
 	manifestsConfig.AggregationLevel = config.AggregationLevel
 	manifestsConfig.EnableLocalPolicies = config.EnableLocalPolicies
+	manifestsConfig.ManagedClusterSyncInterval = config.AgentManagedClusterSyncInterval
+	manifestsConfig.PolicySyncInterval = config.AgentPolicySyncInterval
+	manifestsConfig.ComplianceSyncInterval = config.AgentComplianceSyncInterval
+	manifestsConfig.EventSyncInterval = config.AgentEventSyncInterval
+	manifestsConfig.HubClusterInfoSyncInterval = config.AgentHubClusterInfoSyncInterval
+	manifestsConfig.HeartbeatSyncInterval = config.AgentHeartbeatInterval
+	manifestsConfig.StatusBufferSizeMB = config.AgentStatusBufferSizeMB
+	manifestsConfig.EnableMetrics = mgh.Spec.EnableMetrics
+	manifestsConfig.MetricsScrapeInterval = config.GetMetricsScrapeInterval(mgh)
+	manifestsConfig.AgentReplicas = config.GetAgentReplicas(mgh)
+	manifestsConfig.EnableAgentHA = manifestsConfig.AgentReplicas > 1
+	manifestsConfig.EnableHubMetrics = config.IsHubMetricsEnabled(mgh)
+	manifestsConfig.EnableDiagnostics = mgh.Spec.EnableDiagnostics
+	if config.IsMessageSigningEnabled(mgh) {
+		if manifestsConfig.SigningKey, err = a.ensureSigningKey(mgh, cluster.Name); err != nil {
+			return nil, err
+		}
+	}
 
 	if a.installACMHub(cluster) {
 		manifestsConfig.InstallACMHub = true
@@ -73,7 +73,10 @@ func (r *HoHAddonInstaller) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	err = r.Get(ctx, client.ObjectKeyFromObject(cluster), cluster)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return ctrl.Result{}, nil
+			// the cluster is deleted without passing through the deleting phase above(e.g. the operator is down),
+			// release its transport resources here, the data in the database is cleaned up by the manager
+			config.DeleteManagedCluster(cluster.Name)
+			return ctrl.Result{}, r.removeResources(ctx, cluster)
 		}
 		return ctrl.Result{}, err
 	}
@@ -90,6 +93,17 @@ func (r *HoHAddonInstaller) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, nil
 	}
 
+	// the agent on the global hub cluster, or on the cluster running another global hub, sends the events back to
+	// the global hub which are synced to the cluster again, so it's handled like the cluster without the agent
+	if reason := utils.ImportLoopReason(cluster, string(mgh.GetUID())); reason != "" {
+		r.Log.Info("deleting resources and addon to avoid the import loop", "cluster", cluster.Name, "reason", reason)
+		if err := r.removeResourcesAndAddon(ctx, cluster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to remove resources and addon %s: %v", cluster.Name, err)
+		}
+		config.DeleteManagedCluster(cluster.Name)
+		return ctrl.Result{}, nil
+	}
+
 	return ctrl.Result{}, r.reconclieAddonAndResources(ctx, cluster)
 }
 
@@ -141,7 +155,7 @@ func (r *HoHAddonInstaller) reconclieAddonAndResources(ctx context.Context, clus
 }
 
 func (r *HoHAddonInstaller) updateKafkaResource(cluster *clusterv1.ManagedCluster) error {
-	transporter := config.GetTransporter()
+	transporter := config.GetClusterTransporter(cluster)
 	clusterUser := transporter.GenerateUserName(cluster.Name)
 	clusterTopic := transporter.GenerateClusterTopic(cluster.Name)
 	// create the resources
@@ -198,7 +212,7 @@ func (r *HoHAddonInstaller) removeResourcesAndAddon(ctx context.Context, cluster
 }
 
 func (r *HoHAddonInstaller) removeResources(ctx context.Context, cluster *clusterv1.ManagedCluster) error {
-	transporter := config.GetTransporter()
+	transporter := config.GetClusterTransporter(cluster)
 	clusterUser := transporter.GenerateUserName(cluster.Name)
 	clusterTopic := transporter.GenerateClusterTopic(cluster.Name)
 	if err := transporter.DeleteUser(clusterUser); err != nil {
@@ -305,6 +319,7 @@ func (r *HoHAddonInstaller) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 	secretCond := func(obj client.Object) bool {
 		if obj.GetName() == config.GetImagePullSecretName() ||
 			obj.GetName() == constants.GHTransportSecretName ||
+			obj.GetLabels()[operatorconstants.GHTransportRegionLabelKey] != "" ||
 			obj.GetLabels() != nil && obj.GetLabels()["strimzi.io/cluster"] == transportprotocol.KafkaClusterName &&
 				obj.GetLabels()["strimzi.io/kind"] == "KafkaUser" {
 			return true
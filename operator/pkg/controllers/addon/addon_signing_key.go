@@ -0,0 +1,89 @@
+package addon
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
+)
+
+// ensureSigningKey returns the base64 encoded private key of the managed hub to sign the status events. The key pair
+// is generated once for the hub, the private key is kept in the secret only read by the operator, and the public key
+// is added to the secret mounted to the manager.
+func (a *HohAgentAddon) ensureSigningKey(mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+	hubName string,
+) (string, error) {
+	namespace := mgh.Namespace
+	var privateKey []byte
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		privateSecret, err := a.getOrCreateSecret(namespace, constants.GHSigningKeysSecretName)
+		if err != nil {
+			return err
+		}
+		publicSecret, err := a.getOrCreateSecret(namespace, constants.GHSigningPublicKeysSecretName)
+		if err != nil {
+			return err
+		}
+		if len(privateSecret.Data[hubName]) > 0 && len(publicSecret.Data[hubName]) > 0 {
+			privateKey = privateSecret.Data[hubName]
+			return nil
+		}
+
+		// the key pair is regenerated if either of them is lost
+		a.log.Info("generate the signing key", "cluster", hubName)
+		private, public, err := signature.GenerateKey()
+		if err != nil {
+			return err
+		}
+		if privateSecret.Data == nil {
+			privateSecret.Data = map[string][]byte{}
+		}
+		privateSecret.Data[hubName] = private
+		if err := a.client.Update(a.ctx, privateSecret); err != nil {
+			return err
+		}
+		if publicSecret.Data == nil {
+			publicSecret.Data = map[string][]byte{}
+		}
+		publicSecret.Data[hubName] = public
+		if err := a.client.Update(a.ctx, publicSecret); err != nil {
+			return err
+		}
+		privateKey = private
+		config.RecordAction(mgh, config.ReasonSigningKeyGenerated,
+			fmt.Sprintf("Generated the signing key of the managed hub %s", hubName), "cluster", hubName)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to ensure the signing key of %s: %w", hubName, err)
+	}
+	return base64.StdEncoding.EncodeToString(privateKey), nil
+}
+
+func (a *HohAgentAddon) getOrCreateSecret(namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := a.client.Get(a.ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if errors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		err = a.client.Create(a.ctx, secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
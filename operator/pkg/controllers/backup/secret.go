@@ -31,6 +31,8 @@ var secretList = sets.NewString(
 	constants.CustomGrafanaIniName,
 	constants.GHTransportSecretName,
 	constants.GHStorageSecretName,
+	constants.GHArchiveSecretName,
+	constants.GHInventorySecretName,
 )
 
 type secretBackup struct {
@@ -6,6 +6,14 @@ CREATE TABLE IF NOT EXISTS history.applications (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS history.applicationsets (
+    id uuid PRIMARY KEY,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted boolean DEFAULT false NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS history.channels (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -30,6 +38,14 @@ CREATE TABLE IF NOT EXISTS history.managedclustersets (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS history.managedserviceaccounts (
+    id uuid PRIMARY KEY,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted boolean DEFAULT false NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS history.placementbindings (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -86,6 +102,14 @@ CREATE TABLE IF NOT EXISTS spec.applications (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS spec.applicationsets (
+    id uuid PRIMARY KEY,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted boolean DEFAULT false NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS spec.channels (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -128,6 +152,14 @@ CREATE TABLE IF NOT EXISTS spec.managedclustersets (
     deleted boolean DEFAULT false NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS spec.managedserviceaccounts (
+    id uuid PRIMARY KEY,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    deleted boolean DEFAULT false NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS spec.placementbindings (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -185,6 +217,18 @@ CREATE TABLE IF NOT EXISTS status.compliance (
     cluster_id uuid
 );
 
+CREATE TABLE IF NOT EXISTS status.managed_cluster_sets (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    payload jsonb NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS status.managed_cluster_set_bindings (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    payload jsonb NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS status.placementdecisions (
     id uuid NOT NULL,
     leaf_hub_name character varying(254) NOT NULL,
@@ -203,6 +247,41 @@ CREATE TABLE IF NOT EXISTS status.placements (
     payload jsonb NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS status.resource_conflicts (
+    leaf_hub_name character varying(254) NOT NULL,
+    api_version character varying(254) NOT NULL,
+    kind character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL DEFAULT '',
+    name character varying(254) NOT NULL,
+    policy character varying(63) NOT NULL,
+    field_managers jsonb NOT NULL DEFAULT '[]'::jsonb,
+    detected_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, kind, namespace, name)
+);
+
+CREATE TABLE IF NOT EXISTS status.spec_apply_results (
+    leaf_hub_name character varying(254) NOT NULL,
+    api_version character varying(254) NOT NULL,
+    kind character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL DEFAULT '',
+    name character varying(254) NOT NULL,
+    result character varying(63) NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    applied_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, kind, namespace, name)
+);
+
+CREATE TABLE IF NOT EXISTS status.spec_drifts (
+    leaf_hub_name character varying(254) NOT NULL,
+    kind character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL DEFAULT '',
+    name character varying(254) NOT NULL,
+    drift_type character varying(63) NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    detected_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, kind, namespace, name)
+);
+
 CREATE TABLE IF NOT EXISTS status.subscription_reports (
     id uuid NOT NULL,
     leaf_hub_name character varying(254) NOT NULL,
@@ -217,28 +296,89 @@ CREATE TABLE IF NOT EXISTS status.subscription_statuses (
 
 CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_sets_tracking_cluster_set_name_and_leaf_hub_name_idx ON spec.managed_cluster_sets_tracking (cluster_set_name, leaf_hub_name);
 
+CREATE UNIQUE INDEX IF NOT EXISTS local_placementrules_leaf_hub_name_and_id_idx ON local_spec.placementrules (leaf_hub_name, id);
+
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_cluster_idx ON status.compliance (leaf_hub_name, cluster_name);
 
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_non_compliant_idx ON status.compliance (leaf_hub_name, compliance) WHERE (compliance <> 'compliant'::status.compliance_type);
 
 CREATE UNIQUE INDEX IF NOT EXISTS compliance_leaf_hub_policy_cluster_idx ON status.compliance (leaf_hub_name, policy_id, cluster_name);
 
+CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_sets_leaf_hub_name_and_id_idx ON status.managed_cluster_sets (leaf_hub_name, id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_set_bindings_leaf_hub_name_and_payload_id_namespace_idx ON status.managed_cluster_set_bindings (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
+
+CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_set_bindings_leaf_hub_name_and_id_idx ON status.managed_cluster_set_bindings (leaf_hub_name, id);
+
 CREATE UNIQUE INDEX IF NOT EXISTS placementdecisions_leaf_hub_name_and_payload_id_namespace_idx ON status.placementdecisions (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
+CREATE UNIQUE INDEX IF NOT EXISTS placementdecisions_leaf_hub_name_and_id_idx ON status.placementdecisions (leaf_hub_name, id);
+
 CREATE INDEX IF NOT EXISTS placementdecisions_payload_name_and_namespace_idx ON status.placementdecisions ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
 CREATE UNIQUE INDEX IF NOT EXISTS placementrules_leaf_hub_name_and_payload_id_namespace_idx ON status.placementrules (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
+CREATE UNIQUE INDEX IF NOT EXISTS placementrules_leaf_hub_name_and_id_idx ON status.placementrules (leaf_hub_name, id);
+
 CREATE INDEX IF NOT EXISTS placementrules_payload_name_and_namespace_idx ON status.placementrules ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
 CREATE UNIQUE INDEX IF NOT EXISTS placements_leaf_hub_name_and_payload_id_namespace_idx ON status.placements (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
+CREATE UNIQUE INDEX IF NOT EXISTS placements_leaf_hub_name_and_id_idx ON status.placements (leaf_hub_name, id);
+
 CREATE INDEX IF NOT EXISTS placements_payload_name_and_namespace_idx ON status.placements ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
 CREATE UNIQUE INDEX IF NOT EXISTS subscription_reports_leaf_hub_name_and_payload_id_namespace_idx ON status.subscription_reports (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
+CREATE UNIQUE INDEX IF NOT EXISTS subscription_reports_leaf_hub_name_and_id_idx ON status.subscription_reports (leaf_hub_name, id);
+
 CREATE INDEX IF NOT EXISTS subscription_reports_payload_name_and_namespace_idx ON status.subscription_reports ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
 CREATE UNIQUE INDEX IF NOT EXISTS subscription_statuses_leaf_hub_name_and_payload_id_namespace_idx ON status.subscription_statuses (leaf_hub_name, id, (((payload -> 'metadata'::text) ->> 'namespace'::text)));
 
-CREATE INDEX IF NOT EXISTS subscription_statuses_payload_name_and_namespace_idx ON status.subscription_statuses ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
\ No newline at end of file
+CREATE UNIQUE INDEX IF NOT EXISTS subscription_statuses_leaf_hub_name_and_id_idx ON status.subscription_statuses (leaf_hub_name, id);
+
+CREATE INDEX IF NOT EXISTS subscription_statuses_payload_name_and_namespace_idx ON status.subscription_statuses ((((payload -> 'metadata'::text) ->> 'name'::text)), (((payload -> 'metadata'::text) ->> 'namespace'::text)));
+
+-- the members of the global managed cluster sets on each managed hub. the label selector with matchExpressions isn't
+-- supported, the members of such cluster set are empty.
+CREATE OR REPLACE VIEW status.managed_cluster_set_members AS
+SELECT
+    s.id AS cluster_set_id,
+    s.payload -> 'metadata' ->> 'name' AS cluster_set_name,
+    c.leaf_hub_name,
+    c.cluster_id,
+    c.cluster_name
+FROM
+    status.managed_cluster_sets s
+    JOIN status.managed_clusters c ON c.leaf_hub_name = s.leaf_hub_name AND c.deleted_at IS NULL
+WHERE
+    (
+        COALESCE(s.payload -> 'spec' -> 'clusterSelector' ->> 'selectorType', 'ExclusiveClusterSetLabel') = 'ExclusiveClusterSetLabel'
+        AND c.payload -> 'metadata' -> 'labels' ->> 'cluster.open-cluster-management.io/clusterset' = s.payload -> 'metadata' ->> 'name'
+    )
+    OR (
+        s.payload -> 'spec' -> 'clusterSelector' ->> 'selectorType' = 'LabelSelector'
+        AND s.payload -> 'spec' -> 'clusterSelector' -> 'labelSelector' -> 'matchExpressions' IS NULL
+        AND COALESCE(c.payload -> 'metadata' -> 'labels', '{}'::jsonb) @> COALESCE(s.payload -> 'spec' -> 'clusterSelector' -> 'labelSelector' -> 'matchLabels', '{}'::jsonb)
+    );
+
+-- the deployment summary of the global subscriptions on each managed hub, it's reported by the subscription report
+-- of the same name on the hub
+CREATE OR REPLACE VIEW status.subscription_deployment AS
+SELECT
+    s.id AS subscription_id,
+    s.payload -> 'metadata' ->> 'namespace' AS namespace,
+    s.payload -> 'metadata' ->> 'name' AS name,
+    r.leaf_hub_name,
+    COALESCE(NULLIF(r.payload -> 'summary' ->> 'clusters', ''), '0')::integer AS clusters,
+    COALESCE(NULLIF(r.payload -> 'summary' ->> 'deployed', ''), '0')::integer AS deployed,
+    COALESCE(NULLIF(r.payload -> 'summary' ->> 'inProgress', ''), '0')::integer AS in_progress,
+    COALESCE(NULLIF(r.payload -> 'summary' ->> 'failed', ''), '0')::integer AS failed,
+    COALESCE(NULLIF(r.payload -> 'summary' ->> 'propagationFailed', ''), '0')::integer AS propagation_failed
+FROM
+    spec.subscriptions s
+    JOIN status.subscription_reports r ON r.payload -> 'metadata' ->> 'name' = s.payload -> 'metadata' ->> 'name'
+    AND r.payload -> 'metadata' ->> 'namespace' = s.payload -> 'metadata' ->> 'namespace'
+WHERE
+    s.deleted = FALSE;
@@ -27,6 +27,35 @@ END;
 $$;
 
 
+CREATE OR REPLACE FUNCTION public.move_applicationsets_to_history() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO history.applicationsets SELECT * FROM spec.applicationsets
+  WHERE payload -> 'metadata' ->> 'name' = NEW.payload -> 'metadata' ->> 'name' AND
+  (
+    (
+      (payload -> 'metadata' ->> 'namespace' IS NOT NULL AND NEW.payload -> 'metadata' ->> 'namespace' IS NOT NULL)
+    AND payload -> 'metadata' ->> 'namespace' = NEW.payload -> 'metadata' ->> 'namespace'
+    ) OR (
+      payload -> 'metadata' -> 'namespace' IS NULL AND NEW.payload -> 'metadata' -> 'namespace' IS NULL
+    )
+  );
+  DELETE FROM spec.applicationsets
+  WHERE payload -> 'metadata' ->> 'name' = NEW.payload -> 'metadata' ->> 'name' AND
+  (
+    (
+      (payload -> 'metadata' ->> 'namespace' IS NOT NULL AND NEW.payload -> 'metadata' ->> 'namespace' IS NOT NULL)
+    AND payload -> 'metadata' ->> 'namespace' = NEW.payload -> 'metadata' ->> 'namespace'
+    ) OR (
+      payload -> 'metadata' -> 'namespace' IS NULL AND NEW.payload -> 'metadata' -> 'namespace' IS NULL
+    )
+  );
+  RETURN NEW;
+END;
+$$;
+
+
 CREATE OR REPLACE FUNCTION public.move_channels_to_history() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -111,6 +140,34 @@ BEGIN
 END;
 $$;
 
+CREATE OR REPLACE FUNCTION public.move_managedserviceaccounts_to_history() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO history.managedserviceaccounts SELECT * FROM spec.managedserviceaccounts
+  WHERE payload -> 'metadata' ->> 'name' = NEW.payload -> 'metadata' ->> 'name' AND
+  (
+    (
+      (payload -> 'metadata' ->> 'namespace' IS NOT NULL AND NEW.payload -> 'metadata' ->> 'namespace' IS NOT NULL)
+    AND payload -> 'metadata' ->> 'namespace' = NEW.payload -> 'metadata' ->> 'namespace'
+    ) OR (
+      payload -> 'metadata' -> 'namespace' IS NULL AND NEW.payload -> 'metadata' -> 'namespace' IS NULL
+    )
+  );
+  DELETE FROM spec.managedserviceaccounts
+  WHERE payload -> 'metadata' ->> 'name' = NEW.payload -> 'metadata' ->> 'name' AND
+  (
+    (
+      (payload -> 'metadata' ->> 'namespace' IS NOT NULL AND NEW.payload -> 'metadata' ->> 'namespace' IS NOT NULL)
+    AND payload -> 'metadata' ->> 'namespace' = NEW.payload -> 'metadata' ->> 'namespace'
+    ) OR (
+      payload -> 'metadata' -> 'namespace' IS NULL AND NEW.payload -> 'metadata' -> 'namespace' IS NULL
+    )
+  );
+  RETURN NEW;
+END;
+$$;
+
 CREATE OR REPLACE FUNCTION public.move_placementbindings_to_history() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
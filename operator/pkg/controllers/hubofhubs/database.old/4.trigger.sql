@@ -6,12 +6,16 @@ CREATE TRIGGER set_timestamp BEFORE UPDATE ON local_spec.placementrules FOR EACH
 
 DROP TRIGGER IF EXISTS set_timestamp ON history.applications;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.applications FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON history.applicationsets;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.applicationsets FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.channels;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.channels FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.managedclustersetbindings;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.managedclustersetbindings FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.managedclustersets;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.managedclustersets FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON history.managedserviceaccounts;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.managedserviceaccounts FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.placementbindings;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON history.placementbindings FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON history.placementrules;
@@ -27,12 +31,16 @@ CREATE TRIGGER set_timestamp BEFORE UPDATE ON local_spec.placementrules FOR EACH
 
 DROP TRIGGER IF EXISTS move_to_history ON spec.applications;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.applications FOR EACH ROW EXECUTE FUNCTION public.move_applications_to_history();
+DROP TRIGGER IF EXISTS move_to_history ON spec.applicationsets;
+CREATE TRIGGER move_to_history BEFORE INSERT ON spec.applicationsets FOR EACH ROW EXECUTE FUNCTION public.move_applicationsets_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.channels;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.channels FOR EACH ROW EXECUTE FUNCTION public.move_channels_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.managedclustersetbindings;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.managedclustersetbindings FOR EACH ROW EXECUTE FUNCTION public.move_managedclustersetbindings_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.managedclustersets;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.managedclustersets FOR EACH ROW EXECUTE FUNCTION public.move_managedclustersets_to_history();
+DROP TRIGGER IF EXISTS move_to_history ON spec.managedserviceaccounts;
+CREATE TRIGGER move_to_history BEFORE INSERT ON spec.managedserviceaccounts FOR EACH ROW EXECUTE FUNCTION public.move_managedserviceaccounts_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.placementbindings;
 CREATE TRIGGER move_to_history BEFORE INSERT ON spec.placementbindings FOR EACH ROW EXECUTE FUNCTION public.move_placementbindings_to_history();
 DROP TRIGGER IF EXISTS move_to_history ON spec.placementrules;
@@ -46,12 +54,16 @@ CREATE TRIGGER move_to_history BEFORE INSERT ON spec.subscriptions FOR EACH ROW
 
 DROP TRIGGER IF EXISTS set_timestamp ON spec.applications;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.applications FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON spec.applicationsets;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.applicationsets FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.channels;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.channels FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.managedclustersetbindings;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.managedclustersetbindings FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.managedclustersets;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.managedclustersets FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
+DROP TRIGGER IF EXISTS set_timestamp ON spec.managedserviceaccounts;
+CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.managedserviceaccounts FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.placementbindings;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.placementbindings FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 DROP TRIGGER IF EXISTS set_timestamp ON spec.placementrules;
@@ -59,6 +59,39 @@ CREATE TABLE IF NOT EXISTS status.leaf_hubs (
 CREATE INDEX IF NOT EXISTS leafhub_deleted_at_idx ON status.leaf_hubs (deleted_at);
 
 -- Partition tables
+CREATE TABLE IF NOT EXISTS status.argocd_applications (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    payload jsonb NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS argocd_applications_leaf_hub_name_and_id_idx ON status.argocd_applications (leaf_hub_name, id);
+
+CREATE TABLE IF NOT EXISTS status.argocd_applicationsets (
+    id uuid NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    payload jsonb NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS argocd_applicationsets_leaf_hub_name_and_id_idx ON status.argocd_applicationsets (leaf_hub_name, id);
+
+-- the sync and health status of the argocd applications on each destination cluster
+CREATE OR REPLACE VIEW status.argocd_application_status AS
+SELECT
+    a.leaf_hub_name,
+    a.id AS application_id,
+    a.payload -> 'metadata' ->> 'namespace' AS namespace,
+    a.payload -> 'metadata' ->> 'name' AS name,
+    s.payload -> 'metadata' ->> 'name' AS applicationset_name,
+    COALESCE(a.payload -> 'spec' -> 'destination' ->> 'name', a.payload -> 'spec' -> 'destination' ->> 'server') AS destination,
+    a.payload -> 'status' -> 'sync' ->> 'status' AS sync_status,
+    a.payload -> 'status' -> 'health' ->> 'status' AS health_status
+FROM
+    status.argocd_applications a
+    LEFT JOIN status.argocd_applicationsets s ON s.leaf_hub_name = a.leaf_hub_name
+    AND s.payload -> 'status' -> 'resources' @> jsonb_build_array(jsonb_build_object(
+        'kind', 'Application',
+        'name', a.payload -> 'metadata' ->> 'name',
+        'namespace', a.payload -> 'metadata' ->> 'namespace'));
+
 CREATE TABLE IF NOT EXISTS event.local_policies (
     event_name text NOT NULL,
     policy_id uuid NOT NULL,
@@ -70,6 +103,13 @@ CREATE TABLE IF NOT EXISTS event.local_policies (
     source jsonb,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     compliance local_status.compliance_type NOT NULL,
+    -- the normalized taxonomy of the event, the severity is one of the critical, warning and info
+    event_type character varying(16),
+    severity character varying(16),
+    category character varying(32),
+    involved_kind character varying(63),
+    involved_namespace character varying(254),
+    involved_name character varying(254),
     CONSTRAINT local_policies_unique_constraint UNIQUE (event_name, count, created_at)
 ) PARTITION BY RANGE (created_at);
 
@@ -83,9 +123,18 @@ CREATE TABLE IF NOT EXISTS event.local_root_policies (
     source jsonb,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     compliance local_status.compliance_type NOT NULL,
+    -- the normalized taxonomy of the event, the severity is one of the critical, warning and info
+    event_type character varying(16),
+    severity character varying(16),
+    category character varying(32),
+    involved_kind character varying(63),
+    involved_namespace character varying(254),
+    involved_name character varying(254),
     CONSTRAINT local_root_policies_unique_constraint UNIQUE (event_name, count, created_at)
 ) PARTITION BY RANGE (created_at);
 
+-- the indexes of the taxonomy are created by the upgrade/4.policy-event-taxonomy.sql, after the columns are added to
+-- the existing tables
 -- log tables
 CREATE TABLE IF NOT EXISTS event.data_retention_job_log (
     table_name varchar(254) NOT NULL,
@@ -117,10 +166,280 @@ CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     error TEXT
 );
 
+-- the samples of the key metrics queried from the prometheus of the managed hubs
+CREATE TABLE IF NOT EXISTS history.hub_metrics (
+    leaf_hub_name character varying(254) NOT NULL,
+    metric_name character varying(254) NOT NULL,
+    labels jsonb,
+    value double precision NOT NULL,
+    sampled_at timestamp without time zone NOT NULL
+) PARTITION BY RANGE (sampled_at);
+CREATE INDEX IF NOT EXISTS hub_metrics_metric_idx ON history.hub_metrics (metric_name, sampled_at);
+
+-- the manifest of the partitions which are exported to the object storage before they're dropped by the retention
+CREATE TABLE IF NOT EXISTS history.archived_partitions (
+    partition_name character varying(254) PRIMARY KEY,
+    table_name character varying(254) NOT NULL,
+    bucket character varying(254) NOT NULL,
+    object_key text NOT NULL,
+    row_count bigint NOT NULL DEFAULT 0,
+    size_bytes bigint NOT NULL DEFAULT 0,
+    checksum character varying(64) NOT NULL,
+    archived_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS status.transport (
     -- transport name, it is the topic name for the kafka transport
-    name character varying(254) PRIMARY KEY,
+    name character varying(254) NOT NULL,
+    -- the offset is committed for each partition of the topic
+    partition integer NOT NULL DEFAULT 0,
     payload jsonb NOT NULL,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (name, partition)
+);
+-- the resource counts reported by the agents, which are compared with the records in the database periodically
+CREATE TABLE IF NOT EXISTS status.hub_resource_counts (
+    leaf_hub_name character varying(254) NOT NULL,
+    resource_type character varying(64) NOT NULL,
+    reported_count integer NOT NULL,
+    stored_count integer,
+    consistent boolean,
+    reported_at timestamp without time zone NOT NULL,
+    checked_at timestamp without time zone,
+    PRIMARY KEY (leaf_hub_name, resource_type)
+);
+-- the version and the health of the agents reported with the heartbeats
+CREATE TABLE IF NOT EXISTS status.agent_health (
+    leaf_hub_name character varying(254) PRIMARY KEY,
+    agent_version character varying(254) NOT NULL,
+    collectors jsonb NOT NULL,
+    last_sync_times jsonb,
+    resource_counts jsonb,
+    reported_at timestamp without time zone NOT NULL
+);
+-- the version of the agents detected from the bundles, and their compatibility with the manager
+CREATE TABLE IF NOT EXISTS status.agent_versions (
+    leaf_hub_name character varying(254) PRIMARY KEY,
+    agent_version character varying(254) NOT NULL,
+    manager_version character varying(254) NOT NULL,
+    compatibility character varying(20) NOT NULL,
+    reason text,
+    quarantined boolean NOT NULL DEFAULT false,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+-- the status events rejected by the manager since they don't match the schemas of their versions
+CREATE TABLE IF NOT EXISTS status.dead_letter_events (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    event_id character varying(254) NOT NULL,
+    schema_version character varying(20),
+    reason text NOT NULL,
+    payload bytea,
+    received_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS dead_letter_events_hub_idx ON status.dead_letter_events (leaf_hub_name, received_at);
+-- the audit results of the gatekeeper constraints reported by the agents
+CREATE TABLE IF NOT EXISTS status.gatekeeper_constraints (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    constraint_kind character varying(254) NOT NULL,
+    constraint_name character varying(254) NOT NULL,
+    enforcement_action character varying(64) NOT NULL,
+    total_violations integer NOT NULL,
+    audit_timestamp timestamp without time zone,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, constraint_kind, constraint_name)
+);
+-- the resources violating the gatekeeper constraints, which are limited by the violations in the constraint status
+CREATE TABLE IF NOT EXISTS status.gatekeeper_violations (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    constraint_kind character varying(254) NOT NULL,
+    constraint_name character varying(254) NOT NULL,
+    resource_kind character varying(254) NOT NULL,
+    resource_namespace character varying(254) NOT NULL,
+    resource_name character varying(254) NOT NULL,
+    message text NOT NULL,
+    enforcement_action character varying(64) NOT NULL
+);
+CREATE INDEX IF NOT EXISTS gatekeeper_violations_constraint_idx ON status.gatekeeper_violations (leaf_hub_name, cluster_name, constraint_kind, constraint_name);
+-- the results of the compliance operator scans reported by the agents
+CREATE TABLE IF NOT EXISTS status.compliance_scans (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    scan_name character varying(254) NOT NULL,
+    phase character varying(64) NOT NULL,
+    result character varying(64) NOT NULL,
+    passed integer NOT NULL,
+    failed integer NOT NULL,
+    manual integer NOT NULL,
+    others integer NOT NULL,
+    failed_high integer NOT NULL,
+    failed_medium integer NOT NULL,
+    failed_low integer NOT NULL,
+    end_timestamp timestamp without time zone,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, scan_name)
+);
+-- the vulnerabilities of the images running on the clusters by the severity, reported by the agents
+CREATE TABLE IF NOT EXISTS status.image_vulnerabilities (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    severity character varying(32) NOT NULL,
+    images integer NOT NULL,
+    vulnerabilities integer NOT NULL,
+    fixable integer NOT NULL,
+    affected_pods integer NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, severity)
+);
+-- the latest results of the templates of the policies on the clusters, with the bounded messages and related objects
+CREATE TABLE IF NOT EXISTS status.policy_template_details (
+    leaf_hub_name character varying(254) NOT NULL,
+    policy_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    template_kind character varying(254) NOT NULL,
+    template_name character varying(254) NOT NULL,
+    policy_namespace character varying(254) NOT NULL,
+    policy_name character varying(254) NOT NULL,
+    cluster_id character varying(254),
+    compliance character varying(64) NOT NULL,
+    message text NOT NULL,
+    total_related_objects integer NOT NULL,
+    related_objects jsonb,
+    last_timestamp timestamp without time zone,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, policy_id, cluster_name, template_kind, template_name)
+);
+CREATE INDEX IF NOT EXISTS policy_template_details_cluster_idx ON status.policy_template_details (cluster_name, compliance);
+-- the summaries of the managed hubs forwarded by the regional global hubs, it's only used by the upstream global hub
+CREATE TABLE IF NOT EXISTS status.regional_hub_summaries (
+    regional_hub_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    status character varying(10) NOT NULL,
+    last_heartbeat timestamp without time zone,
+    managed_clusters integer NOT NULL,
+    policies integer NOT NULL,
+    compliance jsonb,
+    reported_at timestamp without time zone NOT NULL,
+    PRIMARY KEY (regional_hub_name, leaf_hub_name)
+);
+-- the health of the addons on the managed clusters reported by the agents
+CREATE TABLE IF NOT EXISTS status.managed_cluster_addons (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    addon_name character varying(254) NOT NULL,
+    status character varying(64) NOT NULL,
+    reason character varying(254) NOT NULL DEFAULT '',
+    message text NOT NULL DEFAULT '',
+    last_transition_time timestamp without time zone,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, addon_name)
+);
+CREATE INDEX IF NOT EXISTS managed_cluster_addons_addon_idx ON status.managed_cluster_addons (addon_name, status);
+-- the credentials of the managed service accounts on the managed clusters reported by the agents, the tokens aren't
+-- reported, only the secrets which hold them on the managed hubs
+CREATE TABLE IF NOT EXISTS status.managed_service_accounts (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    status character varying(64) NOT NULL,
+    reason character varying(254) NOT NULL DEFAULT '',
+    message text NOT NULL DEFAULT '',
+    token_secret_name character varying(254) NOT NULL DEFAULT '',
+    last_refresh_time timestamp without time zone,
+    expiration_time timestamp without time zone,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, name)
+);
+CREATE INDEX IF NOT EXISTS managed_service_accounts_name_idx ON status.managed_service_accounts (name, status);
+-- the fields computed from the managed clusters by the enrichers of the manager, e.g. the business unit from the labels
+CREATE TABLE IF NOT EXISTS status.managed_cluster_enrichments (
+    cluster_id uuid PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    fields jsonb NOT NULL,
+    business_unit text generated always as (fields ->> 'businessUnit') stored,
+    region text generated always as (fields ->> 'region') stored,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS managed_cluster_enrichments_hub_idx ON status.managed_cluster_enrichments (leaf_hub_name, cluster_name);
+-- the last time the bundle of each type is received from the managed hubs, the expected interval is null for the
+-- bundles only sent once they're changed
+CREATE TABLE IF NOT EXISTS status.data_freshness (
+    leaf_hub_name character varying(254) NOT NULL,
+    bundle_type character varying(254) NOT NULL,
+    last_received_at timestamp without time zone NOT NULL,
+    expected_interval_seconds bigint,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, bundle_type)
+);
+-- the status bundles and their bytes consumed from the managed hubs per day and bundle type, the rows of each
+-- manager replica are added up
+CREATE TABLE IF NOT EXISTS status.hub_transport_usage (
+    leaf_hub_name character varying(254) NOT NULL,
+    bundle_type character varying(254) NOT NULL,
+    usage_date date NOT NULL,
+    bundles bigint NOT NULL DEFAULT 0,
+    bytes bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (leaf_hub_name, bundle_type, usage_date)
+);
+-- the records of the managed hubs in each table and the bytes they take, which are estimated by sampling the rows
+CREATE TABLE IF NOT EXISTS status.hub_storage_usage (
+    leaf_hub_name character varying(254) NOT NULL,
+    table_name character varying(254) NOT NULL,
+    records bigint NOT NULL,
+    bytes bigint NOT NULL,
+    measured_at timestamp without time zone NOT NULL,
+    PRIMARY KEY (leaf_hub_name, table_name)
+);
+-- the last run of the scheduled jobs of the manager
+CREATE TABLE IF NOT EXISTS status.cron_jobs (
+    name character varying(254) PRIMARY KEY,
+    last_run_at timestamp without time zone NOT NULL,
+    next_run_at timestamp without time zone,
+    result character varying(20) NOT NULL,
+    error text,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+-- the migrations of the managed clusters between the managed hubs, only one of them is in progress for each cluster
+CREATE TABLE IF NOT EXISTS status.managed_cluster_migrations (
+    id serial PRIMARY KEY,
+    cluster_name character varying(254) NOT NULL,
+    from_hub character varying(254) NOT NULL,
+    to_hub character varying(254) NOT NULL,
+    phase character varying(20) NOT NULL,
+    message text,
+    phase_started_at timestamp without time zone DEFAULT now() NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL
-);
\ No newline at end of file
+);
+CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_migrations_in_progress_idx ON status.managed_cluster_migrations (cluster_name) WHERE phase NOT IN ('Completed', 'Failed');
+-- the named groups of the managed clusters spanning the managed hubs, the members are selected by the label selector
+-- and the explicit clusters, e.g. "hub1/cluster1" or "cluster1" of any hub
+CREATE TABLE IF NOT EXISTS status.cluster_groups (
+    name character varying(254) PRIMARY KEY,
+    description text NOT NULL DEFAULT '',
+    label_selector text NOT NULL DEFAULT '',
+    clusters jsonb NOT NULL DEFAULT '[]'::jsonb,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+-- the members of the cluster groups, they're refreshed by the manager once the groups or the clusters are changed
+CREATE TABLE IF NOT EXISTS status.cluster_group_members (
+    group_name character varying(254) NOT NULL REFERENCES status.cluster_groups (name) ON DELETE CASCADE,
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    PRIMARY KEY (group_name, cluster_id)
+);
+CREATE INDEX IF NOT EXISTS cluster_group_members_cluster_idx ON status.cluster_group_members (cluster_id);
+-- the migrations applied by the operator upgrades
+CREATE TABLE IF NOT EXISTS status.migrations (
+    id serial PRIMARY KEY,
+    name character varying(254) NOT NULL UNIQUE,
+    operator_version character varying(254) NOT NULL,
+    applied_at timestamp without time zone DEFAULT now() NOT NULL
+);
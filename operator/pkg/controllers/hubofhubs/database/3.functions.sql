@@ -107,16 +107,38 @@ $$ LANGUAGE plpgsql;
 
 --- create the monthly partitioned tables function by created_at/compliance_date column
 --- sample: SELECT create_monthly_range_partitioned_table('event.local_root_policies', '2023-08-01');
+--- the rows of the month in the default partition block the creation of the partition, so they're moved into it
 CREATE OR REPLACE FUNCTION create_monthly_range_partitioned_table(full_table_name text, input_time text)
 RETURNS VOID AS
-$$ 
-BEGIN 
-    EXECUTE format('CREATE TABLE IF NOT EXISTS %1$s_%2$s PARTITION OF %1$s FOR VALUES FROM (%3$L) TO (%4$L)',
-                   full_table_name, 
-                   to_char(input_time::date, 'YYYY_MM'),
-                   DATE_TRUNC('MONTH', input_time::date),
-                   DATE_TRUNC('MONTH', (input_time::date + INTERVAL '1 MONTH'))
-                  );
+$$
+DECLARE
+    partition_name text := format('%s_%s', full_table_name, to_char(input_time::date, 'YYYY_MM'));
+    default_name text := full_table_name || '_default';
+    start_time timestamp := DATE_TRUNC('MONTH', input_time::date);
+    end_time timestamp := DATE_TRUNC('MONTH', (input_time::date + INTERVAL '1 MONTH'));
+    partition_key text;
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN;
+    END IF;
+    IF to_regclass(default_name) IS NULL THEN
+        EXECUTE format('CREATE TABLE %1$s PARTITION OF %2$s FOR VALUES FROM (%3$L) TO (%4$L)',
+                       partition_name, full_table_name, start_time, end_time);
+        RETURN;
+    END IF;
+
+    SELECT a.attname INTO partition_key
+    FROM pg_partitioned_table p
+    JOIN pg_attribute a ON a.attrelid = p.partrelid AND a.attnum = p.partattrs[0]
+    WHERE p.partrelid = full_table_name::regclass;
+
+    EXECUTE format('CREATE TABLE %1$s (LIKE %2$s INCLUDING DEFAULTS INCLUDING CONSTRAINTS)',
+                   partition_name, full_table_name);
+    EXECUTE format('WITH moved AS (DELETE FROM %1$s WHERE %2$I >= %3$L AND %2$I < %4$L RETURNING *)
+                    INSERT INTO %5$s SELECT * FROM moved',
+                   default_name, partition_key, start_time, end_time, partition_name);
+    EXECUTE format('ALTER TABLE %1$s ATTACH PARTITION %2$s FOR VALUES FROM (%3$L) TO (%4$L)',
+                   full_table_name, partition_name, start_time, end_time);
 END $$ LANGUAGE plpgsql;
 
 CREATE OR REPLACE FUNCTION public.set_cluster_id_to_local_compliance() RETURNS trigger
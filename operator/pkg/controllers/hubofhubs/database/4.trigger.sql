@@ -12,12 +12,21 @@ AFTER INSERT OR UPDATE ON status.managed_clusters
 FOR EACH ROW
 EXECUTE FUNCTION public.update_local_compliance_cluster_id();
 
+--- create the default partitions as the safety net, the rows out of the monthly partitions are kept in them instead
+--- of failing the inserts, then they're moved into the monthly partitions once they're created
+CREATE TABLE IF NOT EXISTS event.local_root_policies_default PARTITION OF event.local_root_policies DEFAULT;
+CREATE TABLE IF NOT EXISTS event.local_policies_default PARTITION OF event.local_policies DEFAULT;
+CREATE TABLE IF NOT EXISTS history.local_compliance_default PARTITION OF history.local_compliance DEFAULT;
+CREATE TABLE IF NOT EXISTS history.hub_metrics_default PARTITION OF history.hub_metrics DEFAULT;
+
 --- create the current month partitioned tables for local_policies and local_root_policies
 SELECT create_monthly_range_partitioned_table('event.local_root_policies', to_char(current_date, 'YYYY-MM-DD'));
 SELECT create_monthly_range_partitioned_table('event.local_policies', to_char(current_date, 'YYYY-MM-DD'));
 SELECT create_monthly_range_partitioned_table('history.local_compliance', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_monthly_range_partitioned_table('history.hub_metrics', to_char(current_date, 'YYYY-MM-DD'));
 
 --- create the previous month partitioned tables for receiving the data from the previous month
 SELECT create_monthly_range_partitioned_table('event.local_root_policies', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
 SELECT create_monthly_range_partitioned_table('event.local_policies', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
 SELECT create_monthly_range_partitioned_table('history.local_compliance', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_monthly_range_partitioned_table('history.hub_metrics', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
@@ -0,0 +1,209 @@
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
+	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	cdcConnectName        = "multicluster-global-hub-cdc"
+	cdcKafkaUser          = "global-hub-cdc-user"
+	cdcPostgresSecretName = "multicluster-global-hub-cdc-postgres" // #nosec G101
+	// the changes of the table are sent to the topic <prefix>.<schema>.<table>, the internal topics of the kafka
+	// connect are also named with the prefix
+	cdcTopicPrefix = "globalhub-cdc"
+	// the name of the replication slot and the publication in the database
+	cdcReplicationName = "globalhub_cdc"
+	debeziumVersion    = "2.5.0.Final"
+)
+
+// cdcTables are the tables streamed by the debezium, the partitioned history tables aren't included since the
+// changes of them are only published by the partitions
+var cdcTables = []string{
+	"status.managed_clusters",
+	"status.leaf_hub_heartbeats",
+	"local_spec.policies",
+	"local_status.compliance",
+}
+
+// reconcileChangeDataCapture renders the KafkaConnect with the debezium postgres connector, which streams the changes
+// of the global hub database into the kafka topics. It's only supported with the built-in kafka since the connect is
+// managed by the strimzi operator.
+func (r *MulticlusterGlobalHubReconciler) reconcileChangeDataCapture(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	log := r.Log.WithName("cdc")
+
+	if !config.IsChangeDataCaptureEnabled(mgh) {
+		return r.pruneChangeDataCapture(ctx, mgh)
+	}
+	transProtocol, err := detectTransportProtocol(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	if transProtocol != transport.StrimziTransporter {
+		log.Info("the change data capture is skipped since it's only supported with the built-in kafka")
+		return r.pruneChangeDataCapture(ctx, mgh)
+	}
+
+	if r.MiddlewareConfig == nil || r.MiddlewareConfig.StorageConn == nil {
+		return fmt.Errorf("middleware PgConnection config is null")
+	}
+	secretData, err := cdcPostgresSecretData(r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI,
+		r.MiddlewareConfig.StorageConn.CACert)
+	if err != nil {
+		return err
+	}
+	postgresSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cdcPostgresSecretName,
+			Namespace: mgh.GetNamespace(),
+			Labels: map[string]string{
+				constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+			},
+		},
+		Data: secretData,
+	}
+	if err = controllerutil.SetControllerReference(mgh, postgresSecret, r.Scheme); err != nil {
+		return err
+	}
+	if _, err = operatorutils.ApplySecret(ctx, r.Client, postgresSecret); err != nil {
+		return fmt.Errorf("failed to apply the postgres secret of the change data capture: %w", err)
+	}
+
+	cdcObjects, err := r.renderChangeDataCapture(mgh, len(secretData["ca.crt"]) > 0)
+	if err != nil {
+		return err
+	}
+
+	// create restmapper for deployer to find GVR
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	if err = manipulateObj(cdcObjects, mgh, deployer.NewHoHDeployer(r.Client), mapper, r.GetScheme()); err != nil {
+		return fmt.Errorf("failed to create/update the change data capture objects: %w", err)
+	}
+
+	log.Info("change data capture objects created/updated successfully")
+	return nil
+}
+
+func (r *MulticlusterGlobalHubReconciler) renderChangeDataCapture(mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+	withCACert bool,
+) ([]*unstructured.Unstructured, error) {
+	cdcRenderer := renderer.NewHoHRenderer(fs)
+	cdcObjects, err := cdcRenderer.Render("manifests/cdc", "", func(profile string) (interface{}, error) {
+		return struct {
+			Namespace       string
+			ConnectName     string
+			KafkaCluster    string
+			KafkaVersion    string
+			KafkaUser       string
+			TopicPrefix     string
+			PostgresSecret  string
+			PostgresCACert  bool
+			Tables          string
+			ReplicationName string
+			DebeziumVersion string
+			ImagePullSecret string
+		}{
+			Namespace:       mgh.GetNamespace(),
+			ConnectName:     cdcConnectName,
+			KafkaCluster:    transporter.KafkaClusterName,
+			KafkaVersion:    transporter.KafkaVersion,
+			KafkaUser:       cdcKafkaUser,
+			TopicPrefix:     cdcTopicPrefix,
+			PostgresSecret:  cdcPostgresSecretName,
+			PostgresCACert:  withCACert,
+			Tables:          strings.Join(cdcTables, ","),
+			ReplicationName: cdcReplicationName,
+			DebeziumVersion: debeziumVersion,
+			ImagePullSecret: mgh.Spec.ImagePullSecret,
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the change data capture manifests: %w", err)
+	}
+	return cdcObjects, nil
+}
+
+// pruneChangeDataCapture removes the kafka connect and its secret once the change data capture is disabled, the
+// replication slot is left in the database and should be dropped manually, or the WAL is retained for it
+func (r *MulticlusterGlobalHubReconciler) pruneChangeDataCapture(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	cdcObjects, err := r.renderChangeDataCapture(mgh, false)
+	if err != nil {
+		return err
+	}
+	for _, obj := range cdcObjects {
+		// the strimzi or openshift image api isn't installed
+		if err := r.pruneObject(ctx, mgh, obj); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return r.pruneObject(ctx, mgh, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: cdcPostgresSecretName, Namespace: mgh.GetNamespace()},
+	})
+}
+
+// cdcPostgresSecretData returns the connection of the debezium connector, which is read by the directory config
+// provider of the kafka connect
+func cdcPostgresSecretData(databaseURI string, caCert []byte) (map[string][]byte, error) {
+	objURI, err := url.Parse(databaseURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the database uri: %w", err)
+	}
+	password, ok := objURI.User.Password()
+	if !ok {
+		return nil, fmt.Errorf("failed to get password from the database uri")
+	}
+	database := "hoh"
+	if paths := strings.Split(objURI.Path, "/"); len(paths) > 1 && paths[1] != "" {
+		database = paths[1]
+	}
+	port := objURI.Port()
+	if port == "" {
+		port = "5432"
+	}
+	sslMode := objURI.Query().Get("sslmode")
+	if sslMode == "" {
+		sslMode = "prefer"
+	}
+
+	data := map[string][]byte{
+		"host":     []byte(objURI.Hostname()),
+		"port":     []byte(port),
+		"user":     []byte(objURI.User.Username()),
+		"password": []byte(password),
+		"dbname":   []byte(database),
+		"sslmode":  []byte(sslMode),
+	}
+	if len(caCert) > 0 {
+		data["ca.crt"] = caCert
+	}
+	return data, nil
+}
@@ -19,6 +19,7 @@ import (
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 )
 
@@ -48,6 +49,34 @@ func (r *GlobalHubConditionReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, err
 	}
 
+	// the built-in grafana isn't deployed if the grafana is disabled
+	if !config.IsGrafanaEnabled(mgh) {
+		if err := condition.UpdateCondition(ctx, r.Client, mgh, metav1.Condition{
+			Type:               condition.CONDITION_TYPE_GRAFANA_AVAILABLE,
+			Status:             metav1.ConditionTrue,
+			Reason:             "GrafanaDisabled",
+			Message:            "The grafana is disabled, the postgres datasource is exported in the secret",
+			LastTransitionTime: metav1.Time{Time: time.Now()},
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// the built-in grafana isn't deployed if the dashboards are provisioned into the external grafana
+	if mgh.Spec.ExternalGrafana != nil {
+		if err := condition.UpdateCondition(ctx, r.Client, mgh, metav1.Condition{
+			Type:               condition.CONDITION_TYPE_GRAFANA_AVAILABLE,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ExternalGrafana",
+			Message:            "The dashboards are provisioned into the external grafana",
+			LastTransitionTime: metav1.Time{Time: time.Now()},
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// update status of the global hub grafana deployment
 	if err := r.updateDeploymentStatus(ctx, mgh, condition.CONDITION_TYPE_GRAFANA_AVAILABLE,
 		operatorconstants.GHGrafanaDeploymentName); err != nil {
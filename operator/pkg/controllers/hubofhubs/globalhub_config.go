@@ -27,6 +27,9 @@ func (r *MulticlusterGlobalHubReconciler) reconcileSystemConfig(
 	// set image pull secret
 	config.SetImagePullSecretName(mgh)
 
+	// watch the configmaps of the trusted CA bundles
+	config.SetTrustedCABundleConfigMaps(mgh)
+
 	// set statistic log interval
 	if err := config.SetStatisticLogInterval(mgh); err != nil {
 		return err
@@ -0,0 +1,246 @@
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+)
+
+const (
+	consolePluginName = "multicluster-global-hub-console-plugin"
+	// the serving certificate of the plugin issued by the service CA if the custom one isn't specified
+	consolePluginCertSecret = "multicluster-global-hub-console-plugin-certs" // #nosec G101
+)
+
+// consoleOperatorGVK is the cluster scoped config of the console operator, the plugins in its spec are loaded by the
+// OpenShift console
+var consoleOperatorGVK = schema.GroupVersionKind{
+	Group:   "operator.openshift.io",
+	Version: "v1",
+	Kind:    "Console",
+}
+
+// consolePluginVariables are the values of the console plugin manifests
+type consolePluginVariables struct {
+	Namespace         string
+	Image             string
+	ImagePullSecret   string
+	ImagePullPolicy   string
+	NodeSelector      map[string]string
+	Tolerations       []corev1.Toleration
+	ManagerAPIURL     string
+	ServingCertSecret string
+	CustomServingCert bool
+	Route             *globalhubv1alpha4.ConsolePluginRouteConfig
+	RouteCertificate  []byte
+	RouteKey          []byte
+	// the route reencrypts the traffic to the plugin, the router only trusts the service CA by default
+	DestinationCACertificate []byte
+}
+
+// reconcileConsolePlugin deploys the OpenShift console plugin of the fleet summary pages and enables it in the
+// console. The pages call the api of the manager through the proxy of the console, which forwards the token of the
+// user, and the plugin passes them to the manager service.
+func (r *MulticlusterGlobalHubReconciler) reconcileConsolePlugin(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	log := r.Log.WithName("console-plugin")
+
+	if !config.IsConsolePluginEnabled(mgh) {
+		return r.pruneConsolePlugin(ctx, mgh)
+	}
+
+	variables, err := r.consolePluginVariables(ctx, mgh)
+	if err != nil {
+		return err
+	}
+	pluginObjects, err := renderConsolePlugin(variables)
+	if err != nil {
+		return err
+	}
+
+	// create restmapper for deployer to find GVR
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	if err = manipulateObj(pluginObjects, mgh, deployer.NewHoHDeployer(r.Client), mapper, r.GetScheme()); err != nil {
+		return fmt.Errorf("failed to create/update the console plugin objects: %w", err)
+	}
+	// the route isn't rendered once it's removed from the operand
+	if mgh.Spec.Components.ConsolePlugin.Route == nil {
+		if err = r.pruneObject(ctx, mgh, consolePluginObject("route.openshift.io", "v1", "Route",
+			mgh.GetNamespace())); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+
+	if err = r.setConsolePluginEnabled(ctx, true); err != nil {
+		return fmt.Errorf("failed to enable the console plugin: %w", err)
+	}
+
+	log.Info("console plugin created/updated successfully")
+	return nil
+}
+
+func (r *MulticlusterGlobalHubReconciler) consolePluginVariables(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) (*consolePluginVariables, error) {
+	pluginConfig := mgh.Spec.Components.ConsolePlugin
+
+	imagePullPolicy := corev1.PullAlways
+	if mgh.Spec.ImagePullPolicy != "" {
+		imagePullPolicy = mgh.Spec.ImagePullPolicy
+	}
+
+	// the manager api is served by the oauth proxy with the global resources, which authenticates the bearer token
+	managerAPIURL := fmt.Sprintf("http://multicluster-global-hub-manager.%s.svc:8080", mgh.GetNamespace())
+	if r.EnableGlobalResource {
+		managerAPIURL = fmt.Sprintf("https://multicluster-global-hub-manager.%s.svc:8443", mgh.GetNamespace())
+	}
+
+	variables := &consolePluginVariables{
+		Namespace:         mgh.GetNamespace(),
+		Image:             config.GetImage(config.ConsolePluginImageKey),
+		ImagePullSecret:   mgh.Spec.ImagePullSecret,
+		ImagePullPolicy:   string(imagePullPolicy),
+		NodeSelector:      mgh.Spec.NodeSelector,
+		Tolerations:       mgh.Spec.Tolerations,
+		ManagerAPIURL:     managerAPIURL,
+		ServingCertSecret: consolePluginCertSecret,
+		Route:             pluginConfig.Route,
+	}
+
+	if pluginConfig.ServingCertSecret != "" {
+		servingCert, err := r.getTLSSecret(ctx, mgh.GetNamespace(), pluginConfig.ServingCertSecret)
+		if err != nil {
+			return nil, err
+		}
+		variables.ServingCertSecret = pluginConfig.ServingCertSecret
+		variables.CustomServingCert = true
+		variables.DestinationCACertificate = servingCert.Data["ca.crt"]
+	}
+
+	if pluginConfig.Route != nil && pluginConfig.Route.CertificateSecret != "" {
+		routeCert, err := r.getTLSSecret(ctx, mgh.GetNamespace(), pluginConfig.Route.CertificateSecret)
+		if err != nil {
+			return nil, err
+		}
+		variables.RouteCertificate = routeCert.Data[corev1.TLSCertKey]
+		variables.RouteKey = routeCert.Data[corev1.TLSPrivateKeyKey]
+	}
+	return variables, nil
+}
+
+// getTLSSecret returns the secret with the tls.crt and tls.key in the namespace of the operand
+func (r *MulticlusterGlobalHubReconciler) getTLSSecret(ctx context.Context, namespace, name string,
+) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get the certificate secret %s: %w", name, err)
+	}
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil, fmt.Errorf("the certificate secret %s must contain the %s and %s", name, corev1.TLSCertKey,
+			corev1.TLSPrivateKeyKey)
+	}
+	return secret, nil
+}
+
+func renderConsolePlugin(variables *consolePluginVariables) ([]*unstructured.Unstructured, error) {
+	pluginObjects, err := renderer.NewHoHRenderer(fs).Render("manifests/console-plugin", "",
+		func(profile string) (interface{}, error) {
+			return variables, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the console plugin manifests: %w", err)
+	}
+	return pluginObjects, nil
+}
+
+// pruneConsolePlugin disables the plugin in the console and removes its objects once it's disabled, or the operand
+// is deleted
+func (r *MulticlusterGlobalHubReconciler) pruneConsolePlugin(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	// the console operator isn't installed, e.g. on the kubernetes
+	if err := r.setConsolePluginEnabled(ctx, false); err != nil && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to disable the console plugin: %w", err)
+	}
+	pluginObjects := []client.Object{
+		consolePluginObject("console.openshift.io", "v1", "ConsolePlugin", ""),
+		consolePluginObject("route.openshift.io", "v1", "Route", mgh.GetNamespace()),
+		consolePluginObject("apps", "v1", "Deployment", mgh.GetNamespace()),
+		consolePluginObject("", "v1", "Service", mgh.GetNamespace()),
+		consolePluginObject("", "v1", "ConfigMap", mgh.GetNamespace()),
+	}
+	for _, obj := range pluginObjects {
+		if err := r.pruneObject(ctx, mgh, obj); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func consolePluginObject(group, version, kind, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: group, Version: version, Kind: kind})
+	obj.SetName(consolePluginName)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+// setConsolePluginEnabled adds the plugin to the plugins of the console operator config, or removes it from them
+func (r *MulticlusterGlobalHubReconciler) setConsolePluginEnabled(ctx context.Context, enabled bool) error {
+	console := &unstructured.Unstructured{}
+	console.SetGroupVersionKind(consoleOperatorGVK)
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: "cluster"}, console); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	plugins, _, err := unstructured.NestedStringSlice(console.Object, "spec", "plugins")
+	if err != nil {
+		return err
+	}
+
+	updated := updatePlugins(plugins, consolePluginName, enabled)
+	if len(updated) == len(plugins) {
+		return nil
+	}
+	patch := client.MergeFrom(console.DeepCopy())
+	if err = unstructured.SetNestedStringSlice(console.Object, updated, "spec", "plugins"); err != nil {
+		return err
+	}
+	return r.Client.Patch(ctx, console, patch)
+}
+
+// updatePlugins returns the plugins with or without the plugin
+func updatePlugins(plugins []string, plugin string, enabled bool) []string {
+	updated := []string{}
+	for _, p := range plugins {
+		if p != plugin {
+			updated = append(updated, p)
+		}
+	}
+	if enabled {
+		updated = append(updated, plugin)
+	}
+	return updated
+}
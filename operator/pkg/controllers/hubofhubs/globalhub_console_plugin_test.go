@@ -0,0 +1,181 @@
+package hubofhubs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+)
+
+func Test_renderConsolePlugin(t *testing.T) {
+	tlsSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"}, Data: data}
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		tlsSecret("plugin-cert", map[string][]byte{
+			"tls.crt": []byte("crt"), "tls.key": []byte("key"), "ca.crt": []byte("ca"),
+		}),
+		tlsSecret("route-cert", map[string][]byte{"tls.crt": []byte("route\ncrt"), "tls.key": []byte("route\nkey")}),
+		tlsSecret("invalid-cert", map[string][]byte{"tls.crt": []byte("crt")}),
+	).Build()
+
+	tests := []struct {
+		name                 string
+		enableGlobalResource bool
+		pluginConfig         *globalhubv1alpha4.ConsolePluginConfig
+		wantErr              bool
+		check                func(t *testing.T, objects map[string]*unstructured.Unstructured)
+	}{
+		{
+			name:         "default",
+			pluginConfig: &globalhubv1alpha4.ConsolePluginConfig{Enabled: true},
+			check: func(t *testing.T, objects map[string]*unstructured.Unstructured) {
+				assert.Len(t, objects, 4)
+				assert.NotContains(t, objects, "Route")
+				// the serving certificate is issued by the service CA
+				annotations := objects["Service"].GetAnnotations()
+				assert.Equal(t, consolePluginCertSecret, annotations["service.beta.openshift.io/serving-cert-secret-name"])
+				nginxConf, _, _ := unstructured.NestedString(objects["ConfigMap"].Object, "data", "nginx.conf")
+				assert.Contains(t, nginxConf, "proxy_pass http://multicluster-global-hub-manager.test-ns.svc:8080;")
+				// the console plugin is cluster scoped
+				assert.Empty(t, objects["ConsolePlugin"].GetNamespace())
+				proxies, _, _ := unstructured.NestedSlice(objects["ConsolePlugin"].Object, "spec", "proxy")
+				require.Len(t, proxies, 1)
+				assert.Equal(t, "UserToken", proxies[0].(map[string]interface{})["authorization"])
+			},
+		},
+		{
+			name:                 "custom serving certificate and route",
+			enableGlobalResource: true,
+			pluginConfig: &globalhubv1alpha4.ConsolePluginConfig{
+				Enabled:           true,
+				ServingCertSecret: "plugin-cert",
+				Route: &globalhubv1alpha4.ConsolePluginRouteConfig{
+					Host:              "plugin.example.com",
+					CertificateSecret: "route-cert",
+				},
+			},
+			check: func(t *testing.T, objects map[string]*unstructured.Unstructured) {
+				assert.Len(t, objects, 5)
+				assert.Empty(t, objects["Service"].GetAnnotations())
+				nginxConf, _, _ := unstructured.NestedString(objects["ConfigMap"].Object, "data", "nginx.conf")
+				assert.Contains(t, nginxConf, "proxy_pass https://multicluster-global-hub-manager.test-ns.svc:8443;")
+				volumes, _, _ := unstructured.NestedSlice(objects["Deployment"].Object,
+					"spec", "template", "spec", "volumes")
+				secretName, _, _ := unstructured.NestedString(volumes[0].(map[string]interface{}),
+					"secret", "secretName")
+				assert.Equal(t, "plugin-cert", secretName)
+
+				route := objects["Route"]
+				require.NotNil(t, route)
+				host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+				assert.Equal(t, "plugin.example.com", host)
+				tls, _, _ := unstructured.NestedStringMap(route.Object, "spec", "tls")
+				assert.Equal(t, "route\ncrt\n", tls["certificate"])
+				assert.Equal(t, "route\nkey\n", tls["key"])
+				assert.Equal(t, "ca\n", tls["destinationCACertificate"])
+			},
+		},
+		{
+			name: "route with the default certificate",
+			pluginConfig: &globalhubv1alpha4.ConsolePluginConfig{
+				Enabled: true,
+				Route:   &globalhubv1alpha4.ConsolePluginRouteConfig{},
+			},
+			check: func(t *testing.T, objects map[string]*unstructured.Unstructured) {
+				route := objects["Route"]
+				require.NotNil(t, route)
+				_, found, _ := unstructured.NestedString(route.Object, "spec", "host")
+				assert.False(t, found)
+				tls, _, _ := unstructured.NestedStringMap(route.Object, "spec", "tls")
+				assert.Equal(t, map[string]string{
+					"insecureEdgeTerminationPolicy": "Redirect",
+					"termination":                   "reencrypt",
+				}, tls)
+			},
+		},
+		{
+			name: "invalid serving certificate",
+			pluginConfig: &globalhubv1alpha4.ConsolePluginConfig{
+				Enabled:           true,
+				ServingCertSecret: "invalid-cert",
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing route certificate",
+			pluginConfig: &globalhubv1alpha4.ConsolePluginConfig{
+				Enabled: true,
+				Route:   &globalhubv1alpha4.ConsolePluginRouteConfig{CertificateSecret: "missing"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-mgh", Namespace: "test-ns"},
+				Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{
+					Components: &globalhubv1alpha4.ComponentsConfig{ConsolePlugin: tt.pluginConfig},
+				},
+			}
+			r := &MulticlusterGlobalHubReconciler{
+				Client:               fakeClient,
+				Scheme:               scheme.Scheme,
+				EnableGlobalResource: tt.enableGlobalResource,
+			}
+			variables, err := r.consolePluginVariables(context.Background(), mgh)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.wantErr {
+				return
+			}
+			pluginObjects, err := renderConsolePlugin(variables)
+			require.NoError(t, err)
+			objects := map[string]*unstructured.Unstructured{}
+			for _, obj := range pluginObjects {
+				assert.Equal(t, consolePluginName, obj.GetName())
+				objects[obj.GetKind()] = obj
+			}
+			tt.check(t, objects)
+		})
+	}
+}
+
+func Test_setConsolePluginEnabled(t *testing.T) {
+	console := &unstructured.Unstructured{}
+	console.SetGroupVersionKind(consoleOperatorGVK)
+	console.SetName("cluster")
+	assert.NoError(t, unstructured.SetNestedStringSlice(console.Object, []string{"other-plugin"}, "spec", "plugins"))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(console).Build()
+	r := &MulticlusterGlobalHubReconciler{Client: fakeClient, Scheme: scheme.Scheme}
+	ctx := context.Background()
+
+	getPlugins := func() []string {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(consoleOperatorGVK)
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Name: "cluster"}, current))
+		plugins, _, _ := unstructured.NestedStringSlice(current.Object, "spec", "plugins")
+		return plugins
+	}
+
+	require.NoError(t, r.setConsolePluginEnabled(ctx, true))
+	assert.Equal(t, []string{"other-plugin", consolePluginName}, getPlugins())
+
+	// it's idempotent
+	require.NoError(t, r.setConsolePluginEnabled(ctx, true))
+	assert.Equal(t, []string{"other-plugin", consolePluginName}, getPlugins())
+
+	// the other plugins are kept once it's disabled
+	require.NoError(t, r.setConsolePluginEnabled(ctx, false))
+	assert.Equal(t, []string{"other-plugin"}, getPlugins())
+}
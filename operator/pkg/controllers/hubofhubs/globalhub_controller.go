@@ -49,6 +49,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	// pmcontroller "github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/packagemanifest"
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
@@ -56,7 +57,6 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/postgres"
-	transportprotocol "github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
@@ -67,19 +67,19 @@ import (
 var fs embed.FS
 
 // var isPackageManifestControllerRunnning = false
+// the secrets of the built-in kafka users and the storage are watched by the KafkaController and StorageController
 var watchedSecret = sets.NewString(
 	constants.GHTransportSecretName,
-	constants.GHStorageSecretName,
-	constants.GHBuiltInStorageSecretName,
-	postgres.PostgresCertName,
+	constants.GHArchiveSecretName,
+	constants.GHInventorySecretName,
 	constants.CustomGrafanaIniName,
 	config.GetImagePullSecretName(),
-	transportprotocol.DefaultGlobalHubKafkaUser,
 )
 
 var watchedConfigmap = sets.NewString(
 	constants.PostgresCAConfigMap,
 	constants.CustomAlertName,
+	constants.GHNotificationConfigMapName,
 )
 
 // MulticlusterGlobalHubReconciler reconciles a MulticlusterGlobalHub object
@@ -97,6 +97,9 @@ type MulticlusterGlobalHubReconciler struct {
 	upgradeOnce          sync.Once
 	KafkaInit            bool
 	KafkaController      *KafkaController
+	StorageController    *StorageController
+	// middlewareEvents enqueues the mgh once the middleware controllers update the connections
+	middlewareEvents chan event.GenericEvent
 }
 
 // MiddlewareConfig defines the configuration for middleware and shared in opearator
@@ -119,27 +122,36 @@ type MiddlewareConfig struct {
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placements,verbs=get;list;patch;update
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersets,verbs=get;list;patch;update
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;update
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=clusterclaims,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;delete;patch
-// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;delete;patch
+// +kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch;create;update;delete;patch
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterroles,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterrolebindings,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:urls=/metrics,verbs=get
+// +kubebuilder:rbac:urls=/global-hub-api/*,verbs=get;post;put;patch;delete
 // +kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=mutatingwebhookconfigurations,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=clustermanagementaddons,verbs=create;delete;get;list;update;watch
 // +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=clustermanagementaddons/finalizers,verbs=update
 // +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterhubs,verbs=get;list;patch;update;watch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;prometheusrules;podmonitors,verbs=get;create;delete;update;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;delete;deletecollection
 // +kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions,verbs=get;create;delete;update;list;watch
-// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=get;create;list;watch
-// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkas;kafkatopics;kafkausers,verbs=get;create;list;watch;update;delete
+// +kubebuilder:rbac:groups=postgres-operator.crunchydata.com,resources=postgresclusters,verbs=get;create;list;watch;update
+// +kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanadatasources;grafanadashboards,verbs=get;create;delete;update;list;watch
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkas;kafkatopics;kafkausers;kafkaconnects;kafkaconnectors;kafkamirrormaker2s,verbs=get;create;list;watch;update;delete
+// +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=get;create;list;watch;update;delete
+// +kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=get;create;list;watch;update;delete
+// +kubebuilder:rbac:groups=operator.openshift.io,resources=consoles,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -270,8 +282,9 @@ func (r *MulticlusterGlobalHubReconciler) reconcileGlobalHub(ctx context.Context
 	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
 ) error {
 	// add addon.open-cluster-management.io/on-multicluster-hub annotation to the managed hub
-	// clusters indicate the addons are running on a hub cluster
-	if err := r.reconcileManagedHubs(ctx); err != nil {
+	// clusters indicate the addons are running on a hub cluster, the clusters looping the events back
+	// to the global hub are skipped and reported in the condition
+	if err := r.reconcileManagedHubs(ctx, mgh); err != nil {
 		return err
 	}
 
@@ -284,6 +297,21 @@ func (r *MulticlusterGlobalHubReconciler) reconcileGlobalHub(ctx context.Context
 		return err
 	}
 
+	// run the migrations of the operator upgrade, the manager isn't rolled out until they're completed
+	if err := r.reconcileMigration(ctx, mgh); err != nil {
+		return err
+	}
+
+	// replicate the database and mirror the topics from the primary on the standby, or promote it
+	if err := r.reconcileDisasterRecovery(ctx, mgh); err != nil {
+		return err
+	}
+
+	// register the regional kafka clusters, which the agents of the regions connect to and the manager consumes
+	if err := r.reconcileRegionalTransport(ctx, mgh); err != nil {
+		return err
+	}
+
 	// reconcile manager
 	if err := r.reconcileManager(ctx, mgh); err != nil {
 		return err
@@ -299,6 +327,21 @@ func (r *MulticlusterGlobalHubReconciler) reconcileGlobalHub(ctx context.Context
 		return err
 	}
 
+	// reconcile network policies
+	if err := r.reconcileNetworkPolicy(ctx, mgh); err != nil {
+		return err
+	}
+
+	// reconcile the change data capture of the database
+	if err := r.reconcileChangeDataCapture(ctx, mgh); err != nil {
+		return err
+	}
+
+	// reconcile the console plugin of the fleet summary pages
+	if err := r.reconcileConsolePlugin(ctx, mgh); err != nil {
+		return err
+	}
+
 	// reconcile addon
 	r.Log.Info("trigger addon on managed clusters", "size", len(config.GetManagedClusters()))
 	for _, clusterName := range config.GetManagedClusters() {
@@ -358,14 +401,7 @@ var resPred = predicate.Funcs{
 }
 
 var secretCond = func(obj client.Object) bool {
-	if watchedSecret.Has(obj.GetName()) {
-		return true
-	}
-	if obj.GetLabels()["strimzi.io/cluster"] == transportprotocol.KafkaClusterName &&
-		obj.GetLabels()["strimzi.io/kind"] == "KafkaUser" {
-		return true
-	}
-	return false
+	return watchedSecret.Has(obj.GetName()) || obj.GetLabels()[operatorconstants.GHTransportRegionLabelKey] != ""
 }
 
 var secretPred = predicate.Funcs{
@@ -394,24 +430,35 @@ var deletePred = predicate.Funcs{
 
 var configmappred = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
-		return watchedConfigmap.Has(e.Object.GetName())
+		return watchedConfigmap.Has(e.Object.GetName()) || isCustomDashboard(e.Object) ||
+			config.IsTrustedCABundleConfigMap(e.Object.GetName())
 	},
 	UpdateFunc: func(e event.UpdateEvent) bool {
 		if e.ObjectNew.GetLabels()[constants.GlobalHubOwnerLabelKey] ==
 			constants.GHOperatorOwnerLabelVal {
 			return true
 		}
-		return watchedConfigmap.Has(e.ObjectNew.GetName())
+		// the custom dashboard is added or removed by the label
+		if isCustomDashboard(e.ObjectNew) != isCustomDashboard(e.ObjectOld) {
+			return true
+		}
+		return watchedConfigmap.Has(e.ObjectNew.GetName()) || config.IsTrustedCABundleConfigMap(e.ObjectNew.GetName())
 	},
 	DeleteFunc: func(e event.DeleteEvent) bool {
 		if e.Object.GetLabels()[constants.GlobalHubOwnerLabelKey] ==
 			constants.GHOperatorOwnerLabelVal {
 			return true
 		}
-		return watchedConfigmap.Has(e.Object.GetName())
+		return watchedConfigmap.Has(e.Object.GetName()) || isCustomDashboard(e.Object) ||
+			config.IsTrustedCABundleConfigMap(e.Object.GetName())
 	},
 }
 
+func isCustomDashboard(obj client.Object) bool {
+	_, found := obj.GetLabels()[operatorconstants.GHGrafanaDashboardLabelKey]
+	return found
+}
+
 var mhPred = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
 		return true
@@ -473,8 +520,27 @@ var globalHubEventHandler = handler.EnqueueRequestsFromMapFunc(
 	},
 )
 
+// triggerReconcile enqueues the mgh without blocking, the event is dropped if there is already one pending
+func (r *MulticlusterGlobalHubReconciler) triggerReconcile() {
+	if r.middlewareEvents == nil {
+		return
+	}
+	mghNamespacedName := config.GetMGHNamespacedName()
+	select {
+	case r.middlewareEvents <- event.GenericEvent{Object: &globalhubv1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{Namespace: mghNamespacedName.Namespace, Name: mghNamespacedName.Name},
+	}}:
+	default:
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MulticlusterGlobalHubReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the job status is synced by the leader only, since the runnable func requires the leader election
+	if err := mgr.Add(manager.RunnableFunc(r.syncJobStatus)); err != nil {
+		return err
+	}
+	r.middlewareEvents = make(chan event.GenericEvent, 1)
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&globalhubv1alpha4.MulticlusterGlobalHub{}, builder.WithPredicates(mghPred)).
 		Owns(&appsv1.Deployment{}, builder.WithPredicates(ownPred)).
@@ -510,5 +576,7 @@ func (r *MulticlusterGlobalHubReconciler) SetupWithManager(mgr ctrl.Manager) err
 			globalHubEventHandler, builder.WithPredicates(deletePred)).
 		Watches(&clusterv1.ManagedCluster{},
 			globalHubEventHandler, builder.WithPredicates(mhPred)).
+		// the connections are updated by the kafka and storage controllers
+		WatchesRawSource(&source.Channel{Source: r.middlewareEvents}, globalHubEventHandler).
 		Complete(r)
 }
@@ -27,11 +27,6 @@ var databaseFS embed.FS
 //go:embed database.old
 var databaseOldFS embed.FS
 
-//go:embed upgrade
-var upgradeFS embed.FS
-
-var upgraded = false
-
 func (r *MulticlusterGlobalHubReconciler) ReconcileDatabase(ctx context.Context,
 	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
 ) error {
@@ -67,7 +62,8 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileDatabase(ctx context.Context,
 		return err
 	}
 
-	if backupEnabled || !upgraded {
+	// lock the database on the first reconcile, the other operator instance may still run the migrations
+	if backupEnabled || DatabaseReconcileCounter == 0 {
 		lockSql := fmt.Sprintf("select pg_advisory_lock(%s)", constants.LockId)
 		unLockSql := fmt.Sprintf("select pg_advisory_unlock(%s)", constants.LockId)
 		defer func() {
@@ -103,15 +99,6 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileDatabase(ctx context.Context,
 		}
 	}
 
-	if !upgraded {
-		err := applySQL(ctx, conn, upgradeFS, "upgrade", readonlyUsername)
-		if err != nil {
-			log.Error(err, "failed to exec the upgrade sql files")
-			return err
-		}
-		upgraded = true
-	}
-
 	log.V(7).Info("database initialized")
 	DatabaseReconcileCounter++
 	err = condition.SetConditionDatabaseInit(ctx, r.Client, mgh, condition.CONDITION_STATUS_TRUE)
@@ -0,0 +1,402 @@
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// the name of the publication in the primary database, and the subscription and its replication slot in the
+	// standby database
+	standbyReplicationName = "globalhub_standby"
+	standbyMirrorMakerName = "multicluster-global-hub-standby"
+	standbyKafkaUser       = "global-hub-standby-user"
+	// standbyConsumerGroup is the consumer group of the manager, its committed offsets are translated into the standby
+	standbyConsumerGroup = "multicluster-global-hub-manager"
+	// the checkpoints of the consumer groups are emitted to the topic named by the alias of the primary kafka
+	standbyCheckpointsTopic = "primary.checkpoints.internal"
+)
+
+// standbyDB is the connection of the standby database, which runs the statements of the subscription and the
+// promotion
+type standbyDB interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// kafkaACLResource is the topic or the group in the ACLs of the kafka user, the patternType is literal or prefix
+type kafkaACLResource struct {
+	Name        string
+	PatternType string
+}
+
+// createPublicationSQL publishes all the tables of the primary database, the changes of the partitions are published
+// as the partitioned tables, so they're routed to the partitions of the standby
+var createPublicationSQL = fmt.Sprintf(`DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_publication WHERE pubname = '%[1]s') THEN
+    CREATE PUBLICATION %[1]s FOR ALL TABLES WITH (publish_via_partition_root = true);
+  END IF;
+END $$;`, standbyReplicationName)
+
+// syncSequencesSQL moves the sequences of the columns past the rows replicated from the primary, since the logical
+// replication doesn't replicate the sequences
+const syncSequencesSQL = `DO $$
+DECLARE
+  seq record;
+BEGIN
+  FOR seq IN
+    SELECT s.oid::regclass AS seq_name, a.attrelid::regclass AS table_name, a.attname AS column_name
+    FROM pg_class s
+    JOIN pg_depend d ON d.objid = s.oid AND d.deptype IN ('a', 'i')
+    JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+    WHERE s.relkind = 'S'
+  LOOP
+    EXECUTE format('SELECT setval(%L, COALESCE((SELECT max(%I) FROM %s), 0) + 1, false)',
+      seq.seq_name, seq.column_name, seq.table_name);
+  END LOOP;
+END $$;`
+
+// reconcileDisasterRecovery replicates the database and mirrors the kafka topics from the primary global hub on the
+// standby. Once the standby is promoted by changing its role to Primary, the subscription to the primary database is
+// dropped and the mirror maker is removed, then the manager is rolled out to take over the managed hubs.
+func (r *MulticlusterGlobalHubReconciler) reconcileDisasterRecovery(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	role := config.GetDisasterRecoveryRole(mgh)
+	if role == "" && mgh.Status.DisasterRecovery == nil {
+		return nil
+	}
+	if r.MiddlewareConfig == nil || r.MiddlewareConfig.StorageConn == nil {
+		return fmt.Errorf("middleware PgConnection config is null")
+	}
+	conn, err := database.PostgresConnection(ctx, r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI,
+		r.MiddlewareConfig.StorageConn.CACert)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(ctx); err != nil {
+			r.Log.Error(err, "failed to close connection to database")
+		}
+	}()
+
+	if role == globalhubv1alpha4.DisasterRecoveryStandby {
+		if err := r.reconcileStandby(ctx, mgh, conn); err != nil {
+			if e := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_STANDBY_REPLICATING,
+				condition.CONDITION_STATUS_FALSE, condition.CONDITION_REASON_STANDBY_FAILED, err.Error()); e != nil {
+				return condition.FailToSetConditionError(condition.CONDITION_STATUS_FALSE, e)
+			}
+			return err
+		}
+		if err := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_STANDBY_REPLICATING,
+			condition.CONDITION_STATUS_TRUE, condition.CONDITION_REASON_STANDBY_REPLICATING,
+			condition.CONDITION_MESSAGE_STANDBY_REPLICATING); err != nil {
+			return condition.FailToSetConditionError(condition.CONDITION_STATUS_TRUE, err)
+		}
+		return r.updateDisasterRecoveryStatus(ctx, mgh, role, false)
+	}
+
+	// the standby is promoted once its role is changed to Primary or the disaster recovery is removed
+	promoted, err := promoteStandbyDatabase(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if promoted {
+		config.RecordAction(mgh, config.ReasonStandbyPromoted,
+			"Promoted the standby global hub to the primary", "namespace", mgh.Namespace, "name", mgh.Name)
+	}
+	if err := r.pruneStandbyMirror(ctx, mgh); err != nil {
+		return err
+	}
+	if condition.ContainsCondition(mgh, condition.CONDITION_TYPE_STANDBY_REPLICATING) {
+		if err := condition.DeleteCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_STANDBY_REPLICATING,
+			condition.CONDITION_REASON_STANDBY_REPLICATING); err != nil {
+			return err
+		}
+	}
+	if role == globalhubv1alpha4.DisasterRecoveryPrimary {
+		if _, err := conn.Exec(ctx, createPublicationSQL); err != nil {
+			return fmt.Errorf("failed to create the publication for the standby: %w", err)
+		}
+	}
+	return r.updateDisasterRecoveryStatus(ctx, mgh, role, promoted)
+}
+
+// reconcileStandby subscribes to the primary database and mirrors the topics of the primary kafka
+func (r *MulticlusterGlobalHubReconciler) reconcileStandby(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, conn *pgx.Conn,
+) error {
+	secretName := mgh.Spec.DisasterRecovery.PrimaryPostgresSecret
+	if secretName == "" {
+		return fmt.Errorf("the primaryPostgresSecret is required by the standby")
+	}
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: mgh.Namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get the primary postgres secret %s: %w", secretName, err)
+	}
+	primaryURI := string(secret.Data["database_uri"])
+	if primaryURI == "" {
+		return fmt.Errorf("the database_uri isn't found in the primary postgres secret %s", secretName)
+	}
+	if err := ensureStandbySubscription(ctx, conn, primaryURI); err != nil {
+		return err
+	}
+	return r.reconcileStandbyMirror(ctx, mgh)
+}
+
+// ensureStandbySubscription subscribes to the publication of the primary database, the existing rows are copied
+// before the changes are streamed. The connection of the subscription is updated if the primary is changed.
+func ensureStandbySubscription(ctx context.Context, conn standbyDB, primaryURI string) error {
+	var connInfo string
+	var enabled bool
+	err := conn.QueryRow(ctx, "SELECT subconninfo, subenabled FROM pg_subscription WHERE subname = $1",
+		standbyReplicationName).Scan(&connInfo, &enabled)
+	if err == pgx.ErrNoRows {
+		_, err = conn.Exec(ctx, fmt.Sprintf("CREATE SUBSCRIPTION %s CONNECTION %s PUBLICATION %s WITH (copy_data = true)",
+			standbyReplicationName, quoteLiteral(primaryURI), standbyReplicationName))
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to the primary database: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the subscription to the primary database: %w", err)
+	}
+
+	if connInfo != primaryURI {
+		_, err = conn.Exec(ctx, fmt.Sprintf("ALTER SUBSCRIPTION %s CONNECTION %s", standbyReplicationName,
+			quoteLiteral(primaryURI)))
+		if err != nil {
+			return fmt.Errorf("failed to update the connection of the subscription: %w", err)
+		}
+	}
+	if !enabled {
+		if _, err = conn.Exec(ctx, fmt.Sprintf("ALTER SUBSCRIPTION %s ENABLE", standbyReplicationName)); err != nil {
+			return fmt.Errorf("failed to enable the subscription to the primary database: %w", err)
+		}
+	}
+	return nil
+}
+
+// promoteStandbyDatabase drops the subscription to the primary database without connecting to the primary, which may
+// be lost in the disaster, so the replication slot is left in the primary. It returns false if the database doesn't
+// subscribe to the primary.
+func promoteStandbyDatabase(ctx context.Context, conn standbyDB) (bool, error) {
+	var exists bool
+	err := conn.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM pg_subscription WHERE subname = $1)",
+		standbyReplicationName).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the subscription to the primary database: %w", err)
+	}
+	if !exists {
+		return false, nil
+	}
+	// the sequences are synced after the replication is stopped, and the statements are rerun if any of them fails
+	for _, sql := range []string{
+		fmt.Sprintf("ALTER SUBSCRIPTION %s DISABLE", standbyReplicationName),
+		syncSequencesSQL,
+		fmt.Sprintf("ALTER SUBSCRIPTION %s SET (slot_name = NONE)", standbyReplicationName),
+		fmt.Sprintf("DROP SUBSCRIPTION %s", standbyReplicationName),
+	} {
+		if _, err := conn.Exec(ctx, sql); err != nil {
+			return false, fmt.Errorf("failed to promote the standby database: %w", err)
+		}
+	}
+	return true, nil
+}
+
+// reconcileStandbyMirror renders the KafkaMirrorMaker2 which mirrors the topics of the primary kafka into the built-in
+// kafka of the standby. It's skipped with the BYO kafka, which is mirrored by the owner of the kafka.
+func (r *MulticlusterGlobalHubReconciler) reconcileStandbyMirror(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	transProtocol, err := detectTransportProtocol(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	if transProtocol != transport.StrimziTransporter || mgh.Spec.DisasterRecovery.PrimaryKafkaSecret == "" {
+		r.Log.Info("the topics of the primary aren't mirrored by the standby",
+			"builtInKafka", transProtocol == transport.StrimziTransporter)
+		return r.pruneStandbyMirror(ctx, mgh)
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Client.Get(ctx, types.NamespacedName{
+		Name:      mgh.Spec.DisasterRecovery.PrimaryKafkaSecret,
+		Namespace: mgh.Namespace,
+	}, secret)
+	if err != nil {
+		return fmt.Errorf("failed to get the primary kafka secret: %w", err)
+	}
+	bootstrapServer := string(secret.Data["bootstrap_server"])
+	if bootstrapServer == "" {
+		return fmt.Errorf("the bootstrap_server isn't found in the primary kafka secret %s", secret.Name)
+	}
+
+	topic := config.GetTransporter().GenerateClusterTopic(transporter.GlobalHubClusterName)
+	mirrorObjects, err := r.renderStandbyMirror(mgh, bootstrapServer,
+		standbyTopicsPattern(topic.SpecTopic, topic.StatusTopic, topic.EventTopic),
+		standbyTopicACLs(topic.SpecTopic, topic.StatusTopic, topic.EventTopic))
+	if err != nil {
+		return err
+	}
+
+	// create restmapper for deployer to find GVR
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	if err = manipulateObj(mirrorObjects, mgh, deployer.NewHoHDeployer(r.Client), mapper, r.GetScheme()); err != nil {
+		return fmt.Errorf("failed to create/update the standby mirror objects: %w", err)
+	}
+	return nil
+}
+
+func (r *MulticlusterGlobalHubReconciler) renderStandbyMirror(mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+	bootstrapServer, topicsPattern string, mirroredTopics []kafkaACLResource,
+) ([]*unstructured.Unstructured, error) {
+	primaryKafkaSecret := ""
+	if mgh.Spec.DisasterRecovery != nil {
+		primaryKafkaSecret = mgh.Spec.DisasterRecovery.PrimaryKafkaSecret
+	}
+	mirrorRenderer := renderer.NewHoHRenderer(fs)
+	mirrorObjects, err := mirrorRenderer.Render("manifests/standby", "", func(profile string) (interface{}, error) {
+		return struct {
+			Namespace              string
+			MirrorMakerName        string
+			KafkaCluster           string
+			KafkaVersion           string
+			KafkaUser              string
+			PrimaryBootstrapServer string
+			PrimaryKafkaSecret     string
+			TopicsPattern          string
+			MirroredTopics         []kafkaACLResource
+			ConsumerGroup          string
+			CheckpointsTopic       string
+			ImagePullSecret        string
+		}{
+			Namespace:              mgh.GetNamespace(),
+			MirrorMakerName:        standbyMirrorMakerName,
+			KafkaCluster:           transporter.KafkaClusterName,
+			KafkaVersion:           transporter.KafkaVersion,
+			KafkaUser:              standbyKafkaUser,
+			PrimaryBootstrapServer: bootstrapServer,
+			PrimaryKafkaSecret:     primaryKafkaSecret,
+			TopicsPattern:          topicsPattern,
+			MirroredTopics:         mirroredTopics,
+			ConsumerGroup:          standbyConsumerGroup,
+			CheckpointsTopic:       standbyCheckpointsTopic,
+			ImagePullSecret:        mgh.Spec.ImagePullSecret,
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the standby mirror manifests: %w", err)
+	}
+	return mirrorObjects, nil
+}
+
+// pruneStandbyMirror removes the mirror maker and its kafka user once the standby is promoted
+func (r *MulticlusterGlobalHubReconciler) pruneStandbyMirror(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	mirrorObjects, err := r.renderStandbyMirror(mgh, "", "", nil)
+	if err != nil {
+		return err
+	}
+	for _, obj := range mirrorObjects {
+		// the strimzi api isn't installed with the BYO kafka
+		if err := r.pruneObject(ctx, mgh, obj); err != nil && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateDisasterRecoveryStatus records the role of the global hub, and the time if it's just promoted
+func (r *MulticlusterGlobalHubReconciler) updateDisasterRecoveryStatus(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, role globalhubv1alpha4.DisasterRecoveryRole, promoted bool,
+) error {
+	var status *globalhubv1alpha4.DisasterRecoveryStatus
+	if role != "" {
+		status = &globalhubv1alpha4.DisasterRecoveryStatus{Role: role}
+		if mgh.Status.DisasterRecovery != nil {
+			status.PromotedTime = mgh.Status.DisasterRecovery.PromotedTime
+		}
+		if promoted {
+			now := metav1.Now()
+			status.PromotedTime = &now
+		}
+	}
+	if equality.Semantic.DeepEqual(status, mgh.Status.DisasterRecovery) {
+		return nil
+	}
+	mgh.Status.DisasterRecovery = status
+	if err := r.Client.Status().Update(ctx, mgh); err != nil {
+		return fmt.Errorf("failed to update the disaster recovery status: %w", err)
+	}
+	return nil
+}
+
+// standbyTopicsPattern returns the regex of the topics mirrored from the primary, the status topic is either the
+// regex of the status topics per hub or the shared status topic
+func standbyTopicsPattern(specTopic, statusTopic, eventTopic string) string {
+	patterns := []string{regexp.QuoteMeta(specTopic)}
+	if statusTopic == transporter.StatusTopicRegex {
+		patterns = append(patterns, strings.TrimPrefix(statusTopic, "^"))
+	} else {
+		patterns = append(patterns, regexp.QuoteMeta(statusTopic))
+	}
+	if eventTopic != "" {
+		patterns = append(patterns, regexp.QuoteMeta(eventTopic))
+	}
+	return strings.Join(patterns, "|")
+}
+
+// standbyTopicACLs returns the mirrored topics in the ACLs of the standby kafka user, the status topics per hub are
+// granted by their prefix
+func standbyTopicACLs(specTopic, statusTopic, eventTopic string) []kafkaACLResource {
+	topics := []kafkaACLResource{{Name: specTopic, PatternType: "literal"}}
+	if statusTopic == transporter.StatusTopicRegex {
+		topics = append(topics, kafkaACLResource{
+			Name:        strings.TrimSuffix(strings.TrimPrefix(statusTopic, "^"), ".*"),
+			PatternType: "prefix",
+		})
+	} else {
+		topics = append(topics, kafkaACLResource{Name: statusTopic, PatternType: "literal"})
+	}
+	if eventTopic != "" {
+		topics = append(topics, kafkaACLResource{Name: eventTopic, PatternType: "literal"})
+	}
+	return topics
+}
+
+// quoteLiteral quotes the string as the SQL literal, the DDL statements don't accept the parameters
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
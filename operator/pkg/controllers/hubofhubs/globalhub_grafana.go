@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
@@ -46,6 +48,14 @@ const (
 	grafanaIniKey         = "grafana.ini"
 
 	grafanaDeploymentName = "multicluster-global-hub-grafana"
+
+	// the uid of the provisioned global hub datasource, the built-in dashboards refer to the datasource by it
+	globalHubDatasourceUID = "P244538DD76A4C61D"
+
+	// the thanos query frontend of the ACM observability, it's provisioned as the datasource if it's installed
+	observabilityNamespace     = "open-cluster-management-observability"
+	observabilityQueryService  = "observability-thanos-query-frontend"
+	observabilityDatasourceUID = "global-hub-observability"
 )
 
 var (
@@ -68,12 +78,72 @@ var (
 func (r *MulticlusterGlobalHubReconciler) reconcileGrafana(ctx context.Context,
 	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
 ) error {
+	if !config.IsGrafanaEnabled(mgh) {
+		return r.reconcileDatasourceExport(ctx, mgh)
+	}
+	if mgh.Spec.ExternalGrafana != nil {
+		return r.reconcileExternalGrafana(ctx, mgh)
+	}
+
 	log := r.Log.WithName("grafana")
 
+	grafanaObjects, err := r.renderGrafanaObjects(ctx, mgh)
+	if err != nil {
+		return fmt.Errorf("failed to render grafana manifests: %w", err)
+	}
+	grafanaDeployer := deployer.NewHoHDeployer(r.Client)
+
+	// create restmapper for deployer to find GVR
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	if err = manipulateObj(grafanaObjects, mgh, grafanaDeployer, mapper, r.GetScheme()); err != nil {
+		return fmt.Errorf("failed to create/update grafana objects: %w", err)
+	}
+
+	// generate datasource secret: must before the grafana objects
+	changedDatasourceSecret, err := r.GenerateGrafanaDataSourceSecret(ctx, mgh)
+	if err != nil {
+		return fmt.Errorf("failed to generate grafana datasource secret: %v", err)
+	}
+
+	changedAlert, err := r.generateAlertConfigMap(ctx, mgh)
+	if err != nil {
+		return fmt.Errorf("failed to generate merged alert configmap. err:%v", err)
+	}
+
+	changedGrafanaIni, err := r.generateGrafanaIni(ctx, mgh)
+	if err != nil {
+		return fmt.Errorf("failed to generate grafana init. err:%v", err)
+	}
+
+	changedLDAPSecret, err := r.generateGrafanaLDAPSecret(ctx, mgh)
+	if err != nil {
+		return fmt.Errorf("failed to generate grafana ldap secret. err:%v", err)
+	}
+
+	if changedAlert || changedGrafanaIni || changedDatasourceSecret || changedLDAPSecret {
+		err = utils.RestartPod(ctx, r.KubeClient, utils.GetDefaultNamespace(), grafanaDeploymentName)
+		if err != nil {
+			return fmt.Errorf("failed to restart grafana pod. err:%v", err)
+		}
+	}
+
+	log.Info("grafana objects created/updated successfully")
+	return nil
+}
+
+// renderGrafanaObjects renders the grafana manifests with the custom dashboards and the options of the mgh
+func (r *MulticlusterGlobalHubReconciler) renderGrafanaObjects(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) ([]*unstructured.Unstructured, error) {
 	// generate random session secret for oauth-proxy
 	proxySessionSecret, err := config.GetOauthSessionSecret()
 	if err != nil {
-		return fmt.Errorf("failed to generate random session secret for grafana oauth-proxy: %v", err)
+		return nil, fmt.Errorf("failed to generate random session secret for grafana oauth-proxy: %v", err)
 	}
 
 	imagePullPolicy := corev1.PullAlways
@@ -86,9 +156,24 @@ func (r *MulticlusterGlobalHubReconciler) reconcileGrafana(ctx context.Context,
 		replicas = 2
 	}
 
+	customDashboards, err := r.getCustomDashboards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the custom grafana dashboards: %v", err)
+	}
+
+	observabilityURL, err := r.getObservabilityQueryURL(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the query endpoint of the observability: %v", err)
+	}
+
+	// the grafana authenticates the users itself instead of the oauth proxy if the grafanaAuth is configured
+	grafanaAuth, oauthSecretName := mgh.Spec.GrafanaAuth, ""
+	if grafanaAuth != nil && grafanaAuth.OAuth != nil {
+		oauthSecretName = grafanaAuth.OAuth.ClientSecretName
+	}
+
 	// get the grafana objects
-	grafanaRenderer, grafanaDeployer := renderer.NewHoHRenderer(fs), deployer.NewHoHDeployer(r.Client)
-	grafanaObjects, err := grafanaRenderer.Render("manifests/grafana", "", func(profile string) (interface{}, error) {
+	return renderer.NewHoHRenderer(fs).Render("manifests/grafana", "", func(profile string) (interface{}, error) {
 		return struct {
 			Namespace            string
 			Replicas             int32
@@ -102,6 +187,11 @@ func (r *MulticlusterGlobalHubReconciler) reconcileGrafana(ctx context.Context,
 			Tolerations          []corev1.Toleration
 			Resources            *corev1.ResourceRequirements
 			EnableMetrics        bool
+			CustomDashboards     []string
+			EnableSSO            bool
+			OAuthSecretName      string
+			EnableLDAP           bool
+			EnableObservability  bool
 		}{
 			Namespace:            utils.GetDefaultNamespace(),
 			Replicas:             replicas,
@@ -115,48 +205,13 @@ func (r *MulticlusterGlobalHubReconciler) reconcileGrafana(ctx context.Context,
 			Tolerations:          mgh.Spec.Tolerations,
 			EnableMetrics:        mgh.Spec.EnableMetrics,
 			Resources:            operatorutils.GetResources(operatorconstants.Grafana, mgh.Spec.AdvancedConfig),
+			CustomDashboards:     customDashboards,
+			EnableSSO:            grafanaAuth != nil,
+			OAuthSecretName:      oauthSecretName,
+			EnableLDAP:           grafanaAuth != nil && grafanaAuth.LDAP != nil,
+			EnableObservability:  observabilityURL != "",
 		}, nil
 	})
-	if err != nil {
-		return fmt.Errorf("failed to render grafana manifests: %w", err)
-	}
-
-	// create restmapper for deployer to find GVR
-	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
-	if err != nil {
-		return err
-	}
-	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
-
-	if err = manipulateObj(grafanaObjects, mgh, grafanaDeployer, mapper, r.GetScheme()); err != nil {
-		return fmt.Errorf("failed to create/update grafana objects: %w", err)
-	}
-
-	// generate datasource secret: must before the grafana objects
-	changedDatasourceSecret, err := r.GenerateGrafanaDataSourceSecret(ctx, mgh)
-	if err != nil {
-		return fmt.Errorf("failed to generate grafana datasource secret: %v", err)
-	}
-
-	changedAlert, err := r.generateAlertConfigMap(ctx, mgh)
-	if err != nil {
-		return fmt.Errorf("failed to generate merged alert configmap. err:%v", err)
-	}
-
-	changedGrafanaIni, err := r.generateGrafanaIni(ctx, mgh)
-	if err != nil {
-		return fmt.Errorf("failed to generate grafana init. err:%v", err)
-	}
-
-	if changedAlert || changedGrafanaIni || changedDatasourceSecret {
-		err = utils.RestartPod(ctx, r.KubeClient, utils.GetDefaultNamespace(), grafanaDeploymentName)
-		if err != nil {
-			return fmt.Errorf("failed to restart grafana pod. err:%v", err)
-		}
-	}
-
-	log.Info("grafana objects created/updated successfully")
-	return nil
 }
 
 // generateGranafaIni append the custom grafana.ini to default grafana.ini
@@ -235,6 +290,14 @@ func (r *MulticlusterGlobalHubReconciler) generateGrafanaIni(
 		}
 	}
 
+	if mgh.Spec.GrafanaAuth != nil {
+		authGrafanaIni, err := applyGrafanaAuth(defaultGrafanaIniSecret.Data[grafanaIniKey], mgh.Spec.GrafanaAuth)
+		if err != nil {
+			return false, fmt.Errorf("failed to configure the grafana authentication: %w", err)
+		}
+		defaultGrafanaIniSecret.Data[grafanaIniKey] = authGrafanaIni
+	}
+
 	if !foundCustomGrafanaSecret {
 		mergedGrafanaIniSecret.Data = map[string][]byte{
 			grafanaIniKey: defaultGrafanaIniSecret.Data[grafanaIniKey],
@@ -441,11 +504,16 @@ func (r *MulticlusterGlobalHubReconciler) GenerateGrafanaDataSourceSecret(
 		saToken = string(saSecret.Data["token"])
 	}
 
+	observabilityURL, err := r.getObservabilityQueryURL(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	datasourceVal, err := GrafanaDataSource(r.MiddlewareConfig.StorageConn.ReadonlyUserDatabaseURI,
-		r.MiddlewareConfig.StorageConn.CACert, saToken)
+		r.MiddlewareConfig.StorageConn.CACert, saToken, observabilityURL)
 	if err != nil {
 		datasourceVal, err = GrafanaDataSource(r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI,
-			r.MiddlewareConfig.StorageConn.CACert, saToken)
+			r.MiddlewareConfig.StorageConn.CACert, saToken, observabilityURL)
 		if err != nil {
 			return false, err
 		}
@@ -493,7 +561,28 @@ func (r *MulticlusterGlobalHubReconciler) GenerateGrafanaDataSourceSecret(
 	return false, nil
 }
 
-func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken string) ([]byte, error) {
+// getObservabilityQueryURL returns the thanos query endpoint of the ACM observability, it's empty if the observability
+// isn't installed
+func (r *MulticlusterGlobalHubReconciler) getObservabilityQueryURL(ctx context.Context) (string, error) {
+	service, err := r.KubeClient.CoreV1().Services(observabilityNamespace).Get(ctx, observabilityQueryService,
+		metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	port := int32(9090)
+	for _, servicePort := range service.Spec.Ports {
+		if servicePort.Name == "http" {
+			port = servicePort.Port
+		}
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d", service.Name, service.Namespace, port), nil
+}
+
+func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken, observabilityURL string,
+) ([]byte, error) {
 	postgresURI := string(databaseURI)
 	objURI, err := url.Parse(postgresURI)
 	if err != nil {
@@ -513,6 +602,7 @@ func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken stri
 
 	postgresDS := &GrafanaDatasource{
 		Name:      "Global-Hub-DataSource",
+		UID:       globalHubDatasourceUID,
 		Type:      "postgres",
 		Access:    "proxy",
 		IsDefault: true,
@@ -552,6 +642,23 @@ func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken stri
 		datasource = append(datasource, prometheusDS)
 	}
 
+	// the metrics of the managed hubs collected by the ACM observability, the dashboards refer to it by the uid
+	if observabilityURL != "" {
+		datasource = append(datasource, &GrafanaDatasource{
+			Name:      "Observability",
+			UID:       observabilityDatasourceUID,
+			Type:      "prometheus",
+			Access:    "proxy",
+			IsDefault: false,
+			URL:       observabilityURL,
+			Editable:  false,
+			JSONData: &JsonData{
+				QueryTimeout: "300s",
+				TimeInterval: "5m",
+			},
+		})
+	}
+
 	if len(cert) > 0 {
 		postgresDS.JSONData.SSLMode = objURI.Query().Get("sslmode") // sslmode == "verify-full" || sslmode == "verify-ca"
 		postgresDS.JSONData.TLSAuth = true
@@ -583,6 +690,7 @@ type GrafanaDatasource struct {
 	Editable          bool            `yaml:"editable,omitempty"`
 	IsDefault         bool            `yaml:"isDefault,omitempty"`
 	Name              string          `yaml:"name,omitempty"`
+	UID               string          `yaml:"uid,omitempty"`
 	OrgID             int             `yaml:"orgId,omitempty"`
 	Type              string          `yaml:"type,omitempty"`
 	URL               string          `yaml:"url,omitempty"`
@@ -612,3 +720,21 @@ type SecureJsonData struct {
 	TLSClientKey     string `yaml:"tlsClientKey,omitempty"`
 	HttpHeaderValue1 string `yaml:"httpHeaderValue1,omitempty"`
 }
+
+// getCustomDashboards returns the names of the configmaps labeled as the custom grafana dashboards in the global hub
+// namespace. These configmaps are created by the users, so they aren't owned and overridden by the operator.
+func (r *MulticlusterGlobalHubReconciler) getCustomDashboards(ctx context.Context) ([]string, error) {
+	configMaps := &corev1.ConfigMapList{}
+	err := r.Client.List(ctx, configMaps, client.InNamespace(utils.GetDefaultNamespace()),
+		client.HasLabels{operatorconstants.GHGrafanaDashboardLabelKey})
+	if err != nil {
+		return nil, err
+	}
+
+	dashboards := make([]string, 0, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		dashboards = append(dashboards, configMap.Name)
+	}
+	sort.Strings(dashboards)
+	return dashboards, nil
+}
@@ -0,0 +1,199 @@
+package hubofhubs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/ini.v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	grafanaLDAPSecretName = "multicluster-global-hub-grafana-ldap"
+	grafanaLDAPKey        = "ldap.toml"
+
+	// the paths of the secrets mounted into the grafana container when the SSO is enabled
+	grafanaTLSPath   = "/etc/tls/private"
+	grafanaOAuthPath = "/etc/grafana-oauth"
+	grafanaLDAPPath  = "/etc/grafana-ldap"
+)
+
+// applyGrafanaAuth configures the grafana.ini to serve the https by the grafana itself and authenticate the users
+// with the OAuth/OIDC provider or the LDAP server, the OpenShift oauth proxy isn't deployed in this case.
+func applyGrafanaAuth(grafanaIni []byte, auth *globalhubv1alpha4.GrafanaAuthConfig) ([]byte, error) {
+	if auth == nil {
+		return grafanaIni, nil
+	}
+	cfg, err := ini.Load(grafanaIni)
+	if err != nil {
+		return nil, err
+	}
+
+	server := cfg.Section("server")
+	server.Key("protocol").SetValue("https")
+	server.Key("http_port").SetValue("9443")
+	server.Key("cert_file").SetValue(grafanaTLSPath + "/tls.crt")
+	server.Key("cert_key").SetValue(grafanaTLSPath + "/tls.key")
+
+	cfg.Section("auth.proxy").Key("enabled").SetValue("false")
+	cfg.Section("auth").Key("disable_signout_menu").SetValue("false")
+	// the login form is needed by the LDAP, it's hidden if only the OAuth is enabled
+	cfg.Section("auth").Key("disable_login_form").SetValue(strconv.FormatBool(auth.LDAP == nil))
+
+	if auth.OAuth != nil {
+		name := auth.OAuth.Name
+		if name == "" {
+			name = "SSO"
+		}
+		scopes := auth.OAuth.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "profile", "email", "groups"}
+		}
+		groupsPath := auth.OAuth.GroupsAttributePath
+		if groupsPath == "" {
+			groupsPath = "groups"
+		}
+
+		oauth := cfg.Section("auth.generic_oauth")
+		oauth.Key("enabled").SetValue("true")
+		oauth.Key("name").SetValue(name)
+		oauth.Key("allow_sign_up").SetValue("true")
+		oauth.Key("client_id").SetValue(fmt.Sprintf("$__file{%s/client_id}", grafanaOAuthPath))
+		oauth.Key("client_secret").SetValue(fmt.Sprintf("$__file{%s/client_secret}", grafanaOAuthPath))
+		oauth.Key("scopes").SetValue(strings.Join(scopes, " "))
+		oauth.Key("auth_url").SetValue(auth.OAuth.AuthURL)
+		oauth.Key("token_url").SetValue(auth.OAuth.TokenURL)
+		oauth.Key("api_url").SetValue(auth.OAuth.APIURL)
+		oauth.Key("role_attribute_path").SetValue(oauthRoleAttributePath(groupsPath, auth.RoleMapping))
+	}
+
+	if auth.LDAP != nil {
+		ldap := cfg.Section("auth.ldap")
+		ldap.Key("enabled").SetValue("true")
+		ldap.Key("allow_sign_up").SetValue("true")
+		ldap.Key("config_file").SetValue(grafanaLDAPPath + "/" + grafanaLDAPKey)
+	}
+
+	var buf bytes.Buffer
+	if _, err = cfg.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// oauthRoleAttributePath returns the JMESPath expression which maps the groups of the user to the grafana role,
+// e.g. contains(groups[*], 'admins') && 'Admin' || contains(groups[*], 'editors') && 'Editor' || 'Viewer'
+func oauthRoleAttributePath(groupsPath string, mapping *globalhubv1alpha4.GrafanaRoleMapping) string {
+	conditions := []string{}
+	if mapping != nil {
+		for _, group := range mapping.AdminGroups {
+			conditions = append(conditions, fmt.Sprintf("contains(%s[*], '%s') && 'Admin'", groupsPath, group))
+		}
+		for _, group := range mapping.EditorGroups {
+			conditions = append(conditions, fmt.Sprintf("contains(%s[*], '%s') && 'Editor'", groupsPath, group))
+		}
+	}
+	return strings.Join(append(conditions, "'Viewer'"), " || ")
+}
+
+// grafanaLDAPToml renders the ldap.toml of the grafana, the groups of the role mapping are the DNs of the memberOf
+// attribute, and the other users are Viewer.
+func grafanaLDAPToml(ldap *globalhubv1alpha4.GrafanaLDAPConfig, mapping *globalhubv1alpha4.GrafanaRoleMapping,
+	bindPassword string,
+) string {
+	port := ldap.Port
+	if port == 0 {
+		port = 389
+	}
+	searchFilter := ldap.SearchFilter
+	if searchFilter == "" {
+		searchFilter = "(uid=%s)"
+	}
+	baseDNs := make([]string, 0, len(ldap.SearchBaseDNs))
+	for _, dn := range ldap.SearchBaseDNs {
+		baseDNs = append(baseDNs, strconv.Quote(dn))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[[servers]]\n")
+	fmt.Fprintf(&sb, "host = %s\n", strconv.Quote(ldap.Host))
+	fmt.Fprintf(&sb, "port = %d\n", port)
+	fmt.Fprintf(&sb, "use_ssl = %t\n", ldap.UseSSL)
+	fmt.Fprintf(&sb, "bind_dn = %s\n", strconv.Quote(ldap.BindDN))
+	fmt.Fprintf(&sb, "bind_password = %s\n", strconv.Quote(bindPassword))
+	fmt.Fprintf(&sb, "search_filter = %s\n", strconv.Quote(searchFilter))
+	fmt.Fprintf(&sb, "search_base_dns = [%s]\n", strings.Join(baseDNs, ", "))
+	sb.WriteString("\n[servers.attributes]\n")
+	sb.WriteString("name = \"givenName\"\nsurname = \"sn\"\nusername = \"uid\"\nmember_of = \"memberOf\"\n" +
+		"email = \"mail\"\n")
+
+	writeGroupMapping := func(groupDN, role string) {
+		sb.WriteString("\n[[servers.group_mappings]]\n")
+		fmt.Fprintf(&sb, "group_dn = %s\n", strconv.Quote(groupDN))
+		fmt.Fprintf(&sb, "org_role = %s\n", strconv.Quote(role))
+	}
+	if mapping != nil {
+		for _, group := range mapping.AdminGroups {
+			writeGroupMapping(group, "Admin")
+		}
+		for _, group := range mapping.EditorGroups {
+			writeGroupMapping(group, "Editor")
+		}
+	}
+	writeGroupMapping("*", "Viewer")
+	return sb.String()
+}
+
+// generateGrafanaLDAPSecret generates the secret with the ldap.toml of the grafana, it's removed if the LDAP isn't
+// configured in the MGH instance.
+func (r *MulticlusterGlobalHubReconciler) generateGrafanaLDAPSecret(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) (bool, error) {
+	configNamespace := utils.GetDefaultNamespace()
+	ldapSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      grafanaLDAPSecretName,
+			Namespace: configNamespace,
+			Labels: map[string]string{
+				"name":                           grafanaDeploymentName,
+				constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+			},
+		},
+	}
+
+	if mgh.Spec.GrafanaAuth == nil || mgh.Spec.GrafanaAuth.LDAP == nil {
+		if err := r.Client.Delete(ctx, ldapSecret); err != nil && !errors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to delete the grafana ldap secret: %w", err)
+		}
+		return false, nil
+	}
+	ldap := mgh.Spec.GrafanaAuth.LDAP
+
+	bindPasswordSecret := &corev1.Secret{}
+	err := r.Client.Get(ctx, client.ObjectKey{Namespace: configNamespace, Name: ldap.BindPasswordSecretName},
+		bindPasswordSecret)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the ldap bind password secret %s: %w", ldap.BindPasswordSecretName, err)
+	}
+
+	ldapSecret.Data = map[string][]byte{
+		grafanaLDAPKey: []byte(grafanaLDAPToml(ldap, mgh.Spec.GrafanaAuth.RoleMapping,
+			string(bindPasswordSecret.Data["password"]))),
+	}
+	if err = controllerutil.SetControllerReference(mgh, ldapSecret, r.Scheme); err != nil {
+		return false, err
+	}
+	return operatorutils.ApplySecret(ctx, r.Client, ldapSecret)
+}
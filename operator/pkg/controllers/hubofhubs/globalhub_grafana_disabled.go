@@ -0,0 +1,107 @@
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// the secret of the postgres connection details for the visualization stack of the user
+const datasourceExportName = "multicluster-global-hub-postgres-datasource" // #nosec G101
+
+// reconcileDatasourceExport removes the built-in grafana and renders the connection details of the postgres into the
+// secret when the grafana component is disabled, so that the users can bring their own visualization stack.
+func (r *MulticlusterGlobalHubReconciler) reconcileDatasourceExport(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	log := r.Log.WithName("datasource-export")
+	if r.MiddlewareConfig == nil || r.MiddlewareConfig.StorageConn == nil {
+		return fmt.Errorf("middleware PgConnection config is null")
+	}
+
+	if err := r.pruneBuiltInGrafana(ctx, mgh); err != nil {
+		return fmt.Errorf("failed to remove the built-in grafana: %w", err)
+	}
+
+	// the readonly user is preferred, the same as the datasource of the built-in grafana
+	databaseURI := r.MiddlewareConfig.StorageConn.ReadonlyUserDatabaseURI
+	if databaseURI == "" {
+		databaseURI = r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI
+	}
+	data, err := datasourceExportData(databaseURI, r.MiddlewareConfig.StorageConn.CACert)
+	if err != nil {
+		return err
+	}
+
+	exportSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      datasourceExportName,
+			Namespace: utils.GetDefaultNamespace(),
+			Labels: map[string]string{
+				constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	if err = controllerutil.SetControllerReference(mgh, exportSecret, r.Scheme); err != nil {
+		return err
+	}
+	if _, err = operatorutils.ApplySecret(ctx, r.Client, exportSecret); err != nil {
+		return fmt.Errorf("failed to apply the postgres datasource secret: %w", err)
+	}
+
+	log.Info("postgres datasource secret created/updated successfully", "name", datasourceExportName)
+	return nil
+}
+
+// datasourceExportData returns the connection details of the postgres, both the individual fields for the generic
+// clients and the provisioning file of the grafana datasource which the dashboards of the global hub refer to
+func datasourceExportData(databaseURI string, caCert []byte) (map[string][]byte, error) {
+	objURI, err := url.Parse(databaseURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the database uri: %w", err)
+	}
+	password, ok := objURI.User.Password()
+	if !ok {
+		return nil, fmt.Errorf("failed to get password from the database uri")
+	}
+	database := "hoh"
+	if paths := strings.Split(objURI.Path, "/"); len(paths) > 1 {
+		database = paths[1]
+	}
+	port := objURI.Port()
+	if port == "" {
+		port = "5432"
+	}
+
+	grafanaDatasource, err := GrafanaDataSource(databaseURI, caCert, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{
+		"host":        []byte(objURI.Hostname()),
+		"port":        []byte(port),
+		"database":    []byte(database),
+		"user":        []byte(objURI.User.Username()),
+		"password":    []byte(password),
+		"sslmode":     []byte(objURI.Query().Get("sslmode")),
+		"uri":         []byte(databaseURI),
+		datasourceKey: grafanaDatasource,
+	}
+	if len(caCert) > 0 {
+		data["ca.crt"] = caCert
+	}
+	return data, nil
+}
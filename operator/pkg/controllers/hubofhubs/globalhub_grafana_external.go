@@ -0,0 +1,257 @@
+package hubofhubs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	externalGrafanaCredentialName = "multicluster-global-hub-grafana-datasource-credential" // #nosec G101
+	defaultExternalGrafanaFolder  = "Multicluster Global Hub"
+	dashboardConfigMapPrefix      = "grafana-dashboard-"
+)
+
+// ExternalGrafanaDashboard is a dashboard json in the configmap provisioned into the external grafana
+type ExternalGrafanaDashboard struct {
+	Name      string
+	ConfigMap string
+	Key       string
+}
+
+// reconcileExternalGrafana provisions the datasource and dashboards into the Grafana instances of the Grafana
+// Operator by the GrafanaDatasource and GrafanaDashboard, the built-in grafana isn't deployed in this mode.
+func (r *MulticlusterGlobalHubReconciler) reconcileExternalGrafana(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	log := r.Log.WithName("external-grafana")
+	if r.MiddlewareConfig == nil || r.MiddlewareConfig.StorageConn == nil {
+		return fmt.Errorf("middleware PgConnection config is null")
+	}
+
+	if err := r.pruneBuiltInGrafana(ctx, mgh); err != nil {
+		return fmt.Errorf("failed to remove the built-in grafana: %w", err)
+	}
+
+	databaseURI := r.MiddlewareConfig.StorageConn.ReadonlyUserDatabaseURI
+	if databaseURI == "" {
+		databaseURI = r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI
+	}
+	objURI, err := url.Parse(databaseURI)
+	if err != nil {
+		return fmt.Errorf("failed to parse the database uri: %w", err)
+	}
+	password, ok := objURI.User.Password()
+	if !ok {
+		return fmt.Errorf("failed to get password from the database uri")
+	}
+	database := "hoh"
+	if paths := strings.Split(objURI.Path, "/"); len(paths) > 1 {
+		database = paths[1]
+	}
+
+	// the credential is referred by the valuesFrom of the GrafanaDatasource
+	caCert := r.MiddlewareConfig.StorageConn.CACert
+	credentialSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalGrafanaCredentialName,
+			Namespace: utils.GetDefaultNamespace(),
+			Labels: map[string]string{
+				constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+			},
+		},
+		Data: map[string][]byte{
+			"PASSWORD": []byte(password),
+			"CA_CERT":  caCert,
+		},
+	}
+	if err = controllerutil.SetControllerReference(mgh, credentialSecret, r.Scheme); err != nil {
+		return err
+	}
+	if _, err = operatorutils.ApplySecret(ctx, r.Client, credentialSecret); err != nil {
+		return fmt.Errorf("failed to apply the grafana datasource credential: %w", err)
+	}
+
+	// the dashboard configmaps are the same as the built-in grafana, the metrics and observability dashboards are
+	// skipped since the prometheus datasources aren't provisioned into the external grafana
+	dashboardRenderer := renderer.NewHoHRenderer(fs)
+	dashboardObjects, err := dashboardRenderer.RenderWithFilter("manifests/grafana", "", "acm-global-",
+		func(profile string) (interface{}, error) {
+			return struct {
+				Namespace           string
+				EnableMetrics       bool
+				EnableObservability bool
+			}{
+				Namespace:           utils.GetDefaultNamespace(),
+				EnableMetrics:       false,
+				EnableObservability: false,
+			}, nil
+		})
+	if err != nil {
+		return fmt.Errorf("failed to render grafana dashboards: %w", err)
+	}
+
+	datasourceUID := mgh.Spec.ExternalGrafana.DatasourceUID
+	if datasourceUID == "" {
+		datasourceUID = globalHubDatasourceUID
+	}
+
+	dashboards := []ExternalGrafanaDashboard{}
+	externalObjects := dashboardObjects[:0]
+	for _, obj := range dashboardObjects {
+		if obj.GetKind() != "ConfigMap" || !strings.HasPrefix(obj.GetName(), dashboardConfigMapPrefix) {
+			continue
+		}
+		externalObjects = append(externalObjects, obj)
+		data, _ := obj.Object["data"].(map[string]interface{})
+		keys := make([]string, 0, len(data))
+		for key, dashboard := range data {
+			keys = append(keys, key)
+			data[key] = replaceDatasourceUID(dashboard.(string), datasourceUID)
+		}
+		dashboards = append(dashboards, externalGrafanaDashboards(obj.GetName(), keys)...)
+	}
+
+	customDashboards, err := r.getCustomDashboards(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the custom grafana dashboards: %v", err)
+	}
+	for _, name := range customDashboards {
+		cm := &corev1.ConfigMap{}
+		if err = r.Client.Get(ctx, client.ObjectKey{Namespace: utils.GetDefaultNamespace(), Name: name},
+			cm); err != nil {
+			return fmt.Errorf("failed to get the custom grafana dashboard %s: %w", name, err)
+		}
+		keys := make([]string, 0, len(cm.Data))
+		for key := range cm.Data {
+			keys = append(keys, key)
+		}
+		dashboards = append(dashboards, externalGrafanaDashboards(name, keys)...)
+	}
+
+	instanceSelector, err := json.Marshal(mgh.Spec.ExternalGrafana.InstanceSelector)
+	if err != nil {
+		return err
+	}
+	folder := mgh.Spec.ExternalGrafana.Folder
+	if folder == "" {
+		folder = defaultExternalGrafanaFolder
+	}
+
+	grafanaObjects, err := renderer.NewHoHRenderer(fs).Render("manifests/external-grafana", "",
+		func(profile string) (interface{}, error) {
+			return struct {
+				Namespace            string
+				InstanceSelector     string
+				Folder               string
+				CredentialSecretName string
+				DatasourceUID        string
+				DatabaseHost         string
+				DatabaseUser         string
+				Database             string
+				TLSEnabled           bool
+				SSLMode              string
+				Dashboards           []ExternalGrafanaDashboard
+			}{
+				Namespace:            utils.GetDefaultNamespace(),
+				InstanceSelector:     string(instanceSelector),
+				Folder:               folder,
+				CredentialSecretName: externalGrafanaCredentialName,
+				DatasourceUID:        datasourceUID,
+				DatabaseHost:         objURI.Host,
+				DatabaseUser:         objURI.User.Username(),
+				Database:             database,
+				TLSEnabled:           len(caCert) > 0,
+				SSLMode:              objURI.Query().Get("sslmode"),
+				Dashboards:           dashboards,
+			}, nil
+		})
+	if err != nil {
+		return fmt.Errorf("failed to render external grafana manifests: %w", err)
+	}
+	externalObjects = append(externalObjects, grafanaObjects...)
+
+	// create restmapper for deployer to find GVR
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	if err = manipulateObj(externalObjects, mgh, deployer.NewHoHDeployer(r.Client), mapper,
+		r.GetScheme()); err != nil {
+		return fmt.Errorf("failed to create/update external grafana objects: %w", err)
+	}
+
+	log.Info("external grafana objects created/updated successfully")
+	return nil
+}
+
+// pruneBuiltInGrafana removes the deployment and route of the built-in grafana when switching to the external
+// grafana, the other objects are harmless and removed with the MGH instance.
+func (r *MulticlusterGlobalHubReconciler) pruneBuiltInGrafana(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	objs := []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name: grafanaDeploymentName, Namespace: utils.GetDefaultNamespace(),
+		}},
+		&routev1.Route{ObjectMeta: metav1.ObjectMeta{
+			Name: grafanaDeploymentName, Namespace: utils.GetDefaultNamespace(),
+		}},
+	}
+	for _, obj := range objs {
+		if err := r.pruneObject(ctx, mgh, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// externalGrafanaDashboards returns the dashboards of the configmap, the dashboard is named after the configmap if
+// the configmap only has a single dashboard, otherwise it's named after both the configmap and the key.
+func externalGrafanaDashboards(configMap string, keys []string) []ExternalGrafanaDashboard {
+	sort.Strings(keys)
+	dashboards := make([]ExternalGrafanaDashboard, 0, len(keys))
+	for _, key := range keys {
+		name := configMap
+		if len(keys) > 1 {
+			name = configMap + "-" + strings.TrimSuffix(key, ".json")
+		}
+		dashboards = append(dashboards, ExternalGrafanaDashboard{
+			Name:      strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name)),
+			ConfigMap: configMap,
+			Key:       key,
+		})
+	}
+	return dashboards
+}
+
+// replaceDatasourceUID points the global hub datasource of the dashboard to the datasource with the uid in the
+// external grafana
+func replaceDatasourceUID(dashboard, datasourceUID string) string {
+	if datasourceUID == globalHubDatasourceUID {
+		return dashboard
+	}
+	return strings.ReplaceAll(dashboard, fmt.Sprintf("%q", globalHubDatasourceUID), fmt.Sprintf("%q", datasourceUID))
+}
@@ -0,0 +1,107 @@
+package hubofhubs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// jobStatusSyncInterval is the interval to copy the last run of the manager jobs into the status of the mgh
+const jobStatusSyncInterval = 1 * time.Minute
+
+// syncJobStatus periodically reflects the status.cron_jobs table, which is maintained by the manager scheduler, to the
+// status.jobs of the mgh instance. The manager doesn't access the mgh, so the status is written by the operator.
+func (r *MulticlusterGlobalHubReconciler) syncJobStatus(ctx context.Context) error {
+	ticker := time.NewTicker(jobStatusSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.updateJobStatus(ctx); err != nil {
+				r.Log.Error(err, "failed to sync the status of the manager jobs")
+			}
+		}
+	}
+}
+
+func (r *MulticlusterGlobalHubReconciler) updateJobStatus(ctx context.Context) error {
+	// the database isn't ready until the storage connection is reconciled
+	if r.MiddlewareConfig == nil || r.MiddlewareConfig.StorageConn == nil {
+		return nil
+	}
+
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+	if err := r.Client.Get(ctx, config.GetMGHNamespacedName(), mgh); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !mgh.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	jobs, err := r.listJobStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(jobs, mgh.Status.Jobs) {
+		return nil
+	}
+	mgh.Status.Jobs = jobs
+	return r.Client.Status().Update(ctx, mgh)
+}
+
+func (r *MulticlusterGlobalHubReconciler) listJobStatus(ctx context.Context) ([]globalhubv1alpha4.JobStatus, error) {
+	conn, err := database.PostgresConnection(ctx, r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI,
+		r.MiddlewareConfig.StorageConn.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(ctx); err != nil {
+			r.Log.Error(err, "failed to close connection to database")
+		}
+	}()
+
+	rows, err := conn.Query(ctx,
+		"SELECT name, last_run_at, next_run_at, result, error FROM status.cron_jobs ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the status of the jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []globalhubv1alpha4.JobStatus
+	for rows.Next() {
+		var name, result string
+		var lastRunAt time.Time
+		var nextRunAt sql.NullTime
+		var message sql.NullString
+		if err := rows.Scan(&name, &lastRunAt, &nextRunAt, &result, &message); err != nil {
+			return nil, fmt.Errorf("failed to scan the status of the jobs: %w", err)
+		}
+		// the status is serialized in seconds, truncate it to compare with the existing one
+		job := globalhubv1alpha4.JobStatus{
+			Name:        name,
+			LastRunTime: &metav1.Time{Time: lastRunAt.Truncate(time.Second)},
+			Result:      result,
+			Message:     message.String,
+		}
+		if nextRunAt.Valid {
+			job.NextRunTime = &metav1.Time{Time: nextRunAt.Time.Truncate(time.Second)}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubofhubs
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// the manager consumes the status and event topics with the default consumer id
+	managerConsumerGroup = "multicluster-global-hub-manager"
+	autoscaleInterval    = 1 * time.Minute
+	autoscaleTimeout     = 30 * time.Second
+	metadataTimeoutMs    = 10000
+)
+
+// partitionAdmin is the subset of the kafka admin client used to sample the throughput and the lag of the topics
+type partitionAdmin interface {
+	GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error)
+	ListOffsets(ctx context.Context, topicPartitionOffsets map[kafka.TopicPartition]kafka.OffsetSpec,
+		options ...kafka.ListOffsetsAdminOption) (kafka.ListOffsetsResult, error)
+	ListConsumerGroupOffsets(ctx context.Context, groupsPartitions []kafka.ConsumerGroupTopicPartitions,
+		options ...kafka.ListConsumerGroupOffsetsAdminOption) (kafka.ListConsumerGroupOffsetsResult, error)
+	Close()
+}
+
+// topicSample is the sum of the end offsets and the lag of the manager over the partitions of a topic
+type topicSample struct {
+	partitions int32
+	endOffset  int64
+	lag        int64
+}
+
+// topicState is the last sample of the topic and since when the thresholds are exceeded
+type topicState struct {
+	endOffset     int64
+	sampledAt     time.Time
+	exceededSince time.Time
+}
+
+// kafkaTopicAutoscaler samples the status and event topics of the built-in kafka periodically, and increases the
+// partitions of the topic once its throughput or the consumer lag of the manager exceeds the thresholds for the
+// sustained duration. The consumer group of the manager is rebalanced onto the new partitions once its consumers
+// refresh the metadata.
+type kafkaTopicAutoscaler struct {
+	log             logr.Logger
+	client          client.Client
+	kafkaController *KafkaController
+	newAdmin        func(conn *transport.ConnCredential) (partitionAdmin, error)
+	states          map[string]*topicState
+}
+
+func addKafkaTopicAutoscaler(mgr ctrl.Manager, kafkaController *KafkaController) error {
+	return mgr.Add(&kafkaTopicAutoscaler{
+		log:             ctrl.Log.WithName("kafka-topic-autoscaler"),
+		client:          mgr.GetClient(),
+		kafkaController: kafkaController,
+		newAdmin:        newPartitionAdmin,
+		states:          map[string]*topicState{},
+	})
+}
+
+func (a *kafkaTopicAutoscaler) Start(ctx context.Context) error {
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := a.autoscale(ctx, time.Now()); err != nil {
+				a.log.Error(err, "failed to autoscale the kafka topics")
+			}
+		}
+	}
+}
+
+func (a *kafkaTopicAutoscaler) autoscale(ctx context.Context, now time.Time) error {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+	if err := a.client.Get(ctx, config.GetMGHNamespacedName(), mgh); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	autoscaling := config.GetTopicAutoscaling(mgh)
+	conn := a.kafkaController.getConn()
+	if autoscaling == nil || conn == nil {
+		a.states = map[string]*topicState{}
+		return nil
+	}
+
+	topics := &kafkav1beta2.KafkaTopicList{}
+	if err := a.client.List(ctx, topics, client.InNamespace(mgh.Namespace),
+		client.MatchingLabels{constants.GlobalHubOwnerLabelKey: constants.GlobalHubOwnerLabelVal}); err != nil {
+		return fmt.Errorf("failed to list the kafka topics: %w", err)
+	}
+	names := []string{}
+	for _, topic := range topics.Items {
+		// the spec topic is consumed by the agents, each of them reads all the messages with its own group
+		if topic.Name != transport.GenericSpecTopic {
+			names = append(names, topic.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	admin, err := a.newAdmin(conn)
+	if err != nil {
+		return fmt.Errorf("failed to create the kafka admin client: %w", err)
+	}
+	defer admin.Close()
+	samples, err := sampleTopics(ctx, admin, names)
+	if err != nil {
+		return err
+	}
+
+	for i := range topics.Items {
+		topic := &topics.Items[i]
+		sample, found := samples[topic.Name]
+		if !found {
+			continue
+		}
+		partitions := a.desiredPartitions(topic.Name, sample, autoscaling, now)
+		if topic.Spec == nil || (topic.Spec.Partitions != nil && *topic.Spec.Partitions >= partitions) {
+			continue
+		}
+		topic.Spec.Partitions = &partitions
+		if err := a.client.Update(ctx, topic); err != nil {
+			return fmt.Errorf("failed to increase the partitions of the kafka topic %s: %w", topic.Name, err)
+		}
+		config.RecordAction(mgh, config.ReasonKafkaTopicScaled,
+			fmt.Sprintf("Increased the partitions of the kafka topic %s from %d to %d", topic.Name, sample.partitions,
+				partitions), "topic", topic.Name, "partitions", partitions, "lag", sample.lag)
+	}
+	return nil
+}
+
+// desiredPartitions returns the partitions of the topic by the sample, they're doubled up to the max partitions once
+// the average throughput or lag per partition exceeds the thresholds for the sustained duration
+func (a *kafkaTopicAutoscaler) desiredPartitions(name string, sample topicSample,
+	autoscaling *globalhubv1alpha4.TopicAutoscalingConfig, now time.Time,
+) int32 {
+	state, found := a.states[name]
+	if !found {
+		a.states[name] = &topicState{endOffset: sample.endOffset, sampledAt: now}
+		return sample.partitions
+	}
+	throughput := int64(0)
+	if elapsed := now.Sub(state.sampledAt).Seconds(); elapsed > 0 && sample.endOffset > state.endOffset {
+		throughput = int64(float64(sample.endOffset-state.endOffset) / elapsed)
+	}
+	state.endOffset, state.sampledAt = sample.endOffset, now
+
+	partitions := int64(sample.partitions)
+	if partitions == 0 || (throughput/partitions <= autoscaling.ThroughputThreshold &&
+		sample.lag/partitions <= autoscaling.LagThreshold) {
+		state.exceededSince = time.Time{}
+		return sample.partitions
+	}
+	if state.exceededSince.IsZero() {
+		state.exceededSince = now
+	}
+	if now.Sub(state.exceededSince) < autoscaling.SustainedDuration.Duration ||
+		sample.partitions >= autoscaling.MaxPartitions {
+		return sample.partitions
+	}
+	// wait for another sustained duration before increasing them again, so the manager catches up with the new ones
+	state.exceededSince = time.Time{}
+	return min(sample.partitions*2, autoscaling.MaxPartitions)
+}
+
+// sampleTopics returns the partitions, the end offsets and the lag of the manager of the existing topics
+func sampleTopics(ctx context.Context, admin partitionAdmin, names []string) (map[string]topicSample, error) {
+	ctx, cancel := context.WithTimeout(ctx, autoscaleTimeout)
+	defer cancel()
+
+	metadata, err := admin.GetMetadata(nil, true, metadataTimeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kafka metadata: %w", err)
+	}
+	samples := map[string]topicSample{}
+	specs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	for _, name := range names {
+		topicMetadata, found := metadata.Topics[name]
+		if !found || len(topicMetadata.Partitions) == 0 {
+			continue
+		}
+		samples[name] = topicSample{partitions: int32(len(topicMetadata.Partitions))}
+		for _, partition := range topicMetadata.Partitions {
+			topic := name
+			specs[kafka.TopicPartition{Topic: &topic, Partition: partition.ID}] = kafka.LatestOffsetSpec
+		}
+	}
+	if len(specs) == 0 {
+		return samples, nil
+	}
+
+	ends, err := admin.ListOffsets(ctx, specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the end offsets: %w", err)
+	}
+	// the topic of the result is a new pointer, so the offsets are keyed by the topic name and partition
+	endOffsets := map[string]map[int32]int64{}
+	for tp, info := range ends.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return nil, fmt.Errorf("failed to get the end offset of %s: %w", tp, info.Error)
+		}
+		if endOffsets[*tp.Topic] == nil {
+			endOffsets[*tp.Topic] = map[int32]int64{}
+		}
+		endOffsets[*tp.Topic][tp.Partition] = int64(info.Offset)
+		sample := samples[*tp.Topic]
+		sample.endOffset += int64(info.Offset)
+		samples[*tp.Topic] = sample
+	}
+
+	// the nil partitions list the offsets of all the partitions committed by the group
+	committed, err := admin.ListConsumerGroupOffsets(ctx,
+		[]kafka.ConsumerGroupTopicPartitions{{Group: managerConsumerGroup}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the offsets of the consumer group %s: %w", managerConsumerGroup, err)
+	}
+	for _, groupPartitions := range committed.ConsumerGroupsTopicPartitions {
+		for _, tp := range groupPartitions.Partitions {
+			sample, found := samples[*tp.Topic]
+			if !found || tp.Error != nil || tp.Offset < 0 {
+				continue
+			}
+			if lag := endOffsets[*tp.Topic][tp.Partition] - int64(tp.Offset); lag > 0 {
+				sample.lag += lag
+				samples[*tp.Topic] = sample
+			}
+		}
+	}
+	return samples, nil
+}
+
+func newPartitionAdmin(conn *transport.ConnCredential) (partitionAdmin, error) {
+	configMap := &kafka.ConfigMap{
+		"bootstrap.servers":                     conn.BootstrapServer,
+		"ssl.endpoint.identification.algorithm": "none",
+	}
+	if conn.CACert != "" {
+		caCert, err := base64.StdEncoding.DecodeString(conn.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the kafka ca: %w", err)
+		}
+		_ = configMap.SetKey("security.protocol", "ssl")
+		_ = configMap.SetKey("ssl.ca.pem", string(caCert))
+		if conn.ClientCert != "" && conn.ClientKey != "" {
+			clientCert, err := base64.StdEncoding.DecodeString(conn.ClientCert)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the kafka client certificate: %w", err)
+			}
+			clientKey, err := base64.StdEncoding.DecodeString(conn.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the kafka client key: %w", err)
+			}
+			_ = configMap.SetKey("ssl.certificate.pem", string(clientCert))
+			_ = configMap.SetKey("ssl.key.pem", string(clientKey))
+		}
+	}
+	return kafka.NewAdminClient(configMap)
+}
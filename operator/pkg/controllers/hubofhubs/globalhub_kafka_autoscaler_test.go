@@ -0,0 +1,177 @@
+package hubofhubs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// fakePartitionAdmin serves the end offsets and the committed offsets of the manager of the single partition topics
+type fakePartitionAdmin struct {
+	ends      map[string]int64
+	committed map[string]int64
+}
+
+func (a *fakePartitionAdmin) GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error) {
+	metadata := &kafka.Metadata{Topics: map[string]kafka.TopicMetadata{}}
+	for name := range a.ends {
+		metadata.Topics[name] = kafka.TopicMetadata{Topic: name, Partitions: []kafka.PartitionMetadata{{ID: 0}}}
+	}
+	return metadata, nil
+}
+
+func (a *fakePartitionAdmin) ListOffsets(ctx context.Context, specs map[kafka.TopicPartition]kafka.OffsetSpec,
+	options ...kafka.ListOffsetsAdminOption,
+) (kafka.ListOffsetsResult, error) {
+	result := kafka.ListOffsetsResult{ResultInfos: map[kafka.TopicPartition]kafka.ListOffsetsResultInfo{}}
+	for tp := range specs {
+		result.ResultInfos[tp] = kafka.ListOffsetsResultInfo{
+			Offset: kafka.Offset(a.ends[*tp.Topic]), Error: kafka.NewError(kafka.ErrNoError, "", false),
+		}
+	}
+	return result, nil
+}
+
+func (a *fakePartitionAdmin) ListConsumerGroupOffsets(ctx context.Context,
+	groupsPartitions []kafka.ConsumerGroupTopicPartitions, options ...kafka.ListConsumerGroupOffsetsAdminOption,
+) (kafka.ListConsumerGroupOffsetsResult, error) {
+	partitions := []kafka.TopicPartition{}
+	for name, offset := range a.committed {
+		topic := name
+		partitions = append(partitions, kafka.TopicPartition{Topic: &topic, Offset: kafka.Offset(offset)})
+	}
+	return kafka.ListConsumerGroupOffsetsResult{
+		ConsumerGroupsTopicPartitions: []kafka.ConsumerGroupTopicPartitions{
+			{Group: groupsPartitions[0].Group, Partitions: partitions},
+		},
+	}, nil
+}
+
+func (a *fakePartitionAdmin) Close() {}
+
+func newTestKafkaTopic(name string, partitions int32) *kafkav1beta2.KafkaTopic {
+	return &kafkav1beta2.KafkaTopic{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{constants.GlobalHubOwnerLabelKey: constants.GlobalHubOwnerLabelVal},
+		},
+		Spec: &kafkav1beta2.KafkaTopicSpec{Partitions: &partitions},
+	}
+}
+
+func TestKafkaTopicAutoscaler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, globalhubv1alpha4.AddToScheme(scheme))
+	require.NoError(t, kafkav1beta2.AddToScheme(scheme))
+
+	config.SetMGHNamespacedName(types.NamespacedName{Namespace: "default", Name: "multiclusterglobalhub"})
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "multiclusterglobalhub", Namespace: "default"},
+		Spec: globalhubv1alpha4.MulticlusterGlobalHubSpec{
+			DataLayer: globalhubv1alpha4.DataLayerConfig{
+				Kafka: globalhubv1alpha4.KafkaConfig{
+					TopicAutoscaling: &globalhubv1alpha4.TopicAutoscalingConfig{
+						MaxPartitions:       2,
+						LagThreshold:        100,
+						ThroughputThreshold: 10,
+						SustainedDuration:   metav1.Duration{Duration: 2 * time.Minute},
+					},
+				},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(mgh,
+		newTestKafkaTopic("spec", 1), newTestKafkaTopic("status.hub1", 1), newTestKafkaTopic("event", 1)).Build()
+
+	admin := &fakePartitionAdmin{
+		ends:      map[string]int64{"spec": 1000, "status.hub1": 1000, "event": 0},
+		committed: map[string]int64{"status.hub1": 1000},
+	}
+	autoscaler := &kafkaTopicAutoscaler{
+		log:             logr.Discard(),
+		client:          fakeClient,
+		kafkaController: &KafkaController{conn: &transport.ConnCredential{}},
+		newAdmin: func(conn *transport.ConnCredential) (partitionAdmin, error) {
+			return admin, nil
+		},
+		states: map[string]*topicState{},
+	}
+	partitionsOf := func(name string) int32 {
+		topic := &kafkav1beta2.KafkaTopic{}
+		require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name},
+			topic))
+		return *topic.Spec.Partitions
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, autoscaler.autoscale(ctx, now))
+
+	// the throughput of status.hub1 is 20 messages per second, while the manager falls behind the event topic
+	admin.ends["event"] = 500
+	admin.committed["event"] = 0
+	for i := 1; i <= 2; i++ {
+		admin.ends["status.hub1"] += 60 * 20
+		admin.ends["spec"] += 60 * 20
+		admin.committed["status.hub1"] = admin.ends["status.hub1"]
+		require.NoError(t, autoscaler.autoscale(ctx, now.Add(time.Duration(i)*time.Minute)))
+		assert.Equal(t, int32(1), partitionsOf("status.hub1"))
+		assert.Equal(t, int32(1), partitionsOf("event"))
+	}
+
+	// the thresholds are exceeded for the sustained duration
+	admin.ends["status.hub1"] += 60 * 20
+	admin.ends["spec"] += 60 * 20
+	require.NoError(t, autoscaler.autoscale(ctx, now.Add(3*time.Minute)))
+	assert.Equal(t, int32(2), partitionsOf("status.hub1"))
+	assert.Equal(t, int32(2), partitionsOf("event"))
+	// the spec topic isn't scaled by the consumers of the manager
+	assert.Equal(t, int32(1), partitionsOf("spec"))
+}
+
+func TestDesiredPartitions(t *testing.T) {
+	autoscaling := &globalhubv1alpha4.TopicAutoscalingConfig{
+		MaxPartitions:       12,
+		LagThreshold:        100,
+		ThroughputThreshold: 10,
+		SustainedDuration:   metav1.Duration{Duration: time.Minute},
+	}
+	autoscaler := &kafkaTopicAutoscaler{states: map[string]*topicState{}}
+	now := time.Now()
+
+	assert.Equal(t, int32(8), autoscaler.desiredPartitions("status.hub1", topicSample{partitions: 8}, autoscaling, now))
+	// the average lag per partition doesn't exceed the threshold
+	assert.Equal(t, int32(8), autoscaler.desiredPartitions("status.hub1",
+		topicSample{partitions: 8, lag: 800}, autoscaling, now.Add(time.Minute)))
+	assert.True(t, autoscaler.states["status.hub1"].exceededSince.IsZero())
+
+	assert.Equal(t, int32(8), autoscaler.desiredPartitions("status.hub1",
+		topicSample{partitions: 8, lag: 801 * 8}, autoscaling, now.Add(2*time.Minute)))
+	// the partitions are doubled up to the max partitions
+	assert.Equal(t, int32(12), autoscaler.desiredPartitions("status.hub1",
+		topicSample{partitions: 8, lag: 801 * 8}, autoscaling, now.Add(3*time.Minute)))
+
+	// the exceeded thresholds are reset once they recover
+	assert.Equal(t, int32(12), autoscaler.desiredPartitions("status.hub1",
+		topicSample{partitions: 12, lag: 2000 * 12}, autoscaling, now.Add(4*time.Minute)))
+	assert.Equal(t, int32(12), autoscaler.desiredPartitions("status.hub1",
+		topicSample{partitions: 12}, autoscaling, now.Add(5*time.Minute)))
+	assert.True(t, autoscaler.states["status.hub1"].exceededSince.IsZero())
+}
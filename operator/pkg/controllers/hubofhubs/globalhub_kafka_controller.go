@@ -5,28 +5,35 @@ package hubofhubs
 
 import (
 	"context"
+	"reflect"
+	"sync"
 
 	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	transportprotocol "github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
-// KafkaController reconciles the kafka crd
+// KafkaController reconciles the built-in kafka once the kafka, users, topics or the user secrets are changed, the
+// global hub reconciler is triggered if the transport connection is updated
 type KafkaController struct {
 	Log                 logr.Logger
 	mgr                 ctrl.Manager
 	globalHubReconciler *MulticlusterGlobalHubReconciler
 	conn                *transport.ConnCredential
+	connLock            sync.RWMutex
 }
 
 func (r *KafkaController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
@@ -38,14 +45,35 @@ func (r *KafkaController) Reconcile(ctx context.Context, request ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	r.conn, err = r.globalHubReconciler.ReconcileTransport(ctx, mgh, transport.StrimziTransporter)
+	conn, err := r.globalHubReconciler.ReconcileTransport(ctx, mgh, transport.StrimziTransporter)
 	if err != nil {
 		r.Log.Error(err, "failed to get connection from kafka reconciler")
 		return ctrl.Result{}, err
 	}
+	if r.setConn(conn) {
+		r.Log.Info("the kafka connection is updated, trigger the global hub reconciler")
+		r.globalHubReconciler.triggerReconcile()
+	}
 	return ctrl.Result{}, nil
 }
 
+func (r *KafkaController) getConn() *transport.ConnCredential {
+	r.connLock.RLock()
+	defer r.connLock.RUnlock()
+	return r.conn
+}
+
+// setConn returns true if the connection is changed
+func (r *KafkaController) setConn(conn *transport.ConnCredential) bool {
+	r.connLock.Lock()
+	defer r.connLock.Unlock()
+	if reflect.DeepEqual(r.conn, conn) {
+		return false
+	}
+	r.conn = conn
+	return true
+}
+
 var kafkaPred = predicate.Funcs{
 	CreateFunc: func(e event.CreateEvent) bool {
 		return false
@@ -58,6 +86,24 @@ var kafkaPred = predicate.Funcs{
 	},
 }
 
+// the secrets of the kafka users are created and rotated by the strimzi user operator
+var kafkaUserSecretCond = func(obj client.Object) bool {
+	return obj.GetLabels()["strimzi.io/cluster"] == transportprotocol.KafkaClusterName &&
+		obj.GetLabels()["strimzi.io/kind"] == "KafkaUser"
+}
+
+var kafkaUserSecretPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return kafkaUserSecretCond(e.Object)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return kafkaUserSecretCond(e.ObjectNew)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return kafkaUserSecretCond(e.Object)
+	},
+}
+
 // initialize the kafka transporter and then start the kafka/user/topic controller
 func startKafkaController(ctx context.Context, mgr ctrl.Manager,
 	reconciler *MulticlusterGlobalHubReconciler,
@@ -80,10 +126,15 @@ func startKafkaController(ctx context.Context, mgr ctrl.Manager,
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaPred)).
 		Watches(&kafkav1beta2.KafkaTopic{},
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaPred)).
+		Watches(&corev1.Secret{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaUserSecretPred)).
 		Complete(r)
 	if err != nil {
 		return nil, err
 	}
+	if err = addKafkaTopicAutoscaler(mgr, r); err != nil {
+		return nil, err
+	}
 	r.Log.Info("kafka controller is started")
 	return r, nil
 }
@@ -2,25 +2,44 @@ package hubofhubs
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	commonconstants "github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
-func (r *MulticlusterGlobalHubReconciler) reconcileManagedHubs(ctx context.Context) error {
+func (r *MulticlusterGlobalHubReconciler) reconcileManagedHubs(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	// mark the global hub cluster with the claim, so it's told from the managed hubs once it's imported by a hub
+	if err := r.reconcileGlobalHubClaim(ctx, mgh); err != nil {
+		return err
+	}
+
 	clusters := &clusterv1.ManagedClusterList{}
 	if err := r.List(ctx, clusters, &client.ListOptions{}); err != nil {
 		return err
 	}
 
+	loops := []string{}
 	for idx, managedHub := range clusters.Items {
-		if managedHub.Name == constants.LocalClusterName {
+		if reason := utils.ImportLoopReason(&clusters.Items[idx], string(mgh.GetUID())); reason != "" {
+			if managedHub.Name != constants.LocalClusterName {
+				r.Log.Info("skip the managed hub to avoid the import loop", "cluster", managedHub.Name, "reason", reason)
+				loops = append(loops, fmt.Sprintf("%s(%s)", managedHub.Name, reason))
+			}
 			continue
 		}
 		orgAnnotations := managedHub.GetAnnotations()
@@ -40,7 +59,41 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManagedHubs(ctx context.Conte
 		}
 	}
 
-	return nil
+	return r.setImportLoopCondition(ctx, mgh, loops)
+}
+
+func (r *MulticlusterGlobalHubReconciler) reconcileGlobalHubClaim(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	claim := &clusterv1alpha1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: commonconstants.GlobalHubClusterClaimName,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, claim, func() error {
+		labels := claim.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[commonconstants.GlobalHubOwnerLabelKey] = commonconstants.GHOperatorOwnerLabelVal
+		claim.SetLabels(labels)
+		claim.Spec.Value = string(mgh.GetUID())
+		return nil
+	})
+	return err
+}
+
+func (r *MulticlusterGlobalHubReconciler) setImportLoopCondition(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, loops []string,
+) error {
+	if len(loops) == 0 {
+		return condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_IMPORT_LOOP,
+			condition.CONDITION_STATUS_FALSE, condition.CONDITION_REASON_NO_LOOP, condition.CONDITION_MESSAGE_NO_LOOP)
+	}
+	sort.Strings(loops)
+	return condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_IMPORT_LOOP,
+		condition.CONDITION_STATUS_TRUE, condition.CONDITION_REASON_IMPORT_LOOP,
+		fmt.Sprintf("%s: %s", condition.CONDITION_MESSAGE_IMPORT_LOOP, strings.Join(loops, ", ")))
 }
 
 func (r *MulticlusterGlobalHubReconciler) pruneManagedHubs(ctx context.Context) error {
@@ -2,15 +2,18 @@ package hubofhubs
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"reflect"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/restmapper"
@@ -89,6 +92,50 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 	trans := config.GetTransporter()
 
 	transportTopic := trans.GenerateClusterTopic(transportprotocol.GlobalHubClusterName)
+	// the status of the hubs in the regions is consumed from the regional kafka clusters besides the default one
+	additionalKafkaChanged, err := r.applyAdditionalKafkaSecret(ctx, mgh)
+	if err != nil {
+		return err
+	}
+	additionalKafkaSecret := ""
+	if len(config.GetRegionalTransporters()) > 0 {
+		additionalKafkaSecret = additionalKafkaSecretName
+	}
+	// the hubs are sharded by their status topics, so it requires the default status topic per hub
+	enableHubSharding := false
+	if shards := config.GetManagerShards(mgh); shards > 0 {
+		if additionalKafkaSecret != "" {
+			log.Info("skip sharding the hubs since the status is consumed from the regional kafka clusters")
+		} else if transportTopic.StatusTopic == transportprotocol.StatusTopicRegex {
+			replicas, enableHubSharding = shards, true
+		} else {
+			log.Info("skip sharding the hubs since the status topic isn't the default one per hub",
+				"topic", transportTopic.StatusTopic)
+		}
+	}
+	// the standby replica of the high availability keeps the status consumer paused, so it takes over in seconds
+	enableWarmStandby := replicas > 1 && !enableHubSharding
+	// the manager of the disaster recovery standby isn't running until the standby is promoted, or it writes the
+	// database replicated from the primary and consumes the mirrored topics
+	if config.IsStandby(mgh) {
+		replicas = 0
+	}
+	scheduler := mgh.Spec.Scheduler
+	if scheduler == nil {
+		scheduler = &v1alpha4.SchedulerConfig{}
+	}
+	archiveSecret, err := r.getArchiveSecret(ctx)
+	if err != nil {
+		return err
+	}
+	inventorySecret, err := r.getInventorySecret(ctx)
+	if err != nil {
+		return err
+	}
+	notificationConfigHash, err := r.getNotificationConfigHash(ctx)
+	if err != nil {
+		return err
+	}
 	transportConn, err := trans.GetConnCredential(transportprotocol.DefaultGlobalHubKafkaUser)
 	if err != nil {
 		return fmt.Errorf("failed to get global hub transport connection: %v", err)
@@ -97,7 +144,7 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 	if r.MiddlewareConfig.StorageConn == nil {
 		return fmt.Errorf("failed to get storage connection")
 	}
-	if isMiddlewareUpdated(r.MiddlewareConfig) {
+	if isMiddlewareUpdated(r.MiddlewareConfig) || additionalKafkaChanged {
 		err = commonutils.RestartPod(ctx, r.KubeClient, commonutils.GetDefaultNamespace(), constants.ManagerDeploymentName)
 		if err != nil {
 			return fmt.Errorf("failed to restart manager pod: %v", err)
@@ -118,10 +165,11 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 			KafkaCACert:            transportConn.CACert,
 			KafkaClientCert:        transportConn.ClientCert,
 			KafkaClientKey:         transportConn.ClientKey,
-			KafkaBootstrapServer:   transportConn.BootstrapServer,
+			KafkaBootstrapServer:   base64.StdEncoding.EncodeToString([]byte(transportConn.BootstrapServer)),
 			KafkaConsumerTopic:     transportTopic.StatusTopic,
 			KafkaProducerTopic:     transportTopic.SpecTopic,
 			KafkaEventTopic:        transportTopic.EventTopic,
+			AdditionalKafkaSecret:  additionalKafkaSecret,
 			Namespace:              commonutils.GetDefaultNamespace(),
 			MessageCompressionType: string(operatorconstants.GzipCompressType),
 			TransportType:          string(transport.Kafka),
@@ -129,6 +177,10 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 			RenewDeadline:          strconv.Itoa(r.LeaderElection.RenewDeadline),
 			RetryPeriod:            strconv.Itoa(r.LeaderElection.RetryPeriod),
 			SchedulerInterval:      config.GetSchedulerInterval(mgh),
+			HubInactiveTimeout:     config.GetHubInactiveTimeout(mgh),
+			SchedulerTimeZone:      scheduler.TimeZone,
+			ComplianceHistoryCron:  scheduler.LocalComplianceHistory,
+			DataRetentionCron:      scheduler.DataRetention,
 			SkipAuth:               config.SkipAuth(mgh),
 			LaunchJobNames:         config.GetLaunchJobNames(mgh),
 			NodeSelector:           mgh.Spec.NodeSelector,
@@ -136,7 +188,29 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 			RetentionMonth:         months,
 			StatisticLogInterval:   config.GetStatisticLogInterval(),
 			EnableGlobalResource:   r.EnableGlobalResource,
+			EnableHubSharding:      enableHubSharding,
+			EnableWarmStandby:      enableWarmStandby,
+			EnableMetrics:          mgh.Spec.EnableMetrics,
+			EnableDiagnostics:      mgh.Spec.EnableDiagnostics,
+			EnableMessageSigning:   config.IsMessageSigningEnabled(mgh),
+			QuarantineAgents:       config.IsAgentQuarantineEnabled(mgh),
+			SpecDriftRemediation:   config.IsSpecDriftRemediationEnabled(mgh),
+			DataCollectionProfile:  string(config.GetDataCollectionProfile(mgh)),
 			LogLevel:               r.LogLevel,
+			ArchiveSecret:          archiveSecret.Name,
+			ArchiveEndpoint:        string(archiveSecret.Data["endpoint"]),
+			ArchiveBucket:          string(archiveSecret.Data["bucket"]),
+			ArchiveRegion:          string(archiveSecret.Data["region"]),
+			ArchivePrefix:          string(archiveSecret.Data["prefix"]),
+			ArchiveInsecure:        string(archiveSecret.Data["insecure"]) == "true",
+			InventorySecret:        inventorySecret.Name,
+			InventoryServerURL:     string(inventorySecret.Data["server_url"]),
+			InventoryReporterID:    string(inventorySecret.Data["reporter_instance_id"]),
+			InventoryWorkspaceID:   string(inventorySecret.Data["workspace_id"]),
+			InventoryCACert:        len(inventorySecret.Data["ca.crt"]) > 0,
+			InventoryClientCert:    len(inventorySecret.Data["tls.crt"]) > 0,
+			InventoryToken:         len(inventorySecret.Data["token"]) > 0,
+			NotificationConfigHash: notificationConfigHash,
 			Resources:              utils.GetResources(operatorconstants.Manager, mgh.Spec.AdvancedConfig),
 		}, nil
 	})
@@ -151,6 +225,63 @@ func (r *MulticlusterGlobalHubReconciler) reconcileManager(ctx context.Context,
 	return nil
 }
 
+// getArchiveSecret returns the object storage to archive the expired partitions, the secret is empty if the archive
+// isn't configured
+func (r *MulticlusterGlobalHubReconciler) getArchiveSecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      constants.GHArchiveSecretName,
+		Namespace: commonutils.GetDefaultNamespace(),
+	}, secret)
+	if errors.IsNotFound(err) {
+		return &corev1.Secret{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the archive secret: %w", err)
+	}
+	return secret, nil
+}
+
+// getInventorySecret returns the inventory API which the clusters and policies are exported to, the secret is empty
+// if the export isn't configured
+func (r *MulticlusterGlobalHubReconciler) getInventorySecret(ctx context.Context) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      constants.GHInventorySecretName,
+		Namespace: commonutils.GetDefaultNamespace(),
+	}, secret)
+	if errors.IsNotFound(err) {
+		return &corev1.Secret{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the inventory secret: %w", err)
+	}
+	return secret, nil
+}
+
+// getNotificationConfigHash returns the hash of the notification config, it's added to the pod of the manager, so the
+// manager is restarted to load the changed config. The hash is empty if the notification isn't configured.
+func (r *MulticlusterGlobalHubReconciler) getNotificationConfigHash(ctx context.Context) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      constants.GHNotificationConfigMapName,
+		Namespace: commonutils.GetDefaultNamespace(),
+	}, configMap)
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get the notification configmap: %w", err)
+	}
+	notificationConfig, found := configMap.Data[constants.GHNotificationConfigKey]
+	if !found {
+		r.Log.Info("skip the notification, the config isn't found in the configmap",
+			"configmap", constants.GHNotificationConfigMapName, "key", constants.GHNotificationConfigKey)
+		return "", nil
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(notificationConfig))), nil
+}
+
 func isMiddlewareUpdated(curMiddlewareConfig *MiddlewareConfig) bool {
 	if curMiddlewareConfig == nil {
 		return false
@@ -213,6 +344,11 @@ func manipulateObj(objs []*unstructured.Unstructured,
 		labels[constants.GlobalHubOwnerLabelKey] = constants.GHOperatorOwnerLabelVal
 		obj.SetLabels(labels)
 
+		// set the security context of the pods
+		if err := renderer.SetPodSecurityProfile(obj, config.GetPodSecurityProfile(mgh)); err != nil {
+			return err
+		}
+
 		if err := hohDeployer.Deploy(obj); err != nil {
 			return err
 		}
@@ -238,6 +374,7 @@ type ManagerVariables struct {
 	KafkaClientCert        string
 	KafkaClientKey         string
 	KafkaBootstrapServer   string
+	AdditionalKafkaSecret  string
 	MessageCompressionType string
 	TransportType          string
 	Namespace              string
@@ -245,6 +382,10 @@ type ManagerVariables struct {
 	RenewDeadline          string
 	RetryPeriod            string
 	SchedulerInterval      string
+	HubInactiveTimeout     string
+	SchedulerTimeZone      string
+	ComplianceHistoryCron  string
+	DataRetentionCron      string
 	SkipAuth               bool
 	LaunchJobNames         string
 	NodeSelector           map[string]string
@@ -252,6 +393,28 @@ type ManagerVariables struct {
 	RetentionMonth         int
 	StatisticLogInterval   string
 	EnableGlobalResource   bool
+	EnableHubSharding      bool
+	EnableWarmStandby      bool
+	EnableMetrics          bool
+	EnableDiagnostics      bool
+	EnableMessageSigning   bool
+	QuarantineAgents       bool
+	SpecDriftRemediation   bool
+	DataCollectionProfile  string
 	LogLevel               string
 	Resources              *corev1.ResourceRequirements
+	ArchiveSecret          string
+	ArchiveEndpoint        string
+	ArchiveBucket          string
+	ArchiveRegion          string
+	ArchivePrefix          string
+	ArchiveInsecure        bool
+	InventorySecret        string
+	InventoryServerURL     string
+	InventoryReporterID    string
+	InventoryWorkspaceID   string
+	InventoryCACert        bool
+	InventoryClientCert    bool
+	InventoryToken         bool
+	NotificationConfigHash string
 }
@@ -0,0 +1,138 @@
+package hubofhubs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	commonutils "github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func Test_getNotificationConfigHash(t *testing.T) {
+	notificationConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.GHNotificationConfigMapName,
+				Namespace: commonutils.GetDefaultNamespace(),
+			},
+			Data: data,
+		}
+	}
+
+	r := &MulticlusterGlobalHubReconciler{Client: fake.NewClientBuilder().Build(), Log: logr.Discard()}
+	hash, err := r.getNotificationConfigHash(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, hash, "the notification is disabled without the configmap")
+
+	r.Client = fake.NewClientBuilder().WithObjects(notificationConfigMap(map[string]string{"config": "sinks: []"})).
+		Build()
+	hash, err = r.getNotificationConfigHash(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, hash, "the notification is disabled without the config key")
+
+	r.Client = fake.NewClientBuilder().WithObjects(notificationConfigMap(map[string]string{
+		constants.GHNotificationConfigKey: "sinks: []",
+	})).Build()
+	hash, err = r.getNotificationConfigHash(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// the hash is changed with the config, so the manager is restarted to load it
+	r.Client = fake.NewClientBuilder().WithObjects(notificationConfigMap(map[string]string{
+		constants.GHNotificationConfigKey: "sinks: [{name: team-a, type: slack, url: https://example.com}]",
+	})).Build()
+	changedHash, err := r.getNotificationConfigHash(context.Background())
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func Test_renderManagerNotification(t *testing.T) {
+	renderDeployment := func(notificationConfigHash string) *appsv1.Deployment {
+		objects, err := renderer.NewHoHRenderer(fs).Render("manifests/manager", "", func(string) (interface{}, error) {
+			return ManagerVariables{
+				Namespace:              "default",
+				Resources:              &corev1.ResourceRequirements{},
+				NotificationConfigHash: notificationConfigHash,
+			}, nil
+		})
+		require.NoError(t, err)
+		for _, obj := range objects {
+			if obj.GetKind() != "Deployment" {
+				continue
+			}
+			deployment := &appsv1.Deployment{}
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment))
+			return deployment
+		}
+		t.Fatal("the manager deployment isn't rendered")
+		return nil
+	}
+
+	deployment := renderDeployment("")
+	assert.NotContains(t, deployment.Spec.Template.Spec.Containers[0].Args,
+		"--notification-config-path=/notification/notification.yaml")
+	assert.Empty(t, deployment.Spec.Template.Annotations)
+
+	deployment = renderDeployment("abc")
+	assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Args,
+		"--notification-config-path=/notification/notification.yaml")
+	assert.Equal(t, "abc",
+		deployment.Spec.Template.Annotations["global-hub.open-cluster-management.io/notification-config-hash"])
+	assert.Contains(t, deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "notification",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: constants.GHNotificationConfigMapName},
+			Items:                []corev1.KeyToPath{{Key: constants.GHNotificationConfigKey, Path: "notification.yaml"}},
+		}},
+	})
+}
+
+func Test_renderManagerPrometheusRule(t *testing.T) {
+	renderAlerts := func(enableMetrics bool) map[string]string {
+		objects, err := renderer.NewHoHRenderer(fs).Render("manifests/manager", "", func(string) (interface{}, error) {
+			return ManagerVariables{
+				Namespace:     "default",
+				Resources:     &corev1.ResourceRequirements{},
+				EnableMetrics: enableMetrics,
+			}, nil
+		})
+		require.NoError(t, err)
+		alerts := map[string]string{}
+		for _, obj := range objects {
+			if obj.GetKind() != "PrometheusRule" {
+				continue
+			}
+			groups, _, err := unstructured.NestedSlice(obj.Object, "spec", "groups")
+			require.NoError(t, err)
+			for _, group := range groups {
+				rules, _, err := unstructured.NestedSlice(group.(map[string]interface{}), "rules")
+				require.NoError(t, err)
+				for _, rule := range rules {
+					alert := rule.(map[string]interface{})
+					alerts[alert["alert"].(string)] = alert["expr"].(string)
+				}
+			}
+		}
+		return alerts
+	}
+
+	assert.Empty(t, renderAlerts(false), "the alerts aren't rendered without the metrics")
+
+	alerts := renderAlerts(true)
+	assert.Contains(t, alerts["GlobalHubManagedHubStatusStale"],
+		"multicluster_global_hub_status_last_processed_timestamp_seconds")
+	assert.Contains(t, alerts["GlobalHubStatusHandlerErrors"], "multicluster_global_hub_status_handler_errors_total")
+	assert.Contains(t, alerts["GlobalHubKafkaConsumerLagHigh"], `kafka_consumergroup_lag{namespace="default"}`)
+	assert.Contains(t, alerts, "GlobalHubJobFailed")
+}
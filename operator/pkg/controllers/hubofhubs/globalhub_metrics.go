@@ -2,6 +2,7 @@ package hubofhubs
 
 import (
 	"context"
+	"fmt"
 
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -69,6 +70,16 @@ func (r *MulticlusterGlobalHubReconciler) reconcileMetrics(ctx context.Context,
 					Port:     "metrics",
 					Path:     "/metrics",
 					Interval: promv1.Duration(config.GetMetricsScrapeInterval(mgh)),
+					// the metrics are served with the serving certificate of the service, and the token of the
+					// prometheus is authenticated and authorized by the manager
+					Scheme:          "https",
+					BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+					TLSConfig: &promv1.TLSConfig{
+						CAFile: "/etc/prometheus/configmaps/serving-certs-ca-bundle/service-ca.crt",
+						SafeTLSConfig: promv1.SafeTLSConfig{
+							ServerName: fmt.Sprintf("multicluster-global-hub-manager.%s.svc", mgh.Namespace),
+						},
+					},
 				},
 			},
 		},
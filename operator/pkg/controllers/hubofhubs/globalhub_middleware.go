@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -35,6 +36,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/postgres"
@@ -50,6 +52,8 @@ import (
 // 1. create the kafka and postgres subscription at the same time
 // 2. then create the kafka and postgres resources at the same time
 // 3. wait for kafka and postgres ready
+// the built-in kafka and the storage are reconciled by their own controllers once they're started as well, which
+// trigger the global hub reconciler if the connections are changed by the middleware resources
 func (r *MulticlusterGlobalHubReconciler) ReconcileMiddleware(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
 ) (ctrl.Result, error) {
 	// initialize postgres and kafka at the same time
@@ -92,11 +96,11 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileMiddleware(ctx context.Contex
 
 			r.KafkaInit = true
 		}
-		if r.KafkaController == nil || r.KafkaController.conn == nil {
+		if r.KafkaController == nil || r.KafkaController.getConn() == nil {
 			errorChan <- errors.New("the kafka controller is not ready")
 			return
 		}
-		r.MiddlewareConfig.TransportConn = r.KafkaController.conn
+		r.MiddlewareConfig.TransportConn = r.KafkaController.getConn()
 	}()
 
 	// initialize storage
@@ -104,11 +108,18 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileMiddleware(ctx context.Contex
 	go func() {
 		defer wg.Done()
 
-		conn, e := r.ReconcileStorage(ctx, mgh)
-		if e != nil {
+		if r.StorageController == nil {
+			controller, e := startStorageController(ctx, r.Manager, r, mgh)
+			if e != nil {
+				errorChan <- e
+				return
+			}
+			r.StorageController = controller
+		} else if _, e := r.StorageController.reconcile(ctx, mgh); e != nil {
 			errorChan <- e
+			return
 		}
-		r.MiddlewareConfig.StorageConn = conn
+		r.MiddlewareConfig.StorageConn = r.StorageController.getConn()
 	}()
 
 	go func() {
@@ -148,10 +159,16 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileTransport(ctx context.Context
 			return nil, err
 		}
 	case transport.SecretTransporter:
+		trustedCABundle, err := config.GetTrustedCABundle(ctx, r.Client, mgh.Namespace,
+			mgh.Spec.DataLayer.Kafka.TrustedCABundles)
+		if err != nil {
+			return nil, err
+		}
 		trans = transportprotocol.NewBYOTransporter(ctx, types.NamespacedName{
 			Namespace: mgh.Namespace,
 			Name:      constants.GHTransportSecretName,
-		}, r.Client)
+		}, r.Client, transportprotocol.WithTrustedCABundle(trustedCABundle),
+			transportprotocol.WithReadOnly(mgh.Spec.DataLayer.Kafka.ReadOnly))
 	}
 
 	// create the user to connect the transport instance
@@ -163,6 +180,9 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileTransport(ctx context.Context
 	// it's a placeholder for the manager to subscribe the `^status.*`
 	topics := trans.GenerateClusterTopic(transportprotocol.GlobalHubClusterName)
 	err = trans.CreateTopic(topics)
+	if e := r.setKafkaTopicsCondition(ctx, mgh, transProtocol, err); e != nil {
+		return nil, e
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -196,11 +216,44 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileTransport(ctx context.Context
 	return conn, err
 }
 
+// setKafkaTopicsCondition reports the validation result of the topics if the BYO kafka is read-only, the condition is
+// removed once it isn't read-only
+func (r *MulticlusterGlobalHubReconciler) setKafkaTopicsCondition(ctx context.Context,
+	mgh *v1alpha4.MulticlusterGlobalHub, transProtocol transport.TransportProtocol, topicErr error,
+) error {
+	if transProtocol != transport.SecretTransporter || !mgh.Spec.DataLayer.Kafka.ReadOnly {
+		if !condition.ContainsCondition(mgh, condition.CONDITION_TYPE_KAFKA_TOPICS_VALIDATED) {
+			return nil
+		}
+		return condition.DeleteCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_KAFKA_TOPICS_VALIDATED,
+			condition.CONDITION_REASON_KAFKA_TOPICS_VALIDATED)
+	}
+	validationErr := &transportprotocol.TopicValidationError{}
+	if errors.As(topicErr, &validationErr) {
+		return condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_KAFKA_TOPICS_VALIDATED,
+			condition.CONDITION_STATUS_FALSE, condition.CONDITION_REASON_KAFKA_TOPICS_INVALID,
+			strings.Join(validationErr.Problems, "; "))
+	}
+	if topicErr != nil {
+		// the topics aren't validated, e.g. the kafka cluster isn't reachable
+		return nil
+	}
+	return condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_KAFKA_TOPICS_VALIDATED,
+		condition.CONDITION_STATUS_TRUE, condition.CONDITION_REASON_KAFKA_TOPICS_VALIDATED,
+		condition.CONDITION_MESSAGE_KAFKA_TOPICS_VALIDATED)
+}
+
 func (r *MulticlusterGlobalHubReconciler) ReconcileStorage(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
 ) (*postgres.PostgresConnection, error) {
 	// support BYO postgres
 	pgConnection, err := config.GetPGConnectionFromGHStorageSecret(ctx, r.Client)
 	if err == nil {
+		trustedCABundle, err := config.GetTrustedCABundle(ctx, r.Client, mgh.Namespace,
+			mgh.Spec.DataLayer.Postgres.TrustedCABundles)
+		if err != nil {
+			return nil, err
+		}
+		pgConnection.CACert = config.MergeCABundles(pgConnection.CACert, trustedCABundle)
 		return pgConnection, nil
 	} else if err != nil && !apierrors.IsNotFound(err) {
 		return nil, err
@@ -243,6 +296,13 @@ func (r *MulticlusterGlobalHubReconciler) ReconcileStorage(ctx context.Context,
 				return true, nil
 			})
 	}
+
+	// convert the data of the previous built-in postgres if it's switched by the mgh
+	if pgConnection != nil {
+		if err := r.reconcilePostgresConversion(ctx, mgh, pgConnection); err != nil {
+			return nil, err
+		}
+	}
 	return pgConnection, nil
 }
 
@@ -0,0 +1,293 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubofhubs
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	"github.com/jackc/pgx/v4"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+	transportprotocol "github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/version"
+)
+
+//go:embed upgrade
+var upgradeFS embed.FS
+
+// migration is a step of the operator upgrade. The migrations are applied in order and each of them is only applied
+// once, the applied ones are recorded in the status.migrations table.
+type migration struct {
+	name    string
+	migrate migrateFunc
+}
+
+// migrateFunc runs the migration, the tx is the transaction in which the migration is recorded
+type migrateFunc func(ctx context.Context, r *MulticlusterGlobalHubReconciler,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, tx pgx.Tx) error
+
+// migrations are applied in the order of the list. A new migration is always appended to the end, and the existing
+// ones must not be renamed or removed, otherwise they're applied again.
+var migrations = []migration{
+	{name: "1-upgrade-schema", migrate: sqlMigration("upgrade/1.upgrade.sql")},
+	{name: "2-kafka-topic-config", migrate: migrateKafkaTopicConfig},
+	{name: "3-transport-partition", migrate: sqlMigration("upgrade/3.transport-partition.sql")},
+	{name: "4-policy-event-taxonomy", migrate: sqlMigration("upgrade/4.policy-event-taxonomy.sql")},
+}
+
+// reconcileMigration applies the pending migrations once the operator version is changed. It's reconciled before the
+// manager, so the manager of the new version isn't rolled out until the database and the topics are migrated.
+func (r *MulticlusterGlobalHubReconciler) reconcileMigration(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	log := r.Log.WithName("migration")
+
+	operatorVersion := version.Get()
+	if migrationCompleted(mgh, operatorVersion) {
+		return nil
+	}
+	previousVersion := ""
+	if mgh.Status.Upgrade != nil {
+		previousVersion = mgh.Status.Upgrade.OperatorVersion
+	}
+	log.Info("running the migrations", "from", previousVersion, "to", operatorVersion)
+
+	applied, err := r.applyMigrations(ctx, mgh, operatorVersion)
+	if err != nil {
+		if e := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_MIGRATION_COMPLETED,
+			condition.CONDITION_STATUS_FALSE, condition.CONDITION_REASON_MIGRATION_FAILED, err.Error()); e != nil {
+			return condition.FailToSetConditionError(condition.CONDITION_STATUS_FALSE, e)
+		}
+		return err
+	}
+
+	upgradeStatus := &globalhubv1alpha4.UpgradeStatus{OperatorVersion: operatorVersion, Migrations: applied}
+	if !equality.Semantic.DeepEqual(upgradeStatus, mgh.Status.Upgrade) {
+		mgh.Status.Upgrade = upgradeStatus
+		if err := r.Client.Status().Update(ctx, mgh); err != nil {
+			return fmt.Errorf("failed to update the upgrade status: %w", err)
+		}
+	}
+	if err := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_MIGRATION_COMPLETED,
+		condition.CONDITION_STATUS_TRUE, condition.CONDITION_REASON_MIGRATION_COMPLETED,
+		fmt.Sprintf("The migrations of the operator %s are completed", operatorVersion)); err != nil {
+		return condition.FailToSetConditionError(condition.CONDITION_STATUS_TRUE, err)
+	}
+	log.Info("the migrations are completed", "version", operatorVersion, "applied", len(applied))
+	return nil
+}
+
+// migrationCompleted returns true if all the migrations are recorded by the operator of the version
+func migrationCompleted(mgh *globalhubv1alpha4.MulticlusterGlobalHub, operatorVersion string) bool {
+	if mgh.Status.Upgrade == nil || mgh.Status.Upgrade.OperatorVersion != operatorVersion {
+		return false
+	}
+	if !condition.ContainConditionStatus(mgh, condition.CONDITION_TYPE_MIGRATION_COMPLETED,
+		condition.CONDITION_STATUS_TRUE) {
+		return false
+	}
+	applied := map[string]bool{}
+	for _, m := range mgh.Status.Upgrade.Migrations {
+		applied[m.Name] = true
+	}
+	for _, m := range migrations {
+		if !applied[m.name] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyMigrations applies the migrations which aren't recorded in the database, and returns all the applied ones
+func (r *MulticlusterGlobalHubReconciler) applyMigrations(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, operatorVersion string,
+) ([]globalhubv1alpha4.MigrationStatus, error) {
+	if r.MiddlewareConfig == nil || r.MiddlewareConfig.StorageConn == nil {
+		return nil, fmt.Errorf("storage connection is nil")
+	}
+	conn, err := database.PostgresConnection(ctx, r.MiddlewareConfig.StorageConn.SuperuserDatabaseURI,
+		r.MiddlewareConfig.StorageConn.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(ctx); err != nil {
+			r.Log.Error(err, "failed to close connection to database")
+		}
+	}()
+
+	// the lock is shared with the database reconciler, so the migrations don't run with the schema initialization
+	if _, err = conn.Exec(ctx, fmt.Sprintf("select pg_advisory_lock(%s)", constants.LockId)); err != nil {
+		return nil, fmt.Errorf("failed to lock db: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(ctx, fmt.Sprintf("select pg_advisory_unlock(%s)", constants.LockId)); err != nil {
+			r.Log.Error(err, "failed to unlock db")
+		}
+	}()
+
+	applied, err := listMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	appliedNames := map[string]bool{}
+	for _, m := range applied {
+		appliedNames[m.Name] = true
+	}
+
+	for _, m := range migrations {
+		if appliedNames[m.name] {
+			continue
+		}
+		r.Log.Info("applying the migration", "name", m.name)
+		if err := applyMigration(ctx, r, mgh, conn, m, operatorVersion); err != nil {
+			return nil, fmt.Errorf("failed to apply the migration %s: %w", m.name, err)
+		}
+	}
+	return listMigrations(ctx, conn)
+}
+
+func applyMigration(ctx context.Context, r *MulticlusterGlobalHubReconciler,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, conn *pgx.Conn, m migration, operatorVersion string,
+) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// the rollback is a no-op once the transaction is committed
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := m.migrate(ctx, r, mgh, tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "INSERT INTO status.migrations (name, operator_version) VALUES ($1, $2)",
+		m.name, operatorVersion); err != nil {
+		return fmt.Errorf("failed to record the migration: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func listMigrations(ctx context.Context, conn *pgx.Conn) ([]globalhubv1alpha4.MigrationStatus, error) {
+	rows, err := conn.Query(ctx, "SELECT name, operator_version, applied_at FROM status.migrations ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the migrations: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []globalhubv1alpha4.MigrationStatus
+	for rows.Next() {
+		var name, operatorVersion string
+		var appliedAt time.Time
+		if err := rows.Scan(&name, &operatorVersion, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan the migrations: %w", err)
+		}
+		// the status is serialized in seconds, truncate it to compare with the existing one
+		applied = append(applied, globalhubv1alpha4.MigrationStatus{
+			Name:            name,
+			OperatorVersion: operatorVersion,
+			AppliedTime:     &metav1.Time{Time: appliedAt.Truncate(time.Second)},
+		})
+	}
+	return applied, rows.Err()
+}
+
+// sqlMigration executes the sql file of the upgrade directory
+func sqlMigration(file string) migrateFunc {
+	return func(ctx context.Context, _ *MulticlusterGlobalHubReconciler, _ *globalhubv1alpha4.MulticlusterGlobalHub,
+		tx pgx.Tx,
+	) error {
+		sqlBytes, err := upgradeFS.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if _, err := tx.Exec(ctx, string(sqlBytes)); err != nil {
+			return fmt.Errorf("failed to exec %s: %w", file, err)
+		}
+		return nil
+	}
+}
+
+// migrateKafkaTopicConfig updates the config of the existing global hub topics to the default topic config, the
+// topics are only created with the config, so the ones created by the previous releases have to be updated
+func migrateKafkaTopicConfig(ctx context.Context, r *MulticlusterGlobalHubReconciler,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, _ pgx.Tx,
+) error {
+	transProtocol, err := detectTransportProtocol(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	// the topics of the byo kafka aren't managed by the global hub
+	if transProtocol != transport.StrimziTransporter {
+		return nil
+	}
+
+	topics := &kafkav1beta2.KafkaTopicList{}
+	if err := r.Client.List(ctx, topics, client.InNamespace(mgh.Namespace), client.MatchingLabels{
+		"strimzi.io/cluster":             transportprotocol.KafkaClusterName,
+		constants.GlobalHubOwnerLabelKey: constants.GlobalHubOwnerLabelVal,
+	}); err != nil {
+		return fmt.Errorf("failed to list the kafka topics: %w", err)
+	}
+	for i := range topics.Items {
+		topic := &topics.Items[i]
+		updated, err := mergeTopicConfig(topic, transportprotocol.DefaultTopicConfig)
+		if err != nil {
+			return fmt.Errorf("failed to merge the config of the kafka topic %s: %w", topic.Name, err)
+		}
+		if !updated {
+			continue
+		}
+		if err := r.Client.Update(ctx, topic); err != nil {
+			return fmt.Errorf("failed to update the kafka topic %s: %w", topic.Name, err)
+		}
+		r.Log.Info("updated the config of the kafka topic", "topic", topic.Name)
+	}
+	return nil
+}
+
+// mergeTopicConfig sets the desired config into the topic, the other config of the topic is kept. It returns true if
+// the config of the topic is changed.
+func mergeTopicConfig(topic *kafkav1beta2.KafkaTopic, desired map[string]string) (bool, error) {
+	if topic.Spec == nil {
+		topic.Spec = &kafkav1beta2.KafkaTopicSpec{}
+	}
+	topicConfig := map[string]interface{}{}
+	if topic.Spec.Config != nil && len(topic.Spec.Config.Raw) > 0 {
+		if err := json.Unmarshal(topic.Spec.Config.Raw, &topicConfig); err != nil {
+			return false, err
+		}
+	}
+	updated := false
+	for key, value := range desired {
+		if existing, ok := topicConfig[key]; ok && fmt.Sprint(existing) == value {
+			continue
+		}
+		topicConfig[key] = value
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+	raw, err := json.Marshal(topicConfig)
+	if err != nil {
+		return false, err
+	}
+	topic.Spec.Config = &apiextensionsv1.JSON{Raw: raw}
+	return true, nil
+}
@@ -0,0 +1,119 @@
+package hubofhubs
+
+import (
+	"testing"
+
+	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+)
+
+func TestMigrations(t *testing.T) {
+	names := map[string]bool{}
+	for _, m := range migrations {
+		assert.False(t, names[m.name], "the migration %s is duplicated", m.name)
+		names[m.name] = true
+	}
+	// the sql files of the migrations are embedded
+	for _, file := range []string{
+		"upgrade/1.upgrade.sql",
+		"upgrade/3.transport-partition.sql",
+		"upgrade/4.policy-event-taxonomy.sql",
+	} {
+		_, err := upgradeFS.ReadFile(file)
+		require.NoError(t, err)
+	}
+}
+
+func TestMigrationCompleted(t *testing.T) {
+	completed := metav1.Condition{
+		Type:   condition.CONDITION_TYPE_MIGRATION_COMPLETED,
+		Status: condition.CONDITION_STATUS_TRUE,
+	}
+	allApplied := []globalhubv1alpha4.MigrationStatus{}
+	for _, m := range migrations {
+		allApplied = append(allApplied, globalhubv1alpha4.MigrationStatus{Name: m.name, OperatorVersion: "v1.1.0"})
+	}
+
+	tests := []struct {
+		name       string
+		status     globalhubv1alpha4.MulticlusterGlobalHubStatus
+		wantResult bool
+	}{
+		{
+			name:       "fresh install",
+			status:     globalhubv1alpha4.MulticlusterGlobalHubStatus{},
+			wantResult: false,
+		},
+		{
+			name: "completed by the current version",
+			status: globalhubv1alpha4.MulticlusterGlobalHubStatus{
+				Conditions: []metav1.Condition{completed},
+				Upgrade:    &globalhubv1alpha4.UpgradeStatus{OperatorVersion: "v1.2.0", Migrations: allApplied},
+			},
+			wantResult: true,
+		},
+		{
+			name: "the operator is upgraded",
+			status: globalhubv1alpha4.MulticlusterGlobalHubStatus{
+				Conditions: []metav1.Condition{completed},
+				Upgrade:    &globalhubv1alpha4.UpgradeStatus{OperatorVersion: "v1.1.0", Migrations: allApplied},
+			},
+			wantResult: false,
+		},
+		{
+			name: "the migration is failed",
+			status: globalhubv1alpha4.MulticlusterGlobalHubStatus{
+				Conditions: []metav1.Condition{{
+					Type:   condition.CONDITION_TYPE_MIGRATION_COMPLETED,
+					Status: condition.CONDITION_STATUS_FALSE,
+				}},
+				Upgrade: &globalhubv1alpha4.UpgradeStatus{OperatorVersion: "v1.2.0", Migrations: allApplied},
+			},
+			wantResult: false,
+		},
+		{
+			name: "a migration is pending",
+			status: globalhubv1alpha4.MulticlusterGlobalHubStatus{
+				Conditions: []metav1.Condition{completed},
+				Upgrade:    &globalhubv1alpha4.UpgradeStatus{OperatorVersion: "v1.2.0", Migrations: allApplied[:1]},
+			},
+			wantResult: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &globalhubv1alpha4.MulticlusterGlobalHub{Status: tt.status}
+			assert.Equal(t, tt.wantResult, migrationCompleted(mgh, "v1.2.0"))
+		})
+	}
+}
+
+func TestMergeTopicConfig(t *testing.T) {
+	desired := map[string]string{"cleanup.policy": "compact"}
+
+	topic := &kafkav1beta2.KafkaTopic{}
+	updated, err := mergeTopicConfig(topic, desired)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.JSONEq(t, `{"cleanup.policy":"compact"}`, string(topic.Spec.Config.Raw))
+
+	topic.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`{"cleanup.policy":"delete","retention.ms":60000}`)}
+	updated, err = mergeTopicConfig(topic, desired)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.JSONEq(t, `{"cleanup.policy":"compact","retention.ms":60000}`, string(topic.Spec.Config.Raw))
+
+	updated, err = mergeTopicConfig(topic, desired)
+	require.NoError(t, err)
+	assert.False(t, updated, "the topic shouldn't be updated if the config is up to date")
+
+	topic.Spec.Config = &apiextensionsv1.JSON{Raw: []byte(`invalid`)}
+	_, err = mergeTopicConfig(topic, desired)
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/postgres"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// the namespace of the cluster monitoring stack which scrapes the metrics of the global hub components
+const monitoringNamespace = "openshift-monitoring"
+
+// reconcileNetworkPolicy renders the NetworkPolicies which only allow the required ingress traffic of the manager,
+// the built-in postgres, the built-in grafana and the operator. The policy of the component which isn't installed,
+// e.g. the BYO postgres, selects no pods. The egress isn't restricted since the BYO kafka and postgres are reached
+// at arbitrary addresses. The kafka brokers are covered by the NetworkPolicies generated by the strimzi operator.
+func (r *MulticlusterGlobalHubReconciler) reconcileNetworkPolicy(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	log := r.Log.WithName("networkpolicy")
+
+	if !mgh.Spec.EnableNetworkPolicy {
+		return r.pruneNetworkPolicies(ctx, mgh)
+	}
+
+	policyObjects, err := renderNetworkPolicies(mgh)
+	if err != nil {
+		return err
+	}
+	policyDeployer := deployer.NewHoHDeployer(r.Client)
+
+	// create restmapper for deployer to find GVR
+	dc, err := discovery.NewDiscoveryClientForConfig(r.Manager.GetConfig())
+	if err != nil {
+		return err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc))
+
+	if err = manipulateObj(policyObjects, mgh, policyDeployer, mapper, r.GetScheme()); err != nil {
+		return fmt.Errorf("failed to create/update network policies: %w", err)
+	}
+
+	log.Info("network policies created/updated successfully")
+	return nil
+}
+
+func renderNetworkPolicies(mgh *globalhubv1alpha4.MulticlusterGlobalHub) ([]*unstructured.Unstructured, error) {
+	policyObjects, err := renderer.NewHoHRenderer(fs).Render("manifests/networkpolicy", "",
+		func(profile string) (interface{}, error) {
+			return struct {
+				Namespace              string
+				MonitoringNamespace    string
+				PostgresLabelKey       string
+				CrunchyClusterLabelKey string
+				CrunchyCluster         string
+				ConversionLabelKey     string
+			}{
+				Namespace:              mgh.GetNamespace(),
+				MonitoringNamespace:    monitoringNamespace,
+				PostgresLabelKey:       postgres.PostgresLabelKey,
+				CrunchyClusterLabelKey: crunchyClusterLabelKey,
+				CrunchyCluster:         postgres.PostgresName,
+				ConversionLabelKey:     postgresConversionLabelKey,
+			}, nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render network policies: %w", err)
+	}
+	return policyObjects, nil
+}
+
+// pruneNetworkPolicies removes the NetworkPolicies rendered by the operator once they're disabled, the ones
+// generated by the strimzi operator aren't labeled by the global hub
+func (r *MulticlusterGlobalHubReconciler) pruneNetworkPolicies(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	policyList := &networkingv1.NetworkPolicyList{}
+	if err := r.Client.List(ctx, policyList, client.InNamespace(mgh.GetNamespace()),
+		client.MatchingLabels{constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal}); err != nil {
+		return err
+	}
+	for idx := range policyList.Items {
+		if err := r.pruneObject(ctx, mgh, &policyList.Items[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package hubofhubs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/postgres"
+)
+
+func Test_renderNetworkPolicies(t *testing.T) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mgh", Namespace: "test-ns"},
+		Spec:       globalhubv1alpha4.MulticlusterGlobalHubSpec{EnableNetworkPolicy: true},
+	}
+	objects, err := renderNetworkPolicies(mgh)
+	require.NoError(t, err)
+
+	policies := map[string]*networkingv1.NetworkPolicy{}
+	for _, obj := range objects {
+		policy := &networkingv1.NetworkPolicy{}
+		require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, policy))
+		assert.Equal(t, "test-ns", policy.Namespace)
+		assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+		policies[policy.Name] = policy
+	}
+	require.Len(t, policies, 4)
+
+	monitoringPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": monitoringNamespace},
+		},
+	}
+	podPeer := func(labels map[string]string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: labels}}
+	}
+
+	t.Run("manager", func(t *testing.T) {
+		policy := policies["multicluster-global-hub-manager"]
+		require.NotNil(t, policy)
+		assert.Equal(t, map[string]string{"name": "multicluster-global-hub-manager"},
+			policy.Spec.PodSelector.MatchLabels)
+		require.Len(t, policy.Spec.Ingress, 2)
+		assert.Empty(t, policy.Spec.Ingress[0].From)
+		assert.Equal(t, []int{8080, 8443, 9443}, policyPorts(policy.Spec.Ingress[0]))
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{monitoringPeer}, policy.Spec.Ingress[1].From)
+		assert.Equal(t, []int{8384}, policyPorts(policy.Spec.Ingress[1]))
+	})
+
+	t.Run("operator", func(t *testing.T) {
+		policy := policies["multicluster-global-hub-operator"]
+		require.NotNil(t, policy)
+		assert.Equal(t, map[string]string{"name": "multicluster-global-hub-operator"},
+			policy.Spec.PodSelector.MatchLabels)
+		require.Len(t, policy.Spec.Ingress, 2)
+		assert.Empty(t, policy.Spec.Ingress[0].From)
+		assert.Equal(t, []int{8081}, policyPorts(policy.Spec.Ingress[0]))
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{monitoringPeer}, policy.Spec.Ingress[1].From)
+		assert.Equal(t, []int{8080}, policyPorts(policy.Spec.Ingress[1]))
+	})
+
+	t.Run("grafana", func(t *testing.T) {
+		policy := policies["multicluster-global-hub-grafana"]
+		require.NotNil(t, policy)
+		grafanaLabels := map[string]string{"name": "multicluster-global-hub-grafana"}
+		assert.Equal(t, grafanaLabels, policy.Spec.PodSelector.MatchLabels)
+		require.Len(t, policy.Spec.Ingress, 2)
+		assert.Empty(t, policy.Spec.Ingress[0].From)
+		assert.Equal(t, []int{9443}, policyPorts(policy.Spec.Ingress[0]))
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{podPeer(grafanaLabels)}, policy.Spec.Ingress[1].From)
+		assert.Equal(t, []int{9094}, policyPorts(policy.Spec.Ingress[1]))
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		policy := policies["multicluster-global-hub-postgres"]
+		require.NotNil(t, policy)
+		// the label is shared by the statefulset postgres and the instances of the crunchy postgres
+		assert.Equal(t, map[string]string{postgres.PostgresLabelKey: "true"}, policy.Spec.PodSelector.MatchLabels)
+		require.Len(t, policy.Spec.Ingress, 3)
+
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{
+			podPeer(map[string]string{"name": "multicluster-global-hub-manager"}),
+			podPeer(map[string]string{"name": "multicluster-global-hub-grafana"}),
+			podPeer(map[string]string{"name": "multicluster-global-hub-operator"}),
+			podPeer(map[string]string{"strimzi.io/cluster": cdcConnectName, "strimzi.io/kind": "KafkaConnect"}),
+			{PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      postgresConversionLabelKey,
+					Operator: metav1.LabelSelectorOpExists,
+				}},
+			}},
+		}, policy.Spec.Ingress[0].From)
+		assert.Equal(t, []int{5432}, policyPorts(policy.Spec.Ingress[0]))
+
+		// the instances of the crunchy postgres reach each other on any port
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{
+			podPeer(map[string]string{crunchyClusterLabelKey: postgres.PostgresName}),
+		}, policy.Spec.Ingress[1].From)
+		assert.Empty(t, policy.Spec.Ingress[1].Ports)
+
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{monitoringPeer}, policy.Spec.Ingress[2].From)
+		assert.Equal(t, []int{9187}, policyPorts(policy.Spec.Ingress[2]))
+	})
+}
+
+func policyPorts(rule networkingv1.NetworkPolicyIngressRule) []int {
+	ports := []int{}
+	for _, port := range rule.Ports {
+		if port.Port != nil && port.Port.Type == intstr.Int {
+			ports = append(ports, port.Port.IntValue())
+		}
+	}
+	return ports
+}
@@ -88,7 +88,34 @@ func (r *MulticlusterGlobalHubReconciler) EnsureCrunchyPostgres(ctx context.Cont
 	if err != nil && errors.IsNotFound(err) {
 		return r.Client.Create(ctx, postgres.NewPostgres(postgres.PostgresName, utils.GetDefaultNamespace()))
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	updated := false
+	// the postgres cluster is shut down once its data is converted to the statefulset postgres, start it again if the
+	// crunchy postgres is used again
+	if postgresCluster.Spec.Shutdown != nil && *postgresCluster.Spec.Shutdown {
+		postgresCluster.Spec.Shutdown = nil
+		updated = true
+	}
+	// the instances of the cluster created before are labeled to be selected by the network policy
+	for i, instanceSet := range postgresCluster.Spec.InstanceSets {
+		if instanceSet.Metadata.GetLabelsOrNil()[postgres.PostgresLabelKey] == "true" {
+			continue
+		}
+		if instanceSet.Metadata == nil {
+			postgresCluster.Spec.InstanceSets[i].Metadata = &postgresv1beta1.Metadata{}
+		}
+		if postgresCluster.Spec.InstanceSets[i].Metadata.Labels == nil {
+			postgresCluster.Spec.InstanceSets[i].Metadata.Labels = map[string]string{}
+		}
+		postgresCluster.Spec.InstanceSets[i].Metadata.Labels[postgres.PostgresLabelKey] = "true"
+		updated = true
+	}
+	if updated {
+		return r.Client.Update(ctx, postgresCluster)
+	}
+	return nil
 }
 
 func (r *MulticlusterGlobalHubReconciler) InitPostgresByStatefulset(ctx context.Context,
@@ -124,6 +151,7 @@ func (r *MulticlusterGlobalHubReconciler) InitPostgresByStatefulset(ctx context.
 				StorageClass                 string
 				Resources                    *corev1.ResourceRequirements
 				EnableMetrics                bool
+				EnableLogicalReplication     bool
 			}{
 				Namespace:                    mgh.GetNamespace(),
 				PostgresImage:                config.GetImage(config.PostgresImageKey),
@@ -142,6 +170,10 @@ func (r *MulticlusterGlobalHubReconciler) InitPostgresByStatefulset(ctx context.
 				Resources: operatorutils.GetResources(operatorconstants.Postgres,
 					mgh.Spec.AdvancedConfig),
 				EnableMetrics: mgh.Spec.EnableMetrics,
+				// the debezium connector and the standby global hub read the changes by the logical replication,
+				// it's also enabled on the standby, so it publishes the changes once it's promoted
+				EnableLogicalReplication: config.IsChangeDataCaptureEnabled(mgh) ||
+					config.GetDisasterRecoveryRole(mgh) != "",
 			}, nil
 		})
 	if err != nil {
@@ -163,13 +195,17 @@ func (r *MulticlusterGlobalHubReconciler) InitPostgresByStatefulset(ctx context.
 	if err != nil {
 		return nil, err
 	}
+	return newStatefulsetPostgresConnection(credential, ca), nil
+}
+
+func newStatefulsetPostgresConnection(credential *postgresCredential, ca string) *postgres.PostgresConnection {
 	return &postgres.PostgresConnection{
 		SuperuserDatabaseURI: "postgresql://" + credential.postgresAdminUsername + ":" +
 			credential.postgresAdminUserPassword + partialPostgresURI,
 		ReadonlyUserDatabaseURI: "postgresql://" + credential.postgresReadonlyUsername + ":" +
 			credential.postgresReadonlyUserPassword + partialPostgresURI,
 		CACert: []byte(ca),
-	}, nil
+	}
 }
 
 func getPostgresCredential(ctx context.Context, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
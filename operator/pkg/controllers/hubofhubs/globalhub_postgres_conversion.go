@@ -0,0 +1,384 @@
+package hubofhubs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	postgresv1beta1 "github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/postgres"
+	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const (
+	// postgresConversionJobName dumps the database of the previous built-in postgres and restores it to the current one
+	postgresConversionJobName = "multicluster-global-hub-postgres-conversion"
+	// postgresConversionLabelKey is the direction of the conversion job, the job is restarted if it's changed
+	postgresConversionLabelKey  = "global-hub.open-cluster-management.io/postgres-conversion"
+	postgresConversionMountPath = "/conversion"
+
+	postgresToCrunchy     = "to-crunchy"
+	postgresToStatefulset = "to-statefulset"
+
+	// statefulsetPostgresName is the statefulset of the built-in postgres
+	statefulsetPostgresName = "multicluster-global-hub-postgres"
+	// crunchyClusterLabelKey labels the instances of the crunchy postgres cluster
+	crunchyClusterLabelKey = "postgres-operator.crunchydata.com/cluster"
+)
+
+// errPostgresConverting is returned until the conversion is completed, so the global hub isn't reconciled with the
+// database which doesn't have the data yet
+var errPostgresConverting = errors.New("the built-in postgres is being converted")
+
+// postgresConversion is the previous built-in postgres whose data is converted to the current one
+type postgresConversion struct {
+	direction string
+	source    *postgres.PostgresConnection
+	// image runs the pg_dump of the same version as the source postgres
+	image string
+}
+
+// reconcilePostgresConversion converts the data of the previous built-in postgres to the current one once the built-in
+// postgres is switched between the statefulset and the crunchy postgres cluster by the mgh. The manager is stopped
+// during the conversion, so the data isn't changed. The previous postgres is stopped rather than removed once the data
+// is converted, so its volumes are kept until they're removed by the administrator.
+func (r *MulticlusterGlobalHubReconciler) reconcilePostgresConversion(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, target *postgres.PostgresConnection,
+) error {
+	conversion, err := r.getPostgresConversion(ctx, mgh)
+	if err != nil || conversion == nil {
+		return err
+	}
+	log := r.Log.WithName("postgres-conversion")
+	jobs := r.KubeClient.BatchV1().Jobs(mgh.Namespace)
+
+	job, err := jobs.Get(ctx, postgresConversionJobName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && job.Labels[postgresConversionLabelKey] != conversion.direction {
+		// the built-in postgres is switched back before the conversion is completed
+		log.Info("restart the conversion of the postgres", "direction", conversion.direction)
+		if err := r.deletePostgresConversionJob(ctx, mgh); err != nil {
+			return err
+		}
+		return errPostgresConverting
+	}
+
+	if apierrors.IsNotFound(err) {
+		if err := r.startPostgresConversion(ctx, mgh, conversion, target); err != nil {
+			return err
+		}
+		log.Info("started the conversion of the postgres", "direction", conversion.direction)
+		if err := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_POSTGRES_CONVERTED,
+			condition.CONDITION_STATUS_FALSE, condition.CONDITION_REASON_POSTGRES_CONVERTING,
+			fmt.Sprintf("The data of the built-in postgres is being converted %s", conversion.direction)); err != nil {
+			return condition.FailToSetConditionError(condition.CONDITION_STATUS_FALSE, err)
+		}
+		return errPostgresConverting
+	}
+
+	switch {
+	case jobConditionTrue(job, batchv1.JobComplete):
+		if err := r.stopPreviousPostgres(ctx, conversion.direction); err != nil {
+			return err
+		}
+		if err := r.deletePostgresConversionJob(ctx, mgh); err != nil {
+			return err
+		}
+		// grant the privileges of the readonly user of the current postgres to the converted tables
+		DatabaseReconcileCounter = 0
+		log.Info("the conversion of the postgres is completed", "direction", conversion.direction)
+		if err := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_POSTGRES_CONVERTED,
+			condition.CONDITION_STATUS_TRUE, condition.CONDITION_REASON_POSTGRES_CONVERTED,
+			fmt.Sprintf("The data of the built-in postgres is converted %s", conversion.direction)); err != nil {
+			return condition.FailToSetConditionError(condition.CONDITION_STATUS_TRUE, err)
+		}
+		return nil
+	case jobConditionTrue(job, batchv1.JobFailed):
+		message := fmt.Sprintf("The conversion %s of the built-in postgres is failed, check the logs of the job %s "+
+			"and delete it to retry", conversion.direction, postgresConversionJobName)
+		if err := condition.SetCondition(ctx, r.Client, mgh, condition.CONDITION_TYPE_POSTGRES_CONVERTED,
+			condition.CONDITION_STATUS_FALSE, condition.CONDITION_REASON_POSTGRES_CONVERSION_FAILED,
+			message); err != nil {
+			return condition.FailToSetConditionError(condition.CONDITION_STATUS_FALSE, err)
+		}
+		return errors.New(message)
+	default:
+		return errPostgresConverting
+	}
+}
+
+// getPostgresConversion returns the previous built-in postgres if it's still running, it's nil if there isn't
+// anything to convert
+func (r *MulticlusterGlobalHubReconciler) getPostgresConversion(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) (*postgresConversion, error) {
+	if config.GetInstallCrunchyOperator(mgh) {
+		statefulset, err := r.KubeClient.AppsV1().StatefulSets(mgh.Namespace).Get(ctx, statefulsetPostgresName,
+			metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if statefulset.Spec.Replicas != nil && *statefulset.Spec.Replicas == 0 {
+			return nil, nil
+		}
+		credential, err := getPostgresCredential(ctx, mgh, r)
+		if err != nil {
+			return nil, err
+		}
+		ca, err := getPostgresCA(ctx, mgh, r)
+		if err != nil {
+			return nil, err
+		}
+		return &postgresConversion{
+			direction: postgresToCrunchy,
+			source:    newStatefulsetPostgresConnection(credential, ca),
+			image:     config.GetImage(config.PostgresImageKey),
+		}, nil
+	}
+
+	postgresCluster := &postgresv1beta1.PostgresCluster{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: postgres.PostgresName},
+		postgresCluster)
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if postgresCluster.Spec.Shutdown != nil && *postgresCluster.Spec.Shutdown {
+		return nil, nil
+	}
+	source, err := config.GetPGConnectionFromBuildInPostgres(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	image, err := r.getCrunchyPostgresImage(ctx, mgh.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &postgresConversion{direction: postgresToStatefulset, source: source, image: image}, nil
+}
+
+// getCrunchyPostgresImage returns the image of the crunchy postgres instances, the pg_dump of the statefulset
+// postgres image is older than the crunchy postgres server, so it can't dump the crunchy postgres
+func (r *MulticlusterGlobalHubReconciler) getCrunchyPostgresImage(ctx context.Context, namespace string,
+) (string, error) {
+	statefulsets, err := r.KubeClient.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", crunchyClusterLabelKey, postgres.PostgresName),
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, statefulset := range statefulsets.Items {
+		for _, container := range statefulset.Spec.Template.Spec.Containers {
+			if container.Name == "database" {
+				return container.Image, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("the instance of the crunchy postgres cluster %s isn't found", postgres.PostgresName)
+}
+
+// startPostgresConversion stops the manager and starts the job to convert the data to the target postgres
+func (r *MulticlusterGlobalHubReconciler) startPostgresConversion(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, conversion *postgresConversion,
+	target *postgres.PostgresConnection,
+) error {
+	_, err := r.KubeClient.AppsV1().Deployments(mgh.Namespace).Patch(ctx, constants.ManagerDeploymentName,
+		types.MergePatchType, []byte(`{"spec":{"replicas":0}}`), metav1.PatchOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to stop the manager: %w", err)
+	}
+
+	secretData, err := postgresConversionSecretData(conversion.source, target)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      postgresConversionJobName,
+			Namespace: mgh.Namespace,
+			Labels: map[string]string{
+				constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+			},
+		},
+		Data: secretData,
+	}
+	if err = controllerutil.SetControllerReference(mgh, secret, r.Scheme); err != nil {
+		return err
+	}
+	if _, err = operatorutils.ApplySecret(ctx, r.Client, secret); err != nil {
+		return fmt.Errorf("failed to apply the secret of the postgres conversion: %w", err)
+	}
+
+	job := newPostgresConversionJob(mgh, conversion)
+	if err = controllerutil.SetControllerReference(mgh, job, r.Scheme); err != nil {
+		return err
+	}
+	_, err = r.KubeClient.BatchV1().Jobs(mgh.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the job of the postgres conversion: %w", err)
+	}
+	return nil
+}
+
+// stopPreviousPostgres stops the postgres the data is converted from, the statefulset is scaled down and the crunchy
+// postgres cluster is shut down, so their volumes are kept
+func (r *MulticlusterGlobalHubReconciler) stopPreviousPostgres(ctx context.Context, direction string) error {
+	namespace := config.GetMGHNamespacedName().Namespace
+	if direction == postgresToCrunchy {
+		_, err := r.KubeClient.AppsV1().StatefulSets(namespace).Patch(ctx, statefulsetPostgresName,
+			types.MergePatchType, []byte(`{"spec":{"replicas":0}}`), metav1.PatchOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to stop the statefulset postgres: %w", err)
+		}
+		return nil
+	}
+
+	postgresCluster := &postgresv1beta1.PostgresCluster{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: postgres.PostgresName},
+		postgresCluster)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	postgresCluster.Spec.Shutdown = ptr.To(true)
+	if err := r.Client.Update(ctx, postgresCluster); err != nil {
+		return fmt.Errorf("failed to shut down the crunchy postgres cluster: %w", err)
+	}
+	return nil
+}
+
+func (r *MulticlusterGlobalHubReconciler) deletePostgresConversionJob(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
+	err := r.KubeClient.BatchV1().Jobs(mgh.Namespace).Delete(ctx, postgresConversionJobName, metav1.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the job of the postgres conversion: %w", err)
+	}
+	err = r.KubeClient.CoreV1().Secrets(mgh.Namespace).Delete(ctx, postgresConversionJobName,
+		metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the secret of the postgres conversion: %w", err)
+	}
+	return nil
+}
+
+// postgresConversionSecretData returns the superuser connections of the source and the target postgres, which verify
+// the servers with their CAs
+func postgresConversionSecretData(source, target *postgres.PostgresConnection) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, conn := range []struct {
+		name string
+		conn *postgres.PostgresConnection
+	}{
+		{name: "source", conn: source},
+		{name: "target", conn: target},
+	} {
+		databaseURI, err := url.Parse(conn.conn.SuperuserDatabaseURI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the database uri of the %s postgres: %w", conn.name, err)
+		}
+		if len(conn.conn.CACert) > 0 {
+			caKey := conn.name + "-ca.crt"
+			data[caKey] = conn.conn.CACert
+			query := databaseURI.Query()
+			query.Set("sslrootcert", filepath.Join(postgresConversionMountPath, caKey))
+			databaseURI.RawQuery = query.Encode()
+		}
+		data[conn.name+"-uri"] = []byte(databaseURI.String())
+	}
+	return data, nil
+}
+
+// postgresConversionScript dumps the source database and restores it to the target one, the existing objects of the
+// target database are replaced, the owners and the privileges are granted by the database reconciler
+const postgresConversionScript = `set -o pipefail
+pg_dump --dbname="$(cat /conversion/source-uri)" --format=custom --no-owner --no-privileges | \
+  pg_restore --dbname="$(cat /conversion/target-uri)" --clean --if-exists --no-owner --no-privileges --exit-on-error
+`
+
+func newPostgresConversionJob(mgh *globalhubv1alpha4.MulticlusterGlobalHub, conversion *postgresConversion,
+) *batchv1.Job {
+	imagePullPolicy := corev1.PullAlways
+	if mgh.Spec.ImagePullPolicy != "" {
+		imagePullPolicy = mgh.Spec.ImagePullPolicy
+	}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		NodeSelector:  mgh.Spec.NodeSelector,
+		Tolerations:   mgh.Spec.Tolerations,
+		Containers: []corev1.Container{{
+			Name:            "postgres-conversion",
+			Image:           conversion.image,
+			ImagePullPolicy: imagePullPolicy,
+			Command:         []string{"/bin/bash", "-c", postgresConversionScript},
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      "conversion",
+				MountPath: postgresConversionMountPath,
+				ReadOnly:  true,
+			}},
+		}},
+		Volumes: []corev1.Volume{{
+			Name: "conversion",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: postgresConversionJobName},
+			},
+		}},
+	}
+	if mgh.Spec.ImagePullSecret != "" {
+		podSpec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: mgh.Spec.ImagePullSecret}}
+	}
+	labels := map[string]string{
+		constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+		postgresConversionLabelKey:       conversion.direction,
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      postgresConversionJobName,
+			Namespace: mgh.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			// the target postgres may not accept the connections right after it's created
+			BackoffLimit: ptr.To(int32(6)),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec,
+			},
+		},
+	}
+}
+
+func jobConditionTrue(job *batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
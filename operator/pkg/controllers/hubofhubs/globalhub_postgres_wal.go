@@ -0,0 +1,251 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/jackc/pgx/v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const (
+	walMonitorInterval = 5 * time.Minute
+	walMonitorTimeout  = 30 * time.Second
+	// the WAL pending for the archive or retained by a replication slot is warned once it exceeds the percentage of the
+	// postgres storage, so there is time to fix the archive or drop the slot before the disk fills
+	walRetentionPercent = 20
+)
+
+// walSlot is the replication slot and the WAL it retains from being recycled
+type walSlot struct {
+	name          string
+	slotType      string
+	active        bool
+	retainedBytes int64
+}
+
+// walStats is the state of the WAL archive and the replication slots of the postgres
+type walStats struct {
+	archiveEnabled      bool
+	pendingArchiveBytes int64
+	lastArchivedTime    *time.Time
+	lastFailedTime      *time.Time
+	lastFailedWAL       string
+	slots               []walSlot
+}
+
+// postgresWALMonitor checks the WAL archive and the replication slots of the built-in postgres periodically. The WAL
+// is archived by the pgbackrest of the crunchy postgres cluster, and retained by the slots of its replicas, the
+// change data capture and the disaster recovery standby. The WAL isn't recycled until it's archived and consumed by
+// all the slots, so the failing archive or the inactive slot fills the disk of the postgres eventually.
+type postgresWALMonitor struct {
+	log               logr.Logger
+	client            client.Client
+	storageController *StorageController
+}
+
+func addPostgresWALMonitor(mgr ctrl.Manager, storageController *StorageController) error {
+	return mgr.Add(&postgresWALMonitor{
+		log:               ctrl.Log.WithName("postgres-wal-monitor"),
+		client:            mgr.GetClient(),
+		storageController: storageController,
+	})
+}
+
+func (m *postgresWALMonitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(walMonitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := m.monitor(ctx); err != nil {
+				m.log.Error(err, "failed to monitor the WAL of the built-in postgres")
+			}
+		}
+	}
+}
+
+func (m *postgresWALMonitor) monitor(ctx context.Context) error {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+	if err := m.client.Get(ctx, config.GetMGHNamespacedName(), mgh); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	conn := m.storageController.getConn()
+	if conn == nil {
+		return nil
+	}
+	// the WAL of the BYO postgres is managed by its owner
+	err := m.client.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: constants.GHStorageSecretName},
+		&corev1.Secret{})
+	if err == nil {
+		return m.deleteCondition(ctx, mgh)
+	}
+	if !errors.IsNotFound(err) {
+		return err
+	}
+
+	storageSize, err := resource.ParseQuantity(config.GetPostgresStorageSize(mgh))
+	if err != nil {
+		return fmt.Errorf("failed to parse the postgres storage size: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, walMonitorTimeout)
+	defer cancel()
+	pgConn, err := database.PostgresConnection(ctx, conn.SuperuserDatabaseURI, conn.CACert)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := pgConn.Close(ctx); err != nil {
+			m.log.Error(err, "failed to close connection to database")
+		}
+	}()
+	stats, err := getWALStats(ctx, pgConn)
+	if err != nil {
+		return err
+	}
+
+	status, reason, message := evaluateWALStats(stats, storageSize.Value()*walRetentionPercent/100)
+	if status == "" {
+		return m.deleteCondition(ctx, mgh)
+	}
+	if status == condition.CONDITION_STATUS_FALSE {
+		m.log.Info("the WAL of the built-in postgres is at risk", "reason", reason, "message", message)
+	}
+	return condition.SetCondition(ctx, m.client, mgh, condition.CONDITION_TYPE_POSTGRES_WAL_HEALTHY, status, reason,
+		message)
+}
+
+// deleteCondition removes the condition once neither the archive nor the replication slots are enabled
+func (m *postgresWALMonitor) deleteCondition(ctx context.Context, mgh *globalhubv1alpha4.MulticlusterGlobalHub) error {
+	if !condition.ContainsCondition(mgh, condition.CONDITION_TYPE_POSTGRES_WAL_HEALTHY) {
+		return nil
+	}
+	return condition.DeleteCondition(ctx, m.client, mgh, condition.CONDITION_TYPE_POSTGRES_WAL_HEALTHY,
+		condition.CONDITION_REASON_POSTGRES_WAL_HEALTHY)
+}
+
+// getWALStats reads the archiver and the replication slots, the WAL pending for the archive is the segments with the
+// ready status, which is counted by the superuser or the member of pg_monitor
+func getWALStats(ctx context.Context, conn *pgx.Conn) (*walStats, error) {
+	stats := &walStats{}
+	var inRecovery bool
+	var segmentSize int64
+	err := conn.QueryRow(ctx, `SELECT current_setting('archive_mode') <> 'off', pg_is_in_recovery(),
+		(SELECT setting::bigint FROM pg_settings WHERE name = 'wal_segment_size')`).Scan(
+		&stats.archiveEnabled, &inRecovery, &segmentSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the WAL settings: %w", err)
+	}
+
+	if stats.archiveEnabled {
+		err = conn.QueryRow(ctx, `SELECT last_archived_time, last_failed_time, COALESCE(last_failed_wal, '')
+			FROM pg_stat_archiver`).Scan(&stats.lastArchivedTime, &stats.lastFailedTime, &stats.lastFailedWAL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the WAL archiver: %w", err)
+		}
+		var pendingSegments int64
+		err = conn.QueryRow(ctx, `SELECT count(*) FROM pg_ls_archive_statusdir() WHERE name LIKE '%.ready'`).Scan(
+			&pendingSegments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count the WAL pending for the archive: %w", err)
+		}
+		stats.pendingArchiveBytes = pendingSegments * segmentSize
+	}
+
+	// the current WAL location isn't available during the recovery
+	if inRecovery {
+		return stats, nil
+	}
+	rows, err := conn.Query(ctx, `SELECT slot_name, slot_type, active,
+		COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn), 0)::bigint
+		FROM pg_replication_slots ORDER BY slot_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the replication slots: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		slot := walSlot{}
+		if err := rows.Scan(&slot.name, &slot.slotType, &slot.active, &slot.retainedBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan the replication slot: %w", err)
+		}
+		stats.slots = append(stats.slots, slot)
+	}
+	return stats, rows.Err()
+}
+
+// evaluateWALStats returns the condition of the WAL, the status is empty if neither the archive nor the replication
+// slots are enabled. The failing archive is reported before the WAL retained over the threshold.
+func evaluateWALStats(stats *walStats, thresholdBytes int64) (metav1.ConditionStatus, string, string) {
+	if !stats.archiveEnabled && len(stats.slots) == 0 {
+		return "", "", ""
+	}
+
+	reason := ""
+	warnings := []string{}
+	if stats.archiveEnabled && stats.lastFailedTime != nil &&
+		(stats.lastArchivedTime == nil || stats.lastFailedTime.After(*stats.lastArchivedTime)) {
+		reason = condition.CONDITION_REASON_WAL_ARCHIVE_FAILING
+		warnings = append(warnings, fmt.Sprintf("the WAL archive is failing since %s at %s, %s is pending",
+			stats.lastFailedTime.UTC().Format(time.RFC3339), stats.lastFailedWAL,
+			formatWALBytes(stats.pendingArchiveBytes)))
+	} else if stats.archiveEnabled && stats.pendingArchiveBytes > thresholdBytes {
+		reason = condition.CONDITION_REASON_WAL_ARCHIVE_LAGGING
+		warnings = append(warnings, fmt.Sprintf("the WAL archive is lagging, %s is pending",
+			formatWALBytes(stats.pendingArchiveBytes)))
+	}
+
+	slots := []string{}
+	for _, slot := range stats.slots {
+		if slot.retainedBytes <= thresholdBytes {
+			continue
+		}
+		state := "active"
+		if !slot.active {
+			state = "inactive"
+		}
+		slots = append(slots, fmt.Sprintf("%s(%s %s, %s)", slot.name, state, slot.slotType,
+			formatWALBytes(slot.retainedBytes)))
+	}
+	if len(slots) > 0 {
+		if reason == "" {
+			reason = condition.CONDITION_REASON_WAL_RETAINED_BY_SLOTS
+		}
+		warnings = append(warnings, fmt.Sprintf("the replication slots retain the WAL: %s", strings.Join(slots, ", ")))
+	}
+
+	if len(warnings) == 0 {
+		return condition.CONDITION_STATUS_TRUE, condition.CONDITION_REASON_POSTGRES_WAL_HEALTHY,
+			condition.CONDITION_MESSAGE_POSTGRES_WAL_HEALTHY
+	}
+	return condition.CONDITION_STATUS_FALSE, reason, fmt.Sprintf("The WAL may fill the disk of the postgres, "+
+		"the threshold is %s: %s", formatWALBytes(thresholdBytes), strings.Join(warnings, "; "))
+}
+
+// formatWALBytes rounds the size to 0.1GiB, so the message isn't changed by the little WAL written between the checks
+func formatWALBytes(bytes int64) string {
+	return fmt.Sprintf("%.1fGi", float64(bytes)/(1<<30))
+}
@@ -0,0 +1,86 @@
+package hubofhubs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
+)
+
+func TestEvaluateWALStats(t *testing.T) {
+	threshold := int64(5 << 30)
+	archived := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	failed := archived.Add(time.Hour)
+
+	cases := []struct {
+		name    string
+		stats   *walStats
+		status  metav1.ConditionStatus
+		reason  string
+		message string
+	}{
+		{
+			name:  "neither the archive nor the slots are enabled",
+			stats: &walStats{},
+		},
+		{
+			name: "the WAL is archived and consumed by the slots",
+			stats: &walStats{
+				archiveEnabled:      true,
+				pendingArchiveBytes: 16 << 20,
+				lastArchivedTime:    &failed,
+				lastFailedTime:      &archived,
+				slots:               []walSlot{{name: "globalhub_cdc", slotType: "logical", active: true}},
+			},
+			status:  condition.CONDITION_STATUS_TRUE,
+			reason:  condition.CONDITION_REASON_POSTGRES_WAL_HEALTHY,
+			message: condition.CONDITION_MESSAGE_POSTGRES_WAL_HEALTHY,
+		},
+		{
+			name: "the archive is failing",
+			stats: &walStats{
+				archiveEnabled:      true,
+				pendingArchiveBytes: 1 << 30,
+				lastArchivedTime:    &archived,
+				lastFailedTime:      &failed,
+				lastFailedWAL:       "00000001000000000000000A",
+			},
+			status: condition.CONDITION_STATUS_FALSE,
+			reason: condition.CONDITION_REASON_WAL_ARCHIVE_FAILING,
+			message: "The WAL may fill the disk of the postgres, the threshold is 5.0Gi: " +
+				"the WAL archive is failing since 2024-05-01T11:00:00Z at 00000001000000000000000A, 1.0Gi is pending",
+		},
+		{
+			name:   "the archive is lagging",
+			stats:  &walStats{archiveEnabled: true, pendingArchiveBytes: 6 << 30, lastArchivedTime: &archived},
+			status: condition.CONDITION_STATUS_FALSE,
+			reason: condition.CONDITION_REASON_WAL_ARCHIVE_LAGGING,
+			message: "The WAL may fill the disk of the postgres, the threshold is 5.0Gi: " +
+				"the WAL archive is lagging, 6.0Gi is pending",
+		},
+		{
+			name: "the inactive slot retains the WAL",
+			stats: &walStats{
+				slots: []walSlot{
+					{name: "globalhub_cdc", slotType: "logical", active: false, retainedBytes: 7 << 30},
+					{name: "pgha1_abcd", slotType: "physical", active: true, retainedBytes: 16 << 20},
+				},
+			},
+			status: condition.CONDITION_STATUS_FALSE,
+			reason: condition.CONDITION_REASON_WAL_RETAINED_BY_SLOTS,
+			message: "The WAL may fill the disk of the postgres, the threshold is 5.0Gi: " +
+				"the replication slots retain the WAL: globalhub_cdc(inactive logical, 7.0Gi)",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, reason, message := evaluateWALStats(tc.stats, threshold)
+			assert.Equal(t, tc.status, status)
+			assert.Equal(t, tc.reason, reason)
+			assert.Equal(t, tc.message, message)
+		})
+	}
+}
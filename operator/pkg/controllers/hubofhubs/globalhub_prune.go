@@ -13,9 +13,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
@@ -51,12 +54,12 @@ func (r *MulticlusterGlobalHubReconciler) pruneGlobalHubResources(ctx context.Co
 	}
 
 	// clean up namesapced resources, eg. mgh system namespace, etc
-	if err := r.pruneNamespacedResources(ctx); err != nil {
+	if err := r.pruneNamespacedResources(ctx, mgh); err != nil {
 		return err
 	}
 
 	// clean up the cluster resources, eg. clusterrole, clusterrolebinding, etc
-	if err := r.pruneGlobalResources(ctx); err != nil {
+	if err := r.pruneGlobalResources(ctx, mgh); err != nil {
 		return err
 	}
 
@@ -70,7 +73,9 @@ func (r *MulticlusterGlobalHubReconciler) pruneGlobalHubResources(ctx context.Co
 
 // pruneGlobalResources deletes the cluster scoped resources created by the multicluster-global-hub-operator
 // cluster scoped resources need to be deleted manually because they don't have ownerrefenence set
-func (r *MulticlusterGlobalHubReconciler) pruneGlobalResources(ctx context.Context) error {
+func (r *MulticlusterGlobalHubReconciler) pruneGlobalResources(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
 	listOpts := []client.ListOption{
 		client.MatchingLabels(map[string]string{
 			constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
@@ -82,7 +87,7 @@ func (r *MulticlusterGlobalHubReconciler) pruneGlobalResources(ctx context.Conte
 		return err
 	}
 	for idx := range clusterRoleList.Items {
-		if err := r.Client.Delete(ctx, &clusterRoleList.Items[idx]); err != nil && !errors.IsNotFound(err) {
+		if err := r.pruneObject(ctx, mgh, &clusterRoleList.Items[idx]); err != nil {
 			return err
 		}
 	}
@@ -92,7 +97,7 @@ func (r *MulticlusterGlobalHubReconciler) pruneGlobalResources(ctx context.Conte
 		return err
 	}
 	for idx := range clusterRoleBindingList.Items {
-		if err := r.Client.Delete(ctx, &clusterRoleBindingList.Items[idx]); err != nil && !errors.IsNotFound(err) {
+		if err := r.pruneObject(ctx, mgh, &clusterRoleBindingList.Items[idx]); err != nil {
 			return err
 		}
 	}
@@ -102,17 +107,32 @@ func (r *MulticlusterGlobalHubReconciler) pruneGlobalResources(ctx context.Conte
 		return err
 	}
 	for idx := range clusterManagementAddOnList.Items {
-		if err := r.Client.Delete(ctx, &clusterManagementAddOnList.Items[idx]); err != nil && !errors.IsNotFound(err) {
+		if err := r.pruneObject(ctx, mgh, &clusterManagementAddOnList.Items[idx]); err != nil {
 			return err
 		}
 	}
 
+	clusterClaimList := &clusterv1alpha1.ClusterClaimList{}
+	if err := r.Client.List(ctx, clusterClaimList, listOpts...); err != nil {
+		return err
+	}
+	for idx := range clusterClaimList.Items {
+		if err := r.pruneObject(ctx, mgh, &clusterClaimList.Items[idx]); err != nil {
+			return err
+		}
+	}
+
+	// the console plugin is cluster scoped, and it's enabled in the cluster scoped console operator config
+	if err := r.pruneConsolePlugin(ctx, mgh); err != nil {
+		return err
+	}
+
 	webhookList := &admissionregistrationv1.MutatingWebhookConfigurationList{}
 	if err := r.Client.List(ctx, webhookList, listOpts...); err != nil {
 		return err
 	}
 	for idx := range webhookList.Items {
-		if err := r.Client.Delete(ctx, &webhookList.Items[idx]); err != nil && !errors.IsNotFound(err) {
+		if err := r.pruneObject(ctx, mgh, &webhookList.Items[idx]); err != nil {
 			return err
 		}
 	}
@@ -121,7 +141,9 @@ func (r *MulticlusterGlobalHubReconciler) pruneGlobalResources(ctx context.Conte
 }
 
 // pruneNamespacedResources tries to delete mgh resources
-func (r *MulticlusterGlobalHubReconciler) pruneNamespacedResources(ctx context.Context) error {
+func (r *MulticlusterGlobalHubReconciler) pruneNamespacedResources(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) error {
 	mghServiceMonitor := &promv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      operatorconstants.GHServiceMonitorName,
@@ -131,10 +153,26 @@ func (r *MulticlusterGlobalHubReconciler) pruneNamespacedResources(ctx context.C
 			},
 		},
 	}
-	if err := r.Client.Delete(ctx, mghServiceMonitor); err != nil && !errors.IsNotFound(err) {
+	return r.pruneObject(ctx, mgh, mghServiceMonitor)
+}
+
+// pruneObject deletes the object created by the operator, and records the action if the object is deleted
+func (r *MulticlusterGlobalHubReconciler) pruneObject(ctx context.Context,
+	mgh *globalhubv1alpha4.MulticlusterGlobalHub, obj client.Object,
+) error {
+	if err := r.Client.Delete(ctx, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
-
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, r.GetScheme()); err == nil {
+		kind = gvk.Kind
+	}
+	config.RecordAction(mgh, config.ReasonResourcePruned,
+		fmt.Sprintf("Pruned the %s %s", kind, client.ObjectKeyFromObject(obj)),
+		"kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
 	return nil
 }
 
@@ -0,0 +1,163 @@
+package hubofhubs
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	transportprotocol "github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
+	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// additionalKafkaSecretName is mounted to the manager to consume the status from the regional kafka clusters
+	additionalKafkaSecretName = "multicluster-global-hub-manager-additional-kafka" // #nosec G101
+	additionalKafkaMountPath  = "/additional-kafka"
+	additionalKafkaConfigKey  = "kafka.yaml"
+)
+
+// additionalKafkaCluster is the kafka cluster in the additional kafka config of the manager
+type additionalKafkaCluster struct {
+	Identity        string `json:"identity"`
+	BootstrapServer string `json:"bootstrapServer"`
+	CACertPath      string `json:"caCertPath,omitempty"`
+	ClientCertPath  string `json:"clientCertPath,omitempty"`
+	ClientKeyPath   string `json:"clientKeyPath,omitempty"`
+	StatusTopic     string `json:"statusTopic,omitempty"`
+	EventTopic      string `json:"eventTopic,omitempty"`
+}
+
+// reconcileRegionalTransport registers the transporters of the regional kafka clusters, the agents of the managed hubs
+// labeled with the region connect to the kafka cluster of the region, so the status doesn't cross the regions until
+// it's consumed by the manager. The topics are validated or created the same as the BYO kafka cluster.
+func (r *MulticlusterGlobalHubReconciler) reconcileRegionalTransport(ctx context.Context,
+	mgh *v1alpha4.MulticlusterGlobalHub,
+) error {
+	trustedCABundle, err := config.GetTrustedCABundle(ctx, r.Client, mgh.Namespace,
+		mgh.Spec.DataLayer.Kafka.TrustedCABundles)
+	if err != nil {
+		return err
+	}
+	transporters, err := transportprotocol.NewRegionalTransporters(ctx, r.Client, mgh.Namespace,
+		transportprotocol.WithTrustedCABundle(trustedCABundle),
+		transportprotocol.WithReadOnly(mgh.Spec.DataLayer.Kafka.ReadOnly))
+	if err != nil {
+		return err
+	}
+	for region, trans := range transporters {
+		if err := trans.CreateTopic(trans.GenerateClusterTopic(transportprotocol.GlobalHubClusterName)); err != nil {
+			return fmt.Errorf("failed to create the topics of the region %s: %w", region, err)
+		}
+	}
+	config.SetRegionalTransporters(transporters)
+	return nil
+}
+
+// applyAdditionalKafkaSecret creates the additional kafka config of the manager with the credentials of the regional
+// kafka clusters, it returns true if the config is changed, so the manager is restarted to reload it. The secret is
+// removed if there isn't any regional kafka cluster.
+func (r *MulticlusterGlobalHubReconciler) applyAdditionalKafkaSecret(ctx context.Context,
+	mgh *v1alpha4.MulticlusterGlobalHub,
+) (bool, error) {
+	transporters := config.GetRegionalTransporters()
+	if len(transporters) == 0 {
+		err := r.Client.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      additionalKafkaSecretName,
+			Namespace: mgh.Namespace,
+		}})
+		return false, client.IgnoreNotFound(err)
+	}
+
+	secretData, err := additionalKafkaSecretData(transporters)
+	if err != nil {
+		return false, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      additionalKafkaSecretName,
+			Namespace: mgh.Namespace,
+			Labels: map[string]string{
+				constants.GlobalHubOwnerLabelKey: constants.GHOperatorOwnerLabelVal,
+			},
+		},
+		Data: secretData,
+	}
+	if err = controllerutil.SetControllerReference(mgh, secret, r.Scheme); err != nil {
+		return false, err
+	}
+	changed, err := operatorutils.ApplySecret(ctx, r.Client, secret)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply the additional kafka secret of the manager: %w", err)
+	}
+	return changed, nil
+}
+
+// additionalKafkaSecretData returns the additional kafka config of the manager and the certificates it refers to, the
+// region is the identity of the kafka cluster
+func additionalKafkaSecretData(transporters map[string]transport.Transporter) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	clusters := []additionalKafkaCluster{}
+	for _, region := range sortedRegions(transporters) {
+		trans := transporters[region]
+		conn, err := trans.GetConnCredential(transportprotocol.DefaultGlobalHubKafkaUser)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the transport connection of the region %s: %w", region, err)
+		}
+		topic := trans.GenerateClusterTopic(transportprotocol.GlobalHubClusterName)
+		cluster := additionalKafkaCluster{
+			Identity:        region,
+			BootstrapServer: conn.BootstrapServer,
+			StatusTopic:     topic.StatusTopic,
+			EventTopic:      topic.EventTopic,
+		}
+		for _, cert := range []struct {
+			name  string
+			value string
+			path  *string
+		}{
+			{name: "ca.crt", value: conn.CACert, path: &cluster.CACertPath},
+			{name: "client.crt", value: conn.ClientCert, path: &cluster.ClientCertPath},
+			{name: "client.key", value: conn.ClientKey, path: &cluster.ClientKeyPath},
+		} {
+			value, err := base64.StdEncoding.DecodeString(cert.value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the %s of the region %s: %w", cert.name, region, err)
+			}
+			if len(value) == 0 {
+				continue
+			}
+			key := fmt.Sprintf("%s-%s", region, cert.name)
+			data[key] = value
+			*cert.path = filepath.Join(additionalKafkaMountPath, key)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	kafkaConfig, err := yaml.Marshal(map[string]interface{}{"clusters": clusters})
+	if err != nil {
+		return nil, err
+	}
+	data[additionalKafkaConfigKey] = kafkaConfig
+	return data, nil
+}
+
+func sortedRegions(transporters map[string]transport.Transporter) []string {
+	regions := make([]string, 0, len(transporters))
+	for region := range transporters {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
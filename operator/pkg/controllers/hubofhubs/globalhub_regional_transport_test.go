@@ -0,0 +1,72 @@
+package hubofhubs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	transportprotocol "github.com/stolostron/multicluster-global-hub/operator/pkg/transporter"
+)
+
+func Test_additionalKafkaSecretData(t *testing.T) {
+	regionalSecret := func(name, region string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{operatorconstants.GHTransportRegionLabelKey: region},
+			},
+			Data: data,
+		}
+	}
+	c := fake.NewClientBuilder().WithObjects(
+		regionalSecret("transport-west", "west", map[string][]byte{
+			"bootstrap_server": []byte("west-kafka.example.com:9093"),
+			"ca.crt":           []byte("west-ca"),
+			"client.crt":       []byte("west-client-cert"),
+			"client.key":       []byte("west-client-key"),
+		}),
+		regionalSecret("transport-east", "east", map[string][]byte{
+			"bootstrap_server":                  []byte("east-kafka.example.com:9092"),
+			transportprotocol.BYOStatusTopicKey: []byte("east.status.{hub}"),
+		}),
+	).Build()
+	transporters, err := transportprotocol.NewRegionalTransporters(context.Background(), c, "default")
+	require.NoError(t, err)
+
+	data, err := additionalKafkaSecretData(transporters)
+	require.NoError(t, err)
+	assert.Equal(t, "west-ca", string(data["west-ca.crt"]))
+	assert.Equal(t, "west-client-cert", string(data["west-client.crt"]))
+	assert.Equal(t, "west-client-key", string(data["west-client.key"]))
+	// the empty certificates aren't mounted
+	assert.NotContains(t, data, "east-ca.crt")
+
+	kafkaConfig := map[string][]additionalKafkaCluster{}
+	require.NoError(t, yaml.Unmarshal(data[additionalKafkaConfigKey], &kafkaConfig))
+	// the clusters are sorted by the region, which is the identity of the cluster
+	assert.Equal(t, []additionalKafkaCluster{
+		{
+			Identity:        "east",
+			BootstrapServer: "east-kafka.example.com:9092",
+			StatusTopic:     `^east\.status\..*`,
+			EventTopic:      "event",
+		},
+		{
+			Identity:        "west",
+			BootstrapServer: "west-kafka.example.com:9093",
+			CACertPath:      "/additional-kafka/west-ca.crt",
+			ClientCertPath:  "/additional-kafka/west-client.crt",
+			ClientKeyPath:   "/additional-kafka/west-client.key",
+			StatusTopic:     "status",
+			EventTopic:      "event",
+		},
+	}, kafkaConfig["clusters"])
+}
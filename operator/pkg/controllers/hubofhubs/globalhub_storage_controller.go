@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubofhubs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	postgresv1beta1 "github.com/crunchydata/postgres-operator/pkg/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/postgres"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// the secrets of the BYO postgres, the built-in postgres statefulset and the crunchy postgres users
+var storageSecrets = sets.NewString(
+	constants.GHStorageSecretName,
+	constants.GHBuiltInStorageSecretName,
+	postgres.PostgresGuestUserSecretName,
+	postgres.PostgresSuperUserSecretName,
+	postgres.PostgresCertName,
+)
+
+// StorageController reconciles the storage once the postgres secrets or the crunchy postgres cluster are changed, the
+// global hub reconciler is triggered if the storage connection is updated
+type StorageController struct {
+	Log                 logr.Logger
+	mgr                 ctrl.Manager
+	globalHubReconciler *MulticlusterGlobalHubReconciler
+	conn                *postgres.PostgresConnection
+	connLock            sync.RWMutex
+}
+
+func (r *StorageController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	mgh := &globalhubv1alpha4.MulticlusterGlobalHub{}
+	err := r.mgr.GetClient().Get(ctx, config.GetMGHNamespacedName(), mgh)
+	if err != nil {
+		r.Log.Error(err, "failed to get MulticlusterGlobalHub")
+		return ctrl.Result{}, err
+	}
+
+	updated, err := r.reconcile(ctx, mgh)
+	if err != nil {
+		r.Log.Error(err, "failed to get connection from storage reconciler")
+		return ctrl.Result{}, err
+	}
+	if updated {
+		r.Log.Info("the storage connection is updated, trigger the global hub reconciler")
+		r.globalHubReconciler.triggerReconcile()
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcile applies the storage of the mgh and returns true if the connection is changed, it's also invoked by the
+// global hub reconciler, so the postgres is updated with the mgh spec as well
+func (r *StorageController) reconcile(ctx context.Context, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) (bool, error) {
+	conn, err := r.globalHubReconciler.ReconcileStorage(ctx, mgh)
+	if err != nil {
+		return false, err
+	}
+	if conn == nil {
+		return false, fmt.Errorf("the storage connection is not ready")
+	}
+	return r.setConn(conn), nil
+}
+
+func (r *StorageController) getConn() *postgres.PostgresConnection {
+	r.connLock.RLock()
+	defer r.connLock.RUnlock()
+	return r.conn
+}
+
+// setConn returns true if the connection is changed
+func (r *StorageController) setConn(conn *postgres.PostgresConnection) bool {
+	r.connLock.Lock()
+	defer r.connLock.Unlock()
+	if reflect.DeepEqual(r.conn, conn) {
+		return false
+	}
+	r.conn = conn
+	return true
+}
+
+var storageSecretPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return storageSecrets.Has(e.Object.GetName())
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return storageSecrets.Has(e.ObjectNew.GetName())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return storageSecrets.Has(e.Object.GetName())
+	},
+}
+
+var postgresClusterPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectNew.GetName() == postgres.PostgresName &&
+			e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration()
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return e.Object.GetName() == postgres.PostgresName &&
+			e.Object.GetNamespace() == utils.GetDefaultNamespace()
+	},
+}
+
+// initialize the storage connection and then start the storage controller, the crunchy postgres cluster is watched
+// only if its crd is installed
+func startStorageController(ctx context.Context, mgr ctrl.Manager,
+	reconciler *MulticlusterGlobalHubReconciler, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+) (*StorageController, error) {
+	r := &StorageController{
+		Log:                 ctrl.Log.WithName("storage-controller"),
+		mgr:                 mgr,
+		globalHubReconciler: reconciler,
+	}
+	_, err := r.reconcile(ctx, mgh)
+	if err != nil {
+		return nil, err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		Named("middleware_storage_controller").
+		Watches(&corev1.Secret{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(storageSecretPred))
+	_, err = mgr.GetRESTMapper().RESTMapping(postgresv1beta1.GroupVersion.WithKind("PostgresCluster").GroupKind())
+	if err == nil {
+		b = b.Watches(&postgresv1beta1.PostgresCluster{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(postgresClusterPred))
+	} else if !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	if err = b.Complete(r); err != nil {
+		return nil, err
+	}
+	if err = addPostgresWALMonitor(mgr, r); err != nil {
+		return nil, err
+	}
+	r.Log.Info("storage controller is started")
+	return r, nil
+}
@@ -353,7 +353,7 @@ var _ = Describe("MulticlusterGlobalHub controller", Ordered, func() {
 					KafkaCACert:            transportConn.CACert,
 					KafkaClientCert:        transportConn.ClientCert,
 					KafkaClientKey:         transportConn.ClientKey,
-					KafkaBootstrapServer:   transportConn.BootstrapServer,
+					KafkaBootstrapServer:   base64.StdEncoding.EncodeToString([]byte(transportConn.BootstrapServer)),
 					KafkaConsumerTopic:     transportTopic.StatusTopic,
 					KafkaProducerTopic:     transportTopic.SpecTopic,
 					KafkaEventTopic:        transportTopic.EventTopic,
@@ -424,6 +424,10 @@ var _ = Describe("MulticlusterGlobalHub controller", Ordered, func() {
 					LogLevel             string
 					Resources            *corev1.ResourceRequirements
 					EnableMetrics        bool
+					CustomDashboards     []string
+					EnableSSO            bool
+					OAuthSecretName      string
+					EnableLDAP           bool
 				}{
 					Namespace:            commonutils.GetDefaultNamespace(),
 					Replicas:             2,
@@ -909,7 +913,7 @@ var _ = Describe("MulticlusterGlobalHub controller", Ordered, func() {
 		})
 
 		It("Should get the datasource config", func() {
-			_, err := hubofhubs.GrafanaDataSource(testPostgres.URI, []byte("test"), "test")
+			_, err := hubofhubs.GrafanaDataSource(testPostgres.URI, []byte("test"), "test", "")
 			Expect(err).Should(Succeed())
 		})
 	})
@@ -34,6 +34,7 @@ import (
 	"k8s.io/client-go/rest"
 	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1alpha1 "open-cluster-management.io/api/cluster/v1alpha1"
 	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
 	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 	workv1 "open-cluster-management.io/api/work/v1"
@@ -110,6 +111,7 @@ var _ = BeforeSuite(func() {
 	Expect(operatorsv1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(routev1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(clusterv1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
+	Expect(clusterv1alpha1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(clusterv1beta1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(clusterv1beta2.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
 	Expect(workv1.AddToScheme(scheme.Scheme)).NotTo(HaveOccurred())
@@ -0,0 +1,5 @@
+-- the offsets are committed for each partition of the topics, since the busy topics are scaled out with partitions
+ALTER TABLE status.transport ADD COLUMN IF NOT EXISTS partition integer NOT NULL DEFAULT 0;
+UPDATE status.transport SET partition = (payload->>'partition')::integer WHERE payload ? 'partition';
+ALTER TABLE status.transport DROP CONSTRAINT IF EXISTS transport_pkey;
+ALTER TABLE status.transport ADD CONSTRAINT transport_pkey PRIMARY KEY (name, partition);
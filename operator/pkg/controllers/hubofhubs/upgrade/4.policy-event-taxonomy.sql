@@ -0,0 +1,17 @@
+-- the taxonomy columns of the policy events, the tables created by the previous releases don't have them
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS event_type character varying(16);
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS severity character varying(16);
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS category character varying(32);
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS involved_kind character varying(63);
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS involved_namespace character varying(254);
+ALTER TABLE event.local_policies ADD COLUMN IF NOT EXISTS involved_name character varying(254);
+
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS event_type character varying(16);
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS severity character varying(16);
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS category character varying(32);
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS involved_kind character varying(63);
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS involved_namespace character varying(254);
+ALTER TABLE event.local_root_policies ADD COLUMN IF NOT EXISTS involved_name character varying(254);
+
+CREATE INDEX IF NOT EXISTS local_policies_severity_idx ON event.local_policies (severity, category, created_at);
+CREATE INDEX IF NOT EXISTS local_root_policies_severity_idx ON event.local_root_policies (severity, category, created_at);
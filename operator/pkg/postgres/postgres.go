@@ -35,6 +35,9 @@ var (
 	PostgresSuperUser           = "postgres"
 	PostgresSuperUserSecretName = PostgresName + "-" + "pguser" + "-" + PostgresSuperUser
 	PostgresCertName            = PostgresName + "-cluster-cert"
+	// PostgresLabelKey labels the pods of both the statefulset postgres and the instances of the crunchy postgres, so
+	// they're selected by the network policy of the built-in postgres
+	PostgresLabelKey = "global-hub.open-cluster-management.io/postgres"
 
 	replicas3 int32 = 3
 	// need append "?sslmode=verify-ca" to the end of the uri to access postgres
@@ -163,6 +166,7 @@ func NewPostgres(name, namespace string) *postgresv1beta1.PostgresCluster {
 			InstanceSets: []postgresv1beta1.PostgresInstanceSetSpec{
 				{
 					Name:     "pgha1",
+					Metadata: &postgresv1beta1.Metadata{Labels: map[string]string{PostgresLabelKey: "true"}},
 					Replicas: &replicas3,
 					DataVolumeClaimSpec: corev1.PersistentVolumeClaimSpec{
 						AccessModes: []corev1.PersistentVolumeAccessMode{
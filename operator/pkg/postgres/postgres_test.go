@@ -78,4 +78,9 @@ func TestNewPostgres(t *testing.T) {
 	if kafka.Name != PostgresName {
 		t.Errorf("Expected name %s, got %s", PostgresName, kafka.Name)
 	}
+	for _, instanceSet := range kafka.Spec.InstanceSets {
+		if instanceSet.Metadata.GetLabelsOrNil()[PostgresLabelKey] != "true" {
+			t.Errorf("Expected the instance set %s to be labeled by %s", instanceSet.Name, PostgresLabelKey)
+		}
+	}
 }
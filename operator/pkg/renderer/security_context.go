@@ -0,0 +1,96 @@
+package renderer
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+)
+
+// PodSecurityProfileAnnotation is the pod template annotation of the applied security profile, so the workload is
+// updated with the whole desired pod template once the profile is changed
+const PodSecurityProfileAnnotation = "global-hub.open-cluster-management.io/pod-security-profile"
+
+// workloadKinds are the kinds whose pod template is at spec.template
+var workloadKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+	"Job":         true,
+}
+
+// SetPodSecurityProfile annotates the pod template of the workload, e.g. the deployment and the statefulset, with the
+// profile, and sets the security context of the restricted pod security standard if the profile is restricted. It runs
+// the pods as non-root with the RuntimeDefault seccomp profile, and the containers without privilege escalation and
+// any capabilities. The fields which are set in the manifests are kept, so a workload is still able to add the
+// capabilities it requires.
+func SetPodSecurityProfile(obj *unstructured.Unstructured, profile v1alpha4.PodSecurityProfile) error {
+	if !workloadKinds[obj.GetKind()] {
+		return nil
+	}
+
+	annotationsPath := []string{"spec", "template", "metadata", "annotations"}
+	annotations, _, err := unstructured.NestedStringMap(obj.Object, annotationsPath...)
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[PodSecurityProfileAnnotation] = string(profile)
+	if err := unstructured.SetNestedStringMap(obj.Object, annotations, annotationsPath...); err != nil {
+		return err
+	}
+	if profile != v1alpha4.PodSecurityRestricted {
+		return nil
+	}
+
+	podSpecPath := []string{"spec", "template", "spec"}
+	podSecurityContext, _, err := unstructured.NestedMap(obj.Object, append(podSpecPath, "securityContext")...)
+	if err != nil {
+		return err
+	}
+	if podSecurityContext == nil {
+		podSecurityContext = map[string]interface{}{}
+	}
+	setDefault(podSecurityContext, true, "runAsNonRoot")
+	setDefault(podSecurityContext, "RuntimeDefault", "seccompProfile", "type")
+	if err := unstructured.SetNestedMap(obj.Object, podSecurityContext,
+		append(podSpecPath, "securityContext")...); err != nil {
+		return err
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, found, err := unstructured.NestedSlice(obj.Object, append(podSpecPath, field)...)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+		for i := range containers {
+			container, ok := containers[i].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			securityContext, ok := container["securityContext"].(map[string]interface{})
+			if !ok {
+				securityContext = map[string]interface{}{}
+			}
+			setDefault(securityContext, false, "allowPrivilegeEscalation")
+			setDefault(securityContext, []interface{}{"ALL"}, "capabilities", "drop")
+			container["securityContext"] = securityContext
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, containers, append(podSpecPath, field)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDefault sets the value to the nested field of the map if the field isn't set
+func setDefault(obj map[string]interface{}, value interface{}, fields ...string) {
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj, fields...); found {
+		return
+	}
+	_ = unstructured.SetNestedField(obj, value, fields...)
+}
@@ -0,0 +1,88 @@
+package renderer_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
+)
+
+var _ = Describe("SetPodSecurityProfile", func() {
+	newDeployment := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "test"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "app"},
+							map[string]interface{}{
+								"name": "proxy",
+								"securityContext": map[string]interface{}{
+									"capabilities": map[string]interface{}{
+										"add": []interface{}{"NET_BIND_SERVICE"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}}
+	}
+
+	It("Should set the restricted security context to the pods and containers", func() {
+		obj := newDeployment()
+		Expect(renderer.SetPodSecurityProfile(obj, v1alpha4.PodSecurityRestricted)).To(Succeed())
+
+		profile, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations",
+			renderer.PodSecurityProfileAnnotation)
+		Expect(profile).To(Equal("Restricted"))
+		runAsNonRoot, _, _ := unstructured.NestedBool(obj.Object, "spec", "template", "spec", "securityContext",
+			"runAsNonRoot")
+		Expect(runAsNonRoot).To(BeTrue())
+		seccomp, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "spec", "securityContext",
+			"seccompProfile", "type")
+		Expect(seccomp).To(Equal("RuntimeDefault"))
+
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+		Expect(containers).To(HaveLen(2))
+		for _, c := range containers {
+			container := c.(map[string]interface{})
+			escalation, _, _ := unstructured.NestedBool(container, "securityContext", "allowPrivilegeEscalation")
+			Expect(escalation).To(BeFalse())
+			drop, _, _ := unstructured.NestedStringSlice(container, "securityContext", "capabilities", "drop")
+			Expect(drop).To(Equal([]string{"ALL"}))
+		}
+		// the capabilities in the manifests are kept
+		add, _, _ := unstructured.NestedStringSlice(containers[1].(map[string]interface{}),
+			"securityContext", "capabilities", "add")
+		Expect(add).To(Equal([]string{"NET_BIND_SERVICE"}))
+	})
+
+	It("Should only annotate the pods with the custom profile", func() {
+		obj := newDeployment()
+		Expect(renderer.SetPodSecurityProfile(obj, v1alpha4.PodSecurityCustom)).To(Succeed())
+
+		profile, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations",
+			renderer.PodSecurityProfileAnnotation)
+		Expect(profile).To(Equal("Custom"))
+		_, found, _ := unstructured.NestedMap(obj.Object, "spec", "template", "spec", "securityContext")
+		Expect(found).To(BeFalse())
+	})
+
+	It("Should skip the objects which aren't workloads", func() {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "test"},
+		}}
+		Expect(renderer.SetPodSecurityProfile(obj, v1alpha4.PodSecurityRestricted)).To(Succeed())
+		_, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+		Expect(found).To(BeFalse())
+	})
+})
@@ -3,40 +3,107 @@ package transporter
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
 )
 
+const (
+	// the optional properties of the transport secret to use the existing topics, only the status topic can be the
+	// template with the "{hub}" placeholder, e.g. "acme.globalhub.status.{hub}"
+	BYOSpecTopicKey   = "spec_topic"
+	BYOStatusTopicKey = "status_topic"
+	BYOEventTopicKey  = "event_topic"
+
+	adminTimeout = 30 * time.Second
+)
+
+// topicAdmin is the subset of the kafka admin client used to validate and create the topics
+type topicAdmin interface {
+	GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error)
+	DescribeConfigs(ctx context.Context, resources []kafka.ConfigResource,
+		options ...kafka.DescribeConfigsAdminOption) ([]kafka.ConfigResourceResult, error)
+	CreateTopics(ctx context.Context, topics []kafka.TopicSpecification,
+		options ...kafka.CreateTopicsAdminOption) ([]kafka.TopicResult, error)
+	Close()
+}
+
 type BYOTransporter struct {
 	ctx           context.Context
 	log           logr.Logger
 	name          string
 	namespace     string
 	runtimeClient client.Client
+	// trustedCABundle is trusted besides the ca.crt of the transport secret
+	trustedCABundle []byte
+	// readOnly only validates the topics, they're never created
+	readOnly bool
+	// newAdmin connects the kafka cluster with the transport secret, it's replaced by the tests
+	newAdmin func(secret *corev1.Secret) (topicAdmin, error)
 }
 
 // create the transport with secret(BYO case), it should meet the following conditions
 // 1. name: "multicluster-global-hub-transport"
 // 2. properties: "bootstrap_server", "ca.crt", "client.crt" and "client.key"
+// 3. optional properties: "spec_topic", "status_topic" and "event_topic", the topics are validated if they exist,
+// otherwise they're created unless it's read-only
 func NewBYOTransporter(ctx context.Context, namespacedName types.NamespacedName,
-	c client.Client,
+	c client.Client, opts ...BYOOption,
 ) *BYOTransporter {
-	return &BYOTransporter{
+	trans := &BYOTransporter{
 		log:           ctrl.Log.WithName("secret-transporter"),
 		ctx:           ctx,
 		name:          namespacedName.Name,
 		namespace:     namespacedName.Namespace,
 		runtimeClient: c,
+		newAdmin:      newTopicAdmin,
+	}
+	for _, opt := range opts {
+		opt(trans)
+	}
+	return trans
+}
+
+type BYOOption func(*BYOTransporter)
+
+// WithTrustedCABundle trusts the CA bundle besides the ca.crt of the transport secret, e.g. the private enterprise CA
+// which signs the brokers
+func WithTrustedCABundle(bundle []byte) BYOOption {
+	return func(s *BYOTransporter) {
+		s.trustedCABundle = bundle
 	}
 }
 
+// WithReadOnly never creates the topics, they're only validated to exist with the required configuration, the users
+// and ACLs are always left to the kafka cluster
+func WithReadOnly(readOnly bool) BYOOption {
+	return func(s *BYOTransporter) {
+		s.readOnly = readOnly
+	}
+}
+
+// TopicValidationError reports all the invalid topics of the read-only transporter, so they can be fixed together
+type TopicValidationError struct {
+	Problems []string
+}
+
+func (e *TopicValidationError) Error() string {
+	return "the kafka topics are invalid: " + strings.Join(e.Problems, "; ")
+}
+
 func (k *BYOTransporter) GenerateUserName(clusterIdentity string) string {
 	return ""
 }
@@ -49,8 +116,53 @@ func (k *BYOTransporter) DeleteUser(username string) error {
 	return nil
 }
 
-// create the transport topic(KafkaTopic) if not exist for each hub clusters
+// CreateTopic only handles the topics configured in the transport secret, the existing topics are validated and the
+// missing ones are created, so the topics can be pre-created if the topic creation is restricted in the kafka cluster.
+// The default topics are left to the kafka cluster as before. All the topics are validated only if it's read-only.
 func (s *BYOTransporter) CreateTopic(topic *transport.ClusterTopic) error {
+	secret, err := s.getSecret()
+	if err != nil {
+		return err
+	}
+	if !s.readOnly && !hasTopicTemplates(secret) {
+		return nil
+	}
+	for _, key := range []string{BYOSpecTopicKey, BYOEventTopicKey} {
+		if transport.IsTopicTemplate(string(secret.Data[key])) {
+			return fmt.Errorf("the %s placeholder is only supported by the %s of the transport secret",
+				transport.HubPlaceholder, BYOStatusTopicKey)
+		}
+	}
+
+	admin, err := s.newAdmin(secret)
+	if err != nil {
+		return fmt.Errorf("failed to connect the kafka cluster: %w", err)
+	}
+	defer admin.Close()
+
+	metadata, err := admin.GetMetadata(nil, true, int(adminTimeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to get the kafka topics: %w", err)
+	}
+	if s.readOnly {
+		return validateTopics(s.ctx, admin, metadata, topic)
+	}
+	for _, topicName := range []string{topic.SpecTopic, topic.StatusTopic, topic.EventTopic} {
+		// the regex is subscribed by the manager to consume the status topics of all the hubs
+		if strings.HasPrefix(topicName, "^") {
+			continue
+		}
+		if topicMetadata, found := metadata.Topics[topicName]; found {
+			if err := validateTopic(s.ctx, admin, topicMetadata); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := createTopic(s.ctx, admin, topicName); err != nil {
+			return err
+		}
+		s.log.Info("created the kafka topic", "topic", topicName)
+	}
 	return nil
 }
 
@@ -67,20 +179,36 @@ func (k *BYOTransporter) GrantWrite(userName string, topicName string) error {
 	return nil
 }
 
+// GenerateClusterTopic renders the topics configured in the transport secret for the hub, the manager subscribes the
+// regex of the status topic template. The generic topics are used if they aren't configured.
 func (k *BYOTransporter) GenerateClusterTopic(clusterIdentity string) *transport.ClusterTopic {
-	return &transport.ClusterTopic{
-		SpecTopic:   "spec",
-		StatusTopic: "status",
-		EventTopic:  "event",
+	topic := &transport.ClusterTopic{
+		SpecTopic:   transport.GenericSpecTopic,
+		StatusTopic: transport.GenericStatusTopic,
+		EventTopic:  transport.GenericEventTopic,
+	}
+	secret, err := k.getSecret()
+	if err != nil {
+		k.log.Error(err, "failed to get the transport secret, use the generic topics")
+		return topic
 	}
+	if specTopic := string(secret.Data[BYOSpecTopicKey]); specTopic != "" {
+		topic.SpecTopic = specTopic
+	}
+	if eventTopic := string(secret.Data[BYOEventTopicKey]); eventTopic != "" {
+		topic.EventTopic = eventTopic
+	}
+	if statusTopic := string(secret.Data[BYOStatusTopicKey]); statusTopic != "" {
+		topic.StatusTopic = transport.RenderTopic(statusTopic, clusterIdentity)
+		if clusterIdentity == GlobalHubClusterName && transport.IsTopicTemplate(statusTopic) {
+			topic.StatusTopic = transport.TopicRegex(statusTopic)
+		}
+	}
+	return topic
 }
 
 func (s *BYOTransporter) GetConnCredential(username string) (*transport.ConnCredential, error) {
-	kafkaSecret := &corev1.Secret{}
-	err := s.runtimeClient.Get(s.ctx, types.NamespacedName{
-		Name:      s.name,
-		Namespace: s.namespace,
-	}, kafkaSecret)
+	kafkaSecret, err := s.getSecret()
 	if err != nil {
 		return nil, err
 	}
@@ -92,3 +220,127 @@ func (s *BYOTransporter) GetConnCredential(username string) (*transport.ConnCred
 		ClientKey:       base64.StdEncoding.EncodeToString(kafkaSecret.Data[filepath.Join("client.key")]),
 	}, nil
 }
+
+// getSecret returns the transport secret, its ca.crt includes the trusted CA bundle, so the bundle is trusted by the
+// operator, the manager and the agents
+func (s *BYOTransporter) getSecret() (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := s.runtimeClient.Get(s.ctx, types.NamespacedName{
+		Name:      s.name,
+		Namespace: s.namespace,
+	}, secret)
+	if err != nil {
+		return secret, err
+	}
+	if len(s.trustedCABundle) > 0 {
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data["ca.crt"] = config.MergeCABundles(secret.Data["ca.crt"], s.trustedCABundle)
+	}
+	return secret, nil
+}
+
+func hasTopicTemplates(secret *corev1.Secret) bool {
+	for _, key := range []string{BYOSpecTopicKey, BYOStatusTopicKey, BYOEventTopicKey} {
+		if len(secret.Data[key]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTopic makes sure the existing topic can carry the bundles, the message size of the topic has to be larger
+// than the chunk of the producer, otherwise the large bundles are rejected by the kafka cluster
+func validateTopic(ctx context.Context, admin topicAdmin, topic kafka.TopicMetadata) error {
+	if len(topic.Partitions) < int(DefaultPartition) {
+		return fmt.Errorf("the kafka topic %s has %d partitions, at least %d is required", topic.Topic,
+			len(topic.Partitions), DefaultPartition)
+	}
+	results, err := admin.DescribeConfigs(ctx, []kafka.ConfigResource{
+		{Type: kafka.ResourceTopic, Name: topic.Topic},
+	}, kafka.SetAdminRequestTimeout(adminTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to describe the kafka topic %s: %w", topic.Topic, err)
+	}
+	for _, result := range results {
+		entry, found := result.Config["max.message.bytes"]
+		if !found {
+			continue
+		}
+		maxMessageBytes, err := strconv.Atoi(entry.Value)
+		if err != nil {
+			return fmt.Errorf("invalid max.message.bytes %s of the kafka topic %s", entry.Value, topic.Topic)
+		}
+		if maxMessageBytes < producer.DefaultMessageKBSize*1000 {
+			return fmt.Errorf("the max.message.bytes %d of the kafka topic %s is less than %d", maxMessageBytes,
+				topic.Topic, producer.DefaultMessageKBSize*1000)
+		}
+	}
+	return nil
+}
+
+// validateTopics reports all the topics which don't exist, aren't accessible or don't have the required configuration
+func validateTopics(ctx context.Context, admin topicAdmin, metadata *kafka.Metadata,
+	topic *transport.ClusterTopic,
+) error {
+	problems := []string{}
+	for _, topicName := range []string{topic.SpecTopic, topic.StatusTopic, topic.EventTopic} {
+		if strings.HasPrefix(topicName, "^") {
+			continue
+		}
+		topicMetadata, found := metadata.Topics[topicName]
+		if !found || topicMetadata.Error.Code() == kafka.ErrUnknownTopicOrPart {
+			problems = append(problems, fmt.Sprintf("the kafka topic %s doesn't exist", topicName))
+			continue
+		}
+		if topicMetadata.Error.Code() != kafka.ErrNoError {
+			problems = append(problems, fmt.Sprintf("the kafka topic %s isn't accessible: %v", topicName,
+				topicMetadata.Error))
+			continue
+		}
+		if err := validateTopic(ctx, admin, topicMetadata); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return &TopicValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func createTopic(ctx context.Context, admin topicAdmin, topicName string) error {
+	results, err := admin.CreateTopics(ctx, []kafka.TopicSpecification{
+		{Topic: topicName, NumPartitions: int(DefaultPartition), ReplicationFactor: -1},
+	}, kafka.SetAdminOperationTimeout(adminTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to create the kafka topic %s: %w", topicName, err)
+	}
+	for _, result := range results {
+		switch result.Error.Code() {
+		case kafka.ErrNoError, kafka.ErrTopicAlreadyExists:
+		case kafka.ErrTopicAuthorizationFailed, kafka.ErrClusterAuthorizationFailed, kafka.ErrPolicyViolation:
+			return fmt.Errorf("the kafka topic %s isn't allowed to be created, pre-create it in the kafka cluster: %w",
+				topicName, result.Error)
+		default:
+			return fmt.Errorf("failed to create the kafka topic %s: %w", topicName, result.Error)
+		}
+	}
+	return nil
+}
+
+func newTopicAdmin(secret *corev1.Secret) (topicAdmin, error) {
+	configMap := &kafka.ConfigMap{
+		"bootstrap.servers":                     string(secret.Data["bootstrap_server"]),
+		"ssl.endpoint.identification.algorithm": "none",
+	}
+	if len(secret.Data["ca.crt"]) > 0 {
+		_ = configMap.SetKey("security.protocol", "ssl")
+		_ = configMap.SetKey("ssl.ca.pem", string(secret.Data["ca.crt"]))
+		if len(secret.Data["client.crt"]) > 0 && len(secret.Data["client.key"]) > 0 {
+			_ = configMap.SetKey("ssl.certificate.pem", string(secret.Data["client.crt"]))
+			_ = configMap.SetKey("ssl.key.pem", string(secret.Data["client.key"]))
+		}
+	}
+	return kafka.NewAdminClient(configMap)
+}
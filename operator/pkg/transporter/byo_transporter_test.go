@@ -0,0 +1,180 @@
+package transporter
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type fakeTopicAdmin struct {
+	topics  map[string]kafka.TopicMetadata
+	configs map[string]string
+	created []string
+	denied  bool
+}
+
+func (a *fakeTopicAdmin) GetMetadata(topic *string, allTopics bool, timeoutMs int) (*kafka.Metadata, error) {
+	return &kafka.Metadata{Topics: a.topics}, nil
+}
+
+func (a *fakeTopicAdmin) DescribeConfigs(ctx context.Context, resources []kafka.ConfigResource,
+	options ...kafka.DescribeConfigsAdminOption,
+) ([]kafka.ConfigResourceResult, error) {
+	results := []kafka.ConfigResourceResult{}
+	for _, resource := range resources {
+		result := kafka.ConfigResourceResult{Name: resource.Name, Config: map[string]kafka.ConfigEntryResult{}}
+		for name, value := range a.configs {
+			result.Config[name] = kafka.ConfigEntryResult{Name: name, Value: value}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (a *fakeTopicAdmin) CreateTopics(ctx context.Context, topics []kafka.TopicSpecification,
+	options ...kafka.CreateTopicsAdminOption,
+) ([]kafka.TopicResult, error) {
+	results := []kafka.TopicResult{}
+	for _, topic := range topics {
+		if a.denied {
+			results = append(results, kafka.TopicResult{
+				Topic: topic.Topic, Error: kafka.NewError(kafka.ErrTopicAuthorizationFailed, "denied", false),
+			})
+			continue
+		}
+		a.created = append(a.created, topic.Topic)
+		results = append(results, kafka.TopicResult{Topic: topic.Topic, Error: kafka.NewError(kafka.ErrNoError, "", false)})
+	}
+	return results, nil
+}
+
+func (a *fakeTopicAdmin) Close() {}
+
+func newBYOTransporter(data map[string][]byte, admin *fakeTopicAdmin) *BYOTransporter {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "multicluster-global-hub-transport", Namespace: "default"},
+		Data:       data,
+	}
+	trans := NewBYOTransporter(context.Background(), types.NamespacedName{
+		Name: secret.Name, Namespace: secret.Namespace,
+	}, fake.NewClientBuilder().WithObjects(secret).Build())
+	trans.newAdmin = func(secret *corev1.Secret) (topicAdmin, error) {
+		return admin, nil
+	}
+	return trans
+}
+
+func TestBYOTransporterTopics(t *testing.T) {
+	admin := &fakeTopicAdmin{topics: map[string]kafka.TopicMetadata{}}
+	trans := newBYOTransporter(map[string][]byte{"bootstrap_server": []byte("localhost:9092")}, admin)
+
+	// the generic topics are left to the kafka cluster
+	topic := trans.GenerateClusterTopic("hub1")
+	assert.Equal(t, &transport.ClusterTopic{
+		SpecTopic: transport.GenericSpecTopic, StatusTopic: transport.GenericStatusTopic,
+		EventTopic: transport.GenericEventTopic,
+	}, topic)
+	assert.Nil(t, trans.CreateTopic(topic))
+	assert.Empty(t, admin.created)
+
+	admin = &fakeTopicAdmin{
+		topics: map[string]kafka.TopicMetadata{
+			"acme.globalhub.spec": {Topic: "acme.globalhub.spec", Partitions: []kafka.PartitionMetadata{{ID: 0}}},
+		},
+		configs: map[string]string{"max.message.bytes": "1048588"},
+	}
+	trans = newBYOTransporter(map[string][]byte{
+		"bootstrap_server":  []byte("localhost:9092"),
+		BYOSpecTopicKey:     []byte("acme.globalhub.spec"),
+		BYOStatusTopicKey:   []byte("acme.globalhub.status.{hub}"),
+		BYOEventTopicKey:    []byte("acme.globalhub.event"),
+		"unrelated.setting": []byte("value"),
+	}, admin)
+
+	// the manager subscribes the regex of the status topics
+	topic = trans.GenerateClusterTopic(GlobalHubClusterName)
+	assert.Equal(t, `^acme\.globalhub\.status\..*`, topic.StatusTopic)
+	assert.Nil(t, trans.CreateTopic(topic))
+	assert.Equal(t, []string{"acme.globalhub.event"}, admin.created)
+
+	// the status topic of the hub is created
+	topic = trans.GenerateClusterTopic("hub1")
+	assert.Equal(t, "acme.globalhub.spec", topic.SpecTopic)
+	assert.Equal(t, "acme.globalhub.status.hub1", topic.StatusTopic)
+	admin.topics["acme.globalhub.event"] = kafka.TopicMetadata{
+		Topic: "acme.globalhub.event", Partitions: []kafka.PartitionMetadata{{ID: 0}},
+	}
+	assert.Nil(t, trans.CreateTopic(topic))
+	assert.Equal(t, []string{"acme.globalhub.event", "acme.globalhub.status.hub1"}, admin.created)
+
+	// the topic creation is denied
+	admin.denied = true
+	err := trans.CreateTopic(trans.GenerateClusterTopic("hub2"))
+	assert.ErrorContains(t, err, "pre-create it")
+
+	// the existing topic can't carry the bundles
+	admin.configs["max.message.bytes"] = "1000"
+	err = trans.CreateTopic(trans.GenerateClusterTopic(GlobalHubClusterName))
+	assert.ErrorContains(t, err, "max.message.bytes")
+}
+
+func TestBYOTransporterTrustedCABundle(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "multicluster-global-hub-transport", Namespace: "default"},
+		Data: map[string][]byte{
+			"bootstrap_server": []byte("kafka.example.com:9093"),
+			"ca.crt":           []byte("kafka-ca"),
+		},
+	}
+	trans := NewBYOTransporter(context.Background(), types.NamespacedName{
+		Name: secret.Name, Namespace: secret.Namespace,
+	}, fake.NewClientBuilder().WithObjects(secret).Build(), WithTrustedCABundle([]byte("enterprise-ca\n")))
+
+	conn, err := trans.GetConnCredential("")
+	assert.Nil(t, err)
+	caCert, err := base64.StdEncoding.DecodeString(conn.CACert)
+	assert.Nil(t, err)
+	assert.Equal(t, "kafka-ca\nenterprise-ca\n", string(caCert))
+}
+
+func TestBYOTransporterReadOnly(t *testing.T) {
+	admin := &fakeTopicAdmin{
+		topics: map[string]kafka.TopicMetadata{
+			transport.GenericSpecTopic: {Topic: transport.GenericSpecTopic, Partitions: []kafka.PartitionMetadata{{ID: 0}}},
+			transport.GenericEventTopic: {
+				Topic: transport.GenericEventTopic, Partitions: []kafka.PartitionMetadata{{ID: 0}},
+				Error: kafka.NewError(kafka.ErrTopicAuthorizationFailed, "denied", false),
+			},
+		},
+		configs: map[string]string{"max.message.bytes": "1000"},
+	}
+	trans := newBYOTransporter(map[string][]byte{"bootstrap_server": []byte("localhost:9092")}, admin)
+	WithReadOnly(true)(trans)
+
+	// the generic topics are validated as well, all the problems are reported
+	err := trans.CreateTopic(trans.GenerateClusterTopic(GlobalHubClusterName))
+	validationErr := &TopicValidationError{}
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Problems, 3)
+	assert.Contains(t, validationErr.Problems[0], "max.message.bytes")
+	assert.Equal(t, "the kafka topic status doesn't exist", validationErr.Problems[1])
+	assert.Contains(t, validationErr.Problems[2], "isn't accessible")
+	// the missing topics are never created
+	assert.Empty(t, admin.created)
+
+	admin.configs["max.message.bytes"] = "1048588"
+	for _, name := range []string{transport.GenericStatusTopic, transport.GenericEventTopic} {
+		admin.topics[name] = kafka.TopicMetadata{Topic: name, Partitions: []kafka.PartitionMetadata{{ID: 0}}}
+	}
+	assert.Nil(t, trans.CreateTopic(trans.GenerateClusterTopic(GlobalHubClusterName)))
+	assert.Empty(t, admin.created)
+}
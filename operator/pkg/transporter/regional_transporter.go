@@ -0,0 +1,48 @@
+package transporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// NewRegionalTransporters creates the transporters of the regional kafka clusters from the transport secrets labeled
+// with the region in the namespace. The secret has the same properties as the BYO transport secret, and the agents of
+// the managed hubs labeled with the region connect to it. The regions are the dns labels, since they're the identities
+// of the kafka clusters consumed by the manager.
+func NewRegionalTransporters(ctx context.Context, c client.Client, namespace string, opts ...BYOOption,
+) (map[string]transport.Transporter, error) {
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, client.InNamespace(namespace),
+		client.HasLabels{operatorconstants.GHTransportRegionLabelKey}); err != nil {
+		return nil, fmt.Errorf("failed to list the regional transport secrets: %w", err)
+	}
+
+	transporters := map[string]transport.Transporter{}
+	for _, secret := range secrets.Items {
+		region := secret.Labels[operatorconstants.GHTransportRegionLabelKey]
+		if errs := validation.IsDNS1123Label(region); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid region %q of the transport secret %s: %s", region, secret.Name,
+				strings.Join(errs, ", "))
+		}
+		if _, found := transporters[region]; found {
+			return nil, fmt.Errorf("the region %s has more than one transport secret", region)
+		}
+		if len(secret.Data["bootstrap_server"]) == 0 {
+			return nil, fmt.Errorf("the bootstrap_server of the transport secret %s is required", secret.Name)
+		}
+		transporters[region] = NewBYOTransporter(ctx, types.NamespacedName{
+			Namespace: secret.Namespace,
+			Name:      secret.Name,
+		}, c, opts...)
+	}
+	return transporters, nil
+}
@@ -0,0 +1,72 @@
+package transporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+)
+
+func regionalSecret(name, region, namespace string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{operatorconstants.GHTransportRegionLabelKey: region},
+		},
+		Data: data,
+	}
+}
+
+func TestNewRegionalTransporters(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "multicluster-global-hub-transport", Namespace: "default"},
+			Data:       map[string][]byte{"bootstrap_server": []byte("global-kafka.example.com:9093")},
+		},
+		regionalSecret("transport-east", "east", "default", map[string][]byte{
+			"bootstrap_server": []byte("east-kafka.example.com:9093"),
+			"ca.crt":           []byte("east-ca"),
+			BYOStatusTopicKey:  []byte("east.status.{hub}"),
+		}),
+		// the secrets of the other namespaces are ignored
+		regionalSecret("transport-west", "west", "other", map[string][]byte{
+			"bootstrap_server": []byte("west-kafka.example.com:9093"),
+		}),
+	).Build()
+
+	transporters, err := NewRegionalTransporters(ctx, c, "default", WithTrustedCABundle([]byte("enterprise-ca\n")))
+	assert.Nil(t, err)
+	assert.Len(t, transporters, 1)
+
+	// the agents of the region connect to the regional kafka cluster with the trusted CA bundle
+	conn, err := transporters["east"].GetConnCredential("")
+	assert.Nil(t, err)
+	assert.Equal(t, "east-kafka.example.com:9093", conn.BootstrapServer)
+	assert.Equal(t, "east.status.hub1", transporters["east"].GenerateClusterTopic("hub1").StatusTopic)
+
+	// the region is the identity of the kafka cluster consumed by the manager
+	c = fake.NewClientBuilder().WithObjects(regionalSecret("transport-east", "East_1", "default",
+		map[string][]byte{"bootstrap_server": []byte("east-kafka.example.com:9093")})).Build()
+	_, err = NewRegionalTransporters(ctx, c, "default")
+	assert.ErrorContains(t, err, "invalid region")
+
+	c = fake.NewClientBuilder().WithObjects(
+		regionalSecret("transport-east", "east", "default",
+			map[string][]byte{"bootstrap_server": []byte("east-kafka.example.com:9093")}),
+		regionalSecret("transport-east-2", "east", "default",
+			map[string][]byte{"bootstrap_server": []byte("east-kafka-2.example.com:9093")}),
+	).Build()
+	_, err = NewRegionalTransporters(ctx, c, "default")
+	assert.ErrorContains(t, err, "more than one transport secret")
+
+	c = fake.NewClientBuilder().WithObjects(regionalSecret("transport-east", "east", "default", nil)).Build()
+	_, err = NewRegionalTransporters(ctx, c, "default")
+	assert.ErrorContains(t, err, "bootstrap_server")
+}
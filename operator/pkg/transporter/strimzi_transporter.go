@@ -71,6 +71,12 @@ var (
 	KakfaMetricsConfigmapName       = "kafka-metrics"
 	KafkaMetricsConfigmapKeyRef     = "kafka-metrics-config.yml"
 	ZooKeeperMetricsConfigmapKeyRef = "zookeeper-metrics-config.yml"
+	// the kafka exporter collects the metrics of all the consumer groups and topics
+	kafkaExporterRegex = ".*"
+	// DefaultTopicConfig is the config of the global hub topics, the existing topics are updated by the upgrade
+	DefaultTopicConfig = map[string]string{
+		"cleanup.policy": "compact",
+	}
 )
 
 // install the strimzi kafka cluster by operator
@@ -213,7 +219,12 @@ func (k *strimziTransporter) CreateUser(username string) error {
 		Namespace: k.namespace,
 	}, kafkaUser)
 	if err != nil && errors.IsNotFound(err) {
-		return k.runtimeClient.Create(k.ctx, k.newKafkaUser(username))
+		if e := k.runtimeClient.Create(k.ctx, k.newKafkaUser(username)); e != nil {
+			return e
+		}
+		config.RecordAction(k.mgh, config.ReasonKafkaUserCreated, fmt.Sprintf("Created the kafka user %s", username),
+			"user", username, "namespace", k.namespace)
+		return nil
 	}
 	return err
 }
@@ -230,7 +241,12 @@ func (k *strimziTransporter) DeleteUser(topicName string) error {
 	if err != nil {
 		return err
 	}
-	return k.runtimeClient.Delete(k.ctx, kafkaUser)
+	if err := k.runtimeClient.Delete(k.ctx, kafkaUser); err != nil {
+		return err
+	}
+	config.RecordAction(k.mgh, config.ReasonKafkaUserDeleted, fmt.Sprintf("Deleted the kafka user %s", topicName),
+		"user", topicName, "namespace", k.namespace)
+	return nil
 }
 
 func (k *strimziTransporter) GenerateClusterTopic(clusterIdentity string) *transport.ClusterTopic {
@@ -265,13 +281,20 @@ func (k *strimziTransporter) CreateTopic(topic *transport.ClusterTopic) error {
 			if e := k.runtimeClient.Create(k.ctx, k.newKafkaTopic(topicName)); e != nil {
 				return e
 			}
+			config.RecordAction(k.mgh, config.ReasonKafkaTopicCreated,
+				fmt.Sprintf("Created the kafka topic %s", topicName), "topic", topicName, "namespace", k.namespace)
 		}
 	}
 	return nil
 }
 
+// DeleteTopic only deletes the topics dedicated to the cluster, the shared topics are still used by the other clusters
 func (k *strimziTransporter) DeleteTopic(topic *transport.ClusterTopic) error {
 	for _, topicName := range []string{topic.SpecTopic, topic.StatusTopic, topic.EventTopic} {
+		if topicName == transport.GenericSpecTopic || topicName == transport.GenericStatusTopic ||
+			topicName == transport.GenericEventTopic {
+			continue
+		}
 		kafkaTopic := &kafkav1beta2.KafkaTopic{}
 		err := k.runtimeClient.Get(k.ctx, types.NamespacedName{
 			Name:      topicName,
@@ -287,6 +310,8 @@ func (k *strimziTransporter) DeleteTopic(topic *transport.ClusterTopic) error {
 		if err != nil {
 			return err
 		}
+		config.RecordAction(k.mgh, config.ReasonKafkaTopicDeleted,
+			fmt.Sprintf("Deleted the kafka topic %s", topicName), "topic", topicName, "namespace", k.namespace)
 	}
 	return nil
 }
@@ -499,6 +524,7 @@ func (k *strimziTransporter) GetConnCredential(username string) (*transport.Conn
 // }
 
 func (k *strimziTransporter) newKafkaTopic(topicName string) *kafkav1beta2.KafkaTopic {
+	topicConfig, _ := json.Marshal(DefaultTopicConfig)
 	return &kafkav1beta2.KafkaTopic{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      topicName,
@@ -512,9 +538,7 @@ func (k *strimziTransporter) newKafkaTopic(topicName string) *kafkav1beta2.Kafka
 		Spec: &kafkav1beta2.KafkaTopicSpec{
 			Partitions: &DefaultPartition,
 			Replicas:   &k.topicPartitionReplicas,
-			Config: &apiextensions.JSON{Raw: []byte(`{
-				"cleanup.policy": "compact"
-			}`)},
+			Config:     &apiextensions.JSON{Raw: topicConfig},
 		},
 	}
 }
@@ -618,11 +642,34 @@ func (k *strimziTransporter) createUpdateKafkaCluster(mgh *operatorv1alpha4.Mult
 	}
 
 	if !equality.Semantic.DeepDerivative(updatedKafka.Spec, existingKafka.Spec) {
-		return k.runtimeClient.Update(k.ctx, updatedKafka), true
+		if err := k.runtimeClient.Update(k.ctx, updatedKafka); err != nil {
+			return err, true
+		}
+		// the strimzi operator expands the persistent volume claims of the brokers and zookeepers
+		existingSize, desiredSize := kafkaStorageSize(existingKafka), kafkaStorageSize(updatedKafka)
+		if existingSize != desiredSize {
+			config.RecordAction(mgh, config.ReasonKafkaStorageResized,
+				fmt.Sprintf("Resized the kafka storage from %s to %s", existingSize, desiredSize),
+				"kafka", k.name, "namespace", k.namespace, "from", existingSize, "to", desiredSize)
+		}
+		return nil, true
 	}
 	return nil, false
 }
 
+// kafkaStorageSize returns the size of the persistent claim volume of the kafka brokers
+func kafkaStorageSize(kafka *kafkav1beta2.Kafka) string {
+	if kafka.Spec == nil {
+		return ""
+	}
+	for _, volume := range kafka.Spec.Kafka.Storage.Volumes {
+		if volume.Size != nil {
+			return *volume.Size
+		}
+	}
+	return ""
+}
+
 func (k *strimziTransporter) getKafkaResources(
 	mgh *operatorv1alpha4.MulticlusterGlobalHub,
 ) *kafkav1beta2.KafkaSpecKafkaResources {
@@ -740,10 +787,44 @@ func (k *strimziTransporter) newKafkaCluster(mgh *operatorv1alpha4.MulticlusterG
 	k.setTolerations(mgh, kafkaCluster)
 	k.setMetricsConfig(mgh, kafkaCluster)
 	k.setImagePullSecret(mgh, kafkaCluster)
+	k.setNetworkPolicyPeers(mgh, kafkaCluster)
 
 	return kafkaCluster
 }
 
+// plainListenerPeerLabels selects the in-namespace clients of the plain listener: the manager, the debezium connector
+// of the change data capture and the mirror maker of the standby hub
+var plainListenerPeerLabels = []string{
+	`{"name": "multicluster-global-hub-manager"}`,
+	`{"strimzi.io/kind": "KafkaConnect"}`,
+	`{"strimzi.io/kind": "KafkaMirrorMaker2"}`,
+}
+
+// setNetworkPolicyPeers restricts the plain listener to the in-namespace kafka clients if the mgh enableNetworkPolicy,
+// the strimzi operator generates the NetworkPolicy of the listeners. The tls listener is exposed by the route and
+// authenticated by the client certificates, so it's still reachable from the router.
+func (k *strimziTransporter) setNetworkPolicyPeers(mgh *operatorv1alpha4.MulticlusterGlobalHub,
+	kafkaCluster *kafkav1beta2.Kafka,
+) {
+	if !mgh.Spec.EnableNetworkPolicy {
+		return
+	}
+	for i, listener := range kafkaCluster.Spec.Kafka.Listeners {
+		if listener.Tls {
+			continue
+		}
+		for _, labels := range plainListenerPeerLabels {
+			kafkaCluster.Spec.Kafka.Listeners[i].NetworkPolicyPeers = append(
+				kafkaCluster.Spec.Kafka.Listeners[i].NetworkPolicyPeers,
+				kafkav1beta2.KafkaSpecKafkaListenersElemNetworkPolicyPeersElem{
+					PodSelector: &kafkav1beta2.KafkaSpecKafkaListenersElemNetworkPolicyPeersElemPodSelector{
+						MatchLabels: &apiextensions.JSON{Raw: []byte(labels)},
+					},
+				})
+		}
+	}
+}
+
 // set metricsConfig for kafka cluster based on the mgh enableMetrics
 func (k *strimziTransporter) setMetricsConfig(mgh *operatorv1alpha4.MulticlusterGlobalHub,
 	kafkaCluster *kafkav1beta2.Kafka,
@@ -771,6 +852,11 @@ func (k *strimziTransporter) setMetricsConfig(mgh *operatorv1alpha4.Multicluster
 		}
 		kafkaCluster.Spec.Kafka.MetricsConfig = kafkaMetricsConfig
 		kafkaCluster.Spec.Zookeeper.MetricsConfig = zookeeperMetricsConfig
+		// the kafka exporter exposes the consumer lag of the topics
+		kafkaCluster.Spec.KafkaExporter = &kafkav1beta2.KafkaSpecKafkaExporter{
+			GroupRegex: &kafkaExporterRegex,
+			TopicRegex: &kafkaExporterRegex,
+		}
 	}
 }
 
@@ -16,6 +16,7 @@ import (
 	subv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -319,7 +320,56 @@ func TestStrimziTransporter(t *testing.T) {
 	err = trans.DeleteTopic(clusterTopic)
 	assert.Nil(t, err)
 
+	// the shared topics are kept, only the status topic of the cluster is deleted
+	kafkaTopic := &kafkav1beta2.KafkaTopic{}
+	err = runtimeClient.Get(context.TODO(), types.NamespacedName{Name: "spec", Namespace: "default"}, kafkaTopic)
+	assert.Nil(t, err)
+	err = runtimeClient.Get(context.TODO(), types.NamespacedName{
+		Name:      clusterTopic.StatusTopic,
+		Namespace: "default",
+	}, kafkaTopic)
+	assert.True(t, errors.IsNotFound(err))
+
 	// test block
 	_, err = NewStrimziTransporter(runtimeClient, mgh, WithWaitReady(true))
 	assert.Nil(t, err)
 }
+
+func TestSetNetworkPolicyPeers(t *testing.T) {
+	mgh := &v1alpha4.MulticlusterGlobalHub{
+		Spec: v1alpha4.MulticlusterGlobalHubSpec{EnableNetworkPolicy: true},
+	}
+	kafkaCluster := &kafkav1beta2.Kafka{
+		Spec: &kafkav1beta2.KafkaSpec{
+			Kafka: kafkav1beta2.KafkaSpecKafka{
+				Listeners: []kafkav1beta2.KafkaSpecKafkaListenersElem{
+					{Name: "plain", Port: 9092, Tls: false},
+					{Name: "tls", Port: 9093, Tls: true},
+				},
+			},
+		},
+	}
+	k := &strimziTransporter{}
+	k.setNetworkPolicyPeers(mgh, kafkaCluster)
+
+	// the tls listener is reachable from the router
+	assert.Empty(t, kafkaCluster.Spec.Kafka.Listeners[1].NetworkPolicyPeers)
+
+	peers := []map[string]string{}
+	for _, peer := range kafkaCluster.Spec.Kafka.Listeners[0].NetworkPolicyPeers {
+		labels := map[string]string{}
+		assert.Nil(t, json.Unmarshal(peer.PodSelector.MatchLabels.Raw, &labels))
+		peers = append(peers, labels)
+	}
+	assert.Equal(t, []map[string]string{
+		{"name": "multicluster-global-hub-manager"},
+		{"strimzi.io/kind": "KafkaConnect"},
+		{"strimzi.io/kind": "KafkaMirrorMaker2"},
+	}, peers)
+
+	// nothing is restricted if the network policy is disabled
+	mgh.Spec.EnableNetworkPolicy = false
+	kafkaCluster.Spec.Kafka.Listeners[0].NetworkPolicyPeers = nil
+	k.setNetworkPolicyPeers(mgh, kafkaCluster)
+	assert.Empty(t, kafkaCluster.Spec.Kafka.Listeners[0].NetworkPolicyPeers)
+}
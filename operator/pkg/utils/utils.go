@@ -34,12 +34,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	commonconstants "github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -233,6 +235,25 @@ func CopyMap(newMap, originalMap map[string]string) {
 	}
 }
 
+// ImportLoopReason returns the reason why the cluster can't be a managed hub of the global hub identified by the
+// globalHubID, the events would loop back to the global hub if the cluster is the global hub cluster itself or it's
+// running another global hub. It returns empty if the cluster can be a managed hub.
+func ImportLoopReason(cluster *clusterv1.ManagedCluster, globalHubID string) string {
+	if cluster.Name == constants.LocalClusterName || cluster.Labels[constants.LocalClusterName] == "true" {
+		return "it's the global hub cluster itself"
+	}
+	for _, claim := range cluster.Status.ClusterClaims {
+		if claim.Name != commonconstants.GlobalHubClusterClaimName || claim.Value == "" {
+			continue
+		}
+		if claim.Value == globalHubID {
+			return "it's the global hub cluster itself"
+		}
+		return "it's running another global hub " + claim.Value
+	}
+	return ""
+}
+
 func WaitGlobalHubReady(ctx context.Context,
 	client client.Client,
 	interval time.Duration,
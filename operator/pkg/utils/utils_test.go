@@ -25,11 +25,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/apis/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/condition"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	commonconstants "github.com/stolostron/multicluster-global-hub/pkg/constants"
 )
 
 func Test_getAlertGPCcount(t *testing.T) {
@@ -526,3 +528,64 @@ func Test_GetResources(t *testing.T) {
 		})
 	}
 }
+
+func Test_ImportLoopReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *clusterv1.ManagedCluster
+		loop    bool
+	}{
+		{
+			name:    "local cluster",
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: constants.LocalClusterName}},
+			loop:    true,
+		},
+		{
+			name: "local cluster with another name",
+			cluster: &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+				Name: "self", Labels: map[string]string{constants.LocalClusterName: "true"},
+			}},
+			loop: true,
+		},
+		{
+			name: "global hub cluster itself",
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub1"},
+				Status: clusterv1.ManagedClusterStatus{ClusterClaims: []clusterv1.ManagedClusterClaim{
+					{Name: commonconstants.GlobalHubClusterClaimName, Value: "1234"},
+				}},
+			},
+			loop: true,
+		},
+		{
+			name: "another global hub",
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub1"},
+				Status: clusterv1.ManagedClusterStatus{ClusterClaims: []clusterv1.ManagedClusterClaim{
+					{Name: commonconstants.GlobalHubClusterClaimName, Value: "5678"},
+				}},
+			},
+			loop: true,
+		},
+		{
+			name: "managed hub",
+			cluster: &clusterv1.ManagedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "hub1"},
+				Status: clusterv1.ManagedClusterStatus{ClusterClaims: []clusterv1.ManagedClusterClaim{
+					{Name: commonconstants.HubClusterClaimName, Value: commonconstants.HubInstalledByUser},
+				}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := ImportLoopReason(tt.cluster, "1234")
+			if tt.loop && reason == "" {
+				t.Errorf("expected the import loop of the cluster %s", tt.cluster.Name)
+			}
+			if !tt.loop && reason != "" {
+				t.Errorf("unexpected import loop of the cluster %s: %s", tt.cluster.Name, reason)
+			}
+		})
+	}
+}
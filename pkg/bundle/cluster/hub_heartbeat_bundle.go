@@ -0,0 +1,16 @@
+package cluster
+
+import "time"
+
+// HubHeartbeat is the health of the agent reported with the heartbeat of the managed hub, the heartbeat of the agent
+// before it is an empty list, so the health is absent for them
+type HubHeartbeat struct {
+	AgentVersion string `json:"agentVersion"`
+	// Collectors are the status syncers launched by the agent, e.g. status.managed_cluster
+	Collectors []string `json:"collectors"`
+	// LastSyncTimes is the last time each bundle is sent successfully, the bundle is named by its event type without
+	// the common prefix, e.g. managedcluster
+	LastSyncTimes map[string]time.Time `json:"lastSyncTimes,omitempty"`
+	// ResourceCounts is the last resource counts reported by the agent
+	ResourceCounts map[string]int `json:"resourceCounts,omitempty"`
+}
@@ -0,0 +1,18 @@
+package cluster
+
+import "time"
+
+// HubMetrics is the samples of the key metrics queried from the prometheus of the managed hub, they're stored by the
+// global hub so the metrics can be compared across the managed hubs
+type HubMetrics struct {
+	Samples   []HubMetricSample `json:"samples"`
+	SampledAt time.Time         `json:"sampledAt"`
+}
+
+// HubMetricSample is a series of the query result, the labels are the ones returned by the query, e.g. the "le" is
+// aggregated away by the histogram quantile
+type HubMetricSample struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
@@ -0,0 +1,17 @@
+package cluster
+
+import "time"
+
+// the resource types counted by the agent, each of them is compared with the rows of the hub in the database
+const (
+	ResourceManagedClusters = "managedclusters"
+	ResourceLocalPolicies   = "localpolicies"
+)
+
+// HubResourceCounts is the number of the resources reported by the agent of the managed hub, the global hub detects
+// the lost messages by comparing them with the records persisted in the database
+type HubResourceCounts struct {
+	// Counts is the number of each resource type, the type which isn't synced by the agent is absent
+	Counts    map[string]int `json:"counts"`
+	CountedAt time.Time      `json:"countedAt"`
+}
@@ -0,0 +1,24 @@
+package cluster
+
+import "time"
+
+// the health status of the addon on the managed cluster, which is derived from the conditions of the addon
+const (
+	AddonStatusAvailable   = "available"
+	AddonStatusDegraded    = "degraded"
+	AddonStatusProgressing = "progressing"
+	AddonStatusUnknown     = "unknown"
+)
+
+// ManagedClusterAddon is the health of the addon installed on the managed cluster
+type ManagedClusterAddon struct {
+	ClusterName string `json:"clusterName"`
+	AddonName   string `json:"addonName"`
+	Status      string `json:"status"`
+	// Reason and Message are from the condition which decides the status
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+type ManagedClusterAddonBundle []ManagedClusterAddon
@@ -0,0 +1,26 @@
+package cluster
+
+import "time"
+
+// the status of the credential of the managed service account, which is derived from the conditions of it
+const (
+	ServiceAccountStatusReady   = "ready"
+	ServiceAccountStatusFailed  = "failed"
+	ServiceAccountStatusPending = "pending"
+)
+
+// ManagedServiceAccount is the credential brokered by the managed service account on the managed cluster. The token
+// isn't included, it's only in the secret of the managed hub, which is referenced by the TokenSecretName.
+type ManagedServiceAccount struct {
+	ClusterName string `json:"clusterName"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	// Reason and Message are from the condition which decides the status
+	Reason          string     `json:"reason,omitempty"`
+	Message         string     `json:"message,omitempty"`
+	TokenSecretName string     `json:"tokenSecretName,omitempty"`
+	LastRefreshTime *time.Time `json:"lastRefreshTime,omitempty"`
+	ExpirationTime  *time.Time `json:"expirationTime,omitempty"`
+}
+
+type ManagedServiceAccountBundle []ManagedServiceAccount
@@ -0,0 +1,22 @@
+package cluster
+
+import "time"
+
+// RegionalHubSummary is the summary of the managed hubs of the regional global hub, which is forwarded to the upstream
+// global hub in the hierarchical topology, the regional global hub is the source of the event
+type RegionalHubSummary struct {
+	ManagedHubs []ManagedHubSummary `json:"managedHubs"`
+	ReportedAt  time.Time           `json:"reportedAt"`
+}
+
+// ManagedHubSummary is the aggregated status of a managed hub of the regional global hub
+type ManagedHubSummary struct {
+	Name            string    `json:"name"`
+	Status          string    `json:"status"`
+	LastHeartbeat   time.Time `json:"lastHeartbeat"`
+	ManagedClusters int       `json:"managedClusters"`
+	Policies        int       `json:"policies"`
+	// Compliance is the number of the compliance records of the local policies by the compliance status, e.g.
+	// compliant and non_compliant
+	Compliance map[string]int `json:"compliance"`
+}
@@ -0,0 +1,19 @@
+package conflict
+
+import "time"
+
+// ResourceConflict is a global resource which is modified on the managed hub by the other field managers
+type ResourceConflict struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Policy is the conflict policy applied by the agent
+	Policy string `json:"policy"`
+	// FieldManagers are the managers which modified the resource on the managed hub
+	FieldManagers []string  `json:"fieldManagers"`
+	DetectedAt    time.Time `json:"detectedAt"`
+}
+
+// ResourceConflictBundle is the full list of the conflicts on the managed hub
+type ResourceConflictBundle []ResourceConflict
@@ -13,4 +13,8 @@ type BaseEvent struct {
 	Count          int32              `json:"count,omitempty"`
 	Source         corev1.EventSource `json:"source,omitempty"`
 	CreatedAt      metav1.Time        `json:"createdAt,omitempty"`
+	// Type is the type of the kubernetes event, Normal or Warning. It's empty from the agents of the older versions,
+	// then it's inferred by the reason and the compliance on ingestion.
+	Type           string         `json:"type,omitempty"`
+	InvolvedObject InvolvedObject `json:"involvedObject,omitempty"`
 }
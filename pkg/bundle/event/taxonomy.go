@@ -0,0 +1,126 @@
+package event
+
+import (
+	"strings"
+)
+
+// the types of the kubernetes events
+const (
+	NormalEventType  = "Normal"
+	WarningEventType = "Warning"
+)
+
+// Severity is the normalized severity of the event, the dashboards and the notification rules select the events by it.
+type Severity string
+
+const (
+	// SeverityCritical is the warning event reporting the failure of the component, e.g. the policy isn't propagated
+	SeverityCritical Severity = "critical"
+	// SeverityWarning is the other warning event, e.g. the policy is non compliant on the cluster
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is the normal event
+	SeverityInfo Severity = "info"
+)
+
+// Category groups the events by the concern of them.
+type Category string
+
+const (
+	// CategoryCompliance is the compliance of the policy templates reported by the cluster
+	CategoryCompliance Category = "compliance"
+	// CategoryPropagation is the propagation of the policy to the clusters by the hub
+	CategoryPropagation Category = "propagation"
+	CategoryCluster     Category = "cluster"
+	CategoryAddon       Category = "addon"
+	CategoryPlacement   Category = "placement"
+	CategoryOther       Category = "other"
+)
+
+// the keywords of the reasons of the events reporting the failures, e.g. PolicyPropagationFailed, BackOff
+var failureKeywords = []string{"fail", "error", "backoff", "invalid", "unhealthy"}
+
+var categoryOfKind = map[string]Category{
+	"ManagedCluster":      CategoryCluster,
+	"ManagedClusterAddOn": CategoryAddon,
+	"Placement":           CategoryPlacement,
+	"PlacementDecision":   CategoryPlacement,
+	"PlacementRule":       CategoryPlacement,
+}
+
+// InvolvedObject is the object the event is about.
+type InvolvedObject struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// NormalizedEvent is the taxonomy of the event from the agents, it's stored in the structured columns of the event
+// tables.
+type NormalizedEvent struct {
+	Type     string
+	Severity Severity
+	Category Category
+	InvolvedObject
+}
+
+// Normalize returns the taxonomy of the event. The involved object is the defaultObject if the agent doesn't report
+// it, and the compliance is the state of the policy of the event, it's empty for the other kinds.
+func Normalize(evt *BaseEvent, defaultObject InvolvedObject, compliance string) NormalizedEvent {
+	normalized := NormalizedEvent{Type: evt.Type, InvolvedObject: evt.InvolvedObject}
+	if normalized.Kind == "" {
+		normalized.InvolvedObject = defaultObject
+	}
+
+	failed := isFailure(evt.Reason)
+	nonCompliant := strings.EqualFold(compliance, "NonCompliant") || strings.EqualFold(compliance, "non_compliant")
+	if normalized.Type != NormalEventType && normalized.Type != WarningEventType {
+		normalized.Type = NormalEventType
+		if failed || nonCompliant {
+			normalized.Type = WarningEventType
+		}
+	}
+
+	switch {
+	case normalized.Type == WarningEventType && failed:
+		normalized.Severity = SeverityCritical
+	case normalized.Type == WarningEventType || nonCompliant:
+		normalized.Severity = SeverityWarning
+	default:
+		normalized.Severity = SeverityInfo
+	}
+
+	normalized.Category = category(normalized.Kind, evt.Reason)
+	return normalized
+}
+
+func category(kind, reason string) Category {
+	if kind == "Policy" {
+		if strings.Contains(strings.ToLower(reason), "propagat") {
+			return CategoryPropagation
+		}
+		// the compliance events of the templates have the reason "policy: <namespace>/<template>"
+		return CategoryCompliance
+	}
+	if category, found := categoryOfKind[kind]; found {
+		return category
+	}
+	return CategoryOther
+}
+
+func isFailure(reason string) bool {
+	reason = strings.ToLower(reason)
+	for _, keyword := range failureKeywords {
+		if strings.Contains(reason, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicyNameOfEvent returns the name of the policy from the name of its event, which is "<policy>.<suffix>".
+func PolicyNameOfEvent(eventName string) string {
+	if i := strings.LastIndex(eventName, "."); i > 0 {
+		return eventName[:i]
+	}
+	return eventName
+}
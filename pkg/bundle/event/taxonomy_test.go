@@ -0,0 +1,61 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	policy := InvolvedObject{Kind: "Policy", Namespace: "default", Name: "policy-limitrange"}
+
+	cases := []struct {
+		name       string
+		evt        BaseEvent
+		compliance string
+		expected   NormalizedEvent
+	}{
+		{
+			name:       "propagated policy",
+			evt:        BaseEvent{Reason: "PolicyPropagation", Type: NormalEventType},
+			compliance: "Compliant",
+			expected: NormalizedEvent{
+				Type: NormalEventType, Severity: SeverityInfo, Category: CategoryPropagation, InvolvedObject: policy,
+			},
+		},
+		{
+			name:       "non compliant policy from the older agent",
+			evt:        BaseEvent{Reason: "policy: default/policy-limitrange"},
+			compliance: "NonCompliant",
+			expected: NormalizedEvent{
+				Type: WarningEventType, Severity: SeverityWarning, Category: CategoryCompliance, InvolvedObject: policy,
+			},
+		},
+		{
+			name: "failed to propagate the policy",
+			evt:  BaseEvent{Reason: "PolicyPropagationFailed", Type: WarningEventType},
+			expected: NormalizedEvent{
+				Type: WarningEventType, Severity: SeverityCritical, Category: CategoryPropagation, InvolvedObject: policy,
+			},
+		},
+		{
+			name: "reported involved object",
+			evt: BaseEvent{
+				Reason: "AvailableUnknown", Type: WarningEventType,
+				InvolvedObject: InvolvedObject{Kind: "ManagedCluster", Name: "cluster1"},
+			},
+			expected: NormalizedEvent{
+				Type: WarningEventType, Severity: SeverityWarning, Category: CategoryCluster,
+				InvolvedObject: InvolvedObject{Kind: "ManagedCluster", Name: "cluster1"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Normalize(&c.evt, policy, c.compliance))
+		})
+	}
+
+	assert.Equal(t, "policy-limitrange", PolicyNameOfEvent("policy-limitrange.17b8363660d39188"))
+	assert.Equal(t, "policy", PolicyNameOfEvent("policy"))
+}
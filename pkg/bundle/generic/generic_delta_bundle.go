@@ -0,0 +1,21 @@
+package generic
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectIdentity identifies the object deleted from the hub, the uid is empty if the object is unknown to the agent
+type ObjectIdentity struct {
+	UID       types.UID `json:"uid,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+}
+
+// DeltaBundle contains the objects updated and deleted since the last full bundle, which is the checkpoint of it
+type DeltaBundle[T any] struct {
+	Updated []T              `json:"updated"`
+	Deleted []ObjectIdentity `json:"deleted"`
+}
+
+type GenericObjectDeltaBundle = DeltaBundle[client.Object]
@@ -0,0 +1,27 @@
+package grc
+
+import "time"
+
+// GatekeeperConstraint is the audit result of a gatekeeper constraint on the cluster
+type GatekeeperConstraint struct {
+	Kind              string `json:"kind"`
+	Name              string `json:"name"`
+	ClusterName       string `json:"clusterName"`
+	EnforcementAction string `json:"enforcementAction"`
+	// TotalViolations is the number of the violations found by the audit, the violations in the status of the
+	// constraint are limited by the audit, e.g. 20 by default
+	TotalViolations int                   `json:"totalViolations"`
+	AuditTimestamp  *time.Time            `json:"auditTimestamp,omitempty"`
+	Violations      []GatekeeperViolation `json:"violations,omitempty"`
+}
+
+// GatekeeperViolation is the resource which violates the constraint
+type GatekeeperViolation struct {
+	Kind              string `json:"kind"`
+	Namespace         string `json:"namespace,omitempty"`
+	Name              string `json:"name"`
+	Message           string `json:"message"`
+	EnforcementAction string `json:"enforcementAction"`
+}
+
+type GatekeeperConstraintBundle []GatekeeperConstraint
@@ -0,0 +1,32 @@
+package grc
+
+const (
+	// PolicyReportSource is the source of the results in the policy reports collected by the agent
+	PolicyReportSource = "kyverno"
+	// PolicyReportAPIVersion is the apiVersion of the kyverno policies stored in the local policies, which tells them
+	// from the policies of the managed hub
+	PolicyReportAPIVersion = "kyverno.io/v1"
+
+	// the compliance of the kyverno policy, which is the same as the compliance type of the database
+	PolicyReportCompliant    = "compliant"
+	PolicyReportNonCompliant = "non_compliant"
+	PolicyReportUnknown      = "unknown"
+)
+
+// PolicyReportCompliance is the compliance of a kyverno policy on the cluster, normalized from the results of the
+// PolicyReports and ClusterPolicyReports
+type PolicyReportCompliance struct {
+	// PolicyID is generated from the hub and the policy, since the results only refer to the policy by its name
+	PolicyID    string `json:"policyId"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	Category    string `json:"category,omitempty"`
+	Severity    string `json:"severity,omitempty"`
+	ClusterName string `json:"clusterName"`
+	// Compliance is one of the compliance states of the database: compliant, non_compliant or unknown
+	Compliance string `json:"compliance"`
+	// Results is the count of the results by the result, e.g. pass, fail, warn, error and skip
+	Results map[string]int `json:"results"`
+}
+
+type PolicyReportBundle []PolicyReportCompliance
@@ -0,0 +1,43 @@
+package grc
+
+import "time"
+
+const (
+	// MaxTemplateMessageLength is the max length of the template message, the longer message is truncated
+	MaxTemplateMessageLength = 1024
+	// MaxTemplateRelatedObjects is the max number of the related objects of the template, the non-compliant objects
+	// are kept first
+	MaxTemplateRelatedObjects = 10
+)
+
+// PolicyTemplateDetail is the latest result of the template of the replicated policy on the cluster, so the
+// violations are diagnosed without accessing the managed hub
+type PolicyTemplateDetail struct {
+	// PolicyID is the id of the root policy, it's the id of the global policy if it's created by the global hub
+	PolicyID        string     `json:"policyId"`
+	PolicyNamespace string     `json:"policyNamespace"`
+	PolicyName      string     `json:"policyName"`
+	ClusterName     string     `json:"clusterName"`
+	ClusterID       string     `json:"clusterId,omitempty"`
+	TemplateKind    string     `json:"templateKind"`
+	TemplateName    string     `json:"templateName"`
+	Compliance      string     `json:"compliance"`
+	Message         string     `json:"message"`
+	LastTimestamp   *time.Time `json:"lastTimestamp,omitempty"`
+	// TotalRelatedObjects is the number of the related objects of the template, the related objects are limited by
+	// the MaxTemplateRelatedObjects
+	TotalRelatedObjects int                     `json:"totalRelatedObjects"`
+	RelatedObjects      []TemplateRelatedObject `json:"relatedObjects,omitempty"`
+}
+
+// TemplateRelatedObject is the object checked by the template, e.g. the related objects of the configuration policy
+type TemplateRelatedObject struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Compliance string `json:"compliance"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+type PolicyTemplateDetailBundle []PolicyTemplateDetail
@@ -0,0 +1,42 @@
+package grc
+
+import "time"
+
+// SecurityPostureBundle is the security posture of the cluster, it's aggregated from the scans of the compliance
+// operator and the image vulnerabilities found by the container security operator
+type SecurityPostureBundle struct {
+	ClusterName          string                      `json:"clusterName"`
+	ComplianceScans      []ComplianceScanResult      `json:"complianceScans,omitempty"`
+	ImageVulnerabilities []ImageVulnerabilitySummary `json:"imageVulnerabilities,omitempty"`
+}
+
+// ComplianceScanResult is the result of the checks of the compliance scan, e.g. ocp4-cis
+type ComplianceScanResult struct {
+	Name         string     `json:"name"`
+	Phase        string     `json:"phase"`
+	Result       string     `json:"result"`
+	EndTimestamp *time.Time `json:"endTimestamp,omitempty"`
+	Passed       int        `json:"passed"`
+	Failed       int        `json:"failed"`
+	Manual       int        `json:"manual"`
+	// Others are the checks of the other statuses, e.g. ERROR and INCONSISTENT
+	Others int `json:"others"`
+	// the failed checks by the severity of the rules
+	FailedHigh   int `json:"failedHigh"`
+	FailedMedium int `json:"failedMedium"`
+	FailedLow    int `json:"failedLow"`
+}
+
+// ImageVulnerabilitySummary is the vulnerabilities of the severity in the images running on the cluster
+type ImageVulnerabilitySummary struct {
+	// Severity is one of the Critical, High, Medium, Low, Negligible and Unknown
+	Severity string `json:"severity"`
+	// Images are the images of which the highest severity of the vulnerabilities is the severity
+	Images int `json:"images"`
+	// Vulnerabilities are the vulnerabilities of the severity in all the images
+	Vulnerabilities int `json:"vulnerabilities"`
+	// Fixable are the fixable vulnerabilities of the Images
+	Fixable int `json:"fixable"`
+	// AffectedPods are the pods running the Images
+	AffectedPods int `json:"affectedPods"`
+}
@@ -0,0 +1,162 @@
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// DataSchemaPrefix is the prefix of the dataschema of the status events, it's followed by the event type without the
+// common prefix and the version of the schema, e.g. .../schemas/managedcluster/v1
+const DataSchemaPrefix = "https://open-cluster-management.io/multicluster-global-hub/schemas/"
+
+// DefaultVersion is the version of the events without the dataschema, which are sent by the agents before the
+// schemas are versioned
+const DefaultVersion = "v1"
+
+// the schemas are stored as schemas/<version>/<event type without the common prefix>.json, a new version only needs
+// the schemas of the event types changed by it
+//
+//go:embed schemas
+var schemaFS embed.FS
+
+var registry = mustLoad()
+
+// schemas is the versions of the schemas of each event type, the versions are sorted from the oldest to the latest
+type schemas struct {
+	versions []string
+	byType   map[string]map[string]*gojsonschema.Schema
+}
+
+func mustLoad() *schemas {
+	s, err := load()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func load() (*schemas, error) {
+	s := &schemas{byType: map[string]map[string]*gojsonschema.Schema{}}
+	versionDirs, err := schemaFS.ReadDir("schemas")
+	if err != nil {
+		return nil, err
+	}
+	for _, versionDir := range versionDirs {
+		version := versionDir.Name()
+		s.versions = append(s.versions, version)
+		files, err := schemaFS.ReadDir(path.Join("schemas", version))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			data, err := schemaFS.ReadFile(path.Join("schemas", version, file.Name()))
+			if err != nil {
+				return nil, err
+			}
+			jsonSchema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the schema %s/%s: %w", version, file.Name(), err)
+			}
+			eventType := strings.TrimSuffix(file.Name(), ".json")
+			if _, found := s.byType[eventType]; !found {
+				s.byType[eventType] = map[string]*gojsonschema.Schema{}
+			}
+			s.byType[eventType][version] = jsonSchema
+		}
+	}
+	sort.Slice(s.versions, func(i, j int) bool {
+		return versionNumber(s.versions[i]) < versionNumber(s.versions[j])
+	})
+	return s, nil
+}
+
+// versionNumber returns the number of the version, e.g. 2 of v2
+func versionNumber(version string) int {
+	number := 0
+	_, _ = fmt.Sscanf(version, "v%d", &number)
+	return number
+}
+
+// shortType returns the event type without the common prefix
+func shortType(eventType string) string {
+	return strings.TrimPrefix(eventType, enum.EventTypePrefix)
+}
+
+// LatestVersion returns the latest version of the schema of the event type, it's empty if the event type isn't
+// registered
+func LatestVersion(eventType string) string {
+	versions := registry.byType[shortType(eventType)]
+	for i := len(registry.versions) - 1; i >= 0; i-- {
+		if _, found := versions[registry.versions[i]]; found {
+			return registry.versions[i]
+		}
+	}
+	return ""
+}
+
+// DataSchema returns the dataschema of the latest version of the event type, the sender sets it to the events so the
+// receiver validates them with the same version. It's empty if the event type isn't registered.
+func DataSchema(eventType string) string {
+	version := LatestVersion(eventType)
+	if version == "" {
+		return ""
+	}
+	return DataSchemaPrefix + shortType(eventType) + "/" + version
+}
+
+// Version returns the version of the schema which the event claims by its dataschema, it's the default version if
+// the event doesn't have the dataschema
+func Version(evt *cloudevents.Event) (string, error) {
+	dataSchema := evt.DataSchema()
+	if dataSchema == "" {
+		return DefaultVersion, nil
+	}
+	schemaPath, found := strings.CutPrefix(dataSchema, DataSchemaPrefix)
+	if !found {
+		return "", fmt.Errorf("the dataschema %s isn't a schema of the global hub", dataSchema)
+	}
+	idx := strings.LastIndex(schemaPath, "/")
+	if idx < 0 || schemaPath[:idx] != shortType(evt.Type()) {
+		return "", fmt.Errorf("the dataschema %s doesn't match the event type %s", dataSchema, evt.Type())
+	}
+	return schemaPath[idx+1:], nil
+}
+
+// Validate validates the data of the event against the schema of the version claimed by the event, it returns the
+// version and the error if the data doesn't match it. The events of the unregistered types aren't validated and the
+// version is empty.
+func Validate(evt *cloudevents.Event) (string, error) {
+	versions, found := registry.byType[shortType(evt.Type())]
+	if !found {
+		return "", nil
+	}
+	version, err := Version(evt)
+	if err != nil {
+		return "", err
+	}
+	jsonSchema, found := versions[version]
+	if !found {
+		return version, fmt.Errorf("the version %s of the schema of %s is unknown", version, shortType(evt.Type()))
+	}
+	result, err := jsonSchema.Validate(gojsonschema.NewBytesLoader(evt.Data()))
+	if err != nil {
+		return version, fmt.Errorf("failed to validate the data of %s: %w", shortType(evt.Type()), err)
+	}
+	if !result.Valid() {
+		errs := []string{}
+		for _, resultErr := range result.Errors() {
+			errs = append(errs, resultErr.String())
+		}
+		return version, fmt.Errorf("the data of %s doesn't match the schema %s: %s", shortType(evt.Type()), version,
+			strings.Join(errs, "; "))
+	}
+	return version, nil
+}
@@ -0,0 +1,81 @@
+package schema_test
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(t *testing.T, eventType enum.EventType, data interface{}) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetType(string(eventType))
+	evt.SetSource("hub1")
+	assert.Nil(t, evt.SetData(cloudevents.ApplicationJSON, data))
+	return &evt
+}
+
+func TestValidate(t *testing.T) {
+	assert.Equal(t, "v1", schema.LatestVersion(string(enum.ManagedClusterType)))
+	assert.Equal(t, schema.DataSchemaPrefix+"managedcluster/v1", schema.DataSchema(string(enum.ManagedClusterType)))
+	assert.Empty(t, schema.DataSchema("unregistered"))
+
+	// the event without the dataschema is validated with the default version
+	evt := newEvent(t, enum.ManagedClusterType, []clusterv1.ManagedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+	})
+	version, err := schema.Validate(evt)
+	assert.Nil(t, err)
+	assert.Equal(t, schema.DefaultVersion, version)
+
+	evt = newEvent(t, enum.ManagedClusterDeltaType, generic.DeltaBundle[clusterv1.ManagedCluster]{
+		Deleted: []generic.ObjectIdentity{{Name: "cluster2"}},
+	})
+	evt.SetDataSchema(schema.DataSchema(string(enum.ManagedClusterDeltaType)))
+	_, err = schema.Validate(evt)
+	assert.Nil(t, err)
+
+	evt = newEvent(t, enum.ComplianceType, grc.ComplianceBundle{
+		{PolicyID: "1234", CompliantClusters: []string{"cluster1"}},
+	})
+	_, err = schema.Validate(evt)
+	assert.Nil(t, err)
+
+	// the heartbeat of the previous agents is an empty list
+	_, err = schema.Validate(newEvent(t, enum.HubClusterHeartbeatType, []string{}))
+	assert.Nil(t, err)
+
+	// the required field is missing
+	_, err = schema.Validate(newEvent(t, enum.ComplianceType, []map[string]interface{}{
+		{"compliantClusters": []string{"cluster1"}},
+	}))
+	assert.ErrorContains(t, err, "policyId")
+
+	// the payload is another type
+	_, err = schema.Validate(newEvent(t, enum.ManagedClusterType, map[string]string{"name": "cluster1"}))
+	assert.ErrorContains(t, err, "doesn't match the schema v1")
+
+	// the version is unknown
+	evt = newEvent(t, enum.ManagedClusterType, []clusterv1.ManagedCluster{})
+	evt.SetDataSchema(schema.DataSchemaPrefix + "managedcluster/v100")
+	version, err = schema.Validate(evt)
+	assert.ErrorContains(t, err, "unknown")
+	assert.Equal(t, "v100", version)
+
+	// the dataschema is of the other type
+	evt.SetDataSchema(schema.DataSchema(string(enum.ComplianceType)))
+	_, err = schema.Validate(evt)
+	assert.ErrorContains(t, err, "doesn't match the event type")
+
+	// the unregistered type isn't validated
+	version, err = schema.Validate(newEvent(t, "unregistered", "data"))
+	assert.Nil(t, err)
+	assert.Empty(t, version)
+}
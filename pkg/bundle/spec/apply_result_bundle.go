@@ -0,0 +1,26 @@
+package spec
+
+import "time"
+
+// the results of applying the global resources of the spec bundles on the managed hub
+const (
+	ApplyResultApplied    = "applied"
+	ApplyResultFailed     = "failed"
+	ApplyResultConflicted = "conflicted"
+)
+
+// ApplyResult is the result of applying a global resource on the managed hub by the agent
+type ApplyResult struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	// Result is applied, failed or conflicted
+	Result string `json:"result"`
+	// Reason is the error of the failed resource, or the field managers of the conflicted one
+	Reason    string    `json:"reason,omitempty"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+// ApplyResultBundle is the full list of the apply results of the global resources on the managed hub
+type ApplyResultBundle []ApplyResult
@@ -0,0 +1,66 @@
+package spec
+
+import "github.com/stolostron/multicluster-global-hub/pkg/enum"
+
+// the data collection profiles decide which status bundles are collected by the agents
+const (
+	// DataCollectionMinimal only collects the managed clusters and the policy compliance
+	DataCollectionMinimal = "Minimal"
+	// DataCollectionStandard collects everything but the raw events of the policies
+	DataCollectionStandard = "Standard"
+	// DataCollectionFull collects all the bundles, it's the default profile
+	DataCollectionFull = "Full"
+)
+
+// the bundles are always collected by any profile, the manager can't tell the health of the hub or run the operations
+// on it without them
+var alwaysCollectedTypes = map[string]bool{
+	string(enum.HubClusterHeartbeatType):     true,
+	string(enum.HubClusterInfoType):          true,
+	string(enum.ManagedClusterMigrationType): true,
+	string(enum.SpecApplyResultType):         true,
+}
+
+var minimalCollectedTypes = map[string]bool{
+	string(enum.ManagedClusterType):          true,
+	string(enum.ManagedClusterDeltaType):     true,
+	string(enum.LocalPolicySpecType):         true,
+	string(enum.LocalPolicySpecDeltaType):    true,
+	string(enum.LocalComplianceType):         true,
+	string(enum.LocalCompleteComplianceType): true,
+	string(enum.ComplianceType):              true,
+	string(enum.CompleteComplianceType):      true,
+	string(enum.DeltaComplianceType):         true,
+	string(enum.MiniComplianceType):          true,
+}
+
+var standardExcludedTypes = map[string]bool{
+	string(enum.LocalReplicatedPolicyEventType): true,
+	string(enum.LocalRootPolicyEventType):       true,
+}
+
+// Manager to Agent: DataCollectionBundle is the profile deciding which status bundles are collected by the agents
+type DataCollectionBundle struct {
+	Profile string `json:"profile"`
+}
+
+// IsValidDataCollectionProfile returns true if the profile is one of Minimal, Standard and Full
+func IsValidDataCollectionProfile(profile string) bool {
+	return profile == DataCollectionMinimal || profile == DataCollectionStandard || profile == DataCollectionFull
+}
+
+// Collects returns true if the bundle of the event type is collected by the profile, the unknown profile collects
+// all the bundles as the Full one
+func Collects(profile string, eventType string) bool {
+	if alwaysCollectedTypes[eventType] {
+		return true
+	}
+	switch profile {
+	case DataCollectionMinimal:
+		return minimalCollectedTypes[eventType]
+	case DataCollectionStandard:
+		return !standardExcludedTypes[eventType]
+	default:
+		return true
+	}
+}
@@ -0,0 +1,35 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestCollects(t *testing.T) {
+	assert.True(t, IsValidDataCollectionProfile(DataCollectionMinimal))
+	assert.False(t, IsValidDataCollectionProfile("minimal"))
+
+	cases := []struct {
+		profile   string
+		eventType enum.EventType
+		collected bool
+	}{
+		{DataCollectionMinimal, enum.HubClusterHeartbeatType, true},
+		{DataCollectionMinimal, enum.ManagedClusterType, true},
+		{DataCollectionMinimal, enum.CompleteComplianceType, true},
+		{DataCollectionMinimal, enum.PlacementDecisionType, false},
+		{DataCollectionMinimal, enum.HubResourceCountsType, false},
+		{DataCollectionMinimal, enum.LocalRootPolicyEventType, false},
+		{DataCollectionStandard, enum.PlacementDecisionType, true},
+		{DataCollectionStandard, enum.LocalRootPolicyEventType, false},
+		{DataCollectionStandard, enum.LocalReplicatedPolicyEventType, false},
+		{DataCollectionFull, enum.LocalReplicatedPolicyEventType, true},
+		{"", enum.LocalReplicatedPolicyEventType, true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.collected, Collects(c.profile, string(c.eventType)), "%s: %s", c.profile, c.eventType)
+	}
+}
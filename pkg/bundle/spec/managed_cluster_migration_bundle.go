@@ -0,0 +1,45 @@
+package spec
+
+import "time"
+
+// the phases of migrating a managed cluster from the source hub to the target hub
+const (
+	MigrationPending = "Pending"
+	// MigrationRegistering registers the cluster on the target hub, and gets the bootstrap kubeconfig of it
+	MigrationRegistering = "Registering"
+	// MigrationDeploying deploys the bootstrap kubeconfig of the target hub to the cluster from the source hub
+	MigrationDeploying = "Deploying"
+	// MigrationJoining waits for the cluster to join the target hub
+	MigrationJoining = "Joining"
+	// MigrationCleaning removes the cluster from the source hub
+	MigrationCleaning  = "Cleaning"
+	MigrationCompleted = "Completed"
+	MigrationFailed    = "Failed"
+)
+
+// Manager to Agent: ManagedClusterMigrationSpec runs a phase of the migration on the source or the target hub
+type ManagedClusterMigrationSpec struct {
+	MigrationID int64  `json:"migrationId"`
+	Phase       string `json:"phase"`
+	ClusterName string `json:"clusterName"`
+	FromHub     string `json:"fromHub"`
+	ToHub       string `json:"toHub"`
+	// BootstrapKubeconfig is the kubeconfig of the target hub for the klusterlet, it's only set in the Deploying phase
+	BootstrapKubeconfig []byte `json:"bootstrapKubeconfig,omitempty"`
+}
+
+// Agent to Manager: ManagedClusterMigrationStatus is the result of running the phase of the migration on the hub
+type ManagedClusterMigrationStatus struct {
+	MigrationID int64  `json:"migrationId"`
+	Phase       string `json:"phase"`
+	ClusterName string `json:"clusterName"`
+	// Completed is true once the phase is done, otherwise it's still in progress or failed with the message
+	Completed bool   `json:"completed"`
+	Message   string `json:"message,omitempty"`
+	// BootstrapKubeconfig is the kubeconfig of the target hub for the klusterlet, it's only set in the Registering phase
+	BootstrapKubeconfig []byte    `json:"bootstrapKubeconfig,omitempty"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// ManagedClusterMigrationBundle is the list of the migration statuses recently updated on the managed hub
+type ManagedClusterMigrationBundle []ManagedClusterMigrationStatus
@@ -10,6 +10,8 @@ const (
 	ManagerDeploymentName = "multicluster-global-hub-manager"
 	// AgentDeploymentName define the global hub agent deployment name
 	AgentDeploymentName = "multicluster-global-hub-agent"
+	// GHManagedClusterAddonName is the addon of the managed hub, the hub is detached from the global hub without it
+	GHManagedClusterAddonName = "multicluster-global-hub-controller"
 
 	// GHAgentConfigCMName is the name of configmap that stores important global hub settings
 	// eg. aggregationLevel and enableLocalPolicy.
@@ -59,8 +61,21 @@ const (
 	GHStorageSecretName        = "multicluster-global-hub-storage"   // #nosec G101
 	GHBuiltInStorageSecretName = "multicluster-global-hub-postgres"  // #nosec G101
 	KafkaCertSecretName        = "kafka-certs-secret"                // #nosec G101
+	GHArchiveSecretName        = "multicluster-global-hub-archive"   // #nosec G101
 	GHDefaultStorageRetention  = "18m"                               // 18 months
 	PostgresCAConfigMap        = "multicluster-global-hub-postgres-ca"
+
+	// the signing keys of the managed hubs, the private keys are only read by the operator, and the public keys are
+	// mounted to the manager to verify the status events
+	GHSigningKeysSecretName       = "multicluster-global-hub-signing-keys"        // #nosec G101
+	GHSigningPublicKeysSecretName = "multicluster-global-hub-signing-public-keys" // #nosec G101
+
+	// the inventory API which the clusters and policies are exported to
+	GHInventorySecretName = "multicluster-global-hub-inventory" // #nosec G101
+
+	// the sinks and rules of the compliance notifications, the "notification.yaml" of it is mounted to the manager
+	GHNotificationConfigMapName = "multicluster-global-hub-notification"
+	GHNotificationConfigKey     = "notification.yaml"
 )
 
 // global hub console secret/configmap names
@@ -88,6 +103,51 @@ const (
 	ManagedClusterManagedByAnnotation = "global-hub.open-cluster-management.io/managed-by"
 	// identify the resource is from the global hub cluster
 	OriginOwnerReferenceAnnotation = "global-hub.open-cluster-management.io/origin-ownerreference-uid"
+
+	// the comma separated managed hub names that the global resource should be propagated to
+	HubPlacementTargetHubsAnnotation = "global-hub.open-cluster-management.io/target-hubs"
+	// the comma separated cluster groups, the global resource is propagated to the managed hubs of their members
+	HubPlacementTargetClusterGroupsAnnotation = "global-hub.open-cluster-management.io/target-cluster-groups"
+	// the number of managed hubs that the global resource should be propagated to, the hubs with more
+	// managed clusters are preferred
+	HubPlacementNumberOfHubsAnnotation = "global-hub.open-cluster-management.io/number-of-hubs"
+	// the number of clusters selected by the global placement on each of the target managed hubs
+	HubPlacementClustersPerHubAnnotation = "global-hub.open-cluster-management.io/clusters-per-hub"
+	// the JSON object of the parameters on each managed hub, e.g. {"*": {"channel": "stable"}, "hub1": {"channel": "dev"}},
+	// the "{{hub.<parameter>}}" placeholders in the global resource are replaced with the values of the target hub
+	HubParametersAnnotation = "global-hub.open-cluster-management.io/hub-parameters"
+
+	// the waves of the managed hubs that the global policy is rolled out to in order, the waves are separated by ";" and
+	// the hubs of each wave are separated by ",", e.g. "canary-hub;hub1,hub2". the hubs out of the waves are the last wave
+	RolloutWavesAnnotation = "global-hub.open-cluster-management.io/rollout-waves"
+	// the duration that each wave is soaked before the next wave is rolled out, e.g. "30m", the default is 10m
+	RolloutSoakTimeAnnotation = "global-hub.open-cluster-management.io/rollout-soak-time"
+	// the rollout is halted once the non compliant clusters on the rolled out hubs increase by more than the value
+	// during the soak time, the default is 0
+	RolloutMaxNonCompliantAnnotation = "global-hub.open-cluster-management.io/rollout-max-noncompliant"
+	// the rollout state of the global policy, it's maintained by the manager
+	RolloutStateAnnotation = "global-hub.open-cluster-management.io/rollout-state"
+
+	// the policy applied by the agent when the global resource is modified on the managed hub, the value is one of
+	// "overwrite"(default), "ignore" and "report"
+	ConflictPolicyAnnotation = "global-hub.open-cluster-management.io/conflict-policy"
+
+	// the source hub of the managed cluster which is registered on the target hub by the migration
+	ManagedClusterMigratedFromAnnotation = "global-hub.open-cluster-management.io/migrated-from"
+
+	// the time since the status events of the managed hub are throttled by the manager, it's added to the managed
+	// cluster of the hub on the global hub
+	ManagedHubThrottledAnnotation = "global-hub.open-cluster-management.io/ingestion-throttled-since"
+)
+
+// the values of the ConflictPolicyAnnotation
+const (
+	// overwrite the local modification with the global resource
+	ConflictPolicyOverwrite = "overwrite"
+	// keep the local modification, the global resource isn't applied until the modification is reverted
+	ConflictPolicyIgnore = "ignore"
+	// keep the local modification and report the conflict to the global hub
+	ConflictPolicyReport = "report"
 )
 
 // store all the finalizers
@@ -109,6 +169,9 @@ const (
 	VersionClusterClaimName = "version.open-cluster-management.io"
 	// HubClusterClaimName is a claim to record the ACM Hub
 	HubClusterClaimName = "hub.open-cluster-management.io"
+	// GlobalHubClusterClaimName is a claim to mark the global hub cluster, the value is the uid of the
+	// multiclusterglobalhub, it's used to detect the global hub cluster imported as a managed hub
+	GlobalHubClusterClaimName = "global-hub.open-cluster-management.io"
 
 	// the value of the HubClusterClaimName ClusterClaim
 	HubNotInstalled         = "NotInstalled"
@@ -122,9 +185,17 @@ const (
 	StatusBundle = "StatusBundle"
 
 	ResyncMsgKey = "Resync"
+	// ResyncAllEventTypes in the resync message means resync all the supported event types
+	ResyncAllEventTypes = "*"
 
 	// ManagedClustersLabelsMsgKey - managed clusters labels message key.
 	ManagedClustersLabelsMsgKey = "ManagedClustersLabels"
+
+	// ManagedClusterMigrationMsgKey - the message key to run a phase of the managed cluster migration on the hub.
+	ManagedClusterMigrationMsgKey = "ManagedClusterMigration"
+
+	// DataCollectionMsgKey - the message key of the data collection profile deciding which bundles the agents collect.
+	DataCollectionMsgKey = "DataCollection"
 )
 
 // event exporter reference object label keys
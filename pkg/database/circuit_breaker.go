@@ -0,0 +1,310 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrCircuitOpen is returned without accessing the database when the circuit breaker is open
+var ErrCircuitOpen = errors.New("the database circuit breaker is open")
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// RetryConfig is the thresholds of the retry and the circuit breaker of the database access
+type RetryConfig struct {
+	// MaxAttempts is the max attempts of an operation, including the first one
+	MaxAttempts int
+	// InitialBackoff is the interval before the first retry, it's doubled on each retry up to the MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// FailureThreshold is the consecutive transient failures to open the circuit
+	FailureThreshold int
+	// OpenDuration is how long the open circuit fails the operations fast, then an operation is allowed to probe the
+	// database, the circuit is closed if it succeeds, otherwise it's open again
+	OpenDuration time.Duration
+}
+
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:      5,
+	InitialBackoff:   500 * time.Millisecond,
+	MaxBackoff:       10 * time.Second,
+	FailureThreshold: 10,
+	OpenDuration:     30 * time.Second,
+}
+
+var defaultCircuitBreaker = NewCircuitBreaker(DefaultRetryConfig)
+
+// SetRetryConfig replaces the thresholds of the circuit breaker shared by the database access
+func SetRetryConfig(config RetryConfig) {
+	defaultCircuitBreaker.SetConfig(config)
+}
+
+// GetCircuitBreaker returns the circuit breaker shared by the database access, e.g. the status workers, the jobs and
+// the hub management, so an outage found by any of them fails the others fast
+func GetCircuitBreaker() *CircuitBreaker {
+	return defaultCircuitBreaker
+}
+
+// Retry runs the database operation with the shared circuit breaker, see CircuitBreaker.Do
+func Retry(ctx context.Context, operation func(ctx context.Context) error) error {
+	return defaultCircuitBreaker.Do(ctx, operation)
+}
+
+// GetCircuitState returns the state of the shared circuit breaker
+func GetCircuitState() CircuitState {
+	return defaultCircuitBreaker.State()
+}
+
+// CircuitBreaker retries the transient failures of the database operations, e.g. during the postgres failover, with
+// the bounded attempts and backoff. When the transient failures keep happening, the circuit is open and the
+// operations fail fast with ErrCircuitOpen instead of waiting for the connection timeout one by one.
+type CircuitBreaker struct {
+	config   RetryConfig
+	mutex    sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+	// it's closed when the circuit is closed, and replaced with a new one when the circuit is open
+	closedChan    chan struct{}
+	onStateChange func(state CircuitState)
+}
+
+func NewCircuitBreaker(config RetryConfig) *CircuitBreaker {
+	closedChan := make(chan struct{})
+	close(closedChan)
+	return &CircuitBreaker{
+		config:     normalizeRetryConfig(config),
+		state:      CircuitClosed,
+		now:        time.Now,
+		closedChan: closedChan,
+	}
+}
+
+func normalizeRetryConfig(config RetryConfig) RetryConfig {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+	return config
+}
+
+func (b *CircuitBreaker) SetConfig(config RetryConfig) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.config = normalizeRetryConfig(config)
+}
+
+// OnStateChange registers the handler invoked with the new state once the state is changed, it's invoked with the
+// lock held, so it mustn't call back into the circuit breaker
+func (b *CircuitBreaker) OnStateChange(handler func(state CircuitState)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.onStateChange = handler
+}
+
+// Do runs the operation until it succeeds, it fails with a non transient error, or the attempts are exhausted. The
+// non transient errors, e.g. the constraint violation, are returned right away and don't count against the circuit.
+func (b *CircuitBreaker) Do(ctx context.Context, operation func(ctx context.Context) error) error {
+	b.mutex.Lock()
+	config := b.config
+	b.mutex.Unlock()
+
+	backoff := config.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= config.MaxAttempts; attempt++ {
+		if !b.allow() {
+			return ErrCircuitOpen
+		}
+		err = operation(ctx)
+		if err == nil {
+			b.onSuccess()
+			return nil
+		}
+		if ctx.Err() != nil {
+			b.onCanceled()
+			return err
+		}
+		if !IsTransientError(err) {
+			// the database is reachable even though the operation is failed
+			b.onSuccess()
+			return err
+		}
+		b.onFailure(err)
+		if attempt == config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > config.MaxBackoff {
+			backoff = config.MaxBackoff
+		}
+	}
+	return err
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+// Available returns true only if the circuit is closed, the database isn't available to the other operations while
+// an operation is probing it with the half-open circuit
+func (b *CircuitBreaker) Available() bool {
+	return b.State() == CircuitClosed
+}
+
+// WaitAvailable blocks until the circuit is closed, it returns the error only if the context is done
+func (b *CircuitBreaker) WaitAvailable(ctx context.Context) error {
+	b.mutex.Lock()
+	closedChan := b.closedChan
+	b.mutex.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-closedChan:
+		return nil
+	}
+}
+
+// Trip opens the circuit right away, e.g. the database monitor finds out the database is unreachable by the ping,
+// rather than waiting for the failures to reach the threshold
+func (b *CircuitBreaker) Trip(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state != CircuitOpen {
+		log.Error(err, "the database is unavailable, open the circuit breaker", "duration", b.config.OpenDuration)
+	}
+	b.openedAt = b.now()
+	b.setState(CircuitOpen)
+}
+
+// Reset closes the circuit once the database is reachable again, e.g. by the ping of the database monitor
+func (b *CircuitBreaker) Reset() {
+	b.onSuccess()
+}
+
+// setState updates the state with the lock held, and notifies the waiters once the circuit is closed
+func (b *CircuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	if state == CircuitClosed {
+		close(b.closedChan)
+	} else if b.state == CircuitClosed {
+		b.closedChan = make(chan struct{})
+	}
+	b.state = state
+	if b.onStateChange != nil {
+		b.onStateChange(state)
+	}
+}
+
+// allow returns false if the circuit is open, only an operation is allowed to probe the database once the open
+// duration is elapsed
+func (b *CircuitBreaker) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.config.OpenDuration {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+		log.Info("probe the database with the half-open circuit breaker")
+		return true
+	case CircuitHalfOpen:
+		// the probing operation is still running
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) onSuccess() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state != CircuitClosed {
+		log.Info("the database is available, close the circuit breaker")
+	}
+	b.setState(CircuitClosed)
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) onFailure(err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		if b.state != CircuitOpen {
+			log.Error(err, "the database is unavailable, open the circuit breaker", "failures", b.failures,
+				"duration", b.config.OpenDuration)
+		}
+		b.openedAt = b.now()
+		b.setState(CircuitOpen)
+	}
+}
+
+// onCanceled opens the circuit again if the probing operation is canceled, so another operation is able to probe it
+func (b *CircuitBreaker) onCanceled() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.setState(CircuitOpen)
+	}
+}
+
+// IsTransientError returns true if the error is likely to be resolved by retrying, e.g. the connection is refused or
+// reset during the failover, the server is shutting down or in recovery, or the transaction is aborted by the
+// serialization failure or deadlock
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// both the errors of lib/pq and pgx provide the sql state
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		switch {
+		case strings.HasPrefix(state, "08"): // connection exception
+			return true
+		case state == "57P01", state == "57P02", state == "57P03": // shutdown or cannot connect now
+			return true
+		case state == "40001", state == "40P01": // serialization failure or deadlock
+			return true
+		case state == "25006": // read only transaction, the primary is failed over to the replica
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIsTransientError(t *testing.T) {
+	cases := []struct {
+		err       error
+		transient bool
+	}{
+		{nil, false},
+		{errors.New("syntax error"), false},
+		{fmt.Errorf("failed to connect: %w", syscall.ECONNREFUSED), true},
+		{&pq.Error{Code: "08006"}, true},
+		{&pq.Error{Code: "57P01"}, true},
+		{&pq.Error{Code: "25006"}, true},
+		{fmt.Errorf("failed to upsert: %w", &pq.Error{Code: "23505"}), false},
+	}
+	for _, c := range cases {
+		if transient := IsTransientError(c.err); transient != c.transient {
+			t.Errorf("expected transient %v for the error %v, got %v", c.transient, c.err, transient)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	breaker := NewCircuitBreaker(RetryConfig{
+		MaxAttempts:      3,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       time.Millisecond,
+		FailureThreshold: 4,
+		OpenDuration:     time.Minute,
+	})
+	breaker.now = func() time.Time { return now }
+
+	attempts := 0
+	transientFailure := func(ctx context.Context) error {
+		attempts++
+		return &pq.Error{Code: "57P03"}
+	}
+
+	// the transient failure is retried with the bounded attempts
+	if err := breaker.Do(ctx, transientFailure); !IsTransientError(err) {
+		t.Fatalf("expected the transient error, got %v", err)
+	}
+	if attempts != 3 || breaker.State() != CircuitClosed {
+		t.Fatalf("expected 3 attempts with the closed circuit, got %d attempts with the %s circuit", attempts,
+			breaker.State())
+	}
+
+	// the non transient failure isn't retried, and it resets the failures
+	attempts = 0
+	err := breaker.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		return &pq.Error{Code: "23505"}
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected the failure without retrying, got %d attempts with the error %v", attempts, err)
+	}
+
+	// the circuit is open once the consecutive transient failures reach the threshold
+	_ = breaker.Do(ctx, transientFailure)
+	_ = breaker.Do(ctx, transientFailure)
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("expected the open circuit, got %s", breaker.State())
+	}
+	attempts = 0
+	if err := breaker.Do(ctx, transientFailure); !errors.Is(err, ErrCircuitOpen) || attempts != 0 {
+		t.Fatalf("expected to fail fast with the open circuit, got %d attempts with the error %v", attempts, err)
+	}
+
+	// the failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	if err := breaker.Do(ctx, transientFailure); !errors.Is(err, ErrCircuitOpen) || attempts != 1 {
+		t.Fatalf("expected the failed probe, got %d attempts with the error %v", attempts, err)
+	}
+
+	// the successful probe closes the circuit
+	now = now.Add(time.Minute)
+	if err := breaker.Do(ctx, func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("expected the successful probe, got %v", err)
+	}
+	if breaker.State() != CircuitClosed {
+		t.Fatalf("expected the closed circuit, got %s", breaker.State())
+	}
+}
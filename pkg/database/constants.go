@@ -29,6 +29,31 @@ const (
 	// SubscriptionReportsTableName table name of subscription-reports.
 	SubscriptionReportsTableName = "subscription_reports"
 
+	// ArgoApplicationsTableName table name of argocd applications.
+	ArgoApplicationsTableName = "argocd_applications"
+	// ArgoApplicationSetsTableName table name of argocd applicationsets.
+	ArgoApplicationSetsTableName = "argocd_applicationsets"
+	// ArgoApplicationStatusViewName view name of the sync and health status of the argocd applications.
+	ArgoApplicationStatusViewName = "argocd_application_status"
+
+	// ManagedClusterAddonsTableName table name of the health of the addons on the managed clusters.
+	ManagedClusterAddonsTableName = "managed_cluster_addons"
+	// ManagedServiceAccountsTableName table name of the credentials of the managed service accounts.
+	ManagedServiceAccountsTableName = "managed_service_accounts"
+
+	// GatekeeperConstraintsTableName table name of the audit results of the gatekeeper constraints.
+	GatekeeperConstraintsTableName = "gatekeeper_constraints"
+	// GatekeeperViolationsTableName table name of the resources violating the gatekeeper constraints.
+	GatekeeperViolationsTableName = "gatekeeper_violations"
+
+	// ComplianceScansTableName table name of the results of the compliance operator scans.
+	ComplianceScansTableName = "compliance_scans"
+	// ImageVulnerabilitiesTableName table name of the vulnerabilities of the images by the severity.
+	ImageVulnerabilitiesTableName = "image_vulnerabilities"
+
+	// PolicyTemplateDetailsTableName table name of the latest results of the policy templates.
+	PolicyTemplateDetailsTableName = "policy_template_details"
+
 	// PlacementRulesTableName table name of placement-rules.
 	PlacementRulesTableName = "placementrules"
 	// PlacementsTableName table name of placements.
@@ -36,6 +61,20 @@ const (
 	// PlacementDecisionsTableName table name of placement-decisions.
 	PlacementDecisionsTableName = "placementdecisions"
 
+	// ManagedClusterSetsTableName table name of managed-cluster-sets.
+	ManagedClusterSetsTableName = "managed_cluster_sets"
+	// ManagedClusterSetBindingsTableName table name of managed-cluster-set-bindings.
+	ManagedClusterSetBindingsTableName = "managed_cluster_set_bindings"
+	// ManagedClusterSetMembersViewName view name of the managed-cluster-set members.
+	ManagedClusterSetMembersViewName = "managed_cluster_set_members"
+
+	// ResourceConflictsTableName table name of the global resources modified on the managed hubs.
+	ResourceConflictsTableName = "resource_conflicts"
+	// SpecApplyResultsTableName table name of the results of applying the global resources on the managed hubs.
+	SpecApplyResultsTableName = "spec_apply_results"
+	// SpecDriftsTableName table name of the global resources on the managed hubs which drift from the spec.
+	SpecDriftsTableName = "spec_drifts"
+
 	// LeafHubHeartbeatsTableName table name for LH heartbeats.
 	LeafHubHeartbeatsTableName = "leaf_hub_heartbeats"
 
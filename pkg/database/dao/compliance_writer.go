@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// ComplianceWriter writes the compliance of the status bundles with pgx, it's the hot path of the status ingestion.
+// The rows are passed as the array parameters of the statements, so the statements of each table are the same
+// regardless of the number of the rows, they're prepared once and cached on the connections of the pool, and the
+// rows don't go through the reflection of gorm.
+type ComplianceWriter struct {
+	pool      *pgxpool.Pool
+	table     string
+	upsertSQL string
+	updateSQL string
+	deleteSQL string
+}
+
+// NewComplianceWriter creates the writer for the compliance table, e.g. "status.compliance" with the compliance type
+// "status.compliance_type", or "local_status.compliance" with "local_status.compliance_type"
+func NewComplianceWriter(pool *pgxpool.Pool, table, complianceType string) *ComplianceWriter {
+	return &ComplianceWriter{
+		pool:  pool,
+		table: table,
+		upsertSQL: fmt.Sprintf(`
+			INSERT INTO %s (policy_id, cluster_name, leaf_hub_name, error, compliance)
+			SELECT v.policy_id::uuid, v.cluster_name, v.leaf_hub_name, v.error::status.error_type, v.compliance::%s
+			FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[])
+				AS v(policy_id, cluster_name, leaf_hub_name, error, compliance)
+			ON CONFLICT (policy_id, cluster_name, leaf_hub_name)
+			DO UPDATE SET error = EXCLUDED.error, compliance = EXCLUDED.compliance`, table, complianceType),
+		updateSQL: fmt.Sprintf(`
+			UPDATE %s AS c SET compliance = v.compliance::%s, error = v.error::status.error_type
+			FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[])
+				AS v(policy_id, cluster_name, leaf_hub_name, error, compliance)
+			WHERE c.policy_id = v.policy_id::uuid AND c.leaf_hub_name = v.leaf_hub_name
+			AND c.cluster_name = v.cluster_name`, table, complianceType),
+		deleteSQL: fmt.Sprintf(`
+			DELETE FROM %s WHERE leaf_hub_name = $1 AND policy_id = $2::text::uuid
+			AND cluster_name = ANY($3::text[])`, table),
+	}
+}
+
+// Upsert inserts the compliance, or updates it if the row already exists
+func (w *ComplianceWriter) Upsert(ctx context.Context, compliances []models.StatusCompliance) error {
+	if len(compliances) == 0 {
+		return nil
+	}
+	return w.exec(ctx, "upsert", w.upsertSQL, complianceColumns(compliances)...)
+}
+
+// Update updates the compliance of the existing rows, the rows which don't exist in the table are skipped
+func (w *ComplianceWriter) Update(ctx context.Context, compliances []models.StatusCompliance) error {
+	if len(compliances) == 0 {
+		return nil
+	}
+	return w.exec(ctx, "update", w.updateSQL, complianceColumns(compliances)...)
+}
+
+// DeleteClusters deletes the compliance of the clusters for the policy
+func (w *ComplianceWriter) DeleteClusters(ctx context.Context, leafHubName, policyID string,
+	clusterNames []string,
+) error {
+	if len(clusterNames) == 0 {
+		return nil
+	}
+	return w.exec(ctx, "delete", w.deleteSQL, leafHubName, policyID, clusterNames)
+}
+
+// exec runs the statement and observes it as the query "<operation>_<table>", e.g. "upsert_status.compliance"
+func (w *ComplianceWriter) exec(ctx context.Context, operation, statement string, args ...interface{}) error {
+	startAt := time.Now()
+	_, err := w.pool.Exec(ctx, statement, args...)
+	database.ObserveQuery(operation+"_"+w.table, statement, len(args), startAt, err)
+	return err
+}
+
+// complianceColumns converts the rows into the arrays of the columns:
+// policy_id, cluster_name, leaf_hub_name, error, compliance
+func complianceColumns(compliances []models.StatusCompliance) []interface{} {
+	policyIDs := make([]string, len(compliances))
+	clusterNames := make([]string, len(compliances))
+	leafHubNames := make([]string, len(compliances))
+	errors := make([]string, len(compliances))
+	statuses := make([]string, len(compliances))
+	for i, compliance := range compliances {
+		policyIDs[i] = compliance.PolicyID
+		clusterNames[i] = compliance.ClusterName
+		leafHubNames[i] = compliance.LeafHubName
+		errors[i] = compliance.Error
+		statuses[i] = string(compliance.Compliance)
+	}
+	return []interface{}{policyIDs, clusterNames, leafHubNames, errors, statuses}
+}
@@ -0,0 +1,141 @@
+package dao_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/dao"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/test/pkg/testpostgres"
+)
+
+func TestMain(m *testing.M) {
+	testPostgres, err := testpostgres.NewTestPostgres()
+	if err != nil {
+		panic(err)
+	}
+	if err = testpostgres.InitDatabase(testPostgres.URI); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+
+	database.ClosePgxPool()
+	database.CloseGorm(database.GetSqlDb())
+	if err = testPostgres.Stop(); err != nil {
+		panic(err)
+	}
+	os.Exit(code)
+}
+
+func newCompliances(leafHubName string, policies, clusters int) []models.StatusCompliance {
+	compliances := make([]models.StatusCompliance, 0, policies*clusters)
+	for i := 0; i < policies; i++ {
+		policyID := uuid.New().String()
+		for j := 0; j < clusters; j++ {
+			compliances = append(compliances, models.StatusCompliance{
+				PolicyID:    policyID,
+				ClusterName: fmt.Sprintf("cluster-%d", j),
+				LeafHubName: leafHubName,
+				Error:       database.ErrorNone,
+				Compliance:  database.NonCompliant,
+			})
+		}
+	}
+	return compliances
+}
+
+func TestComplianceWriter(t *testing.T) {
+	ctx := context.Background()
+	leafHubName := "hub-compliance-writer"
+	writer := dao.NewComplianceWriter(database.GetPgxPool(), models.LocalStatusCompliance{}.TableName(),
+		"local_status.compliance_type")
+	compliances := newCompliances(leafHubName, 2, 3)
+
+	countRows := func(compliance database.ComplianceStatus) int64 {
+		var count int64
+		err := database.GetGorm().Model(&models.LocalStatusCompliance{}).
+			Where("leaf_hub_name = ? AND compliance = ?", leafHubName, compliance).Count(&count).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	// insert
+	if err := writer.Upsert(ctx, compliances); err != nil {
+		t.Fatalf("failed to upsert the compliances: %v", err)
+	}
+	if count := countRows(database.NonCompliant); count != 6 {
+		t.Errorf("expected 6 non compliant rows, got %d", count)
+	}
+
+	// upsert the existing rows
+	compliances[0].Compliance = database.Compliant
+	if err := writer.Upsert(ctx, compliances[:1]); err != nil {
+		t.Fatalf("failed to upsert the compliances: %v", err)
+	}
+	if count := countRows(database.Compliant); count != 1 {
+		t.Errorf("expected 1 compliant row, got %d", count)
+	}
+
+	// update only touches the existing rows
+	compliances[1].Compliance = database.Compliant
+	notExisting := newCompliances(leafHubName, 1, 1)
+	if err := writer.Update(ctx, append(notExisting, compliances[1])); err != nil {
+		t.Fatalf("failed to update the compliances: %v", err)
+	}
+	if count := countRows(database.Compliant); count != 2 {
+		t.Errorf("expected 2 compliant rows, got %d", count)
+	}
+	if count := countRows(database.NonCompliant); count != 4 {
+		t.Errorf("expected 4 non compliant rows, got %d", count)
+	}
+
+	// delete the clusters of the first policy
+	err := writer.DeleteClusters(ctx, leafHubName, compliances[0].PolicyID, []string{"cluster-0", "cluster-1"})
+	if err != nil {
+		t.Fatalf("failed to delete the compliances: %v", err)
+	}
+	if count := countRows(database.Compliant) + countRows(database.NonCompliant); count != 4 {
+		t.Errorf("expected 4 rows, got %d", count)
+	}
+}
+
+// go test ./pkg/database/dao -run none -bench BenchmarkComplianceUpsert -benchmem
+func BenchmarkComplianceUpsert(b *testing.B) {
+	ctx := context.Background()
+	compliances := newCompliances("hub-compliance-benchmark", 10, 1000)
+
+	b.Run("gorm", func(b *testing.B) {
+		localCompliances := make([]models.LocalStatusCompliance, 0, len(compliances))
+		for _, compliance := range compliances {
+			localCompliances = append(localCompliances, models.LocalStatusCompliance(compliance))
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			err := database.GetGorm().Clauses(clause.OnConflict{UpdateAll: true}).
+				CreateInBatches(localCompliances, 1000).Error
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pgx", func(b *testing.B) {
+		writer := dao.NewComplianceWriter(database.GetPgxPool(), models.LocalStatusCompliance{}.TableName(),
+			"local_status.compliance_type")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := writer.Upsert(ctx, compliances); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
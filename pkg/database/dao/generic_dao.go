@@ -3,7 +3,9 @@ package dao
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -72,3 +74,52 @@ func (dao *GenericDao) Delete(hubName, id string) error {
 		`DELETE FROM %s WHERE leaf_hub_name = $1 AND id = $2::uuid`, dao.table)
 	return dao.tx.Exec(sqlTemplate, hubName, id).Error
 }
+
+// BatchInsert inserts the objects(id to object) of the hub with the multi-row statements, the payload of the object
+// which already exists, e.g. it's inserted by the previous bundle whose transaction isn't visible yet, is updated
+func (dao *GenericDao) BatchInsert(hubName string, objs map[string]interface{}, batchSize int) error {
+	values := make([]string, 0, batchSize)
+	args := make([]interface{}, 0, batchSize*3)
+	flush := func() error {
+		if len(values) == 0 {
+			return nil
+		}
+		sqlTemplate := fmt.Sprintf(`INSERT INTO %s (id, leaf_hub_name, payload) VALUES %s
+			ON CONFLICT (leaf_hub_name, id) DO UPDATE SET payload = EXCLUDED.payload`, dao.table,
+			strings.Join(values, ","))
+		err := dao.tx.Exec(sqlTemplate, args...).Error
+		values, args = values[:0], args[:0]
+		return err
+	}
+
+	for id, obj := range objs {
+		payload, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		values = append(values, "(?::uuid, ?, ?::jsonb)")
+		args = append(args, id, hubName, payload)
+		if len(values) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// BatchDelete deletes the objects of the hub by the ids with a statement per batch, the ids are bound as an uuid array,
+// so the index of the (leaf_hub_name, id) is used to find the rows
+func (dao *GenericDao) BatchDelete(hubName string, ids []string, batchSize int) error {
+	sqlTemplate := fmt.Sprintf(`DELETE FROM %s WHERE leaf_hub_name = ? AND id = ANY(?::uuid[])`, dao.table)
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		if err := dao.tx.Exec(sqlTemplate, hubName, pq.StringArray(ids[start:end])).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
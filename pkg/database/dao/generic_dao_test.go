@@ -0,0 +1,103 @@
+package dao_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/dao"
+)
+
+func newObjects(count int) map[string]interface{} {
+	objs := make(map[string]interface{}, count)
+	for i := 0; i < count; i++ {
+		id := uuid.New().String()
+		objs[id] = &metav1.ObjectMeta{
+			Name:            fmt.Sprintf("placement-%d", i),
+			Namespace:       "default",
+			ResourceVersion: "1",
+		}
+	}
+	return objs
+}
+
+func TestGenericDaoBatch(t *testing.T) {
+	table := fmt.Sprintf("%s.%s", database.StatusSchema, database.PlacementsTableName)
+	genericDao := dao.NewGenericDao(database.GetGorm(), table)
+
+	countRows := func(hubName string) int64 {
+		var count int64
+		if err := database.GetGorm().Table(table).Where("leaf_hub_name = ?", hubName).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		return count
+	}
+
+	// insert the objects with several statements
+	hub1Objs, hub2Objs := newObjects(7), newObjects(2)
+	if err := genericDao.BatchInsert("hub-batch-1", hub1Objs, 3); err != nil {
+		t.Fatalf("failed to insert the objects: %v", err)
+	}
+	if err := genericDao.BatchInsert("hub-batch-2", hub2Objs, 3); err != nil {
+		t.Fatalf("failed to insert the objects: %v", err)
+	}
+	if count := countRows("hub-batch-1"); count != 7 {
+		t.Errorf("expected 7 rows of the hub-batch-1, got %d", count)
+	}
+
+	// the existing objects are updated rather than failing the batch
+	conflicted := map[string]interface{}{}
+	for id, obj := range hub1Objs {
+		meta := obj.(*metav1.ObjectMeta)
+		meta.ResourceVersion = "2"
+		conflicted[id] = meta
+	}
+	if err := genericDao.BatchInsert("hub-batch-1", conflicted, 3); err != nil {
+		t.Fatalf("failed to insert the existing objects: %v", err)
+	}
+	idToVersion, err := genericDao.GetIdToVersionByHub("hub-batch-1")
+	if err != nil {
+		t.Fatalf("failed to get the versions: %v", err)
+	}
+	if len(idToVersion) != 7 {
+		t.Errorf("expected 7 objects of the hub-batch-1, got %d", len(idToVersion))
+	}
+	var updated int64
+	err = database.GetGorm().Table(table).
+		Where("leaf_hub_name = ? AND payload->>'resourceVersion' = ?", "hub-batch-1", "2").Count(&updated).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != 7 {
+		t.Errorf("expected 7 updated objects, got %d", updated)
+	}
+
+	// delete the objects of the hub with several statements, the objects of the other hub are kept
+	ids := make([]string, 0, len(hub1Objs))
+	for id := range hub1Objs {
+		ids = append(ids, id)
+	}
+	for id := range hub2Objs {
+		ids = append(ids, id)
+	}
+	if err := genericDao.BatchDelete("hub-batch-1", ids, 3); err != nil {
+		t.Fatalf("failed to delete the objects: %v", err)
+	}
+	if count := countRows("hub-batch-1"); count != 0 {
+		t.Errorf("expected 0 rows of the hub-batch-1, got %d", count)
+	}
+	if count := countRows("hub-batch-2"); count != 2 {
+		t.Errorf("expected 2 rows of the hub-batch-2, got %d", count)
+	}
+
+	// nothing is deleted without the ids
+	if err := genericDao.BatchDelete("hub-batch-2", nil, 3); err != nil {
+		t.Fatalf("failed to delete the empty ids: %v", err)
+	}
+	if count := countRows("hub-batch-2"); count != 2 {
+		t.Errorf("expected 2 rows of the hub-batch-2, got %d", count)
+	}
+}
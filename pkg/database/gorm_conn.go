@@ -4,18 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"net/url"
 	"sync"
 
-	_ "github.com/lib/pq"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
-
-	"github.com/stolostron/multicluster-global-hub/pkg/constants"
-	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
 const PostgresDialect = "postgres"
@@ -33,6 +27,8 @@ var (
 	log      = ctrl.Log.WithName("database-controller")
 	lockConn *sql.Conn
 	ctx      = context.Background()
+	// the storage of the gorm instance, it's the postgres by default
+	currentStorage Storage = &postgresStorage{}
 )
 
 type DatabaseConfig struct {
@@ -43,49 +39,32 @@ type DatabaseConfig struct {
 }
 
 func InitGormInstance(config *DatabaseConfig) error {
-	var err error
-
-	if config.Dialect != PostgresDialect {
-		return fmt.Errorf("unsupported database dialect: %s", config.Dialect)
+	storage, err := GetStorage(config.Dialect)
+	if err != nil {
+		return err
 	}
 	gormOnce.Do(func() {
-		gormDB, sqlDB, err = NewGormConn(config)
+		gormDB, sqlDB, err = storage.Open(config)
+		if err != nil {
+			return
+		}
+		if err = gormDB.Use(&queryMetricsPlugin{}); err != nil {
+			return
+		}
+		currentStorage = storage
 		fmt.Println("set max connection==============:", config.PoolSize)
 		sqlDB.SetMaxOpenConns(config.PoolSize)
 	})
-	if err != nil {
-		return err
-	}
 	return err
 }
 
+// NewGormConn opens a new connection to the data store by the storage of the dialect
 func NewGormConn(config *DatabaseConfig) (*gorm.DB, *sql.DB, error) {
-	var err error
-	if config.Dialect != PostgresDialect {
-		return nil, nil, fmt.Errorf("unsupported database dialect: %s", config.Dialect)
-	}
-	urlObj, err := completePostgres(config.URL, config.CaCertPath)
+	storage, err := GetStorage(config.Dialect)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	sqlDBConn, err := sql.Open(config.Dialect, urlObj.String())
-	if err != nil {
-		log.Error(err, "failed to open database connection")
-		return nil, nil, err
-	}
-	gormDBconn, err := gorm.Open(postgres.New(postgres.Config{
-		Conn:                 sqlDBConn,
-		PreferSimpleProtocol: true,
-	}), &gorm.Config{
-		PrepareStmt:          false,
-		FullSaveAssociations: false,
-	})
-	if err != nil {
-		log.Error(err, "failed to open gorm connection")
-		return nil, nil, err
-	}
-	return gormDBconn, sqlDBConn, nil
+	return storage.Open(config)
 }
 
 func GetGorm() *gorm.DB {
@@ -128,8 +107,7 @@ func Lock(lockConn *sql.Conn) error {
 	}
 	log.V(2).Info("Add db lock")
 	defer log.V(2).Info("db locked")
-	_, err := lockConn.ExecContext(ctx, "select pg_advisory_lock($1)", constants.LockId)
-	return err
+	return currentStorage.Lock(ctx, lockConn)
 }
 
 func Unlock(lockConn *sql.Conn) {
@@ -146,8 +124,7 @@ func Unlock(lockConn *sql.Conn) {
 		return false
 	},
 		func() error {
-			_, err := lockConn.ExecContext(ctx, "select pg_advisory_unlock($1)", constants.LockId)
-			return err
+			return currentStorage.Unlock(ctx, lockConn)
 		})
 	if err != nil {
 		log.Error(err, "Failed to unlock db")
@@ -163,20 +140,3 @@ func CloseGorm(sqlConn *sql.DB) {
 		}
 	}
 }
-
-func completePostgres(postgresUri string, caCertPath string) (*url.URL, error) {
-	urlObj, err := url.Parse(postgresUri)
-	if err != nil {
-		return nil, err
-	}
-	// only support verify-ca or disable(for test)
-	query := urlObj.Query()
-	_, ok := utils.Validate(caCertPath)
-	if query.Get("sslmode") == "verify-ca" && ok {
-		query.Set("sslrootcert", caCertPath)
-	} else {
-		query.Add("sslmode", "disable")
-	}
-	urlObj.RawQuery = query.Encode()
-	return urlObj, nil
-}
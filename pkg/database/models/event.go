@@ -16,6 +16,13 @@ type BaseLocalPolicyEvent struct {
 	Source      datatypes.JSON `gorm:"column:source;type:jsonb" json:"source"`
 	CreatedAt   time.Time      `gorm:"column:created_at;default:now();not null" json:"createdAt"`
 	Compliance  string         `gorm:"column:compliance" json:"compliance"`
+	// the normalized taxonomy of the event
+	EventType         string `gorm:"column:event_type" json:"eventType"`
+	Severity          string `gorm:"column:severity" json:"severity"`
+	Category          string `gorm:"column:category" json:"category"`
+	InvolvedKind      string `gorm:"column:involved_kind" json:"involvedKind"`
+	InvolvedNamespace string `gorm:"column:involved_namespace" json:"involvedNamespace"`
+	InvolvedName      string `gorm:"column:involved_name" json:"involvedName"`
 }
 
 type LocalClusterPolicyEvent struct {
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 type LocalComplianceJobLog struct {
 	Name     string    `gorm:"column:name"`
@@ -15,3 +19,32 @@ type LocalComplianceJobLog struct {
 func (LocalComplianceJobLog) TableName() string {
 	return "history.local_compliance_job_log"
 }
+
+// ArchivedPartition is the manifest of the partition which is exported to the object storage before it's dropped
+type ArchivedPartition struct {
+	PartitionName string    `gorm:"column:partition_name;primaryKey"`
+	Table         string    `gorm:"column:table_name"`
+	Bucket        string    `gorm:"column:bucket"`
+	ObjectKey     string    `gorm:"column:object_key"`
+	RowCount      int64     `gorm:"column:row_count"`
+	SizeBytes     int64     `gorm:"column:size_bytes"`
+	Checksum      string    `gorm:"column:checksum"`
+	ArchivedAt    time.Time `gorm:"column:archived_at;default:current_timestamp"`
+}
+
+func (ArchivedPartition) TableName() string {
+	return "history.archived_partitions"
+}
+
+// HubMetric is the sample of the key metric queried from the prometheus of the managed hub
+type HubMetric struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name"`
+	MetricName  string         `gorm:"column:metric_name"`
+	Labels      datatypes.JSON `gorm:"column:labels;type:jsonb"`
+	Value       float64        `gorm:"column:value"`
+	SampledAt   time.Time      `gorm:"column:sampled_at"`
+}
+
+func (HubMetric) TableName() string {
+	return "history.hub_metrics"
+}
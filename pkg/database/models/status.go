@@ -61,8 +61,56 @@ func (a AggregatedCompliance) TableName() string {
 	return "status.aggregated_compliance"
 }
 
+type ResourceConflict struct {
+	LeafHubName   string         `gorm:"column:leaf_hub_name;primaryKey"`
+	APIVersion    string         `gorm:"column:api_version;not null"`
+	Kind          string         `gorm:"column:kind;primaryKey"`
+	Namespace     string         `gorm:"column:namespace;primaryKey"`
+	Name          string         `gorm:"column:name;primaryKey"`
+	Policy        string         `gorm:"column:policy;not null"`
+	FieldManagers datatypes.JSON `gorm:"column:field_managers;type:jsonb"`
+	DetectedAt    time.Time      `gorm:"column:detected_at;autoCreateTime:false"`
+}
+
+func (ResourceConflict) TableName() string {
+	return "status.resource_conflicts"
+}
+
+type SpecApplyResult struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey"`
+	APIVersion  string    `gorm:"column:api_version;not null"`
+	Kind        string    `gorm:"column:kind;primaryKey"`
+	Namespace   string    `gorm:"column:namespace;primaryKey"`
+	Name        string    `gorm:"column:name;primaryKey"`
+	Result      string    `gorm:"column:result;not null"`
+	Reason      string    `gorm:"column:reason"`
+	AppliedAt   time.Time `gorm:"column:applied_at;autoCreateTime:false"`
+}
+
+func (SpecApplyResult) TableName() string {
+	return "status.spec_apply_results"
+}
+
+// SpecDrift is the global resource on the managed hub which doesn't match the spec distributed by the global hub
+type SpecDrift struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey"`
+	Kind        string    `gorm:"column:kind;primaryKey"`
+	Namespace   string    `gorm:"column:namespace;primaryKey"`
+	Name        string    `gorm:"column:name;primaryKey"`
+	DriftType   string    `gorm:"column:drift_type;not null"`
+	Reason      string    `gorm:"column:reason"`
+	DetectedAt  time.Time `gorm:"column:detected_at;autoCreateTime:false"`
+}
+
+func (SpecDrift) TableName() string {
+	return "status.spec_drifts"
+}
+
+// Transport is the committed offset of a partition, the name is the topic, which is qualified by the identity of the
+// additional kafka cluster
 type Transport struct {
 	Name      string         `gorm:"column:name;primaryKey"`
+	Partition int32          `gorm:"column:partition;primaryKey"`
 	Payload   datatypes.JSON `gorm:"column:payload;type:jsonb"` // KafkaPosition
 	CreatedAt time.Time      `gorm:"autoCreateTime:true"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime:true"`
@@ -72,6 +120,20 @@ func (Transport) TableName() string {
 	return "status.transport"
 }
 
+// CronJob is the last run of the scheduled job of the manager
+type CronJob struct {
+	Name      string    `gorm:"column:name;primaryKey"`
+	LastRunAt time.Time `gorm:"column:last_run_at"`
+	NextRunAt time.Time `gorm:"column:next_run_at"`
+	Result    string    `gorm:"column:result"`
+	Error     string    `gorm:"column:error"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime:true"`
+}
+
+func (CronJob) TableName() string {
+	return "status.cron_jobs"
+}
+
 type LeafHubHeartbeat struct {
 	Name         string    `gorm:"column:leaf_hub_name;primaryKey"`
 	Status       string    `gorm:"column:status;default:(-)"`
@@ -87,3 +149,309 @@ func (h LeafHubHeartbeat) UpInsertHeartBeat(db *gorm.DB) error {
 		VALUES ($1, $2, $3) ON CONFLICT (leaf_hub_name) DO UPDATE SET last_timestamp = $3;`
 	return db.Exec(tmp, h.Name, h.Status, h.LastUpdateAt).Error
 }
+
+// HubResourceCount is the number of the resources reported by the agent and the number of them in the database, the
+// reported count is updated by the status syncer, and the stored count is updated by the data consistency job
+type HubResourceCount struct {
+	LeafHubName   string     `gorm:"column:leaf_hub_name;primaryKey"`
+	ResourceType  string     `gorm:"column:resource_type;primaryKey"`
+	ReportedCount int        `gorm:"column:reported_count"`
+	StoredCount   *int       `gorm:"column:stored_count"`
+	Consistent    *bool      `gorm:"column:consistent"`
+	ReportedAt    time.Time  `gorm:"column:reported_at"`
+	CheckedAt     *time.Time `gorm:"column:checked_at"`
+}
+
+func (HubResourceCount) TableName() string {
+	return "status.hub_resource_counts"
+}
+
+// GatekeeperConstraint is the audit result of the gatekeeper constraint on the cluster
+type GatekeeperConstraint struct {
+	LeafHubName       string     `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName       string     `gorm:"column:cluster_name;primaryKey"`
+	ConstraintKind    string     `gorm:"column:constraint_kind;primaryKey"`
+	ConstraintName    string     `gorm:"column:constraint_name;primaryKey"`
+	EnforcementAction string     `gorm:"column:enforcement_action;not null"`
+	TotalViolations   int        `gorm:"column:total_violations;not null"`
+	AuditTimestamp    *time.Time `gorm:"column:audit_timestamp"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (GatekeeperConstraint) TableName() string {
+	return "status.gatekeeper_constraints"
+}
+
+// GatekeeperViolation is the resource which violates the gatekeeper constraint
+type GatekeeperViolation struct {
+	LeafHubName       string `gorm:"column:leaf_hub_name;not null"`
+	ClusterName       string `gorm:"column:cluster_name;not null"`
+	ConstraintKind    string `gorm:"column:constraint_kind;not null"`
+	ConstraintName    string `gorm:"column:constraint_name;not null"`
+	ResourceKind      string `gorm:"column:resource_kind;not null"`
+	ResourceNamespace string `gorm:"column:resource_namespace;not null"`
+	ResourceName      string `gorm:"column:resource_name;not null"`
+	Message           string `gorm:"column:message;not null"`
+	EnforcementAction string `gorm:"column:enforcement_action;not null"`
+}
+
+func (GatekeeperViolation) TableName() string {
+	return "status.gatekeeper_violations"
+}
+
+// ComplianceScan is the result of the compliance operator scan on the cluster
+type ComplianceScan struct {
+	LeafHubName  string     `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName  string     `gorm:"column:cluster_name;primaryKey"`
+	ScanName     string     `gorm:"column:scan_name;primaryKey"`
+	Phase        string     `gorm:"column:phase;not null"`
+	Result       string     `gorm:"column:result;not null"`
+	Passed       int        `gorm:"column:passed;not null"`
+	Failed       int        `gorm:"column:failed;not null"`
+	Manual       int        `gorm:"column:manual;not null"`
+	Others       int        `gorm:"column:others;not null"`
+	FailedHigh   int        `gorm:"column:failed_high;not null"`
+	FailedMedium int        `gorm:"column:failed_medium;not null"`
+	FailedLow    int        `gorm:"column:failed_low;not null"`
+	EndTimestamp *time.Time `gorm:"column:end_timestamp"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ComplianceScan) TableName() string {
+	return "status.compliance_scans"
+}
+
+// ImageVulnerability is the vulnerabilities of the severity in the images running on the cluster
+type ImageVulnerability struct {
+	LeafHubName     string    `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName     string    `gorm:"column:cluster_name;primaryKey"`
+	Severity        string    `gorm:"column:severity;primaryKey"`
+	Images          int       `gorm:"column:images;not null"`
+	Vulnerabilities int       `gorm:"column:vulnerabilities;not null"`
+	Fixable         int       `gorm:"column:fixable;not null"`
+	AffectedPods    int       `gorm:"column:affected_pods;not null"`
+	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ImageVulnerability) TableName() string {
+	return "status.image_vulnerabilities"
+}
+
+// PolicyTemplateDetail is the latest result of the policy template on the cluster, the related objects are limited
+// by the agent
+type PolicyTemplateDetail struct {
+	LeafHubName         string         `gorm:"column:leaf_hub_name;primaryKey"`
+	PolicyID            string         `gorm:"column:policy_id;primaryKey"`
+	ClusterName         string         `gorm:"column:cluster_name;primaryKey"`
+	TemplateKind        string         `gorm:"column:template_kind;primaryKey"`
+	TemplateName        string         `gorm:"column:template_name;primaryKey"`
+	PolicyNamespace     string         `gorm:"column:policy_namespace;not null"`
+	PolicyName          string         `gorm:"column:policy_name;not null"`
+	ClusterID           string         `gorm:"column:cluster_id"`
+	Compliance          string         `gorm:"column:compliance;not null"`
+	Message             string         `gorm:"column:message;not null"`
+	TotalRelatedObjects int            `gorm:"column:total_related_objects;not null"`
+	RelatedObjects      datatypes.JSON `gorm:"column:related_objects;type:jsonb"`
+	LastTimestamp       *time.Time     `gorm:"column:last_timestamp"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (PolicyTemplateDetail) TableName() string {
+	return "status.policy_template_details"
+}
+
+// AgentHealth is the version and the health of the agent reported with the heartbeat of the managed hub
+type AgentHealth struct {
+	LeafHubName    string         `gorm:"column:leaf_hub_name;primaryKey"`
+	AgentVersion   string         `gorm:"column:agent_version;not null"`
+	Collectors     datatypes.JSON `gorm:"column:collectors;type:jsonb"`
+	LastSyncTimes  datatypes.JSON `gorm:"column:last_sync_times;type:jsonb"`
+	ResourceCounts datatypes.JSON `gorm:"column:resource_counts;type:jsonb"`
+	ReportedAt     time.Time      `gorm:"column:reported_at"`
+}
+
+func (AgentHealth) TableName() string {
+	return "status.agent_health"
+}
+
+// AgentVersion is the version of the agent detected from the bundles of the managed hub, and its compatibility with
+// the manager
+type AgentVersion struct {
+	LeafHubName    string    `gorm:"column:leaf_hub_name;primaryKey"`
+	AgentVersion   string    `gorm:"column:agent_version;not null"`
+	ManagerVersion string    `gorm:"column:manager_version;not null"`
+	Compatibility  string    `gorm:"column:compatibility;not null"`
+	Reason         string    `gorm:"column:reason"`
+	Quarantined    bool      `gorm:"column:quarantined;not null"`
+	UpdatedAt      time.Time `gorm:"column:updated_at"`
+}
+
+func (AgentVersion) TableName() string {
+	return "status.agent_versions"
+}
+
+// DataFreshness is the last time the bundle of the type is received from the managed hub, and the interval it's
+// expected to be received in, which is null for the bundles only sent once they're changed
+type DataFreshness struct {
+	LeafHubName             string    `gorm:"column:leaf_hub_name;primaryKey"`
+	BundleType              string    `gorm:"column:bundle_type;primaryKey"`
+	LastReceivedAt          time.Time `gorm:"column:last_received_at;not null"`
+	ExpectedIntervalSeconds *int64    `gorm:"column:expected_interval_seconds"`
+	UpdatedAt               time.Time `gorm:"column:updated_at"`
+}
+
+func (DataFreshness) TableName() string {
+	return "status.data_freshness"
+}
+
+// HubTransportUsage is the number and the bytes of the bundles of the type consumed from the managed hub on the date
+type HubTransportUsage struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey"`
+	BundleType  string    `gorm:"column:bundle_type;primaryKey"`
+	UsageDate   time.Time `gorm:"column:usage_date;type:date;primaryKey"`
+	Bundles     int64     `gorm:"column:bundles;not null"`
+	Bytes       int64     `gorm:"column:bytes;not null"`
+}
+
+func (HubTransportUsage) TableName() string {
+	return "status.hub_transport_usage"
+}
+
+// HubStorageUsage is the number of the records of the managed hub in the table and the estimated bytes they take
+type HubStorageUsage struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey"`
+	Table       string    `gorm:"column:table_name;primaryKey"`
+	Records     int64     `gorm:"column:records;not null"`
+	Bytes       int64     `gorm:"column:bytes;not null"`
+	MeasuredAt  time.Time `gorm:"column:measured_at;autoCreateTime:false"`
+}
+
+func (HubStorageUsage) TableName() string {
+	return "status.hub_storage_usage"
+}
+
+// DeadLetterEvent is the status event rejected by the manager since it doesn't match the schema of its version, the
+// payload is kept as it's received to investigate the schema drift between the agent and the manager
+type DeadLetterEvent struct {
+	ID            int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	LeafHubName   string    `gorm:"column:leaf_hub_name;not null"`
+	EventType     string    `gorm:"column:event_type;not null"`
+	EventID       string    `gorm:"column:event_id;not null"`
+	SchemaVersion string    `gorm:"column:schema_version"`
+	Reason        string    `gorm:"column:reason;not null"`
+	Payload       []byte    `gorm:"column:payload"`
+	ReceivedAt    time.Time `gorm:"column:received_at;autoCreateTime:false"`
+}
+
+func (DeadLetterEvent) TableName() string {
+	return "status.dead_letter_events"
+}
+
+// ManagedClusterMigration is the migration of the managed cluster from the source hub to the target hub
+type ManagedClusterMigration struct {
+	ID             int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	ClusterName    string    `gorm:"column:cluster_name;not null"`
+	FromHub        string    `gorm:"column:from_hub;not null"`
+	ToHub          string    `gorm:"column:to_hub;not null"`
+	Phase          string    `gorm:"column:phase;not null"`
+	Message        string    `gorm:"column:message"`
+	PhaseStartedAt time.Time `gorm:"column:phase_started_at"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterMigration) TableName() string {
+	return "status.managed_cluster_migrations"
+}
+
+// ClusterGroup is the named group of the managed clusters spanning the managed hubs
+type ClusterGroup struct {
+	Name          string         `gorm:"column:name;primaryKey"`
+	Description   string         `gorm:"column:description"`
+	LabelSelector string         `gorm:"column:label_selector"`
+	Clusters      datatypes.JSON `gorm:"column:clusters;type:jsonb"` // []string
+	CreatedAt     time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ClusterGroup) TableName() string {
+	return "status.cluster_groups"
+}
+
+// ClusterGroupMember is the managed cluster selected by the cluster group
+type ClusterGroupMember struct {
+	GroupName   string `gorm:"column:group_name;primaryKey"`
+	LeafHubName string `gorm:"column:leaf_hub_name;not null"`
+	ClusterID   string `gorm:"column:cluster_id;primaryKey"`
+	ClusterName string `gorm:"column:cluster_name;not null"`
+}
+
+func (ClusterGroupMember) TableName() string {
+	return "status.cluster_group_members"
+}
+
+// RegionalHubSummary is the summary of a managed hub forwarded by the regional global hub, it's stored on the upstream
+// global hub of the hierarchical topology
+type RegionalHubSummary struct {
+	RegionalHubName string         `gorm:"column:regional_hub_name;primaryKey"`
+	LeafHubName     string         `gorm:"column:leaf_hub_name;primaryKey"`
+	Status          string         `gorm:"column:status;not null"`
+	LastHeartbeat   time.Time      `gorm:"column:last_heartbeat"`
+	ManagedClusters int            `gorm:"column:managed_clusters;not null"`
+	Policies        int            `gorm:"column:policies;not null"`
+	Compliance      datatypes.JSON `gorm:"column:compliance;type:jsonb"`
+	ReportedAt      time.Time      `gorm:"column:reported_at"`
+}
+
+func (RegionalHubSummary) TableName() string {
+	return "status.regional_hub_summaries"
+}
+
+// ManagedClusterAddon is the health of the addon on the managed cluster
+type ManagedClusterAddon struct {
+	LeafHubName        string     `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName        string     `gorm:"column:cluster_name;primaryKey"`
+	AddonName          string     `gorm:"column:addon_name;primaryKey"`
+	Status             string     `gorm:"column:status;not null"`
+	Reason             string     `gorm:"column:reason;not null"`
+	Message            string     `gorm:"column:message;not null"`
+	LastTransitionTime *time.Time `gorm:"column:last_transition_time"`
+	UpdatedAt          time.Time  `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterAddon) TableName() string {
+	return "status.managed_cluster_addons"
+}
+
+// ManagedServiceAccount is the credential of the managed service account on the managed cluster, the token isn't
+// stored, only the secret which holds it on the managed hub
+type ManagedServiceAccount struct {
+	LeafHubName     string     `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName     string     `gorm:"column:cluster_name;primaryKey"`
+	Name            string     `gorm:"column:name;primaryKey"`
+	Status          string     `gorm:"column:status;not null"`
+	Reason          string     `gorm:"column:reason;not null"`
+	Message         string     `gorm:"column:message;not null"`
+	TokenSecretName string     `gorm:"column:token_secret_name;not null"`
+	LastRefreshTime *time.Time `gorm:"column:last_refresh_time"`
+	ExpirationTime  *time.Time `gorm:"column:expiration_time"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedServiceAccount) TableName() string {
+	return "status.managed_service_accounts"
+}
+
+// ManagedClusterEnrichment is the fields computed from the managed cluster by the enrichers, the business_unit and
+// region columns are generated from the fields
+type ManagedClusterEnrichment struct {
+	ClusterID   string         `gorm:"column:cluster_id;primaryKey"`
+	LeafHubName string         `gorm:"column:leaf_hub_name;not null"`
+	ClusterName string         `gorm:"column:cluster_name;not null"`
+	Fields      datatypes.JSON `gorm:"column:fields;type:jsonb"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterEnrichment) TableName() string {
+	return "status.managed_cluster_enrichments"
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -14,6 +15,39 @@ import (
 
 const errMessageFileNotFound = "no such file or directory"
 
+var (
+	pgxPool     *pgxpool.Pool
+	pgxPoolOnce sync.Once
+)
+
+// InitPgxPool initializes the pgx pool for the hot path of the status ingestion by the storage of the dialect, the
+// statements are prepared and cached on the connections of the pool, while the gorm instance is kept for the other
+// queries
+func InitPgxPool(ctx context.Context, config *DatabaseConfig) error {
+	storage, err := GetStorage(config.Dialect)
+	if err != nil {
+		return err
+	}
+	pgxPoolOnce.Do(func() {
+		pgxPool, err = storage.OpenPool(ctx, config)
+	})
+	return err
+}
+
+func GetPgxPool() *pgxpool.Pool {
+	if pgxPool == nil {
+		log.Error(nil, "pgx pool is not initialized")
+		return nil
+	}
+	return pgxPool
+}
+
+func ClosePgxPool() {
+	if pgxPool != nil {
+		pgxPool.Close()
+	}
+}
+
 func PostgresConnection(ctx context.Context, URI string, cert []byte) (*pgx.Conn, error) {
 	config, err := GetPostgresConfig(URI, cert)
 	if err != nil {
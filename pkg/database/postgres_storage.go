@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	_ "github.com/lib/pq"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var _ Storage = &postgresStorage{}
+
+// postgresStorage is the default storage, the global lock is the session level advisory lock of postgres
+type postgresStorage struct{}
+
+func (s *postgresStorage) Dialect() string {
+	return PostgresDialect
+}
+
+func (s *postgresStorage) Open(config *DatabaseConfig) (*gorm.DB, *sql.DB, error) {
+	urlObj, err := completePostgres(config.URL, config.CaCertPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sqlDBConn, err := sql.Open(PostgresDialect, urlObj.String())
+	if err != nil {
+		log.Error(err, "failed to open database connection")
+		return nil, nil, err
+	}
+	gormDBconn, err := gorm.Open(postgres.New(postgres.Config{
+		Conn:                 sqlDBConn,
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{
+		PrepareStmt:          false,
+		FullSaveAssociations: false,
+	})
+	if err != nil {
+		log.Error(err, "failed to open gorm connection")
+		return nil, nil, err
+	}
+	return gormDBconn, sqlDBConn, nil
+}
+
+func (s *postgresStorage) OpenPool(ctx context.Context, config *DatabaseConfig) (*pgxpool.Pool, error) {
+	urlObj, err := completePostgres(config.URL, config.CaCertPath)
+	if err != nil {
+		return nil, err
+	}
+	return PostgresConnPool(ctx, urlObj.String(), config.CaCertPath, int32(config.PoolSize))
+}
+
+func (s *postgresStorage) Lock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "select pg_advisory_lock($1)", constants.LockId)
+	return err
+}
+
+func (s *postgresStorage) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "select pg_advisory_unlock($1)", constants.LockId)
+	return err
+}
+
+func completePostgres(postgresUri string, caCertPath string) (*url.URL, error) {
+	urlObj, err := url.Parse(postgresUri)
+	if err != nil {
+		return nil, err
+	}
+	// only support verify-ca or disable(for test)
+	query := urlObj.Query()
+	_, ok := utils.Validate(caCertPath)
+	if query.Get("sslmode") == "verify-ca" && ok {
+		query.Set("sslrootcert", caCertPath)
+	} else {
+		query.Add("sslmode", "disable")
+	}
+	urlObj.RawQuery = query.Encode()
+	return urlObj, nil
+}
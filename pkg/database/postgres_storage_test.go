@@ -0,0 +1,41 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/test/pkg/testpostgres"
+	"github.com/stolostron/multicluster-global-hub/test/pkg/teststorage"
+)
+
+func TestPostgresStorageConformance(t *testing.T) {
+	postgres, err := testpostgres.NewTestPostgres()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := postgres.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	storage, err := database.GetStorage(database.PostgresDialect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	teststorage.RunConformance(t, storage, &database.DatabaseConfig{
+		URL:      postgres.URI,
+		Dialect:  database.PostgresDialect,
+		PoolSize: 2,
+	})
+}
+
+func TestUnsupportedStorage(t *testing.T) {
+	if _, err := database.GetStorage("unknown"); err == nil {
+		t.Error("expected the error of the unsupported dialect")
+	}
+	err := database.InitGormInstance(&database.DatabaseConfig{Dialect: "unknown"})
+	if err == nil {
+		t.Error("expected the error of the unsupported dialect")
+	}
+}
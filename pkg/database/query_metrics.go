@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// QueryDurationHistogramVec is the duration of the database queries by the query name, it isn't registered by the
+// database package, the component registers it with its metrics registry
+var QueryDurationHistogramVec = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "multicluster_global_hub_database_query_duration_seconds",
+		Help:    "The duration of the database queries.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	},
+	[]string{
+		"query", // The query name, e.g. "upsert_status.compliance".
+	},
+)
+
+// the queries which take longer than it are logged, the slow query log is disabled if it's 0
+var slowQueryThreshold time.Duration
+
+// SetSlowQueryThreshold enables the slow query log with the threshold, or disables it with 0
+func SetSlowQueryThreshold(threshold time.Duration) {
+	slowQueryThreshold = threshold
+}
+
+type queryNameKey struct{}
+
+// WithQueryName names the queries running with the context, e.g. db.WithContext(WithQueryName(ctx, "hub_heartbeat")),
+// otherwise the query is named by its operation and table
+func WithQueryName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryNameKey{}, name)
+}
+
+// ObserveQuery records the duration of the query, and logs it if it's slower than the slow query threshold. Only the
+// statement is logged, the bound parameters are redacted since they might contain the sensitive data.
+func ObserveQuery(name, statement string, vars int, startAt time.Time, err error) {
+	duration := time.Since(startAt)
+	QueryDurationHistogramVec.WithLabelValues(name).Observe(duration.Seconds())
+	if slowQueryThreshold <= 0 || duration < slowQueryThreshold {
+		return
+	}
+	log.Info("slow query", "query", name, "duration", duration.String(), "statement", statement,
+		"vars", redactedVars(vars), "error", err)
+}
+
+func redactedVars(vars int) []string {
+	redacted := make([]string, vars)
+	for i := range redacted {
+		redacted[i] = "?"
+	}
+	return redacted
+}
+
+var _ gorm.Plugin = &queryMetricsPlugin{}
+
+// queryMetricsPlugin observes the queries of the gorm instance with the callbacks around the gorm operations
+type queryMetricsPlugin struct{}
+
+func (p *queryMetricsPlugin) Name() string {
+	return "query-metrics"
+}
+
+func (p *queryMetricsPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	var errs []error
+	register := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	register(callback.Create().Before("gorm:create").Register("metrics:before_create", beforeQuery))
+	register(callback.Create().After("gorm:create").Register("metrics:after_create", afterQuery("insert")))
+	register(callback.Query().Before("gorm:query").Register("metrics:before_query", beforeQuery))
+	register(callback.Query().After("gorm:query").Register("metrics:after_query", afterQuery("select")))
+	register(callback.Update().Before("gorm:update").Register("metrics:before_update", beforeQuery))
+	register(callback.Update().After("gorm:update").Register("metrics:after_update", afterQuery("update")))
+	register(callback.Delete().Before("gorm:delete").Register("metrics:before_delete", beforeQuery))
+	register(callback.Delete().After("gorm:delete").Register("metrics:after_delete", afterQuery("delete")))
+	register(callback.Row().Before("gorm:row").Register("metrics:before_row", beforeQuery))
+	register(callback.Row().After("gorm:row").Register("metrics:after_row", afterQuery("row")))
+	register(callback.Raw().Before("gorm:raw").Register("metrics:before_raw", beforeQuery))
+	register(callback.Raw().After("gorm:raw").Register("metrics:after_raw", afterQuery("raw")))
+	return errors.Join(errs...)
+}
+
+func beforeQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func afterQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		startAt, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		ObserveQuery(queryName(db, operation), db.Statement.SQL.String(), len(db.Statement.Vars), startAt, db.Error)
+	}
+}
+
+// queryName returns the name from the context, or the operation and table, e.g. "select_status.managed_clusters"
+func queryName(db *gorm.DB, operation string) string {
+	if db.Statement.Context != nil {
+		if name, ok := db.Statement.Context.Value(queryNameKey{}).(string); ok && name != "" {
+			return name
+		}
+	}
+	table := db.Statement.Table
+	if db.Statement.TableExpr != nil {
+		// the table with the schema, e.g. "status"."managed_clusters"
+		table = strings.ReplaceAll(db.Statement.TableExpr.SQL, `"`, "")
+	}
+	if table == "" {
+		return operation
+	}
+	return operation + "_" + table
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestQueryMetricsPlugin(t *testing.T) {
+	// the dry run builds the statements without connecting to the database
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "postgres://localhost:5432/test?sslmode=disable"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open the gorm instance: %v", err)
+	}
+	if err := db.Use(&queryMetricsPlugin{}); err != nil {
+		t.Fatalf("failed to use the query metrics plugin: %v", err)
+	}
+
+	var names []string
+	db.Table("status.managed_clusters").Where("leaf_hub_name = ?", "hub1").Pluck("cluster_name", &names)
+	if count := testutil.CollectAndCount(QueryDurationHistogramVec,
+		"multicluster_global_hub_database_query_duration_seconds"); count != 1 {
+		t.Fatalf("expected 1 query name, got %d", count)
+	}
+	if count := histogramCount(t, "select_status.managed_clusters"); count != 1 {
+		t.Fatalf("expected 1 observation of the select query, got %d", count)
+	}
+
+	ctx := WithQueryName(context.Background(), "hub_heartbeat")
+	db.WithContext(ctx).Exec("UPDATE status.leaf_hub_heartbeats SET last_timestamp = now() WHERE leaf_hub_name = ?",
+		"hub1")
+	if count := histogramCount(t, "hub_heartbeat"); count != 1 {
+		t.Fatalf("expected 1 observation of the named query, got %d", count)
+	}
+}
+
+func TestSlowQueryRedactedVars(t *testing.T) {
+	SetSlowQueryThreshold(time.Nanosecond)
+	defer SetSlowQueryThreshold(0)
+
+	// the slow query is logged without panic, and the bound parameters are replaced
+	ObserveQuery("slow_query", "SELECT * FROM status.managed_clusters WHERE leaf_hub_name = $1", 1,
+		time.Now().Add(-time.Second), nil)
+	vars := redactedVars(2)
+	if len(vars) != 2 || vars[0] != "?" || vars[1] != "?" {
+		t.Fatalf("expected the redacted vars, got %v", vars)
+	}
+}
+
+func histogramCount(t *testing.T, name string) uint64 {
+	metric := &dto.Metric{}
+	if err := QueryDurationHistogramVec.WithLabelValues(name).(prometheus.Metric).Write(metric); err != nil {
+		t.Fatalf("failed to write the histogram: %v", err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"gorm.io/gorm"
+)
+
+// Storage is the backend of the global data store. The data store is accessed by gorm and the postgres wire protocol,
+// so the backends compatible with postgres, e.g. CockroachDB or YugabyteDB for the multi-region durability, are able
+// to implement it with their own connection and locking. Both the gorm instance and the pgx pool of the status
+// ingestion are opened by the storage. The postgres storage is registered by default, and the
+// implementations are verified by the conformance tests in test/pkg/teststorage.
+type Storage interface {
+	// Dialect is the name of the storage, it's matched with the dialect of the database config
+	Dialect() string
+	// Open connects to the data store, the sql.DB is the underlying connection pool of the gorm instance
+	Open(config *DatabaseConfig) (*gorm.DB, *sql.DB, error)
+	// OpenPool connects the pgx pool to the data store, it's used by the writers of the status ingestion, which pass
+	// the rows as the array parameters of the prepared statements
+	OpenPool(ctx context.Context, config *DatabaseConfig) (*pgxpool.Pool, error)
+	// Lock acquires the global lock of the data store on the connection, it blocks until the lock is acquired. The
+	// lock is held by the connection, so the other connections are blocked until it's unlocked on the same connection
+	Lock(ctx context.Context, conn *sql.Conn) error
+	// Unlock releases the global lock which is acquired on the connection
+	Unlock(ctx context.Context, conn *sql.Conn) error
+}
+
+var (
+	storages     = map[string]Storage{PostgresDialect: &postgresStorage{}}
+	storageMutex sync.RWMutex
+)
+
+// RegisterStorage registers the storage by its dialect, it replaces the registered storage with the same dialect
+func RegisterStorage(storage Storage) {
+	storageMutex.Lock()
+	defer storageMutex.Unlock()
+	storages[storage.Dialect()] = storage
+}
+
+// GetStorage returns the registered storage of the dialect
+func GetStorage(dialect string) (Storage, error) {
+	storageMutex.RLock()
+	defer storageMutex.RUnlock()
+	storage, found := storages[dialect]
+	if !found {
+		return nil, fmt.Errorf("unsupported database dialect: %s", dialect)
+	}
+	return storage, nil
+}
@@ -0,0 +1,170 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/version"
+)
+
+const (
+	// BundlePath is the path of the diagnostics bundle, the pprof and expvar endpoints are under /debug as well
+	BundlePath     = "/debug/diagnostics"
+	collectTimeout = 10 * time.Second
+)
+
+// CollectFunc returns the state of the component in the diagnostics bundle, e.g. the partitions assigned to the kafka
+// consumer or the stats of the database pool
+type CollectFunc func(ctx context.Context) (interface{}, error)
+
+var (
+	collectors     = map[string]CollectFunc{}
+	collectorsLock sync.RWMutex
+)
+
+// Register adds the collector to the diagnostics bundle, the collector registered with the same name is replaced
+func Register(name string, collect CollectFunc) {
+	collectorsLock.Lock()
+	defer collectorsLock.Unlock()
+	collectors[name] = collect
+}
+
+// Bundle is the snapshot of the runtime and the registered collectors for the supportability of the incidents
+type Bundle struct {
+	CollectedAt time.Time              `json:"collectedAt"`
+	Version     string                 `json:"version"`
+	GoVersion   string                 `json:"goVersion"`
+	Goroutines  int                    `json:"goroutines"`
+	Memory      MemoryStats            `json:"memory"`
+	Collectors  map[string]interface{} `json:"collectors"`
+	// Errors are the collectors failed to collect, keyed by the names of the collectors
+	Errors map[string]string `json:"errors,omitempty"`
+	// GoroutineStacks is the dump of the goroutines, it's only included with the ?goroutines=true
+	GoroutineStacks string `json:"goroutineStacks,omitempty"`
+}
+
+type MemoryStats struct {
+	HeapAllocBytes uint64 `json:"heapAllocBytes"`
+	HeapInuseBytes uint64 `json:"heapInuseBytes"`
+	SysBytes       uint64 `json:"sysBytes"`
+	NumGC          uint32 `json:"numGC"`
+}
+
+// Collect snapshots the runtime and runs the registered collectors
+func Collect(ctx context.Context, withStacks bool) *Bundle {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	bundle := &Bundle{
+		CollectedAt: time.Now(),
+		Version:     version.Get(),
+		GoVersion:   runtime.Version(),
+		Goroutines:  runtime.NumGoroutine(),
+		Memory: MemoryStats{
+			HeapAllocBytes: memStats.HeapAlloc,
+			HeapInuseBytes: memStats.HeapInuse,
+			SysBytes:       memStats.Sys,
+			NumGC:          memStats.NumGC,
+		},
+		Collectors: map[string]interface{}{},
+		Errors:     map[string]string{},
+	}
+
+	collectorsLock.RLock()
+	names := make([]string, 0, len(collectors))
+	for name := range collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	funcs := make([]CollectFunc, 0, len(names))
+	for _, name := range names {
+		funcs = append(funcs, collectors[name])
+	}
+	collectorsLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, collectTimeout)
+	defer cancel()
+	for i, name := range names {
+		result, err := funcs[i](ctx)
+		if err != nil {
+			bundle.Errors[name] = err.Error()
+			continue
+		}
+		bundle.Collectors[name] = result
+	}
+
+	if withStacks {
+		stacks := &strings.Builder{}
+		if profile := runtimepprof.Lookup("goroutine"); profile != nil {
+			_ = profile.WriteTo(stacks, 2)
+		}
+		bundle.GoroutineStacks = stacks.String()
+	}
+	return bundle
+}
+
+// Handlers returns the pprof, expvar and the diagnostics bundle handlers keyed by the paths, they're served as the
+// extra handlers of the metrics server
+func Handlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/debug/pprof/":        http.HandlerFunc(pprof.Index),
+		"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
+		"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
+		"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
+		"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+		"/debug/vars":          expvar.Handler(),
+		BundlePath:             http.HandlerFunc(serveBundle),
+	}
+}
+
+// Protect wraps the handlers with the filter, it's used if the metrics server doesn't filter the extra handlers
+func Protect(handlers map[string]http.Handler, filter metricsserver.Filter, log logr.Logger,
+) (map[string]http.Handler, error) {
+	protected := make(map[string]http.Handler, len(handlers))
+	for path, handler := range handlers {
+		filtered, err := filter(log, handler)
+		if err != nil {
+			return nil, fmt.Errorf("failed to protect the diagnostics endpoint %s: %w", path, err)
+		}
+		protected[path] = filtered
+	}
+	return protected, nil
+}
+
+// ProtectedHandlers returns the handlers authenticated and authorized by the kube-apiserver, they're served by the
+// insecure metrics server, which doesn't filter the extra handlers
+func ProtectedHandlers(config *rest.Config, log logr.Logger) (map[string]http.Handler, error) {
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := filters.WithAuthenticationAndAuthorization(config, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return Protect(Handlers(), filter, log)
+}
+
+func serveBundle(w http.ResponseWriter, req *http.Request) {
+	bundle := Collect(req.Context(), req.URL.Query().Get("goroutines") == "true")
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(bundle); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
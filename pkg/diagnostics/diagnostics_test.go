@@ -0,0 +1,77 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestBundle(t *testing.T) {
+	Register("test-assignment", func(ctx context.Context) (interface{}, error) {
+		return map[string][]int32{"status": {0, 1}}, nil
+	})
+	Register("test-failure", func(ctx context.Context) (interface{}, error) {
+		return nil, fmt.Errorf("not connected")
+	})
+
+	cases := []struct {
+		name       string
+		url        string
+		withStacks bool
+	}{
+		{"without goroutine stacks", BundlePath, false},
+		{"with goroutine stacks", BundlePath + "?goroutines=true", true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			Handlers()[BundlePath].ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.url, nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("expected the status %d, got %d", http.StatusOK, recorder.Code)
+			}
+
+			bundle := &Bundle{}
+			if err := json.Unmarshal(recorder.Body.Bytes(), bundle); err != nil {
+				t.Fatalf("failed to unmarshal the bundle: %v", err)
+			}
+			if bundle.Goroutines == 0 || bundle.Memory.SysBytes == 0 {
+				t.Errorf("expected the runtime stats, got %+v", bundle)
+			}
+			if _, found := bundle.Collectors["test-assignment"]; !found {
+				t.Errorf("expected the result of the test-assignment, got %v", bundle.Collectors)
+			}
+			if bundle.Errors["test-failure"] != "not connected" {
+				t.Errorf("expected the error of the test-failure, got %v", bundle.Errors)
+			}
+			if hasStacks := strings.Contains(bundle.GoroutineStacks, "goroutine "); hasStacks != c.withStacks {
+				t.Errorf("expected the goroutine stacks %v, got %v", c.withStacks, hasStacks)
+			}
+		})
+	}
+}
+
+func TestProtect(t *testing.T) {
+	unauthorized := func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		}), nil
+	}
+	handlers, err := Protect(Handlers(), unauthorized, logr.Discard())
+	if err != nil {
+		t.Fatalf("failed to protect the handlers: %v", err)
+	}
+	for path, handler := range handlers {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusUnauthorized {
+			t.Errorf("expected the status %d of %s without token, got %d", http.StatusUnauthorized, path,
+				recorder.Code)
+		}
+	}
+}
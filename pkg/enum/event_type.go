@@ -7,9 +7,34 @@ type EventType string
 const (
 	HubClusterInfoType      EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.info"
 	HubClusterHeartbeatType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.heartbeat"
+	ResourceConflictType    EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.resource.conflict"
+	SpecApplyResultType     EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.spec.applyresult"
 	ManagedClusterType      EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster"
+	ManagedClusterAddonType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedclusteraddon"
 	SubscriptionReportType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.subscription.report"
 	SubscriptionStatusType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.subscription.status"
+	ArgoApplicationType     EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.argocd.application"
+	ArgoApplicationSetType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.argocd.applicationset"
+
+	// the delta bundles contain the changes since the last full bundle
+	//nolint: go:S103
+	ManagedClusterDeltaType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster.delta"
+	//nolint: go:S103
+	LocalPolicySpecDeltaType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.localspec.delta"
+
+	//nolint: go:S103
+	HubResourceCountsType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.resourcecounts"
+	// the key metrics of the managed hub queried from its prometheus
+	HubMetricsType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.metrics"
+	// the summary of the managed hubs forwarded by the regional global hub to the upstream global hub
+	//nolint: go:S103
+	RegionalHubSummaryType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.regionalhub.summary"
+	// the credentials of the managed service accounts on the managed clusters, without the tokens
+	//nolint: go:S103
+	ManagedServiceAccountType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedserviceaccount"
+	// the results of running the phases of the managed cluster migrations on the hub
+	//nolint: go:S103
+	ManagedClusterMigrationType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster.migration"
 
 	//nolint: go:S103
 	LocalComplianceType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.localcompliance"
@@ -20,6 +45,19 @@ const (
 	//nolint: go:S103
 	CompleteComplianceType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.completecompliance"
 
+	// the compliance of the kyverno policies collected from the policy reports
+	//nolint: go:S103
+	PolicyReportType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.policyreport"
+	// the audit results of the gatekeeper constraints
+	//nolint: go:S103
+	GatekeeperConstraintType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.gatekeeper"
+	// the compliance scans and the image vulnerabilities of the cluster
+	//nolint: go:S103
+	SecurityPostureType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.securityposture"
+	// the latest results of the templates of the replicated policies, with the messages and the related objects
+	//nolint: go:S103
+	PolicyTemplateDetailType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.templatedetail"
+
 	DeltaComplianceType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.deltacompliance"
 	MiniComplianceType  EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.policy.minicompliance"
 
@@ -33,4 +71,9 @@ const (
 	LocalPlacementRuleSpecType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.placementrule.localspec"
 	PlacementRuleSpecType      EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.placementrule.spec"
 	PlacementSpecType          EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.placement.spec"
+
+	//nolint: go:S103
+	ManagedClusterSetSpecType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedclusterset.spec"
+	//nolint: go:S103
+	ManagedClusterSetBindingSpecType EventType = "io.open-cluster-management.operator.multiclusterglobalhubs.managedclustersetbinding.spec"
 )
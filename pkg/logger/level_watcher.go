@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultLevelKey is the key of the default level in the configmap, the other keys are the subsystems
+	DefaultLevelKey = "level"
+	// LevelWatchInterval is the interval to read the configmap of the levels
+	LevelWatchInterval = 15 * time.Second
+)
+
+// LevelWatcher reads the levels from the configmap periodically, so the levels are changed at runtime without
+// restarting the pod, e.g. {"level": "info", "transport": "debug"} only debugs the transport layer. The levels of the
+// flag are restored once the configmap is deleted. The configmap is read directly rather than cached, so it doesn't
+// need the informer of the configmaps.
+type LevelWatcher struct {
+	log    logr.Logger
+	reader client.Reader
+	key    types.NamespacedName
+	levels *Levels
+}
+
+// AddLevelWatcher watches the levels of the DefaultLevels in the configmap, it runs on all the replicas
+func AddLevelWatcher(mgr ctrl.Manager, namespace, name string) error {
+	return mgr.Add(NewLevelWatcher(mgr.GetAPIReader(), namespace, name, DefaultLevels))
+}
+
+func NewLevelWatcher(reader client.Reader, namespace, name string, levels *Levels) *LevelWatcher {
+	return &LevelWatcher{
+		log:    ctrl.Log.WithName("log-level-watcher"),
+		reader: reader,
+		key:    types.NamespacedName{Namespace: namespace, Name: name},
+		levels: levels,
+	}
+}
+
+func (w *LevelWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *LevelWatcher) Start(ctx context.Context) error {
+	w.log.Info("watch the log levels", "configmap", w.key.String())
+	ticker := time.NewTicker(LevelWatchInterval)
+	defer ticker.Stop()
+	for {
+		if err := w.reload(ctx); err != nil {
+			w.log.Error(err, "failed to reload the log levels", "configmap", w.key.String())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload applies the levels of the configmap if they're changed, the current levels are kept if any of them is
+// invalid
+func (w *LevelWatcher) reload(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	data := map[string]string{}
+	if err := w.reader.Get(ctx, w.key, configMap); err == nil {
+		data = configMap.Data
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	var defaultLevel *zapcore.Level
+	subsystems := map[string]zapcore.Level{}
+	for key, value := range data {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("invalid level of %s: %w", key, err)
+		}
+		if key == DefaultLevelKey {
+			defaultLevel = &level
+			continue
+		}
+		subsystems[key] = level
+	}
+
+	currentDefault, currentSubsystems := w.levels.Get()
+	w.levels.Set(defaultLevel, subsystems)
+	newDefault, _ := w.levels.Get()
+	if newDefault != currentDefault || !reflect.DeepEqual(subsystems, currentSubsystems) {
+		w.log.Info("the log levels are changed", "level", newDefault.String(), "subsystems", fmt.Sprint(subsystems))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLevelWatcher(t *testing.T) {
+	ctx := context.Background()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "logging", Namespace: "default"},
+		Data:       map[string]string{DefaultLevelKey: "error", TransportSubsystem: "2"},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(configMap).Build()
+	levels := NewLevels(zapcore.InfoLevel)
+	watcher := NewLevelWatcher(fakeClient, "default", "logging", levels)
+
+	require.NoError(t, watcher.reload(ctx))
+	defaultLevel, subsystems := levels.Get()
+	assert.Equal(t, zapcore.ErrorLevel, defaultLevel)
+	assert.Equal(t, map[string]zapcore.Level{TransportSubsystem: zapcore.Level(-2)}, subsystems)
+
+	// the current levels are kept if any of them is invalid
+	configMap.Data[TransportSubsystem] = "verbose"
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	assert.ErrorContains(t, watcher.reload(ctx), "invalid level of transport")
+	defaultLevel, _ = levels.Get()
+	assert.Equal(t, zapcore.ErrorLevel, defaultLevel)
+
+	// the level of the flag is restored once the configmap is deleted
+	require.NoError(t, fakeClient.Delete(ctx, configMap))
+	require.NoError(t, watcher.reload(ctx))
+	defaultLevel, subsystems = levels.Get()
+	assert.Equal(t, zapcore.InfoLevel, defaultLevel)
+	assert.Empty(t, subsystems)
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// the logger names are joined by the dots, e.g. transport.kafka-producer is the producer of the transport subsystem
+const (
+	// TransportSubsystem is the prefix of the loggers of the kafka producers and consumers
+	TransportSubsystem = "transport"
+)
+
+// DefaultLevels are the levels of the loggers created by New, they're changed at runtime by the LevelWatcher
+var DefaultLevels = NewLevels(zapcore.InfoLevel)
+
+// Levels are the default level and the levels of the named subsystems. The level of the logger is the one of its
+// longest matched subsystem, e.g. the level of transport applies to transport.kafka-producer as well.
+type Levels struct {
+	mutex        sync.RWMutex
+	defaultLevel zapcore.Level
+	// the initial default level, it's restored once the default level is removed from the configmap
+	flagLevel  zapcore.Level
+	subsystems map[string]zapcore.Level
+	// the lowest one of the levels, the entries below it are dropped without looking up the subsystems
+	minLevel zapcore.Level
+}
+
+func NewLevels(defaultLevel zapcore.Level) *Levels {
+	return &Levels{
+		defaultLevel: defaultLevel,
+		flagLevel:    defaultLevel,
+		subsystems:   map[string]zapcore.Level{},
+		minLevel:     defaultLevel,
+	}
+}
+
+// Set replaces the default level and the levels of the subsystems, the default level is the one of the flag if it's
+// empty
+func (l *Levels) Set(defaultLevel *zapcore.Level, subsystems map[string]zapcore.Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.defaultLevel = l.flagLevel
+	if defaultLevel != nil {
+		l.defaultLevel = *defaultLevel
+	}
+	l.subsystems = subsystems
+	l.updateMinLevel()
+}
+
+// Get returns the default level and a copy of the levels of the subsystems
+func (l *Levels) Get() (zapcore.Level, map[string]zapcore.Level) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	subsystems := make(map[string]zapcore.Level, len(l.subsystems))
+	for name, level := range l.subsystems {
+		subsystems[name] = level
+	}
+	return l.defaultLevel, subsystems
+}
+
+// Enabled returns true if the entry of the level is logged by the logger of the name
+func (l *Levels) Enabled(name string, level zapcore.Level) bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if level < l.minLevel {
+		return false
+	}
+	matched, enabledLevel := "", l.defaultLevel
+	for subsystem, subsystemLevel := range l.subsystems {
+		if len(subsystem) > len(matched) && (name == subsystem || strings.HasPrefix(name, subsystem+".")) {
+			matched, enabledLevel = subsystem, subsystemLevel
+		}
+	}
+	return level >= enabledLevel
+}
+
+// SetFlagLevel replaces the initial default level, which is restored once the default level is removed
+func (l *Levels) SetFlagLevel(level zapcore.Level) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.flagLevel, l.defaultLevel = level, level
+	l.updateMinLevel()
+}
+
+func (l *Levels) updateMinLevel() {
+	l.minLevel = l.defaultLevel
+	for _, level := range l.subsystems {
+		l.minLevel = min(l.minLevel, level)
+	}
+}
+
+func (l *Levels) lowest() zapcore.Level {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.minLevel
+}
+
+// ParseLevel parses the level as the zap-log-level flag, it's debug, info, warn, error or the verbosity of the logr,
+// e.g. 2 is the level of the V(2) logs
+func ParseLevel(value string) (zapcore.Level, error) {
+	value = strings.TrimSpace(value)
+	if level, err := zapcore.ParseLevel(value); err == nil {
+		return level, nil
+	}
+	verbosity, err := strconv.Atoi(value)
+	if err != nil || verbosity < 0 || verbosity > math.MaxInt8 {
+		return 0, fmt.Errorf("invalid log level %q, must be debug, info, warn, error or a non-negative integer", value)
+	}
+	return zapcore.Level(-verbosity), nil
+}
+
+// levelOf returns the lowest level enabled by the enabler
+func levelOf(enabler zapcore.LevelEnabler) zapcore.Level {
+	for level := zapcore.Level(math.MinInt8); level < zapcore.FatalLevel; level++ {
+		if enabler.Enabled(level) {
+			return level
+		}
+	}
+	return zapcore.FatalLevel
+}
+
+// New returns the logger writing the JSON logs by default, the --zap-encoder=console flag still switches to the
+// console logs. The zap-log-level flag is the initial default level of the DefaultLevels, and the entries are
+// filtered by the levels of their loggers, so the levels are changed without restarting the process.
+func New(opts *zap.Options) logr.Logger {
+	if opts.Level != nil {
+		DefaultLevels.SetFlagLevel(levelOf(opts.Level))
+	}
+	// the core enables all the levels, the entries are filtered by the DefaultLevels instead
+	opts.Level = zapcore.Level(math.MinInt8)
+	opts.ZapOpts = append(opts.ZapOpts, uberzap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, levels: DefaultLevels}
+	}))
+	return zap.New(zap.UseFlagOptions(opts))
+}
+
+// levelCore filters the entries by the levels of their loggers
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.lowest() && c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.Enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/go-logr/zapr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("debug")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.DebugLevel, level)
+
+	// the verbosity of the logr is the negative zap level
+	level, err = ParseLevel(" 4 ")
+	require.NoError(t, err)
+	assert.Equal(t, zapcore.Level(-4), level)
+
+	_, err = ParseLevel("verbose")
+	assert.ErrorContains(t, err, "invalid log level")
+	_, err = ParseLevel("-1")
+	assert.Error(t, err)
+}
+
+func TestLevelCore(t *testing.T) {
+	levels := NewLevels(zapcore.InfoLevel)
+	core, logs := observer.New(zapcore.Level(-10))
+	log := zapr.NewLogger(uberzap.New(&levelCore{Core: core, levels: levels}))
+
+	producerLog := log.WithName(TransportSubsystem).WithName("kafka-producer")
+	producerLog.V(2).Info("the transport isn't debugged")
+	log.WithName("conflation-dispatcher").Info("the default level is info")
+	assert.Equal(t, 1, logs.Len())
+
+	// only the transport layer is debugged
+	verbose := zapcore.Level(-2)
+	levels.Set(nil, map[string]zapcore.Level{TransportSubsystem: verbose})
+	producerLog.V(2).Info("the transport is debugged")
+	log.WithName("conflation-dispatcher").V(2).Info("the others aren't debugged")
+	log.WithName("transport-like").V(2).Info("the prefix isn't the subsystem")
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "transport.kafka-producer", logs.All()[1].LoggerName)
+
+	// the longest subsystem is matched
+	levels.Set(&verbose, map[string]zapcore.Level{
+		TransportSubsystem:                     zapcore.ErrorLevel,
+		TransportSubsystem + ".kafka-producer": verbose,
+	})
+	producerLog.V(2).Info("the producer is debugged")
+	log.WithName(TransportSubsystem).WithName("kafka-consumer").Info("the consumer only logs the errors")
+	log.WithName("conflation-dispatcher").V(2).Info("the default level is debugged")
+	assert.Equal(t, 4, logs.Len())
+
+	// the flag level is restored once the default level is removed
+	levels.Set(nil, map[string]zapcore.Level{})
+	defaultLevel, subsystems := levels.Get()
+	assert.Equal(t, zapcore.InfoLevel, defaultLevel)
+	assert.Empty(t, subsystems)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -45,6 +46,65 @@ func TestConfluentConfig(t *testing.T) {
 	}
 }
 
+func TestConfluentProducerIdempotence(t *testing.T) {
+	kafkaConfig := &transport.KafkaConfig{
+		BootstrapServer: "localhost:9092",
+		ProducerConfig:  &transport.KafkaProducerConfig{},
+	}
+	configMap, err := GetConfluentConfigMap(kafkaConfig, true)
+	if err != nil {
+		t.Fatalf("failed to get confluent config map - %v", err)
+	}
+	if idempotence, _ := configMap.Get("enable.idempotence", ""); idempotence != "true" {
+		t.Errorf("expected the idempotent producer, got enable.idempotence=%v", idempotence)
+	}
+	if acks, _ := configMap.Get("acks", ""); acks != "all" {
+		t.Errorf("expected acks=all for the idempotent producer, got %v", acks)
+	}
+
+	kafkaConfig.ProducerConfig.DisableIdempotence = true
+	configMap, err = GetConfluentConfigMap(kafkaConfig, true)
+	if err != nil {
+		t.Fatalf("failed to get confluent config map - %v", err)
+	}
+	if idempotence, _ := configMap.Get("enable.idempotence", ""); idempotence != "" {
+		t.Errorf("expected the idempotent producer is disabled, got enable.idempotence=%v", idempotence)
+	}
+}
+
+func TestConfluentBootstrapServerPath(t *testing.T) {
+	kafkaConfig := &transport.KafkaConfig{
+		BootstrapServer:     "localhost:9092",
+		BootstrapServerPath: filepath.Join(t.TempDir(), "bootstrap_server"),
+		ProducerConfig:      &transport.KafkaProducerConfig{},
+	}
+	// the bootstrap server flag is used until the file is mounted
+	configMap, err := GetConfluentConfigMap(kafkaConfig, true)
+	if err != nil {
+		t.Fatalf("failed to get confluent config map - %v", err)
+	}
+	if server, _ := configMap.Get("bootstrap.servers", ""); server != "localhost:9092" {
+		t.Errorf("expected the bootstrap server of the flag, got %v", server)
+	}
+
+	if err := os.WriteFile(kafkaConfig.BootstrapServerPath, []byte("kafka.example.com:443\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configMap, err = GetConfluentConfigMap(kafkaConfig, true)
+	if err != nil {
+		t.Fatalf("failed to get confluent config map - %v", err)
+	}
+	if server, _ := configMap.Get("bootstrap.servers", ""); server != "kafka.example.com:443" {
+		t.Errorf("expected the mounted bootstrap server, got %v", server)
+	}
+
+	kafkaConfig.BootstrapServer = ""
+	kafkaConfig.BootstrapServerPath = filepath.Join(t.TempDir(), "missing")
+	if _, err = GetConfluentConfigMap(kafkaConfig, true); err == nil {
+		t.Errorf("expected the error without the bootstrap server")
+	}
+}
+
 func TestGetSaramaConfig(t *testing.T) {
 	kafkaConfig := &transport.KafkaConfig{
 		EnableTLS:      false,
@@ -3,8 +3,10 @@ package config
 import (
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 
@@ -13,8 +15,12 @@ import (
 )
 
 func GetConfluentConfigMap(kafkaConfig *transport.KafkaConfig, producer bool) (*kafka.ConfigMap, error) {
+	bootstrapServer, err := GetBootstrapServer(kafkaConfig)
+	if err != nil {
+		return nil, err
+	}
 	kafkaConfigMap := &kafka.ConfigMap{
-		"bootstrap.servers":       kafkaConfig.BootstrapServer,
+		"bootstrap.servers":       bootstrapServer,
 		"socket.keepalive.enable": "true",
 		// silence spontaneous disconnection logs, kafka recovers by itself.
 		"log.connection.close": "false",
@@ -25,13 +31,29 @@ func GetConfluentConfigMap(kafkaConfig *transport.KafkaConfig, producer bool) (*
 	}
 	if producer {
 		_ = kafkaConfigMap.SetKey("go.produce.channel.size", 1000)
-		_ = kafkaConfigMap.SetKey("acks", "1")
-		_ = kafkaConfigMap.SetKey("retries", "0")
+		if kafkaConfig.ProducerConfig != nil && kafkaConfig.ProducerConfig.DisableIdempotence {
+			_ = kafkaConfigMap.SetKey("acks", "1")
+			_ = kafkaConfigMap.SetKey("retries", "0")
+		} else {
+			// the broker drops the duplicates of the messages retried by the producer after the timeouts, it requires
+			// all the in-sync replicas to ack and at most 5 in-flight requests to keep the order
+			_ = kafkaConfigMap.SetKey("enable.idempotence", "true")
+			_ = kafkaConfigMap.SetKey("acks", "all")
+			_ = kafkaConfigMap.SetKey("max.in.flight.requests.per.connection", 5)
+			// the sender waits for the delivery, so the retries are bounded by it
+			_ = kafkaConfigMap.SetKey("message.timeout.ms", 60000)
+		}
 	} else {
 		_ = kafkaConfigMap.SetKey("enable.auto.commit", "true")
-		_ = kafkaConfigMap.SetKey("auto.offset.reset", "earliest")
+		offsetReset := transport.OffsetResetEarliest
+		if kafkaConfig.ConsumerConfig.OffsetReset != "" {
+			offsetReset = kafkaConfig.ConsumerConfig.OffsetReset
+		}
+		_ = kafkaConfigMap.SetKey("auto.offset.reset", offsetReset)
 		_ = kafkaConfigMap.SetKey("group.id", kafkaConfig.ConsumerConfig.ConsumerID)
 		_ = kafkaConfigMap.SetKey("client.id", kafkaConfig.ConsumerConfig.ConsumerID)
+		// the consumer group is rebalanced within a minute once the partitions of the subscribed topics are increased
+		_ = kafkaConfigMap.SetKey("topic.metadata.refresh.interval.ms", 60000)
 	}
 
 	_, validCA := utils.Validate(kafkaConfig.CaCertPath)
@@ -57,6 +79,26 @@ func GetConfluentConfigMap(kafkaConfig *transport.KafkaConfig, producer bool) (*
 	return kafkaConfigMap, nil
 }
 
+// GetBootstrapServer returns the bootstrap server mounted in the BootstrapServerPath, so the rebuilt clients connect
+// to the current one. It falls back to the BootstrapServer if the file isn't mounted or it's in the middle of the
+// update by the kubelet.
+func GetBootstrapServer(kafkaConfig *transport.KafkaConfig) (string, error) {
+	if kafkaConfig.BootstrapServerPath == "" {
+		return kafkaConfig.BootstrapServer, nil
+	}
+	content, err := os.ReadFile(filepath.Clean(kafkaConfig.BootstrapServerPath))
+	if bootstrapServer := strings.TrimSpace(string(content)); err == nil && bootstrapServer != "" {
+		return bootstrapServer, nil
+	}
+	if kafkaConfig.BootstrapServer != "" {
+		return kafkaConfig.BootstrapServer, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read the kafka bootstrap server: %w", err)
+	}
+	return "", fmt.Errorf("the kafka bootstrap server is empty in %s", kafkaConfig.BootstrapServerPath)
+}
+
 // registers the ca in root certification authority.
 func setCertificate(caCertPath string) error {
 	certBytes, err := os.ReadFile(filepath.Clean(caCertPath))
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// CredentialCheckInterval is the minimal interval to read the mounted credentials again
+var CredentialCheckInterval = 30 * time.Second
+
+// CredentialWatcher detects the rotation of the kafka credentials and the change of the bootstrap server mounted from
+// the secrets. The kubelet replaces the mounted files by swapping the symlink of the secret volume, so the contents
+// are compared instead of watching the file events. The kafka clients only read the credentials on creation, they're
+// rebuilt once it's changed, which resolves the new bootstrap server and drops the brokers known by the previous one.
+type CredentialWatcher struct {
+	paths           []string
+	mutex           sync.Mutex
+	checksum        []byte
+	pendingChecksum []byte
+	lastCheck       time.Time
+}
+
+func NewCredentialWatcher(kafkaConfig *transport.KafkaConfig) *CredentialWatcher {
+	w := &CredentialWatcher{
+		paths: []string{
+			kafkaConfig.BootstrapServerPath, kafkaConfig.CaCertPath, kafkaConfig.ClientCertPath,
+			kafkaConfig.ClientKeyPath,
+		},
+		lastCheck: time.Now(),
+	}
+	w.checksum, _ = w.read()
+	return w
+}
+
+// Changed returns true if the credentials are different from the accepted ones, it only reads the files once in the
+// CredentialCheckInterval, and the credentials which can't be read, e.g. in the middle of the rotation, are ignored
+func (w *CredentialWatcher) Changed() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if time.Since(w.lastCheck) < CredentialCheckInterval {
+		return false
+	}
+	w.lastCheck = time.Now()
+
+	checksum, err := w.read()
+	if err != nil || string(checksum) == string(w.checksum) {
+		return false
+	}
+	w.pendingChecksum = checksum
+	return true
+}
+
+// Accept marks the changed credentials as accepted once the clients are rebuilt with them
+func (w *CredentialWatcher) Accept() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.pendingChecksum != nil {
+		w.checksum, w.pendingChecksum = w.pendingChecksum, nil
+	}
+}
+
+func (w *CredentialWatcher) read() ([]byte, error) {
+	hash := sha256.New()
+	for _, path := range w.paths {
+		if path == "" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Clean(path))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		hash.Write([]byte(path))
+		hash.Write(content)
+	}
+	return hash.Sum(nil), nil
+}
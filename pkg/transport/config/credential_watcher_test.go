@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestCredentialWatcher(t *testing.T) {
+	interval := CredentialCheckInterval
+	CredentialCheckInterval = 0
+	defer func() { CredentialCheckInterval = interval }()
+
+	dir := t.TempDir()
+	kafkaConfig := &transport.KafkaConfig{
+		CaCertPath:     filepath.Join(dir, "ca.crt"),
+		ClientCertPath: filepath.Join(dir, "client.crt"),
+		ClientKeyPath:  filepath.Join(dir, "client.key"),
+	}
+	for _, path := range []string{kafkaConfig.CaCertPath, kafkaConfig.ClientCertPath, kafkaConfig.ClientKeyPath} {
+		if err := os.WriteFile(path, []byte("origin"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := NewCredentialWatcher(kafkaConfig)
+	if watcher.Changed() {
+		t.Errorf("the credentials shouldn't be changed")
+	}
+
+	if err := os.WriteFile(kafkaConfig.ClientCertPath, []byte("rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !watcher.Changed() {
+		t.Errorf("the rotated client certificate should be detected")
+	}
+	// it's reported until the change is accepted
+	if !watcher.Changed() {
+		t.Errorf("the rotated client certificate should be detected before it's accepted")
+	}
+	watcher.Accept()
+	if watcher.Changed() {
+		t.Errorf("the accepted credentials shouldn't be changed")
+	}
+
+	// the bootstrap server is mounted after the watcher is created
+	kafkaConfig.BootstrapServerPath = filepath.Join(dir, "bootstrap_server")
+	watcher = NewCredentialWatcher(kafkaConfig)
+	if err := os.WriteFile(kafkaConfig.BootstrapServerPath, []byte("kafka.example.com:443"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !watcher.Changed() {
+		t.Errorf("the changed bootstrap server should be detected")
+	}
+	watcher.Accept()
+
+	// the interval limits the reading of the credentials
+	CredentialCheckInterval = time.Hour
+	if err := os.WriteFile(kafkaConfig.ClientKeyPath, []byte("rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if watcher.Changed() {
+		t.Errorf("the credentials shouldn't be read again within the interval")
+	}
+}
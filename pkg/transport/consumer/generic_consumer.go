@@ -7,6 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/client"
@@ -18,6 +22,8 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/diagnostics"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
@@ -25,18 +31,46 @@ import (
 
 var transportID string
 
+const activateRetryInterval = 5 * time.Second
+
 type GenericConsumer struct {
 	log                  logr.Logger
+	mutex                sync.RWMutex
 	client               cloudevents.Client
 	assembler            *messageAssembler
 	eventChan            chan *cloudevents.Event
 	consumeTopics        []string
 	clusterIdentity      string
 	enableDatabaseOffset bool
+	// the kafka receiver is paused until the channel is closed, it's only for the warm standby
+	standbyElected <-chan struct{}
+	kafkaProtocol  *kafka_confluent.Protocol
+	// the config map is used to validate the database offsets against the kafka metadata
+	kafkaConfigMap *kafka.ConfigMap
+	// the config map is rebuilt from the kafka config on reconnecting, so the current bootstrap server is connected
+	kafkaConfig *transport.KafkaConfig
+	offsetReset string
+	// the kafka receiver is rebuilt once the mounted credentials are rotated
+	credentialWatcher *config.CredentialWatcher
+	// additional means the consumer isn't of the default kafka cluster, the received events are stamped with the
+	// cluster identity and the offsets are stored under the names qualified by it
+	additional bool
 }
 
 type GenericConsumeOption func(*GenericConsumer) error
 
+// EnableWarmStandby keeps the kafka receiver assigned with the partitions but paused until the elected channel is
+// closed, then it resumes from the offsets in the database if the database offset is enabled.
+func EnableWarmStandby(elected <-chan struct{}) GenericConsumeOption {
+	return func(c *GenericConsumer) error {
+		if c.kafkaProtocol == nil {
+			return fmt.Errorf("the warm standby is only supported by the kafka transport")
+		}
+		c.standbyElected = elected
+		return nil
+	}
+}
+
 func EnableDatabaseOffset(enableOffset bool) GenericConsumeOption {
 	return func(c *GenericConsumer) error {
 		c.enableDatabaseOffset = enableOffset
@@ -44,21 +78,40 @@ func EnableDatabaseOffset(enableOffset bool) GenericConsumeOption {
 	}
 }
 
+// AsAdditionalCluster consumes the additional kafka cluster besides the default one, e.g. the regional brokers of the
+// managed hubs. The identity of the cluster is required to tell its offsets from the ones of the other clusters.
+func AsAdditionalCluster() GenericConsumeOption {
+	return func(c *GenericConsumer) error {
+		if c.clusterIdentity == "" {
+			return fmt.Errorf("the identity of the additional kafka cluster is required")
+		}
+		c.additional = true
+		return nil
+	}
+}
+
 func NewGenericConsumer(tranConfig *transport.TransportConfig, topics []string,
 	opts ...GenericConsumeOption,
 ) (*GenericConsumer, error) {
-	log := ctrl.Log.WithName(fmt.Sprintf("%s-consumer", tranConfig.TransportType))
+	log := ctrl.Log.WithName(logger.TransportSubsystem).WithName(fmt.Sprintf("%s-consumer", tranConfig.TransportType))
 	var receiver interface{}
 	var err error
-	var clusterIdentity string
+	var clusterIdentity, offsetReset string
+	var configMap *kafka.ConfigMap
 	switch tranConfig.TransportType {
 	case string(transport.Kafka):
 		log.Info("transport consumer with cloudevents-kafka receiver")
-		receiver, err = getConfluentReceiverProtocol(tranConfig, topics)
+		configMap, err = config.GetConfluentConfigMap(tranConfig.KafkaConfig, false)
+		if err != nil {
+			return nil, err
+		}
+		receiver, err = kafka_confluent.New(kafka_confluent.WithConfigMap(configMap),
+			kafka_confluent.WithReceiverTopics(topics))
 		if err != nil {
 			return nil, err
 		}
 		clusterIdentity = tranConfig.KafkaConfig.ClusterIdentity
+		offsetReset = tranConfig.KafkaConfig.ConsumerConfig.OffsetReset
 	case string(transport.Chan):
 		log.Info("transport consumer with go chan receiver")
 		if tranConfig.Extends == nil {
@@ -90,14 +143,50 @@ func NewGenericConsumer(tranConfig *transport.TransportConfig, topics []string,
 		assembler:            newMessageAssembler(),
 		enableDatabaseOffset: false,
 		consumeTopics:        topics,
+		kafkaConfigMap:       configMap,
+		kafkaConfig:          tranConfig.KafkaConfig,
+		offsetReset:          offsetReset,
+	}
+	c.kafkaProtocol, _ = receiver.(*kafka_confluent.Protocol)
+	if c.kafkaProtocol != nil {
+		c.credentialWatcher = config.NewCredentialWatcher(tranConfig.KafkaConfig)
 	}
 	if err := c.applyOptions(opts...); err != nil {
 		return nil, err
 	}
-	transportID = clusterIdentity
+	if !c.additional {
+		transportID = clusterIdentity
+	}
+	diagnosticsName := "kafka-consumer"
+	if c.additional {
+		diagnosticsName = fmt.Sprintf("%s.%s", diagnosticsName, clusterIdentity)
+	}
+	diagnostics.Register(diagnosticsName, func(ctx context.Context) (interface{}, error) {
+		return c.Assignment()
+	})
 	return c, nil
 }
 
+// Assignment returns the partitions assigned to the consumer keyed by the topics, it's empty for the go chan transport
+func (c *GenericConsumer) Assignment() (map[string][]int32, error) {
+	assignment := map[string][]int32{}
+	protocol := c.currentProtocol()
+	if protocol == nil {
+		return assignment, nil
+	}
+	partitions, err := protocol.Assignment()
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range partitions {
+		if partition.Topic == nil {
+			continue
+		}
+		assignment[*partition.Topic] = append(assignment[*partition.Topic], partition.Partition)
+	}
+	return assignment, nil
+}
+
 func (c *GenericConsumer) applyOptions(opts ...GenericConsumeOption) error {
 	for _, fn := range opts {
 		if err := fn(c); err != nil {
@@ -108,9 +197,71 @@ func (c *GenericConsumer) applyOptions(opts ...GenericConsumeOption) error {
 }
 
 func (c *GenericConsumer) Start(ctx context.Context) error {
+	// the offsets of the other clusters are unknown to the additional one, so they're only compacted by the default one
+	if c.enableDatabaseOffset && c.kafkaConfigMap != nil && !c.additional {
+		go c.compactOffsetsPeriodically(ctx)
+	}
+	if c.standbyElected != nil {
+		go c.activate(ctx)
+	}
+	if c.credentialWatcher == nil {
+		return c.receive(ctx)
+	}
+
+	ticker := time.NewTicker(config.CredentialCheckInterval)
+	defer ticker.Stop()
+	for {
+		receiveCtx, cancel := context.WithCancel(ctx)
+		stopped := make(chan error, 1)
+		go func() { stopped <- c.receive(receiveCtx) }()
+
+		changed := false
+		for !changed {
+			select {
+			case err := <-stopped:
+				cancel()
+				return err
+			case <-ticker.C:
+				changed = c.credentialWatcher.Changed()
+			}
+		}
+
+		// the receiver closes the kafka consumer once it's stopped, so it's rebuilt before receiving again
+		c.log.Info("the transport credentials are changed, reconnect the consumer")
+		cancel()
+		if err := <-stopped; err != nil {
+			c.log.Error(err, "failed to stop the receiver")
+		}
+		for {
+			err := c.reconnect()
+			if err == nil {
+				c.credentialWatcher.Accept()
+				break
+			}
+			c.log.Error(err, "failed to reconnect the consumer with the rotated credentials, retrying")
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// receive starts the receiver until the context is done, the standby receiver is paused until it's elected, it's
+// resumed right away if the receiver is rebuilt after the election
+func (c *GenericConsumer) receive(ctx context.Context) error {
 	receiveContext := ctx
-	if c.enableDatabaseOffset {
-		offsets, err := getInitOffset(c.clusterIdentity)
+	if c.standbyElected != nil {
+		protocol := c.currentProtocol()
+		protocol.Standby()
+		if c.elected() {
+			if err := protocol.Activate(c.activateOffsets()); err != nil {
+				return err
+			}
+		}
+	} else if c.enableDatabaseOffset {
+		offsets, err := c.initOffsets()
 		if err != nil {
 			return err
 		}
@@ -120,23 +271,27 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 		}
 	}
 
-	err := c.client.StartReceiver(receiveContext, func(ctx context.Context, event cloudevents.Event) ceprotocol.Result {
-		c.log.V(2).Info("received message", "event.Source", event.Source(), "event.Type", event.Type())
+	err := c.currentClient().StartReceiver(receiveContext,
+		func(ctx context.Context, event cloudevents.Event) ceprotocol.Result {
+			c.log.V(2).Info("received message", "event.Source", event.Source(), "event.Type", event.Type())
+			if c.additional {
+				event.SetExtension(kafka_confluent.KafkaClusterKey, c.clusterIdentity)
+			}
 
-		chunk, isChunk := c.assembler.messageChunk(event)
-		if !isChunk {
-			c.eventChan <- &event
-			return ceprotocol.ResultACK
-		}
-		if payload := c.assembler.assemble(chunk); payload != nil {
-			if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
-				c.log.Error(err, "failed the set the assembled data to event")
-			} else {
+			chunk, isChunk := c.assembler.messageChunk(event)
+			if !isChunk {
 				c.eventChan <- &event
+				return ceprotocol.ResultACK
 			}
-		}
-		return ceprotocol.ResultACK
-	})
+			if payload := c.assembler.assemble(chunk); payload != nil {
+				if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+					c.log.Error(err, "failed the set the assembled data to event")
+				} else {
+					c.eventChan <- &event
+				}
+			}
+			return ceprotocol.ResultACK
+		})
 	if err != nil {
 		return fmt.Errorf("failed to start Receiver: %w", err)
 	}
@@ -144,6 +299,95 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 	return nil
 }
 
+// reconnect rebuilds the kafka receiver with the current credentials and bootstrap server, the config map refers to
+// the paths of the credentials, and it's rebuilt with the bootstrap server read from its path
+func (c *GenericConsumer) reconnect() error {
+	configMap, err := config.GetConfluentConfigMap(c.kafkaConfig, false)
+	if err != nil {
+		return err
+	}
+	protocol, err := kafka_confluent.New(kafka_confluent.WithConfigMap(configMap),
+		kafka_confluent.WithReceiverTopics(c.consumeTopics))
+	if err != nil {
+		return err
+	}
+	receiverClient, err := cloudevents.NewClient(protocol, client.WithPollGoroutines(1))
+	if err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.client, c.kafkaProtocol, c.kafkaConfigMap = receiverClient, protocol, configMap
+	return nil
+}
+
+func (c *GenericConsumer) currentClient() cloudevents.Client {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.client
+}
+
+func (c *GenericConsumer) currentConfigMap() *kafka.ConfigMap {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.kafkaConfigMap
+}
+
+func (c *GenericConsumer) currentProtocol() *kafka_confluent.Protocol {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.kafkaProtocol
+}
+
+func (c *GenericConsumer) elected() bool {
+	select {
+	case <-c.standbyElected:
+		return true
+	default:
+		return false
+	}
+}
+
+// activate resumes the standby receiver once it's elected, the offsets are loaded at that moment so that it
+// continues from the last offsets committed by the previous leader
+func (c *GenericConsumer) activate(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-c.standbyElected:
+	}
+
+	offsets := c.activateOffsets()
+	c.log.Info("activate the standby consumer", "offsets", offsets)
+	for {
+		err := c.currentProtocol().Activate(offsets)
+		if err == nil {
+			return
+		}
+		c.log.Error(err, "failed to activate the standby consumer, retrying")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(activateRetryInterval):
+		}
+	}
+}
+
+// activateOffsets returns the offsets in the database to resume the standby receiver, or empty to resume from the
+// committed offsets
+func (c *GenericConsumer) activateOffsets() []kafka.TopicPartition {
+	offsets := []kafka.TopicPartition{}
+	if c.enableDatabaseOffset {
+		initOffsets, err := c.initOffsets()
+		if err != nil {
+			c.log.Error(err, "failed to get the offsets from database, resume from the committed offsets")
+		} else {
+			offsets = initOffsets
+		}
+	}
+	return offsets
+}
+
 func (c *GenericConsumer) EventChan() chan *cloudevents.Event {
 	return c.eventChan
 }
@@ -158,21 +402,49 @@ func getInitOffset(kafkaClusterIdentity string) ([]kafka.TopicPartition, error)
 		return nil, err
 	}
 	offsetToStart := []kafka.TopicPartition{}
-	for i, pos := range positions {
+	for _, pos := range positions {
 		var kafkaPosition transport.EventPosition
 		err := json.Unmarshal(pos.Payload, &kafkaPosition)
 		if err != nil {
 			return nil, err
 		}
+		// the name of the offset is qualified by the cluster identity if it's from the additional kafka cluster
+		topic := strings.TrimSuffix(pos.Name, "@"+kafkaClusterIdentity)
 		offsetToStart = append(offsetToStart, kafka.TopicPartition{
-			Topic:     &positions[i].Name,
-			Partition: kafkaPosition.Partition,
+			Topic:     &topic,
+			Partition: pos.Partition,
 			Offset:    kafka.Offset(kafkaPosition.Offset),
 		})
 	}
 	return offsetToStart, nil
 }
 
+// filterOffsets keeps the offsets of the topics which are consumed, the consumed topic might be a regex(start with
+// "^") like "^status.*", or a subset of the status topics when the hubs are sharded across the manager replicas
+func filterOffsets(offsets []kafka.TopicPartition, topics []string) []kafka.TopicPartition {
+	filtered := []kafka.TopicPartition{}
+	for _, offset := range offsets {
+		if isConsumed(*offset.Topic, topics) {
+			filtered = append(filtered, offset)
+		}
+	}
+	return filtered
+}
+
+// isConsumed returns true if the topic is any of the consumed topics or matches any of the regexes
+func isConsumed(name string, topics []string) bool {
+	for _, topic := range topics {
+		matched := topic == name
+		if !matched && strings.HasPrefix(topic, "^") {
+			matched, _ = regexp.MatchString(topic, name)
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
 // func getSaramaReceiverProtocol(transportConfig *transport.TransportConfig) (interface{}, error) {
 // 	saramaConfig, err := config.GetSaramaConfig(transportConfig.KafkaConfig)
 // 	if err != nil {
@@ -187,16 +459,16 @@ func getInitOffset(kafkaClusterIdentity string) ([]kafka.TopicPartition, error)
 // 		transportConfig.KafkaConfig.ConsumerConfig.ConsumerTopic)
 // }
 
-func getConfluentReceiverProtocol(transportConfig *transport.TransportConfig, topics []string) (interface{}, error) {
-	configMap, err := config.GetConfluentConfigMap(transportConfig.KafkaConfig, false)
-	if err != nil {
-		return nil, err
-	}
-
-	return kafka_confluent.New(kafka_confluent.WithConfigMap(configMap),
-		kafka_confluent.WithReceiverTopics(topics))
-}
-
+// TransportID returns the identity of the default kafka cluster
 func TransportID() string {
 	return transportID
 }
+
+// OffsetName returns the name of the position stored in the database, the positions of the additional kafka clusters
+// are qualified by their identities, so they don't conflict with the same topics of the default cluster
+func OffsetName(position *transport.EventPosition) string {
+	if position.OwnerIdentity == "" || position.OwnerIdentity == transportID {
+		return position.Topic
+	}
+	return fmt.Sprintf("%s@%s", position.Topic, position.OwnerIdentity)
+}
@@ -49,6 +49,10 @@ func TestGetInitOffset(t *testing.T) {
 	databaseTransports = append(databaseTransports, generateTransport(kafkaClusterIdentity, "status.hub1", 12))
 	databaseTransports = append(databaseTransports, generateTransport(kafkaClusterIdentity, "status.hub2", 11))
 	databaseTransports = append(databaseTransports, generateTransport(kafkaClusterIdentity, "status", 9))
+	// the partitions of the same topic are stored separately
+	partition1 := generateTransport(kafkaClusterIdentity, "status", 10)
+	partition1.Partition = 1
+	databaseTransports = append(databaseTransports, partition1)
 	databaseTransports = append(databaseTransports, generateTransport(kafkaClusterIdentity, "spec", 9))
 	databaseTransports = append(databaseTransports, generateTransport("", "status.hub3", 8))
 	databaseTransports = append(databaseTransports, generateTransport("another", "status.hub4", 7))
@@ -69,7 +73,7 @@ func TestGetInitOffset(t *testing.T) {
 		}
 		count++
 	}
-	assert.Equal(t, 3, count)
+	assert.Equal(t, 4, count)
 }
 
 func generateTransport(ownerIdentity string, topic string, offset int64) models.Transport {
@@ -84,3 +88,23 @@ func generateTransport(ownerIdentity string, topic string, offset int64) models.
 		Payload: payload,
 	}
 }
+
+func TestFilterOffsets(t *testing.T) {
+	topics := []string{"status", "status.hub1", "status.hub2", "event"}
+	offsets := []kafka.TopicPartition{}
+	for i := range topics {
+		offsets = append(offsets, kafka.TopicPartition{Topic: &topics[i], Offset: kafka.Offset(i)})
+	}
+
+	filteredTopics := func(consumeTopics []string) []string {
+		names := []string{}
+		for _, offset := range filterOffsets(offsets, consumeTopics) {
+			names = append(names, *offset.Topic)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"status", "event"}, filteredTopics([]string{"event", "status"}))
+	assert.Equal(t, []string{"status.hub1", "status.hub2"}, filteredTopics([]string{"^status\\..*"}))
+	assert.Equal(t, []string{"status.hub2"}, filteredTopics([]string{"status.hub2"}))
+}
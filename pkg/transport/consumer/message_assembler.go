@@ -13,6 +13,7 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -83,7 +84,7 @@ type messageAssembler struct {
 
 func newMessageAssembler() *messageAssembler {
 	return &messageAssembler{
-		log:                ctrl.Log.WithName("consumer-assembler"),
+		log:                ctrl.Log.WithName(logger.TransportSubsystem).WithName("consumer-assembler"),
 		lock:               sync.Mutex{},
 		chunkCollectionMap: make(map[string]*messageChunksCollection),
 	}
@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// renameMargin is subtracted from the time the position of the renamed topic is committed, so the events sent to the
+// new topic around the commit are consumed again rather than skipped
+const renameMargin = time.Minute
+
+// renamedPosition is the position committed to the topic which is renamed by the naming scheme of the release
+type renamedPosition struct {
+	topic       string
+	committedAt time.Time
+}
+
+// renamedPositions returns the positions of the topics which aren't consumed any more, but are renamed to the other
+// topics by the renames
+func renamedPositions(clusterIdentity string, topics []string, renames []transport.TopicRename,
+) ([]renamedPosition, error) {
+	db := database.GetGorm()
+	var positions []models.Transport
+	if err := db.Where("payload->>'ownerIdentity' = ?", clusterIdentity).Find(&positions).Error; err != nil {
+		return nil, err
+	}
+	renamed := []renamedPosition{}
+	for _, pos := range positions {
+		var kafkaPosition transport.EventPosition
+		if err := json.Unmarshal(pos.Payload, &kafkaPosition); err != nil {
+			return nil, err
+		}
+		topic := pos.Name
+		if kafkaPosition.Topic != "" {
+			topic = kafkaPosition.Topic
+		}
+		if isConsumed(topic, topics) || !isRenamed(topic, renames) {
+			continue
+		}
+		renamed = append(renamed, renamedPosition{topic: topic, committedAt: pos.UpdatedAt})
+	}
+	return renamed, nil
+}
+
+func isRenamed(topic string, renames []transport.TopicRename) bool {
+	for _, rename := range renames {
+		if _, ok := transport.HubOfTopic(rename.From, topic); ok || rename.From == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// renamedTopics returns the existing topics which the topic is renamed to. The topic of the hub is renamed to the topic
+// of the same hub, and the shared topic is renamed to the topics of all the hubs if it's split.
+func renamedTopics(topic string, renames []transport.TopicRename, existing []string) []string {
+	renamed := map[string]bool{}
+	for _, rename := range renames {
+		hubName, ok := transport.HubOfTopic(rename.From, topic)
+		if !ok && rename.From != topic {
+			continue
+		}
+		for _, name := range existing {
+			if name != topic && renamedTo(rename.To, hubName, name) {
+				renamed[name] = true
+			}
+		}
+	}
+	topics := []string{}
+	for name := range renamed {
+		topics = append(topics, name)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// renamedTo returns true if the topic is the target of the rename, the hub name is empty if the renamed topic is shared
+func renamedTo(to, hubName, topic string) bool {
+	switch {
+	case !transport.IsTopicTemplate(to):
+		return topic == to
+	case hubName != "":
+		return topic == transport.RenderTopic(to, hubName)
+	default:
+		_, ok := transport.HubOfTopic(to, topic)
+		return ok
+	}
+}
+
+// migrateRenamedOffsets returns the offsets of the topics renamed from the positions, which start from the events
+// sent since the positions are committed. The topics with the offsets are skipped, since they're consumed already.
+func migrateRenamedOffsets(adminClient *kafka.AdminClient, positions []renamedPosition,
+	offsets []kafka.TopicPartition, renames []transport.TopicRename,
+) ([]kafka.TopicPartition, error) {
+	metadata, err := adminClient.GetMetadata(nil, true, metadataTimeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kafka metadata: %w", err)
+	}
+	existing := []string{}
+	for name := range metadata.Topics {
+		existing = append(existing, name)
+	}
+	consumed := map[string]bool{}
+	for _, offset := range offsets {
+		consumed[*offset.Topic] = true
+	}
+
+	// the new topic starts from the earliest commit of the topics renamed to it, so none of the events are skipped
+	since := map[string]time.Time{}
+	for _, pos := range positions {
+		for _, name := range renamedTopics(pos.topic, renames, existing) {
+			if consumed[name] {
+				continue
+			}
+			if committedAt, ok := since[name]; !ok || pos.committedAt.Before(committedAt) {
+				since[name] = pos.committedAt
+			}
+		}
+	}
+
+	timeSpecs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	latestSpecs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	for name, committedAt := range since {
+		topic := name
+		for _, partition := range metadata.Topics[name].Partitions {
+			key := kafka.TopicPartition{Topic: &topic, Partition: partition.ID}
+			timeSpecs[key] = kafka.NewOffsetSpecForTimestamp(committedAt.Add(-renameMargin).UnixMilli())
+			latestSpecs[key] = kafka.LatestOffsetSpec
+		}
+	}
+	if len(timeSpecs) == 0 {
+		return nil, nil
+	}
+	byTime, err := listOffsets(adminClient, timeSpecs)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := listOffsets(adminClient, latestSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated := []kafka.TopicPartition{}
+	for partition := range timeSpecs {
+		key := fmt.Sprintf("%s@%d", *partition.Topic, partition.Partition)
+		offset, found := byTime[key]
+		// none of the events are sent to the partition since the time
+		if !found || offset < 0 {
+			offset = latest[key]
+		}
+		partition.Offset = offset
+		migrated = append(migrated, partition)
+	}
+	sort.Slice(migrated, func(i, j int) bool {
+		if *migrated[i].Topic != *migrated[j].Topic {
+			return *migrated[i].Topic < *migrated[j].Topic
+		}
+		return migrated[i].Partition < migrated[j].Partition
+	})
+	return migrated, nil
+}
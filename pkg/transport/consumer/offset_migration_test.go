@@ -0,0 +1,77 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestRenamedTopics(t *testing.T) {
+	renames := []transport.TopicRename{
+		{From: "status", To: "status.{hub}"},
+		{From: "acme.{hub}.status", To: "acme.status.{hub}"},
+	}
+	existing := []string{"spec", "status", "status.hub1", "status.hub2", "acme.status.hub1", "acme.status.hub2"}
+
+	// the shared topic is split to the topics of all the hubs
+	assert.Equal(t, []string{"status.hub1", "status.hub2"}, renamedTopics("status", renames, existing))
+	// the topic of the hub is renamed to the topic of the same hub
+	assert.Equal(t, []string{"acme.status.hub1"}, renamedTopics("acme.hub1.status", renames, existing))
+	// the new topic of the hub isn't created yet
+	assert.Empty(t, renamedTopics("acme.hub3.status", renames, existing))
+	assert.Empty(t, renamedTopics("spec", renames, existing))
+
+	assert.True(t, isRenamed("acme.hub3.status", renames))
+	assert.False(t, isRenamed("status.hub1", renames))
+}
+
+func TestMigrateRenamedOffsets(t *testing.T) {
+	mockCluster, err := kafka.NewMockCluster(1)
+	require.NoError(t, err)
+	defer mockCluster.Close()
+
+	configMap := &kafka.ConfigMap{"bootstrap.servers": mockCluster.BootstrapServers()}
+	adminClient, err := kafka.NewAdminClient(configMap)
+	require.NoError(t, err)
+	defer adminClient.Close()
+
+	producer, err := kafka.NewProducer(configMap)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	committedAt := time.Now().Add(-time.Hour)
+	newTopic, consumedTopic := "status.hub1", "status.hub2"
+	for _, topic := range []string{newTopic, consumedTopic} {
+		require.NoError(t, mockCluster.CreateTopic(topic, 2, 1))
+	}
+	for i := 0; i < 3; i++ {
+		err = producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &newTopic, Partition: 0},
+			Value:          []byte("message"),
+			Timestamp:      committedAt.Add(-time.Hour),
+		}, nil)
+		require.NoError(t, err)
+	}
+	producer.Flush(10000)
+
+	renames := []transport.TopicRename{{From: "status", To: "status.{hub}"}}
+	positions := []renamedPosition{{topic: "status", committedAt: committedAt}}
+	offsets := []kafka.TopicPartition{{Topic: &consumedTopic, Partition: 0, Offset: 0}}
+	migrated, err := migrateRenamedOffsets(adminClient, positions, offsets, renames)
+	require.NoError(t, err)
+	// the consumed topic keeps its offset, the partitions of the new topic are migrated
+	require.Len(t, migrated, 2)
+	for i, partition := range migrated {
+		assert.Equal(t, newTopic, *partition.Topic)
+		assert.Equal(t, int32(i), partition.Partition)
+	}
+	// none of the events are sent since the commit, the mock cluster doesn't look up the offsets by the time either,
+	// so they start from the latest offsets
+	assert.Equal(t, kafka.Offset(3), migrated[0].Offset)
+	assert.Equal(t, kafka.Offset(0), migrated[1].Offset)
+}
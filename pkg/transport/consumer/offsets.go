@@ -0,0 +1,237 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	metadataTimeoutMs     = 10000
+	listOffsetsTimeout    = 10 * time.Second
+	offsetCompactInterval = 1 * time.Hour
+)
+
+// initOffsets loads the offsets of the consumed topics from the database, then they're validated against the kafka
+// metadata. The offsets of the removed topics are dropped, and the out of range offsets, e.g. the topic is recreated,
+// are reset by the offset reset policy. The offsets of the topics renamed by the upgrade are migrated from the
+// positions of the old topics.
+func (c *GenericConsumer) initOffsets() ([]kafka.TopicPartition, error) {
+	storedOffsets, err := getInitOffset(c.clusterIdentity)
+	if err != nil {
+		return nil, err
+	}
+	offsets := filterOffsets(storedOffsets, c.consumeTopics)
+	if c.kafkaConfigMap == nil {
+		return offsets, nil
+	}
+	renamed, err := renamedPositions(c.clusterIdentity, c.consumeTopics, transport.TopicRenames)
+	if err != nil {
+		c.log.Error(err, "failed to get the positions of the renamed topics")
+	}
+	if len(offsets) == 0 && len(renamed) == 0 {
+		return offsets, nil
+	}
+
+	adminClient, err := kafka.NewAdminClient(c.currentConfigMap())
+	if err != nil {
+		c.log.Error(err, "failed to validate the offsets, start from them as they are")
+		return offsets, nil
+	}
+	defer adminClient.Close()
+
+	// the topics renamed by the upgrade start from the events sent since the positions of the old topics, rather
+	// than being reset by the offset reset policy
+	if len(renamed) > 0 {
+		migrated, err := migrateRenamedOffsets(adminClient, renamed, storedOffsets, transport.TopicRenames)
+		if err != nil {
+			c.log.Error(err, "failed to migrate the offsets of the renamed topics")
+		}
+		for _, offset := range filterOffsets(migrated, c.consumeTopics) {
+			c.log.Info("migrate the offset of the renamed topic", "topic", *offset.Topic, "partition",
+				offset.Partition, "offset", offset.Offset)
+			offsets = append(offsets, offset)
+		}
+	}
+	if len(offsets) == 0 {
+		return offsets, nil
+	}
+
+	validOffsets, err := validateOffsets(adminClient, offsets, c.offsetReset)
+	if err != nil {
+		c.log.Error(err, "failed to validate the offsets, start from them as they are")
+		return offsets, nil
+	}
+	for _, offset := range offsets {
+		if valid := findOffset(validOffsets, *offset.Topic, offset.Partition); valid == nil {
+			c.log.Info("drop the offset of the removed topic", "topic", *offset.Topic, "partition", offset.Partition)
+		} else if valid.Offset != offset.Offset {
+			c.log.Info("reset the out of range offset", "topic", *offset.Topic, "partition", offset.Partition,
+				"offset", offset.Offset, "reset", valid.Offset, "policy", c.offsetReset)
+		}
+	}
+	return validOffsets, nil
+}
+
+// validateOffsets returns the offsets which are within the range of the current topic partitions. The offset of the
+// removed topic or partition is dropped, and the out of range offset is reset to the earliest or latest offset.
+func validateOffsets(adminClient *kafka.AdminClient, offsets []kafka.TopicPartition, offsetReset string,
+) ([]kafka.TopicPartition, error) {
+	metadata, err := adminClient.GetMetadata(nil, true, metadataTimeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the kafka metadata: %w", err)
+	}
+
+	existing := []kafka.TopicPartition{}
+	earliestSpecs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	latestSpecs := map[kafka.TopicPartition]kafka.OffsetSpec{}
+	for _, offset := range offsets {
+		if !partitionExists(metadata, *offset.Topic, offset.Partition) {
+			continue
+		}
+		existing = append(existing, offset)
+		key := kafka.TopicPartition{Topic: offset.Topic, Partition: offset.Partition}
+		earliestSpecs[key] = kafka.EarliestOffsetSpec
+		latestSpecs[key] = kafka.LatestOffsetSpec
+	}
+	if len(existing) == 0 {
+		return existing, nil
+	}
+
+	earliest, err := listOffsets(adminClient, earliestSpecs)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := listOffsets(adminClient, latestSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	validOffsets := []kafka.TopicPartition{}
+	for _, offset := range existing {
+		key := fmt.Sprintf("%s@%d", *offset.Topic, offset.Partition)
+		offset.Offset = resolveOffset(offset.Offset, earliest[key], latest[key], offsetReset)
+		validOffsets = append(validOffsets, offset)
+	}
+	return validOffsets, nil
+}
+
+// resolveOffset returns the offset if it's within the range [low, high], otherwise it's reset by the policy
+func resolveOffset(offset, low, high kafka.Offset, offsetReset string) kafka.Offset {
+	if offset >= low && offset <= high {
+		return offset
+	}
+	if offsetReset == transport.OffsetResetLatest {
+		return high
+	}
+	return low
+}
+
+// listOffsets returns the offsets of the partitions, the key is "topic@partition"
+func listOffsets(adminClient *kafka.AdminClient, specs map[kafka.TopicPartition]kafka.OffsetSpec,
+) (map[string]kafka.Offset, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), listOffsetsTimeout)
+	defer cancel()
+	result, err := adminClient.ListOffsets(ctx, specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the kafka offsets: %w", err)
+	}
+	offsets := map[string]kafka.Offset{}
+	for partition, info := range result.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return nil, fmt.Errorf("failed to list the offset of %s: %w", partition, info.Error)
+		}
+		offsets[fmt.Sprintf("%s@%d", *partition.Topic, partition.Partition)] = info.Offset
+	}
+	return offsets, nil
+}
+
+// compactOffsets deletes the stale offsets from the database: the offsets committed by a replaced kafka cluster, and the
+// offsets of the topics or the partitions which are removed from the current kafka cluster. Only the offsets which
+// aren't updated since the metadata is retrieved are deleted, so that the offset of a topic created in the meantime is
+// kept.
+func compactOffsets(adminClient *kafka.AdminClient, clusterIdentity string) (int64, error) {
+	retrievedAt := time.Now()
+	metadata, err := adminClient.GetMetadata(nil, true, metadataTimeoutMs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the kafka metadata: %w", err)
+	}
+
+	db := database.GetGorm()
+	stale := db.Where("payload->>'ownerIdentity' <> ?", clusterIdentity)
+	if partitions := existingPartitions(metadata); len(partitions) > 0 {
+		stale = stale.Or("(name, partition) NOT IN ?", partitions)
+	}
+	// the offsets of the additional kafka clusters are qualified by their identities, they're kept as they are
+	result := db.Where("name ~ ?", "^status").Where("name NOT LIKE ?", "%@%").Where("updated_at < ?", retrievedAt).
+		Where(stale).Delete(&models.Transport{})
+	return result.RowsAffected, result.Error
+}
+
+// compactOffsetsPeriodically keeps the offset table compact while the consumer is running, e.g. the topics of the
+// detached hubs are removed
+func (c *GenericConsumer) compactOffsetsPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(offsetCompactInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			adminClient, err := kafka.NewAdminClient(c.currentConfigMap())
+			if err != nil {
+				c.log.Error(err, "failed to create the admin client to compact the offsets")
+				continue
+			}
+			deleted, err := compactOffsets(adminClient, c.clusterIdentity)
+			adminClient.Close()
+			if err != nil {
+				c.log.Error(err, "failed to compact the offsets")
+			} else if deleted > 0 {
+				c.log.Info("compact the stale offsets", "deleted", deleted)
+			}
+		}
+	}
+}
+
+// existingPartitions returns the (topic, partition) pairs of the kafka cluster
+func existingPartitions(metadata *kafka.Metadata) [][]interface{} {
+	partitions := [][]interface{}{}
+	for topic, topicMetadata := range metadata.Topics {
+		for _, partitionMetadata := range topicMetadata.Partitions {
+			partitions = append(partitions, []interface{}{topic, partitionMetadata.ID})
+		}
+	}
+	return partitions
+}
+
+func partitionExists(metadata *kafka.Metadata, topic string, partition int32) bool {
+	topicMetadata, found := metadata.Topics[topic]
+	if !found || topicMetadata.Error.Code() != kafka.ErrNoError {
+		return false
+	}
+	for _, partitionMetadata := range topicMetadata.Partitions {
+		if partitionMetadata.ID == partition {
+			return true
+		}
+	}
+	return false
+}
+
+func findOffset(offsets []kafka.TopicPartition, topic string, partition int32) *kafka.TopicPartition {
+	for i := range offsets {
+		if *offsets[i].Topic == topic && offsets[i].Partition == partition {
+			return &offsets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestResolveOffset(t *testing.T) {
+	cases := []struct {
+		name        string
+		offset      kafka.Offset
+		offsetReset string
+		expected    kafka.Offset
+	}{
+		{"within the range", 15, transport.OffsetResetEarliest, 15},
+		{"the latest offset", 20, transport.OffsetResetEarliest, 20},
+		{"before the earliest offset", 5, transport.OffsetResetLatest, 20},
+		{"after the latest offset with earliest policy", 100, transport.OffsetResetEarliest, 10},
+		{"after the latest offset with latest policy", 100, transport.OffsetResetLatest, 20},
+		{"after the latest offset without policy", 100, "", 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, resolveOffset(c.offset, 10, 20, c.offsetReset))
+		})
+	}
+}
+
+func TestValidateOffsets(t *testing.T) {
+	mockCluster, err := kafka.NewMockCluster(1)
+	require.NoError(t, err)
+	defer mockCluster.Close()
+
+	configMap := &kafka.ConfigMap{"bootstrap.servers": mockCluster.BootstrapServers()}
+	adminClient, err := kafka.NewAdminClient(configMap)
+	require.NoError(t, err)
+	defer adminClient.Close()
+
+	topic := "status.hub1"
+	require.NoError(t, mockCluster.CreateTopic(topic, 1, 1))
+
+	producer, err := kafka.NewProducer(configMap)
+	require.NoError(t, err)
+	defer producer.Close()
+	for i := 0; i < 5; i++ {
+		err = producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 0},
+			Value:          []byte("message"),
+		}, nil)
+		require.NoError(t, err)
+	}
+	producer.Flush(10000)
+
+	removedTopic := "status.hub2"
+	offsets := []kafka.TopicPartition{
+		{Topic: &topic, Partition: 0, Offset: 3},
+		{Topic: &topic, Partition: 1, Offset: 3},
+		{Topic: &removedTopic, Partition: 0, Offset: 3},
+	}
+	validOffsets, err := validateOffsets(adminClient, offsets, transport.OffsetResetEarliest)
+	require.NoError(t, err)
+	require.Len(t, validOffsets, 1)
+	assert.Equal(t, kafka.Offset(3), validOffsets[0].Offset)
+
+	// the topic is recreated, so the stored offset is beyond the latest offset
+	offsets = []kafka.TopicPartition{{Topic: &topic, Partition: 0, Offset: 100}}
+	validOffsets, err = validateOffsets(adminClient, offsets, transport.OffsetResetEarliest)
+	require.NoError(t, err)
+	assert.Equal(t, kafka.Offset(0), validOffsets[0].Offset)
+
+	validOffsets, err = validateOffsets(adminClient, offsets, transport.OffsetResetLatest)
+	require.NoError(t, err)
+	assert.Equal(t, kafka.Offset(5), validOffsets[0].Offset)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 )
@@ -30,7 +31,7 @@ type saramaConsumer struct {
 func NewSaramaConsumer(ctx context.Context, kafkaConfig *transport.KafkaConfig,
 	topics []string,
 ) (SaramaConsumer, error) {
-	log := ctrl.Log.WithName("sarama-consumer")
+	log := ctrl.Log.WithName(logger.TransportSubsystem).WithName("sarama-consumer")
 	saramaConfig, err := config.GetSaramaConfig(kafkaConfig)
 	if err != nil {
 		return nil, err
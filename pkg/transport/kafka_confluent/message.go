@@ -22,6 +22,9 @@ const (
 	KafkaPartitionKey = "kafkapartition"
 	KafkaTopicKey     = "kafkatopic"
 	KafkaMessageKey   = "kafkamessagekey"
+	// KafkaClusterKey is the identity of the kafka cluster the message is received from, it's only set by the consumer
+	// of the additional kafka cluster
+	KafkaClusterKey = "kafkacluster"
 )
 
 var specs = spec.WithPrefix(prefix)
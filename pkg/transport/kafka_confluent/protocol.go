@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
@@ -38,6 +39,12 @@ type Protocol struct {
 	consumerCtx          context.Context
 	consumerCancel       context.CancelFunc
 
+	// the standby receiver assigns the partitions to itself and pauses them until it's activated
+	standby        bool
+	standbyPaused  bool
+	standbyOffsets map[string]kafka.Offset
+	standbyMux     sync.Mutex
+
 	producer             *kafka.Producer
 	producerDeliveryChan chan kafka.Event // optional
 	producerDefaultTopic string           // optional
@@ -189,17 +196,30 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 	defer p.consumerMux.Unlock()
 	logger := cecontext.LoggerFrom(ctx)
 
-	// Query committed offsets for each partition
-	if positions := TopicPartitionOffsetsFrom(ctx); positions != nil {
-		if err := p.consumer.Assign(positions); err != nil {
+	var err error
+	var refreshChan <-chan time.Time
+	if p.standby {
+		logger.Infof("Assigning the partitions of the topics to the standby receiver: %v", p.consumerTopics)
+		if err = p.assignPartitions(); err != nil {
 			return err
 		}
-	}
+		// the standby receiver isn't in the consumer group, so the partitions of the new topics are assigned by itself
+		refreshTicker := time.NewTicker(standbyRefreshInterval)
+		defer refreshTicker.Stop()
+		refreshChan = refreshTicker.C
+	} else {
+		// Query committed offsets for each partition
+		if positions := TopicPartitionOffsetsFrom(ctx); positions != nil {
+			if err := p.consumer.Assign(positions); err != nil {
+				return err
+			}
+		}
 
-	logger.Infof("Subscribing to topics: %v", p.consumerTopics)
-	err := p.consumer.SubscribeTopics(p.consumerTopics, p.consumerRebalanceCb)
-	if err != nil {
-		return err
+		logger.Infof("Subscribing to topics: %v", p.consumerTopics)
+		err = p.consumer.SubscribeTopics(p.consumerTopics, p.consumerRebalanceCb)
+		if err != nil {
+			return err
+		}
 	}
 
 	p.closerMux.Lock()
@@ -221,6 +241,10 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 		select {
 		case <-p.consumerCtx.Done():
 			return p.consumerCtx.Err()
+		case <-refreshChan:
+			if err := p.assignPartitions(); err != nil {
+				logger.Errorf("failed to assign the partitions of the new topics: %v", err)
+			}
 		default:
 			ev := p.consumer.Poll(p.consumerPollTimeout)
 			if ev == nil {
@@ -259,6 +283,14 @@ func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
 	}
 }
 
+// Assignment returns the partitions currently assigned to the consumer, it's empty if the protocol isn't a receiver
+func (p *Protocol) Assignment() ([]kafka.TopicPartition, error) {
+	if p.consumer == nil {
+		return nil, nil
+	}
+	return p.consumer.Assignment()
+}
+
 // Close cleans up resources after use. Must be called to properly close underlying Kafka resources and avoid resource leaks
 func (p *Protocol) Close(ctx context.Context) error {
 	p.closerMux.Lock()
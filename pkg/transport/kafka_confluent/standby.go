@@ -0,0 +1,149 @@
+package kafka_confluent
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+const (
+	standbyRefreshInterval = 30 * time.Second
+	metadataTimeoutMs      = 10000
+)
+
+// Standby makes the receiver assign all the partitions of the topics to itself without joining the consumer group,
+// and pause them until it's activated. So the receiver is able to take over the topics in no time, instead of
+// rejoining the consumer group. It must be invoked before opening the receiver.
+func (p *Protocol) Standby() {
+	p.standbyMux.Lock()
+	defer p.standbyMux.Unlock()
+	p.standby = true
+	p.standbyPaused = true
+}
+
+// Activate resumes the partitions of the standby receiver, the partitions start from the given offsets, or the
+// committed offsets of the consumer group if they aren't given.
+func (p *Protocol) Activate(offsets []kafka.TopicPartition) error {
+	p.standbyMux.Lock()
+	defer p.standbyMux.Unlock()
+	if !p.standby {
+		return errors.New("the receiver isn't in the standby mode")
+	}
+	if !p.standbyPaused {
+		return nil
+	}
+
+	p.standbyOffsets = map[string]kafka.Offset{}
+	for _, offset := range offsets {
+		if offset.Topic != nil {
+			p.standbyOffsets[partitionKey(*offset.Topic, offset.Partition)] = offset.Offset
+		}
+	}
+
+	assigned, err := p.consumer.Assignment()
+	if err != nil {
+		return err
+	}
+	if len(assigned) > 0 {
+		// reassign the partitions to reset the positions, then the partitions are fetched from the new offsets
+		if err = p.consumer.IncrementalUnassign(assigned); err != nil {
+			return err
+		}
+		if err = p.consumer.IncrementalAssign(p.withStandbyOffsets(assigned)); err != nil {
+			return err
+		}
+		if err = p.consumer.Resume(assigned); err != nil {
+			return err
+		}
+	}
+	p.standbyPaused = false
+	return nil
+}
+
+// assignPartitions assigns the partitions of the topics which aren't assigned yet, they're paused if the receiver
+// isn't activated
+func (p *Protocol) assignPartitions() error {
+	p.standbyMux.Lock()
+	defer p.standbyMux.Unlock()
+
+	metadata, err := p.consumer.GetMetadata(nil, true, metadataTimeoutMs)
+	if err != nil {
+		return err
+	}
+	assigned, err := p.consumer.Assignment()
+	if err != nil {
+		return err
+	}
+	assignedKeys := map[string]bool{}
+	for _, partition := range assigned {
+		assignedKeys[partitionKey(*partition.Topic, partition.Partition)] = true
+	}
+
+	partitions := []kafka.TopicPartition{}
+	for topic, topicMetadata := range metadata.Topics {
+		if !p.matchTopic(topic) {
+			continue
+		}
+		for _, partitionMetadata := range topicMetadata.Partitions {
+			if assignedKeys[partitionKey(topic, partitionMetadata.ID)] {
+				continue
+			}
+			topicName := topic
+			partitions = append(partitions, kafka.TopicPartition{
+				Topic:     &topicName,
+				Partition: partitionMetadata.ID,
+				Offset:    kafka.OffsetStored,
+			})
+		}
+	}
+	if len(partitions) == 0 {
+		return nil
+	}
+
+	if !p.standbyPaused {
+		partitions = p.withStandbyOffsets(partitions)
+	}
+	if err = p.consumer.IncrementalAssign(partitions); err != nil {
+		return err
+	}
+	if p.standbyPaused {
+		return p.consumer.Pause(partitions)
+	}
+	return nil
+}
+
+// withStandbyOffsets returns the partitions with the offsets given by the activation
+func (p *Protocol) withStandbyOffsets(partitions []kafka.TopicPartition) []kafka.TopicPartition {
+	result := make([]kafka.TopicPartition, 0, len(partitions))
+	for _, partition := range partitions {
+		partition.Offset = kafka.OffsetStored
+		if offset, found := p.standbyOffsets[partitionKey(*partition.Topic, partition.Partition)]; found {
+			partition.Offset = offset
+		}
+		result = append(result, partition)
+	}
+	return result
+}
+
+// matchTopic follows the subscription of kafka, the topic starts with "^" is a regex
+func (p *Protocol) matchTopic(topic string) bool {
+	for _, consumerTopic := range p.consumerTopics {
+		if consumerTopic == topic {
+			return true
+		}
+		if strings.HasPrefix(consumerTopic, "^") {
+			if matched, _ := regexp.MatchString(consumerTopic, topic); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func partitionKey(topic string, partition int32) string {
+	return fmt.Sprintf("%s@%d", topic, partition)
+}
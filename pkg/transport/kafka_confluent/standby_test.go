@@ -0,0 +1,26 @@
+package kafka_confluent
+
+import (
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStandbyPartitions(t *testing.T) {
+	p := &Protocol{consumerTopics: []string{"event", "^status.*"}}
+	assert.True(t, p.matchTopic("event"))
+	assert.True(t, p.matchTopic("status"))
+	assert.True(t, p.matchTopic("status.hub1"))
+	assert.False(t, p.matchTopic("spec"))
+
+	statusTopic, eventTopic := "status.hub1", "event"
+	p.standbyOffsets = map[string]kafka.Offset{partitionKey(statusTopic, 0): 10}
+	partitions := p.withStandbyOffsets([]kafka.TopicPartition{
+		{Topic: &statusTopic, Partition: 0, Offset: kafka.OffsetBeginning},
+		{Topic: &eventTopic, Partition: 0, Offset: kafka.OffsetBeginning},
+	})
+	// the partitions without the given offsets start from the committed offsets of the consumer group
+	assert.Equal(t, kafka.Offset(10), partitions[0].Offset)
+	assert.Equal(t, kafka.OffsetStored, partitions[1].Offset)
+}
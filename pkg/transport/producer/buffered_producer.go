@@ -0,0 +1,161 @@
+package producer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
+)
+
+var (
+	bufferRetryInterval    = 5 * time.Second
+	bufferMaxRetryInterval = 1 * time.Minute
+)
+
+// BufferedProducer keeps the events into the disk queue once they fail to be sent, e.g. the kafka is unreachable,
+// and delivers them in order once the transport is back. the events sent during the outage are appended to the queue
+// rather than sent directly, so they aren't delivered ahead of the buffered ones.
+type BufferedProducer struct {
+	log      logr.Logger
+	producer transport.Producer
+	queue    *diskQueue
+	lock     sync.Mutex
+	notify   chan struct{}
+	// the interval to retry the head of the queue, it's doubled until the max interval after each failure
+	retryInterval time.Duration
+}
+
+func NewBufferedProducer(ctx context.Context, producer transport.Producer, dir string, maxBytes int64,
+) (*BufferedProducer, error) {
+	queue, err := newDiskQueue(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	p := &BufferedProducer{
+		log:           ctrl.Log.WithName(logger.TransportSubsystem).WithName("buffered-producer"),
+		producer:      producer,
+		queue:         queue,
+		notify:        make(chan struct{}, 1),
+		retryInterval: bufferRetryInterval,
+	}
+	if queue.len() > 0 {
+		p.log.Info("restored the buffered events", "count", queue.len(), "bytes", queue.bytes())
+	}
+	go p.drain(ctx)
+	return p, nil
+}
+
+func (p *BufferedProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.lock.Lock()
+	buffering := p.queue.len() > 0
+	if buffering {
+		defer p.lock.Unlock()
+		return p.buffer(ctx, evt)
+	}
+	p.lock.Unlock()
+
+	err := p.producer.SendEvent(ctx, evt)
+	if err == nil {
+		return nil
+	}
+	p.log.Info("failed to send the event, buffer it until the transport is back", "type", evt.Type(),
+		"error", err.Error())
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if bufferErr := p.buffer(ctx, evt); bufferErr != nil {
+		return fmt.Errorf("%w, and failed to buffer it: %v", err, bufferErr)
+	}
+	return nil
+}
+
+// buffer appends the event to the queue, the caller must hold the lock
+func (p *BufferedProducer) buffer(ctx context.Context, evt cloudevents.Event) error {
+	err := p.queue.push(&bufferedEvent{
+		Topic:      cecontext.TopicFrom(ctx),
+		MessageKey: kafka_confluent.MessageKeyFrom(ctx),
+		Event:      evt,
+	})
+	if err != nil {
+		return err
+	}
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// drain sends the buffered events one by one, the head isn't removed until it's delivered
+func (p *BufferedProducer) drain(ctx context.Context) {
+	retryInterval := p.retryInterval
+	for {
+		p.lock.Lock()
+		evt, err := p.queue.peek()
+		if err != nil {
+			// the event can't be restored, e.g. the file is corrupted, drop it rather than blocking the queue
+			p.log.Error(err, "failed to read the buffered event, drop it")
+			err = p.queue.pop()
+		}
+		p.lock.Unlock()
+		if err != nil {
+			p.log.Error(err, "failed to remove the buffered event")
+		}
+
+		if evt == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.notify:
+				continue
+			case <-time.After(p.retryInterval):
+				continue
+			}
+		}
+
+		sendCtx := ctx
+		if evt.Topic != "" {
+			sendCtx = cecontext.WithTopic(sendCtx, evt.Topic)
+		}
+		if evt.MessageKey != "" {
+			sendCtx = kafka_confluent.WithMessageKey(sendCtx, evt.MessageKey)
+		}
+		if err := p.producer.SendEvent(sendCtx, evt.Event); err != nil {
+			p.log.V(2).Info("failed to send the buffered event", "type", evt.Event.Type(), "error", err.Error(),
+				"retryAfter", retryInterval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			retryInterval = min(2*retryInterval, bufferMaxRetryInterval)
+			continue
+		}
+		retryInterval = p.retryInterval
+
+		p.lock.Lock()
+		if err := p.queue.pop(); err != nil {
+			p.log.Error(err, "failed to remove the delivered event")
+		}
+		if p.queue.len() == 0 {
+			p.log.Info("the buffered events are delivered")
+		}
+		p.lock.Unlock()
+	}
+}
+
+// Buffered returns the count of the events in the queue
+func (p *BufferedProducer) Buffered() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.queue.len()
+}
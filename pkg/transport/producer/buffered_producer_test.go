@@ -0,0 +1,116 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentEvent struct {
+	id    string
+	topic string
+}
+
+// unreachableProducer fails to send the events until it's reachable
+type unreachableProducer struct {
+	lock      sync.Mutex
+	reachable bool
+	sent      []sentEvent
+}
+
+func (p *unreachableProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.reachable {
+		return errors.New("the transport is unreachable")
+	}
+	p.sent = append(p.sent, sentEvent{id: evt.ID(), topic: cecontext.TopicFrom(ctx)})
+	return nil
+}
+
+func (p *unreachableProducer) setReachable(reachable bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.reachable = reachable
+}
+
+func (p *unreachableProducer) sentEvents() []sentEvent {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]sentEvent{}, p.sent...)
+}
+
+func newTestEvent(id string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetSource("hub1")
+	evt.SetType("test")
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"id": id})
+	return evt
+}
+
+func TestBufferedProducer(t *testing.T) {
+	bufferRetryInterval = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	transport := &unreachableProducer{reachable: true}
+	p, err := NewBufferedProducer(ctx, transport, dir, 1024*1024)
+	require.NoError(t, err)
+
+	// send the event directly when the transport is reachable
+	require.NoError(t, p.SendEvent(ctx, newTestEvent("0")))
+	assert.Equal(t, 0, p.Buffered())
+
+	// buffer the events during the outage
+	transport.setReachable(false)
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, p.SendEvent(cecontext.WithTopic(ctx, "event"), newTestEvent(fmt.Sprint(i))))
+	}
+	assert.Equal(t, 3, p.Buffered())
+
+	// the events are restored from the directory after the restart
+	queue, err := newDiskQueue(dir, 1024*1024)
+	require.NoError(t, err)
+	assert.Equal(t, 3, queue.len())
+
+	// deliver the buffered events in order once the transport is back
+	transport.setReachable(true)
+	require.NoError(t, p.SendEvent(ctx, newTestEvent("4")))
+	assert.Eventually(t, func() bool { return p.Buffered() == 0 }, 5*time.Second, 50*time.Millisecond)
+
+	sent := transport.sentEvents()
+	require.Len(t, sent, 5)
+	for i, evt := range sent {
+		assert.Equal(t, fmt.Sprint(i), evt.id)
+	}
+	assert.Equal(t, "event", sent[1].topic)
+}
+
+func TestBufferedProducerFull(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the buffer is only able to hold one event
+	data, err := json.Marshal(&bufferedEvent{Event: newTestEvent("1")})
+	require.NoError(t, err)
+
+	transport := &unreachableProducer{}
+	p, err := NewBufferedProducer(ctx, transport, t.TempDir(), int64(len(data)+1))
+	require.NoError(t, err)
+
+	require.NoError(t, p.SendEvent(ctx, newTestEvent("1")))
+	assert.Error(t, p.SendEvent(ctx, newTestEvent("2")), "the event should be rejected once the buffer is full")
+	assert.Equal(t, 1, p.Buffered())
+}
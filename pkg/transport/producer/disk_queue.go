@@ -0,0 +1,138 @@
+package producer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+const queueFileSuffix = ".event"
+
+var errQueueFull = errors.New("the buffer queue is full")
+
+// bufferedEvent is the event persisted in the disk queue, along with the destination carried by the context
+type bufferedEvent struct {
+	Topic      string            `json:"topic,omitempty"`
+	MessageKey string            `json:"messageKey,omitempty"`
+	Event      cloudevents.Event `json:"event"`
+}
+
+type queueFile struct {
+	seq  uint64
+	size int64
+}
+
+// diskQueue is a bounded FIFO queue persisting each event into a file of the directory, the file is named by the
+// sequence of the event, so the queue is restored in order after the agent is restarted.
+type diskQueue struct {
+	dir      string
+	maxBytes int64
+	files    []queueFile
+	size     int64
+	nextSeq  uint64
+}
+
+func newDiskQueue(dir string, maxBytes int64) (*diskQueue, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the buffer directory %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the buffer directory %s: %w", dir, err)
+	}
+
+	q := &diskQueue{dir: dir, maxBytes: maxBytes}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), queueFileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(entry.Name(), queueFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		q.files = append(q.files, queueFile{seq: seq, size: info.Size()})
+		q.size += info.Size()
+	}
+	sort.Slice(q.files, func(i, j int) bool { return q.files[i].seq < q.files[j].seq })
+	if len(q.files) > 0 {
+		q.nextSeq = q.files[len(q.files)-1].seq + 1
+	}
+	return q, nil
+}
+
+func (q *diskQueue) len() int {
+	return len(q.files)
+}
+
+func (q *diskQueue) bytes() int64 {
+	return q.size
+}
+
+func (q *diskQueue) push(evt *bufferedEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	if q.size+int64(len(data)) > q.maxBytes {
+		return errQueueFull
+	}
+
+	// write into the temporary file and rename it, so a partial event isn't loaded after the agent is restarted
+	seq := q.nextSeq
+	tmpPath := filepath.Join(q.dir, fmt.Sprintf("%020d.tmp", seq))
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, q.path(seq)); err != nil {
+		return err
+	}
+	q.nextSeq++
+	q.files = append(q.files, queueFile{seq: seq, size: int64(len(data))})
+	q.size += int64(len(data))
+	return nil
+}
+
+// peek returns the head of the queue, nil if the queue is empty
+func (q *diskQueue) peek() (*bufferedEvent, error) {
+	if len(q.files) == 0 {
+		return nil, nil
+	}
+	data, err := os.ReadFile(q.path(q.files[0].seq))
+	if err != nil {
+		return nil, err
+	}
+	evt := &bufferedEvent{}
+	if err := json.Unmarshal(data, evt); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// pop removes the head of the queue
+func (q *diskQueue) pop() error {
+	if len(q.files) == 0 {
+		return nil
+	}
+	head := q.files[0]
+	if err := os.Remove(q.path(head.seq)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	q.files = q.files[1:]
+	q.size -= head.size
+	return nil
+}
+
+func (q *diskQueue) path(seq uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", seq, queueFileSuffix))
+}
@@ -0,0 +1,27 @@
+package producer
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// FilteringProducer drops the events which the filter doesn't accept instead of sending them, e.g. the bundles which
+// aren't collected by the data collection profile of the agent
+type FilteringProducer struct {
+	producer transport.Producer
+	accepts  func(eventType string) bool
+}
+
+func NewFilteringProducer(producer transport.Producer, accepts func(eventType string) bool) *FilteringProducer {
+	return &FilteringProducer{producer: producer, accepts: accepts}
+}
+
+func (p *FilteringProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	if !p.accepts(evt.Type()) {
+		return nil
+	}
+	return p.producer.SendEvent(ctx, evt)
+}
@@ -0,0 +1,24 @@
+package producer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilteringProducer(t *testing.T) {
+	ctx := context.Background()
+	transport := &unreachableProducer{reachable: true}
+	accepted := map[string]bool{"test": true}
+	p := NewFilteringProducer(transport, func(eventType string) bool { return accepted[eventType] })
+
+	require.NoError(t, p.SendEvent(ctx, newTestEvent("1")))
+	accepted["test"] = false
+	require.NoError(t, p.SendEvent(ctx, newTestEvent("2")))
+
+	sent := transport.sentEvents()
+	assert.Len(t, sent, 1)
+	assert.Equal(t, "1", sent[0].id)
+}
@@ -6,16 +6,25 @@ package producer
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	"github.com/go-logr/logr"
+	"github.com/google/uuid"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/kafka_confluent"
+	"github.com/stolostron/multicluster-global-hub/pkg/version"
 )
 
 const (
@@ -23,10 +32,22 @@ const (
 	DefaultMessageKBSize = 960
 )
 
+// messageIDNamespace is the namespace of the name-based uuids of the versioned bundles
+var messageIDNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://open-cluster-management.io/multicluster-global-hub"))
+
+// incarnation is the start time of the process, the events sent after the restart have a newer incarnation
+var incarnation = strconv.FormatInt(time.Now().UnixNano(), 10)
+
 type GenericProducer struct {
 	log              logr.Logger
+	mutex            sync.RWMutex
 	client           cloudevents.Client
+	sender           interface{}
 	messageSizeLimit int
+	transportConfig  *transport.TransportConfig
+	defaultTopic     string
+	// the kafka sender is rebuilt once the mounted credentials are rotated
+	credentialWatcher *config.CredentialWatcher
 }
 
 func NewGenericProducer(transportConfig *transport.TransportConfig, defaultTopic string) (*GenericProducer, error) {
@@ -60,25 +81,102 @@ func NewGenericProducer(transportConfig *transport.TransportConfig, defaultTopic
 		return nil, err
 	}
 
-	return &GenericProducer{
-		log:              ctrl.Log.WithName(fmt.Sprintf("%s-producer", transportConfig.TransportType)),
+	p := &GenericProducer{
+		log: ctrl.Log.WithName(logger.TransportSubsystem).WithName(
+			fmt.Sprintf("%s-producer", transportConfig.TransportType)),
 		client:           client,
+		sender:           sender,
 		messageSizeLimit: messageSize,
-	}, nil
+		transportConfig:  transportConfig,
+		defaultTopic:     defaultTopic,
+	}
+	if transportConfig.TransportType == string(transport.Kafka) {
+		p.credentialWatcher = config.NewCredentialWatcher(transportConfig.KafkaConfig)
+	}
+	return p, nil
+}
+
+// Reconnect rebuilds the kafka sender with the current credentials, the previous sender is closed once it's replaced
+func (p *GenericProducer) Reconnect() error {
+	if p.transportConfig.TransportType != string(transport.Kafka) {
+		return nil
+	}
+	sender, err := getConfluentSenderProtocol(p.transportConfig, p.defaultTopic)
+	if err != nil {
+		return err
+	}
+	client, err := cloudevents.NewClient(sender, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	previous := p.sender
+	p.client, p.sender = client, sender
+	p.mutex.Unlock()
+
+	if closer, ok := previous.(protocol.Closer); ok {
+		if err := closer.Close(context.Background()); err != nil {
+			p.log.Error(err, "failed to close the previous sender")
+		}
+	}
+	return nil
+}
+
+// reloadCredentials reconnects the producer if the credentials are rotated, it keeps the current sender on failure,
+// so the rotation is retried on the next check
+func (p *GenericProducer) reloadCredentials() {
+	if p.credentialWatcher == nil || !p.credentialWatcher.Changed() {
+		return
+	}
+	p.log.Info("the transport credentials are changed, reconnect the producer")
+	if err := p.Reconnect(); err != nil {
+		p.log.Error(err, "failed to reconnect the producer with the rotated credentials")
+		return
+	}
+	p.credentialWatcher.Accept()
+}
+
+func (p *GenericProducer) currentClient() cloudevents.Client {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.client
 }
 
 func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.reloadCredentials()
+	client := p.currentClient()
+
 	// message key
 	evtCtx := ctx
 	if kafka_confluent.MessageKeyFrom(ctx) == "" {
 		evtCtx = kafka_confluent.WithMessageKey(ctx, evt.Type())
 	}
 
+	// the receiver detects the version skew between the components by it
+	if _, found := evt.Extensions()[transport.ComponentVersionKey]; !found {
+		evt.SetExtension(transport.ComponentVersionKey, version.Get())
+	}
+	if _, found := evt.Extensions()[transport.IncarnationKey]; !found {
+		evt.SetExtension(transport.IncarnationKey, incarnation)
+	}
+	// the receiver validates the data with the version of the schema which the sender is built with
+	if evt.DataSchema() == "" {
+		if dataSchema := schema.DataSchema(evt.Type()); dataSchema != "" {
+			evt.SetDataSchema(dataSchema)
+		}
+	}
+
+	// the chunks of the event share the message id, so the receiver assembles them by it
+	if evt.ID() == "" {
+		evt.SetID(MessageID(evt))
+	}
+
 	// data
 	payloadBytes := evt.Data()
 	chunks := p.splitPayloadIntoChunks(payloadBytes)
 	if len(chunks) == 1 {
-		if ret := p.client.Send(evtCtx, evt); cloudevents.IsUndelivered(ret) {
+		if ret := client.Send(evtCtx, evt); cloudevents.IsUndelivered(ret) {
 			return fmt.Errorf("failed to send event to transport: %v", ret)
 		}
 		return nil
@@ -92,13 +190,26 @@ func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event)
 		if err := evt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
 			return fmt.Errorf("failed to set cloudevents data: %v", evt)
 		}
-		if result := p.client.Send(evtCtx, evt); cloudevents.IsUndelivered(result) {
+		if result := client.Send(evtCtx, evt); cloudevents.IsUndelivered(result) {
 			return fmt.Errorf("failed to send events to transport: %v", result)
 		}
 	}
 	return nil
 }
 
+// MessageID returns the application-level id of the event. It's derived from the source, the type, the incarnation
+// and the version of the versioned bundle, so the retried sends of the same bundle have the same id and their chunks
+// are assembled as one message by the receiver. The other events get a random id.
+func MessageID(evt cloudevents.Event) string {
+	bundleVersion, found := evt.Extensions()[eventversion.ExtVersion]
+	if !found {
+		return uuid.New().String()
+	}
+	name := fmt.Sprintf("%s/%s/%v/%v", evt.Source(), evt.Type(), evt.Extensions()[transport.IncarnationKey],
+		bundleVersion)
+	return uuid.NewSHA1(messageIDNamespace, []byte(name)).String()
+}
+
 func (p *GenericProducer) splitPayloadIntoChunks(payload []byte) [][]byte {
 	var chunk []byte
 	chunks := make([][]byte, 0, len(payload)/(p.messageSizeLimit)+1)
@@ -0,0 +1,66 @@
+package producer
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newVersionedEvent(bundleVersion string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType("test")
+	evt.SetExtension(eventversion.ExtVersion, bundleVersion)
+	evt.SetExtension(transport.IncarnationKey, "1")
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"name": "cluster1"})
+	return evt
+}
+
+func TestMessageID(t *testing.T) {
+	// the retried sends of the same bundle version have the same id
+	assert.Equal(t, MessageID(newVersionedEvent("1.1")), MessageID(newVersionedEvent("1.1")))
+	assert.NotEqual(t, MessageID(newVersionedEvent("1.1")), MessageID(newVersionedEvent("1.2")))
+
+	restarted := newVersionedEvent("1.1")
+	restarted.SetExtension(transport.IncarnationKey, "2")
+	assert.NotEqual(t, MessageID(newVersionedEvent("1.1")), MessageID(restarted))
+
+	// the unversioned events get a random id
+	evt := newVersionedEvent("1.1")
+	evt.SetExtension(eventversion.ExtVersion, nil)
+	assert.NotEqual(t, MessageID(evt), MessageID(evt))
+}
+
+func TestGenericProducerChunkID(t *testing.T) {
+	transportConfig := &transport.TransportConfig{TransportType: string(transport.Chan)}
+	p, err := NewGenericProducer(transportConfig, "status")
+	require.NoError(t, err)
+	p.SetDataLimit(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.SendEvent(ctx, newVersionedEvent("1.1"))
+	}()
+
+	// all the chunks of the event have the message id of the bundle
+	expectedID := MessageID(newVersionedEvent("1.1"))
+	receiver := transportConfig.Extends["status"].(*gochan.SendReceiver)
+	for offset := 0; offset < len(`{"name":"cluster1"}`); offset += 10 {
+		msg, err := receiver.Receive(ctx)
+		require.NoError(t, err)
+		chunk, err := binding.ToEvent(ctx, msg)
+		require.NoError(t, err)
+		assert.Equal(t, expectedID, chunk.ID())
+	}
+	require.NoError(t, <-errCh)
+}
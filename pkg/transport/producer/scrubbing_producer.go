@@ -0,0 +1,33 @@
+package producer
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/scrubber"
+)
+
+// ScrubbingProducer redacts and truncates the payloads of the events by the rules of the scrubber before sending them,
+// so the sensitive values never leave the cluster. The event is dropped with the error if it can't be scrubbed.
+type ScrubbingProducer struct {
+	producer transport.Producer
+	scrubber *scrubber.Scrubber
+}
+
+func NewScrubbingProducer(producer transport.Producer, scrubber *scrubber.Scrubber) *ScrubbingProducer {
+	return &ScrubbingProducer{producer: producer, scrubber: scrubber}
+}
+
+func (p *ScrubbingProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	if !p.scrubber.Enabled() {
+		return p.producer.SendEvent(ctx, evt)
+	}
+	// the context of the event is shared with the emitter, so it's scrubbed on the copy
+	evt = evt.Clone()
+	if err := p.scrubber.Scrub(&evt); err != nil {
+		return err
+	}
+	return p.producer.SendEvent(ctx, evt)
+}
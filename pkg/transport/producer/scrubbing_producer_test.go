@@ -0,0 +1,40 @@
+package producer
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/scrubber"
+)
+
+type capturingProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *capturingProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func TestScrubbingProducer(t *testing.T) {
+	ctx := context.Background()
+	transport := &capturingProducer{}
+	s := scrubber.NewScrubber()
+	p := NewScrubbingProducer(transport, s)
+
+	evt := newTestEvent("10.0.0.1")
+	require.NoError(t, p.SendEvent(ctx, evt))
+
+	require.NoError(t, s.Set(&scrubber.Config{Rules: []scrubber.Rule{{Name: "ip", Preset: "ipv4"}}}))
+	require.NoError(t, p.SendEvent(ctx, evt))
+
+	require.Len(t, transport.events, 2)
+	assert.JSONEq(t, `{"id": "10.0.0.1"}`, string(transport.events[0].Data()))
+	assert.JSONEq(t, `{"id": "[REDACTED]"}`, string(transport.events[1].Data()))
+	// the event of the emitter isn't changed
+	assert.JSONEq(t, `{"id": "10.0.0.1"}`, string(evt.Data()))
+}
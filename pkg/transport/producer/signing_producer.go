@@ -0,0 +1,30 @@
+package producer
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
+)
+
+// SigningProducer signs the events with the key of the managed hub before sending them, so the manager is able to
+// reject the events which aren't sent by the agent, e.g. injected into the compromised kafka.
+type SigningProducer struct {
+	producer transport.Producer
+	signer   *signature.Signer
+}
+
+func NewSigningProducer(producer transport.Producer, keyPath string) (*SigningProducer, error) {
+	signer, err := signature.NewSigner(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningProducer{producer: producer, signer: signer}, nil
+}
+
+func (p *SigningProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.signer.Sign(&evt)
+	return p.producer.SendEvent(ctx, evt)
+}
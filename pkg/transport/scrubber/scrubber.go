@@ -0,0 +1,245 @@
+package scrubber
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// DefaultReplacement replaces the value matching the rule if the replacement of the rule isn't specified
+const DefaultReplacement = "[REDACTED]"
+
+// presets are the patterns of the common sensitive values, so the rules don't need to write the regex for them
+var presets = map[string]string{
+	"ipv4":        `\b(?:\d{1,3}\.){3}\d{1,3}\b`,
+	"email":       `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	"bearerToken": `(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`,
+	"jwt":         `eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`,
+}
+
+// Config is the scrubbing rules of the event payloads, it's read from the configmap in the yaml format.
+type Config struct {
+	// MaxFieldLength truncates all the string values of the payloads to the length, it isn't truncated if it's 0
+	MaxFieldLength int    `json:"maxFieldLength,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+// Rule scrubs the string values of the payloads. The matched parts of the values are replaced if the pattern or the
+// preset is specified, otherwise the values are truncated to the max length if it's specified, or replaced entirely.
+type Rule struct {
+	Name string `json:"name"`
+	// Pattern is the regex of the sensitive parts of the values
+	Pattern string `json:"pattern,omitempty"`
+	// Preset is the name of the builtin pattern: ipv4, email, bearerToken or jwt
+	Preset      string `json:"preset,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	MaxLength   int    `json:"maxLength,omitempty"`
+	// FieldPaths limits the rule to the fields, which are the keys joined by the dots from the root of the payload,
+	// the indexes of the arrays are skipped, e.g. "message" or "source.host". The path also matches the fields of
+	// the same suffix, e.g. "host" matches "source.host". The rule applies to all the fields if it's empty.
+	FieldPaths []string `json:"fieldPaths,omitempty"`
+	// EventTypes limits the rule to the event types, with or without the prefix of the global hub, e.g.
+	// "event.localreplicatedpolicy.update". The rule applies to all the events if it's empty.
+	EventTypes []string `json:"eventTypes,omitempty"`
+}
+
+type compiledRule struct {
+	Rule
+	regex *regexp.Regexp
+}
+
+type compiledConfig struct {
+	maxFieldLength int
+	rules          []compiledRule
+}
+
+// Scrubber redacts and truncates the payloads of the events before they leave the cluster. The rules are replaced at
+// runtime, and the events are sent as they are if no rule is set.
+type Scrubber struct {
+	config atomic.Pointer[compiledConfig]
+}
+
+func NewScrubber() *Scrubber {
+	return &Scrubber{}
+}
+
+// Set replaces the rules, the current rules are kept if any of them is invalid. The scrubbing is disabled if the
+// config is nil.
+func (s *Scrubber) Set(config *Config) error {
+	if config == nil || (config.MaxFieldLength == 0 && len(config.Rules) == 0) {
+		s.config.Store(nil)
+		return nil
+	}
+	if config.MaxFieldLength < 0 {
+		return fmt.Errorf("the maxFieldLength %d is negative", config.MaxFieldLength)
+	}
+
+	compiled := &compiledConfig{maxFieldLength: config.MaxFieldLength}
+	for _, rule := range config.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("the name of the rule is required")
+		}
+		pattern := rule.Pattern
+		if rule.Preset != "" {
+			if pattern != "" {
+				return fmt.Errorf("the rule %s has both the pattern and the preset", rule.Name)
+			}
+			preset, ok := presets[rule.Preset]
+			if !ok {
+				return fmt.Errorf("unknown preset %q of the rule %s", rule.Preset, rule.Name)
+			}
+			pattern = preset
+		}
+		if rule.MaxLength < 0 {
+			return fmt.Errorf("the maxLength of the rule %s is negative", rule.Name)
+		}
+		if pattern == "" && len(rule.FieldPaths) == 0 {
+			// otherwise all the values of the payloads are redacted
+			return fmt.Errorf("the rule %s requires the pattern, the preset or the field paths", rule.Name)
+		}
+		if rule.Replacement == "" {
+			rule.Replacement = DefaultReplacement
+		}
+		compiledRule := compiledRule{Rule: rule}
+		if pattern != "" {
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid pattern of the rule %s: %w", rule.Name, err)
+			}
+			compiledRule.regex = regex
+		}
+		compiled.rules = append(compiled.rules, compiledRule)
+	}
+	s.config.Store(compiled)
+	return nil
+}
+
+// Enabled returns true if any rule or the max field length is set
+func (s *Scrubber) Enabled() bool {
+	return s.config.Load() != nil
+}
+
+// Scrub redacts and truncates the string values of the json payload of the event in place. It returns an error if the
+// payload isn't json, so the event isn't sent without being scrubbed.
+func (s *Scrubber) Scrub(evt *cloudevents.Event) error {
+	config := s.config.Load()
+	if config == nil || len(evt.Data()) == 0 {
+		return nil
+	}
+	rules := config.rulesOf(evt.Type())
+	if len(rules) == 0 && config.maxFieldLength == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(evt.Data()))
+	// keep the numbers as they are, e.g. the large integers aren't converted into the float
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return fmt.Errorf("failed to decode the payload of the event %s: %w", evt.Type(), err)
+	}
+
+	scrubbed, changed := config.scrubValue(payload, "", rules)
+	if !changed {
+		return nil
+	}
+	return evt.SetData(cloudevents.ApplicationJSON, scrubbed)
+}
+
+func (c *compiledConfig) rulesOf(eventType string) []compiledRule {
+	rules := []compiledRule{}
+	for _, rule := range c.rules {
+		if len(rule.EventTypes) == 0 {
+			rules = append(rules, rule)
+			continue
+		}
+		for _, t := range rule.EventTypes {
+			if t == eventType || enum.EventTypePrefix+t == eventType {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return rules
+}
+
+func (c *compiledConfig) scrubValue(value interface{}, path string, rules []compiledRule) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		changed := false
+		for key, item := range v {
+			itemPath := key
+			if path != "" {
+				itemPath = path + "." + key
+			}
+			scrubbed, itemChanged := c.scrubValue(item, itemPath, rules)
+			if itemChanged {
+				v[key] = scrubbed
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, item := range v {
+			scrubbed, itemChanged := c.scrubValue(item, path, rules)
+			if itemChanged {
+				v[i] = scrubbed
+				changed = true
+			}
+		}
+		return v, changed
+	case string:
+		scrubbed := c.scrubString(v, path, rules)
+		return scrubbed, scrubbed != v
+	default:
+		return value, false
+	}
+}
+
+func (c *compiledConfig) scrubString(value, path string, rules []compiledRule) string {
+	for _, rule := range rules {
+		if !matchPath(rule.FieldPaths, path) {
+			continue
+		}
+		switch {
+		case rule.regex != nil:
+			value = rule.regex.ReplaceAllLiteralString(value, rule.Replacement)
+		case rule.MaxLength > 0:
+			value = truncate(value, rule.MaxLength)
+		default:
+			value = rule.Replacement
+		}
+	}
+	if c.maxFieldLength > 0 {
+		value = truncate(value, c.maxFieldLength)
+	}
+	return value
+}
+
+func matchPath(fieldPaths []string, path string) bool {
+	if len(fieldPaths) == 0 {
+		return true
+	}
+	for _, fieldPath := range fieldPaths {
+		if path == fieldPath || strings.HasSuffix(path, "."+fieldPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate cuts the value to the max bytes without breaking the last character
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return strings.ToValidUTF8(value[:max], "")
+}
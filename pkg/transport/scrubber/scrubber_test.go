@@ -0,0 +1,134 @@
+package scrubber
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(t *testing.T, eventType string, data interface{}) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType(eventType)
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, data))
+	return &evt
+}
+
+func TestScrub(t *testing.T) {
+	s := NewScrubber()
+	require.NoError(t, s.Set(&Config{
+		MaxFieldLength: 64,
+		Rules: []Rule{
+			{Name: "ip", Preset: "ipv4", Replacement: "<ip>"},
+			{Name: "email", Preset: "email", FieldPaths: []string{"message"}},
+			{Name: "owner", FieldPaths: []string{"labels.owner"}},
+			{
+				Name: "event-message", MaxLength: 8, FieldPaths: []string{"reason"},
+				EventTypes: []string{"event.localreplicatedpolicy.update"},
+			},
+		},
+	}))
+
+	data := []map[string]interface{}{{
+		"message": "violation by admin@example.com on 10.0.0.1",
+		"reason":  "PolicyStatusSync",
+		"source":  map[string]interface{}{"host": "10.0.0.2", "user": "admin@example.com"},
+		"labels":  map[string]interface{}{"owner": "alice", "team": "sre"},
+		"count":   9007199254740993,
+		"details": strings.Repeat("x", 100),
+	}}
+	evt := newEvent(t, string(enum.LocalReplicatedPolicyEventType), data)
+	require.NoError(t, s.Scrub(evt))
+
+	scrubbed := []map[string]interface{}{}
+	require.NoError(t, evt.DataAs(&scrubbed))
+	require.Len(t, scrubbed, 1)
+	assert.Equal(t, "violation by [REDACTED] on <ip>", scrubbed[0]["message"])
+	assert.Equal(t, "PolicySt", scrubbed[0]["reason"])
+	// the email is only redacted in the message, while the ip is redacted in all the fields
+	assert.Equal(t, map[string]interface{}{"host": "<ip>", "user": "admin@example.com"}, scrubbed[0]["source"])
+	assert.Equal(t, map[string]interface{}{"owner": "[REDACTED]", "team": "sre"}, scrubbed[0]["labels"])
+	assert.Len(t, scrubbed[0]["details"], 64)
+	// the large integer isn't converted into the float
+	assert.Contains(t, string(evt.Data()), "9007199254740993")
+
+	// the rule of the event type doesn't apply to the other events
+	evt = newEvent(t, string(enum.LocalRootPolicyEventType), data)
+	require.NoError(t, s.Scrub(evt))
+	scrubbed = []map[string]interface{}{}
+	require.NoError(t, evt.DataAs(&scrubbed))
+	assert.Equal(t, "PolicyStatusSync", scrubbed[0]["reason"])
+
+	// the payload isn't json
+	evt = newEvent(t, "test", nil)
+	require.NoError(t, evt.SetData(cloudevents.TextPlain, "plain"))
+	assert.Error(t, s.Scrub(evt))
+
+	// the events are sent as they are once the rules are removed
+	require.NoError(t, s.Set(nil))
+	assert.False(t, s.Enabled())
+	evt = newEvent(t, "test", data)
+	payload := string(evt.Data())
+	require.NoError(t, s.Scrub(evt))
+	assert.Equal(t, payload, string(evt.Data()))
+}
+
+func TestSetInvalidRules(t *testing.T) {
+	s := NewScrubber()
+	require.NoError(t, s.Set(&Config{Rules: []Rule{{Name: "ip", Preset: "ipv4"}}}))
+
+	for _, config := range []*Config{
+		{Rules: []Rule{{Pattern: "foo"}}},
+		{Rules: []Rule{{Name: "all"}}},
+		{Rules: []Rule{{Name: "unknown", Preset: "phone"}}},
+		{Rules: []Rule{{Name: "both", Preset: "ipv4", Pattern: "foo"}}},
+		{Rules: []Rule{{Name: "regex", Pattern: "("}}},
+		{MaxFieldLength: -1},
+	} {
+		assert.Error(t, s.Set(config))
+	}
+	// the current rules are kept
+	assert.True(t, s.Enabled())
+}
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "scrub-rules", Namespace: "default"},
+		Data: map[string]string{RulesKey: `
+maxFieldLength: 1024
+rules:
+- name: ip
+  preset: ipv4
+`},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(configMap).Build()
+	s := NewScrubber()
+	watcher := NewWatcher(fakeClient, "default", "scrub-rules", s)
+
+	require.NoError(t, watcher.reload(ctx))
+	assert.True(t, s.Enabled())
+
+	// the current rules are kept if any of them is invalid
+	configMap.Data[RulesKey] = "rules:\n- name: ip\n  preset: ipv6\n"
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	assert.ErrorContains(t, watcher.reload(ctx), "unknown preset")
+	configMap.Data[RulesKey] = "rule: []"
+	require.NoError(t, fakeClient.Update(ctx, configMap))
+	assert.ErrorContains(t, watcher.reload(ctx), "invalid rules.yaml")
+	assert.True(t, s.Enabled())
+
+	// the scrubbing is disabled once the configmap is deleted
+	require.NoError(t, fakeClient.Delete(ctx, configMap))
+	require.NoError(t, watcher.reload(ctx))
+	assert.False(t, s.Enabled())
+}
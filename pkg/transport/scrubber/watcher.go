@@ -0,0 +1,103 @@
+package scrubber
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RulesKey is the key of the scrubbing rules in the configmap
+	RulesKey = "rules.yaml"
+	// RulesWatchInterval is the interval to read the configmap of the scrubbing rules
+	RulesWatchInterval = 15 * time.Second
+)
+
+// Watcher reads the scrubbing rules from the configmap periodically, so the rules are changed at runtime without
+// restarting the agent. The scrubbing is disabled once the configmap is deleted. The configmap is read directly rather
+// than cached, so it doesn't need the informer of the configmaps.
+type Watcher struct {
+	log      logr.Logger
+	reader   client.Reader
+	key      types.NamespacedName
+	scrubber *Scrubber
+	config   *Config
+}
+
+// AddWatcher watches the scrubbing rules of the scrubber in the configmap, it runs on all the replicas. The rules are
+// loaded before it returns, so the events sent once the manager is started are scrubbed.
+func AddWatcher(ctx context.Context, mgr ctrl.Manager, namespace, name string, scrubber *Scrubber) error {
+	watcher := NewWatcher(mgr.GetAPIReader(), namespace, name, scrubber)
+	if err := watcher.reload(ctx); err != nil {
+		return fmt.Errorf("failed to load the scrubbing rules from the configmap %s: %w", watcher.key.String(), err)
+	}
+	return mgr.Add(watcher)
+}
+
+func NewWatcher(reader client.Reader, namespace, name string, scrubber *Scrubber) *Watcher {
+	return &Watcher{
+		log:      ctrl.Log.WithName("scrub-rules-watcher"),
+		reader:   reader,
+		key:      types.NamespacedName{Namespace: namespace, Name: name},
+		scrubber: scrubber,
+	}
+}
+
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *Watcher) Start(ctx context.Context) error {
+	w.log.Info("watch the scrubbing rules", "configmap", w.key.String())
+	ticker := time.NewTicker(RulesWatchInterval)
+	defer ticker.Stop()
+	for {
+		if err := w.reload(ctx); err != nil {
+			w.log.Error(err, "failed to reload the scrubbing rules", "configmap", w.key.String())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reload applies the rules of the configmap if they're changed, the current rules are kept if any of them is invalid,
+// so the sensitive values aren't sent by mistake
+func (w *Watcher) reload(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	var config *Config
+	if err := w.reader.Get(ctx, w.key, configMap); err == nil {
+		config = &Config{}
+		if err := yaml.UnmarshalStrict([]byte(configMap.Data[RulesKey]), config); err != nil {
+			return fmt.Errorf("invalid %s: %w", RulesKey, err)
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	if reflect.DeepEqual(config, w.config) {
+		return nil
+	}
+	if err := w.scrubber.Set(config); err != nil {
+		return err
+	}
+	w.config = config
+	if config == nil {
+		w.log.Info("the scrubbing rules are removed")
+	} else {
+		w.log.Info("the scrubbing rules are changed", "rules", len(config.Rules),
+			"maxFieldLength", config.MaxFieldLength)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ExtSignature is the cloudevents extension of the signature, it's the base64 encoded ed25519 signature of the event
+const ExtSignature = "signature"
+
+// KeyReloadInterval is the min interval to reload the public keys from the directory
+var KeyReloadInterval = 30 * time.Second
+
+var (
+	ErrMissingSignature = errors.New("the event isn't signed")
+	ErrUnknownSigner    = errors.New("the public key of the event source isn't found")
+	ErrInvalidSignature = errors.New("the signature of the event is invalid")
+)
+
+// GenerateKey returns the PEM encoded ed25519 private key(PKCS #8) and public key(PKIX) of a managed hub
+func GenerateKey() ([]byte, []byte, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}), nil
+}
+
+// digest is the content to sign, it covers the type, the source and the data of the event, so the data can't be
+// replayed as another type or from another managed hub. The extensions are excluded since the chunk extensions are
+// changed by the producer after the event is signed.
+func digest(evt *cloudevents.Event) []byte {
+	hash := sha256.New()
+	hash.Write([]byte(evt.Type()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(evt.Source()))
+	hash.Write([]byte{0})
+	hash.Write(evt.Data())
+	return hash.Sum(nil)
+}
+
+// Signer signs the events with the private key of the managed hub
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+func NewSigner(keyPath string) (*Signer, error) {
+	keyPEM, err := os.ReadFile(filepath.Clean(keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the signing key: %w", err)
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode the signing key %s", keyPath)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the signing key: %w", err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the signing key %s isn't an ed25519 key", keyPath)
+	}
+	return &Signer{key: privateKey}, nil
+}
+
+// Sign sets the signature extension to the event, it must be called after the data is set
+func (s *Signer) Sign(evt *cloudevents.Event) {
+	evt.SetExtension(ExtSignature, base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, digest(evt))))
+}
+
+// Verifier verifies the events with the public keys of the managed hubs. The keys are the files in the directory
+// named by the managed hubs, e.g. the mounted secret, they're reloaded once a key isn't found or the reload interval is
+// elapsed, so the keys of the new hubs and the rotated keys are picked up without restarting.
+type Verifier struct {
+	dir        string
+	mutex      sync.Mutex
+	keys       map[string]ed25519.PublicKey
+	lastReload time.Time
+}
+
+func NewVerifier(dir string) (*Verifier, error) {
+	v := &Verifier{dir: dir}
+	if err := v.reload(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Verify returns nil if the event is signed by the key of its source
+func (v *Verifier) Verify(evt *cloudevents.Event) error {
+	value, ok := evt.Extensions()[ExtSignature]
+	if !ok {
+		return ErrMissingSignature
+	}
+	encoded, ok := value.(string)
+	if !ok {
+		return ErrInvalidSignature
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	publicKey, err := v.publicKey(evt.Source())
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, digest(evt), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (v *Verifier) publicKey(hub string) (ed25519.PublicKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	key, ok := v.keys[hub]
+	// the missing key is reloaded at most once a second, so the events of an unknown source don't read the directory
+	// one by one
+	sinceReload := time.Since(v.lastReload)
+	if sinceReload > KeyReloadInterval || (!ok && sinceReload > time.Second) {
+		if err := v.reload(); err != nil {
+			return nil, err
+		}
+		key, ok = v.keys[hub]
+	}
+	if !ok {
+		return nil, ErrUnknownSigner
+	}
+	return key, nil
+}
+
+func (v *Verifier) reload() error {
+	v.lastReload = time.Now()
+	entries, err := os.ReadDir(v.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the public keys: %w", err)
+	}
+	keys := map[string]ed25519.PublicKey{}
+	for _, entry := range entries {
+		// skip the hidden files, e.g. the "..data" of the mounted secret
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		keyPEM, err := os.ReadFile(filepath.Join(v.dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read the public key of %s: %w", entry.Name(), err)
+		}
+		key, err := parsePublicKey(keyPEM)
+		if err != nil {
+			return fmt.Errorf("failed to parse the public key of %s: %w", entry.Name(), err)
+		}
+		keys[entry.Name()] = key
+	}
+	v.keys = keys
+	return nil
+}
+
+func parsePublicKey(keyPEM []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode the public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("the public key isn't an ed25519 key")
+	}
+	return publicKey, nil
+}
@@ -0,0 +1,86 @@
+package signature
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	keyDir := filepath.Join(dir, "public-keys")
+	if err := os.Mkdir(keyDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	privateKey, publicKey, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate the key: %v", err)
+	}
+	keyPath := filepath.Join(dir, "signing.key")
+	if err := os.WriteFile(keyPath, privateKey, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(keyDir, "hub1"), publicKey, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := NewSigner(keyPath)
+	if err != nil {
+		t.Fatalf("failed to create the signer: %v", err)
+	}
+	verifier, err := NewVerifier(keyDir)
+	if err != nil {
+		t.Fatalf("failed to create the verifier: %v", err)
+	}
+
+	newEvent := func(source string) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.policy.localcompliance")
+		evt.SetSource(source)
+		if err := evt.SetData(cloudevents.ApplicationJSON, []byte(`[{"policyId":"1","compliantClusters":["c1"]}]`)); err != nil {
+			t.Fatal(err)
+		}
+		return &evt
+	}
+
+	evt := newEvent("hub1")
+	if err := verifier.Verify(evt); !errors.Is(err, ErrMissingSignature) {
+		t.Fatalf("expected the missing signature, got %v", err)
+	}
+	signer.Sign(evt)
+	if err := verifier.Verify(evt); err != nil {
+		t.Fatalf("expected the valid signature, got %v", err)
+	}
+
+	// the forged data
+	if err := evt.SetData(cloudevents.ApplicationJSON, []byte(`[{"policyId":"1","compliantClusters":["c2"]}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(evt); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected the invalid signature of the forged data, got %v", err)
+	}
+
+	// the event of another hub signed with the key of hub1
+	evt = newEvent("hub2")
+	signer.Sign(evt)
+	if err := verifier.Verify(evt); !errors.Is(err, ErrUnknownSigner) {
+		t.Fatalf("expected the unknown signer, got %v", err)
+	}
+
+	// the key of the new hub is reloaded
+	_, publicKey2, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(keyDir, "hub2"), publicKey2, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	verifier.lastReload = time.Now().Add(-2 * time.Second)
+	if err := verifier.Verify(evt); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("expected the invalid signature with the key of hub2, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// MemoryStorage keeps the latest bundle of each event type from each managed hub in memory. The bundle is persisted
+// by the same version rules as the conflation of the manager: the bundle is dropped if its version isn't newer than
+// the persisted one, and the first generation(e.g. 0.1) resets the version since the agent is restarted.
+type MemoryStorage struct {
+	mutex   sync.RWMutex
+	bundles map[bundleKey]*Bundle
+}
+
+type bundleKey struct {
+	source    string
+	eventType string
+}
+
+// Bundle is the persisted event of the managed hub
+type Bundle struct {
+	Source  string
+	Type    string
+	Version *eventversion.Version
+	Data    []byte
+	// Count is the number of the events of the bundle persisted so far
+	Count int
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{bundles: map[bundleKey]*Bundle{}}
+}
+
+// Persist stores the event, it returns false if the event is dropped since a newer version is persisted
+func (s *MemoryStorage) Persist(evt *cloudevents.Event) (bool, error) {
+	var version *eventversion.Version
+	if value, ok := evt.Extensions()[eventversion.ExtVersion]; ok {
+		var err error
+		if version, err = eventversion.VersionFrom(fmt.Sprint(value)); err != nil {
+			return false, err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := bundleKey{source: evt.Source(), eventType: evt.Type()}
+	bundle, found := s.bundles[key]
+	if !found {
+		bundle = &Bundle{Source: evt.Source(), Type: evt.Type()}
+		s.bundles[key] = bundle
+	}
+	if version != nil && version.InitGen() && bundle.Version != nil {
+		bundle.Version = eventversion.NewVersion()
+	}
+	// the events without the version are always persisted, e.g. the events of the event topic
+	if version != nil && !version.NewerThan(bundle.Version) {
+		return false, nil
+	}
+	bundle.Version = version
+	bundle.Data = evt.Data()
+	bundle.Count++
+	return true, nil
+}
+
+// Start persists the events of the consumer until the context is done, the consumer is started by it
+func (s *MemoryStorage) Start(ctx context.Context, c transport.Consumer) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- c.Start(ctx)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errChan:
+			return err
+		case evt := <-c.EventChan():
+			if _, err := s.Persist(evt); err != nil {
+				return fmt.Errorf("failed to persist the event %s from %s: %w", evt.Type(), evt.Source(), err)
+			}
+		}
+	}
+}
+
+// Get returns the persisted bundle of the event type from the managed hub, it's nil if the bundle isn't persisted
+func (s *MemoryStorage) Get(source string, eventType enum.EventType) *Bundle {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	bundle, found := s.bundles[bundleKey{source: source, eventType: string(eventType)}]
+	if !found || bundle.Count == 0 {
+		return nil
+	}
+	copied := *bundle
+	return &copied
+}
+
+// GetData decodes the data of the persisted bundle into the out, it returns false if the bundle isn't persisted
+func (s *MemoryStorage) GetData(source string, eventType enum.EventType, out interface{}) (bool, error) {
+	bundle := s.Get(source, eventType)
+	if bundle == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(bundle.Data, out)
+}
+
+// Sources returns the managed hubs which have bundles persisted
+func (s *MemoryStorage) Sources() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	sources := map[string]bool{}
+	for key, bundle := range s.bundles {
+		if bundle.Count > 0 {
+			sources[key.source] = true
+		}
+	}
+	names := make([]string, 0, len(sources))
+	for source := range sources {
+		names = append(names, source)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TestingT is the subset of the testing.T used by the assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	FailNow()
+}
+
+// RequirePersisted waits until the bundle of the event type from the managed hub is persisted and the matcher returns
+// true on its decoded data, the test fails if it isn't matched within the timeout. The out is decoded with the data
+// of the last persisted bundle, the matcher checks the out and it's skipped if it's nil.
+func RequirePersisted(t TestingT, s *MemoryStorage, source string, eventType enum.EventType, out interface{},
+	matcher func() bool, timeout time.Duration,
+) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		found, err := s.GetData(source, eventType, out)
+		lastErr = err
+		if found && err == nil && (matcher == nil || matcher()) {
+			return
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if lastErr != nil {
+		t.Errorf("the bundle %s from %s isn't decoded: %v", eventType, source, lastErr)
+	} else {
+		t.Errorf("the bundle %s from %s isn't persisted as expected within %s", eventType, source, timeout)
+	}
+	t.FailNow()
+}
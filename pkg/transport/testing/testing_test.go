@@ -0,0 +1,113 @@
+package testing_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	transporttesting "github.com/stolostron/multicluster-global-hub/pkg/transport/testing"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	storage := transporttesting.NewMemoryStorage()
+	persist := func(version string, compliant ...string) bool {
+		evt, err := transporttesting.NewEvent("hub1", enum.LocalComplianceType, version,
+			grc.ComplianceBundle{{PolicyID: "p1", CompliantClusters: compliant}})
+		require.NoError(t, err)
+		persisted, err := storage.Persist(&evt)
+		require.NoError(t, err)
+		return persisted
+	}
+
+	assert.True(t, persist("0.1", "c1"))
+	assert.True(t, persist("1.2", "c1", "c2"))
+	assert.False(t, persist("1.1", "c3"), "the older version should be dropped")
+	assert.False(t, persist("1.2", "c3"), "the same version should be dropped")
+
+	compliance := grc.ComplianceBundle{}
+	found, err := storage.GetData("hub1", enum.LocalComplianceType, &compliance)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, []string{"c1", "c2"}, compliance[0].CompliantClusters)
+
+	// the agent is restarted
+	assert.True(t, persist("0.1", "c4"))
+	bundle := storage.Get("hub1", enum.LocalComplianceType)
+	assert.Equal(t, "0.1", bundle.Version.String())
+	assert.Equal(t, 3, bundle.Count)
+
+	assert.Nil(t, storage.Get("hub2", enum.LocalComplianceType))
+	assert.Equal(t, []string{"hub1"}, storage.Sources())
+
+	_, err = transporttesting.NewEvent("hub1", enum.LocalComplianceType, "1", nil)
+	assert.Error(t, err)
+}
+
+func TestTransport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeTransport := transporttesting.NewTransport()
+	storage := transporttesting.NewMemoryStorage()
+	consumer, err := fakeTransport.Consumer("status")
+	require.NoError(t, err)
+	go func() {
+		_ = storage.Start(ctx, consumer)
+	}()
+
+	// the code under test sends the events by the producer created with the transport config
+	producer, err := fakeTransport.Producer("status")
+	require.NoError(t, err)
+	for i := 1; i <= 3; i++ {
+		evt, err := transporttesting.NewEvent("hub1", enum.HubClusterHeartbeatType, fmt.Sprintf("%d.1", i),
+			&cluster.HubHeartbeat{AgentVersion: fmt.Sprintf("v1.%d.0", i)})
+		require.NoError(t, err)
+		require.NoError(t, producer.SendEvent(ctx, evt))
+	}
+
+	evt, err := transporttesting.NewEvent("hub2", enum.HubClusterInfoType, "",
+		&cluster.HubClusterInfo{ConsoleURL: "https://console.hub2"})
+	require.NoError(t, err)
+	require.NoError(t, fakeTransport.Inject(ctx, "status", evt))
+
+	heartbeat := &cluster.HubHeartbeat{}
+	transporttesting.RequirePersisted(t, storage, "hub1", enum.HubClusterHeartbeatType, heartbeat, func() bool {
+		return heartbeat.AgentVersion == "v1.3.0"
+	}, 5*time.Second)
+
+	info := &cluster.HubClusterInfo{}
+	transporttesting.RequirePersisted(t, storage, "hub2", enum.HubClusterInfoType, info, nil, 5*time.Second)
+	assert.Equal(t, "https://console.hub2", info.ConsoleURL)
+	assert.Equal(t, []string{"hub1", "hub2"}, storage.Sources())
+}
+
+type fakeT struct {
+	errors []string
+	failed bool
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) FailNow() {
+	f.failed = true
+}
+
+func TestRequirePersistedTimeout(t *testing.T) {
+	ft := &fakeT{}
+	transporttesting.RequirePersisted(ft, transporttesting.NewMemoryStorage(), "hub1", enum.ManagedClusterType,
+		&[]interface{}{}, nil, 50*time.Millisecond)
+	assert.True(t, ft.failed)
+	require.Len(t, ft.errors, 1)
+	assert.Contains(t, ft.errors[0], "isn't persisted")
+}
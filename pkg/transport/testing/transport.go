@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package testing is the test harness for the integrators of the global hub. It wires the producers and the consumers
+// with the go channels instead of the kafka, and keeps the consumed bundles in memory instead of the postgres, so the
+// code sending or receiving the global hub events can be unit tested without the kafka and the postgres.
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+)
+
+// Transport is the in-process transport, the events sent to a topic are received by the consumer of the same topic.
+// Each topic is a go channel, so an event is only received by one consumer of the topic.
+type Transport struct {
+	mutex     sync.Mutex
+	config    *transport.TransportConfig
+	producers map[string]*producer.GenericProducer
+}
+
+func NewTransport() *Transport {
+	return &Transport{
+		config: &transport.TransportConfig{
+			TransportType: string(transport.Chan),
+			Extends:       map[string]interface{}{},
+		},
+		producers: map[string]*producer.GenericProducer{},
+	}
+}
+
+// Config returns the transport config of the go channels, it's passed to the code under test which creates the
+// producers and the consumers by the config, e.g. the agent or the manager
+func (t *Transport) Config() *transport.TransportConfig {
+	return t.config
+}
+
+// Producer returns the producer of the topic
+func (t *Transport) Producer(topic string) (*producer.GenericProducer, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if p, ok := t.producers[topic]; ok {
+		return p, nil
+	}
+	p, err := producer.NewGenericProducer(t.config, topic)
+	if err != nil {
+		return nil, err
+	}
+	t.producers[topic] = p
+	return p, nil
+}
+
+// Consumer returns a consumer of the topic, it has to be started to receive the events
+func (t *Transport) Consumer(topic string) (*consumer.GenericConsumer, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return consumer.NewGenericConsumer(t.config, []string{topic})
+}
+
+// Inject sends the event to the topic, it blocks if the channel of the topic is full until the event is received by a
+// consumer of the topic or the context is done
+func (t *Transport) Inject(ctx context.Context, topic string, evt cloudevents.Event) error {
+	p, err := t.Producer(topic)
+	if err != nil {
+		return err
+	}
+	if err := p.SendEvent(ctx, evt); err != nil {
+		return fmt.Errorf("failed to inject the event %s into %s: %w", evt.Type(), topic, err)
+	}
+	return nil
+}
+
+// NewEvent returns the event of the bundle sent by the managed hub(source), the version is the bundle version in the
+// format of "<generation>.<value>", e.g. "1.1", it isn't set if it's empty
+func NewEvent(source string, eventType enum.EventType, version string, data interface{}) (cloudevents.Event, error) {
+	evt := cloudevents.NewEvent()
+	evt.SetID(uuid.New().String())
+	evt.SetSource(source)
+	evt.SetType(string(eventType))
+	evt.SetTime(time.Now())
+	if version != "" {
+		if _, err := eventversion.VersionFrom(version); err != nil {
+			return evt, err
+		}
+		evt.SetExtension(eventversion.ExtVersion, version)
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return evt, err
+	}
+	return evt, nil
+}
@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HubPlaceholder is replaced with the name of the managed hub in the topic template, e.g. "acme.globalhub.status.{hub}"
+const HubPlaceholder = "{hub}"
+
+// IsTopicTemplate returns true if the topic is rendered for each hub from the template
+func IsTopicTemplate(template string) bool {
+	return strings.Contains(template, HubPlaceholder)
+}
+
+// RenderTopic returns the topic of the hub from the template, the template without the placeholder is returned as is
+func RenderTopic(template, hubName string) string {
+	return strings.ReplaceAll(template, HubPlaceholder, hubName)
+}
+
+// TopicRegex returns the regex matching the topics of all the hubs rendered from the template, e.g. the consumer of the
+// manager subscribes "^acme\.globalhub\.status\..*" for the template "acme.globalhub.status.{hub}"
+func TopicRegex(template string) string {
+	parts := strings.Split(template, HubPlaceholder)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	regex := "^" + strings.Join(parts, ".*")
+	if !strings.HasSuffix(template, HubPlaceholder) {
+		regex += "$"
+	}
+	return regex
+}
+
+// HubOfTopic returns the name of the hub which the topic is rendered for from the template, it returns false if the
+// topic isn't rendered from the template
+func HubOfTopic(template, topic string) (string, bool) {
+	if !IsTopicTemplate(template) {
+		return "", false
+	}
+	parts := strings.SplitN(template, HubPlaceholder, 2)
+	prefix, suffix := parts[0], strings.ReplaceAll(parts[1], HubPlaceholder, "")
+	if len(topic) <= len(prefix)+len(suffix) || !strings.HasPrefix(topic, prefix) || !strings.HasSuffix(topic, suffix) {
+		return "", false
+	}
+	hubName := strings.TrimSuffix(strings.TrimPrefix(topic, prefix), suffix)
+	if RenderTopic(template, hubName) != topic {
+		return "", false
+	}
+	return hubName, true
+}
+
+// TopicRename is a change of the topic naming scheme between the releases, the From and To are either the topics or
+// the topic templates
+type TopicRename struct {
+	From string
+	To   string
+}
+
+// TopicRenames are the naming schemes changed by the releases, the consumer migrates the positions of the renamed
+// topics to the new topics, so the upgraded manager neither consumes the new topics from the beginning nor skips the
+// events in them. A rename is appended once the naming scheme is changed, and the existing ones are kept for the
+// upgrades from the earlier releases.
+var TopicRenames = []TopicRename{
+	// the shared status topic is split to the status topic of each hub
+	{From: GenericStatusTopic, To: "status." + HubPlaceholder},
+}
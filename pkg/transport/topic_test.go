@@ -0,0 +1,45 @@
+package transport_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestTopicTemplate(t *testing.T) {
+	template := "acme.globalhub.status.{hub}"
+	assert.True(t, transport.IsTopicTemplate(template))
+	assert.False(t, transport.IsTopicTemplate("acme.globalhub.spec"))
+	assert.Equal(t, "acme.globalhub.status.hub1", transport.RenderTopic(template, "hub1"))
+	assert.Equal(t, "acme.globalhub.spec", transport.RenderTopic("acme.globalhub.spec", "hub1"))
+
+	regex := transport.TopicRegex(template)
+	assert.Equal(t, `^acme\.globalhub\.status\..*`, regex)
+	assert.Regexp(t, regexp.MustCompile(regex), "acme.globalhub.status.hub1")
+	assert.NotRegexp(t, regexp.MustCompile(regex), "acme_globalhub.status.hub1")
+
+	regex = transport.TopicRegex("acme.{hub}.status")
+	assert.Equal(t, `^acme\..*\.status$`, regex)
+	assert.Regexp(t, regexp.MustCompile(regex), "acme.hub1.status")
+	assert.NotRegexp(t, regexp.MustCompile(regex), "acme.hub1.status.backup")
+}
+
+func TestHubOfTopic(t *testing.T) {
+	hubName, ok := transport.HubOfTopic("acme.globalhub.status.{hub}", "acme.globalhub.status.hub1")
+	assert.True(t, ok)
+	assert.Equal(t, "hub1", hubName)
+
+	hubName, ok = transport.HubOfTopic("acme.{hub}.status", "acme.hub1.status")
+	assert.True(t, ok)
+	assert.Equal(t, "hub1", hubName)
+
+	_, ok = transport.HubOfTopic("acme.{hub}.status", "acme..status")
+	assert.False(t, ok)
+	_, ok = transport.HubOfTopic("acme.{hub}.status", "acme.hub1.spec")
+	assert.False(t, ok)
+	_, ok = transport.HubOfTopic("status", "status")
+	assert.False(t, ok)
+}
@@ -12,6 +12,11 @@ const (
 	Broadcast      = "broadcast" // Broadcast can be used as destination when a bundle should be broadcasted.
 	ChunkSizeKey   = "extsize"   // ChunkSizeKey is the key used for total bundle size header.
 	ChunkOffsetKey = "extoffset" // ChunkOffsetKey is the key used for message fragment offset header.
+	// ComponentVersionKey is the key used for the version of the component(e.g. the agent) sending the bundle.
+	ComponentVersionKey = "extcomponentversion"
+	// IncarnationKey is the key used for the incarnation of the process sending the bundle, it's increased once the
+	// process is restarted, so the bundle versions restarting from the first generation are newer than the previous ones.
+	IncarnationKey = "extincarnation"
 
 	// Deprecated
 	// CompressionType is the key used for compression type header.
@@ -42,24 +47,36 @@ type TransportConfig struct {
 type KafkaConfig struct {
 	ClusterIdentity string
 	BootstrapServer string
-	CaCertPath      string
-	ClientCertPath  string
-	ClientKeyPath   string
-	EnableTLS       bool
-	Topics          *ClusterTopic
-	ProducerConfig  *KafkaProducerConfig
-	ConsumerConfig  *KafkaConsumerConfig
+	// BootstrapServerPath is the file mounted with the bootstrap server, it overrides the BootstrapServer and the
+	// clients are rebuilt once it's changed, e.g. the brokers of the BYO kafka are migrated
+	BootstrapServerPath string
+	CaCertPath          string
+	ClientCertPath      string
+	ClientKeyPath       string
+	EnableTLS           bool
+	Topics              *ClusterTopic
+	ProducerConfig      *KafkaProducerConfig
+	ConsumerConfig      *KafkaConsumerConfig
 }
 
 type KafkaProducerConfig struct {
 	ProducerID         string
 	MessageSizeLimitKB int
+	// DisableIdempotence turns off the idempotent producer, e.g. the kafka cluster doesn't grant the idempotent write
+	DisableIdempotence bool
 }
 
 type KafkaConsumerConfig struct {
 	ConsumerID string
+	// OffsetReset is the policy to reset the consumer offset when it's out of range, "earliest" or "latest"
+	OffsetReset string
 }
 
+const (
+	OffsetResetEarliest = "earliest"
+	OffsetResetLatest   = "latest"
+)
+
 // transport protocol
 // indicate which kind of transport protocol, only support
 type TransportProtocol int
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -40,6 +41,105 @@ func UpdateObject(ctx context.Context, runtimeClient client.Client, obj *unstruc
 	return nil
 }
 
+// benignFieldManagers are the controllers on the managed hub which set the defaults and the derived fields of the
+// global resources, their fields aren't the local modifications of the resources
+var benignFieldManagers = []string{
+	"governance-policy-propagator",
+	"governance-policy-framework",
+	"multicluster-operators-placementrule",
+	"multicluster-operators-subscription",
+}
+
+// GetLocalFieldManagers returns the managers, other than the agent and the benign controllers, which own the spec
+// fields applied by the agent and set them to the other values. It means the object is modified on the managed hub
+// after it was applied by the agent, the fields which aren't applied by the agent aren't counted.
+func GetLocalFieldManagers(existing, desired *unstructured.Unstructured) []string {
+	appliedPaths := appliedFieldPaths(desired.Object["spec"], []string{"spec"})
+	managers := []string{}
+	for _, entry := range existing.GetManagedFields() {
+		if entry.Manager == controllerName || entry.Subresource != "" || entry.FieldsV1 == nil ||
+			ContainsString(benignFieldManagers, entry.Manager) || ContainsString(managers, entry.Manager) {
+			continue
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		for _, managedPath := range managedFieldPaths(fields, nil) {
+			if isModifiedField(managedPath, appliedPaths, existing, desired) {
+				managers = append(managers, entry.Manager)
+				break
+			}
+		}
+	}
+	return managers
+}
+
+// appliedFieldPaths returns the paths of the leaf fields of the object, the lists are the leaves since they're
+// replaced as a whole by the agent
+func appliedFieldPaths(value interface{}, path []string) [][]string {
+	fields, ok := value.(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		if value == nil {
+			return nil
+		}
+		return [][]string{path}
+	}
+	paths := [][]string{}
+	for key, field := range fields {
+		paths = append(paths, appliedFieldPaths(field, append(append([]string{}, path...), key))...)
+	}
+	return paths
+}
+
+// managedFieldPaths returns the leaf paths of the fieldsV1 of the managed fields entry, the "f:" prefix of the field
+// is removed, the keys of the list items("k:", "v:" and "i:") are kept, and the "." means the parent itself
+func managedFieldPaths(fields map[string]interface{}, path []string) [][]string {
+	if len(fields) == 0 {
+		return [][]string{path}
+	}
+	paths := [][]string{}
+	for key, value := range fields {
+		if key == "." {
+			paths = append(paths, path)
+			continue
+		}
+		children, _ := value.(map[string]interface{})
+		paths = append(paths, managedFieldPaths(children,
+			append(append([]string{}, path...), strings.TrimPrefix(key, "f:")))...)
+	}
+	return paths
+}
+
+// isModifiedField returns true if the managed path is a field applied by the agent, or is nested in it, and the value
+// of the applied field on the managed hub differs from the desired one
+func isModifiedField(managedPath []string, appliedPaths [][]string, existing, desired *unstructured.Unstructured,
+) bool {
+	for _, appliedPath := range appliedPaths {
+		if !hasFieldPrefix(managedPath, appliedPath) {
+			continue
+		}
+		existingValue, _, _ := unstructured.NestedFieldNoCopy(existing.Object, appliedPath...)
+		desiredValue, _, _ := unstructured.NestedFieldNoCopy(desired.Object, appliedPath...)
+		if !equality.Semantic.DeepEqual(existingValue, desiredValue) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFieldPrefix(path, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // DeleteObject tries to delete the given object from k8s. returns error and true/false if object was deleted or not.
 func DeleteObject(ctx context.Context, k8sClient client.Client, obj *unstructured.Unstructured) (bool, error) {
 	if err := k8sClient.Delete(ctx, obj); err != nil {
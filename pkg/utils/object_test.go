@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGetLocalFieldManagers(t *testing.T) {
+	desired := map[string]interface{}{
+		"disabled":          false,
+		"remediationAction": "inform",
+		"policy-templates": []interface{}{
+			map[string]interface{}{"objectDefinition": map[string]interface{}{"kind": "ConfigurationPolicy"}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		existing      map[string]interface{}
+		managedFields []metav1.ManagedFieldsEntry
+		expected      []string
+	}{
+		{
+			name:     "only applied by the agent",
+			existing: desired,
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   controllerName,
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:disabled":{}}}`)},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name:     "status and metadata are updated by the others",
+			existing: desired,
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:     "placement-controller",
+					Operation:   metav1.ManagedFieldsOperationUpdate,
+					Subresource: "status",
+					FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:numberOfSelectedClusters":{}}}`)},
+				},
+				{
+					Manager:   "agent",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:finalizers":{}}}`)},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name: "spec is modified by the others",
+			existing: map[string]interface{}{
+				"disabled":          true,
+				"remediationAction": "inform",
+				"policy-templates":  desired["policy-templates"],
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   controllerName,
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:remediationAction":{}}}`)},
+				},
+				{
+					Manager:   "kubectl-edit",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:disabled":{}}}`)},
+				},
+			},
+			expected: []string{"kubectl-edit"},
+		},
+		{
+			name: "the item of the list applied by the agent is modified",
+			existing: map[string]interface{}{
+				"disabled":          false,
+				"remediationAction": "inform",
+				"policy-templates": []interface{}{
+					map[string]interface{}{"objectDefinition": map[string]interface{}{"kind": "CertificatePolicy"}},
+				},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl-client-side-apply",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(`{"f:spec":{"f:policy-templates":{"i:0":{".":{},"f:objectDefinition":{}}}}}`),
+					},
+				},
+			},
+			expected: []string{"kubectl-client-side-apply"},
+		},
+		{
+			name: "the fields which aren't applied by the agent are set by the others",
+			existing: map[string]interface{}{
+				"disabled":           false,
+				"remediationAction":  "inform",
+				"policy-templates":   desired["policy-templates"],
+				"copyPolicyMetadata": true,
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl-client-side-apply",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{".":{},"f:copyPolicyMetadata":{}}}`)},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name:     "the applied fields are owned by the others with the same values",
+			existing: desired,
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl-client-side-apply",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:disabled":{},"f:remediationAction":{}}}`)},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name: "the applied fields are modified by the benign controllers",
+			existing: map[string]interface{}{
+				"disabled":          false,
+				"remediationAction": "enforce",
+				"policy-templates":  desired["policy-templates"],
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "governance-policy-propagator",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:remediationAction":{}}}`)},
+				},
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &unstructured.Unstructured{Object: map[string]interface{}{"spec": tt.existing}}
+			existing.SetName("test")
+			existing.SetManagedFields(tt.managedFields)
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": desired}}
+			obj.SetName("test")
+			assert.Equal(t, tt.expected, GetLocalFieldManagers(existing, obj))
+		})
+	}
+}
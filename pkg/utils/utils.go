@@ -10,7 +10,6 @@ import (
 
 	"github.com/go-logr/logr"
 	mchv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
-	uberzap "go.uber.org/zap"
 	uberzapcore "go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -20,25 +19,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/version"
 )
 
 func PrintVersion(log logr.Logger) {
+	log.Info(fmt.Sprintf("Version: %s", version.Get()))
 	log.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
 	log.Info(fmt.Sprintf("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH))
 }
 
+// CtrlZapOptions returns the options of the JSON logs, they're passed to the logger.New after the flags are parsed
 func CtrlZapOptions() zap.Options {
-	encoderConfig := uberzap.NewProductionEncoderConfig()
-	encoderConfig.EncodeTime = uberzapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeLevel = uberzapcore.CapitalColorLevelEncoder
-	opts := zap.Options{
-		Encoder: uberzapcore.NewConsoleEncoder(encoderConfig),
-		// for development
-		// ZapOpts: []uberzap.Option{
-		// 	uberzap.AddCaller(),
-		// },
+	return zap.Options{
+		TimeEncoder: uberzapcore.ISO8601TimeEncoder,
 	}
-	return opts
 }
 
 // Validate return true if the file exists and the content is not empty
@@ -0,0 +1,22 @@
+package version
+
+import "runtime/debug"
+
+// Version is the version of the component, it's set by the build flag:
+// -ldflags "-X github.com/stolostron/multicluster-global-hub/pkg/version.Version=<version>"
+var Version = ""
+
+// Get returns the version of the component, or the vcs revision of the build if the version isn't set
+func Get() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && setting.Value != "" {
+				return setting.Value
+			}
+		}
+	}
+	return "unknown"
+}
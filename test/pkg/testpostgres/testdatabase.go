@@ -1,6 +1,7 @@
 package testpostgres
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,6 +19,14 @@ func InitDatabase(uri string) error {
 	if err != nil {
 		return err
 	}
+	err = database.InitPgxPool(context.Background(), &database.DatabaseConfig{
+		URL:      uri,
+		Dialect:  database.PostgresDialect,
+		PoolSize: 10,
+	})
+	if err != nil {
+		return err
+	}
 
 	db := database.GetGorm()
 
@@ -0,0 +1,142 @@
+package teststorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// RunConformance verifies the storage implements the behaviors which the global hub depends on, it should be run by
+// the tests of each storage implementation against a running data store
+func RunConformance(t *testing.T, storage database.Storage, config *database.DatabaseConfig) {
+	ctx := context.Background()
+
+	t.Run("dialect", func(t *testing.T) {
+		if storage.Dialect() == "" {
+			t.Fatal("the dialect of the storage is empty")
+		}
+		database.RegisterStorage(storage)
+		registered, err := database.GetStorage(storage.Dialect())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if registered != storage {
+			t.Errorf("the registered storage of %s is %v", storage.Dialect(), registered)
+		}
+	})
+
+	db, sqlDB, err := storage.Open(config)
+	if err != nil {
+		t.Fatalf("failed to open the storage: %v", err)
+	}
+	defer database.CloseGorm(sqlDB)
+
+	t.Run("jsonb upsert", func(t *testing.T) {
+		// the resources are stored in the jsonb payload and upserted by the conflict of the primary key
+		err := db.Exec(`CREATE TABLE IF NOT EXISTS storage_conformance (
+			id uuid NOT NULL PRIMARY KEY,
+			payload jsonb NOT NULL
+		)`).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Exec("DROP TABLE IF EXISTS storage_conformance")
+
+		upsert := `INSERT INTO storage_conformance (id, payload) VALUES (?, ?)
+			ON CONFLICT (id) DO UPDATE SET payload = EXCLUDED.payload`
+		id := "7d4b6f58-8a5c-4b0e-9b1f-6f6b1c3d2e10"
+		for _, payload := range []string{`{"name": "origin"}`, `{"name": "updated"}`} {
+			if err := db.Exec(upsert, id, payload).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var name string
+		err = db.Raw("SELECT payload->>'name' FROM storage_conformance WHERE id = ?", id).Row().Scan(&name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != "updated" {
+			t.Errorf("expected the upserted payload, got %s", name)
+		}
+	})
+
+	t.Run("array upsert by the pool", func(t *testing.T) {
+		// the status writers pass the rows as the array parameters of the statements on the pgx pool
+		pool, err := storage.OpenPool(ctx, config)
+		if err != nil {
+			t.Fatalf("failed to open the pool: %v", err)
+		}
+		defer pool.Close()
+
+		_, err = pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS storage_pool_conformance (
+			id uuid NOT NULL PRIMARY KEY,
+			name text NOT NULL
+		)`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _, _ = pool.Exec(ctx, "DROP TABLE IF EXISTS storage_pool_conformance") }()
+
+		upsert := `INSERT INTO storage_pool_conformance (id, name)
+			SELECT v.id::uuid, v.name FROM unnest($1::text[], $2::text[]) AS v(id, name)
+			ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name`
+		ids := []string{"7d4b6f58-8a5c-4b0e-9b1f-6f6b1c3d2e11", "7d4b6f58-8a5c-4b0e-9b1f-6f6b1c3d2e12"}
+		for _, names := range [][]string{{"origin", "origin"}, {"updated", "updated"}} {
+			if _, err := pool.Exec(ctx, upsert, ids, names); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var count int
+		err = pool.QueryRow(ctx, "SELECT count(*) FROM storage_pool_conformance WHERE name = 'updated'").Scan(&count)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != len(ids) {
+			t.Errorf("expected %d upserted rows, got %d", len(ids), count)
+		}
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		otherConn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer otherConn.Close()
+
+		if err := storage.Lock(ctx, conn); err != nil {
+			t.Fatalf("failed to lock: %v", err)
+		}
+
+		locked := make(chan error, 1)
+		go func() { locked <- storage.Lock(ctx, otherConn) }()
+		select {
+		case err := <-locked:
+			t.Fatalf("the lock is acquired by the other connection while it's held: %v", err)
+		case <-time.After(time.Second):
+		}
+
+		if err := storage.Unlock(ctx, conn); err != nil {
+			t.Fatalf("failed to unlock: %v", err)
+		}
+		select {
+		case err := <-locked:
+			if err != nil {
+				t.Fatalf("failed to lock by the other connection: %v", err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("the lock isn't acquired by the other connection after it's released")
+		}
+		if err := storage.Unlock(ctx, otherConn); err != nil {
+			t.Fatalf("failed to unlock: %v", err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/test/simulator"
+)
+
+var setupLog = ctrl.Log.WithName("setup")
+
+func main() {
+	options, transportConfig := parseFlags()
+	if err := completeConfig(options, transportConfig); err != nil {
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+
+	genericProducer, err := producer.NewGenericProducer(transportConfig, transportConfig.KafkaConfig.Topics.StatusTopic)
+	if err != nil {
+		setupLog.Error(err, "failed to create the producer")
+		os.Exit(1)
+	}
+	if err := simulator.NewSimulator(options, genericProducer).Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "simulator exited non-zero")
+		os.Exit(1)
+	}
+}
+
+func parseFlags() (*simulator.Options, *transport.TransportConfig) {
+	options := &simulator.Options{}
+	transportConfig := &transport.TransportConfig{
+		TransportType: string(transport.Kafka),
+		KafkaConfig: &transport.KafkaConfig{
+			EnableTLS:      true,
+			Topics:         &transport.ClusterTopic{},
+			ProducerConfig: &transport.KafkaProducerConfig{},
+			ConsumerConfig: &transport.KafkaConsumerConfig{},
+		},
+	}
+
+	// add flags for logger
+	opts := utils.CtrlZapOptions()
+	defaultFlags := flag.CommandLine
+	opts.BindFlags(defaultFlags)
+	pflag.CommandLine.AddGoFlagSet(defaultFlags)
+
+	options.AddFlags(pflag.CommandLine)
+	pflag.StringVar(&transportConfig.KafkaConfig.BootstrapServer, "kafka-bootstrap-server", "",
+		"The bootstrap server for kafka.")
+	pflag.StringVar(&transportConfig.KafkaConfig.CaCertPath, "kafka-ca-cert-path", "",
+		"The path of CA certificate for kafka bootstrap server.")
+	pflag.StringVar(&transportConfig.KafkaConfig.ClientCertPath, "kafka-client-cert-path", "",
+		"The path of client certificate for kafka bootstrap server.")
+	pflag.StringVar(&transportConfig.KafkaConfig.ClientKeyPath, "kafka-client-key-path", "",
+		"The path of client key for kafka bootstrap server.")
+	pflag.IntVar(&transportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB, "kafka-message-size-limit", 940,
+		"The limit for kafka message size in KB.")
+	pflag.Parse()
+
+	// set zap logger
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+
+	return options, transportConfig
+}
+
+func completeConfig(options *simulator.Options, transportConfig *transport.TransportConfig) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if transportConfig.KafkaConfig.BootstrapServer == "" {
+		return fmt.Errorf("flag kafka-bootstrap-server can't be empty")
+	}
+	if transportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB > producer.MaxMessageKBLimit {
+		return fmt.Errorf("flag kafka-message-size-limit %d must not exceed %d",
+			transportConfig.KafkaConfig.ProducerConfig.MessageSizeLimitKB, producer.MaxMessageKBLimit)
+	}
+	transportConfig.KafkaConfig.ProducerConfig.ProducerID = options.HubNamePrefix + "simulator"
+	transportConfig.KafkaConfig.Topics.StatusTopic = options.StatusTopic
+	return nil
+}
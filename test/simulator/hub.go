@@ -0,0 +1,332 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package simulator
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const (
+	simulatorAgentVersion = "simulator"
+	policyNamespace       = "simulated-policies"
+)
+
+// the collectors reported with the heartbeat, they're named as the syncers of the agent
+var collectors = []string{"status.hub_cluster_info", "status.managed_cluster", "status.policy"}
+
+var standards = []string{"NIST SP 800-53", "NIST-CSF", "PCI", "FISMA", "HIPAA"}
+
+// simulatedHub keeps the state of a managed hub in memory, and builds the status bundles the same as the agent
+type simulatedHub struct {
+	name     string
+	rand     *rand.Rand
+	clusters []clusterv1.ManagedCluster
+	policies []policyv1.Policy
+	// compliance is the compliance state of each policy(the first index) on each cluster(the second index)
+	compliance [][]policyv1.ComplianceState
+
+	versions map[enum.EventType]*eventversion.Version
+	// changed are the bundles updated since they're sent last time
+	changed         map[enum.EventType]bool
+	lastSyncTimes   map[string]time.Time
+	resourceVersion int64
+}
+
+func newSimulatedHub(name string, clusters, policies int, seed int64) *simulatedHub {
+	h := &simulatedHub{
+		name:          name,
+		rand:          rand.New(rand.NewSource(seed)), // #nosec G404
+		versions:      map[enum.EventType]*eventversion.Version{},
+		changed:       map[enum.EventType]bool{},
+		lastSyncTimes: map[string]time.Time{},
+	}
+	for i := 0; i < clusters; i++ {
+		h.clusters = append(h.clusters, h.newCluster(i))
+	}
+	for i := 0; i < policies; i++ {
+		h.policies = append(h.policies, h.newPolicy(i))
+		states := make([]policyv1.ComplianceState, clusters)
+		for j := range states {
+			states[j] = policyv1.Compliant
+		}
+		h.compliance = append(h.compliance, states)
+	}
+	for _, eventType := range []enum.EventType{
+		enum.HubClusterInfoType, enum.ManagedClusterType, enum.LocalPolicySpecType, enum.LocalComplianceType,
+		enum.HubClusterHeartbeatType,
+	} {
+		h.versions[eventType] = eventversion.NewVersion()
+		h.update(eventType)
+	}
+	return h
+}
+
+func (h *simulatedHub) nextResourceVersion() string {
+	h.resourceVersion++
+	return strconv.FormatInt(h.resourceVersion, 10)
+}
+
+// uid returns the stable uid of the object, so the hub reports the same objects after the simulator is restarted
+func (h *simulatedHub) uid(kind, name string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(h.name+"/"+kind+"/"+name)).String()
+}
+
+func (h *simulatedHub) newCluster(i int) clusterv1.ManagedCluster {
+	name := fmt.Sprintf("%s-cluster-%d", h.name, i)
+	clusterID := h.uid("cluster", name)
+	region := []string{"us-east-1", "us-west-2", "eu-central-1", "ap-southeast-1"}[i%4]
+	now := metav1.Now()
+	return clusterv1.ManagedCluster{
+		TypeMeta: metav1.TypeMeta{Kind: "ManagedCluster", APIVersion: clusterv1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			UID:             types.UID(h.uid("managedcluster", name)),
+			ResourceVersion: h.nextResourceVersion(),
+			Labels: map[string]string{
+				"cloud":                        "Amazon",
+				"vendor":                       "OpenShift",
+				"name":                         name,
+				"clusterID":                    clusterID,
+				"region":                       region,
+				"openshiftVersion":             "4.14.8",
+				"openshiftVersion-major":       "4",
+				"openshiftVersion-major-minor": "4.14",
+				"cluster.open-cluster-management.io/clusterset":                        "default",
+				"feature.open-cluster-management.io/addon-work-manager":                "available",
+				"feature.open-cluster-management.io/addon-governance-policy-framework": "available",
+			},
+		},
+		Spec: clusterv1.ManagedClusterSpec{
+			HubAcceptsClient:     true,
+			LeaseDurationSeconds: 60,
+			ManagedClusterClientConfigs: []clusterv1.ClientConfig{
+				{URL: fmt.Sprintf("https://api.%s.example.com:6443", name)},
+			},
+		},
+		Status: clusterv1.ManagedClusterStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type: clusterv1.ManagedClusterConditionHubAccepted, Status: metav1.ConditionTrue,
+					Reason: "HubClusterAdminAccepted", Message: "Accepted by hub cluster admin", LastTransitionTime: now,
+				},
+				{
+					Type: clusterv1.ManagedClusterConditionJoined, Status: metav1.ConditionTrue,
+					Reason: "ManagedClusterJoined", Message: "Managed cluster joined", LastTransitionTime: now,
+				},
+				{
+					Type: clusterv1.ManagedClusterConditionAvailable, Status: metav1.ConditionTrue,
+					Reason: "ManagedClusterAvailable", Message: "Managed cluster is available", LastTransitionTime: now,
+				},
+			},
+			Capacity: clusterv1.ResourceList{
+				clusterv1.ResourceCPU:    resource.MustParse("24"),
+				clusterv1.ResourceMemory: resource.MustParse("96Gi"),
+			},
+			Allocatable: clusterv1.ResourceList{
+				clusterv1.ResourceCPU:    resource.MustParse("21"),
+				clusterv1.ResourceMemory: resource.MustParse("90Gi"),
+			},
+			Version: clusterv1.ManagedClusterVersion{Kubernetes: "v1.27.8+4fab27b"},
+			ClusterClaims: []clusterv1.ManagedClusterClaim{
+				{Name: "id.k8s.io", Value: clusterID},
+				{Name: "id.openshift.io", Value: clusterID},
+				{Name: "kubeversion.open-cluster-management.io", Value: "v1.27.8+4fab27b"},
+				{Name: "platform.open-cluster-management.io", Value: "AWS"},
+				{Name: "product.open-cluster-management.io", Value: "OpenShift"},
+				{Name: "region.open-cluster-management.io", Value: region},
+				{Name: "version.openshift.io", Value: "4.14.8"},
+			},
+		},
+	}
+}
+
+func (h *simulatedHub) newPolicy(i int) policyv1.Policy {
+	name := fmt.Sprintf("policy-%d", i)
+	configurationPolicy := fmt.Sprintf(`{"apiVersion":"policy.open-cluster-management.io/v1",`+
+		`"kind":"ConfigurationPolicy","metadata":{"name":"%s"},"spec":{"remediationAction":"inform",`+
+		`"severity":"medium","object-templates":[{"complianceType":"musthave","objectDefinition":`+
+		`{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"%s"}}}]}}`, name, name)
+	return policyv1.Policy{
+		TypeMeta: metav1.TypeMeta{Kind: policyv1.Kind, APIVersion: policyv1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       policyNamespace,
+			UID:             types.UID(h.uid("policy", name)),
+			ResourceVersion: h.nextResourceVersion(),
+			Annotations: map[string]string{
+				"policy.open-cluster-management.io/standards":  standards[i%len(standards)],
+				"policy.open-cluster-management.io/categories": "CM Configuration Management",
+				"policy.open-cluster-management.io/controls":   "CM-2 Baseline Configuration",
+			},
+		},
+		Spec: policyv1.PolicySpec{
+			RemediationAction: policyv1.Inform,
+			PolicyTemplates: []*policyv1.PolicyTemplate{
+				{ObjectDefinition: runtime.RawExtension{Raw: []byte(configurationPolicy)}},
+			},
+		},
+	}
+}
+
+func (h *simulatedHub) update(eventType enum.EventType) {
+	h.versions[eventType].Incr()
+	h.changed[eventType] = true
+}
+
+// churn changes the availability of the ratio of the clusters and the compliance of the ratio of the policies on the
+// clusters, it returns the number of the changed clusters and compliance
+func (h *simulatedHub) churn(rate float64) (int, int) {
+	changedClusters := h.changeCount(len(h.clusters), rate)
+	for i := 0; i < changedClusters; i++ {
+		managedCluster := &h.clusters[h.rand.Intn(len(h.clusters))]
+		for j := range managedCluster.Status.Conditions {
+			condition := &managedCluster.Status.Conditions[j]
+			if condition.Type != clusterv1.ManagedClusterConditionAvailable {
+				continue
+			}
+			if condition.Status == metav1.ConditionTrue {
+				condition.Status, condition.Reason = metav1.ConditionUnknown, "ManagedClusterLeaseUpdateStopped"
+				condition.Message = "Registration agent stopped updating its lease."
+			} else {
+				condition.Status, condition.Reason = metav1.ConditionTrue, "ManagedClusterAvailable"
+				condition.Message = "Managed cluster is available"
+			}
+			condition.LastTransitionTime = metav1.Now()
+		}
+		managedCluster.ResourceVersion = h.nextResourceVersion()
+	}
+	if changedClusters > 0 {
+		h.update(enum.ManagedClusterType)
+	}
+
+	changedCompliance := 0
+	if len(h.policies) > 0 {
+		changedCompliance = h.changeCount(len(h.policies)*len(h.clusters), rate)
+	}
+	for i := 0; i < changedCompliance; i++ {
+		states := h.compliance[h.rand.Intn(len(h.policies))]
+		j := h.rand.Intn(len(states))
+		if states[j] == policyv1.Compliant {
+			states[j] = policyv1.NonCompliant
+		} else {
+			states[j] = policyv1.Compliant
+		}
+	}
+	if changedCompliance > 0 {
+		h.update(enum.LocalComplianceType)
+	}
+	return changedClusters, changedCompliance
+}
+
+// changeCount returns the number of the changes of the total by the rate, the fraction is changed by the probability
+func (h *simulatedHub) changeCount(total int, rate float64) int {
+	if total == 0 {
+		return 0
+	}
+	count := float64(total) * rate
+	changes := int(count)
+	if h.rand.Float64() < count-float64(changes) {
+		changes++
+	}
+	return changes
+}
+
+func (h *simulatedHub) complianceBundle() grc.ComplianceBundle {
+	bundle := grc.ComplianceBundle{}
+	for i, policy := range h.policies {
+		compliance := grc.Compliance{
+			PolicyID:                  string(policy.UID),
+			CompliantClusters:         []string{},
+			NonCompliantClusters:      []string{},
+			UnknownComplianceClusters: []string{},
+			PendingComplianceClusters: []string{},
+		}
+		for j, state := range h.compliance[i] {
+			if state == policyv1.Compliant {
+				compliance.CompliantClusters = append(compliance.CompliantClusters, h.clusters[j].Name)
+			} else {
+				compliance.NonCompliantClusters = append(compliance.NonCompliantClusters, h.clusters[j].Name)
+			}
+		}
+		bundle = append(bundle, compliance)
+	}
+	return bundle
+}
+
+func (h *simulatedHub) heartbeat() *cluster.HubHeartbeat {
+	return &cluster.HubHeartbeat{
+		AgentVersion:  simulatorAgentVersion,
+		Collectors:    collectors,
+		LastSyncTimes: h.lastSyncTimes,
+		ResourceCounts: map[string]int{
+			"managedclusters": len(h.clusters),
+			"policies":        len(h.policies),
+		},
+	}
+}
+
+func (h *simulatedHub) payload(eventType enum.EventType) interface{} {
+	switch eventType {
+	case enum.HubClusterInfoType:
+		return &cluster.HubClusterInfo{
+			ConsoleURL: fmt.Sprintf("https://console-openshift-console.apps.%s.example.com", h.name),
+			ClusterId:  h.uid("hub", h.name),
+		}
+	case enum.ManagedClusterType:
+		return h.clusters
+	case enum.LocalPolicySpecType:
+		return h.policies
+	case enum.LocalComplianceType:
+		return h.complianceBundle()
+	case enum.HubClusterHeartbeatType:
+		return h.heartbeat()
+	}
+	return nil
+}
+
+// pendingEvents returns the events of the bundles changed since they're sent last time
+func (h *simulatedHub) pendingEvents(eventTypes ...enum.EventType) ([]*cloudevents.Event, error) {
+	events := []*cloudevents.Event{}
+	for _, eventType := range eventTypes {
+		if !h.changed[eventType] {
+			continue
+		}
+		evt := cloudevents.NewEvent()
+		evt.SetSource(h.name)
+		evt.SetType(string(eventType))
+		evt.SetExtension(eventversion.ExtVersion, h.versions[eventType].String())
+		if err := evt.SetData(cloudevents.ApplicationJSON, h.payload(eventType)); err != nil {
+			return nil, fmt.Errorf("failed to set the data of %s: %w", eventType, err)
+		}
+		events = append(events, &evt)
+	}
+	return events, nil
+}
+
+// sent records the bundle is sent, the generation of its version is increased like the agent
+func (h *simulatedHub) sent(eventType enum.EventType, sentAt time.Time) {
+	h.versions[eventType].Next()
+	h.changed[eventType] = false
+	if eventType != enum.HubClusterHeartbeatType {
+		h.lastSyncTimes[strings.TrimPrefix(string(eventType), enum.EventTypePrefix)] = sentAt
+	}
+}
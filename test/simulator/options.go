@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package simulator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Options describe the simulated fleet and how fast it changes
+type Options struct {
+	Hubs           int
+	ClustersPerHub int
+	PoliciesPerHub int
+	HubNamePrefix  string
+	// StatusTopic is the topic of the status events, the "%s" in it is replaced by the hub name, e.g. status.%s
+	StatusTopic string
+	// StatusInterval is how often the changed status bundles of each hub are sent, like the status sync interval of the
+	// agent
+	StatusInterval    time.Duration
+	HeartbeatInterval time.Duration
+	// ChurnRate is the ratio of the clusters and the compliance which change in each status interval
+	ChurnRate float64
+	// RampUp spreads the start of the hubs over the duration, so they don't send the initial bundles at once
+	RampUp         time.Duration
+	ReportInterval time.Duration
+	Seed           int64
+}
+
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.Hubs, "hubs", 10, "The number of the simulated managed hubs.")
+	fs.IntVar(&o.ClustersPerHub, "clusters-per-hub", 100, "The number of the managed clusters on each hub.")
+	fs.IntVar(&o.PoliciesPerHub, "policies-per-hub", 10,
+		"The number of the local policies on each hub, each policy is propagated to all the clusters of the hub.")
+	fs.StringVar(&o.HubNamePrefix, "hub-name-prefix", "simulated-hub-", "The prefix of the simulated hub names.")
+	fs.StringVar(&o.StatusTopic, "status-topic", "status",
+		"The topic of the status events, the \"%s\" is replaced by the hub name, e.g. status.%s.")
+	fs.DurationVar(&o.StatusInterval, "status-interval", 5*time.Second,
+		"The interval to send the changed status bundles of each hub.")
+	fs.DurationVar(&o.HeartbeatInterval, "heartbeat-interval", 60*time.Second,
+		"The interval to send the heartbeat of each hub.")
+	fs.Float64Var(&o.ChurnRate, "churn-rate", 0.01,
+		"The ratio of the clusters and the policy compliance changed in each status interval, from 0 to 1.")
+	fs.DurationVar(&o.RampUp, "ramp-up", time.Minute, "The duration to spread the start of the hubs over.")
+	fs.DurationVar(&o.ReportInterval, "report-interval", 30*time.Second,
+		"The interval to log the throughput of the simulator.")
+	fs.Int64Var(&o.Seed, "seed", 0, "The seed of the random changes, it's the current time if it's 0.")
+}
+
+func (o *Options) Validate() error {
+	if o.Hubs < 1 {
+		return fmt.Errorf("flag hubs must be positive")
+	}
+	if o.ClustersPerHub < 0 || o.PoliciesPerHub < 0 {
+		return fmt.Errorf("flag clusters-per-hub and policies-per-hub must not be negative")
+	}
+	if o.HubNamePrefix == "" {
+		return fmt.Errorf("flag hub-name-prefix can't be empty")
+	}
+	if o.StatusTopic == "" || strings.Count(o.StatusTopic, "%s") > 1 {
+		return fmt.Errorf("flag status-topic must be a topic name with at most one \"%%s\"")
+	}
+	if o.StatusInterval <= 0 || o.HeartbeatInterval <= 0 || o.ReportInterval <= 0 {
+		return fmt.Errorf("flag status-interval, heartbeat-interval and report-interval must be positive")
+	}
+	if o.ChurnRate < 0 || o.ChurnRate > 1 {
+		return fmt.Errorf("flag churn-rate must be in the scope [0, 1]")
+	}
+	if o.RampUp < 0 {
+		return fmt.Errorf("flag ramp-up must not be negative")
+	}
+	return nil
+}
+
+func (o *Options) hubName(i int) string {
+	return fmt.Sprintf("%s%d", o.HubNamePrefix, i)
+}
+
+func (o *Options) topic(hubName string) string {
+	if strings.Contains(o.StatusTopic, "%s") {
+		return fmt.Sprintf(o.StatusTopic, hubName)
+	}
+	return o.StatusTopic
+}
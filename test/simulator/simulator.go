@@ -0,0 +1,157 @@
+// Copyright (c) 2024 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// the status bundles sent on each status interval, the policy spec is sent before its compliance
+var statusEventTypes = []enum.EventType{
+	enum.ManagedClusterType, enum.LocalPolicySpecType, enum.LocalComplianceType,
+}
+
+// Simulator emulates the agents of the managed hubs, each hub sends its status bundles to the transport at the rate of
+// the options, so the sizing of the kafka and the database can be validated before the fleet is onboarded
+type Simulator struct {
+	log      logr.Logger
+	options  *Options
+	producer transport.Producer
+	stats    stats
+}
+
+// stats are the throughput of the simulator since the last report
+type stats struct {
+	events   atomic.Int64
+	bytes    atomic.Int64
+	failures atomic.Int64
+	// latency is the sum of the send durations in microseconds
+	latency atomic.Int64
+}
+
+func NewSimulator(options *Options, producer transport.Producer) *Simulator {
+	return &Simulator{
+		log:      ctrl.Log.WithName("simulator"),
+		options:  options,
+		producer: producer,
+	}
+}
+
+// Start runs the simulated hubs until the context is done
+func (s *Simulator) Start(ctx context.Context) error {
+	seed := s.options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s.log.Info("starting the simulator", "hubs", s.options.Hubs, "clustersPerHub", s.options.ClustersPerHub,
+		"policiesPerHub", s.options.PoliciesPerHub, "statusInterval", s.options.StatusInterval,
+		"churnRate", s.options.ChurnRate, "seed", seed)
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.options.Hubs; i++ {
+		hub := newSimulatedHub(s.options.hubName(i), s.options.ClustersPerHub, s.options.PoliciesPerHub, seed+int64(i))
+		delay := time.Duration(0)
+		if s.options.Hubs > 1 {
+			delay = s.options.RampUp * time.Duration(i) / time.Duration(s.options.Hubs)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runHub(ctx, hub, delay)
+		}()
+	}
+
+	ticker := time.NewTicker(s.options.ReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			s.report(s.options.ReportInterval)
+			s.log.Info("the simulator is stopped")
+			return nil
+		case <-ticker.C:
+			s.report(s.options.ReportInterval)
+		}
+	}
+}
+
+func (s *Simulator) runHub(ctx context.Context, hub *simulatedHub, delay time.Duration) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(delay):
+	}
+
+	// the hub info and the initial full status are sent once the hub is started
+	s.send(ctx, hub, enum.HubClusterInfoType, enum.HubClusterHeartbeatType)
+	s.send(ctx, hub, statusEventTypes...)
+
+	statusTicker := time.NewTicker(s.options.StatusInterval)
+	defer statusTicker.Stop()
+	heartbeatTicker := time.NewTicker(s.options.HeartbeatInterval)
+	defer heartbeatTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-statusTicker.C:
+			hub.churn(s.options.ChurnRate)
+			s.send(ctx, hub, statusEventTypes...)
+		case <-heartbeatTicker.C:
+			hub.update(enum.HubClusterHeartbeatType)
+			s.send(ctx, hub, enum.HubClusterHeartbeatType)
+		}
+	}
+}
+
+// send sends the changed bundles of the hub, the failed bundle is kept as changed, so it's resent on the next interval
+func (s *Simulator) send(ctx context.Context, hub *simulatedHub, eventTypes ...enum.EventType) {
+	events, err := hub.pendingEvents(eventTypes...)
+	if err != nil {
+		s.log.Error(err, "failed to build the events", "hub", hub.name)
+		return
+	}
+	sendCtx := cecontext.WithTopic(ctx, s.options.topic(hub.name))
+	for _, evt := range events {
+		start := time.Now()
+		if err := s.producer.SendEvent(sendCtx, *evt); err != nil {
+			s.stats.failures.Add(1)
+			s.log.Error(err, "failed to send the event", "hub", hub.name, "type", evt.Type())
+			continue
+		}
+		s.stats.events.Add(1)
+		s.stats.bytes.Add(int64(len(evt.Data())))
+		s.stats.latency.Add(time.Since(start).Microseconds())
+		hub.sent(enum.EventType(evt.Type()), start)
+	}
+}
+
+// report logs the throughput since the last report and resets the stats
+func (s *Simulator) report(interval time.Duration) {
+	events := s.stats.events.Swap(0)
+	bytes := s.stats.bytes.Swap(0)
+	failures := s.stats.failures.Swap(0)
+	latency := s.stats.latency.Swap(0)
+
+	averageLatency := time.Duration(0)
+	if events > 0 {
+		averageLatency = time.Duration(latency/events) * time.Microsecond
+	}
+	s.log.Info("throughput", "events", events, "failures", failures,
+		"eventsPerSecond", fmt.Sprintf("%.1f", float64(events)/interval.Seconds()),
+		"KBPerSecond", fmt.Sprintf("%.1f", float64(bytes)/1024/interval.Seconds()),
+		"averageSendLatency", averageLatency.String())
+}
@@ -0,0 +1,156 @@
+package simulator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+type fakeProducer struct {
+	mutex  sync.Mutex
+	events []cloudevents.Event
+	topics []string
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.events = append(p.events, evt)
+	p.topics = append(p.topics, cecontext.TopicFrom(ctx))
+	return nil
+}
+
+func (p *fakeProducer) sent() ([]cloudevents.Event, []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]cloudevents.Event{}, p.events...), append([]string{}, p.topics...)
+}
+
+func TestOptions(t *testing.T) {
+	options := &Options{
+		Hubs: 2, ClustersPerHub: 10, PoliciesPerHub: 2, HubNamePrefix: "hub-", StatusTopic: "status.%s",
+		StatusInterval: time.Second, HeartbeatInterval: time.Second, ReportInterval: time.Second, ChurnRate: 0.1,
+	}
+	require.NoError(t, options.Validate())
+	assert.Equal(t, "hub-1", options.hubName(1))
+	assert.Equal(t, "status.hub-1", options.topic("hub-1"))
+
+	options.StatusTopic = "status"
+	assert.Equal(t, "status", options.topic("hub-1"))
+
+	options.ChurnRate = 2
+	assert.ErrorContains(t, options.Validate(), "churn-rate")
+	options.ChurnRate = 0.1
+	options.Hubs = 0
+	assert.ErrorContains(t, options.Validate(), "hubs")
+}
+
+func TestSimulatedHub(t *testing.T) {
+	hub := newSimulatedHub("hub1", 20, 3, 1)
+	hub2 := newSimulatedHub("hub2", 20, 3, 1)
+	assert.NotEqual(t, hub.clusters[0].Status.ClusterClaims[0].Value, hub2.clusters[0].Status.ClusterClaims[0].Value)
+	assert.Equal(t, hub.policies[0].UID, newSimulatedHub("hub1", 1, 1, 2).policies[0].UID,
+		"the uid should be stable after the simulator is restarted")
+
+	events, err := hub.pendingEvents(statusEventTypes...)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	clusters := []clusterv1.ManagedCluster{}
+	require.NoError(t, events[0].DataAs(&clusters))
+	require.Len(t, clusters, 20)
+	assert.Equal(t, "id.k8s.io", clusters[0].Status.ClusterClaims[0].Name)
+
+	policies := []policyv1.Policy{}
+	require.NoError(t, events[1].DataAs(&policies))
+	require.Len(t, policies, 3)
+
+	compliance := grc.ComplianceBundle{}
+	require.NoError(t, events[2].DataAs(&compliance))
+	require.Len(t, compliance, 3)
+	assert.Equal(t, string(policies[0].UID), compliance[0].PolicyID)
+	assert.Len(t, compliance[0].CompliantClusters, 20)
+	assert.Equal(t, "0.1", events[2].Extensions()[eventversion.ExtVersion])
+
+	for _, evt := range events {
+		hub.sent(enum.EventType(evt.Type()), time.Now())
+	}
+	events, err = hub.pendingEvents(statusEventTypes...)
+	require.NoError(t, err)
+	assert.Empty(t, events, "the bundles shouldn't be resent until they're changed")
+
+	changedClusters, changedCompliance := hub.churn(0.5)
+	assert.Equal(t, 10, changedClusters)
+	assert.Equal(t, 30, changedCompliance)
+	events, err = hub.pendingEvents(statusEventTypes...)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, string(enum.ManagedClusterType), events[0].Type())
+	assert.Equal(t, string(enum.LocalComplianceType), events[1].Type())
+	assert.Equal(t, "1.2", events[1].Extensions()[eventversion.ExtVersion])
+
+	compliance = grc.ComplianceBundle{}
+	require.NoError(t, events[1].DataAs(&compliance))
+	for _, c := range compliance {
+		assert.Len(t, append(c.CompliantClusters, c.NonCompliantClusters...), 20)
+	}
+
+	heartbeat := &cluster.HubHeartbeat{}
+	events, err = hub.pendingEvents(enum.HubClusterHeartbeatType)
+	require.NoError(t, err)
+	require.NoError(t, events[0].DataAs(heartbeat))
+	assert.Equal(t, simulatorAgentVersion, heartbeat.AgentVersion)
+	assert.Contains(t, heartbeat.LastSyncTimes, "managedcluster")
+	assert.Equal(t, 20, heartbeat.ResourceCounts["managedclusters"])
+}
+
+func TestSimulator(t *testing.T) {
+	options := &Options{
+		Hubs: 2, ClustersPerHub: 5, PoliciesPerHub: 2, HubNamePrefix: "hub-", StatusTopic: "status.%s",
+		StatusInterval: 50 * time.Millisecond, HeartbeatInterval: time.Hour, ReportInterval: time.Hour, ChurnRate: 1,
+		Seed: 1,
+	}
+	producer := &fakeProducer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- NewSimulator(options, producer).Start(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		events, _ := producer.sent()
+		compliance := 0
+		for _, evt := range events {
+			if evt.Type() == string(enum.LocalComplianceType) {
+				compliance++
+			}
+		}
+		// the initial and the changed compliance of both hubs
+		return compliance >= 4
+	}, 5*time.Second, 50*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	events, topics := producer.sent()
+	infos := map[string]bool{}
+	for i, evt := range events {
+		assert.Equal(t, "status."+evt.Source(), topics[i])
+		if evt.Type() == string(enum.HubClusterInfoType) {
+			infos[evt.Source()] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"hub-0": true, "hub-1": true}, infos)
+}